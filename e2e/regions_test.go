@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
@@ -55,4 +56,7 @@ func TestRegions(t *testing.T) {
 	const invalidZone = "moonlab1"
 	_, err = theCloud.Regions().Get(ctx, meta.GlobalKey(invalidZone))
 	checkErrCode(t, err, 404, "Regions.Get()")
+	if !cloud.IsNotFound(err) {
+		t.Errorf("cloud.IsNotFound(%v) = false, want true", err)
+	}
 }
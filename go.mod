@@ -3,17 +3,17 @@ module github.com/GoogleCloudPlatform/k8s-cloud-provider
 go 1.20
 
 require (
+	github.com/go-logr/logr v1.2.3
 	github.com/google/go-cmp v0.5.9
 	github.com/kr/pretty v0.3.0
 	golang.org/x/oauth2 v0.6.0
 	google.golang.org/api v0.114.0
-	k8s.io/klog/v2 v2.0.0
+	k8s.io/klog/v2 v2.90.1
 )
 
 require (
 	cloud.google.com/go/compute v1.18.0 // indirect
 	cloud.google.com/go/compute/metadata v0.2.3 // indirect
-	github.com/go-logr/logr v0.1.0 // indirect
 	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
 	github.com/golang/protobuf v1.5.2 // indirect
 	github.com/google/uuid v1.3.0 // indirect
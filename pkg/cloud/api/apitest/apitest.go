@@ -0,0 +1,237 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apitest has helpers to certify the TypeTraits of api.Resources:
+// a round-trip fuzzer for the conversions between versions and golden file
+// comparisons of conversion and diff output.
+//
+// This package should only be used for testing.
+package apitest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+// RoundTripOption configures CheckRoundTrip().
+type RoundTripOption func(*roundTripConfig)
+
+type roundTripConfig struct {
+	iterations int
+	seed       int64
+	ignore     []api.Path
+}
+
+// Iterations sets the number of random objects to check (default 100).
+func Iterations(n int) RoundTripOption {
+	return func(c *roundTripConfig) { c.iterations = n }
+}
+
+// Seed sets the seed for the random values. The same seed generates the
+// same objects.
+func Seed(seed int64) RoundTripOption {
+	return func(c *roundTripConfig) { c.seed = seed }
+}
+
+// Ignore fields at or below paths that are known to not round-trip.
+func Ignore(paths ...api.Path) RoundTripOption {
+	return func(c *roundTripConfig) { c.ignore = append(c.ignore, paths...) }
+}
+
+// CheckRoundTrip fills the GA version of resources from newResource with
+// random values and checks that converting to Alpha and Beta and back to GA
+// does not lose any fields. Fields that do not exist in the other version
+// (i.e. are reported in a ConversionError) are not checked. Versions that are
+// not available (NoVersion) are skipped.
+//
+//	apitest.CheckRoundTrip(t, func() api.MutableResource[compute.Foo, alpha.Foo, beta.Foo] {
+//	  return foo.NewMutableFoo("proj", meta.GlobalKey("foo"))
+//	})
+func CheckRoundTrip[GA any, Alpha any, Beta any](
+	t testing.TB,
+	newResource func() api.MutableResource[GA, Alpha, Beta],
+	opts ...RoundTripOption,
+) {
+	t.Helper()
+
+	config := roundTripConfig{iterations: 100, seed: 1}
+	for _, o := range opts {
+		o(&config)
+	}
+
+	for i := 0; i < config.iterations; i++ {
+		seed := config.seed + int64(i)
+		rng := rand.New(rand.NewSource(seed))
+
+		var ga GA
+		if err := api.Fill(&ga, api.BasicFiller(randomFiller(rng))); err != nil {
+			t.Fatalf("seed %d: Fill() = %v, want nil", seed, err)
+			return
+		}
+		src := newResource()
+		if err := src.Set(&ga); err != nil {
+			t.Fatalf("seed %d: Set() = %v, want nil", seed, err)
+			return
+		}
+
+		// Alpha
+		alpha, err := src.ToAlpha()
+		if lost, ok := lostPaths(t, seed, meta.VersionAlpha, err); ok {
+			dest := newResource()
+			if err := dest.SetAlpha(alpha); err != nil {
+				t.Fatalf("seed %d: SetAlpha() = %v, want nil", seed, err)
+				return
+			}
+			checkGA(t, seed, meta.VersionAlpha, &ga, dest, append(lost, config.ignore...))
+		}
+
+		// Beta
+		beta, err := src.ToBeta()
+		if lost, ok := lostPaths(t, seed, meta.VersionBeta, err); ok {
+			dest := newResource()
+			if err := dest.SetBeta(beta); err != nil {
+				t.Fatalf("seed %d: SetBeta() = %v, want nil", seed, err)
+				return
+			}
+			checkGA(t, seed, meta.VersionBeta, &ga, dest, append(lost, config.ignore...))
+		}
+	}
+}
+
+// lostPaths returns the paths of the MissingFields in err from the
+// conversion to ver. Returns false if the version is not available or the
+// conversion failed.
+func lostPaths(t testing.TB, seed int64, ver meta.Version, err error) ([]api.Path, bool) {
+	t.Helper()
+
+	if err == nil {
+		return nil, true
+	}
+	if errors.Is(err, api.ErrVersionNotAvailable) {
+		return nil, false
+	}
+	var cerr *api.ConversionError
+	if !errors.As(err, &cerr) {
+		t.Errorf("seed %d: To(%s) = %v, want nil or ConversionError", seed, ver, err)
+		return nil, false
+	}
+	var ret []api.Path
+	for _, mf := range cerr.MissingFields {
+		ret = append(ret, mf.Path)
+	}
+	return ret, true
+}
+
+// checkGA compares the GA version of dest with want, ignoring fields at or
+// below the paths in ignore.
+func checkGA[GA any, Alpha any, Beta any](
+	t testing.TB,
+	seed int64,
+	ver meta.Version,
+	want *GA,
+	dest api.MutableResource[GA, Alpha, Beta],
+	ignore []api.Path,
+) {
+	t.Helper()
+
+	got, err := dest.ToGA()
+	// Fields that were added in ver (e.g. by a CopyHelper) are lost on the
+	// way back, which is not a round-trip error.
+	lost, ok := lostPaths(t, seed, meta.VersionGA, err)
+	if !ok {
+		return
+	}
+	ignore = append(ignore, lost...)
+
+	ignored := cmp.FilterPath(func(cp cmp.Path) bool {
+		p := apiPath(cp)
+		if len(p) > 0 {
+			switch p[len(p)-1] {
+			case ".NullFields", ".ForceSendFields", ".ServerResponse":
+				return true
+			}
+		}
+		for _, ip := range ignore {
+			if p.MatchPrefix(ip) {
+				return true
+			}
+		}
+		return false
+	}, cmp.Ignore())
+
+	if diff := cmp.Diff(got, want, ignored, equateEmpty); diff != "" {
+		t.Errorf("seed %d: GA => %s => GA did not round-trip: -got,+want: %s", seed, ver, diff)
+	}
+}
+
+// equateEmpty treats nil and empty slices and maps as equal. The
+// conversion does not preserve the distinction.
+var equateEmpty = cmp.FilterValues(func(a, b any) bool {
+	isEmpty := func(x any) bool {
+		v := reflect.ValueOf(x)
+		return (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0
+	}
+	return isEmpty(a) && isEmpty(b)
+}, cmp.Comparer(func(_, _ any) bool { return true }))
+
+// apiPath converts a cmp.Path to an api.Path.
+func apiPath(cp cmp.Path) api.Path {
+	ret := api.Path{}
+	for _, ps := range cp {
+		switch s := ps.(type) {
+		case cmp.StructField:
+			ret = ret.Field(s.Name())
+		case cmp.SliceIndex:
+			ret = ret.Index(s.Key())
+		case cmp.MapIndex:
+			ret = ret.MapIndex(s.Key().Interface())
+		case cmp.Indirect:
+			ret = ret.Pointer()
+		}
+	}
+	return ret
+}
+
+// randomFiller returns a BasicFiller that sets random values. One in four
+// values is left as the zero value.
+func randomFiller(rng *rand.Rand) func(reflect.Type, api.Path) any {
+	return func(t reflect.Type, _ api.Path) any {
+		v := reflect.New(t).Elem()
+		if rng.Intn(4) == 0 {
+			return v.Interface()
+		}
+		switch t.Kind() {
+		case reflect.Bool:
+			v.SetBool(true)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v.SetInt(int64(rng.Intn(100) + 1))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v.SetUint(uint64(rng.Intn(100) + 1))
+		case reflect.Float32, reflect.Float64:
+			v.SetFloat(float64(rng.Intn(1000)+1) / 10)
+		case reflect.String:
+			v.SetString(fmt.Sprintf("s%d", rng.Intn(1000)))
+		}
+		return v.Interface()
+	}
+}
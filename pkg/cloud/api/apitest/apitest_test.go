@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+type inner struct {
+	I               int
+	NullFields      []string
+	ForceSendFields []string
+}
+
+type ga struct {
+	Name            string
+	SelfLink        string
+	Mode            string
+	Shared          *inner
+	L               []string
+	M               map[string]string
+	GAOnly          int
+	NullFields      []string
+	ForceSendFields []string
+}
+
+type alpha struct {
+	Name            string
+	SelfLink        string
+	BalancingMode   string
+	Shared          *inner
+	L               []string
+	M               map[string]string
+	NullFields      []string
+	ForceSendFields []string
+}
+
+type beta = ga
+
+func newResource(tt api.TypeTrait[ga, alpha, beta]) func() api.MutableResource[ga, alpha, beta] {
+	return func() api.MutableResource[ga, alpha, beta] {
+		id := &cloud.ResourceID{ProjectID: "proj", Resource: "res", Key: meta.GlobalKey("obj")}
+		return api.NewResource[ga, alpha, beta](id, tt)
+	}
+}
+
+func traits(v meta.Version) *api.FieldTraits {
+	dt := &api.FieldTraits{}
+	switch v {
+	case meta.VersionGA, meta.VersionBeta:
+		dt.Convert(meta.VersionAlpha, api.Path{}.Pointer().Field("Mode"), api.ConvertRename("BalancingMode"))
+	case meta.VersionAlpha:
+		dt.Convert(meta.VersionGA, api.Path{}.Pointer().Field("BalancingMode"), api.ConvertRename("Mode"))
+		dt.Convert(meta.VersionBeta, api.Path{}.Pointer().Field("BalancingMode"), api.ConvertRename("Mode"))
+	}
+	return dt
+}
+
+// fakeTB records the errors instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeTB) Helper() {}
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+func (f *fakeTB) Fatalf(format string, args ...any) { f.Errorf(format, args...) }
+
+func TestCheckRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tt := &api.TypeTraitFuncs[ga, alpha, beta]{FieldTraitsF: traits}
+	CheckRoundTrip(t, newResource(tt), Iterations(20))
+}
+
+func TestCheckRoundTripFailure(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		tt      api.TypeTrait[ga, alpha, beta]
+		opts    []RoundTripOption
+		wantErr bool
+	}{
+		{
+			name: "CopyHelper drops a field",
+			tt: &api.TypeTraitFuncs[ga, alpha, beta]{
+				FieldTraitsF: traits,
+				CopyHelperAlphaToGAF: func(dest *ga, src *alpha) error {
+					dest.L = nil
+					return nil
+				},
+			},
+			wantErr: true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ftb := &fakeTB{}
+			CheckRoundTrip(ftb, newResource(tc.tt), append(tc.opts, Iterations(10))...)
+			if gotErr := len(ftb.errors) > 0; gotErr != tc.wantErr {
+				t.Errorf("CheckRoundTrip() errors = %q; gotErr = %t, want %t", ftb.errors, gotErr, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	tt := &api.TypeTraitFuncs[ga, alpha, beta]{FieldTraitsF: traits}
+	res := newResource(tt)()
+	if err := res.Set(&ga{Name: "obj", Mode: "RATE", GAOnly: 10, L: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	GoldenConversion(t, "testdata/conversion.golden", res)
+
+	res = newResource(tt)()
+	if err := res.Set(&ga{Name: "obj", Mode: "RATE", L: []string{"a", "b"}}); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	a, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	res = newResource(tt)()
+	if err := res.Set(&ga{Name: "obj", Mode: "UTILIZATION", L: []string{"a"}}); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	b, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	d, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff() = %v, want nil", err)
+	}
+	GoldenDiff(t, "testdata/diff.golden", d)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		return
+	}
+	ftb := &fakeTB{}
+	Golden(ftb, "testdata/diff.golden", "something else")
+	if len(ftb.errors) != 1 {
+		t.Errorf("Golden() errors = %q, want 1 error", ftb.errors)
+	}
+}
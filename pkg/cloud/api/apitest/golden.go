@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/google/go-cmp/cmp"
+)
+
+// UpdateGoldenEnv is the environment variable that causes the golden files to
+// be written instead of compared:
+//
+//	APITEST_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "APITEST_UPDATE_GOLDEN"
+
+// Golden compares got with the contents of the golden file at path.
+func Golden(t testing.TB, path string, got string) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Golden(%q): %v", path, err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Golden(%q): %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Golden(%q): %v (set %s=1 to create the file)", path, err, UpdateGoldenEnv)
+		return
+	}
+	if diff := cmp.Diff(got, string(want)); diff != "" {
+		t.Errorf("Golden(%q): -got,+want: %s", path, diff)
+	}
+}
+
+// GoldenConversion compares all versions of res and the conversion errors
+// (see MutableResource.DebugString()) with the golden file at path.
+func GoldenConversion[GA any, Alpha any, Beta any](t testing.TB, path string, res api.MutableResource[GA, Alpha, Beta]) {
+	t.Helper()
+	Golden(t, path, res.DebugString())
+}
+
+// GoldenDiff compares the formatted DiffResult (see FormatDiff()) with the
+// golden file at path.
+func GoldenDiff(t testing.TB, path string, d *api.DiffResult) {
+	t.Helper()
+	Golden(t, path, FormatDiff(d))
+}
+
+// FormatDiff formats the DiffResult with one line per DiffItem.
+func FormatDiff(d *api.DiffResult) string {
+	var sb strings.Builder
+	for _, item := range d.Items {
+		fmt.Fprintf(&sb, "%s %s: %v => %v", item.State, item.Path, item.A, item.B)
+		if item.Immutable {
+			sb.WriteString(" (immutable)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package celrule implements api.ValidateFuncs using CEL
+// (https://github.com/google/cel-spec) expressions. This allows validations
+// to be written declaratively and shared between controllers:
+//
+//	dt.Validate(api.Path{}.Pointer(), celrule.Must(
+//	  `!has(self.ports) || self.IPProtocol == "TCP"`))
+//	dt.Validate(api.Path{}.Pointer().Field("Port"), celrule.Must(
+//	  `self >= 1 && self <= 65535`))
+//
+// (The examples are for a ForwardingRule.)
+//
+// The value of the field is bound to the variable "self". Structs are
+// represented as maps keyed by the JSON name of the fields. Zero-valued
+// fields and the metafields (by default NullFields, ForceSendFields and
+// ServerResponse; see Metafields()) are omitted so has() can be used to check
+// if a field is set.
+//
+// celrule is a separate module so that users of the api package do not
+// depend on cel-go.
+package celrule
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/google/cel-go/cel"
+)
+
+// Option for New() and Must().
+type Option func(*config)
+
+type config struct {
+	metafields api.MetafieldNames
+}
+
+// Metafields sets the names of the metafields that are omitted from the
+// value bound to "self". This should match the FieldTraits.Metafields() of
+// the type. The default is api.DefaultMetafieldNames().
+func Metafields(names api.MetafieldNames) Option {
+	return func(c *config) { c.metafields = names }
+}
+
+// New returns a ValidateFunc that evaluates the CEL expression expr. The
+// field is invalid if expr evaluates to false. expr must evaluate to a bool.
+func New(expr string, opts ...Option) (api.ValidateFunc, error) {
+	c := config{metafields: api.DefaultMetafieldNames()}
+	for _, o := range opts {
+		o(&c)
+	}
+	env, err := cel.NewEnv(cel.Variable("self", cel.DynType))
+	if err != nil {
+		return nil, fmt.Errorf("celrule: %w", err)
+	}
+	ast, iss := env.Compile(expr)
+	if iss.Err() != nil {
+		return nil, fmt.Errorf("celrule: %q: %w", expr, iss.Err())
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("celrule: %q evaluates to %s, want bool", expr, ast.OutputType())
+	}
+	prg, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("celrule: %q: %w", expr, err)
+	}
+
+	return func(v any) error {
+		out, _, err := prg.Eval(map[string]any{"self": toCEL(reflect.ValueOf(v), c.metafields)})
+		if err != nil {
+			return fmt.Errorf("rule %q: %w", expr, err)
+		}
+		ok, isBool := out.Value().(bool)
+		if !isBool {
+			return fmt.Errorf("rule %q evaluated to %v, want bool", expr, out.Value())
+		}
+		if !ok {
+			return fmt.Errorf("failed rule %q", expr)
+		}
+		return nil
+	}, nil
+}
+
+// Must is like New but panics if the expression is invalid. It is intended
+// for use when defining the FieldTraits of a type.
+func Must(expr string, opts ...Option) api.ValidateFunc {
+	f, err := New(expr, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// toCEL converts v to the native types understood by CEL. The metafields
+// names are omitted from structs.
+func toCEL(v reflect.Value, names api.MetafieldNames) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return toCEL(v.Elem(), names)
+	case reflect.Struct:
+		ret := map[string]any{}
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			fv := v.Field(i)
+			if !sf.IsExported() || fv.IsZero() {
+				continue
+			}
+			if isMetafield(sf.Name, names) {
+				continue
+			}
+			ret[jsonName(sf)] = toCEL(fv, names)
+		}
+		return ret
+	case reflect.Slice, reflect.Array:
+		var ret []any
+		for i := 0; i < v.Len(); i++ {
+			ret = append(ret, toCEL(v.Index(i), names))
+		}
+		return ret
+	case reflect.Map:
+		ret := map[string]any{}
+		iter := v.MapRange()
+		for iter.Next() {
+			ret[fmt.Sprint(iter.Key().Interface())] = toCEL(iter.Value(), names)
+		}
+		return ret
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint()
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	}
+	return v.Interface()
+}
+
+// isMetafield returns true if fieldName is one of the names.
+func isMetafield(fieldName string, names api.MetafieldNames) bool {
+	for _, n := range []string{names.NullFields, names.ForceSendFields, names.ServerResponse} {
+		if n != "" && fieldName == n {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonName of the struct field, e.g. "ipProtocol" for IPProtocol.
+func jsonName(sf reflect.StructField) string {
+	if tag, ok := sf.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return sf.Name
+}
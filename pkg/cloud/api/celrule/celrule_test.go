@@ -0,0 +1,135 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package celrule
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestRule(t *testing.T) {
+	t.Parallel()
+
+	type rule struct {
+		IPProtocol      string   `json:"IPProtocol,omitempty"`
+		Ports           []string `json:"ports,omitempty"`
+		Port            int64    `json:"port,omitempty"`
+		NullFields      []string `json:"-"`
+		ForceSendFields []string `json:"-"`
+	}
+
+	for _, tc := range []struct {
+		name    string
+		expr    string
+		v       any
+		wantErr bool
+	}{
+		{name: "in range", expr: "self >= 1 && self <= 65535", v: int64(80)},
+		{name: "out of range", expr: "self >= 1 && self <= 65535", v: int64(70000), wantErr: true},
+		{name: "int", expr: "self == 10", v: 10},
+		{name: "string", expr: `self.startsWith("10.")`, v: "10.0.0.1"},
+		{
+			name: "cross field",
+			expr: `!has(self.ports) || self.IPProtocol == "TCP"`,
+			v:    &rule{IPProtocol: "TCP", Ports: []string{"80"}},
+		},
+		{
+			name:    "cross field invalid",
+			expr:    `!has(self.ports) || self.IPProtocol == "TCP"`,
+			v:       &rule{IPProtocol: "UDP", Ports: []string{"80"}},
+			wantErr: true,
+		},
+		{
+			name: "unset field",
+			expr: `!has(self.ports) || self.IPProtocol == "TCP"`,
+			v:    &rule{IPProtocol: "UDP"},
+		},
+		{name: "not a bool", expr: "self", v: "abc", wantErr: true},
+	} {
+		f, err := New(tc.expr)
+		if err != nil {
+			t.Errorf("%s: New(%q) = %v, want nil", tc.name, tc.expr, err)
+			continue
+		}
+		if err := f(tc.v); (err != nil) != tc.wantErr {
+			t.Errorf("%s: f(%v) = %v; wantErr = %t", tc.name, tc.v, err, tc.wantErr)
+		}
+	}
+
+	for _, expr := range []string{"self +", `"abc"`} {
+		if _, err := New(expr); err == nil {
+			t.Errorf("New(%q) = _, nil; want error", expr)
+		}
+	}
+}
+
+func TestRuleInFieldTraits(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Name            string
+		SelfLink        string
+		Port            int64 `json:"port,omitempty"`
+		NullFields      []string
+		ForceSendFields []string
+	}
+	traits := &api.FieldTraits{}
+	traits.AllowZeroValue(api.Path{}.Pointer().Field("SelfLink"))
+	traits.Validate(api.Path{}.Pointer().Field("Port"), Must("self >= 1 && self <= 65535"))
+	tt := &api.TypeTraitFuncs[st, st, st]{
+		FieldTraitsF: func(meta.Version) *api.FieldTraits { return traits },
+	}
+	res := api.NewResource[st, st, st](&cloud.ResourceID{Key: meta.GlobalKey("x")}, tt)
+	if err := res.Access(func(x *st) { x.Port = 80 }); err != nil {
+		t.Errorf("Access(Port=80) = %v, want nil", err)
+	}
+	if err := res.Access(func(x *st) { x.Port = 0x10000 }); err == nil {
+		t.Errorf("Access(Port=65536) = nil, want error")
+	}
+}
+
+func TestMetafields(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Name   string   `json:"name,omitempty"`
+		Nulls  []string `json:"nulls,omitempty"`
+		Forces []string `json:"forces,omitempty"`
+	}
+	v := &st{Name: "x", Nulls: []string{"A"}, Forces: []string{"B"}}
+	const expr = "!has(self.nulls) && !has(self.forces)"
+
+	// The default metafields are the NullFields and ForceSendFields.
+	f, err := New(expr)
+	if err != nil {
+		t.Fatalf("New(%q) = %v, want nil", expr, err)
+	}
+	if err := f(v); err == nil {
+		t.Errorf("f(%+v) = nil, want error", v)
+	}
+
+	f, err = New(expr, Metafields(api.MetafieldNames{NullFields: "Nulls", ForceSendFields: "Forces"}))
+	if err != nil {
+		t.Fatalf("New(%q, Metafields()) = %v, want nil", expr, err)
+	}
+	if err := f(v); err != nil {
+		t.Errorf("f(%+v) = %v, want nil", v, err)
+	}
+}
@@ -19,24 +19,80 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
+// FieldError is a single field that failed validation after an Access().
+type FieldError struct {
+	// Path of the field.
+	Path Path
+	// FieldType of the field from the FieldTraits.
+	FieldType FieldType
+	// Msg describes the problem.
+	Msg string
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s (%s) %s", e.Path, e.FieldType, e.Msg)
+}
+
+// ValidationError contains all of the FieldErrors found when validating a
+// resource after an Access(). Individual FieldErrors can be retrieved with
+// errors.As() or by inspecting Errors.
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	var msgs []string
+	for _, fe := range e.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+	return fmt.Sprintf("%d fields are invalid: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the FieldErrors.
+func (e *ValidationError) Unwrap() []error {
+	var ret []error
+	for _, fe := range e.Errors {
+		ret = append(ret, fe)
+	}
+	return ret
+}
+
 // checkPostAccess validates the fields for consistency. See the error messages
-// below for the properties being checked.
+// below for the properties being checked. All of the invalid fields are
+// returned in a *ValidationError.
 func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
+	var verr ValidationError
+	addErr := func(p Path, fType FieldType, format string, args ...any) {
+		verr.Errors = append(verr.Errors, &FieldError{
+			// Copy p as the visitor reuses the underlying array.
+			Path:      append(Path{}, p...),
+			FieldType: fType,
+			Msg:       fmt.Sprintf(format, args...),
+		})
+	}
+
+	names := traits.metafieldNames()
 	acc := newAcceptorFuncs()
 	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
-		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
+		if names.isServerResponse(p) {
 			return false, nil
 		}
 
-		acc, err := newMetafieldAccessor(v)
+		acc, err := newMetafieldAccessor(v, names)
 		if err != nil {
 			return false, fmt.Errorf("checkPostAccess %v: %w", p, err)
 		}
 		for i := 0; i < v.NumField(); i++ {
 			ft := v.Type().Field(i)
-			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" {
+			if names.isMetafield(ft.Name) {
 				continue
 			}
 			fType := traits.fieldType(p.Field(ft.Name))
@@ -44,20 +100,19 @@ func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 			fp := p.Field(ft.Name)
 
 			switch fType {
-			case FieldTypeSystem:
+			case FieldTypeSystem, FieldTypeOutputOnly:
 				if !fv.IsZero() {
-					return false, fmt.Errorf("%s has a non-zero value (%v) but is a System field", fv.Interface(), fp)
-				}
-			case FieldTypeOutputOnly:
-				if !fv.IsZero() {
-					return false, fmt.Errorf("%s has a non-zero value (%v) but is an OutputOnly field", fv.Interface(), fp)
+					addErr(fp, fType, "has a non-zero value (%v) but cannot be set", fv.Interface())
 				}
 			case FieldTypeOrdinary:
 				switch {
+				case acc.empty():
+					// Without metafields, a zero value cannot be
+					// distinguished from an unset field.
 				case fv.IsZero() && !acc.inNull(ft.Name) && !acc.inForceSend(ft.Name):
-					return false, fmt.Errorf("%s is zero value but not in a NullFields or ForceSendFields %v %t", fp, fv.Interface(), fv.IsZero())
+					addErr(fp, fType, "is zero value (%v) but not in a NullFields or ForceSendFields", fv.Interface())
 				case !fv.IsZero() && acc.inNull(ft.Name):
-					return false, fmt.Errorf("%s is non-nil and also in NullFields", fp)
+					addErr(fp, fType, "is non-nil and also in NullFields")
 				}
 			case FieldTypeAllowZeroValue:
 				continue
@@ -67,79 +122,214 @@ func checkPostAccess(traits *FieldTraits, v reflect.Value) error {
 		}
 		return true, nil
 	}
-	return visit(v, acc)
+	if err := visit(v, acc); err != nil {
+		return err
+	}
+	if traits != nil && len(traits.validators) > 0 {
+		validate := func(p Path, v reflect.Value) (bool, error) {
+			// Unset fields are not validated.
+			if v.IsZero() {
+				return true, nil
+			}
+			for _, f := range traits.validatorsFor(p) {
+				if err := f(v.Interface()); err != nil {
+					addErr(p, traits.fieldType(p), "is invalid: %v", err)
+				}
+			}
+			return true, nil
+		}
+		if err := visit(v, acceptorFromFunc(validate)); err != nil {
+			return err
+		}
+	}
+	if len(verr.Errors) > 0 {
+		return &verr
+	}
+	return nil
+}
+
+// checkRequired validates that the Required() fields in traits are set in v.
+// All of the unset fields are returned in a *ValidationError.
+func checkRequired(traits *FieldTraits, v reflect.Value) error {
+	if traits == nil || len(traits.required) == 0 {
+		return nil
+	}
+	var verr ValidationError
+	names := traits.metafieldNames()
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		if names.isServerResponse(p) {
+			return false, nil
+		}
+		var mfa *metafieldAccessor
+		for i := 0; i < v.NumField(); i++ {
+			ft := v.Type().Field(i)
+			fp := p.Field(ft.Name)
+			if !traits.isRequired(fp) || !v.Field(i).IsZero() {
+				continue
+			}
+			if mfa == nil {
+				var err error
+				if mfa, err = newMetafieldAccessor(v, names); err != nil {
+					return false, fmt.Errorf("checkRequired %v: %w", p, err)
+				}
+			}
+			if mfa.inForceSend(ft.Name) {
+				continue
+			}
+			verr.Errors = append(verr.Errors, &FieldError{
+				Path:      append(Path{}, fp...),
+				FieldType: traits.fieldType(fp),
+				Msg:       "is required but not set",
+			})
+		}
+		return true, nil
+	}
+	if err := visit(v, acc); err != nil {
+		return err
+	}
+	if len(verr.Errors) > 0 {
+		return &verr
+	}
+	return nil
+}
+
+// CycleError is returned by the schema check when a struct type contains
+// itself, directly or through other types. The cycle can be broken by marking
+// a field on the cycle with FieldTraits.Opaque().
+type CycleError struct {
+	// Path where the cycle was found.
+	Path Path
+	// Types on the cycle. The first and last elements are the same type.
+	Types []reflect.Type
+}
+
+// Error implements error.
+func (e *CycleError) Error() string {
+	var names []string
+	for _, t := range e.Types {
+		names = append(names, typeName(t))
+	}
+	return fmt.Sprintf("recursive type found at %s: %s (use FieldTraits.Opaque() to break the cycle)", e.Path, strings.Join(names, " -> "))
+}
+
+// typeName returns a human readable name for t. Unnamed types use their
+// definition.
+func typeName(t reflect.Type) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+	return fmt.Sprintf("%s.%s", t.PkgPath(), t.Name())
 }
 
 // checkNoCycles there are no cycles where a struct type appears 2+ times on the
 // same path. Our algorithms requires special handling for recursive structures.
-func checkNoCycles(p Path, t reflect.Type, seen []string) error {
+// Fields that are Opaque() in traits are not checked.
+func checkNoCycles(p Path, t reflect.Type, traits *FieldTraits) error {
+	cc := cycleChecker{traits: traits, done: map[reflect.Type]bool{}}
+	_, err := cc.do(p, t)
+	return err
+}
+
+type cycleChecker struct {
+	traits *FieldTraits
+	// stack of struct types on the current path.
+	stack []reflect.Type
+	// done are the struct types that have been checked and do not contain
+	// cycles.
+	done map[reflect.Type]bool
+}
+
+// do checks t. Returns true if an Opaque() field was skipped in t. The result
+// for such types depends on the Path so they are not added to done.
+func (cc *cycleChecker) do(p Path, t reflect.Type) (bool, error) {
+	if cc.traits.isOpaque(p) {
+		return true, nil
+	}
 	switch t.Kind() {
 	case reflect.Slice:
-		return checkNoCycles(p.Index(0), t.Elem(), seen)
+		return cc.do(p.Index(0), t.Elem())
 	case reflect.Pointer:
-		return checkNoCycles(p.Pointer(), t.Elem(), seen)
+		return cc.do(p.Pointer(), t.Elem())
 	case reflect.Map:
 		// Use "x" as the placeholder for the map key in the Path for debugging
 		// output purposes.
-		return checkNoCycles(p.MapIndex("x"), t.Elem(), seen)
+		return cc.do(p.MapIndex("x"), t.Elem())
 	case reflect.Struct:
-		typeName := fmt.Sprintf("%s/%s", t.PkgPath(), t.Name())
-		for _, seenTypeName := range seen {
-			if typeName == seenTypeName {
-				return fmt.Errorf("recursive type found at %s: %s", p, typeName)
+		if cc.done[t] {
+			return false, nil
+		}
+		for i, st := range cc.stack {
+			if st == t {
+				types := append([]reflect.Type{}, cc.stack[i:]...)
+				return false, &CycleError{Path: append(Path{}, p...), Types: append(types, t)}
 			}
 		}
 		// Add this struct type to the list of types seen on this path.
-		seen = append(seen, fmt.Sprintf("%s/%s", t.PkgPath(), t.Name()))
+		cc.stack = append(cc.stack, t)
+		defer func() { cc.stack = cc.stack[:len(cc.stack)-1] }()
+
+		var skipped bool
 		for i := 0; i < t.NumField(); i++ {
-			if err := checkNoCycles(p.Field(t.Field(i).Name), t.Field(i).Type, seen); err != nil {
-				return err
+			s, err := cc.do(p.Field(t.Field(i).Name), t.Field(i).Type)
+			if err != nil {
+				return false, err
 			}
+			skipped = skipped || s
 		}
+		if !skipped {
+			cc.done[t] = true
+		}
+		return skipped, nil
 	}
-	return nil
+	return false, nil
 }
 
 // checkResourceTypes the type is something we can handle. Assumes
-// checkNoCycles() passed.
-func checkResourceTypes(p Path, t reflect.Type) error {
+// checkNoCycles() passed. Fields that are Opaque() in traits are not checked.
+func checkResourceTypes(p Path, t reflect.Type, traits *FieldTraits) error {
 	// valid_type => basic | ...
-	if isBasicT(t) {
+	if isBasicT(t) || traits.isOpaque(p) {
 		return nil
 	}
 	switch t.Kind() {
 	case reflect.Pointer:
-		if err := checkResourceTypes(p, t.Elem()); err != nil {
+		if err := checkResourceTypes(p.Pointer(), t.Elem(), traits); err != nil {
 			return err
 		}
 	case reflect.Struct:
 		// struct => {all fields are valid_type}
 		for i := 0; i < t.NumField(); i++ {
 			tf := t.Field(i)
-			if err := checkResourceTypes(p.Field(tf.Name), tf.Type); err != nil {
+			if err := checkResourceTypes(p.Field(tf.Name), tf.Type, traits); err != nil {
 				return err
 			}
 		}
 	case reflect.Slice:
 		// slice => {elements => valid_type}
-		if err := checkResourceTypes(p.Index(0), t.Elem()); err != nil {
+		if err := checkResourceTypes(p.Index(0), t.Elem(), traits); err != nil {
 			return err
 		}
 	case reflect.Map:
 		// map => key is basic type; value is valid_type
 		if !isBasicT(t.Key()) {
-			return fmt.Errorf("map key must be basic type %s: %v", p.Pointer(), t)
+			return fmt.Errorf("map key must be basic type %s: %v", p, t)
 		}
 		// Supported value types.
 		if !isBasicT(t.Elem()) {
 			switch t.Elem().Kind() {
 			case reflect.Slice, reflect.Struct:
+			case reflect.Pointer:
+				// Only pointers to structs (e.g. map[string]*Config).
+				if t.Elem().Elem().Kind() != reflect.Struct {
+					return fmt.Errorf("unsupported value type %s: %v", p, t)
+				}
 			default:
 				return fmt.Errorf("unsupported value type %s: %v", p, t)
 			}
 			// Use "x" as the placeholder for the map key in the Path for debugging
 			// output purposes.
-			if err := checkResourceTypes(p.MapIndex("x"), t.Elem()); err != nil {
+			if err := checkResourceTypes(p.MapIndex("x"), t.Elem(), traits); err != nil {
 				return err
 			}
 		}
@@ -149,12 +339,15 @@ func checkResourceTypes(p Path, t reflect.Type) error {
 	return nil
 }
 
-func checkSchema(t reflect.Type) error {
+func checkSchema(t reflect.Type, traits *FieldTraits) error {
+	if traits == nil {
+		traits = &FieldTraits{}
+	}
 	// Run cycleCheck first, other checks will blow up if there are cycles.
-	if err := checkNoCycles(Path{}, t, []string{}); err != nil {
+	if err := checkNoCycles(Path{}, t, traits); err != nil {
 		return err
 	}
-	if err := checkResourceTypes(Path{}, t); err != nil {
+	if err := checkResourceTypes(Path{}, t, traits); err != nil {
 		return err
 	}
 	// Check that common fields are present.
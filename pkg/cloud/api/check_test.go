@@ -17,8 +17,14 @@ limitations under the License.
 package api
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestCheckFieldsAreSet(t *testing.T) {
@@ -134,10 +140,67 @@ func TestCheckFieldsAreSet(t *testing.T) {
 	}
 }
 
+func TestCheckPostAccessAllErrors(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		A               int
+		B               int
+		C               int
+		S               *sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+	ft := NewFieldTraits()
+	ft.OutputOnly(Path{}.Pointer().Field("C"))
+
+	in := &st{C: 1, S: &sti{}}
+	err := checkPostAccess(ft, reflect.ValueOf(in))
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("checkPostAccess() = %v, want ValidationError", err)
+	}
+	var got []string
+	for _, fe := range verr.Errors {
+		got = append(got, fmt.Sprintf("%s %s", fe.Path, fe.FieldType))
+	}
+	want := []string{
+		"*.A Ordinary",
+		"*.B Ordinary",
+		"*.C OutputOnly",
+		"*.S*.A Ordinary",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ValidationError.Errors; -got,+want: %s", diff)
+	}
+	// Individual errors are available via errors.As().
+	var fe *FieldError
+	if !errors.As(err, &fe) || !fe.Path.Equal(Path{}.Pointer().Field("A")) {
+		t.Errorf("errors.As(err, *FieldError) = %v, want *.A", fe)
+	}
+}
+
 // Mutually recursive types need to be declared outside of a func.
 type rec2 struct{ R *rec2i }
 type rec2i struct{ R *rec2 }
 
+// recSt is a resource with a recursive field.
+type recSt struct {
+	Name     string
+	SelfLink string
+	Expr     *recExpr
+}
+type recExpr struct {
+	Op   string
+	Args []*recExpr
+}
+
 func TestCheckNoCycles(t *testing.T) {
 	t.Parallel()
 
@@ -149,6 +212,9 @@ func TestCheckNoCycles(t *testing.T) {
 	type rec1 struct{ R *rec1 }
 	type rec3 struct{ R ****[]rec3 }
 	type rec4 struct{ R map[string]rec4 }
+	type anonSt struct {
+		A struct{ B struct{ C int } }
+	}
 
 	for _, tc := range []struct {
 		name    string
@@ -160,9 +226,10 @@ func TestCheckNoCycles(t *testing.T) {
 		{name: "mutually recursive", t: reflect.TypeOf(rec2{}), wantErr: true},
 		{name: "multiple indirect", t: reflect.TypeOf(rec3{}), wantErr: true},
 		{name: "map", t: reflect.TypeOf(rec4{}), wantErr: true},
+		{name: "unnamed structs", t: reflect.TypeOf(anonSt{})},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := checkNoCycles(Path{}, tc.t, []string{})
+			err := checkNoCycles(Path{}, tc.t, &FieldTraits{})
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Errorf("cycleCheck() = %v; gotErr = %t, want %T", err, gotErr, tc.wantErr)
@@ -171,6 +238,36 @@ func TestCheckNoCycles(t *testing.T) {
 	}
 }
 
+func TestCheckNoCyclesError(t *testing.T) {
+	t.Parallel()
+
+	err := checkNoCycles(Path{}, reflect.TypeOf(&rec2{}), &FieldTraits{})
+	var ce *CycleError
+	if !errors.As(err, &ce) {
+		t.Fatalf("checkNoCycles() = %v, want *CycleError", err)
+	}
+	wantPath := Path{}.Pointer().Field("R").Pointer().Field("R").Pointer()
+	if !ce.Path.Equal(wantPath) {
+		t.Errorf("ce.Path = %s, want %s", ce.Path, wantPath)
+	}
+	wantTypes := []reflect.Type{reflect.TypeOf(rec2{}), reflect.TypeOf(rec2i{}), reflect.TypeOf(rec2{})}
+	if !reflect.DeepEqual(ce.Types, wantTypes) {
+		t.Errorf("ce.Types = %v, want %v", ce.Types, wantTypes)
+	}
+	for _, s := range []string{"rec2 -> ", "rec2i -> ", "Opaque()"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("err = %q, want it to contain %q", err, s)
+		}
+	}
+
+	// Opaque() breaks the cycle.
+	traits := &FieldTraits{}
+	traits.Opaque(Path{}.Pointer().Field("R").Pointer().Field("R"))
+	if err := checkNoCycles(Path{}, reflect.TypeOf(&rec2{}), traits); err != nil {
+		t.Errorf("checkNoCycles() = %v, want nil", err)
+	}
+}
+
 func TestCheckResourceTypes(t *testing.T) {
 	t.Parallel()
 
@@ -182,8 +279,11 @@ func TestCheckResourceTypes(t *testing.T) {
 		LS  []string
 		LPS []*string
 		M   map[string]int
+		MST map[string]innerSt
+		MPS map[string]*innerSt
 		ST  innerSt
 		PST *innerSt
+		PLS *[]innerSt
 	}
 	type invalidSt1 struct {
 		M map[innerSt]int
@@ -192,7 +292,7 @@ func TestCheckResourceTypes(t *testing.T) {
 		C chan int
 	}
 	type invalidSt3 struct {
-		M map[int]*innerSt
+		M map[int]*int
 	}
 
 	for _, tc := range []struct {
@@ -221,7 +321,7 @@ func TestCheckResourceTypes(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := checkResourceTypes(Path{}, tc.t)
+			err := checkResourceTypes(Path{}, tc.t, &FieldTraits{})
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Errorf("typeCheck() = %v; gotErr = %t, want %T", err, gotErr, tc.wantErr)
@@ -252,18 +352,24 @@ func TestCheckSchema(t *testing.T) {
 		SelfLink string
 	}
 
+	opaqueTraits := &FieldTraits{}
+	opaqueTraits.Opaque(Path{}.Pointer().Field("Expr").Pointer().Field("Args"))
+
 	for _, tc := range []struct {
 		name    string
 		t       reflect.Type
+		traits  *FieldTraits
 		wantErr bool
 	}{
 		{name: "ok", t: reflect.TypeOf(&okSt{})},
 		{name: "fails cycle check", t: reflect.TypeOf(&rec2{}), wantErr: true},
 		{name: "fails type check", t: reflect.TypeOf(&badSt{}), wantErr: true},
 		{name: "fails type check bad fields", t: reflect.TypeOf(&badStFieldsBad{}), wantErr: true},
+		{name: "recursive", t: reflect.TypeOf(&recSt{}), wantErr: true},
+		{name: "recursive with opaque field", t: reflect.TypeOf(&recSt{}), traits: opaqueTraits},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			err := checkSchema(tc.t)
+			err := checkSchema(tc.t, tc.traits)
 			gotErr := err != nil
 			if gotErr != tc.wantErr {
 				t.Errorf("ChekcSchema() = %v; gotErr = %t, want %T", err, gotErr, tc.wantErr)
@@ -271,3 +377,178 @@ func TestCheckSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckPostAccessValidators(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		Port            int64
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		IPAddress       string
+		IPCIDRRange     string
+		Protocol        string
+		Ports           []int64
+		StP             *sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	ft := &FieldTraits{}
+	for _, f := range []string{"IPAddress", "IPCIDRRange", "Protocol", "Ports", "StP"} {
+		ft.AllowZeroValue(Path{}.Pointer().Field(f))
+	}
+	ft.Validate(Path{}.Pointer().Field("IPAddress"), ValidateIP())
+	ft.Validate(Path{}.Pointer().Field("IPCIDRRange"), ValidateCIDR())
+	ft.Validate(Path{}.Pointer().Field("Protocol"), ValidateOneOf("TCP", "UDP"))
+	ft.Validate(Path{}.Pointer().Field("Ports").AnySliceIndex(), ValidateRange(1, 65535))
+	ft.Validate(Path{}.Pointer().Field("StP").Pointer().Field("Port"), ValidateRange(1, 65535))
+	if err := ft.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		x        *st
+		wantErrs []string
+	}{
+		{name: "empty", x: &st{}},
+		{
+			name: "valid",
+			x: &st{
+				IPAddress:   "10.0.0.1",
+				IPCIDRRange: "10.0.0.0/8",
+				Protocol:    "TCP",
+				Ports:       []int64{80, 443},
+				StP:         &sti{Port: 8080},
+			},
+		},
+		{
+			name: "invalid",
+			x: &st{
+				IPAddress:   "10.0.0",
+				IPCIDRRange: "10.0.0.0",
+				Protocol:    "ICMP",
+				Ports:       []int64{80, 70000},
+				StP:         &sti{Port: 100000, ForceSendFields: []string{"Port"}},
+			},
+			wantErrs: []string{"*.IPAddress", "*.IPCIDRRange", "*.Ports!1", "*.Protocol", "*.StP*.Port"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPostAccess(ft, reflect.ValueOf(tc.x))
+			var gotErrs []string
+			if err != nil {
+				var verr *ValidationError
+				if !errors.As(err, &verr) {
+					t.Fatalf("checkPostAccess() = %v, want ValidationError", err)
+				}
+				for _, fe := range verr.Errors {
+					gotErrs = append(gotErrs, fe.Path.String())
+				}
+			}
+			sort.Strings(gotErrs)
+			if diff := cmp.Diff(gotErrs, tc.wantErrs); diff != "" {
+				t.Errorf("checkPostAccess() = %v; -got,+want: %s", err, diff)
+			}
+		})
+	}
+}
+
+func TestCheckPostAccessEnum(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		BalancingMode   string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	newTraits := func(values ...string) *FieldTraits {
+		ft := &FieldTraits{}
+		ft.AllowZeroValue(Path{}.Pointer().Field("BalancingMode"))
+		ft.Enum(Path{}.Pointer().Field("BalancingMode"), values...)
+		return ft
+	}
+	gaTraits := newTraits("RATE", "UTILIZATION", "CONNECTION")
+	alphaTraits := newTraits("RATE", "UTILIZATION", "CONNECTION", "CUSTOM_METRICS")
+
+	for _, tc := range []struct {
+		name    string
+		ft      *FieldTraits
+		x       *st
+		wantErr bool
+	}{
+		{name: "unset", ft: gaTraits, x: &st{}},
+		{name: "allowed", ft: gaTraits, x: &st{BalancingMode: "RATE"}},
+		{name: "misspelled", ft: gaTraits, x: &st{BalancingMode: "RATEE"}, wantErr: true},
+		{name: "alpha only value in GA", ft: gaTraits, x: &st{BalancingMode: "CUSTOM_METRICS"}, wantErr: true},
+		{name: "alpha only value in Alpha", ft: alphaTraits, x: &st{BalancingMode: "CUSTOM_METRICS"}},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkPostAccess(tc.ft, reflect.ValueOf(tc.x))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("checkPostAccess() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var fe *FieldError
+			if tc.wantErr && (!errors.As(err, &fe) || !fe.Path.Equal(Path{}.Pointer().Field("BalancingMode"))) {
+				t.Errorf("checkPostAccess() = %v, want FieldError for *.BalancingMode", err)
+			}
+		})
+	}
+}
+
+func TestCheckPostAccessWildcard(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		Group           string
+		Status          string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Backends        []*sti
+		M               map[string]sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	ft := &FieldTraits{}
+	ft.AllowZeroValue(Path{}.Pointer().Field("Backends"))
+	ft.AllowZeroValue(Path{}.Pointer().Field("M"))
+	ft.OutputOnly(Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Status"))
+	ft.OutputOnly(Path{}.Pointer().Field("M").AnyMapIndex().Field("Status"))
+	if err := ft.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	// Status is OutputOnly so it does not need to be in the ForceSendFields.
+	x := &st{
+		Backends: []*sti{{Group: "a"}, {Group: "b"}},
+		M:        map[string]sti{"k": {Group: "c"}},
+	}
+	if err := checkPostAccess(ft, reflect.ValueOf(x)); err != nil {
+		t.Errorf("checkPostAccess() = %v, want nil", err)
+	}
+
+	x.Backends[1].Status = "x"
+	x.M["k"] = sti{Group: "c", Status: "y"}
+	err := checkPostAccess(ft, reflect.ValueOf(x))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Errors) != 2 {
+		t.Fatalf("checkPostAccess() = %v, want 2 errors", err)
+	}
+	var gotErrs []string
+	for _, fe := range verr.Errors {
+		gotErrs = append(gotErrs, fe.Path.String())
+	}
+	sort.Strings(gotErrs)
+	if diff := cmp.Diff(gotErrs, []string{"*.Backends!1*.Status", "*.M:k.Status"}); diff != "" {
+		t.Errorf("checkPostAccess(); -got,+want: %s", diff)
+	}
+}
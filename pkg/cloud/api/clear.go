@@ -0,0 +1,144 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ClearField implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) ClearField(p Path) error {
+	if len(p) == 0 || p[len(p)-1][0] != pathField {
+		return fmt.Errorf("ClearField: path %s is not a field reference", p)
+	}
+	// All versions are checked before any of them are changed so that
+	// an error does not leave the resource partially cleared.
+	var targets []*clearTarget
+	for _, x := range []struct {
+		ver meta.Version
+		v   reflect.Value
+	}{
+		{meta.VersionGA, reflect.ValueOf(&u.ga)},
+		{meta.VersionAlpha, reflect.ValueOf(&u.alpha)},
+		{meta.VersionBeta, reflect.ValueOf(&u.beta)},
+	} {
+		if isNoVersion(x.v.Type()) {
+			continue
+		}
+		target, err := clearPath(p, x.v, u.metafieldNames(x.ver))
+		if err != nil {
+			return fmt.Errorf("ClearField: %w", err)
+		}
+		if target != nil {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("ClearField: field %s does not exist in any version", p)
+	}
+	for _, target := range targets {
+		target.clear()
+	}
+
+	// The field no longer has a value that can be lost in a conversion.
+	for cc := range u.errors {
+		var missing []missingFieldOnCopy
+		for _, mf := range u.errors[cc].missingFields {
+			if !mf.Path.HasPrefix(p) {
+				missing = append(missing, mf)
+			}
+		}
+		u.errors[cc].missingFields = missing
+	}
+	if u.delta != nil {
+		u.delta.add(p)
+	}
+	if u.setFields != nil {
+		u.setFields.add(p)
+	}
+	return nil
+}
+
+// clearTarget is a field to be cleared (see clearPath()).
+type clearTarget struct {
+	// v is the field. v is invalid if a pointer along the path is nil, in
+	// which case the field is already unset.
+	v    reflect.Value
+	name string
+	acc  *metafieldAccessor
+}
+
+// clear sets the field to the zero value and adds it to the NullFields (for
+// pointers, slices and maps) or ForceSendFields of the containing struct.
+func (c *clearTarget) clear() {
+	if !c.v.IsValid() {
+		return
+	}
+	c.v.Set(reflect.Zero(c.v.Type()))
+	var nullValue bool
+	switch c.v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		nullValue = true
+	}
+	c.acc.set(c.name, nullValue)
+}
+
+// clearPath returns the clearTarget for the field at p in v. p may only
+// contain field references and pointer dereferences. Returns nil if the field
+// does not exist in the type of v. v is not changed. names are the metafields
+// of the structs.
+func clearPath(p Path, v reflect.Value, names MetafieldNames) (*clearTarget, error) {
+	for i, x := range p {
+		switch x[0] {
+		case pathField:
+			if v.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("%s: field reference %q on non-struct type %s", p, x, v.Type())
+			}
+			fieldName := x[1:]
+			if _, ok := v.Type().FieldByName(fieldName); !ok {
+				return nil, nil
+			}
+			if i < len(p)-1 {
+				v = v.FieldByName(fieldName)
+				continue
+			}
+			acc, err := newMetafieldAccessor(v, names)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", p, err)
+			}
+			return &clearTarget{v: v.FieldByName(fieldName), name: fieldName, acc: acc}, nil
+		case pathPointer:
+			if v.Kind() != reflect.Pointer {
+				return nil, fmt.Errorf("%s: pointer dereference on non-pointer type %s", p, v.Type())
+			}
+			if v.IsNil() {
+				// Check that the field exists in the type.
+				if _, err := p[i:].ResolveType(v.Type()); err != nil {
+					return nil, nil
+				}
+				return &clearTarget{}, nil
+			}
+			v = v.Elem()
+		default:
+			return nil, fmt.Errorf("%s: unsupported path element %q", p, x)
+		}
+	}
+	return nil, nil
+}
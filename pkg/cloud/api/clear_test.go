@@ -0,0 +1,158 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClearField(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		I               int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		StP             *inner
+		LStr            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		Name            string
+		SelfLink        string
+		StP             *inner
+		LStr            []string
+		AI              int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alpha, alpha](&testTrait[ga, alpha, alpha]{})
+	if err := res.AccessAlpha(func(x *alpha) {
+		x.StP = &inner{I: 1, S: "abc", NullFields: []string{"S"}}
+		x.LStr = []string{"a"}
+		x.AI = 10
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	res.RecordDelta()
+
+	for _, p := range []Path{
+		Path{}.Pointer().Field("StP").Pointer().Field("S"),
+		Path{}.Pointer().Field("LStr"),
+		Path{}.Pointer().Field("AI"),
+	} {
+		if err := res.ClearField(p); err != nil {
+			t.Fatalf("ClearField(%v) = %v, want nil", p, err)
+		}
+	}
+
+	gaObj, err := res.ToGA()
+	if err != nil {
+		t.Errorf("ToGA() = _, %v; want nil (AI was cleared)", err)
+	}
+	wantGA := &ga{
+		Name:       "obj-1",
+		StP:        &inner{I: 1, ForceSendFields: []string{"S"}},
+		NullFields: []string{"LStr"},
+	}
+	if diff := cmp.Diff(gaObj, wantGA); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+	alphaObj, _ := res.ToAlpha()
+	wantAlpha := &alpha{
+		Name:            "obj-1",
+		StP:             &inner{I: 1, ForceSendFields: []string{"S"}},
+		NullFields:      []string{"LStr"},
+		ForceSendFields: []string{"AI"},
+	}
+	if diff := cmp.Diff(alphaObj, wantAlpha); diff != "" {
+		t.Errorf("ToAlpha(); -got,+want: %s", diff)
+	}
+	delta, err := res.Delta()
+	if err != nil || len(delta.Paths) != 3 {
+		t.Errorf("Delta() = %+v, %v; want 3 paths", delta, err)
+	}
+
+	// Clearing a field below a nil pointer is a no-op.
+	if err := res.Access(func(x *ga) { x.StP = nil }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := res.ClearField(Path{}.Pointer().Field("StP").Pointer().Field("I")); err != nil {
+		t.Errorf("ClearField(StP.I) = %v, want nil", err)
+	}
+
+	for _, p := range []Path{
+		Path{}.Pointer().Field("Missing"),
+		Path{}.Pointer().Field("LStr").Index(0),
+		Path{}.Field("Name"),
+		{},
+	} {
+		if err := res.ClearField(p); err == nil {
+			t.Errorf("ClearField(%v) = nil, want error", p)
+		}
+	}
+}
+
+func TestClearFieldNoPartialChange(t *testing.T) {
+	t.Parallel()
+
+	type innerGA struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	// innerAlpha does not have the metafields, so I cannot be cleared in
+	// the Alpha version.
+	type innerAlpha struct {
+		I int
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		StP             *innerGA
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		Name            string
+		SelfLink        string
+		StP             *innerAlpha
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alpha, alpha](&testTrait[ga, alpha, alpha]{})
+	if err := res.Access(func(x *ga) { x.StP = &innerGA{I: 1} }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := res.ClearField(Path{}.Pointer().Field("StP").Pointer().Field("I")); err == nil {
+		t.Fatalf("ClearField(StP.I) = nil, want error")
+	}
+	gaObj, _ := res.ToGA()
+	if diff := cmp.Diff(gaObj.StP, &innerGA{I: 1}); diff != "" {
+		t.Errorf("ToGA().StP after ClearField() error; -got,+want: %s", diff)
+	}
+}
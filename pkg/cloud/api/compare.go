@@ -0,0 +1,55 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// CompareFunc returns true if the field values a and b are equivalent. The
+// values are of the type of the field. See FieldTraits.Compare().
+type CompareFunc func(a, b any) bool
+
+// CompareEqualFold compares string fields ignoring case, e.g. for enum
+// values that are canonicalized to upper case by the API.
+func CompareEqualFold(a, b any) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return reflect.DeepEqual(a, b)
+	}
+	return strings.EqualFold(as, bs)
+}
+
+// CompareDuration compares string fields containing durations by their
+// value, e.g. "10s" and "10.000s" are equal. Strings that are not valid
+// durations are compared as strings.
+func CompareDuration(a, b any) bool {
+	as, aok := a.(string)
+	bs, bok := b.(string)
+	if !aok || !bok {
+		return reflect.DeepEqual(a, b)
+	}
+	ad, aErr := time.ParseDuration(as)
+	bd, bErr := time.ParseDuration(bs)
+	if aErr != nil || bErr != nil {
+		return as == bs
+	}
+	return ad == bd
+}
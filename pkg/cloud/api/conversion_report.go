@@ -0,0 +1,66 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ConversionReport lists the fields that would be lost converting a resource
+// to each API version. Fields tolerated by MutableResource.TolerateMissingFields()
+// are included as they are also lost in the conversion.
+type ConversionReport struct {
+	// MissingFields by version. Versions that can represent all of the
+	// fields have an empty (non-nil) list. Versions that are not available
+	// for the resource type (see NoVersion) are not present in the map.
+	MissingFields map[meta.Version][]MissingField
+}
+
+// Lossless returns true if the resource can be converted to ver without
+// losing any fields.
+func (r *ConversionReport) Lossless(ver meta.Version) bool {
+	mf, ok := r.MissingFields[ver]
+	return ok && len(mf) == 0
+}
+
+// LosslessVersions returns the versions that can represent all of the fields
+// of the resource, ordered from the most to the least stable (GA, Beta,
+// Alpha).
+func (r *ConversionReport) LosslessVersions() []meta.Version {
+	var ret []meta.Version
+	for _, ver := range []meta.Version{meta.VersionGA, meta.VersionBeta, meta.VersionAlpha} {
+		if r.Lossless(ver) {
+			ret = append(ret, ver)
+		}
+	}
+	return ret
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) ConversionReport() *ConversionReport {
+	ret := &ConversionReport{MissingFields: map[meta.Version][]MissingField{}}
+	for _, ver := range meta.AllVersions {
+		if u.available(ver) != nil {
+			continue
+		}
+		mf := append(u.missingFields(ver, false), u.missingFields(ver, true)...)
+		if mf == nil {
+			mf = []MissingField{}
+		}
+		ret.MissingFields[ver] = mf
+	}
+	return ret
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConversionReport(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		I               int
+		BI              int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		I               int
+		AI              int
+		BI              int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name      string
+		f         func(MutableResource[ga, alpha, beta]) error
+		wantPaths map[meta.Version][]string
+		wantVers  []meta.Version
+	}{
+		{
+			name: "all versions",
+			f: func(r MutableResource[ga, alpha, beta]) error {
+				return r.Access(func(x *ga) { x.I = 1 })
+			},
+			wantPaths: map[meta.Version][]string{
+				meta.VersionGA:    nil,
+				meta.VersionAlpha: nil,
+				meta.VersionBeta:  nil,
+			},
+			wantVers: []meta.Version{meta.VersionGA, meta.VersionBeta, meta.VersionAlpha},
+		},
+		{
+			name: "requires beta",
+			f: func(r MutableResource[ga, alpha, beta]) error {
+				return r.AccessBeta(func(x *beta) { x.I = 1; x.BI = 2 })
+			},
+			wantPaths: map[meta.Version][]string{
+				meta.VersionGA:    {"*.BI"},
+				meta.VersionAlpha: nil,
+				meta.VersionBeta:  nil,
+			},
+			wantVers: []meta.Version{meta.VersionBeta, meta.VersionAlpha},
+		},
+		{
+			name: "requires alpha",
+			f: func(r MutableResource[ga, alpha, beta]) error {
+				return r.AccessAlpha(func(x *alpha) { x.I = 1; x.AI = 2; x.BI = 3 })
+			},
+			wantPaths: map[meta.Version][]string{
+				meta.VersionGA:    {"*.AI", "*.BI"},
+				meta.VersionAlpha: nil,
+				meta.VersionBeta:  {"*.AI"},
+			},
+			wantVers: []meta.Version{meta.VersionAlpha},
+		},
+		{
+			name: "tolerated fields are reported",
+			f: func(r MutableResource[ga, alpha, beta]) error {
+				r.TolerateMissingFields(Path{}.Pointer().Field("AI"))
+				return r.AccessAlpha(func(x *alpha) { x.I = 1; x.AI = 2 })
+			},
+			wantPaths: map[meta.Version][]string{
+				meta.VersionGA:    {"*.AI"},
+				meta.VersionAlpha: nil,
+				meta.VersionBeta:  {"*.AI"},
+			},
+			wantVers: []meta.Version{meta.VersionAlpha},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := newTestResource[ga, alpha, beta](&testTrait[ga, alpha, beta]{})
+			if err := tc.f(res); err != nil {
+				t.Fatalf("f() = %v, want nil", err)
+			}
+			report := res.ConversionReport()
+			gotPaths := map[meta.Version][]string{}
+			for ver, mfs := range report.MissingFields {
+				gotPaths[ver] = nil
+				for _, mf := range mfs {
+					gotPaths[ver] = append(gotPaths[ver], mf.Path.String())
+				}
+			}
+			if diff := cmp.Diff(gotPaths, tc.wantPaths); diff != "" {
+				t.Errorf("MissingFields: -got,+want: %s", diff)
+			}
+			if diff := cmp.Diff(report.LosslessVersions(), tc.wantVers); diff != "" {
+				t.Errorf("LosslessVersions(): -got,+want: %s", diff)
+			}
+
+			// The report does not depend on the version of the
+			// frozen Resource.
+			res.VersionPreference(tc.wantVers...)
+			frozen, err := res.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(frozen.ConversionReport(), report); diff != "" {
+				t.Errorf("Resource.ConversionReport(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestConversionReportNoVersion(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	res := newTestResource[st, NoVersion, st](&testTrait[st, NoVersion, st]{})
+	if err := res.Access(func(x *st) { x.I = 1 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	report := res.ConversionReport()
+	if _, ok := report.MissingFields[meta.VersionAlpha]; ok || report.Lossless(meta.VersionAlpha) {
+		t.Errorf("report = %+v, want no entry for Alpha", report)
+	}
+	if diff := cmp.Diff(report.LosslessVersions(), []meta.Version{meta.VersionGA, meta.VersionBeta}); diff != "" {
+		t.Errorf("LosslessVersions(): -got,+want: %s", diff)
+	}
+}
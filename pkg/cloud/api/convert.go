@@ -0,0 +1,124 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ConvertFunc converts the value of a field when copying between versions.
+// dest is a pointer to the destination struct that contains the field (e.g.
+// *alpha.BackendService) and src is the value of the field in the source
+// version. See FieldTraits.Convert().
+//
+// Field names in the NullFields and ForceSendFields of the source struct are
+// not translated; f must set the metafields of dest if needed.
+type ConvertFunc func(dest any, src any) error
+
+// ConvertRename returns a ConvertFunc that copies the value to the field
+// fieldName in the destination struct. The types of the fields must be the
+// same.
+func ConvertRename(fieldName string) ConvertFunc {
+	return func(dest any, src any) error {
+		fv, err := convertDestField(dest, fieldName)
+		if err != nil {
+			return err
+		}
+		sv := reflect.ValueOf(src)
+		if sv.Type() != fv.Type() {
+			return fmt.Errorf("ConvertRename: field %q has type %s, want %s", fieldName, fv.Type(), sv.Type())
+		}
+		return newCopier().doValues(Path{}.Field(fieldName), fv, sv)
+	}
+}
+
+// ConvertEnum returns a ConvertFunc for a string field where the values in
+// the destination version are given by the mapping. Values not in the mapping
+// are copied as-is.
+func ConvertEnum(fieldName string, mapping map[string]string) ConvertFunc {
+	return func(dest any, src any) error {
+		fv, err := convertDestField(dest, fieldName)
+		if err != nil {
+			return err
+		}
+		s, ok := src.(string)
+		if !ok || fv.Kind() != reflect.String {
+			return fmt.Errorf("ConvertEnum: field %q is not a string (src %T, dest %s)", fieldName, src, fv.Type())
+		}
+		if m, ok := mapping[s]; ok {
+			s = m
+		}
+		fv.SetString(s)
+		return nil
+	}
+}
+
+// convertDestField returns the settable field fieldName in the struct
+// pointed to by dest.
+func convertDestField(dest any, fieldName string) (reflect.Value, error) {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("dest is not a pointer to a struct (%T)", dest)
+	}
+	if _, ok := dv.Elem().Type().FieldByName(fieldName); !ok {
+		return reflect.Value{}, fmt.Errorf("dest (%T) does not have field %q", dest, fieldName)
+	}
+	return dv.Elem().FieldByName(fieldName), nil
+}
+
+// TypeAdapter converts a field whose Go type differs between versions, e.g.
+// an int64 in GA that is a string in Alpha or a scalar that became a slice.
+// Create with NewTypeAdapter() and register with FieldTraits.Adapt().
+type TypeAdapter struct {
+	a, b reflect.Type
+	aToB func(reflect.Value) (reflect.Value, error)
+	bToA func(reflect.Value) (reflect.Value, error)
+}
+
+// NewTypeAdapter returns a TypeAdapter between the types A and B. aToB is
+// used when copying a field of type A to a field of type B and bToA for the
+// opposite direction.
+//
+//	adapter := NewTypeAdapter(
+//	  func(i int64) (string, error) { return strconv.FormatInt(i, 10), nil },
+//	  func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+func NewTypeAdapter[A any, B any](aToB func(A) (B, error), bToA func(B) (A, error)) TypeAdapter {
+	return TypeAdapter{
+		a: reflect.TypeOf((*A)(nil)).Elem(),
+		b: reflect.TypeOf((*B)(nil)).Elem(),
+		aToB: func(v reflect.Value) (reflect.Value, error) {
+			ret, err := aToB(v.Interface().(A))
+			return reflect.ValueOf(&ret).Elem(), err
+		},
+		bToA: func(v reflect.Value) (reflect.Value, error) {
+			ret, err := bToA(v.Interface().(B))
+			return reflect.ValueOf(&ret).Elem(), err
+		},
+	}
+}
+
+// convert src to a value of type destT.
+func (a *TypeAdapter) convert(destT reflect.Type, src reflect.Value) (reflect.Value, error) {
+	switch {
+	case src.Type() == a.a && destT == a.b:
+		return a.aToB(src)
+	case src.Type() == a.b && destT == a.a:
+		return a.bToA(src)
+	}
+	return reflect.Value{}, fmt.Errorf("TypeAdapter(%s, %s) cannot convert %s to %s", a.a, a.b, src.Type(), destT)
+}
@@ -20,11 +20,16 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 )
 
 type missingFieldOnCopy struct {
 	Path  Path
 	Value any
+	// Tolerated is true if the path was given to copierTolerate().
+	Tolerated bool
+	// Severity of the loss of the field. See copierInformational().
+	Severity Severity
 }
 
 // copierOption are options that customize the behavior of the internal copier.
@@ -35,8 +40,62 @@ func copierLogS(f func(msg string, kv ...any)) copierOption {
 	return func(c *copier) { c.logSFn = f }
 }
 
+// copierStrict causes the copier to return an error when a field cannot be
+// copied instead of recording it as missing.
+func copierStrict() copierOption {
+	return func(c *copier) { c.strict = true }
+}
+
+// copierConverters sets the custom field conversions for the copier. See
+// FieldTraits.Convert().
+func copierConverters(converters []converterTrait) copierOption {
+	return func(c *copier) { c.converters = converters }
+}
+
+// copierAdapters sets the TypeAdapters for fields with different types in
+// the versions. See FieldTraits.Adapt().
+func copierAdapters(adapters []adapterTrait) copierOption {
+	return func(c *copier) { c.adapters = adapters }
+}
+
+// copierSkipZero causes the copier to leave dest fields unchanged when the
+// src field is the zero value, i.e. the src is overlaid onto dest. Fields in
+// the NullFields or ForceSendFields of src are always copied. Non-zero
+// slices and maps replace the dest value.
+func copierSkipZero() copierOption {
+	return func(c *copier) { c.skipZero = true }
+}
+
+// copierTolerate records fields at or below paths that cannot be copied as
+// Tolerated missing fields. Tolerated fields do not cause an error in strict
+// mode.
+func copierTolerate(paths []Path) copierOption {
+	return func(c *copier) { c.tolerated = paths }
+}
+
+// copierInformational records fields at or below paths that cannot be copied
+// with SeverityInfo. Informational fields do not cause an error in strict
+// mode. See FieldTraits.Informational().
+func copierInformational(paths []Path) copierOption {
+	return func(c *copier) { c.informational = paths }
+}
+
+// copierKeyedSlices sets the keyed slices (see FieldTraits.KeyedSlice()).
+// The elements of a keyed slice are copied onto the dest element with the
+// same key, so fields of the dest elements that do not exist in src are kept
+// when the elements are reordered.
+func copierKeyedSlices(keyedSlices []keyedSlice) copierOption {
+	return func(c *copier) { c.keyedSlices = keyedSlices }
+}
+
+// copierMetafields sets the names of the metafields of the structs being
+// copied. The default is DefaultMetafieldNames().
+func copierMetafields(names MetafieldNames) copierOption {
+	return func(c *copier) { c.metafields = names }
+}
+
 func newCopier(opts ...copierOption) *copier {
-	c := &copier{}
+	c := &copier{metafields: DefaultMetafieldNames()}
 	for _, o := range opts {
 		o(c)
 	}
@@ -47,10 +106,96 @@ type copier struct {
 	// logSFn is an optional structured log function, matching the
 	// signature from klog/v2.
 	logSFn func(msg string, kv ...any)
+	// strict returns an error on missing fields.
+	strict bool
+	// converters are custom conversions for fields.
+	converters []converterTrait
+	// adapters convert fields with different types.
+	adapters []adapterTrait
+	// skipZero does not copy zero-valued fields.
+	skipZero bool
+	// tolerated are the paths of missing fields that are not errors.
+	tolerated []Path
+	// informational are the paths of missing fields with SeverityInfo.
+	informational []Path
+	// keyedSlices are the slices where elements are matched by key.
+	keyedSlices []keyedSlice
+	// metafields are the names of the metafields in the structs.
+	metafields MetafieldNames
 
 	missing []missingFieldOnCopy
 }
 
+// addMissing records a field that does not exist in dest. Returns an error
+// if the copier is strict.
+func (c *copier) addMissing(p Path, v any) error {
+	// p shares the underlying array with the paths of sibling slice
+	// elements and fields, which would overwrite the recorded path.
+	p = append(Path{}, p...)
+	severity := SeverityError
+	for _, ip := range c.informational {
+		if p.MatchPrefix(ip) {
+			severity = SeverityInfo
+			break
+		}
+	}
+	for _, tp := range c.tolerated {
+		if p.MatchPrefix(tp) {
+			c.missing = append(c.missing, missingFieldOnCopy{Path: p, Value: v, Tolerated: true, Severity: severity})
+			return nil
+		}
+	}
+	if c.strict && severity == SeverityError {
+		return &MissingFieldError{Path: p, Value: v}
+	}
+	c.missing = append(c.missing, missingFieldOnCopy{Path: p, Value: v, Severity: severity})
+	return nil
+}
+
+// converter returns the custom ConvertFunc for the field at p. Returns nil if
+// there is none.
+func (c *copier) converter(p Path) ConvertFunc {
+	for _, conv := range c.converters {
+		if p.Match(conv.path) {
+			return conv.f
+		}
+	}
+	return nil
+}
+
+// adapter returns the TypeAdapter for the field at p. Returns nil if there
+// is none.
+func (c *copier) adapter(p Path) *TypeAdapter {
+	for i := range c.adapters {
+		if p.Match(c.adapters[i].path) {
+			return &c.adapters[i].adapter
+		}
+	}
+	return nil
+}
+
+// keyFields returns the key fields of the slice at p. Returns nil if p is
+// not a keyed slice.
+func (c *copier) keyFields(p Path) []string {
+	for _, ks := range c.keyedSlices {
+		if p.Match(ks.path) {
+			return ks.keyFields
+		}
+	}
+	return nil
+}
+
+// hasMetafieldConverter returns true if the field fn named in the metafield
+// at p has a custom conversion, in which case the ConvertFunc is responsible
+// for the field.
+func (c *copier) hasMetafieldConverter(p Path, fn string) bool {
+	if len(c.converters) == 0 || len(p) == 0 {
+		return false
+	}
+	// Copy p as Field() would overwrite the underlying array.
+	return c.converter(append(Path{}, p[:len(p)-1]...).Field(fn)) != nil
+}
+
 func (c *copier) logS(msg string, kv ...any) {
 	if c.logSFn == nil {
 		return
@@ -122,8 +267,23 @@ func (c *copier) doSlice(p Path, dest, src reflect.Value) error {
 		return nil
 	}
 
+	var (
+		keyFields []string
+		destKeys  map[string]int
+	)
+	if len(c.keyedSlices) > 0 {
+		if keyFields = c.keyFields(p); keyFields != nil {
+			// Keyed elements are not reused if dest has duplicate
+			// keys.
+			destKeys, _ = sliceKeys(dest, keyFields)
+		}
+	}
+
 	newSlice := reflect.MakeSlice(dest.Type(), src.Len(), src.Len())
 	for i := 0; i < src.Len(); i++ {
+		if destKeys != nil {
+			c.initKeyedElem(newSlice.Index(i), src.Index(i), dest, destKeys, keyFields)
+		}
 		if err := c.doValues(p.Index(i), newSlice.Index(i), src.Index(i)); err != nil {
 			return err
 		}
@@ -138,54 +298,197 @@ func (c *copier) doSlice(p Path, dest, src reflect.Value) error {
 	return nil
 }
 
+// initKeyedElem sets elem to a copy of the element of dest with the same key
+// as the src element, if there is one.
+func (c *copier) initKeyedElem(elem, src, dest reflect.Value, destKeys map[string]int, keyFields []string) {
+	if src.Kind() == reflect.Pointer && src.IsNil() {
+		return
+	}
+	j, ok := destKeys[sliceKey(src, keyFields)]
+	if !ok {
+		return
+	}
+	dv := dest.Index(j)
+	if dv.Kind() == reflect.Pointer {
+		// Copy the struct so that the element is not shared with the
+		// previous dest slice.
+		nv := reflect.New(dv.Type().Elem())
+		nv.Elem().Set(dv.Elem())
+		dv = nv
+	}
+	elem.Set(dv)
+}
+
 func (c *copier) doStruct(p Path, dest, src reflect.Value) error {
 	if dest.Kind() != reflect.Struct || src.Kind() != reflect.Struct {
 		return fmt.Errorf("copyStruct: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
 	}
+	plan := copyPlanFor(dest.Type(), src.Type(), c.metafields)
+	var srcMeta *metafieldAccessor
+	if c.skipZero {
+		// src may not have metafields, in which case no zero fields
+		// are copied.
+		srcMeta, _ = newMetafieldAccessor(src, c.metafields)
+	}
 	// Copy over fields that are present in both src and dest. Fields in dest
 	// that don't exist in src are left alone.
-	for i := 0; i < src.Type().NumField(); i++ {
-		srcFieldT := src.Type().Field(i)
-		fieldName := srcFieldT.Name
-		destField := dest.FieldByName(fieldName)
-		_, ok := dest.Type().FieldByName(fieldName)
+	for _, fp := range plan.fields {
+		fieldName := fp.name
+		fieldPath := append(p, fp.elem)
+
+		if c.skipZero && !fp.metafield && src.Field(fp.srcIndex).IsZero() {
+			if srcMeta == nil || (!srcMeta.inNull(fieldName) && !srcMeta.inForceSend(fieldName)) {
+				c.logS("copyStruct skip zero", "path", p, "fieldName", fieldName)
+				continue
+			}
+		}
+
+		if len(c.converters) > 0 {
+			if f := c.converter(fieldPath); f != nil {
+				if !dest.CanAddr() {
+					return fmt.Errorf("copyStruct: cannot convert %s, dest is not addressable", fieldPath)
+				}
+				c.logS("copyStruct convert", "path", p, "fieldName", fieldName)
+				if err := f(dest.Addr().Interface(), src.Field(fp.srcIndex).Interface()); err != nil {
+					return fmt.Errorf("copyStruct: convert %s: %w", fieldPath, err)
+				}
+				continue
+			}
+		}
 
-		if !ok {
+		srcField := src.Field(fp.srcIndex)
+		if fp.destIndex == nil {
 			// Only non-zero fields are counted towards
 			// the missing fields. Fields explicitly named
 			// in NullFields or ForceSendFields are
 			// handled by copyMetaFields() below.
-			if !src.Field(i).IsZero() {
-				c.missing = append(c.missing, missingFieldOnCopy{
-					Path:  p.Field(fieldName),
-					Value: src.Field(i).Interface(),
-				})
+			if !srcField.IsZero() {
 				c.logS("copyStruct missing field", "path", p, "fieldName", fieldName)
+				if err := c.addMissing(fieldPath, srcField.Interface()); err != nil {
+					return err
+				}
 			}
 			continue
 		}
 
 		// ServerResponse should be skipped.
-		if (p.Equal(Path{}) || p.Equal(Path{}.Pointer())) && fieldName == "ServerResponse" {
+		if fp.serverResponse && (len(p) == 0 || p.Equal(Path{}.Pointer())) {
 			continue
 		}
 
-		if fieldName == "NullFields" || fieldName == "ForceSendFields" {
-			err := c.doMetaFields(p.Field(fieldName), destField, src.Field(i), dest, src)
+		destField := dest.FieldByIndex(fp.destIndex)
+		if fp.metafield {
+			err := c.doMetaFields(fieldPath, destField, srcField, dest, src)
 			if err != nil {
 				return err
 			}
 			continue
 		}
 
+		if len(c.adapters) > 0 && destField.Type() != srcField.Type() {
+			if a := c.adapter(fieldPath); a != nil {
+				if err := c.doAdapter(fieldPath, a, destField, srcField); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
 		c.logS("copyStruct", "path", p, "fieldName", fieldName)
-		if err := c.doValues(p.Field(fieldName), destField, src.Field(i)); err != nil {
+		if err := c.doValues(fieldPath, destField, srcField); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// doAdapter copies src to dest, which have different types, with the
+// TypeAdapter a. Zero values are not converted.
+func (c *copier) doAdapter(p Path, a *TypeAdapter, dest, src reflect.Value) error {
+	if !dest.CanSet() {
+		return fmt.Errorf("cannot set dest (%s)", p)
+	}
+	if src.IsZero() {
+		dest.Set(reflect.Zero(dest.Type()))
+		c.logS("copyAdapter zero", "path", p)
+		return nil
+	}
+	v, err := a.convert(dest.Type(), src)
+	if err != nil {
+		return fmt.Errorf("copyAdapter %s: %w", p, err)
+	}
+	dest.Set(v)
+	c.logS("copyAdapter", "path", p, "value", dest.Interface())
+	return nil
+}
+
+// copyPlans caches the *copyPlan for each (dest, src) struct type pair and
+// MetafieldNames.
+var copyPlans sync.Map
+
+type copyPlanKey struct {
+	dest, src reflect.Type
+	names     MetafieldNames
+}
+
+// copyPlan is the precomputed field mapping between a src and dest struct
+// type. This avoids repeating the FieldByName() lookups, which are
+// relatively expensive, for every copy.
+type copyPlan struct {
+	// fields of src in declaration order.
+	fields []copyPlanField
+	// srcFields and destFields are the names of the fields in the structs.
+	srcFields  map[string]bool
+	destFields map[string]bool
+}
+
+type copyPlanField struct {
+	name string
+	// elem is the Path element for the field.
+	elem     string
+	srcIndex int
+	// destIndex of the field in dest. This is nil if the field does not
+	// exist in dest.
+	destIndex []int
+	// metafield is true for NullFields and ForceSendFields.
+	metafield bool
+	// serverResponse is true for the ServerResponse field.
+	serverResponse bool
+}
+
+// copyPlanFor returns the (cached) copyPlan for copying src to dest. Both
+// types must be structs. names identify the metafields.
+func copyPlanFor(dest, src reflect.Type, names MetafieldNames) *copyPlan {
+	key := copyPlanKey{dest: dest, src: src, names: names}
+	if plan, ok := copyPlans.Load(key); ok {
+		return plan.(*copyPlan)
+	}
+	plan := &copyPlan{
+		srcFields:  map[string]bool{},
+		destFields: map[string]bool{},
+	}
+	for i := 0; i < dest.NumField(); i++ {
+		plan.destFields[dest.Field(i).Name] = true
+	}
+	for i := 0; i < src.NumField(); i++ {
+		name := src.Field(i).Name
+		plan.srcFields[name] = true
+		fp := copyPlanField{
+			name:           name,
+			elem:           string(pathField) + name,
+			srcIndex:       i,
+			metafield:      names.isMetafield(name),
+			serverResponse: name == names.ServerResponse,
+		}
+		if df, ok := dest.FieldByName(name); ok {
+			fp.destIndex = df.Index
+		}
+		plan.fields = append(plan.fields, fp)
+	}
+	actual, _ := copyPlans.LoadOrStore(key, plan)
+	return actual.(*copyPlan)
+}
+
 func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 	if dest.Type().Kind() != reflect.Map || src.Type().Kind() != reflect.Map {
 		return fmt.Errorf("copyMap: invalid type (dest: %T, src: %T)", dest.Interface(), src.Interface())
@@ -236,6 +539,12 @@ func (c *copier) doMap(p Path, dest, src reflect.Value) error {
 				return err
 			}
 			newMap.SetMapIndex(sk, dv)
+		case svt.Kind() == reflect.Pointer && svt.Elem().Kind() == reflect.Struct && dvt.Elem().Kind() == reflect.Struct:
+			dv := reflect.New(dvt).Elem()
+			if err := c.doValues(p.MapIndex(sk.Interface()), dv, sv); err != nil {
+				return err
+			}
+			newMap.SetMapIndex(sk, dv)
 		default:
 			return fmt.Errorf("unsupported map types (dest: %T, src: %T)", dest.Interface(), src.Interface())
 		}
@@ -283,26 +592,26 @@ func (c *copier) doMetaFields(p Path, destField, srcField, destStruct, srcStruct
 
 	c.logS("copyMetaFields dest", "path", p, "destFields", exists)
 
+	plan := copyPlanFor(destStruct.Type(), srcStruct.Type(), c.metafields)
 	for _, fn := range srcField.Interface().([]string) {
-		if _, ok := srcStruct.Type().FieldByName(fn); !ok {
+		if !plan.srcFields[fn] {
 			return fmt.Errorf("copyMetaFields: %s refers to field %q that doesn't exist (type %T)", p, fn, srcStruct.Interface())
 		}
-		_, destHasField := destStruct.Type().FieldByName(fn)
+		destHasField := plan.destFields[fn]
 		// We only need to add to destMetaFields if it exists
 		// in the dest struct and hasn't already been added to
 		// the list.
 		if destHasField && !exists[fn] {
 			destMetaFields = append(destMetaFields, fn)
 			c.logS("copyMetaFields add", "path", p, "fieldName", fn)
-		} else if !destHasField {
+		} else if !destHasField && !c.hasMetafieldConverter(p, fn) {
 			// Record that the metafield referenced a
 			// field that didn't exist on the dest
 			// version.
-			c.missing = append(c.missing, missingFieldOnCopy{
-				Path:  p.Field(fn),
-				Value: srcField.Interface(),
-			})
 			c.logS("copyMetaFields missing field", "path", p, "fieldName", fn)
+			if err := c.addMissing(p.Field(fn), srcField.Interface()); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -20,7 +20,10 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
 )
 
 func testCopier(t *testing.T) *copier {
@@ -99,13 +102,14 @@ func TestCopyPointer(t *testing.T) {
 		I  *int
 		S  *string
 		ST *stt
+		LS *[]stt
 	}
 
-	src := ptrTypes{I: new(int), S: new(string), ST: new(stt)}
+	src := ptrTypes{I: new(int), S: new(string), ST: new(stt), LS: &[]stt{{A: 1}, {A: 2}}}
 	*src.I = 13
 	*src.S = "hello"
 	src.ST.A = 42
-	dest := ptrTypes{I: new(int), S: new(string), ST: new(stt)}
+	dest := ptrTypes{I: new(int), S: new(string), ST: new(stt), LS: &[]stt{{A: 3}}}
 	var nilDest ptrTypes
 
 	for _, tc := range []struct {
@@ -150,6 +154,18 @@ func TestCopyPointer(t *testing.T) {
 			dest: v(&nilDest.ST).Elem(),
 			want: stt{A: 42},
 		},
+		{
+			name: "*[]struct",
+			src:  v(src.LS),
+			dest: v(dest.LS),
+			want: []stt{{A: 1}, {A: 2}},
+		},
+		{
+			name: "*[]struct nilDest",
+			src:  v(src.LS),
+			dest: v(&nilDest.LS).Elem(),
+			want: []stt{{A: 1}, {A: 2}},
+		},
 		{
 			name:    "invalid types",
 			src:     v(1),
@@ -281,6 +297,16 @@ func TestCopyStruct(t *testing.T) {
 		A S2
 		B *S1
 	}
+	type S5 struct {
+		E []S1
+		F []*S1
+		M map[string]S1
+	}
+	type S6 struct {
+		E []S2
+		F []*S2
+		M map[string]S2
+	}
 
 	for _, tc := range []struct {
 		name        string
@@ -332,6 +358,21 @@ func TestCopyStruct(t *testing.T) {
 			want:        S4{A: S2{A: 12}, B: &S1{D: []int{7}}},
 			wantMissing: []string{".A.B"},
 		},
+		{
+			name: "missing fields in slice and map elements",
+			src: v(S5{
+				E: []S1{{B: "a"}, {A: 1}, {B: "b"}},
+				F: []*S1{nil, {A: 2, B: "c"}},
+				M: map[string]S1{"k": {B: "d"}},
+			}),
+			dest: v(&S6{}).Elem(),
+			want: S6{
+				E: []S2{{}, {A: 1}, {}},
+				F: []*S2{nil, {A: 2}},
+				M: map[string]S2{"k": {}},
+			},
+			wantMissing: []string{".E!0.B", ".E!2.B", ".F!1*.B", ".M:k.B"},
+		},
 		{
 			name: "ServerResponse is not copied",
 			src:  v(S1{ServerResponse: "abc"}),
@@ -374,15 +415,20 @@ func TestCopyMap(t *testing.T) {
 	t.Parallel()
 
 	type st struct{ I int }
+	type st2 struct {
+		I int
+		J int
+	}
 
 	v := reflect.ValueOf
 	var nilmap map[string]int
 
 	for _, tc := range []struct {
-		name      string
-		dest, src reflect.Value
-		want      any
-		wantErr   bool
+		name        string
+		dest, src   reflect.Value
+		want        any
+		wantErr     bool
+		wantMissing []string
 	}{
 		{
 			name: "empty map",
@@ -408,6 +454,26 @@ func TestCopyMap(t *testing.T) {
 			src:  v(map[string]*st{}),
 			want: map[string]*st{},
 		},
+		{
+			name: "copy map[string]*struct",
+			dest: v(&map[string]*st{}).Elem(),
+			src:  v(map[string]*st{"a": {I: 1}, "b": nil}),
+			want: map[string]*st{"a": {I: 1}, "b": nil},
+		},
+		{
+			name:        "map[string]*struct missing field",
+			dest:        v(&map[string]*st{}).Elem(),
+			src:         v(map[string]*st2{"a": {I: 1, J: 2}}),
+			want:        map[string]*st{"a": {I: 1}},
+			wantMissing: []string{":a*.J"},
+		},
+		{
+			name:        "map[string]struct missing field",
+			dest:        v(&map[string]st{}).Elem(),
+			src:         v(map[string]st2{"a": {I: 1, J: 2}}),
+			want:        map[string]st{"a": {I: 1}},
+			wantMissing: []string{":a.J"},
+		},
 		{
 			name: "copy struct",
 			dest: v(&map[int]st{}).Elem(),
@@ -439,7 +505,7 @@ func TestCopyMap(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "pointer values currently not supported",
+			name:    "pointer to basic values not supported",
 			dest:    v(&map[int]*int{}).Elem(),
 			src:     v(map[int]*int{1: new(int)}),
 			wantErr: true,
@@ -458,6 +524,13 @@ func TestCopyMap(t *testing.T) {
 			if diff := cmp.Diff(tc.dest.Interface(), tc.want); diff != "" {
 				t.Fatalf("copyMap: -got,+want: %s (dest=%T)", diff, tc.dest.Interface())
 			}
+			var gotMissing []string
+			for _, m := range cc.missing {
+				gotMissing = append(gotMissing, m.Path.String())
+			}
+			if diff := cmp.Diff(gotMissing, tc.wantMissing); diff != "" {
+				t.Errorf("missing: -got,+want: %s", diff)
+			}
 		})
 	}
 }
@@ -554,3 +627,267 @@ func TestCopyMetaFields(t *testing.T) {
 		})
 	}
 }
+
+func TestCopySkipZero(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		S               string
+		I               int
+		L               []string
+		M               map[string]string
+		P               *inner
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name string
+		src  st
+		dest st
+		want st
+	}{
+		{
+			name: "zero fields are not copied",
+			src:  st{S: "new"},
+			dest: st{S: "old", I: 10, L: []string{"a"}, M: map[string]string{"k": "v"}},
+			want: st{S: "new", I: 10, L: []string{"a"}, M: map[string]string{"k": "v"}},
+		},
+		{
+			name: "nested struct is overlaid",
+			src:  st{P: &inner{B: 2}},
+			dest: st{P: &inner{A: 1, B: 1}},
+			want: st{P: &inner{A: 1, B: 2}},
+		},
+		{
+			name: "slices and maps are replaced",
+			src:  st{L: []string{"b"}, M: map[string]string{"k2": "v2"}},
+			dest: st{L: []string{"a", "c"}, M: map[string]string{"k": "v"}},
+			want: st{L: []string{"b"}, M: map[string]string{"k2": "v2"}},
+		},
+		{
+			name: "metafields are copied",
+			src:  st{ForceSendFields: []string{"I"}, NullFields: []string{"L"}},
+			dest: st{S: "old", I: 10, L: []string{"a"}},
+			want: st{S: "old", ForceSendFields: []string{"I"}, NullFields: []string{"L"}},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := newCopier(copierSkipZero())
+			if err := c.do(reflect.ValueOf(&tc.dest), reflect.ValueOf(&tc.src)); err != nil {
+				t.Fatalf("do() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.dest, tc.want); diff != "" {
+				t.Errorf("do(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestCopyKeyedSlice(t *testing.T) {
+	t.Parallel()
+
+	type srcElem struct {
+		K string
+		A int
+	}
+	type destElem struct {
+		K string
+		A int
+		// X does not exist in srcElem.
+		X string
+	}
+	type srcSt struct {
+		L  []srcElem
+		PL []*srcElem
+	}
+	type destSt struct {
+		L  []destElem
+		PL []*destElem
+	}
+
+	for _, tc := range []struct {
+		name string
+		src  srcSt
+		dest destSt
+		want destSt
+	}{
+		{
+			name: "reordered elements keep dest fields",
+			src: srcSt{
+				L:  []srcElem{{K: "b", A: 2}, {K: "a", A: 1}},
+				PL: []*srcElem{{K: "b", A: 2}, {K: "a", A: 1}},
+			},
+			dest: destSt{
+				L:  []destElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}},
+				PL: []*destElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}},
+			},
+			want: destSt{
+				L:  []destElem{{K: "b", A: 2, X: "xb"}, {K: "a", A: 1, X: "xa"}},
+				PL: []*destElem{{K: "b", A: 2, X: "xb"}, {K: "a", A: 1, X: "xa"}},
+			},
+		},
+		{
+			name: "new and removed elements",
+			src: srcSt{
+				L:  []srcElem{{K: "c", A: 3}, {K: "a", A: 1}},
+				PL: []*srcElem{nil, {K: "a", A: 1}},
+			},
+			dest: destSt{
+				L:  []destElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}},
+				PL: []*destElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}},
+			},
+			want: destSt{
+				L:  []destElem{{K: "c", A: 3}, {K: "a", A: 1, X: "xa"}},
+				PL: []*destElem{nil, {K: "a", A: 1, X: "xa"}},
+			},
+		},
+		{
+			name: "duplicate dest keys are not reused",
+			src: srcSt{
+				L: []srcElem{{K: "a", A: 1}},
+			},
+			dest: destSt{
+				L: []destElem{{K: "a", X: "x1"}, {K: "a", X: "x2"}},
+			},
+			want: destSt{
+				L: []destElem{{K: "a", A: 1}},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			oldPL := append([]*destElem{}, tc.dest.PL...)
+			var oldPLValues []destElem
+			for _, e := range oldPL {
+				oldPLValues = append(oldPLValues, *e)
+			}
+
+			c := newCopier(copierKeyedSlices([]keyedSlice{
+				{path: Path{}.Pointer().Field("L"), keyFields: []string{"K"}},
+				{path: Path{}.Pointer().Field("PL"), keyFields: []string{"K"}},
+			}))
+			if err := c.do(reflect.ValueOf(&tc.dest), reflect.ValueOf(&tc.src)); err != nil {
+				t.Fatalf("do() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(tc.dest, tc.want); diff != "" {
+				t.Errorf("do(); -got,+want: %s", diff)
+			}
+			// The elements of the previous dest slice are not modified.
+			for i, e := range oldPL {
+				if *e != oldPLValues[i] {
+					t.Errorf("old dest element %d = %+v, want %+v", i, *e, oldPLValues[i])
+				}
+			}
+		})
+	}
+
+	// Without the option, the elements are copied by position.
+	src := srcSt{L: []srcElem{{K: "b"}, {K: "a"}}}
+	dest := destSt{L: []destElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}}}
+	if err := newCopier().do(reflect.ValueOf(&dest), reflect.ValueOf(&src)); err != nil {
+		t.Fatalf("do() = %v, want nil", err)
+	}
+	if want := []destElem{{K: "b"}, {K: "a"}}; !reflect.DeepEqual(dest.L, want) {
+		t.Errorf("do() without keyed slices: L = %+v, want %+v", dest.L, want)
+	}
+}
+
+func BenchmarkCopier(b *testing.B) {
+	src := &ga.BackendService{
+		Name:                 "bs",
+		AffinityCookieTtlSec: 10,
+		Backends: []*ga.Backend{
+			{Group: "ig1", MaxUtilization: 0.5},
+			{Group: "ig2", BalancingMode: "RATE", MaxRatePerInstance: 100},
+		},
+		CdnPolicy: &ga.BackendServiceCdnPolicy{
+			CacheKeyPolicy:    &ga.CacheKeyPolicy{IncludeHost: true},
+			SignedUrlKeyNames: []string{},
+		},
+		HealthChecks:    []string{"hc1", "hc2"},
+		Id:              123456789,
+		ForceSendFields: []string{"Port"},
+		NullFields:      []string{"Iap"},
+	}
+	for _, tc := range []struct {
+		name string
+		dest func() any
+	}{
+		{name: "same type", dest: func() any { return &ga.BackendService{} }},
+		{name: "ga to alpha", dest: func() any { return &alpha.BackendService{} }},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := newCopier().do(reflect.ValueOf(tc.dest()), reflect.ValueOf(src)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		// The baseline without the copyPlans cache. The plans are
+		// recomputed for every copy.
+		b.Run(tc.name+"/uncached", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				resetCopyPlans()
+				if err := newCopier().do(reflect.ValueOf(tc.dest()), reflect.ValueOf(src)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	// Access() copies the written version to the other versions. Tracking
+	// the written fields adds a snapshot and a diff of the resource.
+	for _, tc := range []struct {
+		name   string
+		record func(res *mutableResource[ga.BackendService, alpha.BackendService, alpha.BackendService])
+	}{
+		{name: "access", record: func(*mutableResource[ga.BackendService, alpha.BackendService, alpha.BackendService]) {}},
+		{name: "access/set fields", record: func(res *mutableResource[ga.BackendService, alpha.BackendService, alpha.BackendService]) {
+			res.RecordSetFields()
+		}},
+		{name: "access/delta", record: func(res *mutableResource[ga.BackendService, alpha.BackendService, alpha.BackendService]) {
+			res.RecordDelta()
+		}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			res := newTestResource[ga.BackendService, alpha.BackendService, alpha.BackendService](
+				&allowZeroTrait[ga.BackendService, alpha.BackendService, alpha.BackendService]{})
+			tc.record(res)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := res.Access(func(x *ga.BackendService) { *x = *src }); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// allowZeroTrait allows zero values for all fields so that Access() does not
+// require the NullFields or ForceSendFields to be complete.
+type allowZeroTrait[G any, A any, B any] struct {
+	BaseTypeTrait[G, A, B]
+}
+
+func (allowZeroTrait[G, A, B]) FieldTraits(meta.Version) *FieldTraits {
+	ret := &FieldTraits{}
+	ret.AllowZeroValue(Path{}.Pointer())
+	return ret
+}
+
+// resetCopyPlans clears the copyPlans cache.
+func resetCopyPlans() {
+	copyPlans.Range(func(k, _ any) bool {
+		copyPlans.Delete(k)
+		return true
+	})
+}
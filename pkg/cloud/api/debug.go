@@ -0,0 +1,210 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// debugVersions are the versions of the resource in the order they are
+// printed.
+var debugVersions = []meta.Version{meta.VersionGA, meta.VersionAlpha, meta.VersionBeta}
+
+// debugInfo is the state of a resource for debugging. This is also the
+// format of DebugJSON().
+type debugInfo struct {
+	ResourceID string `json:"resourceID,omitempty"`
+	// Version is set only for a frozen Resource.
+	Version meta.Version `json:"version,omitempty"`
+	// Unavailable versions (NoVersion).
+	Unavailable []meta.Version `json:"unavailable,omitempty"`
+	// Fields are the non-zero basic values in each version, indexed by
+	// Path.String().
+	Fields        map[string]map[meta.Version]any `json:"fields"`
+	MissingFields []debugMissingField             `json:"missingFields,omitempty"`
+}
+
+type debugMissingField struct {
+	From      meta.Version `json:"from"`
+	To        meta.Version `json:"to"`
+	Path      string       `json:"path"`
+	Value     any          `json:"value"`
+	Tolerated bool         `json:"tolerated,omitempty"`
+}
+
+// debugInfo returns the debugInfo for u. frozen is the Version of the frozen
+// Resource or "" for a mutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) debugInfo(frozen meta.Version) (*debugInfo, error) {
+	ret := &debugInfo{
+		Version: frozen,
+		Fields:  map[string]map[meta.Version]any{},
+	}
+	if u.resourceID != nil {
+		ret.ResourceID = u.resourceID.String()
+	}
+	for _, ver := range debugVersions {
+		v, err := u.versionValue(ver)
+		if err != nil {
+			ret.Unavailable = append(ret.Unavailable, ver)
+			continue
+		}
+		err = visit(v, acceptorFromFunc(func(p Path, v reflect.Value) (bool, error) {
+			if u.metafieldNames(ver).isServerResponse(p) {
+				return false, nil
+			}
+			if !isBasicV(v) || v.IsZero() {
+				return true, nil
+			}
+			ps := p.String()
+			if ret.Fields[ps] == nil {
+				ret.Fields[ps] = map[meta.Version]any{}
+			}
+			ret.Fields[ps][ver] = v.Interface()
+			return true, nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for cc := range u.errors {
+		for _, mf := range u.errors[cc].missingFields {
+			ret.MissingFields = append(ret.MissingFields, debugMissingField{
+				From:      conversionVersions[cc][0],
+				To:        conversionVersions[cc][1],
+				Path:      mf.Path.String(),
+				Value:     mf.Value,
+				Tolerated: mf.Tolerated,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// String formats the debugInfo as a table with a column for each version.
+// Fields that have different values in the versions are marked with "!".
+func (d *debugInfo) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Resource %s", d.ResourceID)
+	if d.Version != "" {
+		fmt.Fprintf(&sb, " (version %s)", d.Version)
+	}
+	sb.WriteString("\n")
+
+	unavailable := map[meta.Version]bool{}
+	for _, ver := range d.Unavailable {
+		unavailable[ver] = true
+	}
+
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "\tPATH")
+	for _, ver := range debugVersions {
+		fmt.Fprintf(tw, "\t%s", strings.ToUpper(string(ver)))
+	}
+	fmt.Fprint(tw, "\n")
+
+	var paths []string
+	for p := range d.Fields {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		var cols []string
+		same := true
+		var first *string
+		for _, ver := range debugVersions {
+			var s string
+			switch x, ok := d.Fields[p][ver]; {
+			case unavailable[ver]:
+				cols = append(cols, "n/a")
+				continue
+			case !ok:
+				s = "-"
+			case reflect.TypeOf(x).Kind() == reflect.String:
+				s = fmt.Sprintf("%q", x)
+			default:
+				s = fmt.Sprintf("%v", x)
+			}
+			cols = append(cols, s)
+			if first == nil {
+				first = &s
+			} else if *first != s {
+				same = false
+			}
+		}
+		mark := ""
+		if !same {
+			mark = "!"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", mark, p, strings.Join(cols, "\t"))
+	}
+	tw.Flush()
+
+	if len(d.MissingFields) > 0 {
+		sb.WriteString("Missing fields:\n")
+		for _, mf := range d.MissingFields {
+			fmt.Fprintf(&sb, "  %s => %s: %s = %v", mf.From, mf.To, mf.Path, mf.Value)
+			if mf.Tolerated {
+				sb.WriteString(" (tolerated)")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// DebugString implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) DebugString() string {
+	d, err := u.debugInfo("")
+	if err != nil {
+		return fmt.Sprintf("DebugString: %v", err)
+	}
+	return d.String()
+}
+
+// DebugJSON implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) DebugJSON() ([]byte, error) {
+	d, err := u.debugInfo("")
+	if err != nil {
+		return nil, fmt.Errorf("DebugJSON: %w", err)
+	}
+	return json.Marshal(d)
+}
+
+// DebugString implements Resource.
+func (obj *resource[GA, Alpha, Beta]) DebugString() string {
+	d, err := obj.x.debugInfo(obj.ver)
+	if err != nil {
+		return fmt.Sprintf("DebugString: %v", err)
+	}
+	return d.String()
+}
+
+// DebugJSON implements Resource.
+func (obj *resource[GA, Alpha, Beta]) DebugJSON() ([]byte, error) {
+	d, err := obj.x.debugInfo(obj.ver)
+	if err != nil {
+		return nil, fmt.Errorf("DebugJSON: %w", err)
+	}
+	return json.Marshal(d)
+}
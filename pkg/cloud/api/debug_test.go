@@ -0,0 +1,112 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDebugString(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		S               string
+		A               int
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stGA struct {
+		I               int
+		S               string
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[stGA, st, NoVersion](&testTrait[stGA, st, NoVersion]{})
+	if err := res.AccessAlpha(func(x *st) {
+		x.I = 10
+		x.S = "abc"
+		x.A = 5
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	got := res.DebugString()
+	lines := strings.Split(got, "\n")
+	if len(lines) < 6 {
+		t.Fatalf("DebugString() = %q, too short", got)
+	}
+	if !strings.HasPrefix(lines[0], "Resource ") || strings.Contains(lines[0], "version") {
+		t.Errorf("lines[0] = %q, want ResourceID without a version", lines[0])
+	}
+	for _, want := range [][]string{
+		{"PATH", "GA", "ALPHA", "BETA"},
+		{"!", "*.A", "-", "5", "n/a"},
+		{"*.I", "10", "10", "n/a"},
+		{"*.Name", `"obj-1"`, `"obj-1"`, "n/a"},
+		{"*.S", `"abc"`, `"abc"`, "n/a"},
+		{"Missing", "fields:"},
+		{"alpha", "=>", "ga:", "*.A", "=", "5"},
+	} {
+		found := false
+		for _, l := range lines {
+			if cmp.Equal(strings.Fields(l), want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("DebugString() has no line %q", want)
+		}
+	}
+
+	b, err := res.DebugJSON()
+	if err != nil {
+		t.Fatalf("DebugJSON() = %v, want nil", err)
+	}
+	var d debugInfo
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatalf("json.Unmarshal() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(d.Fields["*.A"], map[meta.Version]any{meta.VersionAlpha: float64(5)}); diff != "" {
+		t.Errorf("DebugJSON() fields[*.A]: -got,+want: %s", diff)
+	}
+	if len(d.MissingFields) != 1 || d.MissingFields[0].Path != "*.A" || d.MissingFields[0].To != meta.VersionGA {
+		t.Errorf("DebugJSON() missingFields = %+v, want *.A alpha => ga", d.MissingFields)
+	}
+	if diff := cmp.Diff(d.Unavailable, []meta.Version{meta.VersionBeta}); diff != "" {
+		t.Errorf("DebugJSON() unavailable: -got,+want: %s", diff)
+	}
+
+	res.VersionPreference(meta.VersionAlpha)
+	frozen, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if got := frozen.DebugString(); !strings.Contains(got, "(version alpha)") {
+		t.Errorf("frozen.DebugString() = %q, want version alpha", got)
+	}
+}
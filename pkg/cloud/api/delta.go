@@ -0,0 +1,224 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// PatchDelta contains only the fields that were written by Access*() calls
+// since recording was enabled with MutableResource.RecordDelta(). The
+// versioned objects can be used directly as the body of a PATCH request:
+// written fields that are zero-valued are listed in ForceSendFields (or
+// NullFields for nil pointers, slices and maps).
+type PatchDelta[GA any, Alpha any, Beta any] struct {
+	// Paths of the fields that were written, in sorted order. Writes
+	// within a slice or a map are recorded as a write to the entire
+	// slice or map as PATCH replaces these values.
+	Paths []Path
+
+	GA    *GA
+	Alpha *Alpha
+	Beta  *Beta
+}
+
+// deltaRecorder tracks the paths written by Access*().
+type deltaRecorder struct {
+	paths []Path
+}
+
+// add p to the set of recorded paths. Paths that are covered by a recorded
+// prefix are not added and existing paths that are covered by p are removed.
+func (r *deltaRecorder) add(p Path) {
+	// Writes below a slice or map element are treated as a write to the
+	// entire slice or map.
+	for i, x := range p {
+		if x[0] == pathSliceIndex || x[0] == pathMapIndex {
+			p = p[:i]
+			break
+		}
+	}
+	var paths []Path
+	for _, rp := range r.paths {
+		if p.HasPrefix(rp) {
+			return
+		}
+		if !rp.HasPrefix(p) {
+			paths = append(paths, rp)
+		}
+	}
+	r.paths = append(paths, append(Path{}, p...))
+	sort.Slice(r.paths, func(i, j int) bool { return r.paths[i].String() < r.paths[j].String() })
+}
+
+// snapshotForWrites returns a deep copy of x so that the written fields can
+// be determined after the Access (see recordDelta() and recordSetFields()).
+func snapshotForWrites[T any](x *T) (*T, error) {
+	ret := new(T)
+	if err := newCopier().do(reflect.ValueOf(ret), reflect.ValueOf(x)); err != nil {
+		return nil, fmt.Errorf("snapshotForWrites: %w", err)
+	}
+	return ret, nil
+}
+
+// recordDelta records the paths that differ between before and after.
+//
+// Note: writes that do not change the value of a field cannot be detected
+// and are not recorded.
+func recordDelta[T any](r *deltaRecorder, before, after *T) error {
+	if r == nil {
+		return nil
+	}
+	result, err := diff(before, after, nil)
+	if err != nil {
+		return fmt.Errorf("recordDelta: %w", err)
+	}
+	for _, item := range result.Items {
+		r.add(item.Path)
+	}
+	return nil
+}
+
+// UpdateDelta computes the minimal update payload to change the resource on
+// the server from got to want. The fields that differ (see Resource.Diff())
+// are copied from want and fields that are being cleared or set to a zero
+// value are listed in NullFields or ForceSendFields so that they are sent in
+// the request.
+//
+// The payload should be sent using the want.Version() object. Fields that
+// cannot be represented in the other versions are omitted from those objects.
+func UpdateDelta[GA any, Alpha any, Beta any](want, got Resource[GA, Alpha, Beta]) (*PatchDelta[GA, Alpha, Beta], error) {
+	dr, err := want.Diff(got)
+	if err != nil {
+		return nil, fmt.Errorf("UpdateDelta: %w", err)
+	}
+	r := &deltaRecorder{}
+	for _, item := range dr.Items {
+		r.add(item.Path)
+	}
+	ret := &PatchDelta[GA, Alpha, Beta]{Paths: r.paths}
+
+	// The conversion errors are ignored; the caller should use the object
+	// for want.Version().
+	names := func(ver meta.Version) MetafieldNames {
+		if r, ok := want.(*resource[GA, Alpha, Beta]); ok {
+			return r.x.metafieldNames(ver)
+		}
+		return DefaultMetafieldNames()
+	}
+	gaObj, _ := want.ToGA()
+	if ret.GA, err = buildDelta(gaObj, ret.Paths, names(meta.VersionGA)); err != nil {
+		return nil, fmt.Errorf("UpdateDelta: %w", err)
+	}
+	alphaObj, _ := want.ToAlpha()
+	if ret.Alpha, err = buildDelta(alphaObj, ret.Paths, names(meta.VersionAlpha)); err != nil {
+		return nil, fmt.Errorf("UpdateDelta: %w", err)
+	}
+	betaObj, _ := want.ToBeta()
+	if ret.Beta, err = buildDelta(betaObj, ret.Paths, names(meta.VersionBeta)); err != nil {
+		return nil, fmt.Errorf("UpdateDelta: %w", err)
+	}
+	return ret, nil
+}
+
+// buildDelta returns a new T with only the fields in paths copied from src.
+// Paths that do not exist in T are skipped. names are the metafields of T.
+func buildDelta[T any](src *T, paths []Path, names MetafieldNames) (*T, error) {
+	ret := new(T)
+	for _, p := range paths {
+		if err := copyPath(p, reflect.ValueOf(ret), reflect.ValueOf(src), names); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// copyPath copies the field at p from src to dest, allocating intermediate
+// pointers in dest as needed. p may only contain field references and pointer
+// dereferences.
+func copyPath(p Path, dest, src reflect.Value, names MetafieldNames) error {
+	for i, x := range p {
+		switch x[0] {
+		case pathField:
+			fieldName := x[1:]
+			if _, ok := src.Type().FieldByName(fieldName); !ok {
+				return nil
+			}
+			if i < len(p)-1 {
+				dest = dest.FieldByName(fieldName)
+				src = src.FieldByName(fieldName)
+				continue
+			}
+			sfv := src.FieldByName(fieldName)
+			if err := newCopier(copierMetafields(names)).doValues(p, dest.FieldByName(fieldName), sfv); err != nil {
+				return fmt.Errorf("copyPath %s: %w", p, err)
+			}
+			addMetafield(dest, fieldName, sfv, names)
+			return nil
+		case pathPointer:
+			if src.IsNil() {
+				// A prefix of the path was also written and copied.
+				return nil
+			}
+			if dest.IsNil() {
+				dest.Set(reflect.New(dest.Type().Elem()))
+			}
+			dest = dest.Elem()
+			src = src.Elem()
+		default:
+			return fmt.Errorf("copyPath %s: unsupported path element %q", p, x)
+		}
+	}
+	return nil
+}
+
+// addMetafield adds fieldName to the ForceSendFields or NullFields of the
+// struct v if the value of the field is zero, otherwise the field would be
+// omitted from the request.
+func addMetafield(v reflect.Value, fieldName string, fv reflect.Value, names MetafieldNames) {
+	var nullValue bool
+	switch fv.Kind() {
+	case reflect.Pointer:
+		if !fv.IsNil() {
+			return
+		}
+		nullValue = true
+	case reflect.Slice, reflect.Map:
+		if fv.Len() > 0 {
+			return
+		}
+		nullValue = fv.IsNil()
+	default:
+		if !fv.IsZero() {
+			return
+		}
+	}
+	acc, err := newMetafieldAccessor(v, names)
+	if err != nil || acc.empty() {
+		// Type does not have metafields.
+		return
+	}
+	mf := acc.forceSendFields
+	if nullValue {
+		mf = acc.nullFields
+	}
+	mf.Set(reflect.Append(mf, reflect.ValueOf(fieldName)))
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceDelta(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		StP             *sti
+		LStr            []string
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+	if _, err := res.Delta(); err == nil {
+		t.Fatal("Delta() = _, nil; want error (not recording)")
+	}
+
+	// Writes before RecordDelta() are not recorded.
+	if err := res.Access(func(x *st) { x.I = 1 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	res.RecordDelta()
+	for i, f := range []func(x *st){
+		func(x *st) {
+			x.I = 0
+			x.S = "abc"
+			x.StP = &sti{}
+			x.LStr = []string{"a"}
+			x.M = map[string]string{"a": "b"}
+		},
+		// Writes within a recorded field and slice elements are covered by
+		// the parent path.
+		func(x *st) {
+			x.StP.I = 5
+			x.LStr[0] = "b"
+		},
+		func(x *st) { x.M = nil },
+	} {
+		if err := res.Access(f); err != nil {
+			t.Fatalf("Access() #%d = %v, want nil", i, err)
+		}
+	}
+
+	got, err := res.Delta()
+	if err != nil {
+		t.Fatalf("Delta() = %v, want nil", err)
+	}
+	wantPaths := []Path{
+		Path{}.Pointer().Field("I"),
+		Path{}.Pointer().Field("LStr"),
+		Path{}.Pointer().Field("M"),
+		Path{}.Pointer().Field("S"),
+		Path{}.Pointer().Field("StP"),
+	}
+	if diff := cmp.Diff(got.Paths, wantPaths); diff != "" {
+		t.Errorf("Delta().Paths: -got,+want: %s", diff)
+	}
+	want := &st{
+		S:               "abc",
+		StP:             &sti{I: 5},
+		LStr:            []string{"b"},
+		NullFields:      []string{"M"},
+		ForceSendFields: []string{"I"},
+	}
+	if diff := cmp.Diff(got.GA, want); diff != "" {
+		t.Errorf("Delta().GA: -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(got.Beta, want); diff != "" {
+		t.Errorf("Delta().Beta: -got,+want: %s", diff)
+	}
+}
+
+func TestUpdateDelta(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		StP             *sti
+		LStr            []string
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	freeze := func(f func(x *st)) Resource[st, st, st] {
+		t.Helper()
+		res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+		if err := res.Access(f); err != nil {
+			t.Fatalf("Access() = %v, want nil", err)
+		}
+		r, err := res.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v, want nil", err)
+		}
+		return r
+	}
+
+	got := freeze(func(x *st) {
+		x.I = 10
+		x.S = "abc"
+		x.StP = &sti{I: 1}
+		x.LStr = []string{"a", "b"}
+		x.M = map[string]string{"a": "b"}
+	})
+	want := freeze(func(x *st) {
+		x.S = "abc"
+		x.StP = &sti{I: 0, ForceSendFields: []string{"I"}}
+		x.LStr = []string{"a", "c"}
+	})
+
+	delta, err := UpdateDelta(want, got)
+	if err != nil {
+		t.Fatalf("UpdateDelta() = %v, want nil", err)
+	}
+	wantPaths := []Path{
+		Path{}.Pointer().Field("I"),
+		Path{}.Pointer().Field("LStr"),
+		Path{}.Pointer().Field("M"),
+		Path{}.Pointer().Field("StP").Pointer().Field("I"),
+	}
+	if diff := cmp.Diff(delta.Paths, wantPaths); diff != "" {
+		t.Errorf("UpdateDelta().Paths: -got,+want: %s", diff)
+	}
+	wantObj := &st{
+		StP:             &sti{ForceSendFields: []string{"I"}},
+		LStr:            []string{"a", "c"},
+		NullFields:      []string{"M"},
+		ForceSendFields: []string{"I"},
+	}
+	if diff := cmp.Diff(delta.GA, wantObj); diff != "" {
+		t.Errorf("UpdateDelta().GA: -got,+want: %s", diff)
+	}
+
+	// No diff results in an empty payload.
+	delta, err = UpdateDelta(want, want)
+	if err != nil {
+		t.Fatalf("UpdateDelta() = %v, want nil", err)
+	}
+	if len(delta.Paths) != 0 || cmp.Diff(delta.GA, &st{}) != "" {
+		t.Errorf("UpdateDelta() = %+v, want empty", delta)
+	}
+}
@@ -17,42 +17,107 @@ limitations under the License.
 package api
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 )
 
 // TODO: how to diff force send fields? null fields? and zero values?
 
-// diff returns a diff between A and B.
+// diffOptions customize diff().
+type diffOptions struct {
+	// firstOnly stops the diff after the first difference is found.
+	firstOnly bool
+	// ignore are paths that are not compared.
+	ignore []Path
+}
+
+// errDiffFound stops the differ when diffOptions.firstOnly is set.
+var errDiffFound = errors.New("diff found")
+
+// diff returns a diff between A and B. At most one diffOptions may be given.
 //
 // TODO: the behavior of this is not symmetric -- diff(A,B) != diff(B,A).
-func diff[T any](a, b *T, trait *FieldTraits) (*DiffResult, error) {
+func diff[T any](a, b *T, trait *FieldTraits, opts ...diffOptions) (*DiffResult, error) {
 	if trait == nil {
 		trait = &FieldTraits{}
 	}
 	d := &differ[T]{
 		traits: trait,
-		result: &DiffResult{},
+		result: &DiffResult{a: reflect.ValueOf(a)},
+	}
+	if len(opts) > 0 {
+		d.opts = opts[0]
 	}
 	err := d.do(Path{}, reflect.ValueOf(a), reflect.ValueOf(b))
-	if err != nil {
+	if err != nil && !errors.Is(err, errDiffFound) {
 		return nil, err
 	}
+	for i := range d.result.Items {
+		d.result.Items[i].Immutable = trait.isImmutable(d.result.Items[i].Path)
+	}
 	return d.result, nil
 }
 
 // DiffResult gives a list of elements that differ.
 type DiffResult struct {
 	Items []DiffItem
+
+	// a is the object A that was compared. This is used to render the
+	// result as a patch (see JSONPatch()).
+	a reflect.Value
 }
 
 // HasDiff is true if the result is has a diff.
 func (r *DiffResult) HasDiff() bool { return len(r.Items) > 0 }
 
+// NeedsRecreate is true if the resource must be recreated to resolve the
+// diff, i.e. one of the differences is in a field declared with
+// FieldTraits.Immutable().
+func (r *DiffResult) NeedsRecreate() bool {
+	for _, item := range r.Items {
+		if item.Immutable {
+			return true
+		}
+	}
+	return false
+}
+
+// ImmutableItems returns the items that are in Immutable() fields.
+func (r *DiffResult) ImmutableItems() []DiffItem {
+	var ret []DiffItem
+	for _, item := range r.Items {
+		if item.Immutable {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
+
+// Ignore returns a copy of the DiffResult without the items at or below any
+// of the given paths.
+func (r *DiffResult) Ignore(paths []Path) *DiffResult {
+	ret := &DiffResult{a: r.a}
+	for _, item := range r.Items {
+		ignored := false
+		for _, p := range paths {
+			if item.Path.HasPrefix(p) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			ret.Items = append(ret.Items, item)
+		}
+	}
+	return ret
+}
+
 func (r *DiffResult) add(state DiffItemState, p Path, a, b reflect.Value) {
 	di := DiffItem{
 		State: state,
-		Path:  p,
+		// Copy p as the differ reuses the underlying array.
+		Path: append(Path{}, p...),
 	}
 	if a.IsValid() {
 		di.A = a.Interface()
@@ -83,14 +148,26 @@ type DiffItem struct {
 	Path  Path
 	A     any
 	B     any
+	// Immutable is true if the field cannot be updated in place. See
+	// FieldTraits.Immutable().
+	Immutable bool
 }
 
 type differ[T any] struct {
 	traits *FieldTraits
 	result *DiffResult
+	opts   diffOptions
 }
 
 func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
+	if d.opts.firstOnly && d.result.HasDiff() {
+		return errDiffFound
+	}
+	for _, ip := range d.opts.ignore {
+		if p.MatchPrefix(ip) {
+			return nil
+		}
+	}
 	// cmpZero applies to pointer, slice and map values. Returns true if no
 	// further diff'ing is required for the values.
 	cmpZero := func() bool {
@@ -107,6 +184,23 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 		return false
 	}
 
+	av = d.traits.withServerDefault(p, av)
+	bv = d.traits.withServerDefault(p, bv)
+
+	if d.traits.isOpaque(p) && av.IsValid() && bv.IsValid() {
+		if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			d.result.add(DiffItemDifferent, p, av, bv)
+		}
+		return nil
+	}
+
+	if f := d.traits.comparator(p); f != nil && av.IsValid() && bv.IsValid() {
+		if !f(av.Interface(), bv.Interface()) {
+			d.result.add(DiffItemDifferent, p, av, bv)
+		}
+		return nil
+	}
+
 	switch {
 	case isBasicV(av):
 		if !av.Equal(bv) {
@@ -127,7 +221,7 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 			bfv := bv.Field(i)
 			fp := p.Field(aft.Name)
 
-			if aft.Name == "NullFields" || aft.Name == "ForceSendFields" {
+			if d.traits.metafieldNames().isMetafield(aft.Name) {
 				continue
 			}
 
@@ -146,6 +240,14 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 		if cmpZero() {
 			return nil
 		}
+		if keyFields := d.traits.keyFields(p); keyFields != nil {
+			if done, err := d.doKeyedSlice(p, av, bv, keyFields); done || err != nil {
+				return err
+			}
+		}
+		if d.traits.isUnordered(p) {
+			return d.doUnorderedSlice(p, av, bv)
+		}
 		// If we find the list lengths are difference, don't recurse into a list
 		// to compare item by item. There isn't a use case for a more fine grain
 		// diff within a slice at the moment.
@@ -192,3 +294,101 @@ func (d *differ[T]) do(p Path, av, bv reflect.Value) error {
 
 	return fmt.Errorf("differ: invalid type: %s", av.Type())
 }
+
+// doKeyedSlice diffs the slices av and bv, matching elements by the values of
+// the keyFields. Returns false if the elements cannot be keyed (e.g. there are
+// duplicate keys), in which case the slice should be diff'd by position.
+func (d *differ[T]) doKeyedSlice(p Path, av, bv reflect.Value, keyFields []string) (bool, error) {
+	aKeys, ok := sliceKeys(av, keyFields)
+	if !ok {
+		return false, nil
+	}
+	bKeys, ok := sliceKeys(bv, keyFields)
+	if !ok {
+		return false, nil
+	}
+	for i := 0; i < av.Len(); i++ {
+		j, ok := bKeys[sliceKey(av.Index(i), keyFields)]
+		if !ok {
+			d.result.add(DiffItemOnlyInA, p.Index(i), av.Index(i), reflect.Value{})
+			continue
+		}
+		if err := d.do(p.Index(i), av.Index(i), bv.Index(j)); err != nil {
+			return true, fmt.Errorf("differ keyed slice %s: %w", p, err)
+		}
+	}
+	for j := 0; j < bv.Len(); j++ {
+		if _, ok := aKeys[sliceKey(bv.Index(j), keyFields)]; !ok {
+			d.result.add(DiffItemOnlyInB, p.Index(j), reflect.Value{}, bv.Index(j))
+		}
+	}
+	return true, nil
+}
+
+// doUnorderedSlice diffs the slices av and bv ignoring the order of the
+// elements. Elements are matched with an equal element in the other slice.
+// If the slices do not contain the same elements, the entire slice is
+// reported as different.
+func (d *differ[T]) doUnorderedSlice(p Path, av, bv reflect.Value) error {
+	if av.Len() != bv.Len() {
+		d.result.add(DiffItemDifferent, p, av, bv)
+		return nil
+	}
+	matched := make([]bool, bv.Len())
+	for i := 0; i < av.Len(); i++ {
+		found := false
+		for j := 0; j < bv.Len() && !found; j++ {
+			if matched[j] {
+				continue
+			}
+			sub := &differ[T]{traits: d.traits, result: &DiffResult{}, opts: d.opts}
+			if err := sub.do(p.Index(i), av.Index(i), bv.Index(j)); err != nil && !errors.Is(err, errDiffFound) {
+				return fmt.Errorf("differ unordered slice %s: %w", p, err)
+			}
+			if !sub.result.HasDiff() {
+				matched[j] = true
+				found = true
+			}
+		}
+		if !found {
+			d.result.add(DiffItemDifferent, p, av, bv)
+			return nil
+		}
+	}
+	return nil
+}
+
+// sliceKeys returns a map of key => index for the elements of v. Returns false
+// if an element is nil or the keys are not unique.
+func sliceKeys(v reflect.Value, keyFields []string) (map[string]int, bool) {
+	ret := map[string]int{}
+	for i := 0; i < v.Len(); i++ {
+		ev := v.Index(i)
+		if ev.Kind() == reflect.Pointer && ev.IsNil() {
+			return nil, false
+		}
+		k := sliceKey(ev, keyFields)
+		if _, ok := ret[k]; ok {
+			return nil, false
+		}
+		ret[k] = i
+	}
+	return ret, true
+}
+
+// sliceKey of the element ev. The key fields are printed as the key values
+// may be slices (e.g. UrlMap.HostRules are keyed by Hosts).
+func sliceKey(ev reflect.Value, keyFields []string) string {
+	if ev.Kind() == reflect.Pointer {
+		ev = ev.Elem()
+	}
+	var key []any
+	for _, kf := range keyFields {
+		fv := ev.FieldByName(kf)
+		if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		key = append(key, fv.Interface())
+	}
+	return fmt.Sprintf("%#v", key)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// jsonPatchOp is an operation in a JSON Patch (RFC 6902) document.
+type jsonPatchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// patchItem is a DiffItem normalized for rendering as a patch.
+type patchItem struct {
+	// tokens of the JSON Pointer (RFC 6901) for the field.
+	tokens []string
+	// remove is true if the field is removed, otherwise it is set to
+	// value.
+	remove bool
+	// replace is true if the field exists in B.
+	replace bool
+	value   any
+}
+
+// JSONPatch returns the differences as a JSON Patch (RFC 6902) document that
+// transforms B into A, i.e. for want.Diff(got), the patch to apply to got.
+// Fields are named by their JSON names in the API structs. Differences within
+// a slice are rendered as a replace of the entire slice as the element
+// indices of A and B may not correspond.
+func (r *DiffResult) JSONPatch() ([]byte, error) {
+	items, err := r.patchItems()
+	if err != nil {
+		return nil, fmt.Errorf("JSONPatch: %w", err)
+	}
+	ops := []jsonPatchOp{}
+	for _, item := range items {
+		op := jsonPatchOp{Path: jsonPointer(item.tokens)}
+		switch {
+		case item.remove:
+			op.Op = "remove"
+		default:
+			op.Op = "add"
+			if item.replace {
+				op.Op = "replace"
+			}
+			if op.Value, err = json.Marshal(item.value); err != nil {
+				return nil, fmt.Errorf("JSONPatch: %s: %w", op.Path, err)
+			}
+		}
+		ops = append(ops, op)
+	}
+	return json.Marshal(ops)
+}
+
+// JSONMergePatch returns the differences as a JSON Merge Patch (RFC 7396)
+// document that transforms B into A. See JSONPatch().
+func (r *DiffResult) JSONMergePatch() ([]byte, error) {
+	items, err := r.patchItems()
+	if err != nil {
+		return nil, fmt.Errorf("JSONMergePatch: %w", err)
+	}
+	doc := map[string]any{}
+	for _, item := range items {
+		obj := doc
+		for _, tok := range item.tokens[:len(item.tokens)-1] {
+			next, ok := obj[tok].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				obj[tok] = next
+			}
+			obj = next
+		}
+		var value any
+		if !item.remove {
+			value = item.value
+		}
+		obj[item.tokens[len(item.tokens)-1]] = value
+	}
+	return json.Marshal(doc)
+}
+
+// patchItems normalizes the Items for a patch: items within a slice are
+// replaced by the value of the entire slice in A. The items are sorted by
+// their JSON Pointer.
+func (r *DiffResult) patchItems() ([]patchItem, error) {
+	var ret []patchItem
+	done := map[string]bool{}
+	for _, item := range r.Items {
+		p := item.Path
+		pi := patchItem{
+			remove:  item.State == DiffItemOnlyInB,
+			replace: item.State == DiffItemDifferent && item.B != nil,
+			value:   item.A,
+		}
+		for i, x := range p {
+			if x[0] != pathSliceIndex {
+				continue
+			}
+			// The slice is non-empty in both A and B, otherwise the
+			// diff would not contain the elements.
+			p = p[:i]
+			if !r.a.IsValid() {
+				return nil, fmt.Errorf("value for %s is not available", p)
+			}
+			v, err := getPath(r.a, p)
+			if err != nil {
+				return nil, err
+			}
+			pi = patchItem{replace: true, value: v}
+			break
+		}
+		if len(p) == 0 {
+			continue
+		}
+		if done[p.String()] {
+			continue
+		}
+		done[p.String()] = true
+		tokens, err := jsonTokens(r.a.Type(), p)
+		if err != nil {
+			return nil, err
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		pi.tokens = tokens
+		ret = append(ret, pi)
+	}
+	sort.Slice(ret, func(i, j int) bool { return jsonPointer(ret[i].tokens) < jsonPointer(ret[j].tokens) })
+	return ret, nil
+}
+
+// jsonTokens returns the JSON Pointer tokens for the field at p in type t.
+func jsonTokens(t reflect.Type, p Path) ([]string, error) {
+	var ret []string
+	for _, x := range p {
+		switch x[0] {
+		case pathPointer:
+			if t.Kind() != reflect.Pointer {
+				return nil, fmt.Errorf("%s: %s is not a pointer", p, t)
+			}
+			t = t.Elem()
+		case pathField:
+			if t.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("%s: %s is not a struct", p, t)
+			}
+			f, ok := t.FieldByName(x[1:])
+			if !ok {
+				return nil, fmt.Errorf("%s: %s does not have field %q", p, t, x[1:])
+			}
+			ret = append(ret, jsonName(f))
+			t = f.Type
+		case pathSliceIndex, pathMapIndex:
+			if t.Kind() != reflect.Slice && t.Kind() != reflect.Map {
+				return nil, fmt.Errorf("%s: %s is not a slice or map", p, t)
+			}
+			ret = append(ret, x[1:])
+			t = t.Elem()
+		default:
+			return nil, fmt.Errorf("%s: invalid path element %q", p, x)
+		}
+	}
+	return ret, nil
+}
+
+// jsonName returns the name of the field in the JSON encoding.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+// jsonPointer returns the JSON Pointer (RFC 6901) for the tokens.
+func jsonPointer(tokens []string) string {
+	var sb strings.Builder
+	for _, tok := range tokens {
+		sb.WriteString("/")
+		tok = strings.ReplaceAll(tok, "~", "~0")
+		sb.WriteString(strings.ReplaceAll(tok, "/", "~1"))
+	}
+	return sb.String()
+}
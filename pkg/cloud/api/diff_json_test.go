@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffResultJSONPatch(t *testing.T) {
+	type sti struct {
+		I int64 `json:"i,omitempty"`
+	}
+	type st struct {
+		Name string            `json:"name,omitempty"`
+		I    int64             `json:"i,omitempty"`
+		PSt  *sti              `json:"pst,omitempty"`
+		LS   []string          `json:"ls,omitempty"`
+		M    map[string]string `json:"m,omitempty"`
+		S    string
+	}
+
+	for _, tc := range []struct {
+		name      string
+		a         st
+		b         st
+		wantPatch string
+		wantMerge string
+	}{
+		{
+			name:      "equal",
+			a:         st{I: 3},
+			b:         st{I: 3},
+			wantPatch: `[]`,
+			wantMerge: `{}`,
+		},
+		{
+			name:      "different",
+			a:         st{I: 5, Name: "a"},
+			b:         st{I: 10, Name: "a"},
+			wantPatch: `[{"op":"replace","path":"/i","value":5}]`,
+			wantMerge: `{"i":5}`,
+		},
+		{
+			name:      "only in a",
+			a:         st{PSt: &sti{I: 1}},
+			b:         st{},
+			wantPatch: `[{"op":"add","path":"/pst","value":{"i":1}}]`,
+			wantMerge: `{"pst":{"i":1}}`,
+		},
+		{
+			name:      "only in b",
+			a:         st{},
+			b:         st{PSt: &sti{I: 1}},
+			wantPatch: `[{"op":"remove","path":"/pst"}]`,
+			wantMerge: `{"pst":null}`,
+		},
+		{
+			name:      "no json tag",
+			a:         st{S: "x"},
+			b:         st{S: "y"},
+			wantPatch: `[{"op":"replace","path":"/S","value":"x"}]`,
+			wantMerge: `{"S":"x"}`,
+		},
+		{
+			name:      "nested field",
+			a:         st{PSt: &sti{I: 1}},
+			b:         st{PSt: &sti{I: 2}},
+			wantPatch: `[{"op":"replace","path":"/pst/i","value":1}]`,
+			wantMerge: `{"pst":{"i":1}}`,
+		},
+		{
+			name:      "slice element",
+			a:         st{LS: []string{"a", "b"}},
+			b:         st{LS: []string{"a", "c"}},
+			wantPatch: `[{"op":"replace","path":"/ls","value":["a","b"]}]`,
+			wantMerge: `{"ls":["a","b"]}`,
+		},
+		{
+			name:      "map entry",
+			a:         st{M: map[string]string{"a/b": "1"}},
+			b:         st{M: map[string]string{"a/b": "2"}},
+			wantPatch: `[{"op":"replace","path":"/m/a~1b","value":"1"}]`,
+			wantMerge: `{"m":{"a/b":"1"}}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, nil)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			patch, err := r.JSONPatch()
+			if err != nil {
+				t.Fatalf("JSONPatch() = %v, want nil", err)
+			}
+			if d := cmp.Diff(string(patch), tc.wantPatch); d != "" {
+				t.Errorf("JSONPatch() diff -got,+want: %s", d)
+			}
+			merge, err := r.JSONMergePatch()
+			if err != nil {
+				t.Fatalf("JSONMergePatch() = %v, want nil", err)
+			}
+			if d := cmp.Diff(string(merge), tc.wantMerge); d != "" {
+				t.Errorf("JSONMergePatch() diff -got,+want: %s", d)
+			}
+		})
+	}
+}
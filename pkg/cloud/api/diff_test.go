@@ -17,8 +17,10 @@ limitations under the License.
 package api
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/kr/pretty"
 )
 
@@ -190,3 +192,451 @@ func TestDiff(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffResultIgnore(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I int
+		S string
+	}
+	type st struct {
+		I  int
+		S  string
+		St sti
+	}
+	a := &st{I: 1, S: "a", St: sti{I: 1, S: "a"}}
+	b := &st{I: 2, S: "b", St: sti{I: 2, S: "b"}}
+	r, err := diff(a, b, nil)
+	if err != nil {
+		t.Fatalf("diff() = %v, want nil", err)
+	}
+
+	got := r.Ignore([]Path{
+		Path{}.Pointer().Field("S"),
+		Path{}.Pointer().Field("St"),
+	})
+	if len(got.Items) != 1 || !got.Items[0].Path.Equal(Path{}.Pointer().Field("I")) {
+		t.Errorf("Ignore() = %s, want only .I", pretty.Sprint(got))
+	}
+	if len(r.Items) != 4 {
+		t.Errorf("len(r.Items) = %d, want 4 (Ignore() must not modify the receiver)", len(r.Items))
+	}
+}
+
+func TestDiffKeyedSlice(t *testing.T) {
+	t.Parallel()
+
+	type backend struct {
+		Group string
+		Hosts []string
+		Max   int
+	}
+	type st struct {
+		Backends  []backend
+		PBackends []*backend
+		Rules     []backend
+	}
+
+	traits := &FieldTraits{}
+	traits.KeyedSlice(Path{}.Pointer().Field("Backends"), "Group")
+	traits.KeyedSlice(Path{}.Pointer().Field("PBackends"), "Group")
+	traits.KeyedSlice(Path{}.Pointer().Field("Rules"), "Hosts")
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	type item struct {
+		State DiffItemState
+		Path  string
+	}
+	for _, tc := range []struct {
+		name string
+		a, b st
+		want []item
+	}{
+		{
+			name: "reordered",
+			a:    st{Backends: []backend{{Group: "a"}, {Group: "b"}}},
+			b:    st{Backends: []backend{{Group: "b"}, {Group: "a"}}},
+		},
+		{
+			name: "reordered pointers",
+			a:    st{PBackends: []*backend{{Group: "a"}, {Group: "b"}}},
+			b:    st{PBackends: []*backend{{Group: "b"}, {Group: "a"}}},
+		},
+		{
+			name: "reordered slice key",
+			a:    st{Rules: []backend{{Hosts: []string{"x", "y"}}, {Hosts: []string{"z"}}}},
+			b:    st{Rules: []backend{{Hosts: []string{"z"}}, {Hosts: []string{"x", "y"}}}},
+		},
+		{
+			name: "field diff in reordered element",
+			a:    st{Backends: []backend{{Group: "a", Max: 1}, {Group: "b", Max: 2}}},
+			b:    st{Backends: []backend{{Group: "b", Max: 3}, {Group: "a", Max: 1}}},
+			want: []item{{DiffItemDifferent, "*.Backends!1.Max"}},
+		},
+		{
+			name: "added and removed elements",
+			a:    st{Backends: []backend{{Group: "a"}, {Group: "b"}}},
+			b:    st{Backends: []backend{{Group: "c"}, {Group: "a"}, {Group: "d"}}},
+			want: []item{
+				{DiffItemOnlyInA, "*.Backends!1"},
+				{DiffItemOnlyInB, "*.Backends!0"},
+				{DiffItemOnlyInB, "*.Backends!2"},
+			},
+		},
+		{
+			name: "duplicate keys are compared by position",
+			a:    st{Backends: []backend{{Group: "a", Max: 1}, {Group: "a", Max: 2}}},
+			b:    st{Backends: []backend{{Group: "a", Max: 2}, {Group: "a", Max: 1}}},
+			want: []item{
+				{DiffItemDifferent, "*.Backends!0.Max"},
+				{DiffItemDifferent, "*.Backends!1.Max"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			var got []item
+			for _, di := range r.Items {
+				got = append(got, item{di.State, di.Path.String()})
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("diff(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffUnorderedSlice(t *testing.T) {
+	t.Parallel()
+
+	type backend struct {
+		Group string
+		Max   int
+	}
+	type st struct {
+		URLs     []string
+		Backends []backend
+		Ordered  []string
+		Name     string
+	}
+
+	traits := &FieldTraits{}
+	traits.UnorderedSlice(Path{}.Pointer().Field("URLs"))
+	traits.UnorderedSlice(Path{}.Pointer().Field("Backends"))
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	type item struct {
+		State DiffItemState
+		Path  string
+	}
+	for _, tc := range []struct {
+		name string
+		a, b st
+		want []item
+	}{
+		{
+			name: "reordered",
+			a:    st{URLs: []string{"a", "b", "c"}},
+			b:    st{URLs: []string{"c", "a", "b"}},
+		},
+		{
+			name: "reordered structs",
+			a:    st{Backends: []backend{{Group: "a", Max: 1}, {Group: "b"}}},
+			b:    st{Backends: []backend{{Group: "b"}, {Group: "a", Max: 1}}},
+		},
+		{
+			name: "duplicates",
+			a:    st{URLs: []string{"a", "a", "b"}},
+			b:    st{URLs: []string{"a", "b", "b"}},
+			want: []item{{DiffItemDifferent, "*.URLs"}},
+		},
+		{
+			name: "different element",
+			a:    st{Backends: []backend{{Group: "a", Max: 1}, {Group: "b"}}},
+			b:    st{Backends: []backend{{Group: "b"}, {Group: "a", Max: 2}}},
+			want: []item{{DiffItemDifferent, "*.Backends"}},
+		},
+		{
+			name: "different length",
+			a:    st{URLs: []string{"a", "b"}},
+			b:    st{URLs: []string{"b"}},
+			want: []item{{DiffItemDifferent, "*.URLs"}},
+		},
+		{
+			name: "ordered slice",
+			a:    st{Ordered: []string{"a", "b"}},
+			b:    st{Ordered: []string{"b", "a"}},
+			want: []item{
+				{DiffItemDifferent, "*.Ordered!0"},
+				{DiffItemDifferent, "*.Ordered!1"},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			var got []item
+			for _, di := range r.Items {
+				got = append(got, item{di.State, di.Path.String()})
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("diff(): -got,+want: %s", diff)
+			}
+		})
+	}
+
+	bad := &FieldTraits{}
+	bad.UnorderedSlice(Path{}.Pointer().Field("Name"))
+	if err := bad.CheckSchema(reflect.TypeOf(&st{})); err == nil {
+		t.Errorf("CheckSchema() = nil, want error for UnorderedSlice() on a string")
+	}
+}
+
+func TestDiffOpaque(t *testing.T) {
+	t.Parallel()
+
+	traits := &FieldTraits{}
+	traits.Opaque(Path{}.Pointer().Field("Expr").Pointer().Field("Args"))
+	if err := traits.CheckSchema(reflect.TypeOf(&recSt{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	a := &recSt{Expr: &recExpr{Op: "and", Args: []*recExpr{{Op: "x"}, {Op: "y"}}}}
+	b := &recSt{Expr: &recExpr{Op: "and", Args: []*recExpr{{Op: "x"}, {Op: "z"}}}}
+
+	r, err := diff(a, a, traits)
+	if err != nil || r.HasDiff() {
+		t.Fatalf("diff(a, a) = %v, %v; want no diff", pretty.Sprint(r), err)
+	}
+	r, err = diff(a, b, traits)
+	if err != nil {
+		t.Fatalf("diff(a, b) = %v, want nil", err)
+	}
+	want := Path{}.Pointer().Field("Expr").Pointer().Field("Args")
+	if len(r.Items) != 1 || !r.Items[0].Path.Equal(want) || r.Items[0].State != DiffItemDifferent {
+		t.Errorf("diff(a, b) = %s, want a single DiffItemDifferent at %s", pretty.Sprint(r), want)
+	}
+}
+
+func TestDiffCompare(t *testing.T) {
+	t.Parallel()
+
+	type rule struct {
+		Mode string
+	}
+	type st struct {
+		Mode    string
+		Timeout string
+		Rules   []rule
+		Other   string
+	}
+
+	traits := &FieldTraits{}
+	traits.Compare(Path{}.Pointer().Field("Mode"), CompareEqualFold)
+	traits.Compare(Path{}.Pointer().Field("Timeout"), CompareDuration)
+	traits.Compare(Path{}.Pointer().Field("Rules").AnySliceIndex().Field("Mode"), CompareEqualFold)
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		a, b st
+		want []string
+	}{
+		{
+			name: "equivalent values",
+			a:    st{Mode: "rate", Timeout: "10s", Rules: []rule{{Mode: "a"}}},
+			b:    st{Mode: "RATE", Timeout: "10.000s", Rules: []rule{{Mode: "A"}}},
+		},
+		{
+			name: "different values",
+			a:    st{Mode: "rate", Timeout: "10s", Rules: []rule{{Mode: "a"}}},
+			b:    st{Mode: "UTILIZATION", Timeout: "1m", Rules: []rule{{Mode: "b"}}},
+			want: []string{"*.Mode", "*.Timeout", "*.Rules!0.Mode"},
+		},
+		{
+			name: "fields without a comparator",
+			a:    st{Other: "x"},
+			b:    st{Other: "X"},
+			want: []string{"*.Other"},
+		},
+		{
+			name: "invalid durations",
+			a:    st{Timeout: "abc"},
+			b:    st{Timeout: "abc"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			var got []string
+			for _, di := range r.Items {
+				got = append(got, di.Path.String())
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("diff(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffImmutable(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		A string
+		B string
+	}
+	type st struct {
+		Name    string
+		Network string
+		Desc    string
+		StP     *sti
+		L       []sti
+	}
+
+	traits := &FieldTraits{}
+	traits.Immutable(Path{}.Pointer().Field("Network"))
+	traits.Immutable(Path{}.Pointer().Field("StP").Pointer().Field("A"))
+	traits.Immutable(Path{}.Pointer().Field("L").AnySliceIndex().Field("B"))
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		a, b          st
+		wantImmutable []string
+		wantRecreate  bool
+	}{
+		{
+			name: "no diff",
+			a:    st{Network: "n1"},
+			b:    st{Network: "n1"},
+		},
+		{
+			name: "mutable fields",
+			a:    st{Desc: "a", StP: &sti{B: "x"}, L: []sti{{A: "a"}}},
+			b:    st{Desc: "b", StP: &sti{B: "y"}, L: []sti{{A: "b"}}},
+		},
+		{
+			name:          "immutable field",
+			a:             st{Network: "n1", Desc: "a"},
+			b:             st{Network: "n2", Desc: "b"},
+			wantImmutable: []string{"*.Network"},
+			wantRecreate:  true,
+		},
+		{
+			name:          "nested immutable fields",
+			a:             st{StP: &sti{A: "x"}, L: []sti{{B: "a"}}},
+			b:             st{StP: &sti{A: "y"}, L: []sti{{B: "b"}}},
+			wantImmutable: []string{"*.StP*.A", "*.L!0.B"},
+			wantRecreate:  true,
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			var got []string
+			for _, di := range r.ImmutableItems() {
+				got = append(got, di.Path.String())
+			}
+			if diff := cmp.Diff(got, tc.wantImmutable); diff != "" {
+				t.Errorf("ImmutableItems(): -got,+want: %s", diff)
+			}
+			if r.NeedsRecreate() != tc.wantRecreate {
+				t.Errorf("NeedsRecreate() = %t, want %t", r.NeedsRecreate(), tc.wantRecreate)
+			}
+		})
+	}
+}
+
+func TestDiffServerDefault(t *testing.T) {
+	t.Parallel()
+
+	type backend struct {
+		BalancingMode string
+	}
+	type st struct {
+		TimeoutSec int64
+		MaxRate    *int64
+		Backends   []backend
+	}
+
+	traits := &FieldTraits{}
+	traits.ServerDefault(Path{}.Pointer().Field("TimeoutSec"), int64(30))
+	traits.ServerDefault(Path{}.Pointer().Field("MaxRate"), int64(100))
+	traits.ServerDefault(Path{}.Pointer().Field("Backends").AnySliceIndex().Field("BalancingMode"), "UTILIZATION")
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	badTraits := &FieldTraits{}
+	badTraits.ServerDefault(Path{}.Pointer().Field("TimeoutSec"), 30)
+	if err := badTraits.CheckSchema(reflect.TypeOf(&st{})); err == nil {
+		t.Errorf("CheckSchema() = nil, want error for an int default of an int64 field")
+	}
+
+	i64 := func(i int64) *int64 { return &i }
+
+	for _, tc := range []struct {
+		name string
+		a, b st
+		want []string
+	}{
+		{
+			name: "unset and server default",
+			a:    st{Backends: []backend{{}}},
+			b:    st{TimeoutSec: 30, MaxRate: i64(100), Backends: []backend{{BalancingMode: "UTILIZATION"}}},
+		},
+		{
+			name: "server default and unset",
+			a:    st{TimeoutSec: 30, MaxRate: i64(100)},
+			b:    st{},
+		},
+		{
+			name: "unset and non-default",
+			a:    st{Backends: []backend{{}}},
+			b:    st{TimeoutSec: 10, MaxRate: i64(1), Backends: []backend{{BalancingMode: "RATE"}}},
+			want: []string{"*.TimeoutSec", "*.MaxRate*", "*.Backends!0.BalancingMode"},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := diff(&tc.a, &tc.b, traits)
+			if err != nil {
+				t.Fatalf("diff() = %v, want nil", err)
+			}
+			var got []string
+			for _, di := range r.Items {
+				got = append(got, di.Path.String())
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("diff(): -got,+want: %s", diff)
+			}
+		})
+	}
+}
@@ -55,6 +55,42 @@ limitations under the License.
 //	    if errors.As(err, &objErrors) { /* handle MissingFields, etc. */ }
 //	}
 //
+// ConversionReport() lists the fields that would be lost in each version
+// without converting, e.g. to tell the user that a configuration requires the
+// Beta API:
+//
+//	report := addr.ConversionReport()
+//	if !report.Lossless(meta.VersionGA) { /* report.LosslessVersions() */ }
+//
+// NewResource() takes ResourceOptions to configure the resource at
+// construction, e.g. to seed field values and set the version preference:
+//
+//	res := NewResource[compute.Address, alpha.Address, beta.Address](id, nil,
+//	  WithFieldValue(meta.VersionGA, Path{}.Pointer().Field("Description"), "desc"),
+//	  WithVersionPreference(meta.VersionGA, meta.VersionBeta))
+//
+// # Resources that are missing a version
+//
+// Use NoVersion as the type of a version that does not exist for the
+// resource. To*() for the version returns an error matching
+// ErrVersionNotAvailable:
+//
+//	res := NewResource[NoVersion, alpha.Foo, beta.Foo](id, nil)
+//
+// # Resources with more than three versions
+//
+// Resource is limited to the GA, Alpha and Beta versions. VersionSet wraps an
+// arbitrary number of versions with the same conversion semantics:
+//
+//	vs, err := NewVersionSet(id,
+//	  NewVersion[v1.Foo]("v1", nil),
+//	  NewVersion[v1beta1.Foo]("v1beta1", nil),
+//	  NewVersion[v1alpha1.Foo]("v1alpha1", nil),
+//	  NewVersion[v1alpha2.Foo]("v1alpha2", nil),
+//	)
+//	err = AccessVersion(vs, "v1alpha2", func(x *v1alpha2.Foo) { ... })
+//	obj, err := ToVersion[v1.Foo](vs, "v1")
+//
 // # Checking type assumptions with unit tests
 //
 // Resource.CheckSchema() can be used to check if the types referenced meet the
@@ -76,4 +112,8 @@ limitations under the License.
 //	// finished. This allows for any additional fixup of the fields after
 //	// conversion.
 //	func (*myTypeTrait) CopyHelperGAtoAlpha(...) { ... }
+//
+// Fields whose representation changed between versions (e.g. renamed fields)
+// can be converted individually with FieldTraits.Convert() instead of a
+// CopyHelper.
 package api
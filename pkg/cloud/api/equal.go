@@ -0,0 +1,57 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+// EqualOption is an option to Equal().
+type EqualOption func(*diffOptions)
+
+// EqualIgnore skips the fields at or below paths in the comparison. paths
+// may contain wildcards (see AnySliceIndex() and AnyMapIndex()).
+func EqualIgnore(paths ...Path) EqualOption {
+	return func(o *diffOptions) { o.ignore = append(o.ignore, paths...) }
+}
+
+// Equal returns true if the resources a and b have no differences as
+// reported by Resource.Diff(): OutputOnly and System fields and the
+// metafields are skipped, zero-valued fields with a
+// FieldTraits.ServerDefault() are compared using the default value and
+// custom comparisons from FieldTraits.Compare() are applied.
+//
+// Equal stops at the first difference and does not build a DiffResult, so it
+// is cheaper than Diff() for a "no change needed" check. Resources that
+// cannot be compared (e.g. Alpha and Beta) are not equal.
+func Equal[GA any, Alpha any, Beta any](a, b Resource[GA, Alpha, Beta], opts ...EqualOption) bool {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.firstOnly = true
+
+	var (
+		r   *DiffResult
+		err error
+	)
+	if ar, ok := a.(*resource[GA, Alpha, Beta]); ok {
+		r, err = ar.diff(b, o)
+	} else {
+		r, err = a.Diff(b)
+		if err == nil {
+			r = r.Ignore(o.ignore)
+		}
+	}
+	return err == nil && !r.HasDiff()
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestEqual(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		Status          string
+		LStr            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	traits := func(meta.Version) *FieldTraits {
+		dt := testTrait[st, st, st]{}.FieldTraits(meta.VersionGA)
+		dt.OutputOnly(Path{}.Pointer().Field("Status"))
+		dt.ServerDefault(Path{}.Pointer().Field("S"), "default")
+		dt.UnorderedSlice(Path{}.Pointer().Field("LStr"))
+		return dt
+	}
+	freeze := func(f func(x *st)) Resource[st, st, st] {
+		t.Helper()
+		res := newTestResource[st, st, st](&TypeTraitFuncs[st, st, st]{FieldTraitsF: traits})
+		if err := res.Set(&st{}); err != nil {
+			t.Fatalf("Set() = %v, want nil", err)
+		}
+		x, _ := res.ToGA()
+		f(x)
+		r, err := res.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v, want nil", err)
+		}
+		return r
+	}
+
+	base := freeze(func(x *st) {
+		x.I = 1
+		x.LStr = []string{"a", "b"}
+	})
+	for _, tc := range []struct {
+		name string
+		f    func(x *st)
+		opts []EqualOption
+		want bool
+	}{
+		{
+			name: "same",
+			f: func(x *st) {
+				x.I = 1
+				x.LStr = []string{"a", "b"}
+			},
+			want: true,
+		},
+		{
+			name: "output only and metafields differ",
+			f: func(x *st) {
+				x.I = 1
+				x.Status = "READY"
+				x.LStr = []string{"a", "b"}
+				x.ForceSendFields = []string{"S"}
+			},
+			want: true,
+		},
+		{
+			name: "server default",
+			f: func(x *st) {
+				x.I = 1
+				x.S = "default"
+				x.LStr = []string{"b", "a"}
+			},
+			want: true,
+		},
+		{
+			name: "different",
+			f: func(x *st) {
+				x.I = 2
+				x.S = "abc"
+				x.LStr = []string{"a", "c"}
+			},
+		},
+		{
+			name: "different ignored",
+			f: func(x *st) {
+				x.I = 2
+				x.LStr = []string{"a", "b"}
+			},
+			opts: []EqualOption{EqualIgnore(Path{}.Pointer().Field("I"))},
+			want: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			other := freeze(tc.f)
+			if got := Equal(base, other, tc.opts...); got != tc.want {
+				t.Errorf("Equal() = %t, want %t", got, tc.want)
+			}
+			if tc.opts != nil {
+				return
+			}
+			// Equal() is consistent with Diff().
+			dr, err := base.Diff(other)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if dr.HasDiff() == tc.want {
+				t.Errorf("Diff().HasDiff() = %t, want %t", dr.HasDiff(), !tc.want)
+			}
+		})
+	}
+}
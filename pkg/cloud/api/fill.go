@@ -158,12 +158,16 @@ func (f *filler) doMap(p Path, v reflect.Value) (bool, error) {
 }
 
 func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
+	names := traits.metafieldNames()
+	if !names.hasMetafields() {
+		return nil
+	}
 	acc := newAcceptorFuncs()
 	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
-		if p.Equal(Path{}.Pointer().Field("ServerResponse")) {
+		if names.isServerResponse(p) {
 			return false, nil
 		}
-		acc, err := newMetafieldAccessor(v)
+		acc, err := newMetafieldAccessor(v, names)
 		if err != nil {
 			return false, fmt.Errorf("fillNullAndForceSend: %w", err)
 		}
@@ -173,7 +177,7 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 
 		for i := 0; i < v.NumField(); i++ {
 			ft := v.Type().Field(i)
-			if ft.Name == "NullFields" || ft.Name == "ForceSendFields" {
+			if names.isMetafield(ft.Name) {
 				continue
 			}
 			fType := traits.fieldType(p.Field(ft.Name))
@@ -198,11 +202,66 @@ func fillNullAndForceSend(traits *FieldTraits, v reflect.Value) error {
 			d.Set(reflect.ValueOf(sl))
 		}
 
-		set(nullFields, v.FieldByName(nullFieldsName))
-		set(forceSendFields, v.FieldByName(forceSendFieldsName))
+		set(nullFields, acc.nullFields)
+		set(forceSendFields, acc.forceSendFields)
 
 		return true, nil
 	}
 
 	return visit(v, acc)
 }
+
+// StripOutputOnly zeroes the OutputOnly fields in x (a pointer to the API
+// struct), e.g. to send an object returned by the server in an update. The
+// fields are also removed from the NullFields and ForceSendFields.
+func StripOutputOnly(traits *FieldTraits, x any) error {
+	v := reflect.ValueOf(x)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("StripOutputOnly: x is not a pointer to a struct (%T)", x)
+	}
+
+	names := traits.metafieldNames()
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		if names.isServerResponse(p) {
+			return false, nil
+		}
+		var stripped []string
+		for i := 0; i < v.NumField(); i++ {
+			ft := v.Type().Field(i)
+			if traits.fieldType(p.Field(ft.Name)) != FieldTypeOutputOnly {
+				continue
+			}
+			v.Field(i).Set(reflect.Zero(ft.Type))
+			stripped = append(stripped, ft.Name)
+		}
+		if len(stripped) == 0 {
+			return true, nil
+		}
+		mfa, err := newMetafieldAccessor(v, names)
+		if err != nil || mfa.empty() {
+			// Type does not have metafields.
+			return true, nil
+		}
+		remove := func(mf reflect.Value) {
+			var names []string
+			for _, fn := range mf.Interface().([]string) {
+				keep := true
+				for _, s := range stripped {
+					if fn == s {
+						keep = false
+						break
+					}
+				}
+				if keep {
+					names = append(names, fn)
+				}
+			}
+			mf.Set(reflect.ValueOf(names))
+		}
+		remove(mfa.forceSendFields)
+		remove(mfa.nullFields)
+		return true, nil
+	}
+	return visit(v, acc)
+}
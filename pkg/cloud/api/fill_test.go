@@ -176,3 +176,57 @@ func TestFillNullAndForceSend(t *testing.T) {
 		})
 	}
 }
+
+func TestStripOutputOnly(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		Status          string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name              string
+		CreationTimestamp string
+		Id                uint64
+		Kind              string
+		SelfLink          string
+		I                 int
+		StP               *sti
+		NullFields        []string
+		ForceSendFields   []string
+	}
+
+	traits := &FieldTraits{}
+	traits.OutputOnlyBuiltins()
+	traits.OutputOnly(Path{}.Pointer().Field("StP").Pointer().Field("Status"))
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+
+	x := &st{
+		Name:              "foo",
+		CreationTimestamp: "2023-01-01",
+		Id:                123,
+		Kind:              "compute#foo",
+		SelfLink:          "https://example.com/foo",
+		StP:               &sti{I: 1, Status: "READY"},
+		ForceSendFields:   []string{"I", "Id"},
+	}
+	if err := StripOutputOnly(traits, x); err != nil {
+		t.Fatalf("StripOutputOnly() = %v, want nil", err)
+	}
+	want := &st{
+		Name:            "foo",
+		StP:             &sti{I: 1},
+		ForceSendFields: []string{"I"},
+	}
+	if diff := cmp.Diff(x, want); diff != "" {
+		t.Errorf("StripOutputOnly(); -got,+want: %s", diff)
+	}
+
+	if err := StripOutputOnly(traits, st{}); err == nil {
+		t.Error("StripOutputOnly(<non-pointer>) = nil, want error")
+	}
+}
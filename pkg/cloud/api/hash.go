@@ -0,0 +1,145 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// hashObject returns a stable digest of the non-zero fields of obj. Fields
+// that are OutputOnly or System in traits, ServerResponse and the metafields
+// are excluded. Zero-valued fields with a ServerDefault() are hashed with the
+// default value.
+//
+// The digest only depends on the names and values of the fields, so objects
+// of different API versions with the same fields set have the same hash. Map
+// entries and the elements of KeyedSlice() and UnorderedSlice() slices are
+// hashed independent of their order. The order of the elements of other slices
+// is significant.
+func hashObject(obj any, traits *FieldTraits) (string, error) {
+	h := &hasher{traits: traits}
+	if err := h.do(Path{}, "", reflect.ValueOf(obj)); err != nil {
+		return "", err
+	}
+	sort.Strings(h.lines)
+
+	sum := sha256.New()
+	for _, l := range h.lines {
+		sum.Write([]byte(l))
+		sum.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+type hasher struct {
+	traits *FieldTraits
+	// lines are "<name>=<value>" for each non-zero basic value.
+	lines []string
+}
+
+// do hashes v. p is the Path of v that is used to look up the traits and
+// name is the canonical name of v, which differs from p for elements of
+// keyed slices.
+func (h *hasher) do(p Path, name string, v reflect.Value) error {
+	switch h.traits.fieldType(p) {
+	case FieldTypeOutputOnly, FieldTypeSystem:
+		return nil
+	}
+	v = h.traits.withServerDefault(p, v)
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return h.do(p.Pointer(), name+string(pathPointer), v.Elem())
+	case reflect.Struct:
+		names := h.traits.metafieldNames()
+		for i := 0; i < v.NumField(); i++ {
+			fn := v.Type().Field(i).Name
+			if names.isMetafield(fn) || fn == names.ServerResponse {
+				continue
+			}
+			if err := h.do(p.Field(fn), name+string(pathField)+fn, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		keyFields := h.traits.keyFields(p)
+		_, keyed := sliceKeys(v, keyFields)
+		keyed = keyed && len(keyFields) > 0
+		unordered := !keyed && h.traits.isUnordered(p)
+		for i := 0; i < v.Len(); i++ {
+			en := fmt.Sprintf("%s%c%d", name, pathSliceIndex, i)
+			switch {
+			case keyed:
+				en = fmt.Sprintf("%s%c%s", name, pathMapIndex, sliceKey(v.Index(i), keyFields))
+			case unordered:
+				// Name the element by its own contents so that the
+				// digest does not depend on the position.
+				d, err := h.elemDigest(p.Index(i), v.Index(i))
+				if err != nil {
+					return err
+				}
+				en = fmt.Sprintf("%s%c%s", name, pathSliceIndex, d)
+			}
+			if err := h.do(p.Index(i), en, v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			k := iter.Key()
+			en := fmt.Sprintf("%s%c%v", name, pathMapIndex, k.Interface())
+			if err := h.do(p.MapIndex(k.Interface()), en, iter.Value()); err != nil {
+				return err
+			}
+		}
+	default:
+		if v.IsZero() {
+			return nil
+		}
+		b, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Errorf("hash %s: %w", p, err)
+		}
+		h.lines = append(h.lines, name+"="+string(b))
+	}
+	return nil
+}
+
+// elemDigest returns a digest of the element v of an UnorderedSlice().
+func (h *hasher) elemDigest(p Path, v reflect.Value) (string, error) {
+	sub := &hasher{traits: h.traits}
+	if err := sub.do(p, "", v); err != nil {
+		return "", err
+	}
+	sort.Strings(sub.lines)
+
+	sum := sha256.New()
+	for _, l := range sub.lines {
+		sum.Write([]byte(l))
+		sum.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(sum.Sum(nil))[:16], nil
+}
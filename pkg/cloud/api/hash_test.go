@@ -0,0 +1,254 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+)
+
+func TestHashObject(t *testing.T) {
+	t.Parallel()
+
+	type rule struct {
+		Name string
+		I    int
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		M               map[string]string
+		Rules           []rule
+		LStr            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stAlpha struct {
+		Name     string
+		SelfLink string
+		I        int
+		M        map[string]string
+		Rules    []rule
+		LStr     []string
+	}
+
+	traits := &FieldTraits{}
+	traits.OutputOnly(Path{}.Pointer().Field("SelfLink"))
+	traits.KeyedSlice(Path{}.Pointer().Field("Rules"), "Name")
+
+	mustHash := func(x any) string {
+		t.Helper()
+		h, err := hashObject(x, traits)
+		if err != nil {
+			t.Fatalf("hashObject() = %v, want nil", err)
+		}
+		return h
+	}
+
+	base := mustHash(&st{
+		Name:  "obj",
+		I:     10,
+		M:     map[string]string{"a": "1", "b": "2", "c": "3"},
+		Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+		LStr:  []string{"a", "b"},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		x        any
+		wantSame bool
+	}{
+		{
+			name: "different map and keyed slice order, output only and metafields",
+			x: &st{
+				Name:            "obj",
+				SelfLink:        "https://example.com/obj",
+				I:               10,
+				M:               map[string]string{"c": "3", "b": "2", "a": "1"},
+				Rules:           []rule{{Name: "y", I: 2}, {Name: "x", I: 1}},
+				LStr:            []string{"a", "b"},
+				ForceSendFields: []string{"I"},
+			},
+			wantSame: true,
+		},
+		{
+			name: "other version",
+			x: &stAlpha{
+				Name:  "obj",
+				I:     10,
+				M:     map[string]string{"a": "1", "b": "2", "c": "3"},
+				Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+				LStr:  []string{"a", "b"},
+			},
+			wantSame: true,
+		},
+		{
+			name: "unkeyed slice order",
+			x: &st{
+				Name:  "obj",
+				I:     10,
+				M:     map[string]string{"a": "1", "b": "2", "c": "3"},
+				Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+				LStr:  []string{"b", "a"},
+			},
+		},
+		{
+			name: "different keyed slice element",
+			x: &st{
+				Name:  "obj",
+				I:     10,
+				M:     map[string]string{"a": "1", "b": "2", "c": "3"},
+				Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 3}},
+				LStr:  []string{"a", "b"},
+			},
+		},
+		{
+			name: "different map value",
+			x: &st{
+				Name:  "obj",
+				I:     10,
+				M:     map[string]string{"a": "1", "b": "2", "c": "4"},
+				Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+				LStr:  []string{"a", "b"},
+			},
+		},
+	} {
+		if got := mustHash(tc.x) == base; got != tc.wantSame {
+			t.Errorf("%s: hash equal = %t, want %t", tc.name, got, tc.wantSame)
+		}
+	}
+}
+
+func TestHashObjectUnorderedSlice(t *testing.T) {
+	t.Parallel()
+
+	type rule struct {
+		Name string
+		I    int
+	}
+	type st struct {
+		URLs  []string
+		Rules []rule
+	}
+
+	traits := &FieldTraits{}
+	traits.UnorderedSlice(Path{}.Pointer().Field("URLs"))
+	traits.UnorderedSlice(Path{}.Pointer().Field("Rules"))
+
+	mustHash := func(x any) string {
+		t.Helper()
+		h, err := hashObject(x, traits)
+		if err != nil {
+			t.Fatalf("hashObject() = %v, want nil", err)
+		}
+		return h
+	}
+
+	base := mustHash(&st{
+		URLs:  []string{"a", "b"},
+		Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		x        *st
+		wantSame bool
+	}{
+		{
+			name: "reordered",
+			x: &st{
+				URLs:  []string{"b", "a"},
+				Rules: []rule{{Name: "y", I: 2}, {Name: "x", I: 1}},
+			},
+			wantSame: true,
+		},
+		{
+			name: "fields swapped between elements",
+			x: &st{
+				URLs:  []string{"a", "b"},
+				Rules: []rule{{Name: "x", I: 2}, {Name: "y", I: 1}},
+			},
+		},
+		{
+			name: "duplicate element",
+			x: &st{
+				URLs:  []string{"a", "a"},
+				Rules: []rule{{Name: "x", I: 1}, {Name: "y", I: 2}},
+			},
+		},
+	} {
+		if got := mustHash(tc.x) == base; got != tc.wantSame {
+			t.Errorf("%s: hash equal = %t, want %t", tc.name, got, tc.wantSame)
+		}
+	}
+}
+
+func TestResourceHash(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	hash := func(i int) string {
+		t.Helper()
+		res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+		if err := res.Access(func(x *st) { x.I = i }); err != nil {
+			t.Fatalf("Access() = %v, want nil", err)
+		}
+		r, err := res.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v, want nil", err)
+		}
+		h, err := r.Hash()
+		if err != nil {
+			t.Fatalf("Hash() = %v, want nil", err)
+		}
+		return h
+	}
+	if hash(1) != hash(1) {
+		t.Errorf("Hash() is not stable")
+	}
+	if hash(1) == hash(2) {
+		t.Errorf("Hash() is the same for different resources")
+	}
+}
+
+func TestHashServerDefault(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		TimeoutSec int64
+	}
+	traits := &FieldTraits{}
+	traits.ServerDefault(Path{}.Pointer().Field("TimeoutSec"), int64(30))
+
+	h1, err := hashObject(&st{}, traits)
+	if err != nil {
+		t.Fatalf("hashObject() = %v, want nil", err)
+	}
+	h2, err := hashObject(&st{TimeoutSec: 30}, traits)
+	if err != nil {
+		t.Fatalf("hashObject() = %v, want nil", err)
+	}
+	if h1 != h2 {
+		t.Errorf("hashObject() of unset and server default values differ (%s, %s)", h1, h2)
+	}
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Merge computes the resource to send to the server using three-way merge
+// semantics, similar to "kubectl apply":
+//
+//   - base is the desired state from the previous merge (i.e. what the user
+//     specified last time). This can be nil for the first merge.
+//   - desired is what the user specifies now.
+//   - live is the current state of the resource on the server.
+//
+// The result starts from live. Fields that are set in desired (non-zero or
+// listed in the metafields) are copied from desired. Fields that are set in
+// base but not in desired were removed by the user and are cleared using
+// NullFields or ForceSendFields. All other fields, e.g. fields set by the
+// server, are preserved from live. OutputOnly and System fields are always
+// taken from live.
+//
+// Structs are merged field by field and maps with basic values are merged by
+// key. Slices and all other maps are replaced as a whole.
+//
+// The result has the Version of desired. It is an error if live cannot be
+// represented in that version.
+func Merge[GA any, Alpha any, Beta any](base, desired, live Resource[GA, Alpha, Beta]) (Resource[GA, Alpha, Beta], error) {
+	ver := desired.Version()
+
+	lr, ok := live.(*resource[GA, Alpha, Beta])
+	if !ok {
+		return nil, fmt.Errorf("Merge: unsupported Resource implementation %T", live)
+	}
+	if _, err := versionObj(live, ver); err != nil {
+		return nil, fmt.Errorf("Merge: live: %w", err)
+	}
+	desiredObj, err := versionObj(desired, ver)
+	if err != nil {
+		return nil, fmt.Errorf("Merge: desired: %w", err)
+	}
+	var baseObj reflect.Value
+	if base != nil {
+		// Fields of base that cannot be represented in ver cannot
+		// be in desired either and are ignored.
+		baseObj, _ = versionObj(base, ver)
+	}
+
+	x, err := lr.x.clone()
+	if err != nil {
+		return nil, fmt.Errorf("Merge: %w", err)
+	}
+	var dest reflect.Value
+	switch ver {
+	case meta.VersionGA:
+		dest = reflect.ValueOf(&x.ga)
+	case meta.VersionAlpha:
+		dest = reflect.ValueOf(&x.alpha)
+	case meta.VersionBeta:
+		dest = reflect.ValueOf(&x.beta)
+	}
+	m := &merger{traits: x.typeTrait.FieldTraits(ver)}
+	if baseObj.IsValid() && !baseObj.IsNil() {
+		baseObj = baseObj.Elem()
+	} else {
+		baseObj = reflect.Value{}
+	}
+	if err := m.doStruct(Path{}.Pointer(), dest.Elem(), baseObj, desiredObj.Elem()); err != nil {
+		return nil, fmt.Errorf("Merge: %w", err)
+	}
+	if err := x.postAccess(ver, postAccessSkipValidation); err != nil {
+		return nil, fmt.Errorf("Merge: %w", err)
+	}
+	return x.freeze(ver)
+}
+
+// versionObj returns the object for version ver of r.
+func versionObj[GA any, Alpha any, Beta any](r Resource[GA, Alpha, Beta], ver meta.Version) (reflect.Value, error) {
+	var (
+		obj any
+		err error
+	)
+	switch ver {
+	case meta.VersionGA:
+		obj, err = r.ToGA()
+	case meta.VersionAlpha:
+		obj, err = r.ToAlpha()
+	case meta.VersionBeta:
+		obj, err = r.ToBeta()
+	default:
+		return reflect.Value{}, fmt.Errorf("invalid version %q", ver)
+	}
+	return reflect.ValueOf(obj), err
+}
+
+type merger struct {
+	traits *FieldTraits
+}
+
+// doStruct merges the struct desired into dest. base is invalid if the
+// struct was not present in base.
+func (m *merger) doStruct(p Path, dest, base, desired reflect.Value) error {
+	names := m.traits.metafieldNames()
+	destAcc, err := newMetafieldAccessor(dest, names)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	desiredAcc, err := newMetafieldAccessor(desired, names)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	var baseAcc *metafieldAccessor
+	if base.IsValid() {
+		if baseAcc, err = newMetafieldAccessor(base, names); err != nil {
+			return fmt.Errorf("%s: %w", p, err)
+		}
+	}
+
+	for i := 0; i < desired.NumField(); i++ {
+		fn := desired.Type().Field(i).Name
+		if names.isMetafield(fn) || fn == names.ServerResponse {
+			continue
+		}
+		fp := p.Field(fn)
+		switch m.traits.fieldType(fp) {
+		case FieldTypeOutputOnly, FieldTypeSystem:
+			continue
+		}
+
+		dv := desired.Field(i)
+		var bv reflect.Value
+		if base.IsValid() {
+			bv = base.Field(i)
+		}
+		switch {
+		case !dv.IsZero() || desiredAcc.inNull(fn) || desiredAcc.inForceSend(fn):
+			if err := m.doValue(fp, dest.Field(i), bv, dv, destAcc, fn); err != nil {
+				return err
+			}
+		case bv.IsValid() && (!bv.IsZero() || baseAcc.inNull(fn) || baseAcc.inForceSend(fn)):
+			// The field is no longer specified by the user.
+			clearField(dest.Field(i), destAcc, fn)
+		}
+	}
+	return nil
+}
+
+// doValue merges the field fn with the value desired into dest. acc is for
+// the struct containing the field.
+func (m *merger) doValue(p Path, dest, base, desired reflect.Value, acc *metafieldAccessor, fn string) error {
+	if desired.IsZero() {
+		clearField(dest, acc, fn)
+		return nil
+	}
+	switch {
+	case desired.Kind() == reflect.Struct:
+		return m.doStruct(p, dest, base, desired)
+	case desired.Kind() == reflect.Pointer && desired.Elem().Kind() == reflect.Struct:
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		if base.IsValid() && !base.IsNil() {
+			base = base.Elem()
+		} else {
+			base = reflect.Value{}
+		}
+		return m.doStruct(p.Pointer(), dest.Elem(), base, desired.Elem())
+	case desired.Kind() == reflect.Map && isBasicT(desired.Type().Elem()):
+		if dest.IsNil() {
+			dest.Set(reflect.MakeMap(dest.Type()))
+		}
+		if base.IsValid() {
+			for _, k := range base.MapKeys() {
+				if !desired.MapIndex(k).IsValid() {
+					dest.SetMapIndex(k, reflect.Value{})
+				}
+			}
+		}
+		for _, k := range desired.MapKeys() {
+			dest.SetMapIndex(k, desired.MapIndex(k))
+		}
+		return nil
+	}
+	if err := newCopier(copierMetafields(m.traits.metafieldNames())).doValues(p, dest, desired); err != nil {
+		return fmt.Errorf("%s: %w", p, err)
+	}
+	return nil
+}
+
+// clearField sets the field fn to the zero value and adds it to the
+// metafields in acc so that it is sent to the server.
+func clearField(fv reflect.Value, acc *metafieldAccessor, fn string) {
+	fv.Set(reflect.Zero(fv.Type()))
+	switch fv.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		acc.set(fn, true)
+	default:
+		acc.set(fn, false)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		StP             *sti
+		LStr            []string
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	freeze := func(f func(x *st)) Resource[st, st, st] {
+		t.Helper()
+		res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+		if err := res.Access(f); err != nil {
+			t.Fatalf("Access() = %v, want nil", err)
+		}
+		r, err := res.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v, want nil", err)
+		}
+		return r
+	}
+
+	base := freeze(func(x *st) {
+		x.I = 1
+		x.S = "a"
+		x.StP = &sti{I: 1}
+		x.M = map[string]string{"a": "1", "b": "2"}
+	})
+	desired := freeze(func(x *st) {
+		x.S = "b"
+		x.StP = &sti{I: 0, ForceSendFields: []string{"I"}}
+		x.M = map[string]string{"a": "1", "c": "3"}
+	})
+	live := freeze(func(x *st) {
+		x.SelfLink = "link"
+		x.I = 1
+		x.S = "a"
+		x.StP = &sti{I: 1}
+		x.LStr = []string{"server"}
+		x.M = map[string]string{"a": "1", "b": "2", "s": "x"}
+	})
+
+	for _, tc := range []struct {
+		name string
+		base Resource[st, st, st]
+		want *st
+	}{
+		{
+			name: "three-way",
+			base: base,
+			want: &st{
+				Name:     "obj-1",
+				SelfLink: "link",
+				// I was removed by the user.
+				I:               0,
+				S:               "b",
+				StP:             &sti{ForceSendFields: []string{"I"}},
+				LStr:            []string{"server"},
+				M:               map[string]string{"a": "1", "c": "3", "s": "x"},
+				ForceSendFields: []string{"I"},
+			},
+		},
+		{
+			name: "no base",
+			want: &st{
+				Name:     "obj-1",
+				SelfLink: "link",
+				I:        1,
+				S:        "b",
+				StP:      &sti{ForceSendFields: []string{"I"}},
+				LStr:     []string{"server"},
+				M:        map[string]string{"a": "1", "b": "2", "c": "3", "s": "x"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Merge(tc.base, desired, live)
+			if err != nil {
+				t.Fatalf("Merge() = %v, want nil", err)
+			}
+			if got.Version() != meta.VersionGA {
+				t.Errorf("Version() = %v, want %v", got.Version(), meta.VersionGA)
+			}
+			gotObj, err := got.ToGA()
+			if err != nil {
+				t.Fatalf("ToGA() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(gotObj, tc.want); diff != "" {
+				t.Errorf("Merge(); -got,+want: %s", diff)
+			}
+		})
+	}
+
+	// The inputs are not modified.
+	liveObj, _ := live.ToGA()
+	if liveObj.I != 1 || len(liveObj.ForceSendFields) != 0 || len(liveObj.M) != 3 {
+		t.Errorf("live = %+v, want unmodified", liveObj)
+	}
+}
@@ -21,50 +21,105 @@ import (
 	"reflect"
 )
 
-const (
-	nullFieldsName      = "NullFields"
-	forceSendFieldsName = "ForceSendFields"
-)
+// MetafieldNames are the names of the fields in the API structs that are not
+// part of the resource but control how it is sent to the server. The default
+// (DefaultMetafieldNames()) are the conventions of the google.golang.org/api
+// structs. An empty name means the structs do not have the field; use
+// MetafieldNames{} for structs without metafields, in which case zero-valued
+// fields cannot be distinguished from unset fields.
+type MetafieldNames struct {
+	// NullFields is the list of fields to send as null.
+	NullFields string
+	// ForceSendFields is the list of zero-valued fields to send.
+	ForceSendFields string
+	// ServerResponse is the struct with the HTTP response of the server.
+	ServerResponse string
+}
+
+// DefaultMetafieldNames returns the metafield names used by the
+// google.golang.org/api structs.
+func DefaultMetafieldNames() MetafieldNames {
+	return MetafieldNames{
+		NullFields:      "NullFields",
+		ForceSendFields: "ForceSendFields",
+		ServerResponse:  "ServerResponse",
+	}
+}
+
+// isMetafield returns true if fieldName is NullFields or ForceSendFields.
+func (n MetafieldNames) isMetafield(fieldName string) bool {
+	return fieldName != "" && (fieldName == n.NullFields || fieldName == n.ForceSendFields)
+}
+
+// isServerResponse returns true if p is the top-level ServerResponse field.
+func (n MetafieldNames) isServerResponse(p Path) bool {
+	return n.ServerResponse != "" && p.Equal(Path{}.Pointer().Field(n.ServerResponse))
+}
 
-func newMetafieldAccessor(v reflect.Value) (*metafieldAccessor, error) {
+// hasMetafields returns true if the structs have NullFields and
+// ForceSendFields.
+func (n MetafieldNames) hasMetafields() bool {
+	return n.NullFields != "" && n.ForceSendFields != ""
+}
+
+// newMetafieldAccessor returns the accessor for the metafields of the struct
+// v. It is an error if v does not have the metafields. If names has no
+// metafields, the accessor is empty and set() does nothing.
+func newMetafieldAccessor(v reflect.Value, names MetafieldNames) (*metafieldAccessor, error) {
 	if v.Type().Kind() != reflect.Struct {
 		return nil, fmt.Errorf("invalid type: %s", v.Type())
 	}
 	ret := &metafieldAccessor{}
-	if t, ok := v.Type().FieldByName(nullFieldsName); ok && t.Type.Kind() == reflect.Slice && t.Type.Elem().Kind() == reflect.String {
-		ret.nullFields = v.FieldByName(nullFieldsName)
+	if !names.hasMetafields() {
+		return ret, nil
+	}
+	if t, ok := v.Type().FieldByName(names.NullFields); ok && isSliceOfStringT(t.Type) {
+		ret.nullFields = v.FieldByName(names.NullFields)
 	}
-	if t, ok := v.Type().FieldByName(forceSendFieldsName); ok && t.Type.Kind() == reflect.Slice && t.Type.Elem().Kind() == reflect.String {
-		ret.forceSendFields = v.FieldByName(forceSendFieldsName)
+	if t, ok := v.Type().FieldByName(names.ForceSendFields); ok && isSliceOfStringT(t.Type) {
+		ret.forceSendFields = v.FieldByName(names.ForceSendFields)
 	}
 	if !ret.nullFields.IsValid() || !ret.forceSendFields.IsValid() {
-		return nil, fmt.Errorf("struct does not have NullField or ForceSendFields")
+		return nil, fmt.Errorf("struct does not have %s or %s", names.NullFields, names.ForceSendFields)
 	}
 	return ret, nil
 }
 
 type metafieldAccessor struct {
+	// nullFields and forceSendFields are invalid if the structs do not
+	// have metafields.
 	nullFields      reflect.Value
 	forceSendFields reflect.Value
 }
 
+// empty returns true if the struct does not have metafields.
+func (a *metafieldAccessor) empty() bool { return !a.nullFields.IsValid() }
+
+// names returns the contents of the metafield mf.
+func (a *metafieldAccessor) names(mf reflect.Value) []string {
+	if !mf.IsValid() {
+		return nil
+	}
+	return mf.Interface().([]string)
+}
+
 func (a *metafieldAccessor) null() map[string]bool {
 	ret := map[string]bool{}
-	for _, fn := range a.nullFields.Interface().([]string) {
+	for _, fn := range a.names(a.nullFields) {
 		ret[fn] = true
 	}
 	return ret
 }
 func (a *metafieldAccessor) forceSend() map[string]bool {
 	ret := map[string]bool{}
-	for _, fn := range a.forceSendFields.Interface().([]string) {
+	for _, fn := range a.names(a.forceSendFields) {
 		ret[fn] = true
 	}
 	return ret
 }
 
 func (a *metafieldAccessor) inNull(f string) bool {
-	for _, x := range a.nullFields.Interface().([]string) {
+	for _, x := range a.names(a.nullFields) {
 		if f == x {
 			return true
 		}
@@ -73,10 +128,35 @@ func (a *metafieldAccessor) inNull(f string) bool {
 }
 
 func (a *metafieldAccessor) inForceSend(f string) bool {
-	for _, x := range a.forceSendFields.Interface().([]string) {
+	for _, x := range a.names(a.forceSendFields) {
 		if f == x {
 			return true
 		}
 	}
 	return false
 }
+
+// set adds f to NullFields if null is true, otherwise to ForceSendFields. f
+// is removed from the other metafield.
+func (a *metafieldAccessor) set(f string, null bool) {
+	if a.empty() {
+		return
+	}
+	add, remove := a.forceSendFields, a.nullFields
+	if null {
+		add, remove = remove, add
+	}
+	var kept []string
+	for _, x := range remove.Interface().([]string) {
+		if x != f {
+			kept = append(kept, x)
+		}
+	}
+	remove.Set(reflect.ValueOf(kept))
+	for _, x := range add.Interface().([]string) {
+		if x == f {
+			return
+		}
+	}
+	add.Set(reflect.Append(add, reflect.ValueOf(f)))
+}
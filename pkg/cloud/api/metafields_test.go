@@ -55,7 +55,7 @@ func TestMetafieldAccessor(t *testing.T) {
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
-			a, err := newMetafieldAccessor(reflect.ValueOf(tc.in).Elem())
+			a, err := newMetafieldAccessor(reflect.ValueOf(tc.in).Elem(), DefaultMetafieldNames())
 			if gotErr := err != nil; gotErr != tc.wantErr {
 				t.Fatalf("newMetafieldAccessor() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
 			}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Metrics receives events from Resources so that operators can monitor (and
+// alert on) unexpected losses due to version skew in production. Kind is the
+// ResourceID.Resource of the resource (e.g. "backendServices") and is empty
+// if the resource does not have a ResourceID.
+//
+// Implementations must be safe for concurrent use and should not block.
+type Metrics interface {
+	// MissingField is called for each field that could not be converted
+	// to another version by Access*() or Set*(). tolerated is true if the
+	// path was given to MutableResource.TolerateMissingFields().
+	MissingField(kind string, cc ConversionContext, p Path, tolerated bool)
+	// ValidationFailure is called for each field that failed validation
+	// in Access*().
+	ValidationFailure(kind string, ver meta.Version, p Path)
+}
+
+var metricsHook = struct {
+	lock sync.RWMutex
+	m    Metrics
+}{}
+
+// SetMetrics sets the Metrics for all Resources. Set m to nil to disable
+// metrics (the default).
+func SetMetrics(m Metrics) {
+	metricsHook.lock.Lock()
+	defer metricsHook.lock.Unlock()
+
+	metricsHook.m = m
+}
+
+func getMetrics() Metrics {
+	metricsHook.lock.RLock()
+	defer metricsHook.lock.RUnlock()
+
+	return metricsHook.m
+}
+
+// resourceKind returns the kind reported to Metrics for the resource.
+func resourceKind(id *cloud.ResourceID) string {
+	if id == nil {
+		return ""
+	}
+	return id.Resource
+}
+
+// reportMissingFields calls Metrics.MissingField() for each of missing.
+func reportMissingFields(kind string, cc ConversionContext, missing []missingFieldOnCopy) {
+	m := getMetrics()
+	if m == nil {
+		return
+	}
+	for _, mf := range missing {
+		m.MissingField(kind, cc, mf.Path, mf.Tolerated)
+	}
+}
+
+// reportCopyError calls Metrics.MissingField() for the field if err is a
+// *MissingFieldError from a strict conversion.
+func reportCopyError(kind string, cc ConversionContext, err error) {
+	m := getMetrics()
+	if m == nil {
+		return
+	}
+	var mfErr *MissingFieldError
+	if errors.As(err, &mfErr) {
+		m.MissingField(kind, cc, mfErr.Path, false)
+	}
+}
+
+// reportValidationError calls Metrics.ValidationFailure() for each of the
+// FieldErrors in err. Other errors are not reported.
+func reportValidationError(kind string, ver meta.Version, err error) {
+	m := getMetrics()
+	if m == nil {
+		return
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return
+	}
+	for _, fe := range verr.Errors {
+		m.ValidationFailure(kind, ver, fe.Path)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeMetrics struct {
+	lock   sync.Mutex
+	events []string
+}
+
+func (m *fakeMetrics) MissingField(kind string, cc ConversionContext, p Path, tolerated bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.events = append(m.events, fmt.Sprintf("MissingField %s %d %s %t", kind, cc, p, tolerated))
+}
+
+func (m *fakeMetrics) ValidationFailure(kind string, ver meta.Version, p Path) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.events = append(m.events, fmt.Sprintf("ValidationFailure %s %s %s", kind, ver, p))
+}
+
+func TestMetrics(t *testing.T) {
+	// Not parallel as the Metrics are global.
+
+	type ga struct {
+		A               int
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		A               int
+		I               int
+		AI              int
+		BI              int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	id := &cloud.ResourceID{Resource: "metricsTest", Key: meta.GlobalKey("obj")}
+
+	for _, tc := range []struct {
+		name       string
+		f          func(MutableResource[ga, alpha, alpha]) error
+		wantErr    bool
+		wantEvents []string
+	}{
+		{
+			name: "no events",
+			f: func(r MutableResource[ga, alpha, alpha]) error {
+				return r.Access(func(x *ga) { x.A = 1 })
+			},
+		},
+		{
+			name: "missing fields",
+			f: func(r MutableResource[ga, alpha, alpha]) error {
+				r.TolerateMissingFields(Path{}.Pointer().Field("BI"))
+				return r.AccessAlpha(func(x *alpha) { x.A = 1; x.AI = 2; x.BI = 3 })
+			},
+			wantEvents: []string{
+				"MissingField metricsTest 2 *.AI false",
+				"MissingField metricsTest 2 *.BI true",
+			},
+		},
+		{
+			name: "strict conversion",
+			f: func(r MutableResource[ga, alpha, alpha]) error {
+				r.StrictConversion(meta.VersionGA)
+				return r.AccessAlpha(func(x *alpha) { x.A = 1; x.AI = 2 })
+			},
+			wantErr:    true,
+			wantEvents: []string{"MissingField metricsTest 2 *.AI false"},
+		},
+		{
+			name: "validation failure",
+			f: func(r MutableResource[ga, alpha, alpha]) error {
+				return r.Access(func(x *ga) { x.I = 1 })
+			},
+			wantErr:    true,
+			wantEvents: []string{"ValidationFailure metricsTest ga *.A"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &fakeMetrics{}
+			SetMetrics(m)
+			defer SetMetrics(nil)
+
+			res := NewResource[ga, alpha, alpha](id, &testTrait[ga, alpha, alpha]{})
+			err := tc.f(res)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("f() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if diff := cmp.Diff(m.events, tc.wantEvents); diff != "" {
+				t.Errorf("events: -got,+want: %s", diff)
+			}
+		})
+	}
+}
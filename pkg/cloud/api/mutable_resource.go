@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -35,8 +36,41 @@ const (
 	BetaToGAConversion
 	BetaToAlphaConversion
 	conversionContextCount // Sentinel value used to size arrays.
+
+	// VersionSetConversion is the context for conversions between the
+	// versions of a VersionSet. See MissingField.From and MissingField.To
+	// for the versions involved.
+	VersionSetConversion ConversionContext = -1
 )
 
+// conversionVersions gives the (from, to) versions for each
+// ConversionContext.
+var conversionVersions = [conversionContextCount][2]meta.Version{
+	GAToAlphaConversion:   {meta.VersionGA, meta.VersionAlpha},
+	GAToBetaConversion:    {meta.VersionGA, meta.VersionBeta},
+	AlphaToGAConversion:   {meta.VersionAlpha, meta.VersionGA},
+	AlphaToBetaConversion: {meta.VersionAlpha, meta.VersionBeta},
+	BetaToGAConversion:    {meta.VersionBeta, meta.VersionGA},
+	BetaToAlphaConversion: {meta.VersionBeta, meta.VersionAlpha},
+}
+
+// conversionContextFor returns the ConversionContext for converting from
+// version from to version to. Versions other than GA, Alpha and Beta are
+// VersionSetConversion.
+func conversionContextFor(from, to meta.Version) ConversionContext {
+	for cc, v := range conversionVersions {
+		if v[0] == from && v[1] == to {
+			return ConversionContext(cc)
+		}
+	}
+	return VersionSetConversion
+}
+
+// ErrMissingField matches (with errors.Is()) a *ConversionError or a
+// *MissingFieldError, i.e. a field was set that cannot be represented in
+// the target version.
+var ErrMissingField = errors.New("missing field")
+
 // ConversionError is returned from To*() methods. Inspect this error to get
 // more details on what did not convert.
 type ConversionError struct {
@@ -54,35 +88,172 @@ func (e *ConversionError) Error() string {
 	return fmt.Sprintf("ConversionError: missing fields %v", e.MissingFields)
 }
 
+// Is returns true if target is ErrMissingField.
+func (e *ConversionError) Is(target error) bool {
+	return target == ErrMissingField
+}
+
+// Has returns true if a field at or below p was not converted. p may
+// contain wildcards (see AnySliceIndex() and AnyMapIndex()).
+func (e *ConversionError) Has(p Path) bool {
+	for _, mf := range e.MissingFields {
+		if mf.Path.MatchPrefix(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Errors returns the MissingFields with SeverityError, i.e. the loss of
+// configuration as opposed to informational fields (see
+// FieldTraits.Informational()).
+func (e *ConversionError) Errors() []MissingField {
+	var ret []MissingField
+	for _, mf := range e.MissingFields {
+		if mf.Severity == SeverityError {
+			ret = append(ret, mf)
+		}
+	}
+	return ret
+}
+
+// Informational returns true if all of the MissingFields have SeverityInfo,
+// i.e. the conversion only lost fields that are acceptable to lose.
+func (e *ConversionError) Informational() bool {
+	return len(e.Errors()) == 0
+}
+
+// Without returns the error without the MissingFields at or below any of
+// paths. Returns nil if there are no remaining MissingFields. This can be
+// used to tolerate the loss of known fields:
+//
+//	ga, err := res.ToGA()
+//	var cerr *ConversionError
+//	if errors.As(err, &cerr) {
+//	  err = cerr.Without(Path{}.Pointer().Field("AlphaOnlyField"))
+//	}
+func (e *ConversionError) Without(paths ...Path) error {
+	ret := &ConversionError{}
+	for _, mf := range e.MissingFields {
+		var skip bool
+		for _, p := range paths {
+			if mf.Path.MatchPrefix(p) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			ret.MissingFields = append(ret.MissingFields, mf)
+		}
+	}
+	if !ret.hasErr() {
+		return nil
+	}
+	return ret
+}
+
 // MissingField describes a field that was lost when converting between API
 // versions due to the field not being present in struct.
 type MissingField struct {
 	// Context gives the version to => from.
 	Context ConversionContext
+	// From is the version the field was converted from.
+	From meta.Version
+	// To is the version the field was converted to.
+	To meta.Version
+	// Path of the field that is missing.
+	Path Path
+	// Value of the source field.
+	Value any
+	// Severity of the loss of the field.
+	Severity Severity
+}
+
+// Severity classifies the loss of a field in a conversion.
+type Severity int
+
+const (
+	// SeverityError is the loss of configuration. This is the default.
+	SeverityError Severity = iota
+	// SeverityInfo is an acceptable loss, e.g. an output-only field that
+	// does not exist in the target version. See FieldTraits.Informational().
+	SeverityInfo
+)
+
+// String implements fmt.Stringer.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "Error"
+	case SeverityInfo:
+		return "Info"
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// MissingFieldError is returned when strict conversion is enabled and a field
+// cannot be represented in the strict version. See
+// MutableResource.StrictConversion().
+type MissingFieldError struct {
 	// Path of the field that is missing.
 	Path Path
 	// Value of the source field.
 	Value any
 }
 
+// Error implements error.
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("field %s (value %v) cannot be represented in the target version", e.Path, e.Value)
+}
+
+// Is returns true if target is ErrMissingField.
+func (e *MissingFieldError) Is(target error) bool {
+	return target == ErrMissingField
+}
+
 type conversionErrors struct {
 	missingFields []missingFieldOnCopy
 }
 
 // NewResource constructs a new Resource.
 //
-// If typeTrait is nil, then it will be set to BaseTypeTrait.
+// If typeTrait is nil, then the TypeTrait registered for the kind of resource
+// (see RegisterTypeTrait()) is used. If none is registered, it will be set to
+// BaseTypeTrait. If the registered TypeTrait is for different types,
+// BaseTypeTrait is used and Freeze() returns the error.
+//
+// opts configure the resource (see ResourceOption). NewResource panics if an
+// initial value from WithFieldValue() cannot be set; this is a programming
+// error.
 func NewResource[GA any, Alpha any, Beta any](
 	resourceID *cloud.ResourceID,
 	typeTrait TypeTrait[GA, Alpha, Beta],
+	opts ...ResourceOption,
 ) *mutableResource[GA, Alpha, Beta] {
+	var typeTraitErr error
 	if typeTrait == nil {
-		typeTrait = &BaseTypeTrait[GA, Alpha, Beta]{}
+		typeTrait, typeTraitErr = defaultTypeTrait[GA, Alpha, Beta](resourceID)
+	}
+	var o resourceOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.fieldTraits) > 0 {
+		typeTrait = newOverlayTypeTrait(typeTrait, o.fieldTraits)
 	}
 
 	obj := &mutableResource[GA, Alpha, Beta]{
-		typeTrait:  typeTrait,
-		resourceID: resourceID,
+		copierOptions: o.copierOptions,
+		typeTrait:     typeTrait,
+		typeTraitErr:  typeTraitErr,
+		resourceID:    resourceID,
+		strictVersion: o.strictVersion,
+	}
+	if o.versionPreference != nil {
+		obj.VersionPreference(o.versionPreference...)
+	}
+	if o.tolerated != nil {
+		obj.TolerateMissingFields(o.tolerated...)
 	}
 
 	// Set .Name from the ResourceID.
@@ -100,9 +271,34 @@ func NewResource[GA any, Alpha any, Beta any](
 	setName(reflect.ValueOf(&obj.alpha).Elem())
 	setName(reflect.ValueOf(&obj.beta).Elem())
 
+	for _, fv := range o.values {
+		if err := obj.setInitial(fv); err != nil {
+			panic(fmt.Sprintf("NewResource: WithFieldValue(%s, %s): %v", fv.ver, fv.path, err))
+		}
+	}
+
 	return obj
 }
 
+// setInitial sets the value from WithFieldValue() without validation.
+func (u *mutableResource[GA, Alpha, Beta]) setInitial(fv fieldValue) error {
+	v, err := u.versionValue(fv.ver)
+	if err != nil {
+		return err
+	}
+	vv, err := pathValue(v.Type(), fv.path, fv.value)
+	if err != nil {
+		return err
+	}
+	if err := setPath(v, fv.path, vv); err != nil {
+		return err
+	}
+	if u.setFields != nil {
+		u.setFields.add(fv.path)
+	}
+	return u.postAccess(fv.ver, postAccessSkipValidation)
+}
+
 // MutableResource wraps the multi-versioned concrete resources.
 type MutableResource[GA any, Alpha any, Beta any] interface {
 	// CheckSchema should be called in init() to ensure that the resource being
@@ -119,7 +315,9 @@ type MutableResource[GA any, Alpha any, Beta any] interface {
 	// configuration.
 	ImpliedVersion() (meta.Version, error)
 
-	// Access the mutable resource.
+	// Access the mutable resource. If the resource is invalid after f
+	// returns, a *ValidationError listing all of the invalid fields is
+	// returned.
 	Access(f func(x *GA)) error
 	// AccessAlpha resource.
 	AccessAlpha(f func(x *Alpha)) error
@@ -149,15 +347,107 @@ type MutableResource[GA any, Alpha any, Beta any] interface {
 	// object returned from GCE.
 	SetBeta(src *Beta) error
 
+	// StrictConversion enables fail-fast conversion: Access*() and Set*()
+	// return a *MissingFieldError if a field cannot be represented in
+	// version ver. The default is to record the missing fields, which are
+	// returned as a ConversionError from To*(). After an error, the resource
+	// may be partially updated and should be discarded.
+	StrictConversion(ver meta.Version)
+
+	// TolerateMissingFields downgrades the loss of fields at or below
+	// paths during conversion from an error to a warning: these fields do
+	// not cause an error from To*() (or StrictConversion()) and are
+	// returned by ConversionWarnings() instead. Applies to subsequent
+	// Access*() and Set*() calls. paths may contain wildcards (see
+	// AnySliceIndex() and AnyMapIndex()).
+	TolerateMissingFields(paths ...Path)
+	// ConversionWarnings returns the fields tolerated by
+	// TolerateMissingFields() that could not be represented in version
+	// ver.
+	ConversionWarnings(ver meta.Version) []MissingField
+	// ConversionReport returns the fields that would be lost converting
+	// the current state of the resource to each version, without
+	// modifying the resource.
+	ConversionReport() *ConversionReport
+
+	// VersionPreference sets the order of preference of the versions used
+	// by ImpliedVersion() (and Freeze()) to resolve a resource that can be
+	// represented in more than one version, e.g. (Beta, Alpha) to prefer
+	// Beta over Alpha. The first version in the order that can represent
+	// all of the fields is chosen; versions not in the order are not
+	// considered. Calling with no versions restores the default behavior.
+	VersionPreference(order ...meta.Version)
+
+	// GetByPath returns the value of the field at p in version ver. The
+	// zero value of the field is returned if p traverses a nil pointer or
+	// a missing map key.
+	GetByPath(ver meta.Version, p Path) (any, error)
+	// SetByPath sets the field at p in version ver to value as if it
+	// were written by Access*(). Nil pointers along p are allocated. The
+	// type of value must be assignable to the field.
+	SetByPath(ver meta.Version, p Path, value any) error
+
+	// ClearField sets the field at p to the zero value in all versions
+	// and adds it to the NullFields (for pointers, slices and maps) or
+	// ForceSendFields of the containing struct so that the field is
+	// cleared on the server by an update. p may only contain field
+	// references and pointer dereferences. It is an error if the field
+	// does not exist in any version.
+	ClearField(p Path) error
+
+	// RecordSetFields enables tracking of the fields explicitly set by
+	// subsequent writes. See SetFields(). Tracking is not enabled by
+	// default as it requires a copy of the resource for every Access*().
+	RecordSetFields()
+	// SetFields returns the fields that were explicitly set with Access*(),
+	// SetByPath(), ClearField() or WithFieldValue() since RecordSetFields()
+	// was called, as opposed to fields that were never mentioned. A field
+	// is set if its value was changed or it was added to the NullFields or
+	// ForceSendFields. Fields set from the server with Set*() are not
+	// included. Writes within a slice or a map are recorded as a write to
+	// the entire slice or map. Returns nil if tracking was not enabled.
+	SetFields() []Path
+
+	// RecordDelta enables recording of the fields written by subsequent
+	// calls to Access*(). See Delta().
+	RecordDelta()
+	// Delta returns the fields written by Access*() since RecordDelta() was
+	// called. It is an error if recording was not enabled.
+	Delta() (*PatchDelta[GA, Alpha, Beta], error)
+
 	// Freeze the resource to a read-only copy. It is an error if it is ambiguous
 	// which version is the correct one i.e. not all fields can be represented in a
-	// single version of the resource.
+	// single version of the resource. A *ValidationError listing the unset
+	// fields is returned if FieldTraits.Required() fields are not set.
 	Freeze() (Resource[GA, Alpha, Beta], error)
+
+	// Clone returns an independent deep copy of this resource, including
+	// the conversion errors and recorded delta. The TypeTrait is shared
+	// with the copy.
+	Clone() (MutableResource[GA, Alpha, Beta], error)
+
+	// DebugString returns a human readable dump of the resource for
+	// debugging: the non-zero fields of each version side by side and the
+	// conversion errors.
+	DebugString() string
+	// DebugJSON is a compact JSON version of DebugString().
+	DebugJSON() ([]byte, error)
+
+	// Snapshot saves the current state of the resource (all versions,
+	// conversion errors and recorded delta). Use Restore() to revert to the
+	// state, e.g. when a later step in a sequence of Access*() calls fails.
+	Snapshot() (*Snapshot[GA, Alpha, Beta], error)
+	// Restore the state saved by Snapshot(). The same Snapshot can be
+	// restored multiple times.
+	Restore(s *Snapshot[GA, Alpha, Beta]) error
 }
 
 type mutableResource[GA any, Alpha any, Beta any] struct {
 	copierOptions []copierOption
 	typeTrait     TypeTrait[GA, Alpha, Beta]
+	// typeTraitErr is the error from looking up the registered TypeTrait
+	// in NewResource(). This is returned by Freeze().
+	typeTraitErr error
 
 	ga    GA
 	alpha Alpha
@@ -165,32 +455,75 @@ type mutableResource[GA any, Alpha any, Beta any] struct {
 
 	resourceID *cloud.ResourceID
 	errors     [conversionContextCount]conversionErrors
+
+	// delta is non-nil if the writes from Access*() are being recorded.
+	delta *deltaRecorder
+	// strictVersion is the version for strict conversion. Empty if strict
+	// conversion is not enabled.
+	strictVersion meta.Version
+	// versionPreference for ImpliedVersion(). Empty if the default
+	// behavior is used.
+	versionPreference []meta.Version
+	// tolerated are the paths given to TolerateMissingFields().
+	tolerated []Path
+	// setFields are the fields explicitly set by the user. nil if set
+	// fields are not being tracked. See SetFields().
+	setFields *deltaRecorder
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) CheckSchema() error {
-	err := checkSchema(reflect.TypeOf(&u.ga))
-	if err != nil {
-		return err
+	for _, x := range []struct {
+		ver meta.Version
+		t   reflect.Type
+	}{
+		{meta.VersionGA, reflect.TypeOf(&u.ga)},
+		{meta.VersionAlpha, reflect.TypeOf(&u.alpha)},
+		{meta.VersionBeta, reflect.TypeOf(&u.beta)},
+	} {
+		if isNoVersion(x.t) {
+			continue
+		}
+		if err := checkSchema(x.t, u.typeTrait.FieldTraits(x.ver)); err != nil {
+			return err
+		}
 	}
-	err = checkSchema(reflect.TypeOf(&u.alpha))
-	if err != nil {
-		return err
+	return nil
+}
+
+// available returns an error wrapping ErrVersionNotAvailable if the type for
+// ver is NoVersion.
+func (u *mutableResource[GA, Alpha, Beta]) available(ver meta.Version) error {
+	var t reflect.Type
+	switch ver {
+	case meta.VersionGA:
+		t = reflect.TypeOf(&u.ga)
+	case meta.VersionAlpha:
+		t = reflect.TypeOf(&u.alpha)
+	case meta.VersionBeta:
+		t = reflect.TypeOf(&u.beta)
+	default:
+		return fmt.Errorf("invalid version %q", ver)
 	}
-	err = checkSchema(reflect.TypeOf(&u.beta))
-	if err != nil {
-		return err
+	if isNoVersion(t) {
+		return fmt.Errorf("%s: %w", ver, ErrVersionNotAvailable)
 	}
 	return nil
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) ResourceID() *cloud.ResourceID { return u.resourceID }
 
+// metafieldNames returns the MetafieldNames of the struct for ver.
+func (u *mutableResource[GA, Alpha, Beta]) metafieldNames(ver meta.Version) MetafieldNames {
+	return u.typeTrait.FieldTraits(ver).metafieldNames()
+}
+
 const (
 	postAccessSkipValidation = 1 << iota
 )
 
 func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags int) error {
 	type convert struct {
+		ver        meta.Version
 		dest       reflect.Value
 		copyHelper func() error
 		errors     *conversionErrors
@@ -203,11 +536,13 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 	case meta.VersionGA:
 		src = reflect.ValueOf(&u.ga)
 		conversions = append(conversions, convert{
+			ver:        meta.VersionAlpha,
 			dest:       reflect.ValueOf(&u.alpha),
 			copyHelper: func() error { return u.typeTrait.CopyHelperGAtoAlpha(&u.alpha, &u.ga) },
 			errors:     &u.errors[GAToAlphaConversion],
 		})
 		conversions = append(conversions, convert{
+			ver:        meta.VersionBeta,
 			dest:       reflect.ValueOf(&u.beta),
 			copyHelper: func() error { return u.typeTrait.CopyHelperGAtoBeta(&u.beta, &u.ga) },
 			errors:     &u.errors[GAToBetaConversion],
@@ -215,11 +550,13 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 	case meta.VersionAlpha:
 		src = reflect.ValueOf(&u.alpha)
 		conversions = append(conversions, convert{
+			ver:        meta.VersionGA,
 			dest:       reflect.ValueOf(&u.ga),
 			copyHelper: func() error { return u.typeTrait.CopyHelperAlphaToGA(&u.ga, &u.alpha) },
 			errors:     &u.errors[AlphaToGAConversion],
 		})
 		conversions = append(conversions, convert{
+			ver:        meta.VersionBeta,
 			dest:       reflect.ValueOf(&u.beta),
 			copyHelper: func() error { return u.typeTrait.CopyHelperAlphaToBeta(&u.beta, &u.alpha) },
 			errors:     &u.errors[AlphaToBetaConversion],
@@ -227,49 +564,156 @@ func (u *mutableResource[GA, Alpha, Beta]) postAccess(srcVer meta.Version, flags
 	case meta.VersionBeta:
 		src = reflect.ValueOf(&u.beta)
 		conversions = append(conversions, convert{
+			ver:        meta.VersionGA,
 			dest:       reflect.ValueOf(&u.ga),
 			copyHelper: func() error { return u.typeTrait.CopyHelperBetaToGA(&u.ga, &u.beta) },
 			errors:     &u.errors[BetaToGAConversion],
 		})
 		conversions = append(conversions, convert{
+			ver:        meta.VersionAlpha,
 			dest:       reflect.ValueOf(&u.alpha),
 			copyHelper: func() error { return u.typeTrait.CopyHelperBetaToAlpha(&u.alpha, &u.beta) },
 			errors:     &u.errors[BetaToAlphaConversion],
 		})
 	}
 
+	if err := u.available(srcVer); err != nil {
+		return err
+	}
+	srcTraits := u.typeTrait.FieldTraits(srcVer)
 	if flags&postAccessSkipValidation == 0 {
-		if err := checkPostAccess(u.typeTrait.FieldTraits(srcVer), src); err != nil {
+		if err := checkPostAccess(srcTraits, src); err != nil {
+			reportValidationError(resourceKind(u.resourceID), srcVer, err)
 			return err
 		}
 	}
 	for _, conv := range conversions {
-		c := newCopier(u.copierOptions...)
+		if isNoVersion(conv.dest.Type()) {
+			continue
+		}
+		opts := append([]copierOption{}, u.copierOptions...)
+		if conv.ver == u.strictVersion {
+			opts = append(opts, copierStrict())
+		}
+		if u.tolerated != nil {
+			opts = append(opts, copierTolerate(u.tolerated))
+		}
+		if converters := srcTraits.convertersTo(conv.ver); converters != nil {
+			opts = append(opts, copierConverters(converters))
+		}
+		if srcTraits != nil && srcTraits.adapters != nil {
+			opts = append(opts, copierAdapters(srcTraits.adapters))
+		}
+		if srcTraits != nil && srcTraits.informational != nil {
+			opts = append(opts, copierInformational(srcTraits.informational))
+		}
+		if srcTraits != nil && srcTraits.keyedSlices != nil {
+			opts = append(opts, copierKeyedSlices(srcTraits.keyedSlices))
+		}
+		opts = append(opts, copierMetafields(srcTraits.metafieldNames()))
+		c := newCopier(opts...)
+		cc := conversionContextFor(srcVer, conv.ver)
 		if err := c.do(conv.dest, src); err != nil {
+			reportCopyError(resourceKind(u.resourceID), cc, err)
 			return err
 		}
 		if err := conv.copyHelper(); err != nil {
 			return err
 		}
 		conv.errors.missingFields = c.missing
+		reportMissingFields(resourceKind(u.resourceID), cc, c.missing)
 	}
 
 	return nil
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) Access(f func(x *GA)) error {
+	if u.delta == nil && u.setFields == nil {
+		f(&u.ga)
+		return u.postAccess(meta.VersionGA, 0)
+	}
+	before, err := snapshotForWrites(&u.ga)
+	if err != nil {
+		return err
+	}
 	f(&u.ga)
-	return u.postAccess(meta.VersionGA, 0)
+	if err := u.postAccess(meta.VersionGA, 0); err != nil {
+		return err
+	}
+	if err := recordSetFields(u.setFields, before, &u.ga, u.metafieldNames(meta.VersionGA)); err != nil {
+		return err
+	}
+	return recordDelta(u.delta, before, &u.ga)
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) AccessAlpha(f func(x *Alpha)) error {
+	if u.delta == nil && u.setFields == nil {
+		f(&u.alpha)
+		return u.postAccess(meta.VersionAlpha, 0)
+	}
+	before, err := snapshotForWrites(&u.alpha)
+	if err != nil {
+		return err
+	}
 	f(&u.alpha)
-	return u.postAccess(meta.VersionAlpha, 0)
+	if err := u.postAccess(meta.VersionAlpha, 0); err != nil {
+		return err
+	}
+	if err := recordSetFields(u.setFields, before, &u.alpha, u.metafieldNames(meta.VersionAlpha)); err != nil {
+		return err
+	}
+	return recordDelta(u.delta, before, &u.alpha)
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) AccessBeta(f func(x *Beta)) error {
+	if u.delta == nil && u.setFields == nil {
+		f(&u.beta)
+		return u.postAccess(meta.VersionBeta, 0)
+	}
+	before, err := snapshotForWrites(&u.beta)
+	if err != nil {
+		return err
+	}
 	f(&u.beta)
-	return u.postAccess(meta.VersionBeta, 0)
+	if err := u.postAccess(meta.VersionBeta, 0); err != nil {
+		return err
+	}
+	if err := recordSetFields(u.setFields, before, &u.beta, u.metafieldNames(meta.VersionBeta)); err != nil {
+		return err
+	}
+	return recordDelta(u.delta, before, &u.beta)
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) StrictConversion(ver meta.Version) {
+	u.strictVersion = ver
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) RecordDelta() {
+	u.delta = &deltaRecorder{}
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) Delta() (*PatchDelta[GA, Alpha, Beta], error) {
+	if u.delta == nil {
+		return nil, fmt.Errorf("Delta: RecordDelta() was not called")
+	}
+	ret := &PatchDelta[GA, Alpha, Beta]{
+		Paths: append([]Path{}, u.delta.paths...),
+	}
+	var err error
+	if ret.GA, err = buildDelta(&u.ga, ret.Paths, u.metafieldNames(meta.VersionGA)); err != nil {
+		return nil, err
+	}
+	if ret.Alpha, err = buildDelta(&u.alpha, ret.Paths, u.metafieldNames(meta.VersionAlpha)); err != nil {
+		return nil, err
+	}
+	if ret.Beta, err = buildDelta(&u.beta, ret.Paths, u.metafieldNames(meta.VersionBeta)); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) VersionPreference(order ...meta.Version) {
+	u.versionPreference = append([]meta.Version{}, order...)
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) ImpliedVersion() (meta.Version, error) {
@@ -277,29 +721,63 @@ func (u *mutableResource[GA, Alpha, Beta]) ImpliedVersion() (meta.Version, error
 	_, alphaErr := u.ToAlpha()
 	_, betaErr := u.ToBeta()
 
+	if len(u.versionPreference) > 0 {
+		errs := map[meta.Version]error{
+			meta.VersionGA:    gaErr,
+			meta.VersionAlpha: alphaErr,
+			meta.VersionBeta:  betaErr,
+		}
+		for _, ver := range u.versionPreference {
+			if err, ok := errs[ver]; ok && err == nil {
+				return ver, nil
+			}
+		}
+		return u.versionPreference[0], fmt.Errorf("no preferred version %v can represent the resource (ga=%v, alpha=%v, beta=%v)", u.versionPreference, gaErr, alphaErr, betaErr)
+	}
+
+	// If all of the available versions can represent the resource, then
+	// the most stable one is used. Otherwise, there must be exactly one
+	// version that can represent the resource.
+	var (
+		allOK  = true
+		stable meta.Version
+		ok     []meta.Version
+	)
+	for _, ve := range []struct {
+		ver meta.Version
+		err error
+	}{
+		{meta.VersionGA, gaErr},
+		{meta.VersionBeta, betaErr},
+		{meta.VersionAlpha, alphaErr},
+	} {
+		if u.available(ve.ver) != nil {
+			continue
+		}
+		if stable == "" {
+			stable = ve.ver
+		}
+		if ve.err != nil {
+			allOK = false
+			continue
+		}
+		ok = append(ok, ve.ver)
+	}
 	switch {
-	case gaErr == nil && alphaErr == nil && betaErr == nil:
-		return meta.VersionGA, nil
-	case gaErr != nil && alphaErr == nil && betaErr != nil:
-		return meta.VersionAlpha, nil
-	case gaErr != nil && alphaErr != nil && betaErr == nil:
-		return meta.VersionBeta, nil
+	case allOK && stable != "":
+		return stable, nil
+	case len(ok) == 1:
+		return ok[0], nil
 	default:
 		return meta.VersionGA, fmt.Errorf("indeterminant version (ga=%v, alpha=%v, beta=%v)", gaErr, alphaErr, betaErr)
 	}
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
-	var errs ConversionError
-	for _, cc := range []ConversionContext{AlphaToGAConversion, BetaToGAConversion} {
-		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
-				Context: cc,
-				Path:    mf.Path,
-				Value:   mf.Value,
-			})
-		}
+	if err := u.available(meta.VersionGA); err != nil {
+		return &u.ga, err
 	}
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionGA, false)}
 	if errs.hasErr() {
 		return &u.ga, &errs
 	}
@@ -307,16 +785,10 @@ func (u *mutableResource[GA, Alpha, Beta]) ToGA() (*GA, error) {
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error) {
-	var errs ConversionError
-	for _, cc := range []ConversionContext{GAToAlphaConversion, BetaToAlphaConversion} {
-		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
-				Context: cc,
-				Path:    mf.Path,
-				Value:   mf.Value,
-			})
-		}
+	if err := u.available(meta.VersionAlpha); err != nil {
+		return &u.alpha, err
 	}
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionAlpha, false)}
 	if errs.hasErr() {
 		return &u.alpha, &errs
 	}
@@ -324,22 +796,53 @@ func (u *mutableResource[GA, Alpha, Beta]) ToAlpha() (*Alpha, error) {
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) ToBeta() (*Beta, error) {
-	var errs ConversionError
-	for _, cc := range []ConversionContext{GAToBetaConversion, AlphaToBetaConversion} {
-		for _, mf := range u.errors[cc].missingFields {
-			errs.MissingFields = append(errs.MissingFields, MissingField{
-				Context: cc,
-				Path:    mf.Path,
-				Value:   mf.Value,
-			})
-		}
+	if err := u.available(meta.VersionBeta); err != nil {
+		return &u.beta, err
 	}
+	errs := ConversionError{MissingFields: u.missingFields(meta.VersionBeta, false)}
 	if errs.hasErr() {
 		return &u.beta, &errs
 	}
 	return &u.beta, nil
 }
 
+func (u *mutableResource[GA, Alpha, Beta]) ConversionWarnings(ver meta.Version) []MissingField {
+	return u.missingFields(ver, true)
+}
+
+// missingFields returns the fields that could not be converted to ver.
+// tolerated selects between the errors and the fields tolerated by
+// TolerateMissingFields().
+func (u *mutableResource[GA, Alpha, Beta]) missingFields(ver meta.Version, tolerated bool) []MissingField {
+	var ret []MissingField
+	for cc := ConversionContext(0); cc < conversionContextCount; cc++ {
+		if conversionVersions[cc][1] != ver {
+			continue
+		}
+		for _, mf := range u.errors[cc].missingFields {
+			if mf.Tolerated != tolerated {
+				continue
+			}
+			ret = append(ret, MissingField{
+				Context:  cc,
+				From:     conversionVersions[cc][0],
+				To:       conversionVersions[cc][1],
+				Path:     mf.Path,
+				Value:    mf.Value,
+				Severity: mf.Severity,
+			})
+		}
+	}
+	return ret
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) TolerateMissingFields(paths ...Path) {
+	u.tolerated = nil
+	for _, p := range paths {
+		u.tolerated = append(u.tolerated, append(Path{}, p...))
+	}
+}
+
 // TODO: Set semantics need to be reworked. The copy over to the other versions
 // should skip Access validation. Don't use this for the time being.
 
@@ -368,10 +871,28 @@ func (u *mutableResource[GA, Alpha, Beta]) SetBeta(src *Beta) error {
 }
 
 func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta], error) {
+	if u.typeTraitErr != nil {
+		return nil, fmt.Errorf("Freeze: %w", u.typeTraitErr)
+	}
 	ver, err := u.ImpliedVersion()
 	if err != nil {
 		return nil, err
 	}
+	return u.freeze(ver)
+}
+
+// freeze returns the Resource with version ver. It is an error if the
+// Required() fields of ver are not set.
+func (u *mutableResource[GA, Alpha, Beta]) freeze(ver meta.Version) (Resource[GA, Alpha, Beta], error) {
+	v, err := u.versionValue(ver)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRequired(u.typeTrait.FieldTraits(ver), v); err != nil {
+		reportValidationError(resourceKind(u.resourceID), ver, err)
+		return nil, fmt.Errorf("Freeze: %w", err)
+	}
+
 	// For the structures in the other versions, fill in
 	// zero-valued fields in the metafields. This ensures that if
 	// the resource can be diff'd and sync'd correctly in all
@@ -385,17 +906,17 @@ func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta],
 	//   results in a diff and update.
 	// - At this point, we need to set NullFields = ["Feature1"],
 	//   otherwise the update will ignore the field.
-	if ver != meta.VersionGA {
+	if ver != meta.VersionGA && u.available(meta.VersionGA) == nil {
 		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionGA), reflect.ValueOf(&u.ga)); err != nil {
 			return nil, err
 		}
 	}
-	if ver != meta.VersionAlpha {
+	if ver != meta.VersionAlpha && u.available(meta.VersionAlpha) == nil {
 		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionAlpha), reflect.ValueOf(&u.alpha)); err != nil {
 			return nil, err
 		}
 	}
-	if ver != meta.VersionBeta {
+	if ver != meta.VersionBeta && u.available(meta.VersionBeta) == nil {
 		if err := fillNullAndForceSend(u.typeTrait.FieldTraits(meta.VersionBeta), reflect.ValueOf(&u.beta)); err != nil {
 			return nil, err
 		}
@@ -403,3 +924,79 @@ func (u *mutableResource[GA, Alpha, Beta]) Freeze() (Resource[GA, Alpha, Beta],
 
 	return &resource[GA, Alpha, Beta]{x: u, ver: ver}, nil
 }
+
+func (u *mutableResource[GA, Alpha, Beta]) Clone() (MutableResource[GA, Alpha, Beta], error) {
+	return u.clone()
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) clone() (*mutableResource[GA, Alpha, Beta], error) {
+	ret := &mutableResource[GA, Alpha, Beta]{
+		copierOptions: append([]copierOption{}, u.copierOptions...),
+		typeTrait:     u.typeTrait,
+		typeTraitErr:  u.typeTraitErr,
+		strictVersion: u.strictVersion,
+	}
+	if u.versionPreference != nil {
+		ret.versionPreference = append([]meta.Version{}, u.versionPreference...)
+	}
+	for _, p := range u.tolerated {
+		ret.tolerated = append(ret.tolerated, append(Path{}, p...))
+	}
+	if err := newCopier().do(reflect.ValueOf(&ret.ga), reflect.ValueOf(&u.ga)); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	if err := newCopier().do(reflect.ValueOf(&ret.alpha), reflect.ValueOf(&u.alpha)); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	if err := newCopier().do(reflect.ValueOf(&ret.beta), reflect.ValueOf(&u.beta)); err != nil {
+		return nil, fmt.Errorf("Clone: %w", err)
+	}
+	if u.resourceID != nil {
+		id := *u.resourceID
+		if id.Key != nil {
+			key := *id.Key
+			id.Key = &key
+		}
+		ret.resourceID = &id
+	}
+	for cc := range u.errors {
+		for _, mf := range u.errors[cc].missingFields {
+			value, err := cloneValue(mf.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Clone: missing field %s: %w", mf.Path, err)
+			}
+			ret.errors[cc].missingFields = append(ret.errors[cc].missingFields, missingFieldOnCopy{
+				Path:      append(Path{}, mf.Path...),
+				Value:     value,
+				Tolerated: mf.Tolerated,
+				Severity:  mf.Severity,
+			})
+		}
+	}
+	if u.delta != nil {
+		ret.delta = &deltaRecorder{}
+		for _, p := range u.delta.paths {
+			ret.delta.paths = append(ret.delta.paths, append(Path{}, p...))
+		}
+	}
+	if u.setFields != nil {
+		ret.setFields = &deltaRecorder{}
+		for _, p := range u.setFields.paths {
+			ret.setFields.paths = append(ret.setFields.paths, append(Path{}, p...))
+		}
+	}
+	return ret, nil
+}
+
+// cloneValue returns a deep copy of x.
+func cloneValue(x any) (any, error) {
+	if x == nil {
+		return nil, nil
+	}
+	src := reflect.ValueOf(x)
+	dest := reflect.New(src.Type()).Elem()
+	if err := newCopier().doValues(Path{}, dest, src); err != nil {
+		return nil, err
+	}
+	return dest.Interface(), nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"reflect"
+)
+
+// NoVersion is used as the type parameter for an API version that does not
+// exist for the resource. For example, a resource that only exists in Alpha
+// and Beta:
+//
+//	res := NewResource[NoVersion, alpha.Foo, beta.Foo](id, nil)
+//
+// Conversions to a NoVersion version are skipped. To*() and Access*() return
+// an error matching ErrVersionNotAvailable for that version.
+type NoVersion struct{}
+
+// ErrVersionNotAvailable is returned (wrapped) when accessing a version of a
+// resource that has NoVersion as its type.
+var ErrVersionNotAvailable = errors.New("resource is not available in this version")
+
+var noVersionType = reflect.TypeOf(NoVersion{})
+
+// isNoVersion returns true if t (or the type pointed to by t) is NoVersion.
+func isNoVersion(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == noVersionType
+}
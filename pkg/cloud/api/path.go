@@ -19,6 +19,7 @@ package api
 import (
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -36,6 +37,10 @@ const (
 	pathSliceIndex = '!'
 	pathMapIndex   = ':'
 	pathPointer    = '*'
+
+	// pathAnyIndex is the index used for AnySliceIndex() and
+	// AnyMapIndex().
+	pathAnyIndex = "*"
 )
 
 // Field returns the path extended with a struct field reference.
@@ -48,6 +53,19 @@ func (p Path) Index(i int) Path {
 	return append(p, fmt.Sprintf("%c%d", pathSliceIndex, i))
 }
 
+// AnySliceIndex returns the path extended with a wildcard that matches all
+// elements of a slice. This is only valid in patterns (e.g.
+// FieldTraits.Reference()).
+func (p Path) AnySliceIndex() Path {
+	return append(p, string(pathSliceIndex)+pathAnyIndex)
+}
+
+// AnyMapIndex returns the path extended with a wildcard that matches all
+// values of a map. This is only valid in patterns (e.g. FieldTraits).
+func (p Path) AnyMapIndex() Path {
+	return append(p, string(pathMapIndex)+pathAnyIndex)
+}
+
 // MapIndex returns the path extended with a map index.
 func (p Path) MapIndex(k any) Path {
 	return append(p, fmt.Sprintf("%c%v", pathMapIndex, k))
@@ -71,6 +89,46 @@ func (p Path) Equal(other Path) bool {
 	return true
 }
 
+// Match returns true if the path matches the pattern. AnySliceIndex() and
+// AnyMapIndex() elements in the pattern match any slice index or map key
+// respectively.
+func (p Path) Match(pattern Path) bool {
+	if len(p) != len(pattern) {
+		return false
+	}
+	return p.MatchPrefix(pattern)
+}
+
+// MatchPrefix returns true if the pattern matches a prefix of this path. See
+// Match().
+func (p Path) MatchPrefix(pattern Path) bool {
+	if len(pattern) > len(p) {
+		return false
+	}
+	for i := range pattern {
+		if !matchElement(p[i], pattern[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchElement returns true if the path element x matches the pattern
+// element.
+func matchElement(x, pattern string) bool {
+	if x == pattern {
+		return true
+	}
+	if len(x) == 0 || pattern[1:] != pathAnyIndex {
+		return false
+	}
+	switch pattern[0] {
+	case pathSliceIndex, pathMapIndex:
+		return x[0] == pattern[0]
+	}
+	return false
+}
+
 // HasPrefix returns true if prefix is the prefix of this path.
 func (p Path) HasPrefix(prefix Path) bool {
 	if len(prefix) == 0 {
@@ -92,9 +150,145 @@ func (p Path) HasPrefix(prefix Path) bool {
 	return true
 }
 
-// String implements Stringer.
+// String implements Stringer. The string is the canonical form of the Path
+// and can be converted back with ParsePath(). Characters in map keys that
+// would be ambiguous are escaped with a backslash.
 func (p Path) String() string {
-	return strings.Join(p, "")
+	var sb strings.Builder
+	for _, x := range p {
+		if len(x) == 0 || x[0] != pathMapIndex || x[1:] == pathAnyIndex {
+			sb.WriteString(x)
+			continue
+		}
+		sb.WriteByte(pathMapIndex)
+		for _, r := range x[1:] {
+			if isPathSpecial(r) {
+				sb.WriteByte('\\')
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// isPathSpecial returns true if r starts a new element or is an escape in
+// the string form of a Path.
+func isPathSpecial(r rune) bool {
+	switch r {
+	case pathField, pathSliceIndex, pathMapIndex, pathPointer, '[', ']', '\\':
+		return true
+	}
+	return false
+}
+
+// ParsePath parses the string form of a Path (see String()). For
+// convenience, the following are also accepted:
+//
+//   - "[N]" for a slice index (same as "!N"). "[*]" is AnySliceIndex().
+//   - A field name without the leading "." at the beginning of the string.
+//
+// Example: ParsePath("*.Backends[0].Group") is equivalent to
+// Path{}.Pointer().Field("Backends").Index(0).Field("Group").
+func ParsePath(s string) (Path, error) {
+	ret := Path{}
+	rs := []rune(s)
+
+	// token returns the element value starting at rs[i], handling escapes
+	// if escapes is true, and the index of the next element.
+	token := func(i int, escapes bool) (string, int, error) {
+		var sb strings.Builder
+		for ; i < len(rs); i++ {
+			r := rs[i]
+			if escapes && r == '\\' {
+				if i+1 == len(rs) {
+					return "", 0, fmt.Errorf("ParsePath(%q): trailing escape", s)
+				}
+				i++
+				sb.WriteRune(rs[i])
+				continue
+			}
+			if isPathSpecial(r) {
+				break
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String(), i, nil
+	}
+	parseIndex := func(x string) (string, error) {
+		if x == pathAnyIndex {
+			return string(pathSliceIndex) + pathAnyIndex, nil
+		}
+		n, err := strconv.Atoi(x)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("ParsePath(%q): invalid slice index %q", s, x)
+		}
+		return fmt.Sprintf("%c%d", pathSliceIndex, n), nil
+	}
+
+	for i := 0; i < len(rs); {
+		switch r := rs[i]; {
+		case r == pathPointer:
+			ret = append(ret, string(pathPointer))
+			i++
+		case r == pathField || (i == 0 && !isPathSpecial(r)):
+			if r == pathField {
+				i++
+			}
+			name, next, err := token(i, false)
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				return nil, fmt.Errorf("ParsePath(%q): empty field name at offset %d", s, i)
+			}
+			ret = append(ret, string(pathField)+name)
+			i = next
+		case r == pathSliceIndex:
+			x, next, err := token(i+1, false)
+			if err != nil {
+				return nil, err
+			}
+			if x == "" && next < len(rs) && rs[next] == pathPointer {
+				x, next = pathAnyIndex, next+1
+			}
+			elem, err := parseIndex(x)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, elem)
+			i = next
+		case r == '[':
+			end := i + 1
+			for end < len(rs) && rs[end] != ']' {
+				end++
+			}
+			if end == len(rs) {
+				return nil, fmt.Errorf("ParsePath(%q): unterminated \"[\"", s)
+			}
+			elem, err := parseIndex(string(rs[i+1 : end]))
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, elem)
+			i = end + 1
+		case r == pathMapIndex:
+			// ":*" is AnyMapIndex(). A literal "*" key must be escaped.
+			if i+1 < len(rs) && rs[i+1] == pathPointer {
+				ret = append(ret, string(pathMapIndex)+pathAnyIndex)
+				i += 2
+				continue
+			}
+			k, next, err := token(i+1, true)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, string(pathMapIndex)+k)
+			i = next
+		default:
+			return nil, fmt.Errorf("ParsePath(%q): unexpected %q at offset %d", s, r, i)
+		}
+	}
+	return ret, nil
 }
 
 // ResolveType will attempt to traverse the type with the Path and return the
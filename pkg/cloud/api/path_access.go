@@ -0,0 +1,208 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// versionValue returns a pointer to the object for ver.
+func (u *mutableResource[GA, Alpha, Beta]) versionValue(ver meta.Version) (reflect.Value, error) {
+	if err := u.available(ver); err != nil {
+		return reflect.Value{}, err
+	}
+	switch ver {
+	case meta.VersionGA:
+		return reflect.ValueOf(&u.ga), nil
+	case meta.VersionAlpha:
+		return reflect.ValueOf(&u.alpha), nil
+	default:
+		return reflect.ValueOf(&u.beta), nil
+	}
+}
+
+// GetByPath implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) GetByPath(ver meta.Version, p Path) (any, error) {
+	v, err := u.versionValue(ver)
+	if err != nil {
+		return nil, fmt.Errorf("GetByPath: %w", err)
+	}
+	ret, err := getPath(v, p)
+	if err != nil {
+		return nil, fmt.Errorf("GetByPath: %w", err)
+	}
+	return ret, nil
+}
+
+// SetByPath implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) SetByPath(ver meta.Version, p Path, value any) error {
+	v, err := u.versionValue(ver)
+	if err != nil {
+		return fmt.Errorf("SetByPath: %w", err)
+	}
+	vv, err := pathValue(v.Type(), p, value)
+	if err != nil {
+		return fmt.Errorf("SetByPath: %w", err)
+	}
+
+	var setErr error
+	set := func(x reflect.Value) { setErr = setPath(x, p, vv) }
+	switch ver {
+	case meta.VersionGA:
+		err = u.Access(func(x *GA) { set(reflect.ValueOf(x)) })
+	case meta.VersionAlpha:
+		err = u.AccessAlpha(func(x *Alpha) { set(reflect.ValueOf(x)) })
+	case meta.VersionBeta:
+		err = u.AccessBeta(func(x *Beta) { set(reflect.ValueOf(x)) })
+	}
+	if setErr != nil {
+		return fmt.Errorf("SetByPath: %w", setErr)
+	}
+	if err != nil {
+		return err
+	}
+	// The value may not have changed, which is not detected by Access().
+	if u.setFields != nil {
+		u.setFields.add(p)
+	}
+	return nil
+}
+
+// pathValue returns value as a reflect.Value that can be assigned to the
+// field at p in type t. nil is converted to the zero value for pointers,
+// slices and maps.
+func pathValue(t reflect.Type, p Path, value any) (reflect.Value, error) {
+	ft, err := p.ResolveType(t)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	vv := reflect.ValueOf(value)
+	switch {
+	case value == nil:
+		switch ft.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			vv = reflect.Zero(ft)
+		default:
+			return reflect.Value{}, fmt.Errorf("cannot set %s (type %s) to nil", p, ft)
+		}
+	case !vv.Type().AssignableTo(ft):
+		return reflect.Value{}, fmt.Errorf("cannot set %s (type %s) to a value of type %s", p, ft, vv.Type())
+	}
+	return vv, nil
+}
+
+// getPath returns the value at p in v. The zero value of the field is
+// returned if p traverses a nil pointer or a missing map key.
+func getPath(v reflect.Value, p Path) (any, error) {
+	ft, err := p.ResolveType(v.Type())
+	if err != nil {
+		return nil, err
+	}
+	for i, x := range p {
+		switch x[0] {
+		case pathField:
+			v = v.FieldByName(x[1:])
+		case pathPointer:
+			if v.IsNil() {
+				return reflect.Zero(ft).Interface(), nil
+			}
+			v = v.Elem()
+		case pathSliceIndex:
+			idx, err := sliceIndex(p, i, v)
+			if err != nil {
+				return nil, err
+			}
+			v = v.Index(idx)
+		case pathMapIndex:
+			k, ok := mapKey(v, x[1:])
+			if !ok {
+				return reflect.Zero(ft).Interface(), nil
+			}
+			v = v.MapIndex(k)
+		}
+	}
+	return v.Interface(), nil
+}
+
+// setPath sets the field at p in v to value, allocating nil pointers (and a
+// nil map for a map element) along the way. Map elements are not
+// addressable so map keys may only appear as the last element of p.
+func setPath(v reflect.Value, p Path, value reflect.Value) error {
+	for i, x := range p {
+		switch x[0] {
+		case pathField:
+			v = v.FieldByName(x[1:])
+		case pathPointer:
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		case pathSliceIndex:
+			idx, err := sliceIndex(p, i, v)
+			if err != nil {
+				return err
+			}
+			v = v.Index(idx)
+		case pathMapIndex:
+			if i != len(p)-1 {
+				return fmt.Errorf("at %s element %d, map values can only be set as the last element of the path", p, i)
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMap(v.Type()))
+			}
+			k, ok := mapKey(v, x[1:])
+			if !ok {
+				if v.Type().Key().Kind() != reflect.String {
+					return fmt.Errorf("at %s element %d, cannot add key to a map with %s keys", p, i, v.Type().Key())
+				}
+				k = reflect.ValueOf(x[1:]).Convert(v.Type().Key())
+			}
+			v.SetMapIndex(k, value)
+			return nil
+		}
+	}
+	v.Set(value)
+	return nil
+}
+
+// sliceIndex parses the slice index p[i] and checks that it is in range for
+// the slice v.
+func sliceIndex(p Path, i int, v reflect.Value) (int, error) {
+	idx, err := strconv.Atoi(p[i][1:])
+	if err != nil {
+		return 0, fmt.Errorf("at %s element %d, invalid slice index %q", p, i, p[i][1:])
+	}
+	if idx < 0 || idx >= v.Len() {
+		return 0, fmt.Errorf("at %s element %d, index %d out of range (len %d)", p, i, idx, v.Len())
+	}
+	return idx, nil
+}
+
+// mapKey finds the key in the map v that is printed as s (see
+// Path.MapIndex()).
+func mapKey(v reflect.Value, s string) (reflect.Value, bool) {
+	for _, k := range v.MapKeys() {
+		if fmt.Sprint(k.Interface()) == s {
+			return k, true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetSetByPath(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		StP             *inner
+		LStr            []string
+		M               map[string]string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+
+	for _, tc := range []struct {
+		p     Path
+		value any
+	}{
+		{p: Path{}.Pointer().Field("I"), value: 10},
+		{p: Path{}.Pointer().Field("StP").Pointer().Field("I"), value: 20},
+		{p: Path{}.Pointer().Field("LStr"), value: []string{"a", "b"}},
+		{p: Path{}.Pointer().Field("LStr").Index(1), value: "c"},
+		{p: Path{}.Pointer().Field("M").MapIndex("k"), value: "v"},
+	} {
+		if err := res.SetByPath(meta.VersionBeta, tc.p, tc.value); err != nil {
+			t.Fatalf("SetByPath(%v, %v) = %v, want nil", tc.p, tc.value, err)
+		}
+	}
+	want := &st{
+		Name: "obj-1",
+		I:    10,
+		StP:  &inner{I: 20},
+		LStr: []string{"a", "c"},
+		M:    map[string]string{"k": "v"},
+	}
+	// SetByPath() converts to the other versions like Access*().
+	got, err := res.ToGA()
+	if err != nil {
+		t.Fatalf("ToGA() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+
+	for _, tc := range []struct {
+		p    Path
+		want any
+	}{
+		{p: Path{}.Pointer().Field("I"), want: 10},
+		{p: Path{}.Pointer().Field("StP").Pointer().Field("I"), want: 20},
+		{p: Path{}.Pointer().Field("LStr").Index(0), want: "a"},
+		{p: Path{}.Pointer().Field("M").MapIndex("k"), want: "v"},
+		{p: Path{}.Pointer().Field("M").MapIndex("missing"), want: ""},
+	} {
+		got, err := res.GetByPath(meta.VersionGA, tc.p)
+		if err != nil || got != tc.want {
+			t.Errorf("GetByPath(%v) = %v, %v; want %v, nil", tc.p, got, err, tc.want)
+		}
+	}
+
+	if err := res.SetByPath(meta.VersionGA, Path{}.Pointer().Field("StP"), nil); err != nil {
+		t.Fatalf("SetByPath(StP, nil) = %v, want nil", err)
+	}
+	if got, err := res.GetByPath(meta.VersionGA, Path{}.Pointer().Field("StP").Pointer().Field("I")); err != nil || got != 0 {
+		t.Errorf("GetByPath(StP.I) = %v, %v; want 0, nil", got, err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		p     Path
+		value any
+	}{
+		{name: "no such field", p: Path{}.Pointer().Field("Missing"), value: 1},
+		{name: "wrong type", p: Path{}.Pointer().Field("I"), value: "abc"},
+		{name: "nil for int", p: Path{}.Pointer().Field("I"), value: nil},
+		{name: "out of range", p: Path{}.Pointer().Field("LStr").Index(5), value: "x"},
+	} {
+		if err := res.SetByPath(meta.VersionGA, tc.p, tc.value); err == nil {
+			t.Errorf("%s: SetByPath(%v, %v) = nil, want error", tc.name, tc.p, tc.value)
+		}
+	}
+	if _, err := res.GetByPath(meta.VersionGA, Path{}.Pointer().Field("LStr").Index(5)); err == nil {
+		t.Errorf("GetByPath(LStr!5) = _, nil; want error")
+	}
+}
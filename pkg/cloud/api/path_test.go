@@ -45,6 +45,63 @@ func TestPath(t *testing.T) {
 	}
 }
 
+func TestParsePath(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		s       string
+		want    Path
+		wantStr string
+		wantErr bool
+	}{
+		{s: "", want: Path{}},
+		{s: "*", want: Path{}.Pointer()},
+		{s: "*.Backends!0.Group", want: Path{}.Pointer().Field("Backends").Index(0).Field("Group")},
+		{s: "*.Backends[0].Group", want: Path{}.Pointer().Field("Backends").Index(0).Field("Group"), wantStr: "*.Backends!0.Group"},
+		{s: "spec.backends[12].group", want: Path{}.Field("spec").Field("backends").Index(12).Field("group"), wantStr: ".spec.backends!12.group"},
+		{s: ".abc!5*.def:key1", want: Path{}.Field("abc").Index(5).Pointer().Field("def").MapIndex("key1")},
+		{s: "*.Rules!*.Mode", want: Path{}.Pointer().Field("Rules").AnySliceIndex().Field("Mode")},
+		{s: "*.Rules[*].Mode", want: Path{}.Pointer().Field("Rules").AnySliceIndex().Field("Mode"), wantStr: "*.Rules!*.Mode"},
+		{s: "*.M:**.F", want: Path{}.Pointer().Field("M").AnyMapIndex().Pointer().Field("F")},
+		{s: "*.M:k*.F", want: Path{}.Pointer().Field("M").MapIndex("k").Pointer().Field("F")},
+		{s: `*.Labels:example.com/x`, want: Path{}.Pointer().Field("Labels").MapIndex("example").Field("com/x")},
+		{s: `*.Labels:example\.com/x`, want: Path{}.Pointer().Field("Labels").MapIndex("example.com/x")},
+		{s: `*.M:a\:b\\c\[d\*`, want: Path{}.Pointer().Field("M").MapIndex(`a:b\c[d*`)},
+		{s: "*.M:", want: Path{}.Pointer().Field("M").MapIndex("")},
+		{s: "*.", wantErr: true},
+		{s: "*.A!x", wantErr: true},
+		{s: "*.A!-1", wantErr: true},
+		{s: "*.A[0", wantErr: true},
+		{s: "*.A[]", wantErr: true},
+		{s: `*.M:x\`, wantErr: true},
+		{s: "]", wantErr: true},
+	} {
+		got, err := ParsePath(tc.s)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("ParsePath(%q) = %v; gotErr = %t, want %t", tc.s, err, gotErr, tc.wantErr)
+			continue
+		}
+		if tc.wantErr {
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParsePath(%q) = %v, want %v", tc.s, []string(got), []string(tc.want))
+		}
+		wantStr := tc.wantStr
+		if wantStr == "" {
+			wantStr = tc.s
+		}
+		if got.String() != wantStr {
+			t.Errorf("ParsePath(%q).String() = %q, want %q", tc.s, got.String(), wantStr)
+		}
+		// The canonical form must round trip.
+		rt, err := ParsePath(got.String())
+		if err != nil || !rt.Equal(got) {
+			t.Errorf("ParsePath(%q) = %v, %v; want %v, nil", got.String(), []string(rt), err, []string(got))
+		}
+	}
+}
+
 func TestPathEqual(t *testing.T) {
 	t.Parallel()
 
@@ -96,6 +153,37 @@ func TestPathHasPrefix(t *testing.T) {
 	}
 }
 
+func TestPathMatch(t *testing.T) {
+	t.Parallel()
+
+	base := Path{}.Pointer().Field("L")
+	for _, tc := range []struct {
+		p, pattern       Path
+		want, wantPrefix bool
+	}{
+		{p: base, pattern: base, want: true, wantPrefix: true},
+		{p: base.Index(3), pattern: base.AnySliceIndex(), want: true, wantPrefix: true},
+		{p: base.Index(3).Field("A"), pattern: base.AnySliceIndex(), wantPrefix: true},
+		{p: base.Index(3).Field("A"), pattern: base.AnySliceIndex().Field("A"), want: true, wantPrefix: true},
+		{p: base.Index(3).Field("A"), pattern: base.AnySliceIndex().Field("B")},
+		{p: base.Index(3), pattern: base.Index(3), want: true, wantPrefix: true},
+		{p: base.Index(3), pattern: base.Index(4)},
+		{p: base.MapIndex("k"), pattern: base.AnyMapIndex(), want: true, wantPrefix: true},
+		{p: base.MapIndex("k").Field("A"), pattern: base.AnyMapIndex(), wantPrefix: true},
+		{p: base.MapIndex("k"), pattern: base.AnySliceIndex()},
+		{p: base.Index(0), pattern: base.AnyMapIndex()},
+		{p: base, pattern: base.AnySliceIndex()},
+		{p: base.Field("A"), pattern: base.AnySliceIndex()},
+	} {
+		if got := tc.p.Match(tc.pattern); got != tc.want {
+			t.Errorf("%q.Match(%q) = %t, want %t", tc.p, tc.pattern, got, tc.want)
+		}
+		if got := tc.p.MatchPrefix(tc.pattern); got != tc.wantPrefix {
+			t.Errorf("%q.MatchPrefix(%q) = %t, want %t", tc.p, tc.pattern, got, tc.wantPrefix)
+		}
+	}
+}
+
 func TestResolveType(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Reference is the value of a field declared with FieldTraits.Reference().
+type Reference struct {
+	// Path of the field. AnySliceIndex() and AnyMapIndex() elements in the
+	// trait are replaced with the index or key of the element.
+	Path Path
+	// Resource type that is referenced (e.g. "healthChecks").
+	Resource string
+	// URL of the referenced resource.
+	URL string
+}
+
+// findReferences returns the non-empty references in v. Reference traits that
+// refer to fields that do not exist in the type of v are skipped, as the
+// traits may be shared between versions.
+func findReferences(traits *FieldTraits, v reflect.Value) ([]Reference, error) {
+	var ret []Reference
+	for _, r := range traits.references {
+		err := walkPattern(r.path, Path{}, v, func(p Path, url string) {
+			ret = append(ret, Reference{Path: p, Resource: r.resource, URL: url})
+		})
+		if err != nil {
+			return nil, fmt.Errorf("findReferences: %w", err)
+		}
+	}
+	return ret, nil
+}
+
+// walkPattern traverses v with the pattern, calling fn with the concrete path
+// and value for each non-empty string matched.
+func walkPattern(pattern, p Path, v reflect.Value, fn func(Path, string)) error {
+	if len(pattern) == 0 {
+		if v.Kind() != reflect.String {
+			return fmt.Errorf("at %s, expected string, got %s", p, v.Type())
+		}
+		if v.String() != "" {
+			fn(append(Path{}, p...), v.String())
+		}
+		return nil
+	}
+	x := pattern[0]
+	switch x[0] {
+	case pathField:
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("at %s, expected struct, got %s", p, v.Type())
+		}
+		fv := v.FieldByName(x[1:])
+		if !fv.IsValid() {
+			return nil
+		}
+		return walkPattern(pattern[1:], p.Field(x[1:]), fv, fn)
+	case pathPointer:
+		if v.Kind() != reflect.Pointer {
+			return fmt.Errorf("at %s, expected pointer, got %s", p, v.Type())
+		}
+		if v.IsNil() {
+			return nil
+		}
+		return walkPattern(pattern[1:], p.Pointer(), v.Elem(), fn)
+	case pathSliceIndex:
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("at %s, expected slice, got %s", p, v.Type())
+		}
+		if x[1:] != pathAnyIndex {
+			i, err := strconv.Atoi(x[1:])
+			if err != nil {
+				return fmt.Errorf("at %s, invalid slice index %q", p, x)
+			}
+			if i >= v.Len() {
+				return nil
+			}
+			return walkPattern(pattern[1:], p.Index(i), v.Index(i), fn)
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := walkPattern(pattern[1:], p.Index(i), v.Index(i), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	case pathMapIndex:
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("at %s, expected map, got %s", p, v.Type())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		for _, k := range keys {
+			kp := p.MapIndex(k.Interface())
+			if !matchElement(kp[len(kp)-1], x) {
+				continue
+			}
+			if err := walkPattern(pattern[1:], kp, v.MapIndex(k), fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("at %s, unsupported path element %q", p, x)
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindReferences(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		Ref string
+	}
+	type st struct {
+		Ref   string
+		LRef  []string
+		LStP  []*sti
+		StP   *sti
+		MRef  map[string]string
+		Empty string
+	}
+
+	traits := &FieldTraits{}
+	traits.Reference(Path{}.Pointer().Field("Ref"), "a")
+	traits.Reference(Path{}.Pointer().Field("LRef").AnySliceIndex(), "b")
+	traits.Reference(Path{}.Pointer().Field("LStP").AnySliceIndex().Pointer().Field("Ref"), "c")
+	traits.Reference(Path{}.Pointer().Field("StP").Pointer().Field("Ref"), "d")
+	traits.Reference(Path{}.Pointer().Field("Empty"), "e")
+	traits.Reference(Path{}.Pointer().Field("MRef").AnyMapIndex(), "g")
+	// Field does not exist in this version.
+	traits.Reference(Path{}.Pointer().Field("Missing"), "f")
+
+	if err := traits.CheckSchema(reflect.TypeOf(&st{})); err == nil {
+		t.Errorf("CheckSchema() = nil, want error (Missing field)")
+	}
+
+	obj := &st{
+		Ref:  "url-a",
+		LRef: []string{"url-b0", "", "url-b2"},
+		LStP: []*sti{nil, {Ref: "url-c1"}},
+		MRef: map[string]string{"y": "url-gy", "x": "url-gx"},
+	}
+	got, err := findReferences(traits, reflect.ValueOf(obj))
+	if err != nil {
+		t.Fatalf("findReferences() = %v, want nil", err)
+	}
+	want := []Reference{
+		{Path: Path{}.Pointer().Field("Ref"), Resource: "a", URL: "url-a"},
+		{Path: Path{}.Pointer().Field("LRef").Index(0), Resource: "b", URL: "url-b0"},
+		{Path: Path{}.Pointer().Field("LRef").Index(2), Resource: "b", URL: "url-b2"},
+		{Path: Path{}.Pointer().Field("LStP").Index(1).Pointer().Field("Ref"), Resource: "c", URL: "url-c1"},
+		{Path: Path{}.Pointer().Field("MRef").MapIndex("x"), Resource: "g", URL: "url-gx"},
+		{Path: Path{}.Pointer().Field("MRef").MapIndex("y"), Resource: "g", URL: "url-gy"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("findReferences(); -got,+want: %s", diff)
+	}
+
+	badTraits := &FieldTraits{}
+	badTraits.Reference(Path{}.Pointer().Field("LRef"), "b")
+	if _, err := findReferences(badTraits, reflect.ValueOf(obj)); err == nil {
+		t.Errorf("findReferences() = _, nil; want error (not a string)")
+	}
+	if err := badTraits.CheckSchema(reflect.TypeOf(&st{})); err == nil {
+		t.Errorf("CheckSchema() = nil, want error (not a string)")
+	}
+}
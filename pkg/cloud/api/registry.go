@@ -0,0 +1,99 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// typeTraitKey identifies a kind of resource in the TypeTrait registry.
+type typeTraitKey struct {
+	apiGroup meta.APIGroup
+	resource string
+}
+
+func newTypeTraitKey(apiGroup meta.APIGroup, resource string) typeTraitKey {
+	// An unspecified API group is "compute" (see meta.APIGroup).
+	if apiGroup == "" {
+		apiGroup = meta.APIGroupCompute
+	}
+	return typeTraitKey{apiGroup: apiGroup, resource: resource}
+}
+
+var typeTraitRegistry = struct {
+	lock   sync.RWMutex
+	traits map[typeTraitKey]any
+}{
+	traits: map[typeTraitKey]any{},
+}
+
+// RegisterTypeTrait registers tt as the TypeTrait for resources of the given
+// kind (e.g. meta.APIGroupCompute, "backendServices"). The registered trait is
+// used by NewResource() when a nil TypeTrait is passed. This should be called
+// from init(). It panics if a TypeTrait is already registered for the kind.
+func RegisterTypeTrait[GA any, Alpha any, Beta any](apiGroup meta.APIGroup, resource string, tt TypeTrait[GA, Alpha, Beta]) {
+	key := newTypeTraitKey(apiGroup, resource)
+
+	typeTraitRegistry.lock.Lock()
+	defer typeTraitRegistry.lock.Unlock()
+
+	if _, ok := typeTraitRegistry.traits[key]; ok {
+		panic(fmt.Sprintf("RegisterTypeTrait: duplicate registration for %s/%s", key.apiGroup, key.resource))
+	}
+	typeTraitRegistry.traits[key] = tt
+}
+
+// LookupTypeTrait returns the TypeTrait registered for the given kind of
+// resource. Returns false if there is no TypeTrait registered for the kind.
+// It is an error if the registered TypeTrait is for different types.
+func LookupTypeTrait[GA any, Alpha any, Beta any](apiGroup meta.APIGroup, resource string) (TypeTrait[GA, Alpha, Beta], bool, error) {
+	key := newTypeTraitKey(apiGroup, resource)
+
+	typeTraitRegistry.lock.RLock()
+	x, ok := typeTraitRegistry.traits[key]
+	typeTraitRegistry.lock.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+	tt, ok := x.(TypeTrait[GA, Alpha, Beta])
+	if !ok {
+		return nil, false, fmt.Errorf("TypeTrait registered for %s/%s is %T, not a TypeTrait[%T, %T, %T]", key.apiGroup, key.resource, x, *new(GA), *new(Alpha), *new(Beta))
+	}
+	return tt, true, nil
+}
+
+// defaultTypeTrait returns the registered TypeTrait for the resource,
+// falling back to BaseTypeTrait if none is registered. It is an error if the
+// registered TypeTrait is for different types; BaseTypeTrait is returned
+// with the error.
+func defaultTypeTrait[GA any, Alpha any, Beta any](resourceID *cloud.ResourceID) (TypeTrait[GA, Alpha, Beta], error) {
+	if resourceID != nil {
+		tt, ok, err := LookupTypeTrait[GA, Alpha, Beta](resourceID.APIGroup, resourceID.Resource)
+		if err != nil {
+			return &BaseTypeTrait[GA, Alpha, Beta]{}, err
+		}
+		if ok {
+			return tt, nil
+		}
+	}
+	return &BaseTypeTrait[GA, Alpha, Beta]{}, nil
+}
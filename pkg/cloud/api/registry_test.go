@@ -0,0 +1,112 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestTypeTraitRegistry(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	tt := &testTrait[st, st, st]{}
+	RegisterTypeTrait[st, st, st](meta.APIGroupCompute, "registryTest", tt)
+
+	// The empty APIGroup is the same as compute.
+	got, ok, err := LookupTypeTrait[st, st, st]("", "registryTest")
+	if err != nil || !ok || got != TypeTrait[st, st, st](tt) {
+		t.Errorf("LookupTypeTrait() = %v, %t, %v; want %v, true, nil", got, ok, err, tt)
+	}
+	if _, ok, err := LookupTypeTrait[st, st, st](meta.APIGroupNetworkServices, "registryTest"); ok || err != nil {
+		t.Errorf("LookupTypeTrait(networkservices) = _, %t, %v; want false, nil", ok, err)
+	}
+	if _, _, err := LookupTypeTrait[int, int, int](meta.APIGroupCompute, "registryTest"); err == nil {
+		t.Error("LookupTypeTrait() with the wrong types = _, _, nil; want error")
+	}
+
+	// NewResource() with a nil TypeTrait uses the registered TypeTrait.
+	res := NewResource[st, st, st](&cloud.ResourceID{Resource: "registryTest", Key: meta.GlobalKey("x")}, nil)
+	if res.typeTrait != TypeTrait[st, st, st](tt) {
+		t.Errorf("NewResource().typeTrait = %T, want the registered TypeTrait", res.typeTrait)
+	}
+	// The testTrait allows zero values for I.
+	if err := res.Access(func(x *st) { x.I = 0 }); err != nil {
+		t.Errorf("Access() = %v, want nil", err)
+	}
+	res = NewResource[st, st, st](&cloud.ResourceID{Resource: "unregistered", Key: meta.GlobalKey("x")}, nil)
+	if _, ok := res.typeTrait.(*BaseTypeTrait[st, st, st]); !ok {
+		t.Errorf("NewResource().typeTrait = %T, want BaseTypeTrait", res.typeTrait)
+	}
+
+	// A registered TypeTrait for different types is an error.
+	type st2 struct {
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	other := NewResource[st2, st2, st2](&cloud.ResourceID{Resource: "registryTest", Key: meta.GlobalKey("x")}, nil)
+	if _, err := other.Freeze(); err == nil {
+		t.Error("Freeze() with a mismatched registered TypeTrait = _, nil; want error")
+	}
+	// The error is kept by copies of the resource.
+	clone, err := other.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v", err)
+	}
+	if _, err := clone.Freeze(); err == nil {
+		t.Error("Clone().Freeze() with a mismatched registered TypeTrait = _, nil; want error")
+	}
+	snap, err := other.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() = %v", err)
+	}
+	restored := NewResource[st2, st2, st2](&cloud.ResourceID{Resource: "unregistered", Key: meta.GlobalKey("x")}, nil)
+	if err := restored.Restore(snap); err != nil {
+		t.Fatalf("Restore() = %v", err)
+	}
+	if _, err := restored.Freeze(); err == nil {
+		t.Error("Restore().Freeze() with a mismatched registered TypeTrait = _, nil; want error")
+	}
+	b, err := json.Marshal(other)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	if _, err := UnmarshalResource[st2, st2, st2](b, nil); err == nil {
+		t.Error("UnmarshalResource() with a mismatched registered TypeTrait = _, nil; want error")
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("RegisterTypeTrait() duplicate did not panic")
+			}
+		}()
+		RegisterTypeTrait[st, st, st](meta.APIGroupCompute, "registryTest", tt)
+	}()
+}
@@ -18,6 +18,7 @@ package api
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -25,6 +26,9 @@ import (
 
 // Resource is read-only view into the resource. A Resource
 // has a definitive Version.
+//
+// Resources can be serialized with json.Marshal() and restored with
+// UnmarshalResource().
 type Resource[GA any, Alpha any, Beta any] interface {
 	// Version of the resource. This cannot be indeterminant.
 	Version() meta.Version
@@ -40,10 +44,57 @@ type Resource[GA any, Alpha any, Beta any] interface {
 	// other, taking into account the versions of the resources
 	// being compared. Cross Alpha and Beta comparisons are not
 	// currently supported.
+	//
+	// DiffResult.NeedsRecreate() reports if any of the differences
+	// are in fields declared with FieldTraits.Immutable().
 	Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error)
 
-	// Clone returns an exact structural copy of this resource.
-	// Clone() Resource[GA, Alpha, Beta] XXX
+	// ConversionWarnings returns the fields that could not be represented
+	// in the Version of the resource that were tolerated. See
+	// MutableResource.TolerateMissingFields().
+	ConversionWarnings() []MissingField
+	// ConversionReport returns the fields that would be lost converting
+	// the resource to each version. This can be used to tell the user
+	// which API version a configuration requires before making any
+	// calls. See MutableResource.ConversionReport().
+	ConversionReport() *ConversionReport
+
+	// GetByPath returns the value of the field at p in version ver. See
+	// MutableResource.GetByPath().
+	GetByPath(ver meta.Version, p Path) (any, error)
+
+	// SetFields returns the fields that were explicitly set by the user.
+	// See MutableResource.SetFields().
+	SetFields() []Path
+
+	// References returns the values of the fields declared with
+	// FieldTraits.Reference() for the Version of the resource.
+	References() ([]Reference, error)
+
+	// CheckRequired returns a *ValidationError listing the fields declared
+	// with FieldTraits.Required() that are not set in the Version of the
+	// resource. Freeze() does this check; resources restored with
+	// UnmarshalResource() are not checked.
+	CheckRequired() error
+
+	// Hash returns a stable digest of the fields that are set in the
+	// resource, excluding OutputOnly and System fields. The hash can be
+	// stored (e.g. in an annotation) to detect if the resource has
+	// changed. Resources with the same fields set have the same hash,
+	// regardless of their Version and of the order of map entries and
+	// KeyedSlice() elements.
+	Hash() (string, error)
+
+	// DebugString returns a human readable dump of the resource. See
+	// MutableResource.DebugString().
+	DebugString() string
+	// DebugJSON is a compact JSON version of DebugString().
+	DebugJSON() ([]byte, error)
+
+	// Clone returns an independent deep copy of this resource,
+	// including the conversion errors. The TypeTrait is shared with
+	// the copy.
+	Clone() (Resource[GA, Alpha, Beta], error)
 }
 
 type resource[GA any, Alpha any, Beta any] struct {
@@ -60,6 +111,11 @@ func (obj *resource[GA, Alpha, Beta]) ToBeta() (*Beta, error)        { return ob
 
 // Diff implements Resource.
 func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*DiffResult, error) {
+	return obj.diff(other, diffOptions{})
+}
+
+// diff obj and other with the given options.
+func (obj *resource[GA, Alpha, Beta]) diff(other Resource[GA, Alpha, Beta], opts diffOptions) (*DiffResult, error) {
 	switch {
 	// Comparisons between the same versions don't need conversions.
 	//
@@ -67,17 +123,17 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	case obj.Version() == meta.VersionGA && other.Version() == meta.VersionGA:
 		aObj, _ := obj.ToGA()
 		bObj, _ := other.ToGA()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionGA))
+		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionGA), opts)
 	// cmp(Alpha, Alpha)
 	case obj.Version() == meta.VersionAlpha && other.Version() == meta.VersionAlpha:
 		aObj, _ := obj.ToAlpha()
 		bObj, _ := other.ToAlpha()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha))
+		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha), opts)
 	// cmp(Beta, Beta)
 	case obj.Version() == meta.VersionBeta && other.Version() == meta.VersionBeta:
 		aObj, _ := obj.ToBeta()
 		bObj, _ := other.ToBeta()
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta))
+		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta), opts)
 
 	// GA => Alpha, GA => Beta should be safe and supported with a conversion.
 	//
@@ -93,7 +149,7 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 		if err != nil {
 			return nil, fmt.Errorf("Resource.Diff: %s", err)
 		}
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha))
+		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionAlpha), opts)
 	// cmp(GA, Beta), cmp(Beta, GA): convert to Beta, then compare.
 	case obj.Version() == meta.VersionGA && other.Version() == meta.VersionBeta:
 		fallthrough
@@ -106,7 +162,7 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 		if err != nil {
 			return nil, fmt.Errorf("Resource.Diff: %s", err)
 		}
-		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta))
+		return diff(aObj, bObj, obj.x.typeTrait.FieldTraits(meta.VersionBeta), opts)
 
 	// Comparison between Alpha/Beta is not supported right now. This probably
 	// can work with some manual conversion logic.
@@ -119,11 +175,71 @@ func (obj *resource[GA, Alpha, Beta]) Diff(other Resource[GA, Alpha, Beta]) (*Di
 	return nil, fmt.Errorf("invalid versions (got a.Version=%s, b.Version=%s)", obj.Version(), other.Version())
 }
 
-/*
-func (obj *Resource[GA, Alpha, Beta]) Clone() Resource[GA, Alpha, Beta] {
-	return &Resource[GA, Alpha, Beta]{
-		x:   obj.Clone(),
-		ver: obj.ver,
+// References implements Resource.
+func (obj *resource[GA, Alpha, Beta]) References() ([]Reference, error) {
+	var v reflect.Value
+	switch obj.ver {
+	case meta.VersionGA:
+		v = reflect.ValueOf(&obj.x.ga)
+	case meta.VersionAlpha:
+		v = reflect.ValueOf(&obj.x.alpha)
+	case meta.VersionBeta:
+		v = reflect.ValueOf(&obj.x.beta)
+	default:
+		return nil, fmt.Errorf("Resource.References: invalid version %q", obj.ver)
 	}
+	return findReferences(obj.x.typeTrait.FieldTraits(obj.ver), v)
+}
+
+// CheckRequired implements Resource.
+func (obj *resource[GA, Alpha, Beta]) CheckRequired() error {
+	v, err := obj.x.versionValue(obj.ver)
+	if err != nil {
+		return err
+	}
+	return checkRequired(obj.x.typeTrait.FieldTraits(obj.ver), v)
+}
+
+// ConversionWarnings implements Resource.
+func (obj *resource[GA, Alpha, Beta]) ConversionWarnings() []MissingField {
+	return obj.x.ConversionWarnings(obj.ver)
+}
+
+// ConversionReport implements Resource.
+func (obj *resource[GA, Alpha, Beta]) ConversionReport() *ConversionReport {
+	return obj.x.ConversionReport()
+}
+
+// GetByPath implements Resource.
+func (obj *resource[GA, Alpha, Beta]) GetByPath(ver meta.Version, p Path) (any, error) {
+	return obj.x.GetByPath(ver, p)
+}
+
+// Hash implements Resource.
+func (obj *resource[GA, Alpha, Beta]) Hash() (string, error) {
+	var x any
+	switch obj.ver {
+	case meta.VersionGA:
+		x = &obj.x.ga
+	case meta.VersionAlpha:
+		x = &obj.x.alpha
+	case meta.VersionBeta:
+		x = &obj.x.beta
+	default:
+		return "", fmt.Errorf("Resource.Hash: invalid version %q", obj.ver)
+	}
+	h, err := hashObject(x, obj.x.typeTrait.FieldTraits(obj.ver))
+	if err != nil {
+		return "", fmt.Errorf("Resource.Hash: %w", err)
+	}
+	return h, nil
+}
+
+// Clone implements Resource.
+func (obj *resource[GA, Alpha, Beta]) Clone() (Resource[GA, Alpha, Beta], error) {
+	x, err := obj.x.clone()
+	if err != nil {
+		return nil, err
+	}
+	return &resource[GA, Alpha, Beta]{x: x, ver: obj.ver}, nil
 }
-*/
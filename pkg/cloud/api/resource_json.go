@@ -0,0 +1,347 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// resourceJSON is the serialized form of a (mutable)Resource.
+type resourceJSON struct {
+	ResourceID *cloud.ResourceID `json:"resourceID,omitempty"`
+	// Version is set only for a frozen Resource.
+	Version           meta.Version       `json:"version,omitempty"`
+	StrictVersion     meta.Version       `json:"strictVersion,omitempty"`
+	VersionPreference []meta.Version     `json:"versionPreference,omitempty"`
+	Tolerated         []Path             `json:"tolerated,omitempty"`
+	RecordSetFields   bool               `json:"recordSetFields,omitempty"`
+	SetFields         []Path             `json:"setFields,omitempty"`
+	GA                versionJSON        `json:"ga"`
+	Alpha             versionJSON        `json:"alpha"`
+	Beta              versionJSON        `json:"beta"`
+	MissingFields     []missingFieldJSON `json:"missingFields,omitempty"`
+}
+
+// versionJSON is a serialized version struct. The metafields
+// (ForceSendFields and NullFields) are not part of the JSON encoding of the
+// API types so they are saved separately, indexed by the Path.String() of the
+// struct containing them.
+type versionJSON struct {
+	Object     json.RawMessage           `json:"object"`
+	Metafields map[string]metafieldsJSON `json:"metafields,omitempty"`
+}
+
+type metafieldsJSON struct {
+	ForceSendFields []string `json:"forceSendFields,omitempty"`
+	NullFields      []string `json:"nullFields,omitempty"`
+}
+
+type missingFieldJSON struct {
+	Context   ConversionContext `json:"context"`
+	Path      Path              `json:"path"`
+	Value     json.RawMessage   `json:"value"`
+	Tolerated bool              `json:"tolerated,omitempty"`
+	Severity  Severity          `json:"severity,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. All versions of the resource are
+// serialized along with the conversion errors and ResourceID.
+func (u *mutableResource[GA, Alpha, Beta]) MarshalJSON() ([]byte, error) {
+	rj, err := u.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The TypeTrait of u is kept; it
+// will be set to the registered TypeTrait (see RegisterTypeTrait()) or
+// BaseTypeTrait if it is nil.
+func (u *mutableResource[GA, Alpha, Beta]) UnmarshalJSON(b []byte) error {
+	var rj resourceJSON
+	if err := json.Unmarshal(b, &rj); err != nil {
+		return err
+	}
+	return u.fromJSON(&rj)
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) toJSON() (*resourceJSON, error) {
+	rj := &resourceJSON{
+		ResourceID:        u.resourceID,
+		StrictVersion:     u.strictVersion,
+		VersionPreference: u.versionPreference,
+		Tolerated:         u.tolerated,
+	}
+	if u.setFields != nil {
+		rj.RecordSetFields = true
+		rj.SetFields = u.setFields.paths
+	}
+	var err error
+	if rj.GA, err = marshalVersion(&u.ga, u.metafieldNames(meta.VersionGA)); err != nil {
+		return nil, fmt.Errorf("MarshalJSON: ga: %w", err)
+	}
+	if rj.Alpha, err = marshalVersion(&u.alpha, u.metafieldNames(meta.VersionAlpha)); err != nil {
+		return nil, fmt.Errorf("MarshalJSON: alpha: %w", err)
+	}
+	if rj.Beta, err = marshalVersion(&u.beta, u.metafieldNames(meta.VersionBeta)); err != nil {
+		return nil, fmt.Errorf("MarshalJSON: beta: %w", err)
+	}
+	for cc := ConversionContext(0); cc < conversionContextCount; cc++ {
+		for _, mf := range u.errors[cc].missingFields {
+			value, err := json.Marshal(mf.Value)
+			if err != nil {
+				return nil, fmt.Errorf("MarshalJSON: missing field %s: %w", mf.Path, err)
+			}
+			rj.MissingFields = append(rj.MissingFields, missingFieldJSON{
+				Context:   cc,
+				Path:      mf.Path,
+				Value:     value,
+				Tolerated: mf.Tolerated,
+				Severity:  mf.Severity,
+			})
+		}
+	}
+	return rj, nil
+}
+
+func (u *mutableResource[GA, Alpha, Beta]) fromJSON(rj *resourceJSON) error {
+	if u.typeTrait == nil {
+		tt, err := defaultTypeTrait[GA, Alpha, Beta](rj.ResourceID)
+		if err != nil {
+			return fmt.Errorf("UnmarshalJSON: %w", err)
+		}
+		u.typeTrait = tt
+	}
+	var (
+		ga    GA
+		alpha Alpha
+		beta  Beta
+	)
+	if err := unmarshalVersion(rj.GA, &ga, u.metafieldNames(meta.VersionGA)); err != nil {
+		return fmt.Errorf("UnmarshalJSON: ga: %w", err)
+	}
+	if err := unmarshalVersion(rj.Alpha, &alpha, u.metafieldNames(meta.VersionAlpha)); err != nil {
+		return fmt.Errorf("UnmarshalJSON: alpha: %w", err)
+	}
+	if err := unmarshalVersion(rj.Beta, &beta, u.metafieldNames(meta.VersionBeta)); err != nil {
+		return fmt.Errorf("UnmarshalJSON: beta: %w", err)
+	}
+	var errs [conversionContextCount]conversionErrors
+	for _, mfj := range rj.MissingFields {
+		if mfj.Context < 0 || mfj.Context >= conversionContextCount {
+			return fmt.Errorf("UnmarshalJSON: invalid conversion context %d", mfj.Context)
+		}
+		// Decode the value using the type of the field in the source
+		// version.
+		var srcType reflect.Type
+		switch conversionVersions[mfj.Context][0] {
+		case meta.VersionGA:
+			srcType = reflect.TypeOf(&ga)
+		case meta.VersionAlpha:
+			srcType = reflect.TypeOf(&alpha)
+		case meta.VersionBeta:
+			srcType = reflect.TypeOf(&beta)
+		}
+		value, err := unmarshalValue(mfj.Value, mfj.Path, srcType)
+		if err != nil {
+			return fmt.Errorf("UnmarshalJSON: missing field %s: %w", mfj.Path, err)
+		}
+		errs[mfj.Context].missingFields = append(errs[mfj.Context].missingFields, missingFieldOnCopy{
+			Path:      mfj.Path,
+			Value:     value,
+			Tolerated: mfj.Tolerated,
+			Severity:  mfj.Severity,
+		})
+	}
+
+	u.resourceID = rj.ResourceID
+	u.strictVersion = rj.StrictVersion
+	u.versionPreference = rj.VersionPreference
+	u.tolerated = rj.Tolerated
+	u.setFields = nil
+	if rj.RecordSetFields {
+		u.setFields = &deltaRecorder{paths: rj.SetFields}
+	}
+	u.ga = ga
+	u.alpha = alpha
+	u.beta = beta
+	u.errors = errs
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. See mutableResource.MarshalJSON().
+func (obj *resource[GA, Alpha, Beta]) MarshalJSON() ([]byte, error) {
+	rj, err := obj.x.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	rj.Version = obj.ver
+	return json.Marshal(rj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See
+// mutableResource.UnmarshalJSON().
+func (obj *resource[GA, Alpha, Beta]) UnmarshalJSON(b []byte) error {
+	var rj resourceJSON
+	if err := json.Unmarshal(b, &rj); err != nil {
+		return err
+	}
+	switch rj.Version {
+	case meta.VersionGA, meta.VersionAlpha, meta.VersionBeta:
+	default:
+		return fmt.Errorf("UnmarshalJSON: invalid version %q", rj.Version)
+	}
+	x := &mutableResource[GA, Alpha, Beta]{}
+	if obj.x != nil {
+		x.typeTrait = obj.x.typeTrait
+		x.copierOptions = obj.x.copierOptions
+	}
+	if err := x.fromJSON(&rj); err != nil {
+		return err
+	}
+	obj.x = x
+	obj.ver = rj.Version
+	return nil
+}
+
+// UnmarshalMutableResource restores a MutableResource serialized with
+// json.Marshal(). If typeTrait is nil, then the registered TypeTrait (see
+// RegisterTypeTrait()) or BaseTypeTrait is used.
+func UnmarshalMutableResource[GA any, Alpha any, Beta any](
+	b []byte,
+	typeTrait TypeTrait[GA, Alpha, Beta],
+) (MutableResource[GA, Alpha, Beta], error) {
+	u := &mutableResource[GA, Alpha, Beta]{typeTrait: typeTrait}
+	if err := json.Unmarshal(b, u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UnmarshalResource restores a Resource serialized with json.Marshal(). If
+// typeTrait is nil, then the registered TypeTrait (see RegisterTypeTrait()) or
+// BaseTypeTrait is used.
+func UnmarshalResource[GA any, Alpha any, Beta any](
+	b []byte,
+	typeTrait TypeTrait[GA, Alpha, Beta],
+) (Resource[GA, Alpha, Beta], error) {
+	obj := &resource[GA, Alpha, Beta]{
+		x: &mutableResource[GA, Alpha, Beta]{typeTrait: typeTrait},
+	}
+	if err := json.Unmarshal(b, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// marshalVersion serializes the object x along with its metafields. names
+// are the metafields of x.
+func marshalVersion(x any, names MetafieldNames) (versionJSON, error) {
+	var ret versionJSON
+
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		mfa, err := newMetafieldAccessor(v, names)
+		if err != nil || mfa.empty() {
+			// Type does not have metafields.
+			return true, nil
+		}
+		mf := metafieldsJSON{
+			ForceSendFields: mfa.forceSendFields.Interface().([]string),
+			NullFields:      mfa.nullFields.Interface().([]string),
+		}
+		if len(mf.ForceSendFields) == 0 && len(mf.NullFields) == 0 {
+			return true, nil
+		}
+		if ret.Metafields == nil {
+			ret.Metafields = map[string]metafieldsJSON{}
+		}
+		ret.Metafields[p.String()] = mf
+		return true, nil
+	}
+	if err := visit(reflect.ValueOf(x), acc); err != nil {
+		return ret, err
+	}
+
+	var err error
+	ret.Object, err = json.Marshal(x)
+	return ret, err
+}
+
+// unmarshalVersion restores an object serialized with marshalVersion().
+func unmarshalVersion(vj versionJSON, x any, names MetafieldNames) error {
+	if len(vj.Object) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(vj.Object, x); err != nil {
+		return err
+	}
+	if len(vj.Metafields) == 0 {
+		return nil
+	}
+
+	found := 0
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		mf, ok := vj.Metafields[p.String()]
+		if !ok {
+			return true, nil
+		}
+		mfa, err := newMetafieldAccessor(v, names)
+		if err == nil && mfa.empty() {
+			err = fmt.Errorf("type does not have metafields")
+		}
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", p, err)
+		}
+		mfa.forceSendFields.Set(reflect.ValueOf(mf.ForceSendFields))
+		mfa.nullFields.Set(reflect.ValueOf(mf.NullFields))
+		found++
+		return true, nil
+	}
+	if err := visit(reflect.ValueOf(x), acc); err != nil {
+		return err
+	}
+	if found != len(vj.Metafields) {
+		return fmt.Errorf("metafields for %d paths could not be restored", len(vj.Metafields)-found)
+	}
+	return nil
+}
+
+// unmarshalValue decodes the value of the field at p in type t. The value is
+// decoded into a generic value (e.g. float64 for numbers) if the type of the
+// field cannot be determined.
+func unmarshalValue(b json.RawMessage, p Path, t reflect.Type) (any, error) {
+	if ft, err := p.ResolveType(t); err == nil {
+		v := reflect.New(ft)
+		if err := json.Unmarshal(b, v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+	var ret any
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
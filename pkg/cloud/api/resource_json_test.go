@@ -0,0 +1,142 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceJSON(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		I               int
+		StP             *sti
+		M               map[string]sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		Name            string
+		SelfLink        string
+		I               int
+		AI              int64
+		StP             *sti
+		M               map[string]sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		Name            string
+		SelfLink        string
+		I               int
+		BI              string
+		StP             *sti
+		M               map[string]sti
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alph, beta](&testTrait[ga, alph, beta]{})
+	if err := res.Access(func(x *ga) {
+		x.StP = &sti{I: 1, ForceSendFields: []string{"S"}}
+		x.M = map[string]sti{"a": {S: "abc", NullFields: []string{"I"}}}
+		x.NullFields = []string{"I"}
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := res.AccessAlpha(func(x *alph) { x.AI = 10 }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	res.StrictConversion(meta.VersionGA)
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	got, err := UnmarshalMutableResource[ga, alph, beta](b, &testTrait[ga, alph, beta]{})
+	if err != nil {
+		t.Fatalf("UnmarshalMutableResource() = %v, want nil", err)
+	}
+	gotRes := got.(*mutableResource[ga, alph, beta])
+
+	if diff := cmp.Diff(gotRes.resourceID, res.resourceID); diff != "" {
+		t.Errorf("resourceID; -got,+want: %s", diff)
+	}
+	if gotRes.strictVersion != meta.VersionGA {
+		t.Errorf("strictVersion = %q, want %q", gotRes.strictVersion, meta.VersionGA)
+	}
+	if diff := cmp.Diff(gotRes.ga, res.ga); diff != "" {
+		t.Errorf("ga; -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(gotRes.alpha, res.alpha); diff != "" {
+		t.Errorf("alpha; -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(gotRes.beta, res.beta); diff != "" {
+		t.Errorf("beta; -got,+want: %s", diff)
+	}
+	// The missing field values keep their types.
+	if diff := cmp.Diff(gotRes.errors, res.errors, cmp.AllowUnexported(conversionErrors{}, missingFieldOnCopy{})); diff != "" {
+		t.Errorf("errors; -got,+want: %s", diff)
+	}
+	if _, err := got.ToGA(); err == nil {
+		t.Error("ToGA() = _, nil; want error (AI is missing)")
+	}
+
+	// Frozen resources keep their version.
+	frozen, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	b, err = json.Marshal(frozen)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	gotFrozen, err := UnmarshalResource[ga, alph, beta](b, &testTrait[ga, alph, beta]{})
+	if err != nil {
+		t.Fatalf("UnmarshalResource() = %v, want nil", err)
+	}
+	if gotFrozen.Version() != meta.VersionAlpha {
+		t.Errorf("Version() = %q, want %q", gotFrozen.Version(), meta.VersionAlpha)
+	}
+	dr, err := gotFrozen.Diff(frozen)
+	if err != nil || dr.HasDiff() {
+		t.Errorf("Diff() = %+v, %v; want no diff", dr, err)
+	}
+
+	for _, tc := range []string{
+		`{"version": "v2"}`,
+		`{"missingFields": [{"context": 100}]}`,
+		`{"ga": {"object": {}, "metafields": {"*.StP": {"nullFields": ["I"]}}}}`,
+	} {
+		if _, err := UnmarshalResource[ga, alph, beta]([]byte(tc), nil); err == nil {
+			t.Errorf("UnmarshalResource(%s) = _, nil; want error", tc)
+		}
+	}
+}
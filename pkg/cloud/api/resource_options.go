@@ -0,0 +1,119 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ResourceOption configures the Resource created by NewResource().
+type ResourceOption func(*resourceOptions)
+
+type resourceOptions struct {
+	copierOptions     []copierOption
+	strictVersion     meta.Version
+	versionPreference []meta.Version
+	tolerated         []Path
+	values            []fieldValue
+	fieldTraits       []func(meta.Version, *FieldTraits)
+}
+
+type fieldValue struct {
+	ver   meta.Version
+	path  Path
+	value any
+}
+
+// WithFieldValue sets the field at p in version ver to value. The value is
+// copied to the other versions, the same as SetByPath(). Validation is not
+// done on the initial values as the resource is usually incomplete at
+// construction.
+func WithFieldValue(ver meta.Version, p Path, value any) ResourceOption {
+	return func(o *resourceOptions) {
+		o.values = append(o.values, fieldValue{ver: ver, path: append(Path{}, p...), value: value})
+	}
+}
+
+// WithCopierLogS logs the operations of the copier used to convert between
+// versions to f.
+func WithCopierLogS(f func(msg string, kv ...any)) ResourceOption {
+	return func(o *resourceOptions) { o.copierOptions = append(o.copierOptions, copierLogS(f)) }
+}
+
+// WithStrictConversion is the same as calling StrictConversion(ver).
+func WithStrictConversion(ver meta.Version) ResourceOption {
+	return func(o *resourceOptions) { o.strictVersion = ver }
+}
+
+// WithTolerateMissingFields is the same as calling
+// TolerateMissingFields(paths...).
+func WithTolerateMissingFields(paths ...Path) ResourceOption {
+	return func(o *resourceOptions) { o.tolerated = append(o.tolerated, paths...) }
+}
+
+// WithVersionPreference is the same as calling VersionPreference(order...).
+func WithVersionPreference(order ...meta.Version) ResourceOption {
+	return func(o *resourceOptions) { o.versionPreference = append([]meta.Version{}, order...) }
+}
+
+// WithFieldTraits registers per-field hooks (e.g. FieldTraits.Validate(),
+// FieldTraits.Convert()) for this resource only. f is called with a copy of
+// the FieldTraits of the TypeTrait for each version; the TypeTrait itself is
+// not modified.
+func WithFieldTraits(f func(ver meta.Version, dt *FieldTraits)) ResourceOption {
+	return func(o *resourceOptions) { o.fieldTraits = append(o.fieldTraits, f) }
+}
+
+// WithMetafieldNames sets the names of the metafields of the API structs of
+// this resource for all versions (see FieldTraits.Metafields()). This is used
+// for structs that do not follow the google.golang.org/api conventions.
+func WithMetafieldNames(n MetafieldNames) ResourceOption {
+	return WithFieldTraits(func(_ meta.Version, dt *FieldTraits) { dt.Metafields(n) })
+}
+
+// overlayTypeTrait is a TypeTrait with FieldTraits that replace the ones
+// from the embedded TypeTrait.
+type overlayTypeTrait[GA any, Alpha any, Beta any] struct {
+	TypeTrait[GA, Alpha, Beta]
+	fieldTraits map[meta.Version]*FieldTraits
+}
+
+func newOverlayTypeTrait[GA any, Alpha any, Beta any](
+	base TypeTrait[GA, Alpha, Beta],
+	fns []func(meta.Version, *FieldTraits),
+) *overlayTypeTrait[GA, Alpha, Beta] {
+	ret := &overlayTypeTrait[GA, Alpha, Beta]{
+		TypeTrait:   base,
+		fieldTraits: map[meta.Version]*FieldTraits{},
+	}
+	for _, ver := range meta.AllVersions {
+		dt := &FieldTraits{}
+		if bt := base.FieldTraits(ver); bt != nil {
+			dt = bt.Clone()
+		}
+		for _, f := range fns {
+			f(ver, dt)
+		}
+		ret.fieldTraits[ver] = dt
+	}
+	return ret
+}
+
+// FieldTraits implements TypeTrait.
+func (t *overlayTypeTrait[GA, Alpha, Beta]) FieldTraits(ver meta.Version) *FieldTraits {
+	return t.fieldTraits[ver]
+}
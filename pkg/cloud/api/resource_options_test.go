@@ -0,0 +1,231 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewResourceOptions(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		S               string
+		A               int
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stGA struct {
+		I               int
+		S               string
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	id := &cloud.ResourceID{
+		ProjectID: "proj-1",
+		Resource:  "st",
+		Key:       meta.GlobalKey("obj-1"),
+	}
+	errInvalid := errors.New("invalid")
+
+	var logged bool
+	res := NewResource[stGA, st, st](id, &testTrait[stGA, st, st]{},
+		WithFieldValue(meta.VersionAlpha, Path{}.Pointer().Field("I"), 10),
+		WithFieldValue(meta.VersionAlpha, Path{}.Pointer().Field("A"), 5),
+		WithVersionPreference(meta.VersionBeta, meta.VersionAlpha),
+		WithCopierLogS(func(string, ...any) { logged = true }),
+		WithFieldTraits(func(_ meta.Version, dt *FieldTraits) {
+			dt.Validate(Path{}.Pointer().Field("S"), func(v any) error {
+				if v.(string) == "bad" {
+					return errInvalid
+				}
+				return nil
+			})
+		}),
+	)
+
+	// Initial values are copied to the other versions.
+	beta, err := res.ToBeta()
+	if err != nil {
+		t.Fatalf("ToBeta() = %v, want nil", err)
+	}
+	if beta.I != 10 || beta.A != 5 || beta.Name != "obj-1" {
+		t.Errorf("ToBeta() = %+v, want I=10, A=5, Name=obj-1", beta)
+	}
+	if _, err := res.ToGA(); err == nil {
+		t.Errorf("ToGA() = nil, want error (A is missing)")
+	}
+	if !logged {
+		t.Errorf("copier log was not called")
+	}
+
+	ver, err := res.ImpliedVersion()
+	if err != nil || ver != meta.VersionBeta {
+		t.Errorf("ImpliedVersion() = %s, %v; want %s, nil", ver, err, meta.VersionBeta)
+	}
+
+	// The per-resource field hook is used.
+	err = res.AccessBeta(func(x *st) { x.S = "bad" })
+	var fe *FieldError
+	if !errors.As(err, &fe) || !fe.Path.Equal(Path{}.Pointer().Field("S")) {
+		t.Errorf("AccessBeta() = %v, want FieldError for *.S", err)
+	}
+
+	// The TypeTrait is not modified by WithFieldTraits().
+	other := newTestResource[stGA, st, st](&testTrait[stGA, st, st]{})
+	if err := other.Access(func(x *stGA) { x.S = "bad" }); err != nil {
+		t.Errorf("Access() = %v, want nil", err)
+	}
+}
+
+func TestNewResourceOptionsStrict(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		A               int
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stGA struct {
+		I               int
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	id := &cloud.ResourceID{
+		ProjectID: "proj-1",
+		Resource:  "st",
+		Key:       meta.GlobalKey("obj-1"),
+	}
+	newRes := func(opts ...ResourceOption) *mutableResource[stGA, st, st] {
+		return NewResource[stGA, st, st](id, &testTrait[stGA, st, st]{}, opts...)
+	}
+
+	res := newRes(WithStrictConversion(meta.VersionGA))
+	if err := res.AccessAlpha(func(x *st) { x.A = 1 }); err == nil {
+		t.Errorf("AccessAlpha() = nil, want error for strict conversion")
+	}
+
+	res = newRes(
+		WithStrictConversion(meta.VersionGA),
+		WithTolerateMissingFields(Path{}.Pointer().Field("A")),
+	)
+	if err := res.AccessAlpha(func(x *st) { x.A = 1 }); err != nil {
+		t.Errorf("AccessAlpha() = %v, want nil (A is tolerated)", err)
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("NewResource() did not panic for an invalid WithFieldValue()")
+			}
+		}()
+		newRes(WithFieldValue(meta.VersionGA, Path{}.Pointer().Field("I"), "string"))
+	}()
+}
+
+func TestNewResourceOptionsMetafieldNames(t *testing.T) {
+	t.Parallel()
+
+	type custom struct {
+		I        int
+		A        int
+		Name     string
+		SelfLink string
+		Nulls    []string
+		Forces   []string
+	}
+	type customGA struct {
+		I        int
+		Name     string
+		SelfLink string
+		Nulls    []string
+		Forces   []string
+	}
+	type bare struct {
+		I        int
+		Name     string
+		SelfLink string
+	}
+
+	id := &cloud.ResourceID{
+		ProjectID: "proj-1",
+		Resource:  "st",
+		Key:       meta.GlobalKey("obj-1"),
+	}
+
+	res := NewResource[customGA, custom, custom](id, &BaseTypeTrait[customGA, custom, custom]{},
+		WithMetafieldNames(MetafieldNames{NullFields: "Nulls", ForceSendFields: "Forces"}))
+	if err := res.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	if err := res.AccessAlpha(func(x *custom) {
+		x.Name = "obj-1"
+		x.SelfLink = "link"
+		x.A = 10
+		x.Forces = []string{"I"}
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	ga, err := res.ToGA()
+	var cerr *ConversionError
+	if !errors.As(err, &cerr) || !cerr.Has(Path{}.Pointer().Field("A")) {
+		t.Errorf("ToGA() = _, %v; want missing field A", err)
+	}
+	if diff := cmp.Diff(ga.Forces, []string{"I"}); diff != "" {
+		t.Errorf("ToGA().Forces; -got,+want: %s", diff)
+	}
+	if err := res.ClearField(Path{}.Pointer().Field("A")); err != nil {
+		t.Fatalf("ClearField(A) = %v, want nil", err)
+	}
+	a, _ := res.ToAlpha()
+	if diff := cmp.Diff(a.Forces, []string{"I", "A"}); diff != "" {
+		t.Errorf("ToAlpha().Forces after ClearField(A); -got,+want: %s", diff)
+	}
+
+	// Structs without metafields: zero values are allowed and nothing is
+	// recorded for cleared fields.
+	bareRes := NewResource[bare, bare, bare](id, &BaseTypeTrait[bare, bare, bare]{}, WithMetafieldNames(MetafieldNames{}))
+	if err := bareRes.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	if err := bareRes.Access(func(x *bare) { x.SelfLink = "link" }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := bareRes.ClearField(Path{}.Pointer().Field("I")); err != nil {
+		t.Fatalf("ClearField(I) = %v, want nil", err)
+	}
+	if _, err := bareRes.Freeze(); err != nil {
+		t.Errorf("Freeze() = %v, want nil", err)
+	}
+}
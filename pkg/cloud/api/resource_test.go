@@ -17,6 +17,11 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
@@ -544,6 +549,72 @@ func TestResourceMissingFields(t *testing.T) {
 	}
 }
 
+func TestResourceMapOfStructsAndPointerToSlice(t *testing.T) {
+	t.Parallel()
+
+	type inner struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type innerAlpha struct {
+		I               int
+		AI              int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		M               map[string]*inner
+		LStr            *[]inner
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		Name            string
+		SelfLink        string
+		M               map[string]*innerAlpha
+		LStr            *[]innerAlpha
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, alpha, alpha](&testTrait[ga, alpha, alpha]{})
+	if err := res.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	err := res.AccessAlpha(func(x *alpha) {
+		x.M = map[string]*innerAlpha{"a": {I: 1, AI: 2}, "b": {I: 3}}
+		x.LStr = &[]innerAlpha{{I: 4}, {I: 5, AI: 6}}
+	})
+	if err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	gaObj, err := res.ToGA()
+	want := &ga{
+		Name: "obj-1",
+		M:    map[string]*inner{"a": {I: 1}, "b": {I: 3}},
+		LStr: &[]inner{{I: 4}, {I: 5}},
+	}
+	if diff := cmp.Diff(gaObj, want); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+	var cerr *ConversionError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("ToGA() = _, %v; want ConversionError", err)
+	}
+	var gotPaths []string
+	for _, mf := range cerr.MissingFields {
+		gotPaths = append(gotPaths, mf.Path.String())
+	}
+	wantPaths := []string{"*.M:a*.AI", "*.LStr*!1.AI"}
+	if diff := cmp.Diff(gotPaths, wantPaths); diff != "" {
+		t.Errorf("MissingFields; -got,+want: %s", diff)
+	}
+}
+
 func TestResourceMissingMetaFields(t *testing.T) {
 	t.Parallel()
 
@@ -602,6 +673,103 @@ func TestResourceMissingMetaFields(t *testing.T) {
 	}
 }
 
+func TestResourceStrictConversion(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name    string
+		strict  meta.Version
+		f       func(MutableResource[ga, alph, beta]) error
+		wantErr bool
+		// wantPath is the path of the missing field in the error.
+		wantPath Path
+	}{
+		{
+			name: "not strict",
+			f: func(r MutableResource[ga, alph, beta]) error {
+				return r.AccessAlpha(func(x *alph) { x.A = 10; x.B = 20 })
+			},
+		},
+		{
+			name:   "strict GA",
+			strict: meta.VersionGA,
+			f: func(r MutableResource[ga, alph, beta]) error {
+				return r.AccessAlpha(func(x *alph) { x.A = 10; x.B = 20 })
+			},
+			wantErr:  true,
+			wantPath: Path{}.Pointer().Field("B"),
+		},
+		{
+			name:   "strict GA metafield",
+			strict: meta.VersionGA,
+			f: func(r MutableResource[ga, alph, beta]) error {
+				return r.AccessAlpha(func(x *alph) { x.A = 10; x.ForceSendFields = []string{"B"} })
+			},
+			wantErr:  true,
+			wantPath: Path{}.Pointer().Field("ForceSendFields").Field("B"),
+		},
+		{
+			name:     "strict GA Set",
+			strict:   meta.VersionGA,
+			f:        func(r MutableResource[ga, alph, beta]) error { return r.SetAlpha(&alph{A: 1, B: 20}) },
+			wantErr:  true,
+			wantPath: Path{}.Pointer().Field("B"),
+		},
+		{
+			name:   "strict GA common field",
+			strict: meta.VersionGA,
+			f:      func(r MutableResource[ga, alph, beta]) error { return r.Access(func(x *ga) { x.A = 10 }) },
+		},
+		{
+			name:   "strict Alpha",
+			strict: meta.VersionAlpha,
+			f: func(r MutableResource[ga, alph, beta]) error {
+				return r.AccessAlpha(func(x *alph) { x.A = 10; x.B = 20 })
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res := newTestResource[ga, alph, beta](nil)
+			if tc.strict != "" {
+				res.StrictConversion(tc.strict)
+			}
+			err := tc.f(res)
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("f() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			var mfErr *MissingFieldError
+			if !errors.As(err, &mfErr) {
+				t.Fatalf("f() = %v, want *MissingFieldError", err)
+			}
+			if !mfErr.Path.Equal(tc.wantPath) {
+				t.Errorf("Path = %v, want %v", mfErr.Path, tc.wantPath)
+			}
+		})
+	}
+}
+
 func TestResourceSetX(t *testing.T) {
 	t.Parallel()
 
@@ -958,3 +1126,789 @@ func TestResourceTypeTrait(t *testing.T) {
 		})
 	}
 }
+
+func TestResourceClone(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		I               int
+		StP             *sti
+		LStr            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		Name            string
+		SelfLink        string
+		I               int
+		StP             *sti
+		LStr            []string
+		ABS             []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta = ga
+
+	res := newTestResource[ga, alph, beta](&testTrait[ga, alph, beta]{})
+	res.RecordDelta()
+	if err := res.AccessAlpha(func(x *alph) {
+		x.StP = &sti{I: 1}
+		x.LStr = []string{"a"}
+		x.ABS = []string{"b"}
+	}); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+
+	c, err := res.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	clone := c.(*mutableResource[ga, alph, beta])
+	if diff := cmp.Diff(clone.alpha, res.alpha); diff != "" {
+		t.Errorf("alpha; -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(clone.errors, res.errors, cmp.AllowUnexported(conversionErrors{}, missingFieldOnCopy{})); diff != "" {
+		t.Errorf("errors; -got,+want: %s", diff)
+	}
+
+	// Changes to the clone are independent of the original.
+	if err := clone.Access(func(x *ga) {
+		x.StP.I = 2
+		x.LStr[0] = "z"
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	clone.errors[GAToAlphaConversion].missingFields = nil
+	clone.resourceID.Key.Name = "other"
+	clone.errors[AlphaToGAConversion].missingFields[0].Value.([]string)[0] = "z"
+
+	gaObj, err := res.ToGA()
+	if diff := cmp.Diff(gaObj, &ga{Name: "obj-1", StP: &sti{I: 1}, LStr: []string{"a"}}); diff != "" {
+		t.Errorf("original ToGA(); -got,+want: %s", diff)
+	}
+	var cerr *ConversionError
+	if !errors.As(err, &cerr) || len(cerr.MissingFields) != 1 || cmp.Diff(cerr.MissingFields[0].Value, []string{"b"}) != "" {
+		t.Errorf("original ToGA() = _, %v; want missing field ABS = [b]", err)
+	}
+	if res.resourceID.Key.Name != "obj-1" {
+		t.Errorf("original resourceID = %v, want obj-1", res.resourceID)
+	}
+	delta, err := res.Delta()
+	if err != nil || len(delta.Paths) != 3 {
+		t.Errorf("original Delta() = %v, %v; want 3 paths", delta, err)
+	}
+
+	// Frozen resources.
+	frozen, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	fc, err := frozen.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	if fc.Version() != frozen.Version() {
+		t.Errorf("Clone().Version() = %q, want %q", fc.Version(), frozen.Version())
+	}
+	if dr, err := fc.Diff(frozen); err != nil || dr.HasDiff() {
+		t.Errorf("Diff() = %+v, %v; want no diff", dr, err)
+	}
+}
+
+func TestResourceConvert(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		Name            string
+		SelfLink        string
+		Mode            string
+		Protocol        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		Name            string
+		SelfLink        string
+		BalancingMode   string
+		Protocol        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta = ga
+
+	// "Mode" was renamed to "BalancingMode" in Alpha and the "TCP"
+	// protocol is called "TCP_V2".
+	tt := &TypeTraitFuncs[ga, alph, beta]{
+		FieldTraitsF: func(v meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			dt.AllowZeroValue(Path{}.Pointer().Field("SelfLink"))
+			dt.AllowZeroValue(Path{}.Pointer().Field("Protocol"))
+			switch v {
+			case meta.VersionGA, meta.VersionBeta:
+				dt.AllowZeroValue(Path{}.Pointer().Field("Mode"))
+			case meta.VersionAlpha:
+				dt.AllowZeroValue(Path{}.Pointer().Field("BalancingMode"))
+			}
+			switch v {
+			case meta.VersionGA:
+				dt.Convert(meta.VersionAlpha, Path{}.Pointer().Field("Mode"), ConvertRename("BalancingMode"))
+				dt.Convert(meta.VersionAlpha, Path{}.Pointer().Field("Protocol"), ConvertEnum("Protocol", map[string]string{"TCP": "TCP_V2"}))
+			case meta.VersionAlpha:
+				dt.Convert(meta.VersionGA, Path{}.Pointer().Field("BalancingMode"), ConvertRename("Mode"))
+				dt.Convert(meta.VersionGA, Path{}.Pointer().Field("Protocol"), ConvertEnum("Protocol", map[string]string{"TCP_V2": "TCP"}))
+				dt.Convert(meta.VersionBeta, Path{}.Pointer().Field("BalancingMode"), ConvertRename("Mode"))
+			}
+			return dt
+		},
+	}
+	if err := tt.FieldTraits(meta.VersionGA).CheckSchema(reflect.TypeOf(&ga{})); err != nil {
+		t.Fatalf("CheckSchema(ga) = %v, want nil", err)
+	}
+	if err := tt.FieldTraits(meta.VersionAlpha).CheckSchema(reflect.TypeOf(&alph{})); err != nil {
+		t.Fatalf("CheckSchema(alpha) = %v, want nil", err)
+	}
+
+	res := newTestResource[ga, alph, beta](tt)
+	if err := res.Access(func(x *ga) {
+		x.Mode = "RATE"
+		x.Protocol = "TCP"
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	a, err := res.ToAlpha()
+	if err != nil {
+		t.Errorf("ToAlpha() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(a, &alph{Name: "obj-1", BalancingMode: "RATE", Protocol: "TCP_V2"}); diff != "" {
+		t.Errorf("ToAlpha(); -got,+want: %s", diff)
+	}
+
+	if err := res.AccessAlpha(func(x *alph) { x.BalancingMode = "UTILIZATION" }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	g, err := res.ToGA()
+	if err != nil {
+		t.Errorf("ToGA() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(g, &ga{Name: "obj-1", Mode: "UTILIZATION", Protocol: "TCP"}); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+	// No conversion was registered for Alpha => Beta Protocol.
+	b, err := res.ToBeta()
+	if err != nil {
+		t.Errorf("ToBeta() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(b, &beta{Name: "obj-1", Mode: "UTILIZATION", Protocol: "TCP_V2"}); diff != "" {
+		t.Errorf("ToBeta(); -got,+want: %s", diff)
+	}
+
+	// Errors from the ConvertFunc are returned.
+	tt2 := &TypeTraitFuncs[ga, alph, beta]{
+		FieldTraitsF: func(v meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			dt.AllowZeroValue(Path{}.Pointer().Field("SelfLink"))
+			dt.AllowZeroValue(Path{}.Pointer().Field("Protocol"))
+			dt.Convert(meta.VersionAlpha, Path{}.Pointer().Field("Mode"), ConvertRename("NoSuchField"))
+			return dt
+		},
+	}
+	res = newTestResource[ga, alph, beta](tt2)
+	if err := res.Access(func(x *ga) { x.Mode = "RATE" }); err == nil {
+		t.Error("Access() = nil, want error")
+	}
+}
+
+func TestResourceAdapt(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		Name            string
+		SelfLink        string
+		Port            int64
+		Host            string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alph struct {
+		Name            string
+		SelfLink        string
+		Port            string
+		Host            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta = ga
+
+	// Port is a string in Alpha and Host is repeated.
+	portAdapter := NewTypeAdapter(
+		func(i int64) (string, error) { return strconv.FormatInt(i, 10), nil },
+		func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) })
+	hostAdapter := NewTypeAdapter(
+		func(s string) ([]string, error) { return []string{s}, nil },
+		func(l []string) (string, error) {
+			if len(l) > 1 {
+				return "", fmt.Errorf("%d hosts, only one is supported", len(l))
+			}
+			return l[0], nil
+		})
+	tt := &TypeTraitFuncs[ga, alph, beta]{
+		FieldTraitsF: func(v meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			for _, f := range []string{"SelfLink", "Port", "Host"} {
+				dt.AllowZeroValue(Path{}.Pointer().Field(f))
+			}
+			dt.Adapt(Path{}.Pointer().Field("Port"), portAdapter)
+			dt.Adapt(Path{}.Pointer().Field("Host"), hostAdapter)
+			return dt
+		},
+	}
+	if err := tt.FieldTraits(meta.VersionGA).CheckSchema(reflect.TypeOf(&ga{})); err != nil {
+		t.Fatalf("CheckSchema(ga) = %v, want nil", err)
+	}
+	if err := tt.FieldTraits(meta.VersionAlpha).CheckSchema(reflect.TypeOf(&alph{})); err != nil {
+		t.Fatalf("CheckSchema(alpha) = %v, want nil", err)
+	}
+	badTraits := &FieldTraits{}
+	badTraits.Adapt(Path{}.Pointer().Field("Port"), hostAdapter)
+	if err := badTraits.CheckSchema(reflect.TypeOf(&ga{})); err == nil {
+		t.Errorf("CheckSchema() = nil, want error for adapter with the wrong type")
+	}
+
+	res := newTestResource[ga, alph, beta](tt)
+	if err := res.Access(func(x *ga) {
+		x.Port = 80
+		x.Host = "example.com"
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	a, err := res.ToAlpha()
+	if err != nil {
+		t.Errorf("ToAlpha() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(a, &alph{Name: "obj-1", Port: "80", Host: []string{"example.com"}}); diff != "" {
+		t.Errorf("ToAlpha(); -got,+want: %s", diff)
+	}
+	// Same types are copied as usual.
+	b, err := res.ToBeta()
+	if err != nil {
+		t.Errorf("ToBeta() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(b, &beta{Name: "obj-1", Port: 80, Host: "example.com"}); diff != "" {
+		t.Errorf("ToBeta(); -got,+want: %s", diff)
+	}
+
+	if err := res.AccessAlpha(func(x *alph) { x.Port = "8080" }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	g, err := res.ToGA()
+	if err != nil {
+		t.Errorf("ToGA() = _, %v; want nil", err)
+	}
+	if diff := cmp.Diff(g, &ga{Name: "obj-1", Port: 8080, Host: "example.com"}); diff != "" {
+		t.Errorf("ToGA(); -got,+want: %s", diff)
+	}
+
+	// Errors from the adapter are returned.
+	if err := res.AccessAlpha(func(x *alph) { x.Host = []string{"a", "b"} }); err == nil {
+		t.Error("AccessAlpha() = nil, want error")
+	}
+}
+
+func TestResourceVersionPreference(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	// X is present in both Alpha and Beta.
+	type stX struct {
+		I               int
+		X               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name    string
+		pref    []meta.Version
+		x       int
+		wantVer meta.Version
+		wantErr bool
+	}{
+		{name: "no preference, ga", wantVer: meta.VersionGA},
+		{name: "no preference, ambiguous", x: 1, wantErr: true},
+		{name: "prefer beta", pref: []meta.Version{meta.VersionBeta, meta.VersionAlpha}, x: 1, wantVer: meta.VersionBeta},
+		{name: "prefer alpha", pref: []meta.Version{meta.VersionAlpha, meta.VersionBeta}, x: 1, wantVer: meta.VersionAlpha},
+		{name: "prefer ga", pref: []meta.Version{meta.VersionGA, meta.VersionBeta}, x: 1, wantVer: meta.VersionBeta},
+		{name: "prefer beta over ga", pref: []meta.Version{meta.VersionBeta, meta.VersionGA}, wantVer: meta.VersionBeta},
+		{name: "preferred not representable", pref: []meta.Version{meta.VersionGA}, x: 1, wantErr: true},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := newTestResource[st, stX, stX](nil)
+			res.VersionPreference(tc.pref...)
+			res.SetBeta(&stX{I: 1, X: tc.x})
+
+			ver, err := res.ImpliedVersion()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("ImpliedVersion() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if ver != tc.wantVer {
+				t.Errorf("ImpliedVersion() = %v, want %v", ver, tc.wantVer)
+			}
+			frozen, err := res.Freeze()
+			if err != nil || frozen.Version() != tc.wantVer {
+				t.Errorf("Freeze() = %v, %v; want version %v", frozen, err, tc.wantVer)
+			}
+		})
+	}
+}
+
+func TestConversionError(t *testing.T) {
+	t.Parallel()
+
+	cerr := &ConversionError{
+		MissingFields: []MissingField{
+			{Context: AlphaToGAConversion, Path: Path{}.Pointer().Field("AlphaOnly")},
+			{Context: AlphaToGAConversion, Path: Path{}.Pointer().Field("L").Index(1).Field("X")},
+		},
+	}
+	var err error = cerr
+	if !errors.Is(err, ErrMissingField) {
+		t.Errorf("errors.Is(%v, ErrMissingField) = false, want true", err)
+	}
+	if !errors.Is(&MissingFieldError{}, ErrMissingField) {
+		t.Errorf("errors.Is(MissingFieldError, ErrMissingField) = false, want true")
+	}
+
+	for _, tc := range []struct {
+		p    Path
+		want bool
+	}{
+		{p: Path{}.Pointer().Field("AlphaOnly"), want: true},
+		{p: Path{}.Pointer().Field("L"), want: true},
+		{p: Path{}.Pointer().Field("L").AnySliceIndex().Field("X"), want: true},
+		{p: Path{}.Pointer().Field("L").Index(0).Field("X")},
+		{p: Path{}.Pointer().Field("Other")},
+	} {
+		if got := cerr.Has(tc.p); got != tc.want {
+			t.Errorf("Has(%v) = %t, want %t", tc.p, got, tc.want)
+		}
+	}
+
+	err = cerr.Without(Path{}.Pointer().Field("AlphaOnly"))
+	var remaining *ConversionError
+	if !errors.As(err, &remaining) || len(remaining.MissingFields) != 1 || remaining.Has(Path{}.Pointer().Field("AlphaOnly")) {
+		t.Errorf("Without(AlphaOnly) = %v, want only *.L!1.X", err)
+	}
+	if err := cerr.Without(Path{}.Pointer().Field("AlphaOnly"), Path{}.Pointer().Field("L")); err != nil {
+		t.Errorf("Without(AlphaOnly, L) = %v, want nil", err)
+	}
+	if len(cerr.MissingFields) != 2 {
+		t.Errorf("len(MissingFields) = %d after Without(), want 2 (unchanged)", len(cerr.MissingFields))
+	}
+}
+
+func TestResourceNoVersion(t *testing.T) {
+	t.Parallel()
+
+	type beta struct {
+		Name            string
+		SelfLink        string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		Name            string
+		SelfLink        string
+		I               int
+		AlphaOnly       string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[NoVersion, alpha, beta](&testTrait[NoVersion, alpha, beta]{})
+	if err := res.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	if err := res.Access(func(*NoVersion) {}); !errors.Is(err, ErrVersionNotAvailable) {
+		t.Errorf("Access() = %v, want ErrVersionNotAvailable", err)
+	}
+	if err := res.AccessBeta(func(x *beta) { x.I = 10 }); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	if _, err := res.ToGA(); !errors.Is(err, ErrVersionNotAvailable) {
+		t.Errorf("ToGA() = _, %v, want ErrVersionNotAvailable", err)
+	}
+	if a, err := res.ToAlpha(); err != nil || a.I != 10 {
+		t.Errorf("ToAlpha() = %+v, %v; want I=10, nil", a, err)
+	}
+	// Both available versions can represent the resource, Beta is the most
+	// stable.
+	if ver, err := res.ImpliedVersion(); err != nil || ver != meta.VersionBeta {
+		t.Errorf("ImpliedVersion() = %q, %v; want %q, nil", ver, err, meta.VersionBeta)
+	}
+
+	if err := res.AccessAlpha(func(x *alpha) { x.AlphaOnly = "abc" }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	r, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if r.Version() != meta.VersionAlpha {
+		t.Errorf("Freeze().Version() = %q, want %q", r.Version(), meta.VersionAlpha)
+	}
+}
+
+func TestResourceTolerateMissingFields(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		Name            string
+		SelfLink        string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		Name            string
+		SelfLink        string
+		I               int
+		BI              int
+		S               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	knob := Path{}.Pointer().Field("BI")
+
+	res := newTestResource[ga, beta, beta](&testTrait[ga, beta, beta]{})
+	res.TolerateMissingFields(knob)
+	res.StrictConversion(meta.VersionGA)
+
+	if err := res.AccessBeta(func(x *beta) { x.S = "abc" }); err == nil {
+		t.Fatalf("AccessBeta() = nil, want error (StrictConversion)")
+	}
+
+	res = newTestResource[ga, beta, beta](&testTrait[ga, beta, beta]{})
+	res.TolerateMissingFields(knob)
+	if err := res.AccessBeta(func(x *beta) {
+		x.BI = 10
+		x.S = "def"
+	}); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	var cerr *ConversionError
+	if _, err := res.ToGA(); !errors.As(err, &cerr) || cerr.Has(knob) || len(cerr.MissingFields) != 1 {
+		t.Errorf("ToGA() = _, %v; want ConversionError for S only", err)
+	}
+
+	res = newTestResource[ga, beta, beta](&testTrait[ga, beta, beta]{})
+	res.TolerateMissingFields(knob)
+	res.StrictConversion(meta.VersionGA)
+	if err := res.AccessBeta(func(x *beta) { x.BI = 10 }); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	if _, err := res.ToGA(); err != nil {
+		t.Errorf("ToGA() = _, %v; want nil", err)
+	}
+	wantWarnings := []MissingField{{
+		Context: BetaToGAConversion,
+		From:    meta.VersionBeta,
+		To:      meta.VersionGA,
+		Path:    knob,
+		Value:   10,
+	}}
+	if diff := cmp.Diff(res.ConversionWarnings(meta.VersionGA), wantWarnings); diff != "" {
+		t.Errorf("ConversionWarnings(); -got,+want: %s", diff)
+	}
+
+	// The tolerated paths and the warnings are preserved by Clone().
+	clone, err := res.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(clone.ConversionWarnings(meta.VersionGA), wantWarnings); diff != "" {
+		t.Errorf("Clone().ConversionWarnings(); -got,+want: %s", diff)
+	}
+	// ... and by JSON serialization.
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	restored, err := UnmarshalMutableResource[ga, beta, beta](b, &testTrait[ga, beta, beta]{})
+	if err != nil {
+		t.Fatalf("UnmarshalMutableResource() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(restored.ConversionWarnings(meta.VersionGA), wantWarnings); diff != "" {
+		t.Errorf("restored ConversionWarnings(); -got,+want: %s", diff)
+	}
+	r, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if r.Version() != meta.VersionGA {
+		t.Errorf("Freeze().Version() = %q, want %q", r.Version(), meta.VersionGA)
+	}
+	if diff := cmp.Diff(r.ConversionWarnings(), wantWarnings); diff != "" {
+		t.Errorf("Resource.ConversionWarnings(); -got,+want: %s", diff)
+	}
+}
+
+type informationalTrait[G any, A any, B any] struct {
+	testTrait[G, A, B]
+}
+
+func (t informationalTrait[G, A, B]) FieldTraits(ver meta.Version) *FieldTraits {
+	ret := t.testTrait.FieldTraits(ver)
+	if ver == meta.VersionBeta {
+		ret.Informational(Path{}.Pointer().Field("Status"))
+	}
+	return ret
+}
+
+func TestResourceInformationalMissingFields(t *testing.T) {
+	t.Parallel()
+
+	type ga struct {
+		Name            string
+		SelfLink        string
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		Status          string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	status := Path{}.Pointer().Field("Status")
+
+	res := newTestResource[ga, beta, beta](&informationalTrait[ga, beta, beta]{})
+	if err := res.CheckSchema(); err != nil {
+		t.Fatalf("CheckSchema() = %v, want nil", err)
+	}
+	res.StrictConversion(meta.VersionGA)
+	if err := res.AccessBeta(func(x *beta) { x.Status = "READY" }); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil (informational fields are not strict errors)", err)
+	}
+	var cerr *ConversionError
+	if _, err := res.ToGA(); !errors.As(err, &cerr) {
+		t.Fatalf("ToGA() = _, %v; want ConversionError", err)
+	}
+	if !cerr.Informational() || len(cerr.Errors()) != 0 {
+		t.Errorf("ToGA() = _, %v; want only informational fields", cerr)
+	}
+	wantMissing := []MissingField{{
+		Context:  BetaToGAConversion,
+		From:     meta.VersionBeta,
+		To:       meta.VersionGA,
+		Path:     status,
+		Value:    "READY",
+		Severity: SeverityInfo,
+	}}
+	if diff := cmp.Diff(cerr.MissingFields, wantMissing); diff != "" {
+		t.Errorf("MissingFields; -got,+want: %s", diff)
+	}
+
+	res = newTestResource[ga, beta, beta](&informationalTrait[ga, beta, beta]{})
+	if err := res.AccessBeta(func(x *beta) {
+		x.Status = "READY"
+		x.S = "abc"
+	}); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	if _, err := res.ToGA(); !errors.As(err, &cerr) {
+		t.Fatalf("ToGA() = _, %v; want ConversionError", err)
+	}
+	if cerr.Informational() {
+		t.Errorf("Informational() = true, want false")
+	}
+	if errs := cerr.Errors(); len(errs) != 1 || !errs[0].Path.Equal(Path{}.Pointer().Field("S")) || errs[0].Severity != SeverityError {
+		t.Errorf("Errors() = %v, want only *.S", errs)
+	}
+
+	// The severity is preserved by JSON serialization.
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	restored, err := UnmarshalMutableResource[ga, beta, beta](b, &informationalTrait[ga, beta, beta]{})
+	if err != nil {
+		t.Fatalf("UnmarshalMutableResource() = %v, want nil", err)
+	}
+	if _, err := restored.ToGA(); !errors.As(err, &cerr) || len(cerr.Errors()) != 1 || len(cerr.MissingFields) != 2 {
+		t.Errorf("restored ToGA() = _, %v; want one error and one informational field", err)
+	}
+}
+
+type requiredTrait[G any, A any, B any] struct {
+	testTrait[G, A, B]
+}
+
+func (t requiredTrait[G, A, B]) FieldTraits(ver meta.Version) *FieldTraits {
+	ret := t.testTrait.FieldTraits(ver)
+	ret.AllowZeroValue(Path{}.Pointer().Field("L"))
+	ret.Required(Path{}.Pointer().Field("S"))
+	ret.Required(Path{}.Pointer().Field("L").AnySliceIndex().Field("I"))
+	return ret
+}
+
+func TestResourceRequired(t *testing.T) {
+	t.Parallel()
+
+	type elem struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		S               string
+		L               []elem
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	for _, tc := range []struct {
+		name      string
+		f         func(x *st)
+		wantPaths []Path
+	}{
+		{
+			name: "all set",
+			f: func(x *st) {
+				x.S = "abc"
+				x.L = []elem{{I: 1}}
+			},
+		},
+		{
+			name: "zero value in ForceSendFields",
+			f: func(x *st) {
+				x.S = "abc"
+				x.L = []elem{{I: 0, ForceSendFields: []string{"I"}}}
+			},
+		},
+		{
+			name: "no slice elements",
+			f:    func(x *st) { x.S = "abc" },
+		},
+		{
+			name: "unset",
+			f: func(x *st) {
+				x.L = []elem{{I: 1}, {I: 0, NullFields: []string{"I"}}}
+			},
+			wantPaths: []Path{
+				Path{}.Pointer().Field("S"),
+				Path{}.Pointer().Field("L").Index(1).Field("I"),
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := newTestResource[st, st, st](&requiredTrait[st, st, st]{})
+			if err := res.CheckSchema(); err != nil {
+				t.Fatalf("CheckSchema() = %v, want nil", err)
+			}
+			if err := res.Access(tc.f); err != nil {
+				t.Fatalf("Access() = %v, want nil", err)
+			}
+			_, err := res.Freeze()
+			var verr *ValidationError
+			if tc.wantPaths == nil {
+				if err != nil {
+					t.Errorf("Freeze() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &verr) {
+				t.Fatalf("Freeze() = %v, want ValidationError", err)
+			}
+			var gotPaths []Path
+			for _, fe := range verr.Errors {
+				gotPaths = append(gotPaths, fe.Path)
+			}
+			if diff := cmp.Diff(gotPaths, tc.wantPaths); diff != "" {
+				t.Errorf("Freeze() error paths; -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+type keyedSliceTrait[G any, A any, B any] struct {
+	testTrait[G, A, B]
+}
+
+func (t keyedSliceTrait[G, A, B]) FieldTraits(ver meta.Version) *FieldTraits {
+	ret := t.testTrait.FieldTraits(ver)
+	ret.KeyedSlice(Path{}.Pointer().Field("L"), "K")
+	return ret
+}
+
+func TestResourceKeyedSliceConversion(t *testing.T) {
+	t.Parallel()
+
+	type gaElem struct {
+		K               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		SelfLink        string
+		L               []gaElem
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type betaElem struct {
+		K               string
+		X               string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		Name            string
+		SelfLink        string
+		L               []betaElem
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[ga, beta, beta](&keyedSliceTrait[ga, beta, beta]{})
+	if err := res.AccessBeta(func(x *beta) {
+		x.L = []betaElem{{K: "a", X: "xa"}, {K: "b", X: "xb"}}
+	}); err != nil {
+		t.Fatalf("AccessBeta() = %v, want nil", err)
+	}
+	// Reordering the elements in GA keeps the beta-only fields of the
+	// elements.
+	if err := res.Access(func(x *ga) {
+		x.L[0], x.L[1] = x.L[1], x.L[0]
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	got, _ := res.ToBeta()
+	want := []betaElem{{K: "b", X: "xb"}, {K: "a", X: "xa"}}
+	if diff := cmp.Diff(got.L, want); diff != "" {
+		t.Errorf("ToBeta().L; -got,+want: %s", diff)
+	}
+}
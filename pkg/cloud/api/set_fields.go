@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RecordSetFields implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) RecordSetFields() {
+	if u.setFields == nil {
+		u.setFields = &deltaRecorder{}
+	}
+}
+
+// SetFields implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) SetFields() []Path {
+	if u.setFields == nil {
+		return nil
+	}
+	var ret []Path
+	for _, p := range u.setFields.paths {
+		ret = append(ret, append(Path{}, p...))
+	}
+	return ret
+}
+
+// SetFields implements Resource.
+func (obj *resource[GA, Alpha, Beta]) SetFields() []Path { return obj.x.SetFields() }
+
+// recordSetFields records the fields that were written between before and
+// after: the fields with a different value and the fields that were added to
+// the NullFields or ForceSendFields (e.g. a field explicitly set to zero).
+//
+// Note: writes that do not change the value of a field and do not add it to
+// the metafields cannot be detected and are not recorded.
+func recordSetFields[T any](r *deltaRecorder, before, after *T, names MetafieldNames) error {
+	if r == nil {
+		return nil
+	}
+	result, err := diff(before, after, nil)
+	if err != nil {
+		return fmt.Errorf("recordSetFields: %w", err)
+	}
+	for _, item := range result.Items {
+		r.add(item.Path)
+	}
+	if !names.hasMetafields() {
+		return nil
+	}
+	beforeMeta, err := metafieldPaths(reflect.ValueOf(before), names)
+	if err != nil {
+		return fmt.Errorf("recordSetFields: %w", err)
+	}
+	afterMeta, err := metafieldPaths(reflect.ValueOf(after), names)
+	if err != nil {
+		return fmt.Errorf("recordSetFields: %w", err)
+	}
+	for k, p := range afterMeta {
+		if _, ok := beforeMeta[k]; !ok {
+			r.add(p)
+		}
+	}
+	return nil
+}
+
+// metafieldPaths returns the paths of the fields listed in the NullFields and
+// ForceSendFields of the structs in v, indexed by Path.String().
+func metafieldPaths(v reflect.Value, names MetafieldNames) (map[string]Path, error) {
+	ret := map[string]Path{}
+	acc := newAcceptorFuncs()
+	acc.onStructF = func(p Path, v reflect.Value) (bool, error) {
+		if names.isServerResponse(p) {
+			return false, nil
+		}
+		mfa, err := newMetafieldAccessor(v, names)
+		if err != nil {
+			// Type does not have metafields.
+			return true, nil
+		}
+		for _, m := range []map[string]bool{mfa.null(), mfa.forceSend()} {
+			for fn := range m {
+				// Copy p as the visitor reuses the underlying array.
+				fp := append(Path{}, p.Field(fn)...)
+				ret[fp.String()] = fp
+			}
+		}
+		return true, nil
+	}
+	if err := visit(v, acc); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetFields(t *testing.T) {
+	t.Parallel()
+
+	type sti struct {
+		I               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type st struct {
+		Name            string
+		SelfLink        string
+		I               int
+		S               string
+		StP             *sti
+		LStr            []string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[st, st, st](&testTrait[st, st, st]{})
+	// Set fields are not tracked by default.
+	if err := res.Access(func(x *st) { x.S = "xyz" }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if got := res.SetFields(); got != nil {
+		t.Errorf("SetFields() = %v, want nil before RecordSetFields()", got)
+	}
+	res.RecordSetFields()
+	// Fields from the server are not set by the user.
+	if err := res.Set(&st{SelfLink: "link", LStr: []string{"a"}}); err != nil {
+		t.Fatalf("Set() = %v, want nil", err)
+	}
+	if got := res.SetFields(); got != nil {
+		t.Errorf("SetFields() = %v, want nil after Set()", got)
+	}
+	if err := res.Access(func(x *st) {
+		x.S = "abc"
+		// Explicit zero value.
+		x.ForceSendFields = []string{"I"}
+		x.LStr = append(x.LStr, "b")
+	}); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	if err := res.AccessAlpha(func(x *st) { x.StP = &sti{I: 1} }); err != nil {
+		t.Fatalf("AccessAlpha() = %v, want nil", err)
+	}
+	// Setting the same value is recorded for SetByPath().
+	if err := res.SetByPath(meta.VersionGA, Path{}.Pointer().Field("S"), "abc"); err != nil {
+		t.Fatalf("SetByPath() = %v, want nil", err)
+	}
+	if err := res.SetByPath(meta.VersionGA, Path{}.Pointer().Field("Name"), "obj-1"); err != nil {
+		t.Fatalf("SetByPath() = %v, want nil", err)
+	}
+
+	want := []Path{
+		Path{}.Pointer().Field("I"),
+		Path{}.Pointer().Field("LStr"),
+		Path{}.Pointer().Field("Name"),
+		Path{}.Pointer().Field("S"),
+		Path{}.Pointer().Field("StP"),
+	}
+	if diff := cmp.Diff(res.SetFields(), want); diff != "" {
+		t.Errorf("SetFields(); -got,+want: %s", diff)
+	}
+
+	clone, err := res.Clone()
+	if err != nil {
+		t.Fatalf("Clone() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(clone.SetFields(), want); diff != "" {
+		t.Errorf("Clone().SetFields(); -got,+want: %s", diff)
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	restored, err := UnmarshalMutableResource[st, st, st](b, &testTrait[st, st, st]{})
+	if err != nil {
+		t.Fatalf("UnmarshalMutableResource() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(restored.SetFields(), want); diff != "" {
+		t.Errorf("restored SetFields(); -got,+want: %s", diff)
+	}
+	r, err := res.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v, want nil", err)
+	}
+	if diff := cmp.Diff(r.SetFields(), want); diff != "" {
+		t.Errorf("Resource.SetFields(); -got,+want: %s", diff)
+	}
+}
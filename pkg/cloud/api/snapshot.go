@@ -0,0 +1,48 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import "fmt"
+
+// Snapshot is the saved state of a MutableResource. See
+// MutableResource.Snapshot().
+type Snapshot[GA any, Alpha any, Beta any] struct {
+	x *mutableResource[GA, Alpha, Beta]
+}
+
+// Snapshot implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) Snapshot() (*Snapshot[GA, Alpha, Beta], error) {
+	x, err := u.clone()
+	if err != nil {
+		return nil, fmt.Errorf("Snapshot: %w", err)
+	}
+	return &Snapshot[GA, Alpha, Beta]{x: x}, nil
+}
+
+// Restore implements MutableResource.
+func (u *mutableResource[GA, Alpha, Beta]) Restore(s *Snapshot[GA, Alpha, Beta]) error {
+	if s == nil || s.x == nil {
+		return fmt.Errorf("Restore: nil Snapshot")
+	}
+	// Restore from a copy so that the Snapshot is not shared with u.
+	x, err := s.x.clone()
+	if err != nil {
+		return fmt.Errorf("Restore: %w", err)
+	}
+	*u = *x
+	return nil
+}
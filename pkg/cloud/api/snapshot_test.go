@@ -0,0 +1,103 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		I               int
+		S               string
+		A               int
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type stGA struct {
+		I               int
+		S               string
+		Name            string
+		SelfLink        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	res := newTestResource[stGA, st, st](&testTrait[stGA, st, st]{})
+	if err := res.Access(func(x *stGA) { x.I = 10 }); err != nil {
+		t.Fatalf("Access() = %v, want nil", err)
+	}
+	res.RecordDelta()
+
+	snap, err := res.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() = %v, want nil", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := res.AccessAlpha(func(x *st) { x.I = 20; x.A = 1 }); err != nil {
+			t.Fatalf("AccessAlpha() = %v, want nil", err)
+		}
+		if _, err := res.ToGA(); err == nil {
+			t.Fatalf("ToGA() = nil, want error (A is missing in GA)")
+		}
+		// The Snapshot is not affected by changes to the resource.
+		if snap.x.alpha.I != 10 || snap.x.alpha.A != 0 {
+			t.Fatalf("snap.x.alpha = %+v, want I=10, A=0", snap.x.alpha)
+		}
+
+		// The same Snapshot can be restored multiple times.
+		if err := res.Restore(snap); err != nil {
+			t.Fatalf("Restore() = %v, want nil", err)
+		}
+		ga, err := res.ToGA()
+		if err != nil {
+			t.Fatalf("ToGA() = %v, want nil", err)
+		}
+		if ga.I != 10 {
+			t.Errorf("ga.I = %d, want 10", ga.I)
+		}
+		beta, err := res.ToBeta()
+		if err != nil {
+			t.Fatalf("ToBeta() = %v, want nil", err)
+		}
+		if beta.I != 10 || beta.A != 0 {
+			t.Errorf("beta = %+v, want I=10, A=0", beta)
+		}
+		ver, err := res.ImpliedVersion()
+		if err != nil || ver != meta.VersionGA {
+			t.Errorf("ImpliedVersion() = %s, %v; want %s, nil", ver, err, meta.VersionGA)
+		}
+		d, err := res.Delta()
+		if err != nil {
+			t.Fatalf("Delta() = %v, want nil", err)
+		}
+		if len(d.Paths) != 0 {
+			t.Errorf("Delta().Paths = %v, want none", d.Paths)
+		}
+	}
+
+	if err := res.Restore(nil); err == nil {
+		t.Errorf("Restore(nil) = nil, want error")
+	}
+}
@@ -0,0 +1,395 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Generator for FieldTraits from the Compute API discovery documents. The
+// generated code marks the OutputOnly fields and lists the required fields
+// and the fields that are not present in GA for each resource:
+//
+//	$ go run ./pkg/cloud/api/traitgen \
+//	    -ga compute/v1/compute-api.json \
+//	    -alpha compute/v0.alpha/compute-api.json \
+//	    -beta compute/v0.beta/compute-api.json \
+//	    -package traits > traits.go
+//
+// The discovery documents are distributed with google.golang.org/api.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+var flags = struct {
+	ga        string
+	alpha     string
+	beta      string
+	pkg       string
+	resources string
+}{}
+
+func init() {
+	flag.StringVar(&flags.ga, "ga", "", "path to the GA discovery document (required)")
+	flag.StringVar(&flags.alpha, "alpha", "", "path to the Alpha discovery document")
+	flag.StringVar(&flags.beta, "beta", "", "path to the Beta discovery document")
+	flag.StringVar(&flags.pkg, "package", "traits", "package name of the generated code")
+	flag.StringVar(&flags.resources, "resources", "", "comma separated list of the schemas to generate; defaults to the objects in meta.AllServices")
+}
+
+// discoveryDoc is the subset of the discovery document used by the
+// generator.
+type discoveryDoc struct {
+	Schemas map[string]*schema `json:"schemas"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Description          string             `json:"description"`
+	Properties           map[string]*schema `json:"properties"`
+	Items                *schema            `json:"items"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Annotations          struct {
+		Required []string `json:"required"`
+	} `json:"annotations"`
+}
+
+// field is a property of a schema.
+type field struct {
+	// Expr is the Go expression for the api.Path of the field.
+	Expr string
+	// key identifies the field across versions.
+	key        string
+	outputOnly bool
+	required   bool
+}
+
+// versionFields are the generated traits for one version of a resource.
+type versionFields struct {
+	Version    string
+	OutputOnly []string
+	Required   []string
+	NonGA      []string
+}
+
+type resourceFields struct {
+	Name     string
+	Versions []versionFields
+}
+
+func loadDoc(path string) (*discoveryDoc, error) {
+	if path == "" {
+		return nil, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc discoveryDoc
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &doc, nil
+}
+
+// goFieldName returns the name of the Go struct field generated for the JSON
+// property name (see google.golang.org/api/google-api-go-generator).
+func goFieldName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		switch {
+		case r == '-' || r == '_' || r == '.' || r == '$' || r == '/' || r == '@':
+			upper = true
+			continue
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// fields returns the fields of the schema, recursing into nested schemas.
+func fields(doc *discoveryDoc, name string) ([]field, error) {
+	s, ok := doc.Schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("schema %q not found", name)
+	}
+	var ret []field
+	walkSchema(doc, s, "api.Path{}.Pointer()", "*", map[string]bool{name: true}, &ret)
+	return ret, nil
+}
+
+// walkSchema appends the properties of s to out. expr and key are for the
+// struct containing the properties. refs are the schemas being walked, to
+// avoid infinite recursion on recursive types.
+func walkSchema(doc *discoveryDoc, s *schema, expr, key string, refs map[string]bool, out *[]field) {
+	var names []string
+	for n := range s.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		p := s.Properties[n]
+		goName := goFieldName(n)
+		f := field{
+			Expr:       fmt.Sprintf("%s.Field(%q)", expr, goName),
+			key:        key + "." + goName,
+			outputOnly: strings.Contains(p.Description, "[Output Only]"),
+			required:   len(p.Annotations.Required) > 0,
+		}
+		*out = append(*out, f)
+		if f.outputOnly {
+			// The entire field is OutputOnly.
+			continue
+		}
+		walkValue(doc, p, f.Expr, f.key, true, refs, out)
+	}
+}
+
+// walkValue recurses into the value of the property p. Nested structs are
+// pointers except for the values of maps.
+func walkValue(doc *discoveryDoc, p *schema, expr, key string, pointer bool, refs map[string]bool, out *[]field) {
+	if pointer {
+		expr += ".Pointer()"
+		key += "*"
+	}
+	switch {
+	case p.Ref != "":
+		if refs[p.Ref] {
+			return
+		}
+		s, ok := doc.Schemas[p.Ref]
+		if !ok {
+			return
+		}
+		refs[p.Ref] = true
+		walkSchema(doc, s, expr, key, refs, out)
+		delete(refs, p.Ref)
+	case p.Type == "array" && p.Items != nil:
+		walkValue(doc, p.Items, strings.TrimSuffix(expr, ".Pointer()")+".AnySliceIndex()", strings.TrimSuffix(key, "*")+"!*", true, refs, out)
+	case p.Type == "object" && p.Properties != nil:
+		walkSchema(doc, p, expr, key, refs, out)
+	case p.Type == "object" && p.AdditionalProperties != nil:
+		walkValue(doc, p.AdditionalProperties, strings.TrimSuffix(expr, ".Pointer()")+".AnyMapIndex()", strings.TrimSuffix(key, "*")+":*", false, refs, out)
+	}
+}
+
+// generate the FieldTraits for the resources from the discovery documents.
+// alpha and beta may be nil.
+func generate(wr io.Writer, pkg string, resources []string, ga, alpha, beta *discoveryDoc) error {
+	var all []resourceFields
+	for _, name := range resources {
+		gaFields, err := fields(ga, name)
+		if err != nil {
+			return fmt.Errorf("ga: %w", err)
+		}
+		gaKeys := map[string]bool{}
+		for _, f := range gaFields {
+			gaKeys[f.key] = true
+		}
+		rf := resourceFields{Name: name}
+		for _, vd := range []struct {
+			ver string
+			doc *discoveryDoc
+		}{
+			{"VersionGA", ga},
+			{"VersionAlpha", alpha},
+			{"VersionBeta", beta},
+		} {
+			if vd.doc == nil {
+				continue
+			}
+			if _, ok := vd.doc.Schemas[name]; !ok {
+				continue
+			}
+			fs, err := fields(vd.doc, name)
+			if err != nil {
+				return err
+			}
+			vf := versionFields{Version: vd.ver}
+			var nonGA []string
+			for _, f := range fs {
+				if f.outputOnly {
+					vf.OutputOnly = append(vf.OutputOnly, f.Expr)
+				}
+				if f.required {
+					vf.Required = append(vf.Required, f.Expr)
+				}
+				if gaKeys[f.key] {
+					continue
+				}
+				// Only list the top-most field that is not in GA.
+				covered := false
+				for _, k := range nonGA {
+					if len(f.key) > len(k) && strings.HasPrefix(f.key, k) && strings.ContainsRune("*!:", rune(f.key[len(k)])) {
+						covered = true
+						break
+					}
+				}
+				if !covered {
+					nonGA = append(nonGA, f.key)
+					vf.NonGA = append(vf.NonGA, f.Expr)
+				}
+			}
+			rf.Versions = append(rf.Versions, vf)
+		}
+		all = append(all, rf)
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, map[string]any{
+		"Year":      time.Now().Year(),
+		"Package":   pkg,
+		"Resources": all,
+	})
+	if err != nil {
+		return err
+	}
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("gofmt: %w", err)
+	}
+	_, err = wr.Write(out)
+	return err
+}
+
+var tmpl = template.Must(template.New("traits").Parse(`/*
+Copyright {{.Year}} Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file was generated by "go run ./pkg/cloud/api/traitgen". Do not edit
+// directly.
+
+package {{.Package}}
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+{{range $r := .Resources}}
+// {{$r.Name}}FieldTraits returns the FieldTraits for {{$r.Name}} with the
+// OutputOnly fields from the discovery document for ver.
+func {{$r.Name}}FieldTraits(ver meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	switch ver {
+{{- range $r.Versions}}
+	case meta.{{.Version}}:
+{{- range .OutputOnly}}
+		dt.OutputOnly({{.}})
+{{- end}}
+{{- end}}
+	}
+	return dt
+}
+
+// {{$r.Name}}RequiredFields are the fields that are required by one or more
+// methods (e.g. insert) for each version.
+var {{$r.Name}}RequiredFields = map[meta.Version][]api.Path{
+{{- range $r.Versions}}{{if .Required}}
+	meta.{{.Version}}: {
+{{- range .Required}}
+		{{.}},
+{{- end}}
+	},
+{{- end}}{{end}}
+}
+
+// {{$r.Name}}NonGAFields are the fields for each version that are not present
+// in GA.
+var {{$r.Name}}NonGAFields = map[meta.Version][]api.Path{
+{{- range $r.Versions}}{{if .NonGA}}
+	meta.{{.Version}}: {
+{{- range .NonGA}}
+		{{.}},
+{{- end}}
+	},
+{{- end}}{{end}}
+}
+{{end}}`))
+
+// defaultResources are the object types of meta.AllServices.
+func defaultResources() []string {
+	seen := map[string]bool{}
+	var ret []string
+	for _, s := range meta.AllServices {
+		if seen[s.Object] {
+			continue
+		}
+		seen[s.Object] = true
+		ret = append(ret, s.Object)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func main() {
+	flag.Parse()
+	if flags.ga == "" {
+		log.Fatal("-ga is required")
+	}
+	var docs [3]*discoveryDoc
+	for i, path := range []string{flags.ga, flags.alpha, flags.beta} {
+		doc, err := loadDoc(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		docs[i] = doc
+	}
+	resources := defaultResources()
+	if flags.resources != "" {
+		resources = strings.Split(flags.resources, ",")
+	}
+	// Skip resources that are not in GA, e.g. the objects of alpha-only
+	// services.
+	var gaResources []string
+	for _, r := range resources {
+		if _, ok := docs[0].Schemas[r]; ok {
+			gaResources = append(gaResources, r)
+		}
+	}
+	if err := generate(os.Stdout, flags.pkg, gaResources, docs[0], docs[1], docs[2]); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testGADoc = `{
+  "schemas": {
+    "Foo": {
+      "properties": {
+        "name": {"type": "string", "annotations": {"required": ["compute.foos.insert"]}},
+        "selfLink": {"type": "string", "description": "[Output Only] Server-defined URL."},
+        "IPProtocol": {"type": "string"},
+        "backends": {"type": "array", "items": {"$ref": "Backend"}},
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "configs": {"type": "object", "additionalProperties": {"$ref": "Backend"}},
+        "next": {"$ref": "Foo"}
+      }
+    },
+    "Backend": {
+      "properties": {
+        "group": {"type": "string"},
+        "status": {"type": "string", "description": "[Output Only] Status."}
+      }
+    }
+  }
+}`
+
+const testAlphaDoc = `{
+  "schemas": {
+    "Foo": {
+      "properties": {
+        "name": {"type": "string"},
+        "alphaField": {"type": "string"},
+        "alphaStruct": {"type": "object", "properties": {"x": {"type": "string"}}},
+        "backends": {"type": "array", "items": {"$ref": "Backend"}}
+      }
+    },
+    "Backend": {
+      "properties": {
+        "group": {"type": "string"},
+        "preference": {"type": "string"}
+      }
+    }
+  }
+}`
+
+func TestGoFieldName(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"selfLink", "SelfLink"},
+		{"IPProtocol", "IPProtocol"},
+		{"id", "Id"},
+		{"x-goog-field", "XGoogField"},
+		{"some_name", "SomeName"},
+	} {
+		if got := goFieldName(tc.in); got != tc.want {
+			t.Errorf("goFieldName(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var ga, alpha discoveryDoc
+	if err := json.Unmarshal([]byte(testGADoc), &ga); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(testAlphaDoc), &alpha); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf, "traits", []string{"Foo"}, &ga, &alpha, nil); err != nil {
+		t.Fatalf("generate() = %v, want nil", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"package traits",
+		"func FooFieldTraits(ver meta.Version) *api.FieldTraits {",
+		`dt.OutputOnly(api.Path{}.Pointer().Field("SelfLink"))`,
+		`dt.OutputOnly(api.Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Status"))`,
+		`dt.OutputOnly(api.Path{}.Pointer().Field("Configs").AnyMapIndex().Field("Status"))`,
+		"var FooRequiredFields = map[meta.Version][]api.Path{",
+		`api.Path{}.Pointer().Field("Name"),`,
+		`api.Path{}.Pointer().Field("AlphaField"),`,
+		`api.Path{}.Pointer().Field("AlphaStruct"),`,
+		`api.Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Preference"),`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("generate() output does not contain %q; output:\n%s", want, out)
+		}
+	}
+	for _, notWant := range []string{
+		// Recursive types are not expanded.
+		`Field("Next").Pointer().Field("SelfLink")`,
+		// Only the top-most field that is not in GA is listed.
+		`Field("AlphaStruct").Pointer().Field("X")`,
+	} {
+		if strings.Contains(out, notWant) {
+			t.Errorf("generate() output contains %q; output:\n%s", notWant, out)
+		}
+	}
+}
@@ -124,9 +124,136 @@ func (f *TypeTraitFuncs[GA, Alpha, Beta]) FieldTraits(v meta.Version) *FieldTrai
 	return f.FieldTraitsF(v)
 }
 
+// ComposeTypeTraits returns a TypeTrait that combines traits. This allows a
+// TypeTrait to be assembled from smaller pieces (e.g. a generated trait with
+// the OutputOnly() fields and a hand-written trait with the CopyHelpers).
+//
+// The CopyHelpers of traits are called in order; the first error stops the
+// chain. The FieldTraits are merged with FieldTraits.Merge(), so when traits
+// conflict (e.g. the same field has different FieldTypes), the trait listed
+// first wins.
+func ComposeTypeTraits[GA any, Alpha any, Beta any](traits ...TypeTrait[GA, Alpha, Beta]) TypeTrait[GA, Alpha, Beta] {
+	return &TypeTraitFuncs[GA, Alpha, Beta]{
+		CopyHelperGAtoAlphaF: func(dest *Alpha, src *GA) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperGAtoAlpha(dest, src) })
+		},
+		CopyHelperGAtoBetaF: func(dest *Beta, src *GA) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperGAtoBeta(dest, src) })
+		},
+		CopyHelperAlphaToGAF: func(dest *GA, src *Alpha) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperAlphaToGA(dest, src) })
+		},
+		CopyHelperAlphaToBetaF: func(dest *Beta, src *Alpha) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperAlphaToBeta(dest, src) })
+		},
+		CopyHelperBetaToGAF: func(dest *GA, src *Beta) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperBetaToGA(dest, src) })
+		},
+		CopyHelperBetaToAlphaF: func(dest *Alpha, src *Beta) error {
+			return chainCopyHelpers(traits, func(t TypeTrait[GA, Alpha, Beta]) error { return t.CopyHelperBetaToAlpha(dest, src) })
+		},
+		FieldTraitsF: func(ver meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			for _, t := range traits {
+				dt.Merge(t.FieldTraits(ver))
+			}
+			return dt
+		},
+	}
+}
+
+func chainCopyHelpers[GA any, Alpha any, Beta any](
+	traits []TypeTrait[GA, Alpha, Beta],
+	f func(TypeTrait[GA, Alpha, Beta]) error,
+) error {
+	for _, t := range traits {
+		if err := f(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // FieldTraits are the features and behavior for fields in the resource.
+//
+// The Paths given to FieldTraits may use AnySliceIndex() and AnyMapIndex() to
+// apply the trait to all elements of a slice or map, e.g.
+//
+//	dt.OutputOnly(Path{}.Pointer().Field("Backends").AnySliceIndex().Field("Status"))
 type FieldTraits struct {
-	fields []fieldTrait
+	fields      []fieldTrait
+	keyedSlices []keyedSlice
+	references  []referenceTrait
+	comparators []comparatorTrait
+	converters  []converterTrait
+	validators  []validatorTrait
+	immutable   []Path
+	defaults    []defaultTrait
+	unordered   []Path
+	opaque      []Path
+	enums       []enumTrait
+	adapters    []adapterTrait
+	// informational are the fields whose loss in a conversion from this
+	// version has SeverityInfo.
+	informational []Path
+	// required are the fields that must be set to Freeze() the resource.
+	required []Path
+	// metafields are the MetafieldNames of the structs. nil if the
+	// default is used.
+	metafields *MetafieldNames
+}
+
+// defaultTrait is the value set by the server for the field at path when
+// it is not specified.
+type defaultTrait struct {
+	path  Path
+	value reflect.Value
+}
+
+// validatorTrait is a validation for the field at path.
+type validatorTrait struct {
+	path Path
+	f    ValidateFunc
+}
+
+// enumTrait is the set of allowed values of the string field at path.
+type enumTrait struct {
+	path   Path
+	values []string
+}
+
+// converterTrait is a custom conversion for the field at path when copying
+// to version to.
+type converterTrait struct {
+	to   meta.Version
+	path Path
+	f    ConvertFunc
+}
+
+// adapterTrait converts the field at path between versions where the type of
+// the field differs.
+type adapterTrait struct {
+	path    Path
+	adapter TypeAdapter
+}
+
+// comparatorTrait is a custom comparison for the field at path.
+type comparatorTrait struct {
+	path Path
+	f    CompareFunc
+}
+
+// referenceTrait is a string field that contains the URL of another resource.
+type referenceTrait struct {
+	path     Path
+	resource string
+}
+
+// keyedSlice is a slice of structs where the elements are identified by the
+// value of keyFields instead of their position.
+type keyedSlice struct {
+	path      Path
+	keyFields []string
 }
 
 type fieldTrait struct {
@@ -164,6 +291,116 @@ func (dt *FieldTraits) CheckSchema(t reflect.Type) error {
 			return fmt.Errorf("CheckSchema: %w", err)
 		}
 	}
+	for _, ks := range dt.keyedSlices {
+		st, err := ks.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if st.Kind() != reflect.Slice {
+			return fmt.Errorf("CheckSchema: keyed slice %s is not a slice (%s)", ks.path, st)
+		}
+		et := st.Elem()
+		if et.Kind() == reflect.Pointer {
+			et = et.Elem()
+		}
+		if et.Kind() != reflect.Struct {
+			return fmt.Errorf("CheckSchema: keyed slice %s elements are not structs (%s)", ks.path, st.Elem())
+		}
+		for _, kf := range ks.keyFields {
+			if _, ok := et.FieldByName(kf); !ok {
+				return fmt.Errorf("CheckSchema: keyed slice %s elements do not have key field %q", ks.path, kf)
+			}
+		}
+	}
+	for _, at := range dt.adapters {
+		ft, err := at.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if ft != at.adapter.a && ft != at.adapter.b {
+			return fmt.Errorf("CheckSchema: adapter for %s converts %s and %s, field has type %s", at.path, at.adapter.a, at.adapter.b, ft)
+		}
+		if at.path[len(at.path)-1][0] != pathField {
+			return fmt.Errorf("CheckSchema: adapter path %s is not a field reference", at.path)
+		}
+	}
+	for _, e := range dt.enums {
+		ft, err := e.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if ft.Kind() != reflect.String {
+			return fmt.Errorf("CheckSchema: enum %s is not a string (%s)", e.path, ft)
+		}
+	}
+	for _, p := range dt.opaque {
+		if _, err := p.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, p := range dt.required {
+		if p[len(p)-1][0] != pathField {
+			return fmt.Errorf("CheckSchema: required path %s is not a field reference", p)
+		}
+		if _, err := p.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, p := range dt.informational {
+		if _, err := p.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, p := range dt.unordered {
+		st, err := p.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if st.Kind() != reflect.Slice {
+			return fmt.Errorf("CheckSchema: unordered slice %s is not a slice (%s)", p, st)
+		}
+	}
+	for _, c := range dt.comparators {
+		if _, err := c.path.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, p := range dt.immutable {
+		if _, err := p.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, d := range dt.defaults {
+		ft, err := d.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if d.value.Type() != ft && (ft.Kind() != reflect.Pointer || d.value.Type() != ft.Elem()) {
+			return fmt.Errorf("CheckSchema: server default for %s has type %s, want %s", d.path, d.value.Type(), ft)
+		}
+	}
+	for _, vt := range dt.validators {
+		if _, err := vt.path.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+	}
+	for _, c := range dt.converters {
+		if _, err := c.path.ResolveType(t); err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if c.path[len(c.path)-1][0] != pathField {
+			return fmt.Errorf("CheckSchema: converter path %s is not a field reference", c.path)
+		}
+	}
+	for _, r := range dt.references {
+		ft, err := r.path.ResolveType(t)
+		if err != nil {
+			return fmt.Errorf("CheckSchema: %w", err)
+		}
+		if ft.Kind() != reflect.String {
+			return fmt.Errorf("CheckSchema: reference %s is not a string (%s)", r.path, ft)
+		}
+	}
 	return nil
 }
 
@@ -174,17 +411,351 @@ func (dt *FieldTraits) add(p Path, t FieldType) {
 // OutputOnly specifies the type of the given path.
 func (dt *FieldTraits) OutputOnly(p Path) { dt.add(p, FieldTypeOutputOnly) }
 
+// OutputOnlyBuiltins marks the output-only fields that are common to the
+// compute resources (CreationTimestamp, Id, Kind and SelfLink).
+func (dt *FieldTraits) OutputOnlyBuiltins() {
+	for _, f := range []string{"CreationTimestamp", "Id", "Kind", "SelfLink"} {
+		dt.OutputOnly(Path{}.Pointer().Field(f))
+	}
+}
+
 // System specifies the type of the given path.
 func (dt *FieldTraits) System(p Path) { dt.add(p, FieldTypeSystem) }
 
 // AllowZeroValue specifies the type of the given path.
 func (dt *FieldTraits) AllowZeroValue(p Path) { dt.add(p, FieldTypeAllowZeroValue) }
 
+// KeyedSlice specifies that the elements of the slice of structs at p are
+// identified by the values of the keyFields (e.g. BackendService.Backends is
+// keyed by "Group"). Diffs of keyed slices ignore the order of the elements
+// and compare the elements with the same key. When copying between versions,
+// fields of an element that do not exist in the source version are kept
+// from the element with the same key. See UnorderedSlice() for slices without
+// key fields.
+func (dt *FieldTraits) KeyedSlice(p Path, keyFields ...string) {
+	dt.keyedSlices = append(dt.keyedSlices, keyedSlice{path: p, keyFields: keyFields})
+}
+
+// UnorderedSlice specifies that the slice at p is a set: the order of the
+// elements is not significant (e.g. the URLs in HealthChecks). Diffs of
+// unordered slices only report a difference if the elements differ. Use
+// KeyedSlice() instead for slices of structs where elements can be matched
+// by key fields.
+func (dt *FieldTraits) UnorderedSlice(p Path) {
+	dt.unordered = append(dt.unordered, p)
+}
+
+// Reference specifies that the string field at p contains the URL of a
+// resource of the given type (e.g. "healthChecks"). p may use AnySliceIndex()
+// to match all elements of a slice, e.g.
+//
+//	dt.Reference(Path{}.Pointer().Field("HealthChecks").AnySliceIndex(), "healthChecks")
+//
+// The references in a resource are returned by Resource.References().
+func (dt *FieldTraits) Reference(p Path, resource string) {
+	dt.references = append(dt.references, referenceTrait{path: p, resource: resource})
+}
+
+// Compare sets a custom comparison for the field at p used by Diff. This is
+// used for fields where the API canonicalizes the value that was sent (e.g.
+// "10s" is returned as "10.000s"). p may use AnySliceIndex() to match all
+// elements of a slice.
+func (dt *FieldTraits) Compare(p Path, f CompareFunc) {
+	dt.comparators = append(dt.comparators, comparatorTrait{path: p, f: f})
+}
+
+// Convert sets a custom conversion for the field at p used when copying from
+// the version of these traits to version to. f replaces the default copy of
+// the field. This is used for fields whose representation changed between
+// versions (e.g. a renamed field or different enum values). p may use
+// AnySliceIndex() to match all elements of a slice.
+//
+//	// "Mode" was renamed to "BalancingMode" in Alpha.
+//	gaTraits.Convert(meta.VersionAlpha, Path{}.Pointer().Field("Mode"), ConvertRename("BalancingMode"))
+func (dt *FieldTraits) Convert(to meta.Version, p Path, f ConvertFunc) {
+	dt.converters = append(dt.converters, converterTrait{to: to, path: p, f: f})
+}
+
+// Adapt specifies that the type of the field at p differs between versions
+// and a is used to convert the value when copying between versions with
+// different types. Versions where the field has the same type are copied as
+// usual. The same Adapt() is typically registered in the FieldTraits of all
+// versions. p must be a field reference; it may use AnySliceIndex() for
+// fields of slice elements.
+//
+//	dt.Adapt(Path{}.Pointer().Field("Port"), NewTypeAdapter(itoa, atoi))
+func (dt *FieldTraits) Adapt(p Path, a TypeAdapter) {
+	dt.adapters = append(dt.adapters, adapterTrait{path: p, adapter: a})
+}
+
+// Validate adds a validation for the field at p that is run after an
+// Access(). p may use AnySliceIndex() to match all elements of a slice.
+//
+//	dt.Validate(Path{}.Pointer().Field("Port"), ValidateRange(1, 65535))
+//
+// Package celrule (a separate module) implements ValidateFuncs with CEL
+// expressions.
+func (dt *FieldTraits) Validate(p Path, f ValidateFunc) {
+	dt.validators = append(dt.validators, validatorTrait{path: p, f: f})
+}
+
+// Enum specifies the allowed values of the string field at p. The value is
+// validated after an Access() (see Validate()). The FieldTraits are per
+// version, so versions that accept additional values (e.g. Alpha) can have a
+// longer list. p may use AnySliceIndex() to match all elements of a slice.
+//
+//	dt.Enum(Path{}.Pointer().Field("BalancingMode"), "RATE", "UTILIZATION", "CONNECTION")
+func (dt *FieldTraits) Enum(p Path, values ...string) {
+	values = append([]string{}, values...)
+	dt.enums = append(dt.enums, enumTrait{path: p, values: values})
+	dt.Validate(p, ValidateOneOf(values...))
+}
+
+// Immutable specifies that the field at p cannot be changed without
+// recreating the resource. Diffs of the field are marked with
+// DiffItem.Immutable. p may use AnySliceIndex() and AnyMapIndex().
+func (dt *FieldTraits) Immutable(p Path) {
+	dt.immutable = append(dt.immutable, p)
+}
+
+// Required specifies that the field at p must be set (non-zero or in the
+// ForceSendFields) in the version of the resource returned by Freeze(), e.g.
+// BackendService.LoadBalancingScheme. Fields in structs that are not present
+// (e.g. behind a nil pointer) are not checked. p must be a field reference;
+// it may use AnySliceIndex() and AnyMapIndex().
+func (dt *FieldTraits) Required(p Path) {
+	dt.required = append(dt.required, p)
+}
+
+// Opaque specifies that the field at p is treated as a single value: the
+// schema checks do not descend into it and Diff() compares it as a whole.
+// This is used to break cycles in recursive types (e.g. a struct with a
+// pointer to a struct of the same type), which are otherwise rejected by
+// CheckSchema().
+func (dt *FieldTraits) Opaque(p Path) {
+	dt.opaque = append(dt.opaque, p)
+}
+
+// Informational specifies that the loss of the field at p when converting
+// from this version to a version without the field is acceptable (e.g. an
+// output-only Alpha field that does not exist in GA). The MissingFields for
+// the field have SeverityInfo and do not fail StrictConversion(). To*() still
+// return a ConversionError; use ConversionError.Errors() or
+// ConversionError.Informational() to ignore the informational losses. p may
+// use AnySliceIndex() and AnyMapIndex().
+func (dt *FieldTraits) Informational(p Path) {
+	dt.informational = append(dt.informational, p)
+}
+
+// ServerDefault specifies the value the server sets for the field at p when
+// it is not specified (i.e. is the zero value). Resource.Diff() and
+// Resource.Hash() treat a zero-valued field as having the default value, so
+// a resource where the field is unset does not differ from the server copy
+// where it has been filled in. value must be of the type of the field or the
+// element type for pointer fields.
+func (dt *FieldTraits) ServerDefault(p Path, value any) {
+	dt.defaults = append(dt.defaults, defaultTrait{path: p, value: reflect.ValueOf(value)})
+}
+
+// Metafields sets the names of the metafields of the API structs. The default
+// is DefaultMetafieldNames(). Use MetafieldNames{} for structs from SDKs
+// without metafields. This is usually set for all versions of a resource,
+// e.g. with WithMetafieldNames().
+func (dt *FieldTraits) Metafields(n MetafieldNames) {
+	dt.metafields = &n
+}
+
+// metafieldNames returns the names set by Metafields() or the default.
+func (dt *FieldTraits) metafieldNames() MetafieldNames {
+	if dt == nil || dt.metafields == nil {
+		return DefaultMetafieldNames()
+	}
+	return *dt.metafields
+}
+
 // Clone create an exact copy of the traits.
 func (dt *FieldTraits) Clone() *FieldTraits {
-	return &FieldTraits{
+	ret := &FieldTraits{
 		fields: append([]fieldTrait{}, dt.fields...),
 	}
+	if dt.keyedSlices != nil {
+		ret.keyedSlices = append([]keyedSlice{}, dt.keyedSlices...)
+	}
+	if dt.references != nil {
+		ret.references = append([]referenceTrait{}, dt.references...)
+	}
+	if dt.comparators != nil {
+		ret.comparators = append([]comparatorTrait{}, dt.comparators...)
+	}
+	if dt.converters != nil {
+		ret.converters = append([]converterTrait{}, dt.converters...)
+	}
+	if dt.validators != nil {
+		ret.validators = append([]validatorTrait{}, dt.validators...)
+	}
+	if dt.immutable != nil {
+		ret.immutable = append([]Path{}, dt.immutable...)
+	}
+	if dt.unordered != nil {
+		ret.unordered = append([]Path{}, dt.unordered...)
+	}
+	if dt.opaque != nil {
+		ret.opaque = append([]Path{}, dt.opaque...)
+	}
+	if dt.enums != nil {
+		ret.enums = append([]enumTrait{}, dt.enums...)
+	}
+	if dt.adapters != nil {
+		ret.adapters = append([]adapterTrait{}, dt.adapters...)
+	}
+	if dt.defaults != nil {
+		ret.defaults = append([]defaultTrait{}, dt.defaults...)
+	}
+	if dt.informational != nil {
+		ret.informational = append([]Path{}, dt.informational...)
+	}
+	if dt.required != nil {
+		ret.required = append([]Path{}, dt.required...)
+	}
+	if dt.metafields != nil {
+		mf := *dt.metafields
+		ret.metafields = &mf
+	}
+	return ret
+}
+
+// Merge adds the traits from other to dt. Traits already in dt take
+// precedence over the ones from other when they apply to the same path.
+// other may be nil.
+func (dt *FieldTraits) Merge(other *FieldTraits) {
+	if other == nil {
+		return
+	}
+	o := other.Clone()
+	dt.fields = append(dt.fields, o.fields...)
+	dt.keyedSlices = append(dt.keyedSlices, o.keyedSlices...)
+	dt.references = append(dt.references, o.references...)
+	dt.comparators = append(dt.comparators, o.comparators...)
+	dt.converters = append(dt.converters, o.converters...)
+	dt.validators = append(dt.validators, o.validators...)
+	dt.immutable = append(dt.immutable, o.immutable...)
+	dt.defaults = append(dt.defaults, o.defaults...)
+	dt.unordered = append(dt.unordered, o.unordered...)
+	dt.opaque = append(dt.opaque, o.opaque...)
+	dt.enums = append(dt.enums, o.enums...)
+	dt.adapters = append(dt.adapters, o.adapters...)
+	dt.informational = append(dt.informational, o.informational...)
+	dt.required = append(dt.required, o.required...)
+	if dt.metafields == nil {
+		dt.metafields = o.metafields
+	}
+}
+
+// keyFields returns the key fields for the slice at p. Returns nil if the
+// slice is not keyed.
+func (dt *FieldTraits) keyFields(p Path) []string {
+	for _, ks := range dt.keyedSlices {
+		if p.Match(ks.path) {
+			return ks.keyFields
+		}
+	}
+	return nil
+}
+
+// isUnordered returns true if p is an UnorderedSlice().
+func (dt *FieldTraits) isUnordered(p Path) bool {
+	for _, up := range dt.unordered {
+		if p.Match(up) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired returns true if p is Required().
+func (dt *FieldTraits) isRequired(p Path) bool {
+	for _, rp := range dt.required {
+		if p.Match(rp) {
+			return true
+		}
+	}
+	return false
+}
+
+// isOpaque returns true if p is Opaque().
+func (dt *FieldTraits) isOpaque(p Path) bool {
+	for _, op := range dt.opaque {
+		if p.Match(op) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparator returns the custom CompareFunc for p. Returns nil if there is
+// none.
+func (dt *FieldTraits) comparator(p Path) CompareFunc {
+	for _, c := range dt.comparators {
+		if p.Match(c.path) {
+			return c.f
+		}
+	}
+	return nil
+}
+
+// isImmutable returns true if p is at or below an Immutable() field.
+func (dt *FieldTraits) isImmutable(p Path) bool {
+	for _, ip := range dt.immutable {
+		if p.MatchPrefix(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// withServerDefault returns the ServerDefault() for p if v is the zero
+// value, otherwise v is returned.
+func (dt *FieldTraits) withServerDefault(p Path, v reflect.Value) reflect.Value {
+	if !v.IsValid() || !v.IsZero() {
+		return v
+	}
+	for _, d := range dt.defaults {
+		if !p.Match(d.path) {
+			continue
+		}
+		if v.Kind() == reflect.Pointer && d.value.Type() == v.Type().Elem() {
+			ret := reflect.New(d.value.Type())
+			ret.Elem().Set(d.value)
+			return ret
+		}
+		return d.value
+	}
+	return v
+}
+
+// validatorsFor returns the validations for the field at p.
+func (dt *FieldTraits) validatorsFor(p Path) []ValidateFunc {
+	var ret []ValidateFunc
+	for _, vt := range dt.validators {
+		if p.Match(vt.path) {
+			ret = append(ret, vt.f)
+		}
+	}
+	return ret
+}
+
+// convertersTo returns the converters used when copying to version to.
+func (dt *FieldTraits) convertersTo(to meta.Version) []converterTrait {
+	if dt == nil {
+		return nil
+	}
+	var ret []converterTrait
+	for _, c := range dt.converters {
+		if c.to == to {
+			ret = append(ret, c)
+		}
+	}
+	return ret
 }
 
 func (dt *FieldTraits) fieldType(p Path) FieldType { return dt.fieldTrait(p).fType }
@@ -192,11 +763,20 @@ func (dt *FieldTraits) fieldType(p Path) FieldType { return dt.fieldTrait(p).fTy
 func (dt *FieldTraits) fieldTrait(p Path) fieldTrait {
 	// TODO(bowei): this can be made very efficient with a tree, early bailout
 	// etc.. We will go with a very inefficient implimentation for now.
-	for _, f := range dt.fields {
-		if p.HasPrefix(f.path) {
-			return f
+	//
+	// The most specific (longest) matching path is used so that traits on
+	// the elements of a slice or map (e.g. "Backends!*.Status") take
+	// precedence over traits on the containing field.
+	var ret *fieldTrait
+	for i := range dt.fields {
+		f := &dt.fields[i]
+		if p.MatchPrefix(f.path) && (ret == nil || len(f.path) > len(ret.path)) {
+			ret = f
 		}
 	}
+	if ret != nil {
+		return *ret
+	}
 	return fieldTrait{
 		path:  p,
 		fType: FieldTypeOrdinary,
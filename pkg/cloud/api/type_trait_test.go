@@ -17,9 +17,11 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/kr/pretty"
 )
 
@@ -181,6 +183,26 @@ func TestFieldTraitsCheckSchema(t *testing.T) {
 			ty:      reflect.TypeOf(&st{}),
 			wantErr: true,
 		},
+		{
+			name: "enums",
+			ft: func() *FieldTraits {
+				var ret FieldTraits
+				ret.Enum(Path{}.Pointer().Field("S").Field("L").AnySliceIndex(), "A", "B")
+				ret.Enum(Path{}.Pointer().Field("P").Pointer(), "A", "B")
+				return &ret
+			}(),
+			ty: reflect.TypeOf(&st{}),
+		},
+		{
+			name: "enum is not a string",
+			ft: func() *FieldTraits {
+				var ret FieldTraits
+				ret.Enum(Path{}.Pointer().Field("A"), "A", "B")
+				return &ret
+			}(),
+			ty:      reflect.TypeOf(&st{}),
+			wantErr: true,
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			err := tc.ft.CheckSchema(tc.ty)
@@ -190,3 +212,74 @@ func TestFieldTraitsCheckSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestComposeTypeTraits(t *testing.T) {
+	t.Parallel()
+
+	type st struct {
+		A string
+		B string
+		C int
+	}
+
+	var calls []string
+	t1 := &TypeTraitFuncs[st, st, st]{
+		CopyHelperGAtoAlphaF: func(dest *st, src *st) error {
+			calls = append(calls, "t1")
+			dest.C = 1
+			return nil
+		},
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := NewFieldTraits()
+			dt.OutputOnly(Path{}.Pointer().Field("A"))
+			return dt
+		},
+	}
+	t2 := &TypeTraitFuncs[st, st, st]{
+		CopyHelperGAtoAlphaF: func(dest *st, src *st) error {
+			calls = append(calls, "t2")
+			dest.C++
+			return nil
+		},
+		CopyHelperGAtoBetaF: func(dest *st, src *st) error {
+			return fmt.Errorf("t2 error")
+		},
+		FieldTraitsF: func(meta.Version) *FieldTraits {
+			dt := &FieldTraits{}
+			dt.System(Path{}.Pointer().Field("A"))
+			dt.System(Path{}.Pointer().Field("B"))
+			return dt
+		},
+	}
+	tt := ComposeTypeTraits[st, st, st](t1, &BaseTypeTrait[st, st, st]{}, t2)
+
+	var dest st
+	if err := tt.CopyHelperGAtoAlpha(&dest, &st{}); err != nil {
+		t.Fatalf("CopyHelperGAtoAlpha() = %v, want nil", err)
+	}
+	if !reflect.DeepEqual(calls, []string{"t1", "t2"}) {
+		t.Errorf("calls = %v, want [t1 t2]", calls)
+	}
+	if dest.C != 2 {
+		t.Errorf("dest.C = %d, want 2", dest.C)
+	}
+	if err := tt.CopyHelperGAtoBeta(&dest, &st{}); err == nil {
+		t.Error("CopyHelperGAtoBeta() = nil, want error")
+	}
+
+	dt := tt.FieldTraits(meta.VersionGA)
+	for _, tc := range []struct {
+		p    Path
+		want FieldType
+	}{
+		// t1 is listed first and takes precedence.
+		{Path{}.Pointer().Field("A"), FieldTypeOutputOnly},
+		{Path{}.Pointer().Field("B"), FieldTypeSystem},
+		{Path{}.Pointer().Field("C"), FieldTypeOrdinary},
+		{Path{}.Pointer().Field("ServerResponse"), FieldTypeSystem},
+	} {
+		if got := dt.fieldType(tc.p); got != tc.want {
+			t.Errorf("fieldType(%v) = %v, want %v", tc.p, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+)
+
+// ValidateFunc returns an error if the value of the field is invalid. v is
+// the value of the field. ValidateFuncs are not called for zero-valued
+// fields. See FieldTraits.Validate().
+type ValidateFunc func(v any) error
+
+// ValidateRange checks that an integer field is in the range [min, max].
+func ValidateRange(min, max int64) ValidateFunc {
+	return func(v any) error {
+		rv := reflect.ValueOf(v)
+		var i int64
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = rv.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			i = int64(rv.Uint())
+		default:
+			return fmt.Errorf("%T is not an integer", v)
+		}
+		if i < min || i > max {
+			return fmt.Errorf("%d is not in the range [%d, %d]", i, min, max)
+		}
+		return nil
+	}
+}
+
+// ValidateCIDR checks that a string field is an IP range in CIDR notation
+// (e.g. "10.0.0.0/8").
+func ValidateCIDR() ValidateFunc {
+	return func(v any) error {
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%T is not a string", v)
+		}
+		if _, _, err := net.ParseCIDR(s); err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+// ValidateIP checks that a string field is an IP address.
+func ValidateIP() ValidateFunc {
+	return func(v any) error {
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%T is not a string", v)
+		}
+		if net.ParseIP(s) == nil {
+			return fmt.Errorf("%q is not an IP address", s)
+		}
+		return nil
+	}
+}
+
+// ValidateOneOf checks that a string field has one of the given values, e.g.
+// for enums.
+func ValidateOneOf(values ...string) ValidateFunc {
+	return func(v any) error {
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%T is not a string", v)
+		}
+		for _, x := range values {
+			if s == x {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %q", s, values)
+	}
+}
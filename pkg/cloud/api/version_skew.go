@@ -0,0 +1,243 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// SkewKind is the kind of difference between the versions of a field.
+type SkewKind string
+
+const (
+	// SkewMissing is a field that does not exist in some of the versions
+	// that have the containing struct.
+	SkewMissing SkewKind = "Missing"
+	// SkewTypeDiffers is a field that has a different kind of type in the
+	// versions (e.g. int64 in GA and string in Alpha). These fields need a
+	// TypeAdapter or a CopyHelper to be converted.
+	SkewTypeDiffers SkewKind = "TypeDiffers"
+)
+
+// FieldSkew is a field that differs between the versions of a resource.
+type FieldSkew struct {
+	Kind SkewKind
+	// Path of the field.
+	Path Path
+	// Types of the field in each version that has the field.
+	Types map[meta.Version]reflect.Type
+}
+
+// Versions that have the field, ordered from the most to the least stable.
+func (s *FieldSkew) Versions() []meta.Version {
+	var ret []meta.Version
+	for _, ver := range skewVersions {
+		if _, ok := s.Types[ver]; ok {
+			ret = append(ret, ver)
+		}
+	}
+	return ret
+}
+
+// VersionSkewReport lists the fields that differ between the API versions of
+// a resource type. This can be used to check that the CopyHelpers of a
+// TypeTrait cover the fields that need them and to tell users which features
+// require a non-GA API.
+type VersionSkewReport struct {
+	// Fields that differ between the versions, in the order of the GA,
+	// Beta, and Alpha struct fields.
+	Fields []FieldSkew
+}
+
+// skewVersions in order of stability.
+var skewVersions = []meta.Version{meta.VersionGA, meta.VersionBeta, meta.VersionAlpha}
+
+// NewVersionSkewReport returns the report for the types of a resource. Versions
+// that are NoVersion are not included. The children of a field that is
+// missing in some versions are compared among the versions that have the
+// field. Metafields and ServerResponse (see DefaultMetafieldNames()) are not
+// compared.
+func NewVersionSkewReport[GA any, Alpha any, Beta any]() *VersionSkewReport {
+	types := map[meta.Version]reflect.Type{}
+	for ver, t := range map[meta.Version]reflect.Type{
+		meta.VersionGA:    reflect.TypeOf((*GA)(nil)).Elem(),
+		meta.VersionAlpha: reflect.TypeOf((*Alpha)(nil)).Elem(),
+		meta.VersionBeta:  reflect.TypeOf((*Beta)(nil)).Elem(),
+	} {
+		if !isNoVersion(t) {
+			types[ver] = t
+		}
+	}
+	sc := skewChecker{names: DefaultMetafieldNames(), report: &VersionSkewReport{}}
+	sc.do(Path{}.Pointer(), types)
+	return sc.report
+}
+
+// OnlyIn returns the fields that exist exactly in the versions vers.
+func (r *VersionSkewReport) OnlyIn(vers ...meta.Version) []Path {
+	var ret []Path
+	for _, fs := range r.Fields {
+		if fs.Kind != SkewMissing || len(fs.Types) != len(vers) {
+			continue
+		}
+		ok := true
+		for _, ver := range vers {
+			if _, has := fs.Types[ver]; !has {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ret = append(ret, fs.Path)
+		}
+	}
+	return ret
+}
+
+// TypeDiffers returns the fields that have different types in the versions.
+func (r *VersionSkewReport) TypeDiffers() []Path {
+	var ret []Path
+	for _, fs := range r.Fields {
+		if fs.Kind == SkewTypeDiffers {
+			ret = append(ret, fs.Path)
+		}
+	}
+	return ret
+}
+
+// String returns a human readable report with a line for each field.
+func (r *VersionSkewReport) String() string {
+	var sb strings.Builder
+	for _, fs := range r.Fields {
+		var vts []string
+		for _, ver := range fs.Versions() {
+			vts = append(vts, fmt.Sprintf("%s=%s", ver, fs.Types[ver]))
+		}
+		fmt.Fprintf(&sb, "%s %s: %s\n", fs.Kind, fs.Path, strings.Join(vts, ", "))
+	}
+	return sb.String()
+}
+
+type skewChecker struct {
+	names  MetafieldNames
+	report *VersionSkewReport
+	// stack of the struct types on the current path, used to stop at
+	// recursive types.
+	stack []map[meta.Version]reflect.Type
+}
+
+func (sc *skewChecker) add(kind SkewKind, p Path, types map[meta.Version]reflect.Type) {
+	sc.report.Fields = append(sc.report.Fields, FieldSkew{
+		Kind:  kind,
+		Path:  append(Path{}, p...),
+		Types: types,
+	})
+}
+
+// do compares the types of the field at p in each version.
+func (sc *skewChecker) do(p Path, types map[meta.Version]reflect.Type) {
+	var kind reflect.Kind
+	for _, ver := range skewVersions {
+		t, ok := types[ver]
+		if !ok {
+			continue
+		}
+		if kind == reflect.Invalid {
+			kind = t.Kind()
+		} else if t.Kind() != kind {
+			sc.add(SkewTypeDiffers, p, types)
+			return
+		}
+	}
+
+	elems := func() map[meta.Version]reflect.Type {
+		ret := map[meta.Version]reflect.Type{}
+		for ver, t := range types {
+			ret[ver] = t.Elem()
+		}
+		return ret
+	}
+	switch kind {
+	case reflect.Pointer:
+		sc.do(p.Pointer(), elems())
+	case reflect.Slice:
+		sc.do(p.AnySliceIndex(), elems())
+	case reflect.Map:
+		var keyKind reflect.Kind
+		for _, t := range types {
+			if keyKind != reflect.Invalid && t.Key().Kind() != keyKind {
+				sc.add(SkewTypeDiffers, p, types)
+				return
+			}
+			keyKind = t.Key().Kind()
+		}
+		sc.do(p.AnyMapIndex(), elems())
+	case reflect.Struct:
+		sc.doStruct(p, types)
+	}
+}
+
+func (sc *skewChecker) doStruct(p Path, types map[meta.Version]reflect.Type) {
+	for _, prev := range sc.stack {
+		same := len(prev) == len(types)
+		for ver, t := range types {
+			same = same && prev[ver] == t
+		}
+		if same {
+			return
+		}
+	}
+	sc.stack = append(sc.stack, types)
+	defer func() { sc.stack = sc.stack[:len(sc.stack)-1] }()
+
+	// Field names in the order of the struct fields in each version.
+	var fieldNames []string
+	seen := map[string]bool{}
+	for _, ver := range skewVersions {
+		t, ok := types[ver]
+		if !ok {
+			continue
+		}
+		for i := 0; i < t.NumField(); i++ {
+			fn := t.Field(i).Name
+			if seen[fn] || sc.names.isMetafield(fn) {
+				continue
+			}
+			if fn == sc.names.ServerResponse && p.Equal(Path{}.Pointer()) {
+				continue
+			}
+			seen[fn] = true
+			fieldNames = append(fieldNames, fn)
+		}
+	}
+	for _, fn := range fieldNames {
+		ft := map[meta.Version]reflect.Type{}
+		for ver, t := range types {
+			if f, ok := t.FieldByName(fn); ok {
+				ft[ver] = f.Type
+			}
+		}
+		if len(ft) < len(types) {
+			sc.add(SkewMissing, p.Field(fn), ft)
+		}
+		sc.do(p.Field(fn), ft)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVersionSkewReport(t *testing.T) {
+	t.Parallel()
+
+	type gaBackend struct {
+		Group           string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type betaBackend struct {
+		Group           string
+		Weight          int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type ga struct {
+		Name            string
+		Port            int64
+		Backends        []*gaBackend
+		ServerResponse  struct{}
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type beta struct {
+		Name            string
+		Port            int64
+		Backends        []*betaBackend
+		BetaOnly        string
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type alpha struct {
+		Name            string
+		Port            string
+		Backends        []*betaBackend
+		BetaOnly        string
+		AlphaOnly       map[string]int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	r := NewVersionSkewReport[ga, alpha, beta]()
+
+	weight := Path{}.Pointer().Field("Backends").AnySliceIndex().Pointer().Field("Weight")
+	if diff := cmp.Diff(r.OnlyIn(meta.VersionAlpha, meta.VersionBeta), []Path{
+		weight,
+		Path{}.Pointer().Field("BetaOnly"),
+	}); diff != "" {
+		t.Errorf("OnlyIn(alpha, beta); -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(r.OnlyIn(meta.VersionAlpha), []Path{Path{}.Pointer().Field("AlphaOnly")}); diff != "" {
+		t.Errorf("OnlyIn(alpha); -got,+want: %s", diff)
+	}
+	if diff := cmp.Diff(r.TypeDiffers(), []Path{Path{}.Pointer().Field("Port")}); diff != "" {
+		t.Errorf("TypeDiffers(); -got,+want: %s", diff)
+	}
+	for _, fs := range r.Fields {
+		if fs.Path.Equal(weight) {
+			if diff := cmp.Diff(fs.Versions(), []meta.Version{meta.VersionBeta, meta.VersionAlpha}); diff != "" {
+				t.Errorf("Versions(); -got,+want: %s", diff)
+			}
+			if fs.Types[meta.VersionAlpha] != reflect.TypeOf(0) {
+				t.Errorf("Types[alpha] = %v, want int", fs.Types[meta.VersionAlpha])
+			}
+		}
+	}
+	if s := r.String(); !strings.Contains(s, "TypeDiffers *.Port: ga=int64, beta=int64, alpha=string") {
+		t.Errorf("String() = %q, want a line for *.Port", s)
+	}
+
+	// Only the available versions are compared.
+	r = NewVersionSkewReport[NoVersion, alpha, beta]()
+	if got := r.OnlyIn(meta.VersionAlpha, meta.VersionBeta); got != nil {
+		t.Errorf("OnlyIn(alpha, beta) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// VersionDescriptor describes one typed version of a resource in a
+// VersionSet. Use NewVersion() to construct.
+type VersionDescriptor struct {
+	// Version is the API version of the type.
+	Version meta.Version
+	// Type is the struct type (not a pointer) for the version.
+	Type reflect.Type
+	// FieldTraits for the version. If nil, an empty FieldTraits is used.
+	FieldTraits *FieldTraits
+}
+
+// NewVersion returns a descriptor for version ver with the type T. traits may
+// be nil.
+func NewVersion[T any](ver meta.Version, traits *FieldTraits) VersionDescriptor {
+	return VersionDescriptor{
+		Version:     ver,
+		Type:        reflect.TypeOf((*T)(nil)).Elem(),
+		FieldTraits: traits,
+	}
+}
+
+// VersionSet wraps an arbitrary number of typed versions of a resource. It is
+// the generalization of MutableResource, which is limited to the GA, Alpha
+// and Beta versions. Changes made to one version with Access() are
+// propagated to all of the other versions and fields that cannot be
+// represented in a version are returned as a ConversionError from To().
+//
+// Use the typed helpers AccessVersion() and ToVersion() to work with the
+// concrete types:
+//
+//	vs, err := NewVersionSet(id,
+//	  NewVersion[v1.Foo]("v1", nil),
+//	  NewVersion[v1beta2.Foo]("v1beta2", nil),
+//	  NewVersion[v1alpha3.Foo]("v1alpha3", nil),
+//	)
+//	err = AccessVersion(vs, "v1beta2", func(x *v1beta2.Foo) { ... })
+//	obj, err := ToVersion[v1.Foo](vs, "v1")
+type VersionSet struct {
+	copierOptions []copierOption
+
+	resourceID *cloud.ResourceID
+	// versions in order of preference. See ImpliedVersion().
+	versions []VersionDescriptor
+	// objs are pointers to the object for each version.
+	objs map[meta.Version]reflect.Value
+	// errors are the missing fields, indexed by [from][to].
+	errors map[meta.Version]map[meta.Version]conversionErrors
+
+	// strictVersion is the version for strict conversion. Empty if strict
+	// conversion is not enabled.
+	strictVersion meta.Version
+}
+
+// NewVersionSet constructs a new VersionSet for the given versions. The order
+// of the versions gives the preference used by ImpliedVersion(); the most
+// stable version should be listed first.
+func NewVersionSet(resourceID *cloud.ResourceID, versions ...VersionDescriptor) (*VersionSet, error) {
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("NewVersionSet: no versions given")
+	}
+	vs := &VersionSet{
+		resourceID: resourceID,
+		objs:       map[meta.Version]reflect.Value{},
+		errors:     map[meta.Version]map[meta.Version]conversionErrors{},
+	}
+	for _, vd := range versions {
+		if vd.Type == nil || vd.Type.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("NewVersionSet: version %q: type %v is not a struct", vd.Version, vd.Type)
+		}
+		if _, ok := vs.objs[vd.Version]; ok {
+			return nil, fmt.Errorf("NewVersionSet: duplicate version %q", vd.Version)
+		}
+		if vd.FieldTraits == nil {
+			vd.FieldTraits = &FieldTraits{}
+		}
+		obj := reflect.New(vd.Type)
+		// Set .Name from the ResourceID.
+		if resourceID != nil && resourceID.Key != nil {
+			if ft, ok := vd.Type.FieldByName("Name"); ok && ft.Type.Kind() == reflect.String {
+				obj.Elem().FieldByName("Name").SetString(resourceID.Key.Name)
+			}
+		}
+		vs.versions = append(vs.versions, vd)
+		vs.objs[vd.Version] = obj
+		vs.errors[vd.Version] = map[meta.Version]conversionErrors{}
+	}
+	return vs, nil
+}
+
+// CheckSchema should be called in init() to ensure that the types being
+// wrapped meet the assumptions we are making for the transformations to work.
+func (vs *VersionSet) CheckSchema() error {
+	for _, vd := range vs.versions {
+		if err := checkSchema(reflect.PointerTo(vd.Type), vd.FieldTraits); err != nil {
+			return fmt.Errorf("version %q: %w", vd.Version, err)
+		}
+		if err := vd.FieldTraits.CheckSchema(reflect.PointerTo(vd.Type)); err != nil {
+			return fmt.Errorf("version %q: %w", vd.Version, err)
+		}
+	}
+	return nil
+}
+
+// ResourceID is the resource ID of this resource.
+func (vs *VersionSet) ResourceID() *cloud.ResourceID { return vs.resourceID }
+
+// Versions returns the versions in the set, in order of preference.
+func (vs *VersionSet) Versions() []meta.Version {
+	var ret []meta.Version
+	for _, vd := range vs.versions {
+		ret = append(ret, vd.Version)
+	}
+	return ret
+}
+
+// StrictConversion enables fail-fast conversion: Access() and Set() return a
+// *MissingFieldError if a field cannot be represented in version ver. See
+// MutableResource.StrictConversion().
+func (vs *VersionSet) StrictConversion(ver meta.Version) {
+	vs.strictVersion = ver
+}
+
+// Access the object for version ver. f is called with a pointer to the object
+// (e.g. *v1.Foo). If the resource is invalid after f returns, a
+// *ValidationError listing all of the invalid fields is returned.
+func (vs *VersionSet) Access(ver meta.Version, f func(x any)) error {
+	obj, err := vs.obj(ver)
+	if err != nil {
+		return err
+	}
+	f(obj.Interface())
+	return vs.postAccess(ver, 0)
+}
+
+// Set the value of version ver to src, which must be a pointer to the type for
+// the version. This skips some of the field validation in Access so should
+// only be used with a valid object returned from GCE.
+func (vs *VersionSet) Set(ver meta.Version, src any) error {
+	obj, err := vs.obj(ver)
+	if err != nil {
+		return err
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type() != obj.Type() {
+		return fmt.Errorf("Set(%q): invalid type %T, want %v", ver, src, obj.Type())
+	}
+	if err := newCopier(vs.copierOptions...).do(obj, sv); err != nil {
+		return err
+	}
+	return vs.postAccess(ver, postAccessSkipValidation)
+}
+
+// To returns a pointer to the object for version ver. Use errors.As
+// ConversionError to get the details on fields that could not be represented
+// in the version.
+func (vs *VersionSet) To(ver meta.Version) (any, error) {
+	obj, err := vs.obj(ver)
+	if err != nil {
+		return nil, err
+	}
+	var errs ConversionError
+	for _, vd := range vs.versions {
+		if vd.Version == ver {
+			continue
+		}
+		for _, mf := range vs.errors[vd.Version][ver].missingFields {
+			errs.MissingFields = append(errs.MissingFields, MissingField{
+				Context:  VersionSetConversion,
+				From:     vd.Version,
+				To:       ver,
+				Path:     mf.Path,
+				Value:    mf.Value,
+				Severity: mf.Severity,
+			})
+		}
+	}
+	if errs.hasErr() {
+		return obj.Interface(), &errs
+	}
+	return obj.Interface(), nil
+}
+
+// ImpliedVersion returns the best API version for the set of fields in the
+// resource. The first version is returned if all versions can represent the
+// fields. Otherwise, the version is the single version that can represent all
+// of the fields. It is an error if this is ambiguous.
+func (vs *VersionSet) ImpliedVersion() (meta.Version, error) {
+	errs := map[meta.Version]error{}
+	var ok []meta.Version
+	for _, vd := range vs.versions {
+		if _, err := vs.To(vd.Version); err != nil {
+			errs[vd.Version] = err
+		} else {
+			ok = append(ok, vd.Version)
+		}
+	}
+	first := vs.versions[0].Version
+	switch {
+	case len(ok) == len(vs.versions):
+		return first, nil
+	case len(ok) == 1:
+		return ok[0], nil
+	default:
+		return first, fmt.Errorf("indeterminant version (%v)", errs)
+	}
+}
+
+func (vs *VersionSet) obj(ver meta.Version) (reflect.Value, error) {
+	obj, ok := vs.objs[ver]
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("version %q is not in the VersionSet (versions: %v)", ver, vs.Versions())
+	}
+	return obj, nil
+}
+
+func (vs *VersionSet) postAccess(srcVer meta.Version, flags int) error {
+	src := vs.objs[srcVer]
+
+	var srcTraits *FieldTraits
+	for _, vd := range vs.versions {
+		if vd.Version == srcVer {
+			srcTraits = vd.FieldTraits
+		}
+	}
+
+	if flags&postAccessSkipValidation == 0 {
+		if err := checkPostAccess(srcTraits, src); err != nil {
+			reportValidationError(resourceKind(vs.resourceID), srcVer, err)
+			return err
+		}
+	}
+	for _, vd := range vs.versions {
+		if vd.Version == srcVer {
+			continue
+		}
+		opts := append([]copierOption{}, vs.copierOptions...)
+		if vd.Version == vs.strictVersion {
+			opts = append(opts, copierStrict())
+		}
+		if converters := srcTraits.convertersTo(vd.Version); converters != nil {
+			opts = append(opts, copierConverters(converters))
+		}
+		if srcTraits != nil && srcTraits.adapters != nil {
+			opts = append(opts, copierAdapters(srcTraits.adapters))
+		}
+		if srcTraits != nil && srcTraits.informational != nil {
+			opts = append(opts, copierInformational(srcTraits.informational))
+		}
+		if srcTraits != nil && srcTraits.keyedSlices != nil {
+			opts = append(opts, copierKeyedSlices(srcTraits.keyedSlices))
+		}
+		opts = append(opts, copierMetafields(srcTraits.metafieldNames()))
+		c := newCopier(opts...)
+		if err := c.do(vs.objs[vd.Version], src); err != nil {
+			reportCopyError(resourceKind(vs.resourceID), VersionSetConversion, err)
+			return err
+		}
+		vs.errors[srcVer][vd.Version] = conversionErrors{missingFields: c.missing}
+		reportMissingFields(resourceKind(vs.resourceID), VersionSetConversion, c.missing)
+	}
+	return nil
+}
+
+// AccessVersion is a typed wrapper around VersionSet.Access(). It is an error
+// if T is not the type for version ver.
+func AccessVersion[T any](vs *VersionSet, ver meta.Version, f func(x *T)) error {
+	obj, err := vs.obj(ver)
+	if err != nil {
+		return err
+	}
+	if _, ok := obj.Interface().(*T); !ok {
+		return fmt.Errorf("AccessVersion(%q): invalid type %T, want %v", ver, (*T)(nil), obj.Type())
+	}
+	return vs.Access(ver, func(x any) { f(x.(*T)) })
+}
+
+// ToVersion is a typed wrapper around VersionSet.To(). It is an error if T is
+// not the type for version ver.
+func ToVersion[T any](vs *VersionSet, ver meta.Version) (*T, error) {
+	x, err := vs.To(ver)
+	if x == nil {
+		return nil, err
+	}
+	ret, ok := x.(*T)
+	if !ok {
+		return nil, fmt.Errorf("ToVersion(%q): invalid type %T, want %T", ver, (*T)(nil), x)
+	}
+	return ret, err
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVersionSet(t *testing.T) {
+	t.Parallel()
+
+	type v1 struct {
+		Name            string
+		SelfLink        string
+		A               int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type v2 struct {
+		Name            string
+		SelfLink        string
+		A, B            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type v3 struct {
+		Name            string
+		SelfLink        string
+		A, B, C         int
+		NullFields      []string
+		ForceSendFields []string
+	}
+	type v4 struct {
+		Name            string
+		SelfLink        string
+		A, C            int
+		NullFields      []string
+		ForceSendFields []string
+	}
+
+	// Zero values are allowed to simplify the test cases.
+	traits := func(fields ...string) *FieldTraits {
+		dt := &FieldTraits{}
+		dt.OutputOnly(Path{}.Pointer().Field("SelfLink"))
+		for _, f := range fields {
+			dt.AllowZeroValue(Path{}.Pointer().Field(f))
+		}
+		return dt
+	}
+
+	id := &cloud.ResourceID{Resource: "foos", Key: meta.GlobalKey("foo")}
+	newVS := func(t *testing.T) *VersionSet {
+		vs, err := NewVersionSet(id,
+			NewVersion[v1]("v1", traits("A")),
+			NewVersion[v2]("v2", traits("A", "B")),
+			NewVersion[v3]("v3", traits("A", "B", "C")),
+			NewVersion[v4]("v4", traits("A", "C")),
+		)
+		if err != nil {
+			t.Fatalf("NewVersionSet() = %v, want nil", err)
+		}
+		if err := vs.CheckSchema(); err != nil {
+			t.Fatalf("CheckSchema() = %v, want nil", err)
+		}
+		return vs
+	}
+
+	t.Run("propagate", func(t *testing.T) {
+		vs := newVS(t)
+		if err := AccessVersion(vs, "v2", func(x *v2) { x.A = 10 }); err != nil {
+			t.Fatalf("AccessVersion(v2) = %v, want nil", err)
+		}
+		for _, tc := range []struct {
+			ver  meta.Version
+			want any
+		}{
+			{"v1", &v1{Name: "foo", A: 10}},
+			{"v2", &v2{Name: "foo", A: 10}},
+			{"v3", &v3{Name: "foo", A: 10}},
+			{"v4", &v4{Name: "foo", A: 10}},
+		} {
+			got, err := vs.To(tc.ver)
+			if err != nil {
+				t.Errorf("To(%q) = _, %v; want nil", tc.ver, err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("To(%q); -got,+want: %s", tc.ver, diff)
+			}
+		}
+		if ver, err := vs.ImpliedVersion(); err != nil || ver != "v1" {
+			t.Errorf("ImpliedVersion() = %q, %v; want v1, nil", ver, err)
+		}
+	})
+
+	t.Run("missing fields", func(t *testing.T) {
+		vs := newVS(t)
+		// C is only in v3 and v4, B is only in v2 and v3.
+		if err := AccessVersion(vs, "v3", func(x *v3) { x.B = 1; x.C = 2 }); err != nil {
+			t.Fatalf("AccessVersion(v3) = %v, want nil", err)
+		}
+		got, err := ToVersion[v4](vs, "v4")
+		if diff := cmp.Diff(got, &v4{Name: "foo", C: 2}); diff != "" {
+			t.Errorf("ToVersion(v4); -got,+want: %s", diff)
+		}
+		var cerr *ConversionError
+		if !errors.As(err, &cerr) {
+			t.Fatalf("ToVersion(v4) = _, %v; want ConversionError", err)
+		}
+		wantMF := []MissingField{{Context: VersionSetConversion, From: "v3", To: "v4", Path: Path{}.Pointer().Field("B"), Value: 1}}
+		if diff := cmp.Diff(cerr.MissingFields, wantMF); diff != "" {
+			t.Errorf("MissingFields; -got,+want: %s", diff)
+		}
+		if _, err := ToVersion[v1](vs, "v1"); err == nil {
+			t.Error("ToVersion(v1) = _, nil; want error")
+		}
+		if _, err := ToVersion[v3](vs, "v3"); err != nil {
+			t.Errorf("ToVersion(v3) = _, %v; want nil", err)
+		}
+		if ver, err := vs.ImpliedVersion(); err != nil || ver != "v3" {
+			t.Errorf("ImpliedVersion() = %q, %v; want v3, nil", ver, err)
+		}
+
+		// Setting only C makes the version ambiguous (v3 or v4).
+		vs = newVS(t)
+		if err := vs.Set("v4", &v4{Name: "foo", C: 3}); err != nil {
+			t.Fatalf("Set(v4) = %v, want nil", err)
+		}
+		if _, err := vs.ImpliedVersion(); err == nil {
+			t.Error("ImpliedVersion() = _, nil; want error")
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		vs := newVS(t)
+		vs.StrictConversion("v1")
+		err := AccessVersion(vs, "v4", func(x *v4) { x.C = 1 })
+		var mfErr *MissingFieldError
+		if !errors.As(err, &mfErr) {
+			t.Errorf("AccessVersion(v4) = %v, want MissingFieldError", err)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		vs := newVS(t)
+		if err := AccessVersion(vs, "v1", func(x *v2) {}); err == nil {
+			t.Error("AccessVersion(v1) with the wrong type = nil, want error")
+		}
+		if err := vs.Access("v5", func(any) {}); err == nil {
+			t.Error("Access(v5) = nil, want error")
+		}
+		if err := vs.Set("v1", &v2{}); err == nil {
+			t.Error("Set(v1) with the wrong type = nil, want error")
+		}
+		if _, err := ToVersion[v2](vs, "v1"); err == nil {
+			t.Error("ToVersion(v1) with the wrong type = _, nil; want error")
+		}
+		if _, err := NewVersionSet(id); err == nil {
+			t.Error("NewVersionSet() with no versions = _, nil; want error")
+		}
+		if _, err := NewVersionSet(id, NewVersion[v1]("v1", nil), NewVersion[v2]("v1", nil)); err == nil {
+			t.Error("NewVersionSet() with duplicate versions = _, nil; want error")
+		}
+		if _, err := NewVersionSet(id, NewVersion[int]("v1", nil)); err == nil {
+			t.Error("NewVersionSet() with a non-struct = _, nil; want error")
+		}
+	})
+}
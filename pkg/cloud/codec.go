@@ -0,0 +1,207 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Codec copies an object to an object of a different (but compatible) type,
+// e.g. a GA compute.Address to an alpha.Address. Fields are matched by name
+// and fields that do not exist in the destination are dropped. This is used by
+// the mocks to convert between API versions.
+type Codec interface {
+	// Copy src into dest. dest must be a pointer to a zero value.
+	Copy(dest, src any) error
+}
+
+// JSONCodec copies objects by serializing to JSON and back. This is the
+// default Codec.
+type JSONCodec struct{}
+
+// Copy implements Codec.
+func (JSONCodec) Copy(dest, src any) error { return copyViaJSON(dest, src) }
+
+// ReflectCodec copies objects directly field-by-field using reflection. This
+// gives the same results as JSONCodec for the API types but is significantly
+// faster as no intermediate serialization is done.
+//
+// Fields that are unexported or not serialized to JSON (e.g.
+// ServerResponse) are not copied.
+type ReflectCodec struct{}
+
+// Copy implements Codec.
+func (ReflectCodec) Copy(dest, src any) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("ReflectCodec: dest must be a non-nil pointer (got %T)", dest)
+	}
+	sv := reflect.ValueOf(src)
+	if !sv.IsValid() || (sv.Kind() == reflect.Pointer && sv.IsNil()) {
+		return nil
+	}
+	if sv.Kind() == reflect.Pointer {
+		sv = sv.Elem()
+	}
+	if err := reflectCopy(".", dv.Elem(), sv); err != nil {
+		return fmt.Errorf("ReflectCodec: %w", err)
+	}
+	return nil
+}
+
+// reflectCopy copies src to dest. path is used for error messages.
+func reflectCopy(path string, dest, src reflect.Value) error {
+	if src.Kind() != dest.Kind() {
+		// JSON would omit zero values, so these are ignored.
+		if src.IsZero() {
+			return nil
+		}
+		return fmt.Errorf("at %s, cannot copy %s to %s", path, src.Type(), dest.Type())
+	}
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return nil
+		}
+		if dest.IsNil() {
+			dest.Set(reflect.New(dest.Type().Elem()))
+		}
+		return reflectCopy(path, dest.Elem(), src.Elem())
+	case reflect.Struct:
+		st := src.Type()
+		for i := 0; i < st.NumField(); i++ {
+			sf := st.Field(i)
+			tag := sf.Tag.Get("json")
+			if !sf.IsExported() || strings.HasPrefix(tag, "-") {
+				continue
+			}
+			if strings.Contains(tag, ",omitempty") && isEmptyValue(src.Field(i)) {
+				continue
+			}
+			df := dest.FieldByName(sf.Name)
+			if !df.IsValid() || !df.CanSet() {
+				continue
+			}
+			if err := reflectCopy(path+sf.Name+".", df, src.Field(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil
+		}
+		if src.Type() == dest.Type() && isPlainType(src.Type().Elem()) {
+			dest.Set(reflect.AppendSlice(reflect.MakeSlice(dest.Type(), 0, src.Len()), src))
+			return nil
+		}
+		dest.Set(reflect.MakeSlice(dest.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			if err := reflectCopy(fmt.Sprintf("%s[%d].", path, i), dest.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if src.IsNil() {
+			return nil
+		}
+		if !src.Type().Key().ConvertibleTo(dest.Type().Key()) {
+			return fmt.Errorf("at %s, cannot copy map key %s to %s", path, src.Type().Key(), dest.Type().Key())
+		}
+		dest.Set(reflect.MakeMapWithSize(dest.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(dest.Type().Elem()).Elem()
+			if err := reflectCopy(fmt.Sprintf("%s[%v].", path, iter.Key()), v, iter.Value()); err != nil {
+				return err
+			}
+			dest.SetMapIndex(iter.Key().Convert(dest.Type().Key()), v)
+		}
+		return nil
+	case reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+		// The contents of an interface{} are not typed by the API
+		// definition, fall back to JSON to get the same result.
+		v := reflect.New(dest.Type())
+		if err := copyViaJSON(v.Interface(), src.Interface()); err != nil {
+			return fmt.Errorf("at %s: %w", path, err)
+		}
+		dest.Set(v.Elem())
+		return nil
+	}
+
+	if !src.Type().ConvertibleTo(dest.Type()) {
+		return fmt.Errorf("at %s, cannot copy %s to %s", path, src.Type(), dest.Type())
+	}
+	dest.Set(src.Convert(dest.Type()))
+	return nil
+}
+
+// isPlainType returns true if values of t do not contain references.
+func isPlainType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isEmptyValue returns true if v is omitted from JSON with omitempty.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Struct:
+		return false
+	}
+	return v.IsZero()
+}
+
+// codecOrDefault returns c or the default Codec if c is nil.
+func codecOrDefault(c Codec) Codec {
+	if c == nil {
+		return JSONCodec{}
+	}
+	return c
+}
+
+func copyViaJSON(dest, src interface{}) error {
+	bytes, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bytes, dest)
+}
+
+// MockGCEOption configures the MockGCE returned by NewMockGCE.
+type MockGCEOption func(*MockGCE)
+
+// MockCodecOption sets the Codec used by the mocks to convert objects between
+// versions. ReflectCodec can be used to speed up large tests.
+func MockCodecOption(c Codec) MockGCEOption {
+	return func(m *MockGCE) { m.SetCodec(c) }
+}
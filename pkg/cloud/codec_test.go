@@ -0,0 +1,136 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func testBackendService() *ga.BackendService {
+	return &ga.BackendService{
+		Name:                 "bs",
+		AffinityCookieTtlSec: 10,
+		Backends: []*ga.Backend{
+			{Group: "ig1", MaxUtilization: 0.5},
+			{Group: "ig2", BalancingMode: "RATE", MaxRatePerInstance: 100},
+		},
+		CdnPolicy: &ga.BackendServiceCdnPolicy{
+			CacheKeyPolicy:    &ga.CacheKeyPolicy{IncludeHost: true},
+			SignedUrlKeyNames: []string{},
+		},
+		HealthChecks:    []string{"hc1", "hc2"},
+		Id:              123456789,
+		ForceSendFields: []string{"Port"},
+		NullFields:      []string{"Iap"},
+		ServerResponse:  googleapi.ServerResponse{HTTPStatusCode: 200},
+	}
+}
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+
+	fr := &ga.ForwardingRule{Name: "fr", Labels: map[string]string{"a": "b"}}
+	for _, tc := range []struct {
+		name string
+		src  any
+		dest func() any
+	}{
+		{name: "ga", src: testBackendService(), dest: func() any { return &ga.BackendService{} }},
+		{name: "alpha", src: testBackendService(), dest: func() any { return &alpha.BackendService{} }},
+		{name: "beta", src: testBackendService(), dest: func() any { return &beta.BackendService{} }},
+		{name: "map", src: fr, dest: func() any { return &alpha.ForwardingRule{} }},
+		{name: "nil", src: (*ga.Address)(nil), dest: func() any { return &alpha.Address{} }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := tc.dest()
+			if err := (JSONCodec{}).Copy(want, tc.src); err != nil {
+				t.Fatalf("JSONCodec.Copy() = %v, want nil", err)
+			}
+			got := tc.dest()
+			if err := (ReflectCodec{}).Copy(got, tc.src); err != nil {
+				t.Fatalf("ReflectCodec.Copy() = %v, want nil", err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("ReflectCodec.Copy() differs from JSONCodec; -got,+want: %s", diff)
+			}
+		})
+	}
+
+	// The copy is deep.
+	src := testBackendService()
+	got := &ga.BackendService{}
+	if err := (ReflectCodec{}).Copy(got, src); err != nil {
+		t.Fatalf("ReflectCodec.Copy() = %v, want nil", err)
+	}
+	got.Backends[0].Group = "changed"
+	got.HealthChecks[0] = "changed"
+	if diff := cmp.Diff(src, testBackendService()); diff != "" {
+		t.Errorf("src was modified; -got,+want: %s", diff)
+	}
+
+	if err := (ReflectCodec{}).Copy(ga.BackendService{}, src); err == nil {
+		t.Error("ReflectCodec.Copy(<not a pointer>) = nil, want error")
+	}
+}
+
+func TestMockCodecOption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj1"}, MockCodecOption(ReflectCodec{}))
+	key := meta.GlobalKey("bs")
+	if err := mock.BackendServices().Insert(ctx, key, testBackendService()); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+	if _, ok := mock.MockBackendServices.Objects[*key].Codec.(ReflectCodec); !ok {
+		t.Errorf("Codec = %T, want ReflectCodec", mock.MockBackendServices.Objects[*key].Codec)
+	}
+	bs, err := mock.AlphaBackendServices().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("AlphaBackendServices().Get() = %v, want nil", err)
+	}
+	if len(bs.Backends) != 2 || bs.Backends[1].MaxRatePerInstance != 100 {
+		t.Errorf("AlphaBackendServices().Get() = %+v, want 2 Backends", bs)
+	}
+}
+
+func BenchmarkCodec(b *testing.B) {
+	src := testBackendService()
+	for _, tc := range []struct {
+		name  string
+		codec Codec
+	}{
+		{"json", JSONCodec{}},
+		{"reflect", ReflectCodec{}},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := tc.codec.Copy(&alpha.BackendService{}, src); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
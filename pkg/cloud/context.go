@@ -43,4 +43,6 @@ type CallContextKey struct {
 	Version meta.Version
 	// Service is the service being invoked (e.g. "Firewalls", "BackendServices")
 	Service string
+	// APIGroup of the Service (e.g. "compute", "networkservices").
+	APIGroup meta.APIGroup
 }
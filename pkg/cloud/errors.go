@@ -0,0 +1,67 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
+)
+
+// NotFoundError is returned by Get() and Delete() when the resource does not
+// exist. The underlying error (a *googleapi.Error with StatusNotFound) can be
+// retrieved with errors.As().
+type NotFoundError struct {
+	// ID of the resource that was not found.
+	ID *ResourceID
+	// Version of the API used for the call.
+	Version meta.Version
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v (%s) not found: %v", e.ID, e.Version, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *NotFoundError) Unwrap() error { return e.Err }
+
+// IsNotFound returns true if err is a NotFoundError or is a *googleapi.Error
+// with StatusNotFound.
+func IsNotFound(err error) bool {
+	var nfErr *NotFoundError
+	if errors.As(err, &nfErr) {
+		return true
+	}
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
+
+// wrapNotFound wraps err in a NotFoundError if err is a *googleapi.Error with
+// StatusNotFound. Other errors are returned unchanged.
+func wrapNotFound(err error, id *ResourceID, ver meta.Version) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+		return err
+	}
+	return &NotFoundError{ID: id, Version: ver, Err: err}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+func TestNotFoundError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"code": 404, "message": "not found"}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	gaSvc, err := ga.NewService(ctx, option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client()))
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	gce := NewGCE(&Service{
+		GA:            gaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj1"},
+		RateLimiter:   &NopRateLimiter{},
+	})
+	mock := NewMockGCE(&SingleProjectRouter{ID: "proj1"})
+
+	key := meta.RegionalKey("a", "us-central1")
+	wantID := &ResourceID{"proj1", meta.APIGroupCompute, "addresses", key}
+
+	for _, tc := range []struct {
+		name string
+		f    func() error
+	}{
+		{
+			name: "GCE Get",
+			f: func() error {
+				_, err := gce.Addresses().Get(ctx, key)
+				return err
+			},
+		},
+		{
+			name: "GCE Delete",
+			f:    func() error { return gce.Addresses().Delete(ctx, key) },
+		},
+		{
+			name: "mock Get",
+			f: func() error {
+				_, err := mock.Addresses().Get(ctx, key)
+				return err
+			},
+		},
+		{
+			name: "mock Delete",
+			f:    func() error { return mock.Addresses().Delete(ctx, key) },
+		},
+	} {
+		err := tc.f()
+		var nfErr *NotFoundError
+		if !errors.As(err, &nfErr) {
+			t.Errorf("%s: f() = %v, want NotFoundError", tc.name, err)
+			continue
+		}
+		if !nfErr.ID.Equal(wantID) || nfErr.Version != meta.VersionGA {
+			t.Errorf("%s: NotFoundError = {%v, %v}, want {%v, %v}", tc.name, nfErr.ID, nfErr.Version, wantID, meta.VersionGA)
+		}
+		// The googleapi.Error is still available.
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+			t.Errorf("%s: errors.As(%v, *googleapi.Error) = false, want StatusNotFound", tc.name, err)
+		}
+		if !IsNotFound(err) {
+			t.Errorf("%s: IsNotFound(%v) = false, want true", tc.name, err)
+		}
+	}
+
+	if IsNotFound(&googleapi.Error{Code: http.StatusBadRequest}) {
+		t.Errorf("IsNotFound(StatusBadRequest) = true, want false")
+	}
+	if !IsNotFound(&googleapi.Error{Code: http.StatusNotFound}) {
+		t.Errorf("IsNotFound(StatusNotFound) = false, want true")
+	}
+}
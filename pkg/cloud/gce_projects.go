@@ -45,9 +45,13 @@ func (m *MockProjects) Get(ctx context.Context, projectID string) (*compute.Proj
 	if p, ok := m.Objects[*meta.GlobalKey(projectID)]; ok {
 		return p.ToGA(), nil
 	}
-	return nil, &googleapi.Error{
-		Code:    http.StatusNotFound,
-		Message: fmt.Sprintf("MockProjects %v not found", projectID),
+	return nil, &NotFoundError{
+		ID:      &ResourceID{ProjectID: projectID, APIGroup: meta.APIGroupCompute, Resource: "projects"},
+		Version: meta.VersionGA,
+		Err: &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("MockProjects %v not found", projectID),
+		},
 	}
 }
 
@@ -58,6 +62,7 @@ func (g *GCEProjects) Get(ctx context.Context, projectID string) (*compute.Proje
 		Operation: "Get",
 		Version:   meta.Version("ga"),
 		Service:   "Projects",
+		APIGroup:  meta.APIGroupCompute,
 	}
 	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
 		return nil, err
@@ -66,7 +71,10 @@ func (g *GCEProjects) Get(ctx context.Context, projectID string) (*compute.Proje
 	call.Context(ctx)
 	v, err := call.Do()
 	g.s.RateLimiter.Observe(ctx, err, rk)
-	return v, err
+	if err != nil {
+		return nil, wrapNotFound(err, &ResourceID{ProjectID: projectID, APIGroup: meta.APIGroupCompute, Resource: "projects"}, meta.VersionGA)
+	}
+	return v, nil
 }
 
 // SetCommonInstanceMetadata for a given project.
@@ -86,6 +94,10 @@ func (g *GCEProjects) SetCommonInstanceMetadata(ctx context.Context, projectID s
 		Operation: "SetCommonInstanceMetadata",
 		Version:   meta.Version("ga"),
 		Service:   "Projects",
+		APIGroup:  meta.APIGroupCompute,
+	}
+	if g.s.DryRun {
+		return dryRunError(rk)
 	}
 	if err := g.s.RateLimiter.Accept(ctx, rk); err != nil {
 		return err
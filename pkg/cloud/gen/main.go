@@ -203,7 +203,7 @@ func (gce *GCE) {{.WrapType}}() {{.WrapType}} {
 {{- end}}
 
 // NewMockGCE returns a new mock for GCE.
-func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
+func NewMockGCE(projectRouter ProjectRouter, opts ...MockGCEOption) *MockGCE {
 	{{- range .Groups}}
 	mock{{.Service}}Objs := map[meta.Key]*Mock{{.Service}}Obj{}
 	{{- end}}
@@ -213,9 +213,20 @@ func NewMockGCE(projectRouter ProjectRouter) *MockGCE {
 		{{.MockField}}: New{{.MockWrapType}}(projectRouter, mock{{.Service}}Objs),
 	{{- end}}
 	}
+	for _, opt := range opts {
+		opt(mock)
+	}
 	return mock
 }
 
+// SetCodec sets the Codec used by all of the mocks to convert objects between
+// versions. This should be called before any objects are added to the mock.
+func (mock *MockGCE) SetCodec(c Codec) {
+	{{- range .All}}
+	mock.{{.MockField}}.Codec = c
+	{{- end}}
+}
+
 // MockGCE implements Cloud.
 var _ Cloud = (*MockGCE)(nil)
 
@@ -238,6 +249,9 @@ func (mock *MockGCE) {{.WrapType}}() {{.WrapType}} {
 // share the same "view" of the objects in the backend.
 type Mock{{.Service}}Obj struct {
 	Obj interface{}
+	// Codec used to convert Obj to the other versions. JSONCodec is used if
+	// this is nil.
+	Codec Codec
 }
 {{- if .HasAlpha}}
 // ToAlpha retrieves the given version of the object.
@@ -245,10 +259,10 @@ func (m *Mock{{.Service}}Obj) ToAlpha() *{{.Alpha.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.Alpha.FQObjectType}}); ok {
 		return ret
 	}
-	// Convert the object via JSON copying to the type that was requested.
+	// Convert the object to the type that was requested.
 	ret := &{{.Alpha.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.Alpha.FQObjectType}} via JSON: %v", m.Obj, err)
+	if err := codecOrDefault(m.Codec).Copy(ret, m.Obj); err != nil {
+		klog.Errorf("Could not convert %T to *{{.Alpha.FQObjectType}}: %v", m.Obj, err)
 	}
 	return ret
 }
@@ -259,10 +273,10 @@ func (m *Mock{{.Service}}Obj) ToBeta() *{{.Beta.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.Beta.FQObjectType}}); ok {
 		return ret
 	}
-	// Convert the object via JSON copying to the type that was requested.
+	// Convert the object to the type that was requested.
 	ret := &{{.Beta.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.Beta.FQObjectType}} via JSON: %v", m.Obj, err)
+	if err := codecOrDefault(m.Codec).Copy(ret, m.Obj); err != nil {
+		klog.Errorf("Could not convert %T to *{{.Beta.FQObjectType}}: %v", m.Obj, err)
 	}
 	return ret
 }
@@ -273,10 +287,10 @@ func (m *Mock{{.Service}}Obj) ToGA() *{{.GA.FQObjectType}} {
 	if ret, ok := m.Obj.(*{{.GA.FQObjectType}}); ok {
 		return ret
 	}
-		// Convert the object via JSON copying to the type that was requested.
+		// Convert the object to the type that was requested.
 	ret := &{{.GA.FQObjectType}}{}
-	if err := copyViaJSON(ret, m.Obj); err != nil {
-		klog.Errorf("Could not convert %T to *{{.GA.FQObjectType}} via JSON: %v", m.Obj, err)
+	if err := codecOrDefault(m.Codec).Copy(ret, m.Obj); err != nil {
+		klog.Errorf("Could not convert %T to *{{.GA.FQObjectType}}: %v", m.Obj, err)
 	}
 	return ret
 }
@@ -363,6 +377,9 @@ type {{.MockWrapType}} struct {
 
 	// Objects maintained by the mock.
 	Objects map[meta.Key]*Mock{{.Service}}Obj
+	// Codec used to convert the Objects between versions. JSONCodec is used
+	// if this is nil.
+	Codec Codec
 
 	// If an entry exists for the given key and operation, then the error
 	// will be returned instead of the operation.
@@ -439,6 +456,10 @@ func (m *{{.MockWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjec
 	if !key.Valid() {
 		return nil, fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = nil, %v", ctx, key, err)
+		return nil, err
+	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -453,9 +474,14 @@ func (m *{{.MockWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObjec
 		return typedObj, nil
 	}
 
-	err := &googleapi.Error{
-		Code: http.StatusNotFound,
-		Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+	projectID := m.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Resource}}")
+	err := &NotFoundError{
+		ID:      &ResourceID{projectID, "{{.APIGroup}}", "{{.Resource}}", key},
+		Version: meta.Version{{.VersionTitle}},
+		Err: &googleapi.Error{
+			Code: http.StatusNotFound,
+			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		},
 	}
 	klog.V(5).Infof("{{.MockWrapType}}.Get(%v, %s) = nil, %v", ctx, key, err)
 	return nil, err
@@ -557,6 +583,10 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = %v", ctx, key, obj, err)
+		return err
+	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -578,7 +608,7 @@ func (m *{{.MockWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.F
 	projectID := m.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Resource}}")
 	obj.SelfLink = SelfLinkWithGroup("{{.APIGroup}}", meta.Version{{.VersionTitle}}, projectID, "{{.Resource}}", key)
 
-	m.Objects[*key] = &Mock{{.Service}}Obj{obj}
+	m.Objects[*key] = m.Obj(obj)
 	klog.V(5).Infof("{{.MockWrapType}}.Insert(%v, %v, %+v) = nil", ctx, key, obj)
 	return nil
 }
@@ -596,6 +626,10 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 	if !key.Valid() {
 		return fmt.Errorf("invalid GCE key (%+v)", key)
 	}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
+		return err
+	}
 
 	m.Lock.Lock()
 	defer m.Lock.Unlock()
@@ -605,9 +639,14 @@ func (m *{{.MockWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 		return err
 	}
 	if _, ok := m.Objects[*key]; !ok {
-		err := &googleapi.Error{
-			Code: http.StatusNotFound,
-			Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+		projectID := m.ProjectRouter.ProjectID(ctx, "{{.Version}}", "{{.Resource}}")
+		err := &NotFoundError{
+			ID:      &ResourceID{projectID, "{{.APIGroup}}", "{{.Resource}}", key},
+			Version: meta.Version{{.VersionTitle}},
+			Err: &googleapi.Error{
+				Code: http.StatusNotFound,
+				Message: fmt.Sprintf("{{.MockWrapType}} %v not found", key),
+			},
 		}
 		klog.V(5).Infof("{{.MockWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
 		return err
@@ -684,8 +723,8 @@ func (m *{{.MockWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{
 		{{.Version}}Obj := obj.To{{.VersionTitle}}()
 		dest := &{{.FQListUsableObjectType}}{}
 		// Convert to Usable type to avoid separate Usable struct
-		if err := copyViaJSON(dest, {{.Version}}Obj); err != nil {
-			klog.Errorf("Could not convert %T to *{{.FQListUsableObjectType}} via JSON: %v", {{.Version}}Obj, err)
+		if err := codecOrDefault(m.Codec).Copy(dest, {{.Version}}Obj); err != nil {
+			klog.Errorf("Could not convert %T to *{{.FQListUsableObjectType}}: %v", {{.Version}}Obj, err)
 		}
 		objs = append(objs, dest)
 	}
@@ -696,7 +735,7 @@ func (m *{{.MockWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{
 
 // Obj wraps the object for use in the mock.
 func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
-	return &Mock{{.Service}}Obj{o}
+	return &Mock{{.Service}}Obj{Obj: o, Codec: m.Codec}
 }
 
 {{with .Methods -}}
@@ -704,16 +743,28 @@ func (m *{{.MockWrapType}}) Obj(o *{{.FQObjectType}}) *Mock{{.Service}}Obj {
 // {{.Name}} is a mock for the corresponding method.
 func (m *{{.MockWrapType}}) {{.FcnArgs}} {
 {{- if .IsOperation }}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = %v", ctx, key, err)
+		return err
+	}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
 	return nil
 {{- else if .IsGet}}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = nil, %v", ctx, key, err)
+		return nil, err
+	}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, m)
 	}
 	return nil, fmt.Errorf("{{.MockHookName}} must be set")
 {{- else if .IsPaged}}
+	if err := mockCheckKeyScope("{{.MockWrapType}}", key, {{.KeyTypeConst}}); err != nil {
+		klog.V(5).Infof("{{.MockWrapType}}.{{.Name}}(%v, %v, ...) = nil, %v", ctx, key, err)
+		return nil, err
+	}
 	if m.{{.MockHookName}} != nil {
 		return m.{{.MockHookName}}(ctx, key {{.CallArgs}}, fl, m)
 	}
@@ -742,6 +793,7 @@ func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObject
 		Operation: "Get",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.Get(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
@@ -767,7 +819,10 @@ func (g *{{.GCEWrapType}}) Get(ctx context.Context, key *meta.Key) (*{{.FQObject
 	callObserverEnd(ctx, ck, err)
 	g.s.RateLimiter.Observe(ctx, err, ck)
 
-	return v, err
+	if err != nil {
+		return nil, wrapNotFound(err, &ResourceID{projectID, "{{.APIGroup}}", "{{.Resource}}", key}, meta.Version("{{.Version}}"))
+	}
+	return v, nil
 }
 {{- end}}
 
@@ -791,6 +846,7 @@ func (g *{{.GCEWrapType}}) List(ctx context.Context, zone string, fl *filter.F)
 		Operation: "List",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 
 	callObserverStart(ctx, ck)
@@ -858,9 +914,16 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		Operation: "Insert",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+{{- if not .InsertValidateOnly}}
+	if g.s.DryRun {
+		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): blocked (dry run)", ctx, key)
+		return dryRunError(ck)
+	}
+{{- end}}
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
@@ -878,6 +941,11 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 	call := g.s.{{.VersionTitle}}.{{.Service}}.Insert(projectID, key.Zone, obj)
 {{- end}}
 	call.Context(ctx)
+{{- if .InsertValidateOnly}}
+	if g.s.DryRun {
+		call.ValidateOnly(true)
+	}
+{{- end}}
 
 	op, err := call.Do()
 
@@ -888,6 +956,13 @@ func (g *{{.GCEWrapType}}) Insert(ctx context.Context, key *meta.Key, obj *{{.FQ
 		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...) = %+v", ctx, key, err)
 		return err
 	}
+{{- if .InsertValidateOnly}}
+	if g.s.DryRun {
+		// The request was validated but not committed.
+		klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, ...) = nil (validateOnly)", ctx, key)
+		return nil
+	}
+{{- end}}
 
 	err = g.s.WaitForCompletion(ctx, op)
 	klog.V(4).Infof("{{.GCEWrapType}}.Insert(%v, %v, %+v) = %+v", ctx, key, obj, err)
@@ -909,8 +984,13 @@ func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 		Operation: "Delete",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 	klog.V(5).Infof("{{.GCEWrapType}}.Delete(%v, %v): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+	if g.s.DryRun {
+		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v): blocked (dry run)", ctx, key)
+		return dryRunError(ck)
+	}
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v): RateLimiter error: %v", ctx, key, err)
@@ -935,7 +1015,7 @@ func (g *{{.GCEWrapType}}) Delete(ctx context.Context, key *meta.Key) error {
 
 	if err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.Delete(%v, %v) = %v", ctx, key, err)
-		return err
+		return wrapNotFound(err, &ResourceID{projectID, "{{.APIGroup}}", "{{.Resource}}", key}, meta.Version("{{.Version}}"))
 	}
 
 	err = g.s.WaitForCompletion(ctx, op)
@@ -955,6 +1035,7 @@ func (g *{{.GCEWrapType}}) AggregatedList(ctx context.Context, fl *filter.F) (ma
 		Operation: "AggregatedList",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 
 	klog.V(5).Infof("{{.GCEWrapType}}.AggregatedList(%v, %v): projectID = %v, ck = %+v", ctx, fl, projectID, ck)
@@ -1011,6 +1092,7 @@ func (g *{{.GCEWrapType}}) ListUsable(ctx context.Context, fl *filter.F) ([]*{{.
 		Operation: "ListUsable",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
     callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
@@ -1074,8 +1156,15 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 		Operation: "{{.Name}}",
 		Version: meta.Version("{{.Version}}"),
 		Service: "{{.Service}}",
+		APIGroup: meta.APIGroup("{{.APIGroup}}"),
 	}
 	klog.V(5).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): projectID = %v, ck = %+v", ctx, key, projectID, ck)
+{{- if and .IsOperation (not .HasValidateOnly)}}
+	if g.s.DryRun {
+		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): blocked (dry run)", ctx, key)
+		return dryRunError(ck)
+	}
+{{- end}}
 	callObserverStart(ctx, ck)
 	if err := g.s.RateLimiter.Accept(ctx, ck); err != nil {
 		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...): RateLimiter error: %v", ctx, key, err)
@@ -1097,6 +1186,11 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 {{- end}}
 {{- if .IsOperation}}
 	call.Context(ctx)
+{{- if .HasValidateOnly}}
+	if g.s.DryRun {
+		call.ValidateOnly(true)
+	}
+{{- end}}
 	op, err := call.Do()
 
 	if err != nil {
@@ -1106,6 +1200,16 @@ func (g *{{.GCEWrapType}}) {{.FcnArgs}} {
 		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = %+v", ctx, key, err)
 		return err
 	}
+{{- if .HasValidateOnly}}
+	if g.s.DryRun {
+		// The request was validated but not committed.
+		callObserverEnd(ctx, ck, nil)
+		g.s.RateLimiter.Observe(ctx, nil, ck)
+
+		klog.V(4).Infof("{{.GCEWrapType}}.{{.Name}}(%v, %v, ...) = nil (validateOnly)", ctx, key)
+		return nil
+	}
+{{- end}}
 
 	err = g.s.WaitForCompletion(ctx, op)
 
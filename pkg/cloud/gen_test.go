@@ -169,6 +169,141 @@ func TestAddressesGroup(t *testing.T) {
 	}
 }
 
+func TestAutoscalersGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.ZonalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.ZonalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.ZonalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaAutoscalers().Get(ctx, key); err == nil {
+		t.Errorf("AlphaAutoscalers().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaAutoscalers().Get(ctx, key); err == nil {
+		t.Errorf("BetaAutoscalers().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.Autoscalers().Get(ctx, key); err == nil {
+		t.Errorf("Autoscalers().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.Autoscaler{}
+		if err := mock.AlphaAutoscalers().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaAutoscalers().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.Autoscaler{}
+		if err := mock.BetaAutoscalers().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaAutoscalers().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.Autoscaler{}
+		if err := mock.Autoscalers().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("Autoscalers().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaAutoscalers().Get(ctx, key); err != nil {
+		t.Errorf("AlphaAutoscalers().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaAutoscalers().Get(ctx, key); err != nil {
+		t.Errorf("BetaAutoscalers().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.Autoscalers().Get(ctx, key); err != nil {
+		t.Errorf("Autoscalers().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaAutoscalers.Objects[*keyAlpha] = mock.MockAlphaAutoscalers.Obj(&alpha.Autoscaler{Name: keyAlpha.Name})
+	mock.MockBetaAutoscalers.Objects[*keyBeta] = mock.MockBetaAutoscalers.Obj(&beta.Autoscaler{Name: keyBeta.Name})
+	mock.MockAutoscalers.Objects[*keyGA] = mock.MockAutoscalers.Obj(&ga.Autoscaler{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaAutoscalers().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaAutoscalers().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaAutoscalers().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaAutoscalers().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaAutoscalers().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaAutoscalers().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.Autoscalers().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("Autoscalers().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Autoscalers().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaAutoscalers().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaAutoscalers().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaAutoscalers().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaAutoscalers().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.Autoscalers().Delete(ctx, keyGA); err != nil {
+		t.Errorf("Autoscalers().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaAutoscalers().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaAutoscalers().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaAutoscalers().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaAutoscalers().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.Autoscalers().Delete(ctx, keyGA); err == nil {
+		t.Errorf("Autoscalers().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestBackendServicesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -1437,17 +1572,39 @@ func TestInstanceTemplatesGroup(t *testing.T) {
 	mock := NewMockGCE(pr)
 
 	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
 	keyGA := meta.GlobalKey("key-ga")
 	key = keyGA
 	// Ignore unused variables.
 	_, _, _ = ctx, mock, key
 
 	// Get not found.
+	if _, err := mock.AlphaInstanceTemplates().Get(ctx, key); err == nil {
+		t.Errorf("AlphaInstanceTemplates().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaInstanceTemplates().Get(ctx, key); err == nil {
+		t.Errorf("BetaInstanceTemplates().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
 	if _, err := mock.InstanceTemplates().Get(ctx, key); err == nil {
 		t.Errorf("InstanceTemplates().Get(%v, %v) = _, nil; want error", ctx, key)
 	}
 
 	// Insert.
+	{
+		obj := &alpha.InstanceTemplate{}
+		if err := mock.AlphaInstanceTemplates().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaInstanceTemplates().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.InstanceTemplate{}
+		if err := mock.BetaInstanceTemplates().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaInstanceTemplates().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
 	{
 		obj := &ga.InstanceTemplate{}
 		if err := mock.InstanceTemplates().Insert(ctx, keyGA, obj); err != nil {
@@ -1456,16 +1613,54 @@ func TestInstanceTemplatesGroup(t *testing.T) {
 	}
 
 	// Get across versions.
+	if obj, err := mock.AlphaInstanceTemplates().Get(ctx, key); err != nil {
+		t.Errorf("AlphaInstanceTemplates().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaInstanceTemplates().Get(ctx, key); err != nil {
+		t.Errorf("BetaInstanceTemplates().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
 	if obj, err := mock.InstanceTemplates().Get(ctx, key); err != nil {
 		t.Errorf("InstanceTemplates().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
 	}
 
 	// List.
+	mock.MockAlphaInstanceTemplates.Objects[*keyAlpha] = mock.MockAlphaInstanceTemplates.Obj(&alpha.InstanceTemplate{Name: keyAlpha.Name})
+	mock.MockBetaInstanceTemplates.Objects[*keyBeta] = mock.MockBetaInstanceTemplates.Obj(&beta.InstanceTemplate{Name: keyBeta.Name})
 	mock.MockInstanceTemplates.Objects[*keyGA] = mock.MockInstanceTemplates.Obj(&ga.InstanceTemplate{Name: keyGA.Name})
 	want := map[string]bool{
-		"key-ga": true,
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
 	}
 	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaInstanceTemplates().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaInstanceTemplates().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaInstanceTemplates().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaInstanceTemplates().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaInstanceTemplates().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaInstanceTemplates().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
 	{
 		objs, err := mock.InstanceTemplates().List(ctx, filter.None)
 		if err != nil {
@@ -1482,11 +1677,23 @@ func TestInstanceTemplatesGroup(t *testing.T) {
 	}
 
 	// Delete across versions.
+	if err := mock.AlphaInstanceTemplates().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaInstanceTemplates().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaInstanceTemplates().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaInstanceTemplates().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
 	if err := mock.InstanceTemplates().Delete(ctx, keyGA); err != nil {
 		t.Errorf("InstanceTemplates().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
 	}
 
 	// Delete not found.
+	if err := mock.AlphaInstanceTemplates().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaInstanceTemplates().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaInstanceTemplates().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaInstanceTemplates().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
 	if err := mock.InstanceTemplates().Delete(ctx, keyGA); err == nil {
 		t.Errorf("InstanceTemplates().Delete(%v, %v) = nil; want error", ctx, keyGA)
 	}
@@ -2189,6 +2396,141 @@ func TestRegionDisksGroup(t *testing.T) {
 	}
 }
 
+func TestRegionHealthCheckServicesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaRegionHealthCheckServices().Get(ctx, key); err == nil {
+		t.Errorf("AlphaRegionHealthCheckServices().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaRegionHealthCheckServices().Get(ctx, key); err == nil {
+		t.Errorf("BetaRegionHealthCheckServices().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.RegionHealthCheckServices().Get(ctx, key); err == nil {
+		t.Errorf("RegionHealthCheckServices().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.HealthCheckService{}
+		if err := mock.AlphaRegionHealthCheckServices().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaRegionHealthCheckServices().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.HealthCheckService{}
+		if err := mock.BetaRegionHealthCheckServices().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaRegionHealthCheckServices().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.HealthCheckService{}
+		if err := mock.RegionHealthCheckServices().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("RegionHealthCheckServices().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaRegionHealthCheckServices().Get(ctx, key); err != nil {
+		t.Errorf("AlphaRegionHealthCheckServices().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaRegionHealthCheckServices().Get(ctx, key); err != nil {
+		t.Errorf("BetaRegionHealthCheckServices().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.RegionHealthCheckServices().Get(ctx, key); err != nil {
+		t.Errorf("RegionHealthCheckServices().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaRegionHealthCheckServices.Objects[*keyAlpha] = mock.MockAlphaRegionHealthCheckServices.Obj(&alpha.HealthCheckService{Name: keyAlpha.Name})
+	mock.MockBetaRegionHealthCheckServices.Objects[*keyBeta] = mock.MockBetaRegionHealthCheckServices.Obj(&beta.HealthCheckService{Name: keyBeta.Name})
+	mock.MockRegionHealthCheckServices.Objects[*keyGA] = mock.MockRegionHealthCheckServices.Obj(&ga.HealthCheckService{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaRegionHealthCheckServices().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaRegionHealthCheckServices().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaRegionHealthCheckServices().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaRegionHealthCheckServices().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaRegionHealthCheckServices().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaRegionHealthCheckServices().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.RegionHealthCheckServices().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("RegionHealthCheckServices().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("RegionHealthCheckServices().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaRegionHealthCheckServices().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaRegionHealthCheckServices().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaRegionHealthCheckServices().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaRegionHealthCheckServices().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.RegionHealthCheckServices().Delete(ctx, keyGA); err != nil {
+		t.Errorf("RegionHealthCheckServices().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaRegionHealthCheckServices().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaRegionHealthCheckServices().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaRegionHealthCheckServices().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaRegionHealthCheckServices().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.RegionHealthCheckServices().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionHealthCheckServices().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestRegionHealthChecksGroup(t *testing.T) {
 	t.Parallel()
 
@@ -2387,6 +2729,141 @@ func TestRegionNetworkFirewallPoliciesGroup(t *testing.T) {
 	}
 }
 
+func TestRegionNotificationEndpointsGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.RegionalKey("key-alpha", "location")
+	key = keyAlpha
+	keyBeta := meta.RegionalKey("key-beta", "location")
+	key = keyBeta
+	keyGA := meta.RegionalKey("key-ga", "location")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaRegionNotificationEndpoints().Get(ctx, key); err == nil {
+		t.Errorf("AlphaRegionNotificationEndpoints().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaRegionNotificationEndpoints().Get(ctx, key); err == nil {
+		t.Errorf("BetaRegionNotificationEndpoints().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.RegionNotificationEndpoints().Get(ctx, key); err == nil {
+		t.Errorf("RegionNotificationEndpoints().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.NotificationEndpoint{}
+		if err := mock.AlphaRegionNotificationEndpoints().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaRegionNotificationEndpoints().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.NotificationEndpoint{}
+		if err := mock.BetaRegionNotificationEndpoints().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaRegionNotificationEndpoints().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.NotificationEndpoint{}
+		if err := mock.RegionNotificationEndpoints().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("RegionNotificationEndpoints().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaRegionNotificationEndpoints().Get(ctx, key); err != nil {
+		t.Errorf("AlphaRegionNotificationEndpoints().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaRegionNotificationEndpoints().Get(ctx, key); err != nil {
+		t.Errorf("BetaRegionNotificationEndpoints().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.RegionNotificationEndpoints().Get(ctx, key); err != nil {
+		t.Errorf("RegionNotificationEndpoints().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaRegionNotificationEndpoints.Objects[*keyAlpha] = mock.MockAlphaRegionNotificationEndpoints.Obj(&alpha.NotificationEndpoint{Name: keyAlpha.Name})
+	mock.MockBetaRegionNotificationEndpoints.Objects[*keyBeta] = mock.MockBetaRegionNotificationEndpoints.Obj(&beta.NotificationEndpoint{Name: keyBeta.Name})
+	mock.MockRegionNotificationEndpoints.Objects[*keyGA] = mock.MockRegionNotificationEndpoints.Obj(&ga.NotificationEndpoint{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaRegionNotificationEndpoints().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("AlphaRegionNotificationEndpoints().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaRegionNotificationEndpoints().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaRegionNotificationEndpoints().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("BetaRegionNotificationEndpoints().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaRegionNotificationEndpoints().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.RegionNotificationEndpoints().List(ctx, location, filter.None)
+		if err != nil {
+			t.Errorf("RegionNotificationEndpoints().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("RegionNotificationEndpoints().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaRegionNotificationEndpoints().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaRegionNotificationEndpoints().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaRegionNotificationEndpoints().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaRegionNotificationEndpoints().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.RegionNotificationEndpoints().Delete(ctx, keyGA); err != nil {
+		t.Errorf("RegionNotificationEndpoints().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaRegionNotificationEndpoints().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaRegionNotificationEndpoints().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaRegionNotificationEndpoints().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaRegionNotificationEndpoints().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.RegionNotificationEndpoints().Delete(ctx, keyGA); err == nil {
+		t.Errorf("RegionNotificationEndpoints().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestRegionSslCertificatesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4023,6 +4500,141 @@ func TestTargetPoolsGroup(t *testing.T) {
 	}
 }
 
+func TestTargetSslProxiesGroup(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	pr := &SingleProjectRouter{"mock-project"}
+	mock := NewMockGCE(pr)
+
+	var key *meta.Key
+	keyAlpha := meta.GlobalKey("key-alpha")
+	key = keyAlpha
+	keyBeta := meta.GlobalKey("key-beta")
+	key = keyBeta
+	keyGA := meta.GlobalKey("key-ga")
+	key = keyGA
+	// Ignore unused variables.
+	_, _, _ = ctx, mock, key
+
+	// Get not found.
+	if _, err := mock.AlphaTargetSslProxies().Get(ctx, key); err == nil {
+		t.Errorf("AlphaTargetSslProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.BetaTargetSslProxies().Get(ctx, key); err == nil {
+		t.Errorf("BetaTargetSslProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+	if _, err := mock.TargetSslProxies().Get(ctx, key); err == nil {
+		t.Errorf("TargetSslProxies().Get(%v, %v) = _, nil; want error", ctx, key)
+	}
+
+	// Insert.
+	{
+		obj := &alpha.TargetSslProxy{}
+		if err := mock.AlphaTargetSslProxies().Insert(ctx, keyAlpha, obj); err != nil {
+			t.Errorf("AlphaTargetSslProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyAlpha, obj, err)
+		}
+	}
+	{
+		obj := &beta.TargetSslProxy{}
+		if err := mock.BetaTargetSslProxies().Insert(ctx, keyBeta, obj); err != nil {
+			t.Errorf("BetaTargetSslProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyBeta, obj, err)
+		}
+	}
+	{
+		obj := &ga.TargetSslProxy{}
+		if err := mock.TargetSslProxies().Insert(ctx, keyGA, obj); err != nil {
+			t.Errorf("TargetSslProxies().Insert(%v, %v, %v) = %v; want nil", ctx, keyGA, obj, err)
+		}
+	}
+
+	// Get across versions.
+	if obj, err := mock.AlphaTargetSslProxies().Get(ctx, key); err != nil {
+		t.Errorf("AlphaTargetSslProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.BetaTargetSslProxies().Get(ctx, key); err != nil {
+		t.Errorf("BetaTargetSslProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+	if obj, err := mock.TargetSslProxies().Get(ctx, key); err != nil {
+		t.Errorf("TargetSslProxies().Get(%v, %v) = %v, %v; want nil", ctx, key, obj, err)
+	}
+
+	// List.
+	mock.MockAlphaTargetSslProxies.Objects[*keyAlpha] = mock.MockAlphaTargetSslProxies.Obj(&alpha.TargetSslProxy{Name: keyAlpha.Name})
+	mock.MockBetaTargetSslProxies.Objects[*keyBeta] = mock.MockBetaTargetSslProxies.Obj(&beta.TargetSslProxy{Name: keyBeta.Name})
+	mock.MockTargetSslProxies.Objects[*keyGA] = mock.MockTargetSslProxies.Obj(&ga.TargetSslProxy{Name: keyGA.Name})
+	want := map[string]bool{
+		"key-alpha": true,
+		"key-beta":  true,
+		"key-ga":    true,
+	}
+	_ = want // ignore unused variables.
+	{
+		objs, err := mock.AlphaTargetSslProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("AlphaTargetSslProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("AlphaTargetSslProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.BetaTargetSslProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("BetaTargetSslProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BetaTargetSslProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+	{
+		objs, err := mock.TargetSslProxies().List(ctx, filter.None)
+		if err != nil {
+			t.Errorf("TargetSslProxies().List(%v, %v, %v) = %v, %v; want _, nil", ctx, location, filter.None, objs, err)
+		} else {
+			got := map[string]bool{}
+			for _, obj := range objs {
+				got[obj.Name] = true
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("TargetSslProxies().List(); got %+v, want %+v", got, want)
+			}
+		}
+	}
+
+	// Delete across versions.
+	if err := mock.AlphaTargetSslProxies().Delete(ctx, keyAlpha); err != nil {
+		t.Errorf("AlphaTargetSslProxies().Delete(%v, %v) = %v; want nil", ctx, keyAlpha, err)
+	}
+	if err := mock.BetaTargetSslProxies().Delete(ctx, keyBeta); err != nil {
+		t.Errorf("BetaTargetSslProxies().Delete(%v, %v) = %v; want nil", ctx, keyBeta, err)
+	}
+	if err := mock.TargetSslProxies().Delete(ctx, keyGA); err != nil {
+		t.Errorf("TargetSslProxies().Delete(%v, %v) = %v; want nil", ctx, keyGA, err)
+	}
+
+	// Delete not found.
+	if err := mock.AlphaTargetSslProxies().Delete(ctx, keyAlpha); err == nil {
+		t.Errorf("AlphaTargetSslProxies().Delete(%v, %v) = nil; want error", ctx, keyAlpha)
+	}
+	if err := mock.BetaTargetSslProxies().Delete(ctx, keyBeta); err == nil {
+		t.Errorf("BetaTargetSslProxies().Delete(%v, %v) = nil; want error", ctx, keyBeta)
+	}
+	if err := mock.TargetSslProxies().Delete(ctx, keyGA); err == nil {
+		t.Errorf("TargetSslProxies().Delete(%v, %v) = nil; want error", ctx, keyGA)
+	}
+}
+
 func TestTargetTcpProxiesGroup(t *testing.T) {
 	t.Parallel()
 
@@ -4346,6 +4958,7 @@ func TestResourceIDConversion(t *testing.T) {
 
 	for _, id := range []*ResourceID{
 		NewAddressesResourceID("some-project", "us-central1", "my-addresses-resource"),
+		NewAutoscalersResourceID("some-project", "us-east1-b", "my-autoscalers-resource"),
 		NewBackendServicesResourceID("some-project", "my-backendServices-resource"),
 		NewDisksResourceID("some-project", "us-east1-b", "my-disks-resource"),
 		NewFirewallsResourceID("some-project", "my-firewalls-resource"),
@@ -4366,8 +4979,10 @@ func TestResourceIDConversion(t *testing.T) {
 		NewProjectsResourceID("my-projects-resource"),
 		NewRegionBackendServicesResourceID("some-project", "us-central1", "my-backendServices-resource"),
 		NewRegionDisksResourceID("some-project", "us-central1", "my-disks-resource"),
+		NewRegionHealthCheckServicesResourceID("some-project", "us-central1", "my-healthCheckServices-resource"),
 		NewRegionHealthChecksResourceID("some-project", "us-central1", "my-healthChecks-resource"),
 		NewRegionNetworkFirewallPoliciesResourceID("some-project", "us-central1", "my-regionNetworkFirewallPolicies-resource"),
+		NewRegionNotificationEndpointsResourceID("some-project", "us-central1", "my-notificationEndpoints-resource"),
 		NewRegionSslCertificatesResourceID("some-project", "us-central1", "my-sslCertificates-resource"),
 		NewRegionTargetHttpProxiesResourceID("some-project", "us-central1", "my-targetHttpProxies-resource"),
 		NewRegionTargetHttpsProxiesResourceID("some-project", "us-central1", "my-targetHttpsProxies-resource"),
@@ -4383,6 +4998,7 @@ func TestResourceIDConversion(t *testing.T) {
 		NewTargetHttpProxiesResourceID("some-project", "my-targetHttpProxies-resource"),
 		NewTargetHttpsProxiesResourceID("some-project", "my-targetHttpsProxies-resource"),
 		NewTargetPoolsResourceID("some-project", "us-central1", "my-targetPools-resource"),
+		NewTargetSslProxiesResourceID("some-project", "my-targetSslProxies-resource"),
 		NewTargetTcpProxiesResourceID("some-project", "my-targetTcpProxies-resource"),
 		NewUrlMapsResourceID("some-project", "my-urlMaps-resource"),
 		NewZonesResourceID("some-project", "my-zones-resource"),
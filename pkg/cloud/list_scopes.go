@@ -0,0 +1,158 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"k8s.io/klog/v2"
+)
+
+// DefaultScopedListParallelism is the number of concurrent List() calls made
+// by ListZonal() and ListRegional() if parallelism is not specified.
+const DefaultScopedListParallelism = 8
+
+// ScopedListResult is the result of listing a resource across multiple zones
+// or regions.
+type ScopedListResult[T any] struct {
+	// Objects found in all of the scopes that were listed successfully.
+	Objects map[meta.Key]T
+	// Errors by zone or region. Objects from scopes with errors are not
+	// included in Objects.
+	Errors map[string]error
+}
+
+// Err returns an error combining all of the per-scope errors. Returns nil if
+// all scopes were listed successfully.
+func (r *ScopedListResult[T]) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	var scopes []string
+	for s := range r.Errors {
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+	var errs []error
+	for _, s := range scopes {
+		errs = append(errs, fmt.Errorf("%s: %w", s, r.Errors[s]))
+	}
+	return errors.Join(errs...)
+}
+
+// ListZonal lists a zonal resource in all of the given zones, making up to
+// parallelism concurrent calls (DefaultScopedListParallelism if <= 0). list is
+// the List method of the resource (e.g. gce.Instances().List). Calls are made
+// through the rate limiter of the Cloud so parallelism only bounds the number
+// of outstanding calls.
+//
+// Errors are reported per zone; the objects from the other zones are still
+// returned.
+func ListZonal[T any](ctx context.Context, zones []string, list func(context.Context, string, *filter.F) ([]T, error), fl *filter.F, parallelism int) *ScopedListResult[T] {
+	return listScopes(ctx, zones, list, fl, parallelism, func(name, zone string) meta.Key { return *meta.ZonalKey(name, zone) })
+}
+
+// ListRegional lists a regional resource in all of the given regions. See
+// ListZonal().
+func ListRegional[T any](ctx context.Context, regions []string, list func(context.Context, string, *filter.F) ([]T, error), fl *filter.F, parallelism int) *ScopedListResult[T] {
+	return listScopes(ctx, regions, list, fl, parallelism, func(name, region string) meta.Key { return *meta.RegionalKey(name, region) })
+}
+
+func listScopes[T any](
+	ctx context.Context,
+	scopes []string,
+	list func(context.Context, string, *filter.F) ([]T, error),
+	fl *filter.F,
+	parallelism int,
+	makeKey func(name, scope string) meta.Key,
+) *ScopedListResult[T] {
+	if parallelism <= 0 {
+		parallelism = DefaultScopedListParallelism
+	}
+	ret := &ScopedListResult[T]{
+		Objects: map[meta.Key]T{},
+		Errors:  map[string]error{},
+	}
+
+	var (
+		lock sync.Mutex
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, parallelism)
+	)
+	done := func(scope string, objs []T, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if err != nil {
+			ret.Errors[scope] = err
+			return
+		}
+		for _, obj := range objs {
+			ret.Objects[makeKey(objectName(obj), scope)] = obj
+		}
+	}
+
+	for _, scope := range scopes {
+		if err := ctx.Err(); err != nil {
+			done(scope, nil, err)
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			done(scope, nil, ctx.Err())
+			continue
+		}
+		wg.Add(1)
+		go func(scope string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			objs, err := list(ctx, scope, fl)
+			klog.V(5).Infof("listScopes(%q) = [%d items], %v", scope, len(objs), err)
+			done(scope, objs, err)
+		}(scope)
+	}
+	wg.Wait()
+
+	return ret
+}
+
+// objectName returns the value of the Name field of the compute API object.
+func objectName(obj any) string {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Name"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestListZonal(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	zones := []string{"us-central1-a", "us-central1-b", "us-central1-c"}
+	testErr := errors.New("injected error")
+
+	for _, parallelism := range []int{0, 1, 2} {
+		mock := NewMockGCE(&SingleProjectRouter{"proj1"})
+		for _, key := range []*meta.Key{
+			meta.ZonalKey("a1", "us-central1-a"),
+			meta.ZonalKey("a2", "us-central1-a"),
+			meta.ZonalKey("b1", "us-central1-b"),
+			meta.ZonalKey("c1", "us-central1-c"),
+		} {
+			mock.Instances().Insert(ctx, key, &ga.Instance{})
+		}
+
+		var (
+			lock             sync.Mutex
+			inFlight, maxIFl int
+		)
+		mock.MockInstances.ListHook = func(ctx context.Context, zone string, fl *filter.F, m *MockInstances) (bool, []*ga.Instance, error) {
+			lock.Lock()
+			inFlight++
+			if inFlight > maxIFl {
+				maxIFl = inFlight
+			}
+			lock.Unlock()
+			defer func() {
+				lock.Lock()
+				inFlight--
+				lock.Unlock()
+			}()
+			if zone == "us-central1-c" {
+				return true, nil, testErr
+			}
+			return false, nil, nil
+		}
+
+		res := ListZonal(ctx, zones, mock.Instances().List, filter.None, parallelism)
+
+		var gotKeys []string
+		for k, obj := range res.Objects {
+			if k.Name != obj.Name {
+				t.Errorf("parallelism=%d: Objects[%v].Name = %q", parallelism, k, obj.Name)
+			}
+			gotKeys = append(gotKeys, k.String())
+		}
+		sort.Strings(gotKeys)
+		wantKeys := []string{
+			meta.ZonalKey("a1", "us-central1-a").String(),
+			meta.ZonalKey("a2", "us-central1-a").String(),
+			meta.ZonalKey("b1", "us-central1-b").String(),
+		}
+		if diff := cmp.Diff(gotKeys, wantKeys); diff != "" {
+			t.Errorf("parallelism=%d: Objects; -got,+want: %s", parallelism, diff)
+		}
+		if len(res.Errors) != 1 || !errors.Is(res.Errors["us-central1-c"], testErr) {
+			t.Errorf("parallelism=%d: Errors = %v, want error for us-central1-c", parallelism, res.Errors)
+		}
+		if !errors.Is(res.Err(), testErr) {
+			t.Errorf("parallelism=%d: Err() = %v, want %v", parallelism, res.Err(), testErr)
+		}
+		if parallelism > 0 && maxIFl > parallelism {
+			t.Errorf("parallelism=%d: max in flight = %d", parallelism, maxIFl)
+		}
+	}
+}
+
+func TestListRegionalCanceled(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockGCE(&SingleProjectRouter{"proj1"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	res := ListRegional(ctx, []string{"us-central1", "us-east1"}, mock.Addresses().List, filter.None, 1)
+	if res.Err() == nil {
+		t.Errorf("Err() = nil, want error")
+	}
+	if len(res.Objects) != 0 {
+		t.Errorf("Objects = %v, want empty", res.Objects)
+	}
+}
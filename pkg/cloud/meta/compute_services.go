@@ -403,6 +403,66 @@ var ComputeServices = []*ServiceInfo{
 			"Update",
 		},
 	},
+	{
+		Object:      "HealthCheckService",
+		Service:     "RegionHealthCheckServices",
+		Resource:    "healthCheckServices",
+		version:     VersionAlpha,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&alpha.RegionHealthCheckServicesService{}),
+		listType:    "HealthCheckServicesList",
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "HealthCheckService",
+		Service:     "RegionHealthCheckServices",
+		Resource:    "healthCheckServices",
+		version:     VersionBeta,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&beta.RegionHealthCheckServicesService{}),
+		listType:    "HealthCheckServicesList",
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "HealthCheckService",
+		Service:     "RegionHealthCheckServices",
+		Resource:    "healthCheckServices",
+		version:     VersionGA,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.RegionHealthCheckServicesService{}),
+		listType:    "HealthCheckServicesList",
+		additionalMethods: []string{
+			"Patch",
+		},
+	},
+	{
+		Object:      "NotificationEndpoint",
+		Service:     "RegionNotificationEndpoints",
+		Resource:    "notificationEndpoints",
+		version:     VersionAlpha,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&alpha.RegionNotificationEndpointsService{}),
+	},
+	{
+		Object:      "NotificationEndpoint",
+		Service:     "RegionNotificationEndpoints",
+		Resource:    "notificationEndpoints",
+		version:     VersionBeta,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&beta.RegionNotificationEndpointsService{}),
+	},
+	{
+		Object:      "NotificationEndpoint",
+		Service:     "RegionNotificationEndpoints",
+		Resource:    "notificationEndpoints",
+		version:     VersionGA,
+		keyType:     Regional,
+		serviceType: reflect.TypeOf(&ga.RegionNotificationEndpointsService{}),
+	},
 	{
 		Object:      "HttpHealthCheck",
 		Service:     "HttpHealthChecks",
@@ -446,6 +506,7 @@ var ComputeServices = []*ServiceInfo{
 			"AttachDisk",
 			"DetachDisk",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Instance",
@@ -459,6 +520,7 @@ var ComputeServices = []*ServiceInfo{
 			"DetachDisk",
 			"UpdateNetworkInterface",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "Instance",
@@ -472,6 +534,7 @@ var ComputeServices = []*ServiceInfo{
 			"DetachDisk",
 			"UpdateNetworkInterface",
 		},
+		options: AggregatedList,
 	},
 	{
 		Object:      "InstanceGroupManager",
@@ -482,10 +545,27 @@ var ComputeServices = []*ServiceInfo{
 		additionalMethods: []string{
 			"CreateInstances",
 			"DeleteInstances",
+			"Patch",
 			"Resize",
 			"SetInstanceTemplate",
 		},
 	},
+	{
+		Object:      "InstanceTemplate",
+		Service:     "InstanceTemplates",
+		Resource:    "instanceTemplates",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.InstanceTemplatesService{}),
+	},
+	{
+		Object:      "InstanceTemplate",
+		Service:     "InstanceTemplates",
+		Resource:    "instanceTemplates",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.InstanceTemplatesService{}),
+	},
 	{
 		Object:      "InstanceTemplate",
 		Service:     "InstanceTemplates",
@@ -493,6 +573,35 @@ var ComputeServices = []*ServiceInfo{
 		keyType:     Global,
 		serviceType: reflect.TypeOf(&ga.InstanceTemplatesService{}),
 	},
+	// Autoscalers.Update() and Patch() name the resource in the request
+	// body instead of the URL and cannot be generated.
+	{
+		Object:      "Autoscaler",
+		Service:     "Autoscalers",
+		Resource:    "autoscalers",
+		version:     VersionAlpha,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&alpha.AutoscalersService{}),
+		options:     AggregatedList,
+	},
+	{
+		Object:      "Autoscaler",
+		Service:     "Autoscalers",
+		Resource:    "autoscalers",
+		version:     VersionBeta,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&beta.AutoscalersService{}),
+		options:     AggregatedList,
+	},
+	{
+		Object:      "Autoscaler",
+		Service:     "Autoscalers",
+		Resource:    "autoscalers",
+		version:     VersionGA,
+		keyType:     Zonal,
+		serviceType: reflect.TypeOf(&ga.AutoscalersService{}),
+		options:     AggregatedList,
+	},
 	{
 		Object:      "Image",
 		Service:     "Images",
@@ -966,6 +1075,51 @@ var ComputeServices = []*ServiceInfo{
 			"RemoveInstance",
 		},
 	},
+	{
+		Object:      "TargetSslProxy",
+		Service:     "TargetSslProxies",
+		Resource:    "targetSslProxies",
+		version:     VersionAlpha,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&alpha.TargetSslProxiesService{}),
+		additionalMethods: []string{
+			"SetBackendService",
+			"SetCertificateMap",
+			"SetProxyHeader",
+			"SetSslCertificates",
+			"SetSslPolicy",
+		},
+	},
+	{
+		Object:      "TargetSslProxy",
+		Service:     "TargetSslProxies",
+		Resource:    "targetSslProxies",
+		version:     VersionBeta,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&beta.TargetSslProxiesService{}),
+		additionalMethods: []string{
+			"SetBackendService",
+			"SetCertificateMap",
+			"SetProxyHeader",
+			"SetSslCertificates",
+			"SetSslPolicy",
+		},
+	},
+	{
+		Object:      "TargetSslProxy",
+		Service:     "TargetSslProxies",
+		Resource:    "targetSslProxies",
+		version:     VersionGA,
+		keyType:     Global,
+		serviceType: reflect.TypeOf(&ga.TargetSslProxiesService{}),
+		additionalMethods: []string{
+			"SetBackendService",
+			"SetCertificateMap",
+			"SetProxyHeader",
+			"SetSslCertificates",
+			"SetSslPolicy",
+		},
+	},
 	{
 		Object:      "TargetTcpProxy",
 		Service:     "TargetTcpProxies",
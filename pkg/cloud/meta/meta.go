@@ -60,8 +60,36 @@ const (
 
 	// APIGroupNetworkServices is the networkservices API group.
 	APIGroupNetworkServices APIGroup = "networkservices"
+
+	// APIGroupCertificateManager is the certificatemanager API group.
+	APIGroupCertificateManager APIGroup = "certificatemanager"
 )
 
+// AllAPIGroups is a list of all of the supported API groups.
+var AllAPIGroups = []APIGroup{
+	APIGroupCompute,
+	APIGroupNetworkServices,
+	APIGroupCertificateManager,
+}
+
+// VersionPath returns the path segment used for the version in resource
+// URLs of the API group (e.g. "v1", "beta"). The beta version is "v1beta1"
+// for API groups other than compute. Returns "" for an invalid version.
+func (g APIGroup) VersionPath(ver Version) string {
+	switch ver {
+	case VersionGA:
+		return "v1"
+	case VersionAlpha:
+		return "alpha"
+	case VersionBeta:
+		if g == APIGroupCompute {
+			return "beta"
+		}
+		return "v1beta1"
+	}
+	return ""
+}
+
 // AllVersions is a list of all versions of the GCE API.
 var AllVersions = []Version{
 	VersionGA,
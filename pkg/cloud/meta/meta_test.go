@@ -0,0 +1,68 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package meta
+
+import (
+	"testing"
+)
+
+func TestAPIGroupVersionPath(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		group APIGroup
+		ver   Version
+		want  string
+	}{
+		{APIGroupCompute, VersionGA, "v1"},
+		{APIGroupCompute, VersionAlpha, "alpha"},
+		{APIGroupCompute, VersionBeta, "beta"},
+		{APIGroupNetworkServices, VersionGA, "v1"},
+		{APIGroupNetworkServices, VersionBeta, "v1beta1"},
+		{APIGroupCertificateManager, VersionBeta, "v1beta1"},
+		{APIGroupCompute, Version("invalid"), ""},
+	} {
+		if got := tc.group.VersionPath(tc.ver); got != tc.want {
+			t.Errorf("%q.VersionPath(%q) = %q, want %q", tc.group, tc.ver, got, tc.want)
+		}
+	}
+}
+
+func TestGroupServicesAPIGroupConflict(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("groupServices() did not panic, want panic")
+		}
+	}()
+	groupServices([]*ServiceInfo{
+		{Service: "Foos", APIGroup: APIGroupCompute},
+		{Service: "Foos", APIGroup: APIGroupNetworkServices, version: VersionBeta},
+	})
+}
+
+func TestKeyTypeConstInvalid(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("KeyTypeConst() did not panic, want panic")
+		}
+	}()
+	(&ServiceInfo{Service: "Foos", keyType: KeyType("invalid")}).KeyTypeConst()
+}
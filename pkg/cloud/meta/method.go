@@ -134,6 +134,19 @@ func (m *Method) IsGet() bool {
 	return m.kind == MethodGet
 }
 
+// HasValidateOnly is true if the method call supports the validateOnly
+// parameter.
+func (m *Method) HasValidateOnly() bool {
+	return hasValidateOnly(m.m.Func.Type().Out(0))
+}
+
+// hasValidateOnly returns true if the xxxCall type has a ValidateOnly()
+// setter.
+func hasValidateOnly(callType reflect.Type) bool {
+	_, ok := callType.MethodByName("ValidateOnly")
+	return ok
+}
+
 // argsSkip is the number of arguments to skip when generating the
 // synthesized method.
 func (m *Method) argsSkip() int {
@@ -44,6 +44,9 @@ type ServiceInfo struct {
 	additionalMethods   []string
 	options             int
 	aggregatedListField string
+	// listType overrides the name of the List type for services where it
+	// does not follow the <Object>List convention.
+	listType string
 }
 
 // Version returns the version of the Service, defaulting to GA if APIVersion
@@ -78,7 +81,7 @@ func (i *ServiceInfo) WrapType() string {
 	case VersionBeta:
 		return "Beta" + i.Service
 	}
-	return "Invalid"
+	panic(fmt.Errorf("invalid version %q", i.Version()))
 }
 
 // WrapTypeOps is the name of the additional operations type.
@@ -93,6 +96,9 @@ func (i *ServiceInfo) FQObjectType() string {
 
 // ObjectListType is the compute List type for the object (contains Items field).
 func (i *ServiceInfo) ObjectListType() string {
+	if i.listType != "" {
+		return fmt.Sprintf("%v.%v", i.Version(), i.listType)
+	}
 	return fmt.Sprintf("%v.%vList", i.Version(), i.Object)
 }
 
@@ -172,6 +178,20 @@ func (i *ServiceInfo) KeyIsZonal() bool {
 	return i.keyType == Zonal
 }
 
+// KeyTypeConst is the Go expression for the key type of the service (e.g.
+// "meta.Zonal").
+func (i *ServiceInfo) KeyTypeConst() string {
+	switch i.keyType {
+	case Global:
+		return "meta.Global"
+	case Regional:
+		return "meta.Regional"
+	case Zonal:
+		return "meta.Zonal"
+	}
+	panic(fmt.Errorf("invalid key type %q for %s", i.keyType, i.Service))
+}
+
 // KeyIsProject is true if the key represents the project resource.
 func (i *ServiceInfo) KeyIsProject() bool {
 	// Projects are a special resource for ResourceId because there is no 'key' value. This func
@@ -189,7 +209,7 @@ func (i *ServiceInfo) MakeKey(name, location string) string {
 	case Zonal:
 		return fmt.Sprintf("ZonalKey(%q, %q)", name, location)
 	}
-	return "Invalid"
+	panic(fmt.Errorf("invalid key type %q for %s", i.keyType, i.Service))
 }
 
 // GenerateGet is true if the method is to be generated.
@@ -212,6 +232,16 @@ func (i *ServiceInfo) GenerateInsert() bool {
 	return i.options&NoInsert == 0
 }
 
+// InsertValidateOnly is true if the Insert call supports the validateOnly
+// parameter.
+func (i *ServiceInfo) InsertValidateOnly() bool {
+	m, ok := i.serviceType.MethodByName("Insert")
+	if !ok {
+		return false
+	}
+	return hasValidateOnly(m.Func.Type().Out(0))
+}
+
 // GenerateCustomOps is true if we should generated a xxxOps interface for
 // adding additional methods to the generated interface.
 func (i *ServiceInfo) GenerateCustomOps() bool {
@@ -287,6 +317,13 @@ func groupServices(services []*ServiceInfo) map[string]*ServiceGroup {
 			ret[si.Service] = &ServiceGroup{}
 		}
 		group := ret[si.Service]
+		// The generated Cloud interface spans all API groups, so
+		// Service names must be unique across groups.
+		for _, other := range []*ServiceInfo{group.GA, group.Alpha, group.Beta} {
+			if other != nil && other.APIGroup != si.APIGroup {
+				panic(fmt.Errorf("service %q is defined in API groups %q and %q", si.Service, other.APIGroup, si.APIGroup))
+			}
+		}
 		switch si.Version() {
 		case VersionAlpha:
 			group.Alpha = si
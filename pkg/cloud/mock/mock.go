@@ -405,6 +405,145 @@ func RemoveInstancesHook(ctx context.Context, key *meta.Key, req *ga.InstanceGro
 	return nil
 }
 
+// SetNamedPortsHook mocks setting the named ports of an InstanceGroup.
+func SetNamedPortsHook(ctx context.Context, key *meta.Key, req *ga.InstanceGroupsSetNamedPortsRequest, m *cloud.MockInstanceGroups) error {
+	ig, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	ig.NamedPorts = req.NamedPorts
+	return nil
+}
+
+// ResizeInstanceGroupManagerHook mocks resizing an InstanceGroupManager.
+func ResizeInstanceGroupManagerHook(ctx context.Context, key *meta.Key, size int64, m *cloud.MockInstanceGroupManagers) error {
+	igm, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	igm.TargetSize = size
+	return nil
+}
+
+// SetInstanceTemplateInstanceGroupManagerHook mocks setting the instance
+// template of an InstanceGroupManager.
+func SetInstanceTemplateInstanceGroupManagerHook(ctx context.Context, key *meta.Key, req *ga.InstanceGroupManagersSetInstanceTemplateRequest, m *cloud.MockInstanceGroupManagers) error {
+	igm, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	igm.InstanceTemplate = req.InstanceTemplate
+	return nil
+}
+
+// PatchInstanceGroupManagerHook mocks patching an InstanceGroupManager. The
+// patch is applied with JSON merge patch semantics (RFC 7386), which is what
+// the API uses for PATCH requests.
+func PatchInstanceGroupManagerHook(ctx context.Context, key *meta.Key, obj *ga.InstanceGroupManager, m *cloud.MockInstanceGroupManagers) error {
+	igm, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	patched := &ga.InstanceGroupManager{}
+	if err := jsonMergePatch(patched, igm, obj); err != nil {
+		return err
+	}
+	// Name and SelfLink cannot be changed by a patch.
+	patched.Name = igm.Name
+	patched.SelfLink = igm.SelfLink
+	*igm = *patched
+	return nil
+}
+
+// jsonMergePatch applies patch to orig and stores the result in dest.
+func jsonMergePatch(dest any, orig, patch gceObject) error {
+	var o, p map[string]any
+	for _, x := range []struct {
+		obj gceObject
+		m   *map[string]any
+	}{{orig, &o}, {patch, &p}} {
+		b, err := x.obj.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(b, x.m); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(mergePatch(o, p))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dest)
+}
+
+func mergePatch(orig any, patch any) any {
+	pm, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	om, ok := orig.(map[string]any)
+	if !ok {
+		om = map[string]any{}
+	}
+	for k, v := range pm {
+		if v == nil {
+			delete(om, k)
+			continue
+		}
+		om[k] = mergePatch(om[k], v)
+	}
+	return om
+}
+
+// PatchRegionHealthCheckServiceHook mocks patching a HealthCheckService.
+func PatchRegionHealthCheckServiceHook(ctx context.Context, key *meta.Key, obj *ga.HealthCheckService, m *cloud.MockRegionHealthCheckServices) error {
+	hcs, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	patched := &ga.HealthCheckService{}
+	if err := jsonMergePatch(patched, hcs, obj); err != nil {
+		return err
+	}
+	patched.Name = hcs.Name
+	patched.SelfLink = hcs.SelfLink
+	*hcs = *patched
+	return nil
+}
+
+// PatchAlphaRegionHealthCheckServiceHook mocks patching an alpha HealthCheckService.
+func PatchAlphaRegionHealthCheckServiceHook(ctx context.Context, key *meta.Key, obj *alpha.HealthCheckService, m *cloud.MockAlphaRegionHealthCheckServices) error {
+	hcs, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	patched := &alpha.HealthCheckService{}
+	if err := jsonMergePatch(patched, hcs, obj); err != nil {
+		return err
+	}
+	patched.Name = hcs.Name
+	patched.SelfLink = hcs.SelfLink
+	*hcs = *patched
+	return nil
+}
+
+// PatchBetaRegionHealthCheckServiceHook mocks patching a beta HealthCheckService.
+func PatchBetaRegionHealthCheckServiceHook(ctx context.Context, key *meta.Key, obj *beta.HealthCheckService, m *cloud.MockBetaRegionHealthCheckServices) error {
+	hcs, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	patched := &beta.HealthCheckService{}
+	if err := jsonMergePatch(patched, hcs, obj); err != nil {
+		return err
+	}
+	patched.Name = hcs.Name
+	patched.SelfLink = hcs.SelfLink
+	*hcs = *patched
+	return nil
+}
+
 // UpdateFirewallHook defines the hook for updating a Firewall. It replaces the
 // object with the same key in the mock with the updated object.
 func UpdateFirewallHook(ctx context.Context, key *meta.Key, obj *ga.Firewall, m *cloud.MockFirewalls) error {
@@ -949,6 +1088,56 @@ func SetBackendServiceBetaTargetTCPProxyHook(ctx context.Context, key *meta.Key,
 	return nil
 }
 
+// SetBackendServiceTargetSSLProxyHook defines the hook for setting the backend service for a TargetSslProxy.
+func SetBackendServiceTargetSSLProxyHook(ctx context.Context, key *meta.Key, req *ga.TargetSslProxiesSetBackendServiceRequest, m *cloud.MockTargetSslProxies) error {
+	tp, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	tp.Service = req.Service
+	return nil
+}
+
+// SetSslCertificatesTargetSSLProxyHook defines the hook for setting ssl certificates for a TargetSslProxy.
+func SetSslCertificatesTargetSSLProxyHook(ctx context.Context, key *meta.Key, req *ga.TargetSslProxiesSetSslCertificatesRequest, m *cloud.MockTargetSslProxies) error {
+	tp, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	tp.SslCertificates = req.SslCertificates
+	return nil
+}
+
+// SetSslPolicyTargetSSLProxyHook defines the hook for setting the ssl policy for a TargetSslProxy.
+func SetSslPolicyTargetSSLProxyHook(ctx context.Context, key *meta.Key, req *ga.SslPolicyReference, m *cloud.MockTargetSslProxies) error {
+	tp, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	tp.SslPolicy = req.SslPolicy
+	return nil
+}
+
+// SetProxyHeaderTargetSSLProxyHook defines the hook for setting the proxy header for a TargetSslProxy.
+func SetProxyHeaderTargetSSLProxyHook(ctx context.Context, key *meta.Key, req *ga.TargetSslProxiesSetProxyHeaderRequest, m *cloud.MockTargetSslProxies) error {
+	tp, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	tp.ProxyHeader = req.ProxyHeader
+	return nil
+}
+
+// SetCertificateMapTargetSSLProxyHook defines the hook for setting the certificate map for a TargetSslProxy.
+func SetCertificateMapTargetSSLProxyHook(ctx context.Context, key *meta.Key, req *ga.TargetSslProxiesSetCertificateMapRequest, m *cloud.MockTargetSslProxies) error {
+	tp, err := m.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	tp.CertificateMap = req.CertificateMap
+	return nil
+}
+
 // SetSslCertificateTargetHTTPSProxyHook defines the hook for setting ssl certificates on a TargetHttpsProxy.
 func SetSslCertificateTargetHTTPSProxyHook(ctx context.Context, key *meta.Key, req *ga.TargetHttpsProxiesSetSslCertificatesRequest, m *cloud.MockTargetHttpsProxies) error {
 	tp, err := m.Get(ctx, key)
@@ -0,0 +1,215 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// ChangeType is the type of change to a mock object between two snapshots.
+type ChangeType string
+
+const (
+	// Created objects exist only in the later snapshot.
+	Created ChangeType = "Created"
+	// Updated objects exist in both snapshots with different values.
+	Updated ChangeType = "Updated"
+	// Deleted objects exist only in the earlier snapshot.
+	Deleted ChangeType = "Deleted"
+)
+
+// SnapshotKey identifies an object in a Snapshot.
+type SnapshotKey struct {
+	// Resource is the name of the mock resource, e.g. "Addresses" or
+	// "GlobalAddresses".
+	Resource string
+	Key      meta.Key
+}
+
+// String implements Stringer.
+func (k SnapshotKey) String() string {
+	return fmt.Sprintf("%s:%s", k.Resource, k.Key.String())
+}
+
+// Snapshot is a point-in-time copy of the objects stored in a MockGCE.
+type Snapshot struct {
+	// objects are stored as generic JSON values so that later changes to
+	// the mock do not affect the snapshot.
+	objects map[SnapshotKey]any
+}
+
+// Len is the number of objects in the snapshot.
+func (s *Snapshot) Len() int { return len(s.objects) }
+
+// Change to a single object between two snapshots.
+type Change struct {
+	Type ChangeType
+	SnapshotKey
+	// Fields that differ for Updated objects, using the JSON field names
+	// (e.g. "description", "backends[0].group"). Fields are in sorted order.
+	Fields []string
+}
+
+// String implements Stringer.
+func (c Change) String() string {
+	if c.Type != Updated {
+		return fmt.Sprintf("%s %s", c.Type, c.SnapshotKey)
+	}
+	return fmt.Sprintf("%s %s %v", c.Type, c.SnapshotKey, c.Fields)
+}
+
+// TakeSnapshot of the objects in all of the mocks in m. Mocks for different
+// API versions of the same resource share the same objects and are only
+// included once.
+//
+// Example:
+//
+//	before := mock.TakeSnapshot(mockGCE)
+//	// ... run code under test ...
+//	changes, err := mock.DiffSnapshots(before, mock.TakeSnapshot(mockGCE))
+func TakeSnapshot(m *cloud.MockGCE) (*Snapshot, error) {
+	ret := &Snapshot{objects: map[SnapshotKey]any{}}
+	seen := map[uintptr]bool{}
+
+	mv := reflect.ValueOf(m).Elem()
+	for i := 0; i < mv.NumField(); i++ {
+		fv := mv.Field(i)
+		if fv.Kind() != reflect.Pointer || fv.IsNil() || fv.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		mockV := fv.Elem()
+		objects := mockV.FieldByName("Objects")
+		lockV := mockV.FieldByName("Lock")
+		if objects.Kind() != reflect.Map || !lockV.IsValid() || objects.IsNil() {
+			continue
+		}
+		if seen[objects.Pointer()] {
+			continue
+		}
+		seen[objects.Pointer()] = true
+
+		resource := objects.Type().Elem().Elem().Name()
+		resource = strings.TrimSuffix(strings.TrimPrefix(resource, "Mock"), "Obj")
+
+		lock := lockV.Addr().Interface().(*sync.Mutex)
+		lock.Lock()
+		err := snapshotObjects(ret, resource, objects)
+		lock.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func snapshotObjects(s *Snapshot, resource string, objects reflect.Value) error {
+	iter := objects.MapRange()
+	for iter.Next() {
+		key, ok := iter.Key().Interface().(meta.Key)
+		if !ok {
+			return fmt.Errorf("TakeSnapshot: %s: invalid key type %T", resource, iter.Key().Interface())
+		}
+		obj := iter.Value()
+		if obj.IsNil() {
+			continue
+		}
+		raw, err := json.Marshal(obj.Elem().FieldByName("Obj").Interface())
+		if err != nil {
+			return fmt.Errorf("TakeSnapshot: %s %v: %w", resource, key, err)
+		}
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return fmt.Errorf("TakeSnapshot: %s %v: %w", resource, key, err)
+		}
+		s.objects[SnapshotKey{Resource: resource, Key: key}] = v
+	}
+	return nil
+}
+
+// DiffSnapshots returns the objects that were created, updated or deleted
+// between before and after. Changes are sorted by resource and key.
+func DiffSnapshots(before, after *Snapshot) []Change {
+	var ret []Change
+	for k, bv := range before.objects {
+		av, ok := after.objects[k]
+		if !ok {
+			ret = append(ret, Change{Type: Deleted, SnapshotKey: k})
+			continue
+		}
+		var fields []string
+		diffJSON("", bv, av, &fields)
+		if len(fields) > 0 {
+			sort.Strings(fields)
+			ret = append(ret, Change{Type: Updated, SnapshotKey: k, Fields: fields})
+		}
+	}
+	for k := range after.objects {
+		if _, ok := before.objects[k]; !ok {
+			ret = append(ret, Change{Type: Created, SnapshotKey: k})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].SnapshotKey.String() < ret[j].SnapshotKey.String() })
+	return ret
+}
+
+// diffJSON appends the paths of the differences between the generic JSON
+// values a and b to out. Lists with different lengths are reported as a
+// single difference.
+func diffJSON(path string, a, b any, out *[]string) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		for k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			diffJSON(p, av[k], bv[k], out)
+		}
+		return
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			break
+		}
+		for i := range av {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], out)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*out = append(*out, path)
+	}
+}
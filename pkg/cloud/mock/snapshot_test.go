@@ -0,0 +1,79 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"testing"
+
+	cloud "github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: "proj1"})
+
+	m.Addresses().Insert(ctx, meta.RegionalKey("unchanged", "us-central1"), &ga.Address{Address: "10.0.0.1"})
+	m.Addresses().Insert(ctx, meta.RegionalKey("updated", "us-central1"), &ga.Address{Address: "10.0.0.2"})
+	m.Addresses().Insert(ctx, meta.RegionalKey("deleted", "us-central1"), &ga.Address{})
+	m.HealthChecks().Insert(ctx, meta.GlobalKey("hc"), &ga.HealthCheck{})
+
+	before, err := TakeSnapshot(m)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() = %v", err)
+	}
+	// Addresses are visible from the GA, Alpha and Beta mocks and must be
+	// counted only once.
+	if before.Len() != 4 {
+		t.Errorf("before.Len() = %d, want 4", before.Len())
+	}
+
+	m.Addresses().Delete(ctx, meta.RegionalKey("deleted", "us-central1"))
+	// Objects are updated in place by some code.
+	obj := m.MockAddresses.Objects[*meta.RegionalKey("updated", "us-central1")]
+	obj.Obj.(*ga.Address).Description = "new"
+	obj.Obj.(*ga.Address).Address = "10.0.0.3"
+	m.AlphaGlobalAddresses().Insert(ctx, meta.GlobalKey("created"), &alpha.Address{})
+
+	after, err := TakeSnapshot(m)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() = %v", err)
+	}
+
+	got := DiffSnapshots(before, after)
+	want := []Change{
+		{Type: Deleted, SnapshotKey: SnapshotKey{"Addresses", *meta.RegionalKey("deleted", "us-central1")}},
+		{
+			Type:        Updated,
+			SnapshotKey: SnapshotKey{"Addresses", *meta.RegionalKey("updated", "us-central1")},
+			Fields:      []string{"address", "description"},
+		},
+		{Type: Created, SnapshotKey: SnapshotKey{"GlobalAddresses", *meta.GlobalKey("created")}},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("DiffSnapshots(); -got,+want: %s", diff)
+	}
+	if got := DiffSnapshots(after, after); len(got) != 0 {
+		t.Errorf("DiffSnapshots(after, after) = %v, want []", got)
+	}
+}
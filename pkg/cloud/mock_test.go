@@ -18,12 +18,15 @@ package cloud
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"reflect"
 	"testing"
 
 	alpha "google.golang.org/api/compute/v0.alpha"
 	beta "google.golang.org/api/compute/v0.beta"
 	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -149,3 +152,78 @@ func TestMocks(t *testing.T) {
 		t.Errorf("Addresses().Delete(%v, %v) = nil; want error", ctx, key)
 	}
 }
+
+func TestMockKeyScope(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"mock-project"})
+
+	for _, tc := range []struct {
+		desc     string
+		call     func() error
+		wantCode int
+	}{
+		{
+			desc:     "regional resource, zonal key",
+			call:     func() error { return mock.Addresses().Insert(ctx, meta.ZonalKey("a", "us-central1-b"), &ga.Address{}) },
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc: "regional resource, global key",
+			call: func() error {
+				_, err := mock.Addresses().Get(ctx, meta.GlobalKey("a"))
+				return err
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc:     "zonal resource, global key",
+			call:     func() error { return mock.Instances().Delete(ctx, meta.GlobalKey("i")) },
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "global resource, regional key",
+			call: func() error {
+				_, err := mock.GlobalAddresses().Get(ctx, meta.RegionalKey("a", "us-central1"))
+				return err
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc: "zonal resource, global key, custom method",
+			call: func() error {
+				return mock.InstanceGroups().SetNamedPorts(ctx, meta.GlobalKey("ig"), &ga.InstanceGroupsSetNamedPortsRequest{})
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			desc: "global resource, zonal key, custom get method",
+			call: func() error {
+				_, err := mock.BackendServices().GetHealth(ctx, meta.ZonalKey("bs", "us-central1-b"), &ga.ResourceGroupReference{})
+				return err
+			},
+			wantCode: http.StatusNotFound,
+		},
+		{
+			desc: "zonal resource, regional key, custom paged method",
+			call: func() error {
+				_, err := mock.InstanceGroups().ListInstances(ctx, meta.RegionalKey("ig", "us-central1"), &ga.InstanceGroupsListInstancesRequest{}, filter.None)
+				return err
+			},
+			wantCode: http.StatusNotFound,
+		},
+	} {
+		err := tc.call()
+		var apiErr *googleapi.Error
+		if !errors.As(err, &apiErr) || apiErr.Code != tc.wantCode {
+			t.Errorf("%s: got %v, want googleapi.Error with code %d", tc.desc, err, tc.wantCode)
+		}
+	}
+
+	// Keys with the right scope are accepted.
+	key := meta.RegionalKey("a", "us-central1")
+	if err := mock.Addresses().Insert(ctx, key, &ga.Address{}); err != nil {
+		t.Errorf("Addresses().Insert(%v) = %v, want nil", key, err)
+	}
+}
@@ -120,6 +120,7 @@ func (o *gaOperation) rateLimitKey() *RateLimitKey {
 		ProjectID: o.projectID,
 		Operation: "Get",
 		Service:   "Operations",
+		APIGroup:  meta.APIGroupCompute,
 		Version:   meta.VersionGA,
 	}
 }
@@ -194,6 +195,7 @@ func (o *alphaOperation) rateLimitKey() *RateLimitKey {
 		ProjectID: o.projectID,
 		Operation: "Get",
 		Service:   "Operations",
+		APIGroup:  meta.APIGroupCompute,
 		Version:   meta.VersionAlpha,
 	}
 }
@@ -267,6 +269,7 @@ func (o *betaOperation) rateLimitKey() *RateLimitKey {
 		ProjectID: o.projectID,
 		Operation: "Get",
 		Service:   "Operations",
+		APIGroup:  meta.APIGroupCompute,
 		Version:   meta.VersionBeta,
 	}
 }
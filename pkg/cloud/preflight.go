@@ -0,0 +1,219 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+)
+
+// PreflightCheck is the type of check done by Preflight().
+type PreflightCheck string
+
+const (
+	// PreflightCredentials checks that the credentials are accepted.
+	PreflightCredentials PreflightCheck = "Credentials"
+	// PreflightAPIEnabled checks that the compute API is enabled for the
+	// project.
+	PreflightAPIEnabled PreflightCheck = "APIEnabled"
+	// PreflightProjectAccess checks that the project exists and is
+	// accessible with the credentials.
+	PreflightProjectAccess PreflightCheck = "ProjectAccess"
+	// PreflightQuota checks that there is enough remaining quota.
+	PreflightQuota PreflightCheck = "Quota"
+)
+
+// PreflightConfig configures the checks done by Preflight().
+type PreflightConfig struct {
+	// ProjectID to check.
+	ProjectID string
+	// Quotas that must be available.
+	Quotas []QuotaRequirement
+}
+
+// QuotaRequirement is an amount of quota that must be remaining.
+type QuotaRequirement struct {
+	// Metric of the quota (e.g. "BACKEND_SERVICES").
+	Metric string
+	// Region of the quota. This is empty for project-wide quotas.
+	Region string
+	// Min amount of quota that must be remaining (i.e. Limit - Usage).
+	Min float64
+}
+
+// PreflightFinding is the result of a single check.
+type PreflightFinding struct {
+	Check PreflightCheck
+	// OK is true if the check passed.
+	OK bool
+	// Message is a human readable description of the finding.
+	Message string
+	// Err is the error returned by the Cloud, if any.
+	Err error
+}
+
+// String implements Stringer.
+func (f *PreflightFinding) String() string {
+	status := "OK"
+	if !f.OK {
+		status = "Failed"
+	}
+	return fmt.Sprintf("%s %s: %s", f.Check, status, f.Message)
+}
+
+// PreflightResult is the result of Preflight().
+type PreflightResult struct {
+	Findings []*PreflightFinding
+}
+
+// OK is true if all of the checks passed.
+func (r *PreflightResult) OK() bool { return len(r.Failed()) == 0 }
+
+// Failed returns the findings for the checks that failed.
+func (r *PreflightResult) Failed() []*PreflightFinding {
+	var ret []*PreflightFinding
+	for _, f := range r.Findings {
+		if !f.OK {
+			ret = append(ret, f)
+		}
+	}
+	return ret
+}
+
+func (r *PreflightResult) add(check PreflightCheck, ok bool, err error, format string, args ...any) {
+	r.Findings = append(r.Findings, &PreflightFinding{
+		Check:   check,
+		OK:      ok,
+		Message: fmt.Sprintf(format, args...),
+		Err:     err,
+	})
+}
+
+// Preflight checks the environment before starting to make changes: the
+// credentials are valid, the compute API is enabled, the project is accessible
+// and there is enough quota remaining. This allows problems such as a
+// disabled API to be reported as a single clear condition instead of a
+// series of errors from the operations.
+//
+// Checks that depend on a failed check (e.g. quota requires project access)
+// are not done and have no finding.
+func Preflight(ctx context.Context, c Cloud, config PreflightConfig) *PreflightResult {
+	ret := &PreflightResult{}
+
+	project, err := c.Projects().Get(ctx, config.ProjectID)
+	if !preflightProject(ret, config.ProjectID, err) {
+		klog.V(2).Infof("Preflight(%q): %v", config.ProjectID, ret.Failed())
+		return ret
+	}
+
+	regions := map[string]*compute.Region{}
+	for _, q := range config.Quotas {
+		var quotas []*compute.Quota
+		if q.Region == "" {
+			quotas = project.Quotas
+		} else {
+			region, ok := regions[q.Region]
+			if !ok {
+				region, err = c.Regions().Get(ctx, meta.GlobalKey(q.Region))
+				if err != nil {
+					ret.add(PreflightQuota, false, err, "could not get quota %s for region %q: %v", q.Metric, q.Region, err)
+					continue
+				}
+				regions[q.Region] = region
+			}
+			quotas = region.Quotas
+		}
+		preflightQuota(ret, q, quotas)
+	}
+	if !ret.OK() {
+		klog.V(2).Infof("Preflight(%q): %v", config.ProjectID, ret.Failed())
+	}
+	return ret
+}
+
+// preflightProject adds the findings for the result of the project Get.
+// Returns true if the project is accessible.
+func preflightProject(r *PreflightResult, projectID string, err error) bool {
+	if err == nil {
+		r.add(PreflightCredentials, true, nil, "credentials accepted")
+		r.add(PreflightAPIEnabled, true, nil, "compute API is enabled")
+		r.add(PreflightProjectAccess, true, nil, "project %q is accessible", projectID)
+		return true
+	}
+
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		r.add(PreflightProjectAccess, false, err, "could not get project %q: %v", projectID, err)
+		return false
+	}
+	switch {
+	case apiErr.Code == http.StatusUnauthorized:
+		r.add(PreflightCredentials, false, err, "credentials were rejected: %v", apiErr.Message)
+	case apiErr.Code == http.StatusForbidden && isAPIDisabled(apiErr):
+		r.add(PreflightCredentials, true, nil, "credentials accepted")
+		r.add(PreflightAPIEnabled, false, err, "compute API is not enabled for project %q: %v", projectID, apiErr.Message)
+	case apiErr.Code == http.StatusForbidden:
+		r.add(PreflightCredentials, true, nil, "credentials accepted")
+		r.add(PreflightProjectAccess, false, err, "access to project %q denied: %v", projectID, apiErr.Message)
+	case apiErr.Code == http.StatusNotFound:
+		r.add(PreflightCredentials, true, nil, "credentials accepted")
+		r.add(PreflightProjectAccess, false, err, "project %q not found", projectID)
+	default:
+		r.add(PreflightProjectAccess, false, err, "could not get project %q: %v", projectID, err)
+	}
+	return false
+}
+
+// isAPIDisabled returns true if the error is due to the API not being enabled
+// for the project.
+func isAPIDisabled(err *googleapi.Error) bool {
+	for _, item := range err.Errors {
+		if item.Reason == "accessNotConfigured" {
+			return true
+		}
+	}
+	return strings.Contains(err.Message, "SERVICE_DISABLED") || strings.Contains(err.Message, "has not been used in project")
+}
+
+// preflightQuota adds the finding for the quota requirement q.
+func preflightQuota(r *PreflightResult, q QuotaRequirement, quotas []*compute.Quota) {
+	scope := "project"
+	if q.Region != "" {
+		scope = fmt.Sprintf("region %q", q.Region)
+	}
+	for _, quota := range quotas {
+		if quota == nil || quota.Metric != q.Metric {
+			continue
+		}
+		remaining := quota.Limit - quota.Usage
+		if remaining < q.Min {
+			r.add(PreflightQuota, false, nil, "quota %s in %s: %v remaining, need %v", q.Metric, scope, remaining, q.Min)
+			return
+		}
+		r.add(PreflightQuota, true, nil, "quota %s in %s: %v remaining", q.Metric, scope, remaining)
+		return
+	}
+	r.add(PreflightQuota, false, nil, "quota %s not found in %s", q.Metric, scope)
+}
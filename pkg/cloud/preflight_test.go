@@ -0,0 +1,164 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+// errProjects returns err for all calls.
+type errProjects struct {
+	ProjectsOps
+	err error
+}
+
+func (p *errProjects) Get(context.Context, string) (*ga.Project, error) { return nil, p.err }
+
+type preflightCloud struct {
+	*MockGCE
+	projects Projects
+}
+
+func (c *preflightCloud) Projects() Projects { return c.projects }
+
+func TestPreflight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj1"})
+	mock.MockProjects.Objects[*meta.GlobalKey("proj1")] = mock.MockProjects.Obj(&ga.Project{
+		Name:   "proj1",
+		Quotas: []*ga.Quota{{Metric: "BACKEND_SERVICES", Limit: 10, Usage: 9}},
+	})
+	mock.MockRegions.Objects[*meta.GlobalKey("us-central1")] = mock.MockRegions.Obj(&ga.Region{
+		Name:   "us-central1",
+		Quotas: []*ga.Quota{{Metric: "ADDRESSES", Limit: 10, Usage: 2}},
+	})
+
+	type finding struct {
+		Check PreflightCheck
+		OK    bool
+	}
+	for _, tc := range []struct {
+		name   string
+		err    error
+		config PreflightConfig
+		want   []finding
+	}{
+		{
+			name: "ok",
+			config: PreflightConfig{
+				ProjectID: "proj1",
+				Quotas: []QuotaRequirement{
+					{Metric: "BACKEND_SERVICES", Min: 1},
+					{Metric: "ADDRESSES", Region: "us-central1", Min: 5},
+				},
+			},
+			want: []finding{
+				{PreflightCredentials, true},
+				{PreflightAPIEnabled, true},
+				{PreflightProjectAccess, true},
+				{PreflightQuota, true},
+				{PreflightQuota, true},
+			},
+		},
+		{
+			name: "quota",
+			config: PreflightConfig{
+				ProjectID: "proj1",
+				Quotas: []QuotaRequirement{
+					{Metric: "BACKEND_SERVICES", Min: 2},
+					{Metric: "NOT_A_METRIC", Min: 1},
+					{Metric: "ADDRESSES", Region: "missing-region", Min: 1},
+				},
+			},
+			want: []finding{
+				{PreflightCredentials, true},
+				{PreflightAPIEnabled, true},
+				{PreflightProjectAccess, true},
+				{PreflightQuota, false},
+				{PreflightQuota, false},
+				{PreflightQuota, false},
+			},
+		},
+		{
+			name:   "project not found",
+			config: PreflightConfig{ProjectID: "proj2"},
+			want: []finding{
+				{PreflightCredentials, true},
+				{PreflightProjectAccess, false},
+			},
+		},
+		{
+			name:   "bad credentials",
+			err:    &googleapi.Error{Code: http.StatusUnauthorized},
+			config: PreflightConfig{ProjectID: "proj1"},
+			want:   []finding{{PreflightCredentials, false}},
+		},
+		{
+			name: "api disabled",
+			err: &googleapi.Error{
+				Code:   http.StatusForbidden,
+				Errors: []googleapi.ErrorItem{{Reason: "accessNotConfigured"}},
+			},
+			config: PreflightConfig{ProjectID: "proj1"},
+			want: []finding{
+				{PreflightCredentials, true},
+				{PreflightAPIEnabled, false},
+			},
+		},
+		{
+			name:   "access denied",
+			err:    &googleapi.Error{Code: http.StatusForbidden},
+			config: PreflightConfig{ProjectID: "proj1"},
+			want: []finding{
+				{PreflightCredentials, true},
+				{PreflightProjectAccess, false},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var c Cloud = mock
+			if tc.err != nil {
+				c = &preflightCloud{MockGCE: mock, projects: &errProjects{err: tc.err}}
+			}
+			r := Preflight(ctx, c, tc.config)
+			var got []finding
+			for _, f := range r.Findings {
+				got = append(got, finding{f.Check, f.OK})
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("Preflight() findings; -got,+want: %s", diff)
+			}
+			wantOK := true
+			for _, f := range tc.want {
+				wantOK = wantOK && f.OK
+			}
+			if r.OK() != wantOK {
+				t.Errorf("OK() = %t, want %t (findings: %v)", r.OK(), wantOK, r.Findings)
+			}
+		})
+	}
+}
@@ -43,3 +43,34 @@ type SingleProjectRouter struct {
 func (r *SingleProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
 	return r.ID
 }
+
+var projectIDContextKey = contextKey("projectID")
+
+// WithProjectID returns a context that routes calls to the project id when
+// used with ContextProjectRouter. This is used for calls on resources in a
+// project other than the default, e.g. the host project of a Shared VPC.
+func WithProjectID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, projectIDContextKey, id)
+}
+
+// ProjectIDFromContext returns the project set by WithProjectID. Returns ""
+// if no project has been set.
+func ProjectIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(projectIDContextKey).(string)
+	return id
+}
+
+// ContextProjectRouter routes calls to the project in the context (see
+// WithProjectID). Calls without a project in the context are routed by
+// Router.
+type ContextProjectRouter struct {
+	Router ProjectRouter
+}
+
+// ProjectID returns the project ID to be used for a call to the API.
+func (r *ContextProjectRouter) ProjectID(ctx context.Context, version meta.Version, service string) string {
+	if id := ProjectIDFromContext(ctx); id != "" {
+		return id
+	}
+	return r.Router.ProjectID(ctx, version, service)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+)
+
+func TestContextProjectRouter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := &ContextProjectRouter{Router: &SingleProjectRouter{ID: "default"}}
+	if got := r.ProjectID(ctx, meta.VersionGA, "subnetworks"); got != "default" {
+		t.Errorf("ProjectID() = %q, want %q", got, "default")
+	}
+	hostCtx := WithProjectID(ctx, "host")
+	if got := ProjectIDFromContext(hostCtx); got != "host" {
+		t.Errorf("ProjectIDFromContext() = %q, want %q", got, "host")
+	}
+	if got := r.ProjectID(hostCtx, meta.VersionGA, "subnetworks"); got != "host" {
+		t.Errorf("ProjectID() = %q, want %q", got, "host")
+	}
+
+	mock := NewMockGCE(r)
+	key := meta.RegionalKey("subnet", "us-central1")
+	if err := mock.Subnetworks().Insert(hostCtx, key, &ga.Subnetwork{Name: "subnet"}); err != nil {
+		t.Fatalf("Insert() = %v, want nil", err)
+	}
+	obj, err := mock.Subnetworks().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if !strings.Contains(obj.SelfLink, "/projects/host/") {
+		t.Errorf("SelfLink = %q, want project host", obj.SelfLink)
+	}
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// providerIDPrefix is the scheme used by Kubernetes Node.Spec.ProviderID
+	// for GCE instances.
+	providerIDPrefix = "gce://"
+)
+
+// ParseProviderID parses a Kubernetes providerID of the form
+//
+//	gce://<project>/<zone>/<instance>
+//
+// into the ResourceID of the instance.
+func ParseProviderID(providerID string) (*ResourceID, error) {
+	if !strings.HasPrefix(providerID, providerIDPrefix) {
+		return nil, fmt.Errorf("%q is not a valid providerID (missing %q prefix)", providerID, providerIDPrefix)
+	}
+	parts := strings.Split(strings.TrimPrefix(providerID, providerIDPrefix), "/")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%q is not a valid providerID (want %s<project>/<zone>/<instance>)", providerID, providerIDPrefix)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("%q is not a valid providerID (empty path element)", providerID)
+		}
+	}
+	key := meta.ZonalKey(parts[2], parts[1])
+	if !key.Valid() {
+		return nil, fmt.Errorf("%q is not a valid providerID (invalid zone %q)", providerID, parts[1])
+	}
+	return &ResourceID{
+		ProjectID: parts[0],
+		APIGroup:  meta.APIGroupCompute,
+		Resource:  "instances",
+		Key:       key,
+	}, nil
+}
+
+// ProviderID returns the Kubernetes providerID for the instance named by the
+// ResourceID. Returns an error if the ResourceID is not a zonal instance.
+func (r *ResourceID) ProviderID() (string, error) {
+	if r.Resource != "instances" || r.Key == nil || r.Key.Type() != meta.Zonal {
+		return "", fmt.Errorf("%v is not a zonal instance", r)
+	}
+	return fmt.Sprintf("%s%s/%s/%s", providerIDPrefix, r.ProjectID, r.Key.Zone, r.Key.Name), nil
+}
+
+// NewInstanceResolver returns a new InstanceResolver using c to make calls.
+func NewInstanceResolver(c Cloud) *InstanceResolver {
+	return &InstanceResolver{
+		c:      c,
+		byKey:  map[instanceCacheKey]*ga.Instance{},
+		zoneOf: map[instanceCacheKey]string{},
+	}
+}
+
+// InstanceResolver looks up instances, caching the results. When the zone of
+// an instance is not known (i.e. the instance is named by a global key), the
+// resolver falls back to an AggregatedList() across all zones to locate it.
+//
+// Calls are routed to the project set in the context (see WithProjectID()).
+// The Cloud must use a ContextProjectRouter for this to take effect. Cached
+// instances are keyed by the project in the context.
+//
+// InstanceResolver is thread-safe.
+type InstanceResolver struct {
+	c Cloud
+
+	lock sync.Mutex
+	// byKey caches instances by project and zonal key.
+	byKey map[instanceCacheKey]*ga.Instance
+	// zoneOf caches the zone where an instance name was found. The zone of
+	// the key is not set.
+	zoneOf map[instanceCacheKey]string
+}
+
+// instanceCacheKey identifies an instance in the InstanceResolver cache.
+// project is "" for calls without a project in the context.
+type instanceCacheKey struct {
+	project string
+	zone    string
+	name    string
+}
+
+// ProviderID returns the instance named by the Kubernetes providerID. The
+// lookup is routed to the project in the providerID.
+func (r *InstanceResolver) ProviderID(ctx context.Context, providerID string) (*ga.Instance, error) {
+	id, err := ParseProviderID(providerID)
+	if err != nil {
+		return nil, err
+	}
+	return r.Instance(WithProjectID(ctx, id.ProjectID), id.Key)
+}
+
+// Instance returns the instance named by key. If key is a GlobalKey (i.e. the
+// zone is unknown), then the instance will be searched for in all zones.
+func (r *InstanceResolver) Instance(ctx context.Context, key *meta.Key) (*ga.Instance, error) {
+	switch key.Type() {
+	case meta.Zonal:
+		return r.get(ctx, *key)
+	case meta.Global:
+		r.lock.Lock()
+		zone, ok := r.zoneOf[instanceCacheKey{project: ProjectIDFromContext(ctx), name: key.Name}]
+		r.lock.Unlock()
+		if ok {
+			return r.get(ctx, *meta.ZonalKey(key.Name, zone))
+		}
+		return r.aggregatedLookup(ctx, key.Name)
+	}
+	return nil, fmt.Errorf("InstanceResolver: invalid key for instance: %v", key)
+}
+
+// Invalidate removes the instance named by key from the cache in all
+// projects.
+func (r *InstanceResolver) Invalidate(key *meta.Key) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for ck := range r.byKey {
+		if ck.name == key.Name && (key.Type() != meta.Zonal || ck.zone == key.Zone) {
+			delete(r.byKey, ck)
+		}
+	}
+	for ck := range r.zoneOf {
+		if ck.name == key.Name {
+			delete(r.zoneOf, ck)
+		}
+	}
+}
+
+func (r *InstanceResolver) get(ctx context.Context, key meta.Key) (*ga.Instance, error) {
+	r.lock.Lock()
+	inst, ok := r.byKey[instanceCacheKey{project: ProjectIDFromContext(ctx), zone: key.Zone, name: key.Name}]
+	r.lock.Unlock()
+	if ok {
+		klog.V(5).Infof("InstanceResolver.get(%v): cache hit", key)
+		return inst, nil
+	}
+
+	inst, err := r.c.Instances().Get(ctx, &key)
+	if err != nil {
+		return nil, err
+	}
+	r.add(ctx, key.Zone, inst)
+	return inst, nil
+}
+
+func (r *InstanceResolver) aggregatedLookup(ctx context.Context, name string) (*ga.Instance, error) {
+	all, err := r.c.Instances().AggregatedList(ctx, filter.Regexp("name", "^"+regexp.QuoteMeta(name)+"$"))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		found *ga.Instance
+		zone  string
+	)
+	for _, instances := range all {
+		for _, inst := range instances {
+			if inst.Name != name {
+				continue
+			}
+			id, err := ParseResourceURL(inst.SelfLink)
+			if err != nil {
+				return nil, fmt.Errorf("InstanceResolver: instance %q: %w", name, err)
+			}
+			if found != nil {
+				return nil, fmt.Errorf("InstanceResolver: instance %q found in multiple zones (%s, %s)", name, zone, id.Key.Zone)
+			}
+			found = inst
+			zone = id.Key.Zone
+		}
+	}
+	if found == nil {
+		return nil, &googleapi.Error{
+			Code:    http.StatusNotFound,
+			Message: fmt.Sprintf("instance %q not found in any zone", name),
+		}
+	}
+	klog.V(5).Infof("InstanceResolver.aggregatedLookup(%q): found in zone %q", name, zone)
+	r.add(ctx, zone, found)
+	return found, nil
+}
+
+func (r *InstanceResolver) add(ctx context.Context, zone string, inst *ga.Instance) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	project := ProjectIDFromContext(ctx)
+	r.byKey[instanceCacheKey{project: project, zone: zone, name: inst.Name}] = inst
+	r.zoneOf[instanceCacheKey{project: project, name: inst.Name}] = zone
+}
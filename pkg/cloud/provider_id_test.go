@@ -0,0 +1,187 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+)
+
+func TestParseProviderID(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		providerID string
+		want       *ResourceID
+		wantErr    bool
+	}{
+		{
+			providerID: "gce://proj1/us-central1-b/inst1",
+			want:       &ResourceID{"proj1", meta.APIGroupCompute, "instances", meta.ZonalKey("inst1", "us-central1-b")},
+		},
+		{
+			providerID: "gce://example.com:proj1/us-central1-b/inst1",
+			want:       &ResourceID{"example.com:proj1", meta.APIGroupCompute, "instances", meta.ZonalKey("inst1", "us-central1-b")},
+		},
+		{providerID: "aws://proj1/us-central1-b/inst1", wantErr: true},
+		{providerID: "gce://proj1/inst1", wantErr: true},
+		{providerID: "gce://proj1/us-central1-b/inst1/extra", wantErr: true},
+		{providerID: "gce://proj1//inst1", wantErr: true},
+		{providerID: "gce://proj1/US_CENTRAL/inst1", wantErr: true},
+		{providerID: "", wantErr: true},
+	} {
+		got, err := ParseProviderID(tc.providerID)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("ParseProviderID(%q) = %v, %v; gotErr = %t, want %t", tc.providerID, got, err, gotErr, tc.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("ParseProviderID(%q) = %v, want %v", tc.providerID, got, tc.want)
+		}
+		// Round trip.
+		s, err := got.ProviderID()
+		if err != nil || s != tc.providerID {
+			t.Errorf("ProviderID() = %q, %v; want %q, nil", s, err, tc.providerID)
+		}
+	}
+
+	if _, err := (&ResourceID{"proj1", meta.APIGroupCompute, "addresses", meta.GlobalKey("x")}).ProviderID(); err == nil {
+		t.Errorf("ProviderID() for an address = _, nil; want error")
+	}
+}
+
+func TestInstanceResolver(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj1"})
+	for _, key := range []*meta.Key{
+		meta.ZonalKey("inst1", "us-central1-a"),
+		meta.ZonalKey("inst2", "us-central1-b"),
+		meta.ZonalKey("dupe", "us-central1-a"),
+		meta.ZonalKey("dupe", "us-central1-b"),
+	} {
+		if err := mock.Instances().Insert(ctx, key, &ga.Instance{}); err != nil {
+			t.Fatalf("Insert(%v) = %v, want nil", key, err)
+		}
+	}
+
+	r := NewInstanceResolver(mock)
+
+	inst, err := r.ProviderID(ctx, "gce://proj1/us-central1-a/inst1")
+	if err != nil || inst.Name != "inst1" {
+		t.Errorf("ProviderID(inst1) = %v, %v; want inst1, nil", inst, err)
+	}
+	// Zone unknown, found with AggregatedList.
+	inst, err = r.Instance(ctx, meta.GlobalKey("inst2"))
+	if err != nil || inst.Name != "inst2" {
+		t.Errorf("Instance(inst2) = %v, %v; want inst2, nil", inst, err)
+	}
+	if _, err := r.Instance(ctx, meta.GlobalKey("dupe")); err == nil {
+		t.Errorf("Instance(dupe) = _, nil; want error")
+	}
+	var apiErr *googleapi.Error
+	if _, err := r.Instance(ctx, meta.GlobalKey("missing")); !errors.As(err, &apiErr) || apiErr.Code != http.StatusNotFound {
+		t.Errorf("Instance(missing) = _, %v; want StatusNotFound", err)
+	}
+	if _, err := r.Instance(ctx, meta.RegionalKey("inst1", "us-central1")); err == nil {
+		t.Errorf("Instance(<regional key>) = _, nil; want error")
+	}
+
+	// Cached values are returned even after the instance is deleted.
+	mock.Instances().Delete(ctx, meta.ZonalKey("inst2", "us-central1-b"))
+	if _, err := r.Instance(ctx, meta.GlobalKey("inst2")); err != nil {
+		t.Errorf("Instance(inst2) = _, %v; want nil (cached)", err)
+	}
+	r.Invalidate(meta.GlobalKey("inst2"))
+	if _, err := r.Instance(ctx, meta.GlobalKey("inst2")); err == nil {
+		t.Errorf("Instance(inst2) after Invalidate() = _, nil; want error")
+	}
+}
+
+func TestInstanceResolverExactName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&SingleProjectRouter{"proj1"})
+	for _, key := range []*meta.Key{
+		meta.ZonalKey("node-1", "us-central1-a"),
+		meta.ZonalKey("node-10", "us-central1-b"),
+	} {
+		if err := mock.Instances().Insert(ctx, key, &ga.Instance{}); err != nil {
+			t.Fatalf("Insert(%v) = %v, want nil", key, err)
+		}
+	}
+	var gotFilter *filter.F
+	mock.MockInstances.AggregatedListHook = func(ctx context.Context, fl *filter.F, m *MockInstances) (bool, map[string][]*ga.Instance, error) {
+		gotFilter = fl
+		return false, nil, nil
+	}
+
+	r := NewInstanceResolver(mock)
+	inst, err := r.Instance(ctx, meta.GlobalKey("node-1"))
+	if err != nil || inst.Name != "node-1" {
+		t.Fatalf("Instance(node-1) = %v, %v; want node-1, nil", inst, err)
+	}
+	if gotFilter.Match(&ga.Instance{Name: "node-10"}) {
+		t.Errorf("AggregatedList() filter %v matches node-10, want only node-1", gotFilter)
+	}
+	if !gotFilter.Match(&ga.Instance{Name: "node-1"}) {
+		t.Errorf("AggregatedList() filter %v does not match node-1", gotFilter)
+	}
+}
+
+func TestInstanceResolverProject(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := NewMockGCE(&ContextProjectRouter{Router: &SingleProjectRouter{"proj1"}})
+	key := meta.ZonalKey("inst1", "us-central1-a")
+	if err := mock.Instances().Insert(WithProjectID(ctx, "proj2"), key, &ga.Instance{}); err != nil {
+		t.Fatalf("Insert(%v) = %v, want nil", key, err)
+	}
+	var gotProjects []string
+	mock.MockInstances.GetHook = func(ctx context.Context, key *meta.Key, m *MockInstances) (bool, *ga.Instance, error) {
+		gotProjects = append(gotProjects, m.ProjectRouter.ProjectID(ctx, meta.VersionGA, "instances"))
+		return false, nil, nil
+	}
+
+	r := NewInstanceResolver(mock)
+	for _, providerID := range []string{
+		"gce://proj2/us-central1-a/inst1",
+		"gce://proj3/us-central1-a/inst1",
+	} {
+		if _, err := r.ProviderID(ctx, providerID); err != nil {
+			t.Errorf("ProviderID(%q) = _, %v; want nil", providerID, err)
+		}
+	}
+	// The instances in different projects are cached separately.
+	if want := []string{"proj2", "proj3"}; !reflect.DeepEqual(gotProjects, want) {
+		t.Errorf("Get() called for projects %v, want %v", gotProjects, want)
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+)
+
+var callerContextKey = contextKey("caller")
+
+// WithCaller returns a context that identifies the component making the calls
+// (e.g. "neg-controller"). The caller identity is used by FairRateLimiter to
+// queue calls from different components separately.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerContextKey, caller)
+}
+
+// CallerFromContext returns the caller set by WithCaller. Returns "" if no
+// caller has been set.
+func CallerFromContext(ctx context.Context) string {
+	caller, _ := ctx.Value(callerContextKey).(string)
+	return caller
+}
+
+// NewFairRateLimiter returns a FairRateLimiter that fair queues calls in
+// front of rl.
+func NewFairRateLimiter(rl RateLimiter) *FairRateLimiter {
+	return &FairRateLimiter{
+		rl:          rl,
+		queues:      map[string][]*fairWaiter{},
+		dispatching: map[string]bool{},
+	}
+}
+
+// FairRateLimiter wraps a RateLimiter and fair queues callers in front of it.
+// Each caller (see WithCaller) gets its own FIFO queue and at most one call
+// from each queue waits in the underlying RateLimiter at a time. This
+// prevents a caller issuing a large number of calls from starving other
+// callers. Queues are dispatched independently, so a call that is blocked in
+// the underlying RateLimiter (e.g. because its key is throttled) only holds
+// back the calls of the same caller.
+//
+// Calls without a caller identity share a single queue.
+type FairRateLimiter struct {
+	rl RateLimiter
+
+	lock sync.Mutex
+	// queues of waiting calls by caller.
+	queues map[string][]*fairWaiter
+	// dispatching is true for the callers that have a goroutine admitting
+	// calls.
+	dispatching map[string]bool
+}
+
+type fairWaiter struct {
+	ctx  context.Context
+	key  *RateLimitKey
+	done chan error
+}
+
+// Accept blocks until the call is admitted by the underlying RateLimiter or
+// ctx is Done.
+func (f *FairRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	caller := CallerFromContext(ctx)
+	w := &fairWaiter{ctx: ctx, key: key, done: make(chan error, 1)}
+
+	f.lock.Lock()
+	f.queues[caller] = append(f.queues[caller], w)
+	if !f.dispatching[caller] {
+		f.dispatching[caller] = true
+		go f.dispatch(caller)
+	}
+	f.lock.Unlock()
+
+	select {
+	case err := <-w.done:
+		return err
+	case <-ctx.Done():
+		f.remove(caller, w)
+		return ctx.Err()
+	}
+}
+
+// Observe passes the result to the underlying RateLimiter.
+func (f *FairRateLimiter) Observe(ctx context.Context, err error, key *RateLimitKey) {
+	f.rl.Observe(ctx, err, key)
+}
+
+// dispatch admits the waiting calls of caller to the underlying RateLimiter
+// one at a time until there are no more calls waiting.
+func (f *FairRateLimiter) dispatch(caller string) {
+	for {
+		w := f.next(caller)
+		if w == nil {
+			return
+		}
+		if w.ctx.Err() != nil {
+			// Accept() has returned already.
+			continue
+		}
+		w.done <- f.rl.Accept(w.ctx, w.key)
+	}
+}
+
+// next pops the next waiter of caller. Returns nil if there are no waiters
+// and marks the dispatch loop for caller as finished.
+func (f *FairRateLimiter) next(caller string) *fairWaiter {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	q := f.queues[caller]
+	if len(q) == 0 {
+		delete(f.queues, caller)
+		delete(f.dispatching, caller)
+		return nil
+	}
+	w := q[0]
+	if len(q) == 1 {
+		delete(f.queues, caller)
+	} else {
+		f.queues[caller] = q[1:]
+	}
+	return w
+}
+
+// remove w from the queue if it has not been dispatched yet.
+func (f *FairRateLimiter) remove(caller string, w *fairWaiter) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	q := f.queues[caller]
+	for i := range q {
+		if q[i] != w {
+			continue
+		}
+		q = append(q[0:i], q[i+1:]...)
+		if len(q) > 0 {
+			f.queues[caller] = q
+		} else {
+			delete(f.queues, caller)
+		}
+		return
+	}
+}
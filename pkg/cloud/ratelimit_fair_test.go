@@ -0,0 +1,229 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gatedRateLimiter admits one call for each value sent to gate and records
+// the order of the admitted calls.
+type gatedRateLimiter struct {
+	gate chan struct{}
+
+	lock    sync.Mutex
+	blocked int
+	order   []string
+}
+
+func (rl *gatedRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	rl.lock.Lock()
+	rl.blocked++
+	rl.lock.Unlock()
+	defer func() {
+		rl.lock.Lock()
+		rl.blocked--
+		rl.lock.Unlock()
+	}()
+
+	select {
+	case <-rl.gate:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	rl.order = append(rl.order, CallerFromContext(ctx))
+	return nil
+}
+
+func (rl *gatedRateLimiter) Observe(context.Context, error, *RateLimitKey) {}
+
+// waitingIn returns the number of calls blocked in Accept().
+func (rl *gatedRateLimiter) waitingIn() int {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+	return rl.blocked
+}
+
+// throttledRateLimiter blocks calls for the throttled operation until ctx is
+// Done and admits all other calls.
+type throttledRateLimiter struct {
+	throttled string
+}
+
+func (rl *throttledRateLimiter) Accept(ctx context.Context, key *RateLimitKey) error {
+	if key != nil && key.Operation == rl.throttled {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (rl *throttledRateLimiter) Observe(context.Context, error, *RateLimitKey) {}
+
+func (f *FairRateLimiter) waiting() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var n int
+	for _, q := range f.queues {
+		n += len(q)
+	}
+	return n
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestCallerContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	if got := CallerFromContext(ctx); got != "" {
+		t.Errorf("CallerFromContext() = %q, want empty", got)
+	}
+	if got := CallerFromContext(WithCaller(ctx, "abc")); got != "abc" {
+		t.Errorf("CallerFromContext() = %q, want %q", got, "abc")
+	}
+}
+
+func TestFairRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	rl := &gatedRateLimiter{gate: make(chan struct{})}
+	frl := NewFairRateLimiter(rl)
+
+	bulkCtx := WithCaller(context.Background(), "bulk")
+	urgentCtx := WithCaller(context.Background(), "urgent")
+
+	const bulkCalls = 10
+	var wg sync.WaitGroup
+	for i := 0; i < bulkCalls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := frl.Accept(bulkCtx, &RateLimitKey{Operation: "List"}); err != nil {
+				t.Errorf("Accept(bulk) = %v, want nil", err)
+			}
+		}()
+	}
+	// One of the bulk calls is blocked in the underlying RateLimiter.
+	waitFor(t, "bulk calls to queue", func() bool { return frl.waiting() == bulkCalls-1 })
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := frl.Accept(urgentCtx, &RateLimitKey{Operation: "Insert"}); err != nil {
+			t.Errorf("Accept(urgent) = %v, want nil", err)
+		}
+	}()
+	// The urgent call does not queue behind the bulk calls.
+	waitFor(t, "urgent call to dispatch", func() bool { return rl.waitingIn() == 2 })
+
+	for i := 0; i < bulkCalls+1; i++ {
+		rl.gate <- struct{}{}
+	}
+	wg.Wait()
+
+	urgentPos := -1
+	for i, caller := range rl.order {
+		if caller == "urgent" {
+			urgentPos = i
+		}
+	}
+	// The urgent call may wait for at most the call in progress plus one
+	// call from the bulk queue.
+	if urgentPos < 0 || urgentPos > 2 {
+		t.Errorf("urgent call admitted at position %d, want <= 2 (order = %v)", urgentPos, rl.order)
+	}
+	if n := frl.waiting(); n != 0 {
+		t.Errorf("waiting() = %d, want 0", n)
+	}
+}
+
+func TestFairRateLimiterCancel(t *testing.T) {
+	t.Parallel()
+
+	rl := &gatedRateLimiter{gate: make(chan struct{})}
+	frl := NewFairRateLimiter(rl)
+
+	// Block the underlying limiter with the first call.
+	firstDone := make(chan error)
+	go func() { firstDone <- frl.Accept(context.Background(), nil) }()
+	waitFor(t, "first call to dispatch", func() bool { return rl.waitingIn() == 1 })
+
+	// The second call from the same caller queues behind the first.
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() { errCh <- frl.Accept(ctx, nil) }()
+	waitFor(t, "call to queue", func() bool { return frl.waiting() == 1 })
+	cancel()
+
+	if err := <-errCh; err != context.Canceled {
+		t.Errorf("Accept() = %v, want %v", err, context.Canceled)
+	}
+	if n := frl.waiting(); n != 0 {
+		t.Errorf("waiting() = %d, want 0", n)
+	}
+	rl.gate <- struct{}{}
+	if err := <-firstDone; err != nil {
+		t.Errorf("Accept() = %v, want nil", err)
+	}
+	// Only the first call was admitted.
+	if len(rl.order) != 1 || rl.order[0] != "" {
+		t.Errorf("order = %q, want [\"\"]", rl.order)
+	}
+}
+
+func TestFairRateLimiterThrottledKey(t *testing.T) {
+	t.Parallel()
+
+	frl := NewFairRateLimiter(&throttledRateLimiter{throttled: "List"})
+
+	// Caller A's call blocks in the underlying RateLimiter.
+	ctxA, cancelA := context.WithCancel(WithCaller(context.Background(), "a"))
+	errA := make(chan error)
+	go func() { errA <- frl.Accept(ctxA, &RateLimitKey{Operation: "List"}) }()
+	waitFor(t, "caller a to dispatch", func() bool {
+		frl.lock.Lock()
+		defer frl.lock.Unlock()
+		return frl.dispatching["a"] && len(frl.queues["a"]) == 0
+	})
+
+	// Caller B is still admitted.
+	ctxB, cancelB := context.WithTimeout(WithCaller(context.Background(), "b"), 10*time.Second)
+	defer cancelB()
+	if err := frl.Accept(ctxB, &RateLimitKey{Operation: "Insert"}); err != nil {
+		t.Errorf("Accept(b) = %v, want nil", err)
+	}
+
+	cancelA()
+	if err := <-errA; err != context.Canceled {
+		t.Errorf("Accept(a) = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// EnsureAbsent marks the Node id as NodeDoesNotExist so that the resource is
+// deleted if it exists. The Node must be OwnershipManaged. The deletes are
+// ordered by the planner after the references to the resource are dropped.
+func (g *Builder) EnsureAbsent(id *cloud.ResourceID) error {
+	nb := g.Get(id)
+	if nb == nil {
+		return fmt.Errorf("%s: EnsureAbsent: %v is not in the graph", builderErrPrefix, id)
+	}
+	if nb.Ownership() != rnode.OwnershipManaged {
+		return fmt.Errorf("%s: EnsureAbsent: %v is not managed (ownership %s)", builderErrPrefix, id, nb.Ownership())
+	}
+	nb.SetState(rnode.NodeDoesNotExist)
+	return nil
+}
+
+// EnsureAbsentTree marks the Node id and its exclusive dependents as
+// NodeDoesNotExist (see EnsureAbsent()). The exclusive dependents are the
+// managed Nodes referenced by id, directly or transitively, that are only
+// referenced by Nodes that are marked. This is used to tear down a set of
+// resources (e.g. a load balancer) from its root while keeping resources that
+// are shared with other Nodes in the graph.
+//
+// Resources that are not in the graph may still reference a dependent, in
+// which case deleting the dependent fails in the Cloud.
+//
+// Returns the IDs of the Nodes that were marked, sorted.
+func (g *Builder) EnsureAbsentTree(id *cloud.ResourceID) ([]*cloud.ResourceID, error) {
+	root := g.Get(id)
+	if root == nil {
+		return nil, fmt.Errorf("%s: EnsureAbsentTree: %v is not in the graph", builderErrPrefix, id)
+	}
+	if root.Ownership() != rnode.OwnershipManaged {
+		return nil, fmt.Errorf("%s: EnsureAbsentTree: %v is not managed (ownership %s)", builderErrPrefix, id, root.Ownership())
+	}
+	// References are computed before changing any state as Nodes that do
+	// not exist have no references.
+	outRefs := map[cloud.ResourceMapKey][]rnode.ResourceRef{}
+	inRefs := map[cloud.ResourceMapKey][]rnode.ResourceRef{}
+	for _, nb := range g.nodes {
+		if nb.State() == rnode.NodeDoesNotExist {
+			continue
+		}
+		refs, err := nb.OutRefs()
+		if err != nil {
+			return nil, fmt.Errorf("%s: EnsureAbsentTree: %w", builderErrPrefix, err)
+		}
+		outRefs[nb.ID().MapKey()] = refs
+		for _, ref := range refs {
+			inRefs[ref.To.MapKey()] = append(inRefs[ref.To.MapKey()], ref)
+		}
+	}
+
+	marked := map[cloud.ResourceMapKey]bool{id.MapKey(): true}
+	exclusive := func(key cloud.ResourceMapKey) bool {
+		nb := g.nodes[key]
+		if nb == nil || marked[key] || nb.Ownership() != rnode.OwnershipManaged || nb.State() == rnode.NodeDoesNotExist {
+			return false
+		}
+		for _, ref := range inRefs[key] {
+			if !marked[ref.From.MapKey()] {
+				return false
+			}
+		}
+		return true
+	}
+	// Marking a Node can make the Nodes it references exclusive, so repeat
+	// until there are no changes.
+	for changed := true; changed; {
+		changed = false
+		for key := range marked {
+			for _, ref := range outRefs[key] {
+				if exclusive(ref.To.MapKey()) {
+					marked[ref.To.MapKey()] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	var ret []*cloud.ResourceID
+	for key := range marked {
+		nb := g.nodes[key]
+		nb.SetState(rnode.NodeDoesNotExist)
+		ret = append(ret, nb.ID())
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEnsureAbsentTree(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		top      string
+		external []string
+		absent   []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "chain",
+			top:  "a -> b -> c",
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "shared dependent",
+			top:  "a -> b -> c; d -> c",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "diamond",
+			top:  "a -> b -> d; a -> c -> d",
+			want: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "referenced by an absent node",
+			top:  "a -> b; d -> b",
+			// d is already absent so it does not reference b.
+			absent: []string{"d"},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:     "external dependent",
+			top:      "a -> b -> c",
+			external: []string{"b"},
+			want:     []string{"a"},
+		},
+		{
+			name:     "external root",
+			top:      "a -> b",
+			external: []string{"a"},
+			wantErr:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := refBuilder(tc.top)
+			for _, name := range tc.external {
+				b.Get(fake.ID("proj", meta.GlobalKey(name))).SetOwnership(rnode.OwnershipExternal)
+			}
+			for _, name := range tc.absent {
+				b.Get(fake.ID("proj", meta.GlobalKey(name))).SetState(rnode.NodeDoesNotExist)
+			}
+			ids, err := b.EnsureAbsentTree(fake.ID("proj", meta.GlobalKey("a")))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("EnsureAbsentTree() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, id := range ids {
+				got = append(got, id.Key.Name)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("EnsureAbsentTree(); -got,+want: %s", diff)
+			}
+			for _, name := range tc.want {
+				if s := b.Get(fake.ID("proj", meta.GlobalKey(name))).State(); s != rnode.NodeDoesNotExist {
+					t.Errorf("State(%s) = %s, want %s", name, s, rnode.NodeDoesNotExist)
+				}
+			}
+			if _, err := b.Build(); err != nil {
+				t.Errorf("Build() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestEnsureAbsent(t *testing.T) {
+	b := refBuilder("a -> b")
+	if err := b.EnsureAbsent(fake.ID("proj", meta.GlobalKey("a"))); err != nil {
+		t.Fatalf("EnsureAbsent() = %v, want nil", err)
+	}
+	for name, want := range map[string]rnode.NodeState{"a": rnode.NodeDoesNotExist, "b": rnode.NodeExists} {
+		if s := b.Get(fake.ID("proj", meta.GlobalKey(name))).State(); s != want {
+			t.Errorf("State(%s) = %s, want %s", name, s, want)
+		}
+	}
+	if err := b.EnsureAbsent(fake.ID("proj", meta.GlobalKey("x"))); err == nil {
+		t.Errorf("EnsureAbsent(x) = nil, want error")
+	}
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 )
 
 // Do accumulates all of the Actions for executing a plan to transform
@@ -36,7 +37,27 @@ func Do(got, want *rgraph.Graph) ([]exec.Action, error) {
 		if err != nil {
 			return nil, err
 		}
+		if n.Ownership() != rnode.OwnershipManaged {
+			if err := checkUnmanaged(n, act); err != nil {
+				return nil, err
+			}
+		}
 		actions = append(actions, act...)
 	}
 	return actions, nil
 }
+
+// checkUnmanaged returns an error if the plan or the Actions for the
+// unmanaged Node n would change the resource. Only the (meta) Actions that
+// signal the existence of the resource are allowed.
+func checkUnmanaged(n rnode.Node, acts []exec.Action) error {
+	if op := n.Plan().Op(); op != rnode.OpNothing {
+		return fmt.Errorf("actions: node %s is %s but has planned operation %s", n.ID(), n.Ownership(), op)
+	}
+	for _, a := range acts {
+		if t := a.Metadata().Type; t != exec.ActionTypeMeta {
+			return fmt.Errorf("actions: node %s is %s but has a %s action (%s)", n.ID(), n.Ownership(), t, a)
+		}
+	}
+	return nil
+}
@@ -63,6 +63,22 @@ func TestActions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "external node with planned change",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				gotb.Add(newNode())
+				nb := newNode()
+				nb.SetOwnership(rnode.OwnershipExternal)
+				wantb.Add(nb)
+			},
+			setupGraph: func(got, want *rgraph.Graph) {
+				want.Get(id).Plan().Set(rnode.PlanDetails{
+					Operation: rnode.OpDelete,
+					Why:       "test plan",
+				})
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid plan",
 			setupBuilder: func(gotb, wantb *rgraph.Builder) {
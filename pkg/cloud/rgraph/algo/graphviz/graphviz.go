@@ -19,44 +19,163 @@ package graphviz
 import (
 	"bytes"
 	"fmt"
+	"html"
 	"sort"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 )
 
+// Option for rendering the Graph.
+type Option func(*config)
+
+type config struct {
+	diff      bool
+	diffItems int
+}
+
+// DiffOption includes the field-level diff that led to the planned
+// Operation of each Node. At most maxItems fields are shown per Node; the
+// rest are summarized. maxItems <= 0 shows all of the fields.
+func DiffOption(maxItems int) Option {
+	return func(c *config) {
+		c.diff = true
+		c.diffItems = maxItems
+	}
+}
+
 // Do returns a .dot (http://graphviz.org) representation of the resource graph
 // for visualization.
-func Do(g *rgraph.Graph) string {
+func Do(g *rgraph.Graph, opts ...Option) string {
+	c := &config{}
+	for _, o := range opts {
+		o(c)
+	}
 	var buf bytes.Buffer
 	buf.WriteString("digraph G {\n")
 	buf.WriteString("  rankdir=TB\n") // layout top to bottom.
 
 	for _, node := range g.All() {
-		gn := &viznode{
-			name:  node.ID().String(),
-			shape: "box",
-			style: "filled",
-			kv: map[string]any{
-				"localPlan": node.Plan().GraphvizString(),
-				"state":     node.State(),
-			},
-		}
-		deps := node.OutRefs()
-		for _, dep := range deps {
-			e := vizedge{from: node.ID(), to: dep.To, field: dep.Path.String()}
-			buf.WriteString(e.String())
-		}
+		writeNode(&buf, node, node.OutRefs(), c)
+	}
+	buf.WriteString("}\n")
 
-		gn.fillcolor = gn.opColor(node.Plan().Op())
-		buf.WriteString(gn.String())
+	return buf.String()
+}
+
+// Cycle returns a .dot representation of only the Nodes and references that
+// form the cycle in ce. This is smaller than the output of Do() and shows
+// the cause of the rgraph.CycleError.
+func Cycle(g *rgraph.Graph, ce *rgraph.CycleError) string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph G {\n")
+	buf.WriteString("  rankdir=TB\n") // layout top to bottom.
+
+	for _, ref := range ce.Refs {
+		node := g.Get(ref.From)
+		if node == nil {
+			continue
+		}
+		writeNode(&buf, node, []rnode.ResourceRef{ref}, &config{})
 	}
 	buf.WriteString("}\n")
 
 	return buf.String()
 }
 
+// writeNode writes the node and the edges for refs to buf.
+func writeNode(buf *bytes.Buffer, node rnode.Node, refs []rnode.ResourceRef, c *config) {
+	gn := &viznode{
+		name:  node.ID().String(),
+		shape: "box",
+		style: "filled",
+		kv: map[string]any{
+			"localPlan": node.Plan().GraphvizString(),
+			"state":     node.State(),
+			"project":   node.ID().ProjectID,
+			"scope":     scope(node.ID().Key),
+		},
+	}
+	if res := node.Resource(); res != nil {
+		gn.kv["version"] = res.Version()
+	}
+	for _, dep := range refs {
+		e := vizedge{from: node.ID(), to: dep.To, field: dep.Path.String()}
+		buf.WriteString(e.String())
+	}
+
+	if details := node.Plan().Details(); c.diff && details != nil && details.Diff != nil {
+		gn.diff = diffRows(details, c.diffItems)
+	}
+
+	gn.fillcolor = gn.opColor(node.Plan().Op())
+	buf.WriteString(gn.String())
+}
+
+// maxValueLen is the maximum length of a value shown in a diff row.
+const maxValueLen = 40
+
+// diffRows returns the rows of the diff in details, limited to maxItems.
+func diffRows(details *rnode.PlanDetails, maxItems int) [][2]string {
+	recreate := func(p api.Path) bool {
+		for _, rp := range details.RecreatePaths {
+			if rp.Equal(p) {
+				return true
+			}
+		}
+		return false
+	}
+	var ret [][2]string
+	items := details.Diff.Items
+	for i, item := range items {
+		if maxItems > 0 && i == maxItems {
+			ret = append(ret, [2]string{"...", fmt.Sprintf("%d more", len(items)-maxItems)})
+			break
+		}
+		var got, want string
+		switch item.State {
+		case api.DiffItemOnlyInA:
+			got, want = diffValue(item.A), "(unset)"
+		case api.DiffItemOnlyInB:
+			got, want = "(unset)", diffValue(item.B)
+		default:
+			got, want = diffValue(item.A), diffValue(item.B)
+		}
+		row := [2]string{item.Path.String(), got + " -> " + want}
+		if item.Immutable || recreate(item.Path) {
+			row[1] += " (recreate)"
+		}
+		ret = append(ret, row)
+	}
+	return ret
+}
+
+// diffValue returns the value v truncated to maxValueLen.
+func diffValue(v any) string {
+	s := fmt.Sprintf("%v", v)
+	if len(s) > maxValueLen {
+		s = s[:maxValueLen-3] + "..."
+	}
+	return s
+}
+
+// scope returns the location of the resource (e.g. "zone/us-central1-b").
+func scope(key *meta.Key) string {
+	if key == nil {
+		return ""
+	}
+	switch key.Type() {
+	case meta.Zonal:
+		return "zone/" + key.Zone
+	case meta.Regional:
+		return "region/" + key.Region
+	}
+	return "global"
+}
+
 type viznode struct {
 	name string
 
@@ -65,6 +184,8 @@ type viznode struct {
 	style     string
 
 	kv map[string]any
+	// diff rows of (path, change).
+	diff [][2]string
 }
 
 func (*viznode) indent(n int) string {
@@ -114,6 +235,12 @@ func (n *viznode) String() string {
 	for _, k := range keys {
 		lines = append(lines, line{3, fmt.Sprintf("<tr><td>%s</td><td align=\"left\">%v</td></tr>", k, n.kv[k])})
 	}
+	if len(n.diff) > 0 {
+		lines = append(lines, line{3, "<tr><td colspan=\"2\">--- diff ---</td></tr>"})
+		for _, row := range n.diff {
+			lines = append(lines, line{3, fmt.Sprintf("<tr><td align=\"left\">%s</td><td align=\"left\">%s</td></tr>", html.EscapeString(row[0]), html.EscapeString(row[1]))})
+		}
+	}
 	lines = append(lines, line{2, "</table>"})
 
 	var attribsStr string
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package graphviz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+)
+
+func TestDo(t *testing.T) {
+	b := rgraph.NewBuilder()
+
+	nb := fake.NewBuilder(fake.ID("proj-1", meta.ZonalKey("a", "us-central1-b")))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	mr := fake.NewMutableFake("proj-1", meta.ZonalKey("a", "us-central1-b"))
+	r, _ := mr.Freeze()
+	nb.SetResource(r)
+	b.Add(nb)
+
+	nb = fake.NewBuilder(fake.ID("proj-2", meta.GlobalKey("b")))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeDoesNotExist)
+	b.Add(nb)
+
+	out := Do(b.MustBuild())
+	for _, want := range []string{
+		`<tr><td>project</td><td align="left">proj-1</td></tr>`,
+		`<tr><td>scope</td><td align="left">zone/us-central1-b</td></tr>`,
+		`<tr><td>version</td><td align="left">ga</td></tr>`,
+		`<tr><td>project</td><td align="left">proj-2</td></tr>`,
+		`<tr><td>scope</td><td align="left">global</td></tr>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Do() does not contain %q; output:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "<td>version</td>"); n != 1 {
+		t.Errorf("version appears %d times, want 1 (nodes without a resource have no version)", n)
+	}
+}
+
+func TestCycle(t *testing.T) {
+	b := rgraph.NewBuilder()
+	ids := map[string]*cloud.ResourceID{}
+	for _, name := range []string{"a", "b", "c"} {
+		ids[name] = fake.ID("proj", meta.GlobalKey(name))
+	}
+	// a -> b -> c -> b
+	for from, to := range map[string]string{"a": "b", "b": "c", "c": "b"} {
+		nb := fake.NewBuilder(ids[from])
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		nb.FakeOutRefs = []rnode.ResourceRef{{From: ids[from], Path: api.Path{}.Pointer().Field("Dependencies"), To: ids[to]}}
+		b.Add(nb)
+	}
+	g := b.MustBuild()
+	ce := g.FindCycle()
+	if ce == nil {
+		t.Fatalf("FindCycle() = nil, want cycle")
+	}
+
+	out := Cycle(g, ce)
+	for _, want := range []string{
+		`"fakes:proj/b" -> "fakes:proj/c" [label=<*.Dependencies>]`,
+		`"fakes:proj/c" -> "fakes:proj/b" [label=<*.Dependencies>]`,
+		`"fakes:proj/b" [label=<`,
+		`"fakes:proj/c" [label=<`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Cycle() does not contain %q; output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "fakes:proj/a") {
+		t.Errorf("Cycle() contains fakes:proj/a, which is not in the cycle; output:\n%s", out)
+	}
+}
+
+func TestDiffOption(t *testing.T) {
+	id := fake.ID("proj", meta.GlobalKey("a"))
+	field := func(name string) api.Path { return api.Path{}.Pointer().Field(name) }
+	b := rgraph.NewBuilder()
+	nb := fake.NewBuilder(id)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	b.Add(nb)
+	g := b.MustBuild()
+	g.Get(id).Plan().Set(rnode.PlanDetails{
+		Operation: rnode.OpRecreate,
+		Diff: &api.DiffResult{Items: []api.DiffItem{
+			{State: api.DiffItemDifferent, Path: field("Value"), A: "<x>", B: "y"},
+			{State: api.DiffItemOnlyInB, Path: field("Name"), B: strings.Repeat("n", 50)},
+			{State: api.DiffItemOnlyInA, Path: field("Other"), A: 1},
+		}},
+		RecreatePaths: []api.Path{field("Name")},
+	})
+
+	if out := Do(g); strings.Contains(out, "--- diff ---") {
+		t.Errorf("Do() without DiffOption contains the diff; output:\n%s", out)
+	}
+
+	out := Do(g, DiffOption(0))
+	for _, want := range []string{
+		`<tr><td align="left">*.Value</td><td align="left">&lt;x&gt; -&gt; y</td></tr>`,
+		`<tr><td align="left">*.Name</td><td align="left">(unset) -&gt; ` + strings.Repeat("n", 37) + `... (recreate)</td></tr>`,
+		`<tr><td align="left">*.Other</td><td align="left">1 -&gt; (unset)</td></tr>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Do(DiffOption(0)) does not contain %q; output:\n%s", want, out)
+		}
+	}
+
+	out = Do(g, DiffOption(1))
+	for _, want := range []string{
+		`<td align="left">*.Value</td>`,
+		`<tr><td align="left">...</td><td align="left">2 more</td></tr>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Do(DiffOption(1)) does not contain %q; output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `<td align="left">*.Name</td>`) {
+		t.Errorf("Do(DiffOption(1)) contains *.Name; output:\n%s", out)
+	}
+}
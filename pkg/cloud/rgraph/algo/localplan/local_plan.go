@@ -19,6 +19,7 @@ package localplan
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
 )
@@ -26,15 +27,36 @@ import (
 // PlanWantGraph computes a plan local to each Node in the graph and puts the
 // resulting plan in the "want" Graph. It is required that got and want have the
 // same set of Nodes; Nodes that don't exist need to be marked as with
-// NodeStateDoesNotExist.
-func PlanWantGraph(got, want *rgraph.Graph) error {
+// NodeStateDoesNotExist. It is an error to recreate a Node that is still
+// referenced by a Node that is not deleted, recreated or updated to drop the
+// reference, as the resource cannot be deleted while it is in use.
+func PlanWantGraph(got, want *rgraph.Graph, opts ...Option) error {
 	p := planner{got: got, want: want}
+	for _, o := range opts {
+		o(&p)
+	}
 	return p.do()
 }
 
+// Option for planning.
+type Option func(*planner)
+
+// ReuseOption reuses the plans in prevWant (computed against prevGot) for
+// the Nodes that are unchanged in both got and want (see
+// rnode.Fingerprint()). This skips the Diff of these Nodes.
+func ReuseOption(prevGot, prevWant *rgraph.Graph) Option {
+	return func(p *planner) {
+		p.prevGot = prevGot
+		p.prevWant = prevWant
+	}
+}
+
 type planner struct {
 	got  *rgraph.Graph
 	want *rgraph.Graph
+
+	prevGot  *rgraph.Graph
+	prevWant *rgraph.Graph
 }
 
 func (p *planner) do() error {
@@ -48,10 +70,51 @@ func (p *planner) do() error {
 			return err
 		}
 	}
+	for _, gotNode := range p.got.All() {
+		if err := p.checkRecreate(gotNode, p.want.Get(gotNode.ID())); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// checkRecreate returns an error if wantNode is planned to be recreated
+// while a referrer keeps referencing it. The resource cannot be deleted
+// while it is in use, so each referrer must drop the reference first by
+// being deleted, recreated or updated to no longer reference the resource.
+func (p *planner) checkRecreate(gotNode, wantNode rnode.Node) error {
+	if details := wantNode.Plan().Details(); details == nil || details.Operation != rnode.OpRecreate {
+		return nil
+	}
+	for _, ref := range gotNode.InRefs() {
+		referrer := p.want.Get(ref.From)
+		if referrer == nil {
+			return fmt.Errorf("localPlanner: node %s is referenced by %s which is not in want", wantNode.ID(), ref.From)
+		}
+		if !dropsRef(referrer, gotNode.ID()) {
+			return fmt.Errorf("localPlanner: node %s cannot be recreated as it is still referenced by %s (%s)", wantNode.ID(), ref.From, referrer.Plan().Op())
+		}
+	}
+	return nil
+}
+
+// dropsRef returns true if the plan for referrer drops its reference to id.
+func dropsRef(referrer rnode.Node, id *cloud.ResourceID) bool {
+	switch referrer.Plan().Op() {
+	case rnode.OpDelete, rnode.OpRecreate:
+		return true
+	case rnode.OpUpdate:
+		for _, ref := range referrer.OutRefs() {
+			if ref.To.Equal(id) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
 func (p *planner) preconditions() error {
 	for _, node := range p.got.All() {
 		if p.want.Get(node.ID()) == nil {
@@ -69,6 +132,11 @@ func (p *planner) preconditions() error {
 
 func (p *planner) planWantGraph(gotNode, wantNode rnode.Node) error {
 	if wantNode.Ownership() != rnode.OwnershipManaged {
+		// Other Nodes may reference the resource, so it must exist if
+		// it is wanted.
+		if wantNode.State() == rnode.NodeExists && gotNode.State() != rnode.NodeExists {
+			return fmt.Errorf("localPlanner: node %s is not managed and does not exist (got state %s)", wantNode.ID(), gotNode.State())
+		}
 		wantNode.Plan().Set(rnode.PlanDetails{
 			Operation: rnode.OpNothing,
 			Why:       "Node is not managed",
@@ -81,6 +149,10 @@ func (p *planner) planWantGraph(gotNode, wantNode rnode.Node) error {
 	statePair := s{gotNode.State(), wantNode.State()}
 	switch statePair {
 	case s{rnode.NodeExists, rnode.NodeExists}:
+		if details := p.prevPlan(gotNode, wantNode); details != nil {
+			wantNode.Plan().Set(*details)
+			return nil
+		}
 		action, err := wantNode.Diff(gotNode)
 		if err != nil {
 			return fmt.Errorf("localPlanner: %w", err)
@@ -111,3 +183,33 @@ func (p *planner) planWantGraph(gotNode, wantNode rnode.Node) error {
 
 	return nil
 }
+
+// prevPlan returns the plan for wantNode from the previous planning if
+// neither gotNode nor wantNode have changed since. Returns nil if the plan
+// needs to be recomputed.
+func (p *planner) prevPlan(gotNode, wantNode rnode.Node) *rnode.PlanDetails {
+	if p.prevGot == nil || p.prevWant == nil {
+		return nil
+	}
+	prevGotNode := p.prevGot.Get(gotNode.ID())
+	prevWantNode := p.prevWant.Get(wantNode.ID())
+	if prevGotNode == nil || prevWantNode == nil {
+		return nil
+	}
+	details := prevWantNode.Plan().Details()
+	if details == nil {
+		return nil
+	}
+	for _, pair := range [][2]rnode.Node{{prevGotNode, gotNode}, {prevWantNode, wantNode}} {
+		a, err := rnode.Fingerprint(pair[0])
+		if err != nil {
+			return nil
+		}
+		b, err := rnode.Fingerprint(pair[1])
+		if err != nil || a != b {
+			return nil
+		}
+	}
+	ret := *details
+	return &ret
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/graphviz"
@@ -108,6 +109,38 @@ func TestLocalPlan(t *testing.T) {
 				makeID(0).String(): rnode.OpNothing,
 			},
 		},
+		{
+			name: "error: node is not managed and does not exist",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				node := newNode(0)
+				node.SetOwnership(rnode.OwnershipExternal)
+				node.SetState(rnode.NodeDoesNotExist)
+				gotb.Add(node)
+
+				node = newNode(0)
+				node.SetOwnership(rnode.OwnershipExternal)
+				node.SetState(rnode.NodeExists)
+				wantb.Add(node)
+			},
+			wantErr: true,
+		},
+		{
+			name: "node is not managed and not wanted (nop)",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				node := newNode(0)
+				node.SetOwnership(rnode.OwnershipExternal)
+				node.SetState(rnode.NodeExists)
+				gotb.Add(node)
+
+				node = newNode(0)
+				node.SetOwnership(rnode.OwnershipExternal)
+				node.SetState(rnode.NodeDoesNotExist)
+				wantb.Add(node)
+			},
+			wantPlan: map[string]rnode.Operation{
+				makeID(0).String(): rnode.OpNothing,
+			},
+		},
 		{
 			name: "delete resource (1 -> 0 node)",
 			setupBuilder: func(gotb, wantb *rgraph.Builder) {
@@ -159,6 +192,24 @@ func TestLocalPlan(t *testing.T) {
 				makeID(0).String(): rnode.OpUpdate,
 			},
 		},
+		{
+			name: "diff in ignored field (nop)",
+			setupBuilder: func(gotb, wantb *rgraph.Builder) {
+				node := newNodeWithValue(0, "abc")
+				node.SetOwnership(rnode.OwnershipManaged)
+				node.SetState(rnode.NodeExists)
+				gotb.Add(node)
+
+				node = newNodeWithValue(0, "def")
+				node.SetOwnership(rnode.OwnershipManaged)
+				node.SetState(rnode.NodeExists)
+				node.SetIgnoreDiffPaths([]api.Path{api.Path{}.Pointer().Field("Value")})
+				wantb.Add(node)
+			},
+			wantPlan: map[string]rnode.Operation{
+				makeID(0).String(): rnode.OpNothing,
+			},
+		},
 		{
 			name: "multiple nodes",
 			setupBuilder: func(gotb, wantb *rgraph.Builder) {
@@ -292,3 +343,47 @@ func TestLocalPlan(t *testing.T) {
 		})
 	}
 }
+
+func TestLocalPlanReuse(t *testing.T) {
+	const project = "project-1"
+	graph := func(values ...string) *rgraph.Graph {
+		b := rgraph.NewBuilder()
+		for i, v := range values {
+			id := fake.ID(project, meta.GlobalKey(fmt.Sprintf("fake-%d", i)))
+			nb := fake.NewBuilder(id)
+			mr := fake.NewMutableFake(project, id.Key)
+			mr.Access(func(x *fake.FakeResource) { x.Value = v })
+			r, _ := mr.Freeze()
+			nb.SetResource(r)
+			nb.SetState(rnode.NodeExists)
+			nb.SetOwnership(rnode.OwnershipManaged)
+			b.Add(nb)
+		}
+		return b.MustBuild()
+	}
+
+	prevGot := graph("a", "b", "c")
+	prevWant := graph("a", "x", "c")
+	if err := PlanWantGraph(prevGot, prevWant); err != nil {
+		t.Fatalf("PlanWantGraph() = %v, want nil", err)
+	}
+	// Mark the plans so we can tell if they were reused.
+	for _, n := range prevWant.All() {
+		d := *n.Plan().Details()
+		d.Why = "reused"
+		n.Plan().Set(d)
+	}
+
+	// fake-0: unchanged; fake-1: got changed; fake-2: want changed.
+	got := graph("a", "x", "c")
+	want := graph("a", "x", "y")
+	if err := PlanWantGraph(got, want, ReuseOption(prevGot, prevWant)); err != nil {
+		t.Fatalf("PlanWantGraph() = %v, want nil", err)
+	}
+	for i, wantReused := range []bool{true, false, false} {
+		n := want.Get(fake.ID(project, meta.GlobalKey(fmt.Sprintf("fake-%d", i))))
+		if reused := n.Plan().Details().Why == "reused"; reused != wantReused {
+			t.Errorf("%s: reused = %t, want %t (plan: %v)", n.ID(), reused, wantReused, n.Plan())
+		}
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mermaid renders a resource graph as a Mermaid
+// (https://mermaid.js.org) flowchart. Mermaid is rendered natively by e.g.
+// GitHub and GitLab markdown where installing graphviz is not possible.
+package mermaid
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// opClasses are the class names and fill colors for each Operation. This is
+// the same scheme as graphviz.Do().
+var opClasses = []struct {
+	op    rnode.Operation
+	class string
+	fill  string
+}{
+	{rnode.OpCreate, "create", "palegreen"},
+	{rnode.OpDelete, "delete", "pink"},
+	{rnode.OpRecreate, "recreate", "yellow"},
+	{rnode.OpUpdate, "update", "khaki"},
+	{rnode.OpNothing, "nothing", "#e5e5e5"},
+	{rnode.OpUnknown, "unknown", "#e5e5e5"},
+}
+
+// otherClass is used for Operations not in opClasses.
+const otherClass = "other"
+
+// Do returns a Mermaid flowchart of the resource graph for visualization.
+// Nodes are colored by their planned Operation.
+func Do(g *rgraph.Graph) string {
+	nodes := g.All()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID().String() < nodes[j].ID().String() })
+
+	// Mermaid node ids cannot contain most punctuation, so the nodes are
+	// numbered.
+	ids := map[string]string{}
+	for i, node := range nodes {
+		ids[node.ID().String()] = fmt.Sprintf("n%d", i)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("flowchart TB\n")
+	for _, c := range opClasses {
+		fmt.Fprintf(&buf, "  classDef %s fill:%s\n", c.class, c.fill)
+	}
+	fmt.Fprintf(&buf, "  classDef %s fill:mediumpurple\n", otherClass)
+
+	for _, node := range nodes {
+		label := strings.Join([]string{
+			node.ID().String(),
+			"plan: " + string(node.Plan().Op()),
+			"state: " + string(node.State()),
+		}, "<br/>")
+		fmt.Fprintf(&buf, "  %s[\"%s\"]:::%s\n", ids[node.ID().String()], escape(label), opClass(node.Plan().Op()))
+	}
+	for _, node := range nodes {
+		for _, ref := range node.OutRefs() {
+			to, ok := ids[ref.To.String()]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(&buf, "  %s -->|\"%s\"| %s\n", ids[node.ID().String()], escape(ref.Path.String()), to)
+		}
+	}
+	return buf.String()
+}
+
+func opClass(op rnode.Operation) string {
+	for _, c := range opClasses {
+		if c.op == op {
+			return c.class
+		}
+	}
+	return otherClass
+}
+
+// escape s for use in a quoted Mermaid label.
+func escape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
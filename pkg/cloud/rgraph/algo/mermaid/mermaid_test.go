@@ -0,0 +1,69 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mermaid
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDo(t *testing.T) {
+	a := fake.ID("proj", meta.GlobalKey("a"))
+	b := fake.ID("proj", meta.GlobalKey("b"))
+
+	gb := rgraph.NewBuilder()
+	nb := fake.NewBuilder(a)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	nb.FakeOutRefs = []rnode.ResourceRef{{From: a, Path: api.Path{}.Pointer().Field("Dependencies"), To: b}}
+	gb.Add(nb)
+	nb = fake.NewBuilder(b)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeDoesNotExist)
+	gb.Add(nb)
+	g := gb.MustBuild()
+	g.Get(a).Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	g.Get(b).Plan().Set(rnode.PlanDetails{Operation: rnode.OpDelete})
+
+	want := `flowchart TB
+  classDef create fill:palegreen
+  classDef delete fill:pink
+  classDef recreate fill:yellow
+  classDef update fill:khaki
+  classDef nothing fill:#e5e5e5
+  classDef unknown fill:#e5e5e5
+  classDef other fill:mediumpurple
+  n0["fakes:proj/a<br/>plan: Create<br/>state: Exists"]:::create
+  n1["fakes:proj/b<br/>plan: Delete<br/>state: DoesNotExist"]:::delete
+  n0 -->|"*.Dependencies"| n1
+`
+	if diff := cmp.Diff(Do(g), want); diff != "" {
+		t.Errorf("Do(); -got,+want: %s", diff)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got, want := escape(`a "b"`), "a #quot;b#quot;"; got != want {
+		t.Errorf("escape() = %q, want %q", got, want)
+	}
+}
@@ -37,6 +37,9 @@ func NewBuilder() *Builder {
 // Builder builds resource Graphs.
 type Builder struct {
 	nodes map[cloud.ResourceMapKey]rnode.Builder
+	// collisions are the ids of nodes that replaced a different node with
+	// the same id in Add(). See Validate().
+	collisions []*cloud.ResourceID
 }
 
 func (g *Builder) All() []rnode.Builder {
@@ -47,8 +50,15 @@ func (g *Builder) All() []rnode.Builder {
 	return ret
 }
 
-// Add a node to the resource graph.
-func (g *Builder) Add(node rnode.Builder) { g.nodes[node.ID().MapKey()] = node }
+// Add a node to the resource graph. A node with the same ID is replaced;
+// this is reported by Validate() if the nodes are different.
+func (g *Builder) Add(node rnode.Builder) {
+	key := node.ID().MapKey()
+	if old, ok := g.nodes[key]; ok && old != node {
+		g.collisions = append(g.collisions, node.ID())
+	}
+	g.nodes[key] = node
+}
 
 // Get the node named by id from the graph. Returns nil if the node does not
 // exist.
@@ -71,6 +81,7 @@ func (g *Builder) Build() (*Graph, error) {
 		}
 		newGraph.add(newNode)
 	}
+	newGraph.collisions = append(newGraph.collisions, g.collisions...)
 
 	return newGraph, nil
 }
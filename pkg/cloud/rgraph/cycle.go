@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// CycleError is returned when the references between the Nodes in a Graph
+// form a cycle. The resources in a cycle cannot be created or deleted in any
+// order.
+type CycleError struct {
+	// Refs forming the cycle, in order. Each reference is from the To of
+	// the previous one and the last reference is to the From of the first.
+	Refs []rnode.ResourceRef
+}
+
+// Error lists the resources and the fields with the references, e.g.
+// "reference cycle: fakes:proj/a (*.Dependencies) -> fakes:proj/b
+// (*.Dependencies) -> fakes:proj/a".
+func (e *CycleError) Error() string {
+	var parts []string
+	for _, ref := range e.Refs {
+		parts = append(parts, fmt.Sprintf("%v (%v)", ref.From, ref.Path))
+	}
+	if len(e.Refs) > 0 {
+		parts = append(parts, e.Refs[0].From.String())
+	}
+	return "reference cycle: " + strings.Join(parts, " -> ")
+}
+
+// FindCycle returns a CycleError for a cycle in the references between the
+// Nodes in the Graph. Returns nil if there are no cycles. The search is
+// deterministic: the same Graph returns the same cycle.
+func (g *Graph) FindCycle() *CycleError {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := map[cloud.ResourceMapKey]int{}
+	// path are the references followed from the root of the search to the
+	// current Node.
+	var path []rnode.ResourceRef
+
+	var visit func(n rnode.Node) *CycleError
+	visit = func(n rnode.Node) *CycleError {
+		state[n.ID().MapKey()] = visiting
+		for _, ref := range n.OutRefs() {
+			to := g.Get(ref.To)
+			if to == nil {
+				continue
+			}
+			switch state[ref.To.MapKey()] {
+			case visiting:
+				// The cycle starts at the first reference from ref.To.
+				start := len(path)
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i].From.Equal(ref.To) {
+						start = i
+						break
+					}
+				}
+				cycle := append(append([]rnode.ResourceRef{}, path[start:]...), ref)
+				return &CycleError{Refs: cycle}
+			case unvisited:
+				path = append(path, ref)
+				if err := visit(to); err != nil {
+					return err
+				}
+				path = path[:len(path)-1]
+			}
+		}
+		state[n.ID().MapKey()] = done
+		return nil
+	}
+
+	nodes := g.All()
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID().String() < nodes[j].ID().String() })
+	for _, n := range nodes {
+		if state[n.ID().MapKey()] != unvisited {
+			continue
+		}
+		if err := visit(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+)
+
+// refGraph builds a Graph of Fake nodes from a topology string (see
+// parseTopology()). The references are in the Dependencies field.
+func refGraph(t *testing.T, s string) *Graph {
+	t.Helper()
+	g, err := refBuilder(s).Build()
+	if err != nil {
+		t.Fatalf("Build() = %v, want nil", err)
+	}
+	return g
+}
+
+// refBuilder returns the Builder for refGraph().
+func refBuilder(s string) *Builder {
+	top := parseTopology(s)
+	b := NewBuilder()
+	for name := range top.nodes {
+		id := fake.ID("proj", meta.GlobalKey(name))
+		nb := fake.NewBuilder(id)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		for to := range top.edges[name] {
+			nb.FakeOutRefs = append(nb.FakeOutRefs, rnode.ResourceRef{
+				From: id,
+				Path: api.Path{}.Pointer().Field("Dependencies"),
+				To:   fake.ID("proj", meta.GlobalKey(to)),
+			})
+		}
+		b.Add(nb)
+	}
+	return b
+}
+
+func TestFindCycle(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		top  string
+		want string
+	}{
+		{name: "empty"},
+		{name: "no cycle", top: "a -> b -> c; a -> c"},
+		{
+			name: "self reference",
+			top:  "a -> a",
+			want: "reference cycle: fakes:proj/a (*.Dependencies) -> fakes:proj/a",
+		},
+		{
+			name: "cycle",
+			top:  "x -> a -> b -> c -> a",
+			want: "reference cycle: fakes:proj/a (*.Dependencies) -> fakes:proj/b (*.Dependencies) -> fakes:proj/c (*.Dependencies) -> fakes:proj/a",
+		},
+		{
+			name: "cycle not through the first node",
+			top:  "a -> b; c -> d -> c",
+			want: "reference cycle: fakes:proj/c (*.Dependencies) -> fakes:proj/d (*.Dependencies) -> fakes:proj/c",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			g := refGraph(t, tc.top)
+			ce := g.FindCycle()
+			var got string
+			if ce != nil {
+				got = ce.Error()
+				for i, ref := range ce.Refs {
+					next := ce.Refs[(i+1)%len(ce.Refs)]
+					if !ref.To.Equal(next.From) {
+						t.Errorf("Refs[%d].To = %v, want %v", i, ref.To, next.From)
+					}
+				}
+			}
+			if got != tc.want {
+				t.Errorf("FindCycle() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
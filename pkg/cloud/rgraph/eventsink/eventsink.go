@@ -0,0 +1,134 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package eventsink defines the interface used to report progress from
+// planning and execution.
+//
+// The Event fields map onto Kubernetes Events. A Kubernetes EventRecorder can
+// be used as a Sink with FromRecorder:
+//
+//	sink := eventsink.FromRecorder(func(eventType, reason, message string) {
+//		recorder.Event(obj, eventType, reason, message)
+//	})
+package eventsink
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// Severity of the Event. The values are the same as the Kubernetes Event types.
+type Severity string
+
+const (
+	// SeverityNormal is for information about normal progress.
+	SeverityNormal Severity = "Normal"
+	// SeverityWarning is for errors and other conditions that may need
+	// attention.
+	SeverityWarning Severity = "Warning"
+)
+
+// Reasons used for Events emitted by this library.
+const (
+	// ReasonPlanned is emitted for each Node with a change planned.
+	ReasonPlanned = "Planned"
+	// ReasonActionCompleted is emitted when an Action completes
+	// successfully.
+	ReasonActionCompleted = "ActionCompleted"
+	// ReasonActionFailed is emitted when an Action returns an error.
+	ReasonActionFailed = "ActionFailed"
+	// ReasonReconcileFailed is emitted when an iteration of reconciliation
+	// fails.
+	ReasonReconcileFailed = "ReconcileFailed"
+)
+
+// Event describes a notable occurrence in planning or execution.
+type Event struct {
+	Severity Severity
+	// Reason is a short, CamelCase, machine-understandable string.
+	Reason string
+	// ResourceID the Event is about. This may be nil if the Event is not
+	// about a specific resource.
+	ResourceID *cloud.ResourceID
+	// Message is a human readable description.
+	Message string
+}
+
+// String implements Stringer.
+func (e *Event) String() string {
+	return fmt.Sprintf("%s %s: %s", e.Severity, e.Reason, e.FullMessage())
+}
+
+// FullMessage is the Message, prefixed by the ResourceID if set.
+func (e *Event) FullMessage() string {
+	if e.ResourceID == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%v: %s", e.ResourceID, e.Message)
+}
+
+// Sink receives Events. Implementations must be thread-safe and should not
+// block.
+type Sink interface {
+	Emit(ev *Event)
+}
+
+// Emit ev to sink if sink is not nil. This is a convenience for emitters
+// where the Sink is optional.
+func Emit(sink Sink, ev *Event) {
+	if sink == nil {
+		return
+	}
+	sink.Emit(ev)
+}
+
+// RecorderFunc has the same signature as the Kubernetes
+// EventRecorder.Event(), with the involved object bound by the caller.
+type RecorderFunc func(eventType, reason, message string)
+
+// FromRecorder returns a Sink that sends Events to f. The ResourceID is
+// included in the message.
+func FromRecorder(f RecorderFunc) Sink {
+	return recorderSink(f)
+}
+
+type recorderSink RecorderFunc
+
+func (s recorderSink) Emit(ev *Event) {
+	s(string(ev.Severity), ev.Reason, ev.FullMessage())
+}
+
+// Recorder is a Sink that stores the Events. This is useful for testing.
+type Recorder struct {
+	lock   sync.Mutex
+	events []*Event
+}
+
+// Emit implements Sink.
+func (r *Recorder) Emit(ev *Event) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, ev)
+}
+
+// Events returns the Events that have been recorded.
+func (r *Recorder) Events() []*Event {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*Event{}, r.events...)
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package eventsink
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromRecorder(t *testing.T) {
+	var got []string
+	sink := FromRecorder(func(eventType, reason, message string) {
+		got = append(got, eventType+"/"+reason+"/"+message)
+	})
+	id := &cloud.ResourceID{ProjectID: "proj", Resource: "addresses", Key: meta.GlobalKey("addr")}
+	Emit(sink, &Event{Severity: SeverityWarning, Reason: ReasonActionFailed, ResourceID: id, Message: "boom"})
+	Emit(sink, &Event{Severity: SeverityNormal, Reason: ReasonPlanned, Message: "no id"})
+	// Emit to a nil Sink is a no-op.
+	Emit(nil, &Event{})
+
+	want := []string{
+		"Warning/ActionFailed/addresses:proj/addr: boom",
+		"Normal/Planned/no id",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("events: -got,+want: %s", diff)
+	}
+}
@@ -47,6 +47,25 @@ type Action interface {
 	Metadata() *ActionMetadata
 }
 
+// ResumableAction is an Action that can detect that its operation has already
+// been performed, e.g. by a prior execution that did not run to completion.
+type ResumableAction interface {
+	Action
+	// Resume checks the current state of the resources in the Cloud and
+	// completes the operation of the Action if needed (e.g. verifying or
+	// updating a resource that already exists instead of creating it).
+	// An existing resource is only changed or treated as done if adopt
+	// returns true for it. Returns done = false if the Action should be
+	// Run() normally.
+	Resume(ctx context.Context, c cloud.Cloud, adopt AdoptFunc) (events EventList, done bool, err error)
+}
+
+// AdoptFunc returns true if the existing resource id may be adopted by a
+// resumed Action, i.e. it is owned by the caller (e.g. it has an ownership
+// marker in its description). obj is the API object for the resource (e.g.
+// *compute.Address) at the version it was fetched.
+type AdoptFunc func(id *cloud.ResourceID, obj any) bool
+
 type ActionType string
 
 var (
@@ -65,6 +84,9 @@ type ActionMetadata struct {
 	Type ActionType
 	// Summary is a human readable description of this action.
 	Summary string
+	// ResourceID is the resource affected by this action. This is nil if
+	// the action is not for a single resource.
+	ResourceID *cloud.ResourceID
 }
 
 // ActionBase is a helper that implements some standard behaviors of common
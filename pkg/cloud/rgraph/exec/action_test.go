@@ -32,6 +32,7 @@ type testAction struct {
 	name   string
 	events EventList
 	err    error
+	id     *cloud.ResourceID
 }
 
 func (a *testAction) String() string {
@@ -48,9 +49,10 @@ func (a *testAction) Run(context.Context, cloud.Cloud) (EventList, error) {
 
 func (a *testAction) Metadata() *ActionMetadata {
 	return &ActionMetadata{
-		Name:    fmt.Sprintf("%s(%v)", a.name, a.events),
-		Type:    ActionTypeCustom,
-		Summary: "Action used for testing",
+		Name:       fmt.Sprintf("%s(%v)", a.name, a.events),
+		Type:       ActionTypeCustom,
+		Summary:    "Action used for testing",
+		ResourceID: a.id,
 	}
 }
 
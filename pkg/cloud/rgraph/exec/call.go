@@ -0,0 +1,100 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// Call is a call to the Cloud API made by an Action.
+type Call struct {
+	// Method of the API, e.g. "Insert" or "SetSslPolicy".
+	Method string
+	// Version of the API.
+	Version meta.Version
+	// ID of the resource.
+	ID *cloud.ResourceID
+	// Body is a summary of the request body (see BodySummary()). This is
+	// empty if the call has no body.
+	Body string
+}
+
+// String returns a human readable representation of the Call, e.g.
+// `Insert ga compute/addresses:proj/us-central1/addr {"name":"addr"}`.
+func (c Call) String() string {
+	s := fmt.Sprintf("%s %s %v", c.Method, c.Version, c.ID)
+	if c.Body != "" {
+		s += " " + c.Body
+	}
+	return s
+}
+
+// BodySummary returns the compact JSON encoding of the request body obj.
+func BodySummary(obj any) string {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("<%T: %v>", obj, err)
+	}
+	return string(b)
+}
+
+// CallDescriber is implemented by Actions that can describe the Calls that
+// Run() will make, in order.
+type CallDescriber interface {
+	Calls() []Call
+}
+
+// DryRunCalls returns the Calls that would be made by executing the
+// Actions, in the order they would be executed. Nothing is executed. Actions
+// that do not implement CallDescriber are assumed to make no Calls (e.g.
+// Actions that only signal Events).
+//
+// The Actions are used in the same way as by an Executor in DryRun mode and
+// cannot be executed afterwards.
+func DryRunCalls(pending []Action) ([]Call, error) {
+	o := &callRecorder{}
+	ex, err := NewSerialExecutor(pending, DryRunOption(true), ObserverOption(o))
+	if err != nil {
+		return nil, err
+	}
+	result, err := ex.Run(context.Background(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Pending) > 0 {
+		return o.calls, errors.New("DryRunCalls: some Actions could not be run (dependency cycle or missing Events)")
+	}
+	return o.calls, nil
+}
+
+// callRecorder records the Calls of the Actions as they are executed.
+type callRecorder struct {
+	BaseObserver
+	calls []Call
+}
+
+func (o *callRecorder) OnNodeDone(oa ObservedAction) {
+	if cd, ok := oa.Action.(CallDescriber); ok {
+		o.calls = append(o.calls, cd.Calls()...)
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+// callAction is a testAction that makes a single Insert Call.
+type callAction struct {
+	*testAction
+}
+
+func (a *callAction) Calls() []Call {
+	return []Call{{Method: "Insert", Version: meta.VersionGA, ID: a.id, Body: BodySummary(map[string]string{"name": a.name})}}
+}
+
+func TestDryRunCalls(t *testing.T) {
+	id := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(name)}
+	}
+	// B does not make any Calls.
+	actions := func(graphStr string) []Action {
+		var actions []Action
+		for _, a := range actionsFromGraphStr(graphStr) {
+			ta := a.(*testAction)
+			ta.id = id(ta.name)
+			if ta.name == "B" {
+				actions = append(actions, ta)
+			} else {
+				actions = append(actions, &callAction{ta})
+			}
+		}
+		return actions
+	}
+
+	for _, tc := range []struct {
+		name     string
+		graphStr string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "empty",
+			graphStr: "",
+		},
+		{
+			name:     "chain",
+			graphStr: "C -> B -> A",
+			want: []string{
+				`Insert ga fakes:proj/C {"name":"C"}`,
+				`Insert ga fakes:proj/A {"name":"A"}`,
+			},
+		},
+		{
+			name:     "Run() is not called",
+			graphStr: "!A -> C",
+			want: []string{
+				`Insert ga fakes:proj/A {"name":"A"}`,
+				`Insert ga fakes:proj/C {"name":"C"}`,
+			},
+		},
+		{
+			name:     "cycle",
+			graphStr: "A -> C -> A",
+			wantErr:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			calls, err := DryRunCalls(actions(tc.graphStr))
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("DryRunCalls() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var got []string
+			for _, c := range calls {
+				got = append(got, c.String())
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("DryRunCalls(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
@@ -18,30 +18,21 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/eventsink"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/go-logr/logr"
 )
 
-type Result struct {
-	// Completed Actions with no errors.
-	Completed []Action
-	// Errors are Actions that failed with an error.
-	Errors []ActionWithErr
-	// Pending are Actions that could not be executed due to missing
-	// preconditions.
-	Pending []Action
-}
-
-type ActionWithErr struct {
-	Action Action
-	Err    error
-}
-
 // Executor peforms the operations given by a list of Actions.
 type Executor interface {
 	// Run the actions. Returns non-nil if there was an error in execution of
-	// one or more Actions.
+	// one or more Actions. Errors from Actions are returned as an
+	// *ExecError.
 	Run(context.Context, cloud.Cloud) (*Result, error)
 }
 
@@ -52,11 +43,93 @@ func TracerOption(t Tracer) Option {
 	return func(c *ExecutorConfig) { c.Tracer = t }
 }
 
+// EventSinkOption sets a sink for Events about the execution of the Actions.
+func EventSinkOption(s eventsink.Sink) Option {
+	return func(c *ExecutorConfig) { c.EventSink = s }
+}
+
+// ObserverOption adds an Observer that is notified of the progress of the
+// execution. This option can be given multiple times.
+func ObserverOption(o Observer) Option {
+	return func(c *ExecutorConfig) { c.Observers = append(c.Observers, o) }
+}
+
+// LoggerOption sets the Logger for the execution. The log lines for an
+// Action have the keys defined in package rlog. The default is the Logger in
+// the context given to Executor.Run() (see rlog.FromContext()).
+func LoggerOption(l logr.Logger) Option {
+	return func(c *ExecutorConfig) { c.Logger = l }
+}
+
 // DryRunOption will run in dry run mode if true.
 func DryRunOption(dryRun bool) Option {
 	return func(c *ExecutorConfig) { c.DryRun = dryRun }
 }
 
+// ResumeOption will call Resume() on Actions that implement ResumableAction
+// before running them. This allows a plan to be executed again after a prior
+// execution was interrupted. Resuming adds a call to the Cloud before each
+// such Action. adopt verifies that an existing resource is owned by the
+// caller before it is adopted; an Action that finds a resource that is not
+// owned fails without changing it. Resume is disabled by default or if adopt
+// is nil.
+func ResumeOption(adopt AdoptFunc) Option {
+	return func(c *ExecutorConfig) { c.Adopt = adopt }
+}
+
+// MaxParallelismOption sets the maximum number of Actions that are run
+// concurrently. This only applies to the parallel Executor.
+func MaxParallelismOption(n int) Option {
+	return func(c *ExecutorConfig) { c.MaxParallelism = n }
+}
+
+// ActionTimeoutOption limits the time each Action can run to d. An Action
+// that exceeds its timeout fails with an error wrapping ErrActionTimeout;
+// the other Actions are not affected (subject to the ErrorStrategy). Zero
+// means no timeout.
+func ActionTimeoutOption(d time.Duration) Option {
+	return func(c *ExecutorConfig) { c.ActionTimeout = d }
+}
+
+// PlanTimeoutOption limits the time for the whole execution to d. When the
+// timeout is exceeded, the running Actions are cancelled (their context is
+// done) and the Actions that have not been started are reported in
+// Result.Skipped. Run() returns an *ExecError with Err set to
+// context.DeadlineExceeded. Zero means no timeout.
+func PlanTimeoutOption(d time.Duration) Option {
+	return func(c *ExecutorConfig) { c.PlanTimeout = d }
+}
+
+// StaleStateOption fails the Actions on resources whose state was fetched
+// from the Cloud more than ttl before the Action is run, as the plan for the
+// Action may be based on state that has since changed. fetchTime returns
+// when the state of the resource was fetched; a zero time means the fetch
+// time is unknown and the Action is run. fetchTime may be called
+// concurrently. The failed Actions return an error wrapping ErrStaleState;
+// the caller should fetch the state again, re-plan and execute the new plan
+// (see plan.Refresh()).
+func StaleStateOption(ttl time.Duration, fetchTime func(*cloud.ResourceID) time.Time) Option {
+	return func(c *ExecutorConfig) {
+		c.StaleTTL = ttl
+		c.FetchTime = fetchTime
+	}
+}
+
+// ClockOption sets the function returning the current time used to check
+// StaleStateOption(). The default is time.Now. This is used for testing.
+func ClockOption(now func() time.Time) Option {
+	return func(c *ExecutorConfig) { c.Now = now }
+}
+
+// ErrActionTimeout is wrapped by the error of an Action that exceeded the
+// ActionTimeoutOption(). The state of the resource is unknown as the
+// operation may have been completed in the Cloud.
+var ErrActionTimeout = errors.New("action timed out")
+
+// ErrStaleState is wrapped by the error of an Action that was not run
+// because the state of its resource was stale (see StaleStateOption()).
+var ErrStaleState = errors.New("stale resource state")
+
 // ErrorStrategy to use when an Action returns an error.
 type ErrorStrategy string
 
@@ -78,23 +151,221 @@ func ErrorStrategyOption(s ErrorStrategy) Option {
 
 func defaultExecutorConfig() *ExecutorConfig {
 	return &ExecutorConfig{
-		DryRun:        false,
-		ErrorStrategy: StopOnError,
+		DryRun:         false,
+		ErrorStrategy:  StopOnError,
+		MaxParallelism: defaultMaxParallelism,
+		Now:            time.Now,
 	}
 }
 
 // ExecutorConfig for the executor implementation.
 type ExecutorConfig struct {
-	Tracer        Tracer
-	DryRun        bool
-	ErrorStrategy ErrorStrategy
+	Tracer         Tracer
+	EventSink      eventsink.Sink
+	DryRun         bool
+	ErrorStrategy  ErrorStrategy
+	Adopt          AdoptFunc
+	MaxParallelism int
+	Observers      []Observer
+	OrderHints     []OrderHint
+	SpanStarter    SpanStarter
+	Logger         logr.Logger
+	Progress       ProgressStore
+	ActionTimeout  time.Duration
+	PlanTimeout    time.Duration
+	StaleTTL       time.Duration
+	FetchTime      func(*cloud.ResourceID) time.Time
+	Now            func() time.Time
 }
 
+// defaultMaxParallelism is the default limit on concurrently running
+// Actions.
+const defaultMaxParallelism = 10
+
 func (c *ExecutorConfig) validate() error {
 	switch c.ErrorStrategy {
 	case ContinueOnError, StopOnError:
 	default:
 		return fmt.Errorf("invalid ErrorStrategy: %q", c.ErrorStrategy)
 	}
+	if c.MaxParallelism < 1 {
+		return fmt.Errorf("invalid MaxParallelism: %d", c.MaxParallelism)
+	}
+	if c.ActionTimeout < 0 {
+		return fmt.Errorf("invalid ActionTimeout: %v", c.ActionTimeout)
+	}
+	if c.PlanTimeout < 0 {
+		return fmt.Errorf("invalid PlanTimeout: %v", c.PlanTimeout)
+	}
+	if c.StaleTTL < 0 || c.StaleTTL > 0 && c.FetchTime == nil {
+		return fmt.Errorf("invalid StaleStateOption: ttl %v, fetchTime set %t", c.StaleTTL, c.FetchTime != nil)
+	}
+	if c.Now == nil {
+		return fmt.Errorf("invalid ClockOption: nil")
+	}
+	return nil
+}
+
+// logger returns the Logger for the execution with ctx.
+func (c *ExecutorConfig) logger(ctx context.Context) logr.Logger {
+	return rlog.OrContext(ctx, c.Logger)
+}
+
+// runAction runs a, resuming the Action if it is a ResumableAction and
+// resume is enabled (adopt is not nil).
+func runAction(ctx context.Context, c cloud.Cloud, a Action, adopt AdoptFunc) (EventList, error) {
+	if ra, ok := a.(ResumableAction); ok && adopt != nil {
+		events, done, err := ra.Resume(ctx, c, adopt)
+		if err != nil {
+			return nil, err
+		}
+		if done {
+			return events, nil
+		}
+	}
+	return a.Run(ctx, c)
+}
+
+// checkStale returns an error wrapping ErrStaleState if the state of the
+// resource of a was fetched more than StaleTTL ago. Meta Actions do not
+// change resources and are not checked.
+func (c *ExecutorConfig) checkStale(a Action) error {
+	if c.StaleTTL == 0 {
+		return nil
+	}
+	md := a.Metadata()
+	if md.ResourceID == nil || md.Type == ActionTypeMeta {
+		return nil
+	}
+	fetched := c.FetchTime(md.ResourceID)
+	if fetched.IsZero() {
+		return nil
+	}
+	if age := c.Now().Sub(fetched); age > c.StaleTTL {
+		return fmt.Errorf("%w: %v was fetched %v ago (ttl %v)", ErrStaleState, md.ResourceID, age, c.StaleTTL)
+	}
 	return nil
 }
+
+// runWithTimeout calls run with a context that is done after d. d == 0 means
+// no timeout. If the timeout was exceeded, the error from run is wrapped with
+// ErrActionTimeout.
+func runWithTimeout(ctx context.Context, d time.Duration, run func(context.Context) (EventList, error)) (EventList, error) {
+	if d == 0 {
+		return run(ctx)
+	}
+	actx, cancel := context.WithTimeout(ctx, d)
+	defer cancel()
+	events, err := run(actx)
+	// Only report a timeout for this Action; the parent context may have
+	// been done for other reasons (e.g. PlanTimeoutOption()).
+	if err != nil && ctx.Err() == nil && errors.Is(actx.Err(), context.DeadlineExceeded) {
+		return events, fmt.Errorf("%w after %v: %w", ErrActionTimeout, d, err)
+	}
+	return events, err
+}
+
+// withPlanTimeout returns the context for the execution with the
+// PlanTimeout.
+func (c *ExecutorConfig) withPlanTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.PlanTimeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.PlanTimeout)
+}
+
+// actionFinished records the outcome of running te.Action in result. The
+// Events of the Action are signaled to the pending Actions only if it
+// succeeded, so the dependencies of a failed Action remain pending. Returns
+// errStopExecution if the execution should stop due to the ErrorStrategy.
+// The Executors share this so that they handle errors in the same way.
+func (c *ExecutorConfig) actionFinished(ctx context.Context, result *Result, progress *progressTracker, te *TraceEntry, events EventList, runErr error, signal func(Event) []TraceSignal) error {
+	a := te.Action
+	observeEnd(c.Observers, te, runErr)
+	logActionEnd(c.logger(ctx), te, runErr)
+
+	if runErr == nil {
+		result.Completed = append(result.Completed, a)
+	} else {
+		te.Err = runErr
+		result.Errors = append(result.Errors, ActionWithErr{Action: a, Err: runErr})
+	}
+	if !c.DryRun {
+		emitActionEvent(c.EventSink, a, runErr)
+	}
+	if err := progress.record(ctx, a, runErr); err != nil {
+		return err
+	}
+	if runErr == nil {
+		for _, ev := range events {
+			te.Signaled = append(te.Signaled, signal(ev)...)
+		}
+	}
+	if c.Tracer != nil {
+		c.Tracer.Record(te, runErr)
+	}
+	if runErr != nil && c.ErrorStrategy == StopOnError {
+		return errStopExecution
+	}
+	return nil
+}
+
+// emitActionEvent emits the Event for the completion of Action a.
+func emitActionEvent(sink eventsink.Sink, a Action, err error) {
+	md := a.Metadata()
+	if err != nil {
+		eventsink.Emit(sink, &eventsink.Event{
+			Severity:   eventsink.SeverityWarning,
+			Reason:     eventsink.ReasonActionFailed,
+			ResourceID: md.ResourceID,
+			Message:    fmt.Sprintf("%s failed: %v", md.Name, err),
+		})
+		return
+	}
+	eventsink.Emit(sink, &eventsink.Event{
+		Severity:   eventsink.SeverityNormal,
+		Reason:     eventsink.ReasonActionCompleted,
+		ResourceID: md.ResourceID,
+		Message:    fmt.Sprintf("%s completed", md.Name),
+	})
+}
+
+// actionLogValues returns the key/value pairs for logging a.
+func actionLogValues(a Action) []any {
+	md := a.Metadata()
+	var ret []any
+	if md.ResourceID != nil {
+		ret = append(ret, rlog.KeyNode, md.ResourceID.String())
+	}
+	ret = append(ret, rlog.KeyOp, string(md.Type))
+	if v := actionVersion(a); v != "" {
+		ret = append(ret, rlog.KeyVersion, v)
+	}
+	return ret
+}
+
+// actionVersion returns the API version used by a. Returns "" if a does not
+// implement CallDescriber.
+func actionVersion(a Action) string {
+	if cd, ok := a.(CallDescriber); ok {
+		if calls := cd.Calls(); len(calls) > 0 {
+			return string(calls[0].Version)
+		}
+	}
+	return ""
+}
+
+// logActionEnd logs the result of the Action in te. Actions that only
+// signal Events are logged at a higher verbosity.
+func logActionEnd(l logr.Logger, te *TraceEntry, err error) {
+	kv := append(actionLogValues(te.Action), "duration", te.End.Sub(te.Start).String())
+	if err != nil {
+		l.Error(err, "Action failed", kv...)
+		return
+	}
+	level := 2
+	if te.Action.Metadata().Type == ActionTypeMeta {
+		level = 5
+	}
+	l.V(level).Info("Action done", kv...)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// NewParallelExecutor returns a new Executor that runs Actions concurrently
+// as soon as their dependencies are satisfied. At most
+// ExecutorConfig.MaxParallelism Actions will be running at the same time
+// (see MaxParallelismOption()).
+//
+// An Action is never started before all of the Events it is waiting on have
+// been signaled, i.e. the Actions it depends on have completed without error.
+// With StopOnError, no new Actions are started after an error is detected;
+// Actions that are already running will be allowed to finish and their
+// results are included in the Result.
+func NewParallelExecutor(pending []Action, opts ...Option) (*parallelExecutor, error) {
+	ret := &parallelExecutor{
+		config: defaultExecutorConfig(),
+		result: &Result{Pending: pending},
+	}
+	for _, opt := range opts {
+		opt(ret.config)
+	}
+
+	if err := ret.config.validate(); err != nil {
+		return nil, err
+	}
+
+	if ret.config.DryRun {
+		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
+			return a.DryRun(), nil
+		}
+	} else {
+		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
+			if ret.progress.isDone(a) {
+				ret.config.logger(ctx).V(2).Info("Action done in a previous execution, skipping", actionLogValues(a)...)
+				return a.DryRun(), nil
+			}
+			if err := ret.config.checkStale(a); err != nil {
+				return nil, err
+			}
+			return runAction(ctx, c, a, ret.config.Adopt)
+		}
+	}
+
+	return ret, nil
+}
+
+// parallelExecutor runs the Actions in worker goroutines. All of the
+// bookkeeping (Result, signaling Events, Tracer, EventSink) is done by the
+// goroutine calling Run() so the Actions are not accessed concurrently
+// except for Run() itself.
+type parallelExecutor struct {
+	config *ExecutorConfig
+
+	runFunc func(context.Context, cloud.Cloud, Action) (EventList, error)
+	result  *Result
+	// progress is nil unless ProgressOption() is set.
+	progress *progressTracker
+	// running are the Actions that have been started but not finished.
+	running []Action
+}
+
+var _ Executor = (*parallelExecutor)(nil)
+
+// actionDone is sent by a worker when an Action has finished running.
+type actionDone struct {
+	te     *TraceEntry
+	events EventList
+	err    error
+}
+
+func (ex *parallelExecutor) Run(ctx context.Context, c cloud.Cloud) (*Result, error) {
+	if err := ex.loadProgress(ctx); err != nil {
+		return observePlanDone(ex.config.Observers, ex.result, err)
+	}
+	ctx, cancel := ex.config.withPlanTimeout(ctx)
+	defer cancel()
+
+	done := make(chan actionDone)
+	var (
+		stopped bool
+		errOut  error
+		// ctxErr is set if the execution was stopped because ctx was
+		// done.
+		ctxErr error
+	)
+
+	for {
+		if ctxErr == nil && ctx.Err() != nil && len(ex.result.Pending) > 0 {
+			stopped = true
+			ctxErr = ctx.Err()
+		}
+		for !stopped && len(ex.running) < ex.config.MaxParallelism {
+			a := ex.next()
+			if a == nil {
+				break
+			}
+			ex.running = append(ex.running, a)
+			te := &TraceEntry{
+				Action: a,
+				Start:  time.Now(),
+			}
+			observeStart(ex.config.Observers, te)
+			go ex.runAction(ctx, c, te, done)
+		}
+		if len(ex.running) == 0 {
+			break
+		}
+		d := <-done
+		for i, a := range ex.running {
+			if a == d.te.Action {
+				ex.running = append(ex.running[:i], ex.running[i+1:]...)
+				break
+			}
+		}
+
+		err := ex.finish(ctx, d)
+		if err == nil {
+			continue
+		}
+		stopped = true
+		if !errors.Is(err, errStopExecution) && errOut == nil {
+			errOut = err
+		}
+	}
+
+	obs := ex.config.Observers
+	if errOut != nil {
+		return observePlanDone(obs, ex.result, errOut)
+	}
+	if ctxErr != nil {
+		ex.config.logger(ctx).V(2).Info("Execution cancelled, skipping remaining Actions", "skipped", len(ex.result.Pending), "err", ctxErr.Error())
+		ex.result.skipPending()
+		return observePlanDone(obs, ex.result, &ExecError{Result: ex.result, Stopped: true, Err: ctxErr})
+	}
+	if stopped {
+		return observePlanDone(obs, ex.result, &ExecError{Result: ex.result, Stopped: true})
+	}
+	if ex.config.Tracer != nil {
+		ex.config.Tracer.Finish(ex.result.Pending)
+	}
+	if len(ex.result.Errors) > 0 {
+		return observePlanDone(obs, ex.result, &ExecError{Result: ex.result})
+	}
+
+	return observePlanDone(obs, ex.result, nil)
+}
+
+// runAction runs the Action for te in the worker goroutine.
+func (ex *parallelExecutor) runAction(ctx context.Context, c cloud.Cloud, te *TraceEntry, done chan<- actionDone) {
+	events, err := runWithTimeout(ctx, ex.config.ActionTimeout, func(ctx context.Context) (EventList, error) {
+		return runWithSpan(ctx, ex.config.SpanStarter, te.Action, func(ctx context.Context) (EventList, error) {
+			return ex.runFunc(ctx, c, te.Action)
+		})
+	})
+	te.End = time.Now()
+	done <- actionDone{te: te, events: events, err: err}
+}
+
+// finish records the result of the Action (see actionFinished()).
+func (ex *parallelExecutor) finish(ctx context.Context, d actionDone) error {
+	if err := ex.config.actionFinished(ctx, ex.result, ex.progress, d.te, d.events, d.err, ex.signal); err != nil {
+		return fmt.Errorf("parallelExecutor: %w", err)
+	}
+	return nil
+}
+
+// loadProgress sets up the progressTracker if ProgressOption() is set. This
+// is called before any workers are started.
+func (ex *parallelExecutor) loadProgress(ctx context.Context) error {
+	if ex.config.DryRun {
+		return nil
+	}
+	var err error
+	ex.progress, err = newProgressTracker(ctx, ex.config.Progress, ex.result.Pending)
+	if err != nil {
+		return fmt.Errorf("parallelExecutor: %w", err)
+	}
+	return nil
+}
+
+func (ex *parallelExecutor) next() Action {
+	i := nextRunnable(ex.result.Pending, ex.running, ex.config.OrderHints)
+	if i < 0 {
+		return nil
+	}
+	a := ex.result.Pending[i]
+	ex.result.Pending = append(ex.result.Pending[0:i], ex.result.Pending[i+1:]...)
+	return a
+}
+
+func (ex *parallelExecutor) signal(ev Event) []TraceSignal {
+	var ret []TraceSignal
+	for _, a := range ex.result.Pending {
+		if a.Signal(ev) {
+			ret = append(ret, TraceSignal{Event: ev, SignaledAction: a})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/eventsink"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParallelExecutor(t *testing.T) {
+	for _, dryRun := range []string{"dry run", "normal run"} {
+		t.Run(dryRun, func(t *testing.T) {
+			for _, tc := range []struct {
+				name     string
+				graph    string
+				strategy ErrorStrategy
+				// pending should be sorted alphabetically for comparison.
+				pending []string
+				errs    []string
+				wantErr bool
+			}{
+				{
+					name:  "empty graph",
+					graph: "",
+				},
+				{
+					name:  "one action",
+					graph: "A",
+				},
+				{
+					name:  "chain of 3 actions",
+					graph: "A -> B -> C",
+				},
+				{
+					name:  "two chains with common root",
+					graph: "A -> B -> C; A -> C",
+				},
+				{
+					name:  "wide fan out and fan in",
+					graph: "A -> B -> Z; A -> C -> Z; A -> D -> Z; A -> E -> Z; A -> F -> Z",
+				},
+				{
+					name:    "cycle in larger graph",
+					graph:   "A -> B -> C -> D -> C; X -> Y",
+					pending: []string{"C", "D"},
+				},
+				{
+					name:     "stop on error",
+					graph:    "A -> !B -> C -> D",
+					strategy: StopOnError,
+					pending:  []string{"C", "D"},
+					errs:     []string{"B"},
+					wantErr:  true,
+				},
+				{
+					name:     "continue on error",
+					graph:    "A -> !B -> C -> D; A -> E -> F",
+					strategy: ContinueOnError,
+					pending:  []string{"C", "D"},
+					errs:     []string{"B"},
+					wantErr:  true,
+				},
+			} {
+				if dryRun == "dry run" && tc.wantErr {
+					// Dry run assumes no errors happen, so skip these test cases.
+					continue
+				}
+				t.Run(tc.name, func(t *testing.T) {
+					t.Logf("Graph: %q", tc.graph)
+					actions := actionsFromGraphStr(tc.graph)
+
+					strategy := tc.strategy
+					if strategy == "" {
+						strategy = StopOnError
+					}
+					tr := NewGraphvizTracer()
+					ex, err := NewParallelExecutor(actions,
+						ErrorStrategyOption(strategy),
+						MaxParallelismOption(3),
+						TracerOption(tr),
+						DryRunOption(dryRun == "dry run"))
+					if err != nil {
+						t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+					}
+					result, err := ex.Run(context.Background(), nil)
+					if gotErr := err != nil; gotErr != tc.wantErr {
+						t.Fatalf("Run() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+					}
+					got := sortedStrings(result.Pending, func(a Action) string { return a.(*testAction).name })
+					if diff := cmp.Diff(got, tc.pending); diff != "" {
+						t.Errorf("pending: diff -got,+want: %s", diff)
+					}
+					got = sortedStrings(result.Errors, func(a ActionWithErr) string { return a.Action.(*testAction).name })
+					if diff := cmp.Diff(got, tc.errs); diff != "" {
+						t.Errorf("errors: diff -got,+want: %s", diff)
+					}
+					t.Log(tr.String())
+				})
+			}
+		})
+	}
+}
+
+func TestParallelExecutorInvalidConfig(t *testing.T) {
+	if _, err := NewParallelExecutor(nil, MaxParallelismOption(0)); err == nil {
+		t.Error("NewParallelExecutor(MaxParallelism=0) = nil, want error")
+	}
+}
+
+// hookTestAction calls hook (if set) when Run.
+type hookTestAction struct {
+	testAction
+	hook func()
+}
+
+func (a *hookTestAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
+	if a.hook != nil {
+		a.hook()
+	}
+	return a.testAction.Run(ctx, c)
+}
+
+// orderLog records the start and end of the Actions.
+type orderLog struct {
+	lock      sync.Mutex
+	active    int
+	maxActive int
+	start     map[string]int
+	end       map[string]int
+	seq       int
+}
+
+func (l *orderLog) hook(name string) func() {
+	return func() {
+		l.lock.Lock()
+		l.seq++
+		l.start[name] = l.seq
+		l.active++
+		if l.active > l.maxActive {
+			l.maxActive = l.active
+		}
+		l.lock.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		l.lock.Lock()
+		l.seq++
+		l.end[name] = l.seq
+		l.active--
+		l.lock.Unlock()
+	}
+}
+
+func TestParallelExecutorOrdering(t *testing.T) {
+	// Three layers: R -> M0..M7 -> L0..L7, each Li depends on Mi and
+	// M((i+1)%8).
+	log := &orderLog{start: map[string]int{}, end: map[string]int{}}
+	deps := map[string][]string{}
+	actions := map[string]*hookTestAction{}
+	add := func(name string, want ...string) {
+		a := &hookTestAction{
+			testAction: testAction{name: name, events: EventList{StringEvent(name)}},
+			hook:       log.hook(name),
+		}
+		for _, w := range want {
+			a.Want = append(a.Want, StringEvent(w))
+		}
+		actions[name] = a
+		deps[name] = want
+	}
+	add("R")
+	for i := 0; i < 8; i++ {
+		add(fmt.Sprintf("M%d", i), "R")
+	}
+	for i := 0; i < 8; i++ {
+		add(fmt.Sprintf("L%d", i), fmt.Sprintf("M%d", i), fmt.Sprintf("M%d", (i+1)%8))
+	}
+	var pending []Action
+	for _, a := range actions {
+		pending = append(pending, a)
+	}
+
+	const maxParallelism = 4
+	ex, err := NewParallelExecutor(pending, MaxParallelismOption(maxParallelism))
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if len(result.Completed) != len(actions) {
+		t.Errorf("len(result.Completed) = %d, want %d", len(result.Completed), len(actions))
+	}
+	for name, want := range deps {
+		for _, w := range want {
+			if log.end[w] > log.start[name] {
+				t.Errorf("%s started (%d) before dependency %s ended (%d)", name, log.start[name], w, log.end[w])
+			}
+		}
+	}
+	if log.maxActive > maxParallelism {
+		t.Errorf("maxActive = %d, want <= %d", log.maxActive, maxParallelism)
+	}
+}
+
+func TestParallelExecutorConcurrent(t *testing.T) {
+	// Each Action waits for all of the others to start. This will only
+	// finish if the Actions are run concurrently.
+	const n = 4
+	var wg sync.WaitGroup
+	wg.Add(n)
+	allStarted := make(chan struct{})
+	go func() { wg.Wait(); close(allStarted) }()
+
+	var pending []Action
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("A%d", i)
+		pending = append(pending, &hookTestAction{
+			testAction: testAction{name: name, events: EventList{StringEvent(name)}},
+			hook: func() {
+				wg.Done()
+				select {
+				case <-allStarted:
+				case <-time.After(10 * time.Second):
+				}
+			},
+		})
+	}
+	ex, err := NewParallelExecutor(pending, MaxParallelismOption(n))
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(context.Background(), nil); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	select {
+	case <-allStarted:
+	default:
+		t.Errorf("Actions were not run concurrently")
+	}
+}
+
+func TestParallelExecutorStopOnError(t *testing.T) {
+	// X is running when B fails. X is allowed to finish but Y, which
+	// depends on X, must not be started.
+	failed := make(chan struct{})
+	sink := eventsink.FromRecorder(func(_, reason, _ string) {
+		if reason == eventsink.ReasonActionFailed {
+			close(failed)
+		}
+	})
+	b := &testAction{name: "B", events: EventList{StringEvent("B")}, err: errors.New("injected")}
+	x := &hookTestAction{
+		testAction: testAction{name: "X", events: EventList{StringEvent("X")}},
+		hook:       func() { <-failed },
+	}
+	y := &testAction{name: "Y", events: EventList{StringEvent("Y")}}
+	y.Want = EventList{StringEvent("X")}
+	c := &testAction{name: "C", events: EventList{StringEvent("C")}}
+	c.Want = EventList{StringEvent("B")}
+
+	ex, err := NewParallelExecutor([]Action{b, x, y, c}, EventSinkOption(sink))
+	if err != nil {
+		t.Fatalf("NewParallelExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+
+	var execErr *ExecError
+	if !errors.As(err, &execErr) || !execErr.Stopped {
+		t.Fatalf("Run() = %v, want stopped ExecError", err)
+	}
+	name := func(a Action) string { return a.Metadata().Name }
+	var got []string
+	for _, a := range result.Completed {
+		got = append(got, name(a))
+	}
+	if diff := cmp.Diff(got, []string{"X([X])"}); diff != "" {
+		t.Errorf("completed: diff -got,+want: %s", diff)
+	}
+	got = nil
+	for _, a := range result.Pending {
+		got = append(got, name(a))
+	}
+	sort.Strings(got)
+	if diff := cmp.Diff(got, []string{"C([C])", "Y([Y])"}); diff != "" {
+		t.Errorf("pending: diff -got,+want: %s", diff)
+	}
+}
@@ -18,6 +18,7 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -44,7 +45,14 @@ func NewSerialExecutor(pending []Action, opts ...Option) (*serialExecutor, error
 		}
 	} else {
 		ret.runFunc = func(ctx context.Context, c cloud.Cloud, a Action) (EventList, error) {
-			return a.Run(ctx, c)
+			if ret.progress.isDone(a) {
+				ret.config.logger(ctx).V(2).Info("Action done in a previous execution, skipping", actionLogValues(a)...)
+				return a.DryRun(), nil
+			}
+			if err := ret.config.checkStale(a); err != nil {
+				return nil, err
+			}
+			return runAction(ctx, c, a, ret.config.Adopt)
 		}
 	}
 
@@ -56,25 +64,51 @@ type serialExecutor struct {
 
 	runFunc func(context.Context, cloud.Cloud, Action) (EventList, error)
 	result  *Result
+	// progress is nil unless ProgressOption() is set.
+	progress *progressTracker
 }
 
 var _ Executor = (*serialExecutor)(nil)
 
 func (ex *serialExecutor) Run(ctx context.Context, c cloud.Cloud) (*Result, error) {
-	for a := ex.next(); a != nil; a = ex.next() {
+	obs := ex.config.Observers
+	if err := ex.loadProgress(ctx); err != nil {
+		return observePlanDone(obs, ex.result, err)
+	}
+	ctx, cancel := ex.config.withPlanTimeout(ctx)
+	defer cancel()
+
+	for {
+		if ctx.Err() != nil && len(ex.result.Pending) > 0 {
+			ex.config.logger(ctx).V(2).Info("Execution cancelled, skipping remaining Actions", "skipped", len(ex.result.Pending), "err", ctx.Err().Error())
+			ex.result.skipPending()
+			return observePlanDone(obs, ex.result, &ExecError{Result: ex.result, Stopped: true, Err: ctx.Err()})
+		}
+		a := ex.next()
+		if a == nil {
+			break
+		}
 		err := ex.runAction(ctx, c, a)
+		if errors.Is(err, errStopExecution) && ctx.Err() != nil {
+			// The Action failed due to the cancellation; the remaining
+			// Actions are skipped above.
+			continue
+		}
+		if errors.Is(err, errStopExecution) {
+			return observePlanDone(obs, ex.result, &ExecError{Result: ex.result, Stopped: true})
+		}
 		if err != nil {
-			return ex.result, err
+			return observePlanDone(obs, ex.result, err)
 		}
 	}
 	if ex.config.Tracer != nil {
 		ex.config.Tracer.Finish(ex.result.Pending)
 	}
 	if len(ex.result.Errors) > 0 {
-		return ex.result, fmt.Errorf("serialExecutor: errors in execution %v", ex.result.Errors)
+		return observePlanDone(obs, ex.result, &ExecError{Result: ex.result})
 	}
 
-	return ex.result, nil
+	return observePlanDone(obs, ex.result, nil)
 }
 
 func (ex *serialExecutor) runAction(ctx context.Context, c cloud.Cloud, a Action) error {
@@ -82,40 +116,40 @@ func (ex *serialExecutor) runAction(ctx context.Context, c cloud.Cloud, a Action
 		Action: a,
 		Start:  time.Now(),
 	}
-	events, runErr := ex.runFunc(ctx, c, a)
+	observeStart(ex.config.Observers, te)
+	events, runErr := runWithTimeout(ctx, ex.config.ActionTimeout, func(ctx context.Context) (EventList, error) {
+		return runWithSpan(ctx, ex.config.SpanStarter, a, func(ctx context.Context) (EventList, error) {
+			return ex.runFunc(ctx, c, a)
+		})
+	})
 	te.End = time.Now()
-
-	if runErr == nil {
-		ex.result.Completed = append(ex.result.Completed, a)
-	} else {
-		ex.result.Errors = append(ex.result.Errors, ActionWithErr{Action: a, Err: runErr})
-		switch ex.config.ErrorStrategy {
-		case ContinueOnError:
-		case StopOnError:
-			return fmt.Errorf("serialExecutor: stopping execution (got %v)", runErr)
-		default:
-			return fmt.Errorf("serialExecutor: invalid ErrorStrategy %q", ex.config.ErrorStrategy)
-		}
+	if err := ex.config.actionFinished(ctx, ex.result, ex.progress, te, events, runErr, ex.signal); err != nil {
+		return fmt.Errorf("serialExecutor: %w", err)
 	}
-	for _, ev := range events {
-		signaled := ex.signal(ev)
-		te.Signaled = append(te.Signaled, signaled...)
+	return nil
+}
+
+// loadProgress sets up the progressTracker if ProgressOption() is set.
+func (ex *serialExecutor) loadProgress(ctx context.Context) error {
+	if ex.config.DryRun {
+		return nil
 	}
-	if ex.config.Tracer != nil {
-		ex.config.Tracer.Record(te, runErr)
+	var err error
+	ex.progress, err = newProgressTracker(ctx, ex.config.Progress, ex.result.Pending)
+	if err != nil {
+		return fmt.Errorf("serialExecutor: %w", err)
 	}
-
 	return nil
 }
 
 func (ex *serialExecutor) next() Action {
-	for i, a := range ex.result.Pending {
-		if a.CanRun() {
-			ex.result.Pending = append(ex.result.Pending[0:i], ex.result.Pending[i+1:]...)
-			return a
-		}
+	i := nextRunnable(ex.result.Pending, nil, ex.config.OrderHints)
+	if i < 0 {
+		return nil
 	}
-	return nil
+	a := ex.result.Pending[i]
+	ex.result.Pending = append(ex.result.Pending[0:i], ex.result.Pending[i+1:]...)
+	return a
 }
 
 func (ex *serialExecutor) signal(ev Event) []TraceSignal {
@@ -21,6 +21,9 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/eventsink"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -150,7 +153,7 @@ func TestSerialExecutorErrorStrategy(t *testing.T) {
 			name:     "continue on error",
 			graph:    "A -> !B -> C -> D -> E",
 			strategy: ContinueOnError,
-			pending:  nil,
+			pending:  []string{"C", "D", "E"},
 			errs:     []string{"B"},
 			wantErr:  true,
 		},
@@ -183,3 +186,112 @@ func TestSerialExecutorErrorStrategy(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutorEventSink(t *testing.T) {
+	id := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(name)}
+	}
+	want := []string{
+		"Normal ActionCompleted: fakes:proj/A: A([A]) completed",
+		"Warning ActionFailed: fakes:proj/B: B([B]) failed: injected",
+	}
+
+	for _, tc := range []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			actions := actionsFromGraphStr("A -> !B")
+			for _, a := range actions {
+				ta := a.(*testAction)
+				ta.id = id(ta.name)
+			}
+			var sink eventsink.Recorder
+			ex, err := tc.new(actions, EventSinkOption(&sink))
+			if err != nil {
+				t.Fatalf("new() = %v, want nil", err)
+			}
+			ex.Run(context.Background(), nil)
+
+			var got []string
+			for _, ev := range sink.Events() {
+				if ev.ResourceID == nil {
+					t.Errorf("Event %q has no ResourceID", ev)
+				}
+				got = append(got, ev.String())
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("events: diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+// resumableTestAction records whether Resume() and Run() were called.
+type resumableTestAction struct {
+	testAction
+	done    bool
+	resumed bool
+	ran     bool
+}
+
+func (a *resumableTestAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
+	a.ran = true
+	return a.testAction.Run(ctx, c)
+}
+
+func (a *resumableTestAction) Resume(context.Context, cloud.Cloud, AdoptFunc) (EventList, bool, error) {
+	a.resumed = true
+	if !a.done {
+		return nil, false, nil
+	}
+	return a.events, true, nil
+}
+
+func TestSerialExecutorResume(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		resume      bool
+		done        bool
+		wantResumed bool
+		wantRan     bool
+	}{
+		{name: "already done", resume: true, done: true, wantResumed: true},
+		{name: "not done", resume: true, wantResumed: true, wantRan: true},
+		{name: "resume disabled by default", resume: false, done: true, wantRan: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &resumableTestAction{
+				testAction: testAction{name: "A", events: EventList{StringEvent("A")}},
+				done:       tc.done,
+			}
+			var opts []Option
+			if tc.resume {
+				opts = append(opts, ResumeOption(func(*cloud.ResourceID, any) bool { return true }))
+			}
+			ex, err := NewSerialExecutor([]Action{a}, opts...)
+			if err != nil {
+				t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+			}
+			result, err := ex.Run(context.Background(), nil)
+			if err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			if len(result.Completed) != 1 {
+				t.Errorf("result.Completed = %v, want [A]", result.Completed)
+			}
+			if a.resumed != tc.wantResumed || a.ran != tc.wantRan {
+				t.Errorf("resumed, ran = %t, %t; want %t, %t", a.resumed, a.ran, tc.wantResumed, tc.wantRan)
+			}
+		})
+	}
+}
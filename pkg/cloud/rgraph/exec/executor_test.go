@@ -17,8 +17,14 @@ limitations under the License.
 package exec
 
 import (
+	"context"
 	"errors"
+	"sort"
 	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // actionsFromGraphStr parses a graph in the form of "A -> B -> C; B -> D" to a
@@ -67,3 +73,89 @@ func actionsFromGraphStr(graphStr string) []Action {
 
 	return actions
 }
+
+// recordingTracer records the names of the traced Actions.
+type recordingTracer struct {
+	lock    sync.Mutex
+	entries []string
+}
+
+func (tr *recordingTracer) Record(entry *TraceEntry, err error) {
+	tr.lock.Lock()
+	defer tr.lock.Unlock()
+	name := entry.Action.(*testAction).name
+	if err != nil {
+		name += " (failed)"
+	}
+	tr.entries = append(tr.entries, name)
+}
+
+func (tr *recordingTracer) Finish([]Action) {}
+
+// TestExecutorErrorStrategy checks that the Executors handle errors in the
+// same way.
+func TestExecutorErrorStrategy(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		graph    string
+		strategy ErrorStrategy
+		pending  []string
+		errs     []string
+		traced   []string
+	}{
+		{
+			name:     "stop on error",
+			graph:    "A -> !B -> C",
+			strategy: StopOnError,
+			pending:  []string{"C"},
+			errs:     []string{"B"},
+			traced:   []string{"A", "B (failed)"},
+		},
+		{
+			name:     "continue on error",
+			graph:    "A -> !B -> C; A -> D",
+			strategy: ContinueOnError,
+			pending:  []string{"C"},
+			errs:     []string{"B"},
+			traced:   []string{"A", "B (failed)", "D"},
+		},
+	} {
+		for _, ex := range []struct {
+			name string
+			new  func([]Action, ...Option) (Executor, error)
+		}{
+			{
+				name: "serial",
+				new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+			},
+			{
+				name: "parallel",
+				new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+			},
+		} {
+			t.Run(tc.name+"/"+ex.name, func(t *testing.T) {
+				var tr recordingTracer
+				e, err := ex.new(actionsFromGraphStr(tc.graph), ErrorStrategyOption(tc.strategy), TracerOption(&tr))
+				if err != nil {
+					t.Fatalf("new() = %v, want nil", err)
+				}
+				result, err := e.Run(context.Background(), nil)
+				if err == nil {
+					t.Fatalf("Run() = nil, want error")
+				}
+				name := func(a Action) string { return a.(*testAction).name }
+				if diff := cmp.Diff(sortedStrings(result.Pending, name), tc.pending); diff != "" {
+					t.Errorf("pending: diff -got,+want: %s", diff)
+				}
+				errName := func(a ActionWithErr) string { return name(a.Action) }
+				if diff := cmp.Diff(sortedStrings(result.Errors, errName), tc.errs); diff != "" {
+					t.Errorf("errors: diff -got,+want: %s", diff)
+				}
+				sort.Strings(tr.entries)
+				if diff := cmp.Diff(tr.entries, tc.traced); diff != "" {
+					t.Errorf("traced: diff -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
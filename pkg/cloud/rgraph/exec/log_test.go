@@ -0,0 +1,127 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/go-logr/logr"
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordingSink records the messages and key/value pairs (except for
+// "duration") logged at verbosity <= 2.
+type recordingSink struct {
+	lock   *sync.Mutex
+	lines  *[]string
+	values []any
+}
+
+func newRecordingLogger() (logr.Logger, *recordingSink) {
+	s := &recordingSink{lock: &sync.Mutex{}, lines: &[]string{}}
+	return logr.New(s), s
+}
+
+func (s *recordingSink) record(msg string, kv []any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	kv = append(append([]any{}, s.values...), kv...)
+	var out []string
+	for i := 0; i+1 < len(kv); i += 2 {
+		if kv[i] == "duration" {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%v=%v", kv[i], kv[i+1]))
+	}
+	*s.lines = append(*s.lines, fmt.Sprintf("%s %v", msg, out))
+}
+
+func (s *recordingSink) Init(logr.RuntimeInfo)  {}
+func (s *recordingSink) Enabled(level int) bool { return level <= 2 }
+func (s *recordingSink) Info(_ int, msg string, kv ...any) {
+	s.record(msg, kv)
+}
+func (s *recordingSink) Error(err error, msg string, kv ...any) {
+	s.record(fmt.Sprintf("%s: %v", msg, err), kv)
+}
+func (s *recordingSink) WithValues(kv ...any) logr.LogSink {
+	ret := *s
+	ret.values = append(append([]any{}, s.values...), kv...)
+	return &ret
+}
+func (s *recordingSink) WithName(string) logr.LogSink { return s }
+
+func TestLogger(t *testing.T) {
+	actions := func() []Action {
+		actions := actionsFromGraphStr("A -> !B -> C")
+		for _, a := range actions {
+			ta := a.(*testAction)
+			ta.id = &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(ta.name)}
+		}
+		return actions
+	}
+	want := []string{
+		"Action done [attempt=1 node=fakes:proj/A op=Custom]",
+		"Action failed: injected [attempt=1 node=fakes:proj/B op=Custom]",
+	}
+
+	for _, tc := range []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	} {
+		t.Run(tc.name+"/option", func(t *testing.T) {
+			l, sink := newRecordingLogger()
+			ex, err := tc.new(actions(), LoggerOption(l.WithValues(rlog.KeyAttempt, 1)))
+			if err != nil {
+				t.Fatalf("new executor = %v, want nil", err)
+			}
+			ex.Run(context.Background(), nil)
+			sort.Strings(*sink.lines)
+			if diff := cmp.Diff(*sink.lines, want); diff != "" {
+				t.Errorf("log; -got,+want: %s", diff)
+			}
+		})
+		t.Run(tc.name+"/context", func(t *testing.T) {
+			l, sink := newRecordingLogger()
+			ex, err := tc.new(actions())
+			if err != nil {
+				t.Fatalf("new executor = %v, want nil", err)
+			}
+			ex.Run(rlog.NewContext(context.Background(), l.WithValues(rlog.KeyAttempt, 1)), nil)
+			sort.Strings(*sink.lines)
+			if diff := cmp.Diff(*sink.lines, want); diff != "" {
+				t.Errorf("log; -got,+want: %s", diff)
+			}
+		})
+	}
+}
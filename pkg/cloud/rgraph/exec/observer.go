@@ -0,0 +1,118 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+)
+
+// Observer is notified of the progress of the execution. This can be used to
+// emit logs, metrics and Kubernetes Events. The methods are called from the
+// goroutine calling Executor.Run() and are never called concurrently.
+// Observers are also called in DryRun mode.
+type Observer interface {
+	// OnNodeStart is called before the Action is run. ObservedAction.End
+	// is zero.
+	OnNodeStart(ObservedAction)
+	// OnNodeDone is called after the Action has completed without error.
+	OnNodeDone(ObservedAction)
+	// OnNodeError is called after the Action returned an error.
+	OnNodeError(ObservedAction, error)
+	// OnPlanDone is called when the execution is finished with the Result
+	// and error returned by Executor.Run().
+	OnPlanDone(*Result, error)
+}
+
+// ObservedAction is an Action being executed.
+type ObservedAction struct {
+	Action Action
+	// ID of the resource affected by the Action. This is nil if the Action
+	// is not for a single resource (see ActionMetadata.ResourceID).
+	ID *cloud.ResourceID
+	// Operation is the type of the Action.
+	Operation ActionType
+	// Start and End of the execution of the Action.
+	Start time.Time
+	End   time.Time
+}
+
+// Duration of the execution of the Action.
+func (o ObservedAction) Duration() time.Duration {
+	if o.End.IsZero() {
+		return 0
+	}
+	return o.End.Sub(o.Start)
+}
+
+// BaseObserver is an Observer that does nothing. This can be embedded to
+// reduce verbosity when creating a custom Observer.
+type BaseObserver struct{}
+
+// Implements Observer.
+func (BaseObserver) OnNodeStart(ObservedAction)        {}
+func (BaseObserver) OnNodeDone(ObservedAction)         {}
+func (BaseObserver) OnNodeError(ObservedAction, error) {}
+func (BaseObserver) OnPlanDone(*Result, error)         {}
+
+// observedAction returns the ObservedAction for te.
+func observedAction(te *TraceEntry) ObservedAction {
+	md := te.Action.Metadata()
+	return ObservedAction{
+		Action:    te.Action,
+		ID:        md.ResourceID,
+		Operation: md.Type,
+		Start:     te.Start,
+		End:       te.End,
+	}
+}
+
+// observeStart calls OnNodeStart for all of the observers.
+func observeStart(observers []Observer, te *TraceEntry) {
+	if len(observers) == 0 {
+		return
+	}
+	oa := observedAction(te)
+	for _, o := range observers {
+		o.OnNodeStart(oa)
+	}
+}
+
+// observeEnd calls OnNodeDone or OnNodeError for all of the observers.
+func observeEnd(observers []Observer, te *TraceEntry, err error) {
+	if len(observers) == 0 {
+		return
+	}
+	oa := observedAction(te)
+	for _, o := range observers {
+		if err != nil {
+			o.OnNodeError(oa, err)
+		} else {
+			o.OnNodeDone(oa)
+		}
+	}
+}
+
+// observePlanDone calls OnPlanDone for all of the observers and returns
+// result, err.
+func observePlanDone(observers []Observer, result *Result, err error) (*Result, error) {
+	for _, o := range observers {
+		o.OnPlanDone(result, err)
+	}
+	return result, err
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+type recordingObserver struct {
+	BaseObserver
+	calls []string
+}
+
+func (o *recordingObserver) OnNodeStart(oa ObservedAction) {
+	if !oa.End.IsZero() {
+		o.calls = append(o.calls, "invalid End for OnNodeStart")
+	}
+	o.calls = append(o.calls, fmt.Sprintf("start %v %s", oa.ID, oa.Operation))
+}
+
+func (o *recordingObserver) OnNodeDone(oa ObservedAction) {
+	if oa.Duration() < 0 {
+		o.calls = append(o.calls, "invalid Duration")
+	}
+	o.calls = append(o.calls, fmt.Sprintf("done %v", oa.ID))
+}
+
+func (o *recordingObserver) OnNodeError(oa ObservedAction, err error) {
+	o.calls = append(o.calls, fmt.Sprintf("error %v: %v", oa.ID, err))
+}
+
+func (o *recordingObserver) OnPlanDone(r *Result, err error) {
+	o.calls = append(o.calls, fmt.Sprintf("plan done %+v, err=%t", r.Counts(), err != nil))
+}
+
+func TestObserver(t *testing.T) {
+	id := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(name)}
+	}
+	actions := func() []Action {
+		actions := actionsFromGraphStr("A -> !B -> C")
+		for _, a := range actions {
+			ta := a.(*testAction)
+			ta.id = id(ta.name)
+		}
+		return actions
+	}
+	want := []string{
+		"start fakes:proj/A Custom",
+		"done fakes:proj/A",
+		"start fakes:proj/B Custom",
+		"error fakes:proj/B: injected",
+		"plan done {Completed:1 Errors:1 Pending:1 Skipped:0}, err=true",
+	}
+
+	for _, tc := range []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var o1, o2 recordingObserver
+			ex, err := tc.new(actions(), ObserverOption(&o1), ObserverOption(&o2))
+			if err != nil {
+				t.Fatalf("new executor = %v, want nil", err)
+			}
+			if _, err := ex.Run(context.Background(), nil); err == nil {
+				t.Fatal("Run() = nil, want error")
+			}
+			for _, o := range []*recordingObserver{&o1, &o2} {
+				if diff := cmp.Diff(o.calls, want); diff != "" {
+					t.Errorf("calls: diff -got,+want: %s", diff)
+				}
+			}
+		})
+	}
+}
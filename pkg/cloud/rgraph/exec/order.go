@@ -0,0 +1,123 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import "fmt"
+
+// OrderHint orders the Actions matching Before ahead of the other Actions
+// matching After, in addition to the ordering given by the Events. This is
+// used for constraints of the Cloud that are not expressed as references
+// between the resources, e.g. "create all health checks before any backend
+// service" or "delete forwarding rules first" (After matches all Actions).
+//
+// Hints are best effort: an Action is started in spite of a hint if no other
+// Action can be run, e.g. when the Before Actions are waiting on Events from
+// the After Actions.
+type OrderHint struct {
+	Before ActionMatcher
+	After  ActionMatcher
+}
+
+func (h OrderHint) String() string {
+	return fmt.Sprintf("%v before %v", h.Before, h.After)
+}
+
+// ActionMatcher selects Actions by their ActionMetadata. Empty fields match
+// all values.
+type ActionMatcher struct {
+	// Type of the Action, e.g. ActionTypeCreate.
+	Type ActionType
+	// Resource is the type of resource of the Action (ResourceID.Resource),
+	// e.g. "healthChecks".
+	Resource string
+}
+
+// Matches returns true if the Action with metadata md is selected.
+func (m ActionMatcher) Matches(md *ActionMetadata) bool {
+	if m.Type != "" && m.Type != md.Type {
+		return false
+	}
+	if m.Resource != "" && (md.ResourceID == nil || md.ResourceID.Resource != m.Resource) {
+		return false
+	}
+	return true
+}
+
+func (m ActionMatcher) String() string {
+	t, r := string(m.Type), m.Resource
+	if t == "" {
+		t = "*"
+	}
+	if r == "" {
+		r = "*"
+	}
+	return t + "/" + r
+}
+
+// OrderHintsOption adds OrderHints to the execution. This option can be
+// given multiple times.
+func OrderHintsOption(hints ...OrderHint) Option {
+	return func(c *ExecutorConfig) { c.OrderHints = append(c.OrderHints, hints...) }
+}
+
+// nextRunnable returns the index of the next Action in pending to run or -1
+// if there is none. The first runnable Action that is not held back by one of
+// the hints is returned. running are the Actions that are currently running.
+//
+// If all of the runnable Actions are held back, the first one is returned if
+// nothing is running; otherwise -1 is returned to wait for the running
+// Actions to complete.
+func nextRunnable(pending, running []Action, hints []OrderHint) int {
+	first := -1
+	for i, a := range pending {
+		if !a.CanRun() {
+			continue
+		}
+		if len(hints) == 0 {
+			return i
+		}
+		if first == -1 {
+			first = i
+		}
+		if !heldBack(a, pending, running, hints) {
+			return i
+		}
+	}
+	if len(running) > 0 {
+		return -1
+	}
+	return first
+}
+
+// heldBack returns true if a matches the After of a hint and there are other
+// Actions matching the Before that have not completed.
+func heldBack(a Action, pending, running []Action, hints []OrderHint) bool {
+	md := a.Metadata()
+	for _, h := range hints {
+		if !h.After.Matches(md) || h.Before.Matches(md) {
+			continue
+		}
+		for _, l := range [][]Action{pending, running} {
+			for _, b := range l {
+				if b != a && h.Before.Matches(b.Metadata()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
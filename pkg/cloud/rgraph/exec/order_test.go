@@ -0,0 +1,173 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+// typedAction is a testAction with an ActionType and resource.
+type typedAction struct {
+	*testAction
+	typ ActionType
+}
+
+func (a *typedAction) Metadata() *ActionMetadata {
+	md := a.testAction.Metadata()
+	md.Name = a.name
+	md.Type = a.typ
+	return md
+}
+
+// newTypedAction returns an Action for the resource named name that waits
+// for the Events of the Actions named in want.
+func newTypedAction(typ ActionType, resource, name string, want ...string) Action {
+	a := &testAction{
+		name:   name,
+		events: EventList{StringEvent(name)},
+		id:     &cloud.ResourceID{Resource: resource, ProjectID: "proj", Key: meta.GlobalKey(name)},
+	}
+	for _, w := range want {
+		a.Want = append(a.Want, StringEvent(w))
+	}
+	return &typedAction{testAction: a, typ: typ}
+}
+
+func TestOrderHints(t *testing.T) {
+	hcFirst := OrderHint{
+		Before: ActionMatcher{Type: ActionTypeCreate, Resource: "healthChecks"},
+		After:  ActionMatcher{Type: ActionTypeCreate, Resource: "backendServices"},
+	}
+	frFirst := OrderHint{
+		Before: ActionMatcher{Type: ActionTypeDelete, Resource: "forwardingRules"},
+	}
+
+	for _, tc := range []struct {
+		name    string
+		actions func() []Action
+		hints   []OrderHint
+		want    []string
+	}{
+		{
+			name: "no hints",
+			actions: func() []Action {
+				return []Action{
+					newTypedAction(ActionTypeCreate, "backendServices", "bs"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc1"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc2"),
+				}
+			},
+			want: []string{"bs", "hc1", "hc2"},
+		},
+		{
+			name: "before",
+			actions: func() []Action {
+				return []Action{
+					newTypedAction(ActionTypeCreate, "backendServices", "bs"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc1"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc2"),
+				}
+			},
+			hints: []OrderHint{hcFirst},
+			want:  []string{"hc1", "hc2", "bs"},
+		},
+		{
+			name: "hint does not apply to other types",
+			actions: func() []Action {
+				return []Action{
+					newTypedAction(ActionTypeDelete, "backendServices", "bs"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc"),
+				}
+			},
+			hints: []OrderHint{hcFirst},
+			want:  []string{"bs", "hc"},
+		},
+		{
+			name: "events take precedence",
+			actions: func() []Action {
+				return []Action{
+					newTypedAction(ActionTypeCreate, "backendServices", "bs"),
+					newTypedAction(ActionTypeCreate, "healthChecks", "hc", "bs"),
+				}
+			},
+			hints: []OrderHint{hcFirst},
+			want:  []string{"bs", "hc"},
+		},
+		{
+			name: "first",
+			actions: func() []Action {
+				return []Action{
+					newTypedAction(ActionTypeDelete, "backendServices", "bs"),
+					newTypedAction(ActionTypeDelete, "targetHttpProxies", "tp"),
+					newTypedAction(ActionTypeDelete, "forwardingRules", "fr1"),
+					newTypedAction(ActionTypeDelete, "forwardingRules", "fr2"),
+				}
+			},
+			hints: []OrderHint{frFirst},
+			want:  []string{"fr1", "fr2", "bs", "tp"},
+		},
+	} {
+		for _, ex := range []struct {
+			name string
+			new  func([]Action, ...Option) (Executor, error)
+		}{
+			{"serial", func(a []Action, o ...Option) (Executor, error) { return NewSerialExecutor(a, o...) }},
+			{"parallel", func(a []Action, o ...Option) (Executor, error) {
+				return NewParallelExecutor(a, append(o, MaxParallelismOption(1))...)
+			}},
+		} {
+			t.Run(tc.name+"/"+ex.name, func(t *testing.T) {
+				e, err := ex.new(tc.actions(), OrderHintsOption(tc.hints...))
+				if err != nil {
+					t.Fatalf("new() = %v", err)
+				}
+				result, err := e.Run(context.Background(), nil)
+				if err != nil {
+					t.Fatalf("Run() = %v, want nil", err)
+				}
+				var got []string
+				for _, a := range result.Completed {
+					got = append(got, a.Metadata().Name)
+				}
+				if diff := cmp.Diff(got, tc.want); diff != "" {
+					t.Errorf("Completed; -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestNextRunnableWaitsForRunning(t *testing.T) {
+	hint := OrderHint{
+		Before: ActionMatcher{Resource: "healthChecks"},
+		After:  ActionMatcher{Resource: "backendServices"},
+	}
+	pending := []Action{newTypedAction(ActionTypeCreate, "backendServices", "bs")}
+	running := []Action{newTypedAction(ActionTypeCreate, "healthChecks", "hc")}
+
+	if got := nextRunnable(pending, running, []OrderHint{hint}); got != -1 {
+		t.Errorf("nextRunnable() = %d, want -1 (wait for the running Action)", got)
+	}
+	if got := nextRunnable(pending, nil, []OrderHint{hint}); got != 0 {
+		t.Errorf("nextRunnable() = %d, want 0", got)
+	}
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ActionStatus is the persisted execution status of an Action.
+type ActionStatus string
+
+const (
+	// ActionPending has not been run or was interrupted while running.
+	ActionPending ActionStatus = "Pending"
+	// ActionDone completed without error.
+	ActionDone ActionStatus = "Done"
+	// ActionFailed returned an error.
+	ActionFailed ActionStatus = "Failed"
+)
+
+// Progress of an execution. This is the status of each Action, keyed by
+// ActionMetadata.Name.
+type Progress struct {
+	Actions map[string]ActionStatus `json:"actions"`
+}
+
+// ProgressStore persists the Progress of an execution so that it can be
+// resumed if the process restarts (see ProgressOption()).
+type ProgressStore interface {
+	// Load the saved Progress. Returns an empty Progress if nothing has
+	// been saved.
+	Load(context.Context) (*Progress, error)
+	// Save the Progress, replacing the previous value.
+	Save(context.Context, *Progress) error
+}
+
+// ProgressOption persists the status of the Actions to s.
+//
+// At the start of Run(), Actions that are Done in the saved Progress are not
+// run again: their Events (see Action.DryRun()) are signaled and they are
+// reported in Result.Completed. Failed and Pending Actions are run as usual.
+// The Progress is saved after each Action finishes; an error saving the
+// Progress stops the execution. The store is not used with DryRunOption.
+//
+// The Actions must be the same as in the execution that saved the Progress,
+// e.g. computed from the plan.Result that was saved with it.
+func ProgressOption(s ProgressStore) Option {
+	return func(c *ExecutorConfig) { c.Progress = s }
+}
+
+// FileProgressStore saves the Progress as JSON to the file Path. The file is
+// replaced atomically on each Save().
+type FileProgressStore struct {
+	Path string
+}
+
+var _ ProgressStore = (*FileProgressStore)(nil)
+
+// Load implements ProgressStore.
+func (s *FileProgressStore) Load(context.Context) (*Progress, error) {
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Progress{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FileProgressStore: %w", err)
+	}
+	var ret Progress
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, fmt.Errorf("FileProgressStore: %s: %w", s.Path, err)
+	}
+	return &ret, nil
+}
+
+// Save implements ProgressStore.
+func (s *FileProgressStore) Save(_ context.Context, p *Progress) error {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("FileProgressStore: %w", err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("FileProgressStore: %w", err)
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), s.Path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("FileProgressStore: %w", err)
+	}
+	return nil
+}
+
+// progressTracker updates and saves the Progress for an execution. A nil
+// progressTracker does nothing.
+type progressTracker struct {
+	store    ProgressStore
+	progress *Progress
+	// done are the Actions that were Done when the execution started. This
+	// is read by the worker goroutines and is not modified after
+	// newProgressTracker().
+	done map[string]bool
+}
+
+// newProgressTracker loads the Progress from the store and saves the status
+// of the pending Actions. Returns nil if store is nil.
+func newProgressTracker(ctx context.Context, store ProgressStore, pending []Action) (*progressTracker, error) {
+	if store == nil {
+		return nil, nil
+	}
+	loaded, err := store.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("progress: %w", err)
+	}
+	t := &progressTracker{
+		store:    store,
+		progress: &Progress{Actions: map[string]ActionStatus{}},
+		done:     map[string]bool{},
+	}
+	for _, a := range pending {
+		name := a.Metadata().Name
+		if loaded.Actions[name] == ActionDone {
+			t.done[name] = true
+			t.progress.Actions[name] = ActionDone
+		} else {
+			t.progress.Actions[name] = ActionPending
+		}
+	}
+	if err := store.Save(ctx, t.progress); err != nil {
+		return nil, fmt.Errorf("progress: %w", err)
+	}
+	return t, nil
+}
+
+// isDone returns true if a was Done in a previous execution.
+func (t *progressTracker) isDone(a Action) bool {
+	if t == nil {
+		return false
+	}
+	return t.done[a.Metadata().Name]
+}
+
+// record the result of running a and save the Progress.
+func (t *progressTracker) record(ctx context.Context, a Action, runErr error) error {
+	if t == nil {
+		return nil
+	}
+	status := ActionDone
+	if runErr != nil {
+		status = ActionFailed
+	}
+	t.progress.Actions[a.Metadata().Name] = status
+	if err := t.store.Save(ctx, t.progress); err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProgress(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := &FileProgressStore{Path: filepath.Join(t.TempDir(), "progress.json")}
+
+			// The first execution fails at B.
+			ex, err := tc.new(actionsFromGraphStr("A -> !B -> C"), ProgressOption(store))
+			if err != nil {
+				t.Fatalf("new() = %v, want nil", err)
+			}
+			if _, err := ex.Run(ctx, nil); err == nil {
+				t.Fatal("Run() = nil, want error")
+			}
+			p, err := store.Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() = %v, want nil", err)
+			}
+			want := map[string]ActionStatus{
+				"A([A])": ActionDone,
+				"B([B])": ActionFailed,
+				"C([C])": ActionPending,
+			}
+			if diff := cmp.Diff(p.Actions, want); diff != "" {
+				t.Errorf("Progress: diff -got,+want: %s", diff)
+			}
+
+			// The second execution does not run A again; it would fail if
+			// it did.
+			ex, err = tc.new(actionsFromGraphStr("!A -> B -> C"), ProgressOption(store))
+			if err != nil {
+				t.Fatalf("new() = %v, want nil", err)
+			}
+			result, err := ex.Run(ctx, nil)
+			if err != nil {
+				t.Fatalf("Run() = %v, want nil", err)
+			}
+			got := sortedStrings(result.Completed, func(a Action) string { return a.Metadata().Name })
+			if diff := cmp.Diff(got, []string{"A([A])", "B([B])", "C([C])"}); diff != "" {
+				t.Errorf("Completed: diff -got,+want: %s", diff)
+			}
+			p, err = store.Load(ctx)
+			if err != nil {
+				t.Fatalf("Load() = %v, want nil", err)
+			}
+			for name, status := range p.Actions {
+				if status != ActionDone {
+					t.Errorf("Progress.Actions[%q] = %s, want %s", name, status, ActionDone)
+				}
+			}
+		})
+	}
+}
+
+type failingProgressStore struct{ saves int }
+
+func (s *failingProgressStore) Load(context.Context) (*Progress, error) { return &Progress{}, nil }
+
+func (s *failingProgressStore) Save(context.Context, *Progress) error {
+	s.saves++
+	// Allow the initial Save() before any Action is run.
+	if s.saves > 1 {
+		return errors.New("injected")
+	}
+	return nil
+}
+
+func TestProgressSaveError(t *testing.T) {
+	ex, err := NewSerialExecutor(actionsFromGraphStr("A -> B"), ProgressOption(&failingProgressStore{}))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+	if err == nil {
+		t.Fatal("Run() = nil, want error")
+	}
+	if len(result.Pending) != 1 {
+		t.Errorf("len(result.Pending) = %d, want 1", len(result.Pending))
+	}
+}
+
+func TestFileProgressStoreMissing(t *testing.T) {
+	store := &FileProgressStore{Path: filepath.Join(t.TempDir(), "missing.json")}
+	p, err := store.Load(context.Background())
+	if err != nil {
+		t.Fatalf("Load() = %v, want nil", err)
+	}
+	if len(p.Actions) != 0 {
+		t.Errorf("Load() = %+v, want empty", p)
+	}
+}
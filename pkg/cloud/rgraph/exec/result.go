@@ -0,0 +1,178 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Result of the execution.
+type Result struct {
+	// Completed Actions with no errors.
+	Completed []Action
+	// Errors are Actions that failed with an error.
+	Errors []ActionWithErr
+	// Pending are Actions that could not be executed due to missing
+	// preconditions.
+	Pending []Action
+	// Skipped are Actions that were not run because the context of the
+	// execution was done, e.g. the PlanTimeoutOption() was exceeded.
+	Skipped []Action
+}
+
+// Outcome of an Action in the execution.
+type Outcome string
+
+const (
+	// OutcomeCompleted means the Action ran without error.
+	OutcomeCompleted Outcome = "Completed"
+	// OutcomeFailed means the Action returned an error.
+	OutcomeFailed Outcome = "Failed"
+	// OutcomePending means the Action was not run.
+	OutcomePending Outcome = "Pending"
+	// OutcomeSkipped means the Action was not run because the execution
+	// was cancelled.
+	OutcomeSkipped Outcome = "Skipped"
+)
+
+// Outcomes returns the Outcome for each Action, indexed by the
+// ActionMetadata.Name.
+func (r *Result) Outcomes() map[string]Outcome {
+	ret := map[string]Outcome{}
+	for _, a := range r.Completed {
+		ret[a.Metadata().Name] = OutcomeCompleted
+	}
+	for _, ae := range r.Errors {
+		ret[ae.Action.Metadata().Name] = OutcomeFailed
+	}
+	for _, a := range r.Pending {
+		ret[a.Metadata().Name] = OutcomePending
+	}
+	for _, a := range r.Skipped {
+		ret[a.Metadata().Name] = OutcomeSkipped
+	}
+	return ret
+}
+
+// ResultCounts are the number of Actions with each Outcome.
+type ResultCounts struct {
+	Completed int
+	Errors    int
+	Pending   int
+	Skipped   int
+}
+
+// Counts of the Actions in the Result.
+func (r *Result) Counts() ResultCounts {
+	return ResultCounts{
+		Completed: len(r.Completed),
+		Errors:    len(r.Errors),
+		Pending:   len(r.Pending),
+		Skipped:   len(r.Skipped),
+	}
+}
+
+// skipPending moves the Pending Actions to Skipped.
+func (r *Result) skipPending() {
+	r.Skipped = append(r.Skipped, r.Pending...)
+	r.Pending = nil
+}
+
+// GroupErrors groups the failed Actions by the cause returned by classify.
+// Example: classify could return "quota" for quota errors so that only these
+// Actions are retried.
+func (r *Result) GroupErrors(classify func(error) string) map[string][]ActionWithErr {
+	ret := map[string][]ActionWithErr{}
+	for _, ae := range r.Errors {
+		cause := classify(ae.Err)
+		ret[cause] = append(ret[cause], ae)
+	}
+	return ret
+}
+
+// ActionWithErr is an Action that failed with Err. ActionWithErr is an error
+// that wraps Err.
+type ActionWithErr struct {
+	Action Action
+	Err    error
+}
+
+// Error implements error.
+func (e ActionWithErr) Error() string {
+	return fmt.Sprintf("%s: %v", e.Action.Metadata().Name, e.Err)
+}
+
+// Unwrap returns the error from the Action.
+func (e ActionWithErr) Unwrap() error { return e.Err }
+
+// ExecError is returned from Executor.Run() when one or more Actions failed.
+// errors.Is() and errors.As() will match against the errors returned by the
+// Actions, e.g.:
+//
+//	var apiErr *googleapi.Error
+//	if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests { ... }
+type ExecError struct {
+	// Result of the execution.
+	Result *Result
+	// Stopped is true if the execution was stopped early due to
+	// StopOnError or because the context was done.
+	Stopped bool
+	// Err is the error from the context if the execution was stopped
+	// because the context was done (e.g. context.DeadlineExceeded).
+	Err error
+}
+
+// Error implements error.
+func (e *ExecError) Error() string {
+	c := e.Result.Counts()
+	var msgs []string
+	for _, ae := range e.Result.Errors {
+		msgs = append(msgs, ae.Error())
+	}
+	sort.Strings(msgs)
+	var stopped string
+	if c.Skipped > 0 {
+		stopped += fmt.Sprintf(", %d skipped", c.Skipped)
+	}
+	if e.Stopped {
+		stopped += ", stopped early"
+	}
+	if e.Err != nil {
+		stopped += fmt.Sprintf(": %v", e.Err)
+	}
+	return fmt.Sprintf("exec: %d actions failed (%d completed, %d pending%s): %s",
+		c.Errors, c.Completed, c.Pending, stopped, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the errors of the failed Actions, as ActionWithErr, and
+// Err if set.
+func (e *ExecError) Unwrap() []error {
+	var ret []error
+	if e.Err != nil {
+		ret = append(ret, e.Err)
+	}
+	for _, ae := range e.Result.Errors {
+		ret = append(ret, ae)
+	}
+	return ret
+}
+
+// errStopExecution is returned internally when execution is to be stopped.
+var errStopExecution = errors.New("stop execution")
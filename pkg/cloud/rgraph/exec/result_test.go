@@ -0,0 +1,108 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/googleapi"
+)
+
+var errTestSentinel = errors.New("sentinel")
+
+func TestExecError(t *testing.T) {
+	actions := actionsFromGraphStr("A -> !B; C -> !D; E -> F")
+	for _, a := range actions {
+		switch ta := a.(*testAction); ta.name {
+		case "B":
+			ta.err = &googleapi.Error{Code: http.StatusTooManyRequests}
+		case "D":
+			ta.err = errTestSentinel
+		}
+	}
+	ex, err := NewSerialExecutor(actions, ErrorStrategyOption(ContinueOnError))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	result, err := ex.Run(context.Background(), nil)
+
+	var execErr *ExecError
+	if !errors.As(err, &execErr) {
+		t.Fatalf("Run() = %v; want *ExecError", err)
+	}
+	if execErr.Stopped || execErr.Result != result {
+		t.Errorf("ExecError = %+v, want Stopped = false and Result = result", execErr)
+	}
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusTooManyRequests {
+		t.Errorf("errors.As(err, *googleapi.Error) = %v, want Code %d", apiErr, http.StatusTooManyRequests)
+	}
+	if !errors.Is(err, errTestSentinel) {
+		t.Errorf("errors.Is(err, errTestSentinel) = false, want true")
+	}
+	var ae ActionWithErr
+	if !errors.As(err, &ae) {
+		t.Errorf("errors.As(err, ActionWithErr) = false, want true")
+	}
+
+	if got, want := result.Counts(), (ResultCounts{Completed: 4, Errors: 2}); got != want {
+		t.Errorf("Counts() = %+v, want %+v", got, want)
+	}
+	groups := result.GroupErrors(func(err error) string {
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusTooManyRequests {
+			return "quota"
+		}
+		return "other"
+	})
+	if len(groups["quota"]) != 1 || groups["quota"][0].Action.(*testAction).name != "B" {
+		t.Errorf("GroupErrors()[quota] = %v, want [B]", groups["quota"])
+	}
+	if len(groups["other"]) != 1 || groups["other"][0].Action.(*testAction).name != "D" {
+		t.Errorf("GroupErrors()[other] = %v, want [D]", groups["other"])
+	}
+
+	wantOutcomes := map[string]Outcome{
+		"A([A])": OutcomeCompleted,
+		"B([B])": OutcomeFailed,
+		"C([C])": OutcomeCompleted,
+		"D([D])": OutcomeFailed,
+		"E([E])": OutcomeCompleted,
+		"F([F])": OutcomeCompleted,
+	}
+	if diff := cmp.Diff(result.Outcomes(), wantOutcomes); diff != "" {
+		t.Errorf("Outcomes(): -got,+want: %s", diff)
+	}
+}
+
+func TestExecErrorStopped(t *testing.T) {
+	ex, err := NewSerialExecutor(actionsFromGraphStr("!A -> B"), ErrorStrategyOption(StopOnError))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	_, err = ex.Run(context.Background(), nil)
+	var execErr *ExecError
+	if !errors.As(err, &execErr) || !execErr.Stopped {
+		t.Fatalf("Run() = %v; want *ExecError with Stopped = true", err)
+	}
+	if got := execErr.Result.Outcomes()["B([B])"]; got != OutcomePending {
+		t.Errorf("Outcomes()[B] = %v, want %v", got, OutcomePending)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import "context"
+
+// Span attribute keys set by the Executor.
+const (
+	// SpanAttrResourceID is the ResourceID of the resource affected by the
+	// Action.
+	SpanAttrResourceID = "rgraph.resource_id"
+	// SpanAttrOperation is the ActionType of the Action.
+	SpanAttrOperation = "rgraph.operation"
+	// SpanAttrVersion is the API version used by the Action. This is only
+	// set for Actions that implement CallDescriber.
+	SpanAttrVersion = "rgraph.version"
+)
+
+// Results of a Span.
+const (
+	SpanResultOK    = "ok"
+	SpanResultError = "error"
+)
+
+// SpanAttribute is a key/value attribute of a Span.
+type SpanAttribute struct {
+	Key   string
+	Value string
+}
+
+// Span for the execution of an Action.
+type Span interface {
+	// End the Span. result is one of SpanResultOK or SpanResultError;
+	// err is the error returned by the Action.
+	End(result string, err error)
+}
+
+// SpanStarter creates a tracing span for each Action that is run. This is
+// used to integrate with a tracing system such as OpenTelemetry without this
+// package depending on it, e.g.:
+//
+//	type otelSpans struct{ tracer trace.Tracer }
+//
+//	func (o otelSpans) StartSpan(ctx context.Context, name string, attrs []exec.SpanAttribute) (context.Context, exec.Span) {
+//		ctx, span := o.tracer.Start(ctx, name)
+//		for _, a := range attrs {
+//			span.SetAttributes(attribute.String(a.Key, a.Value))
+//		}
+//		return ctx, otelSpan{span}
+//	}
+//
+// Actions that only signal Events (ActionTypeMeta) do not have a Span.
+type SpanStarter interface {
+	// StartSpan starts a Span as a child of the span in ctx (i.e. the
+	// span of the plan execution from the caller of Executor.Run()). The
+	// returned context is passed to the Action so that the Cloud calls it
+	// makes are children of the Span. StartSpan may be called
+	// concurrently by the parallel Executor.
+	StartSpan(ctx context.Context, name string, attrs []SpanAttribute) (context.Context, Span)
+}
+
+// SpanStarterOption creates a Span with s for each Action that is run.
+func SpanStarterOption(s SpanStarter) Option {
+	return func(c *ExecutorConfig) { c.SpanStarter = s }
+}
+
+// spanAttributes returns the attributes of the Span for a.
+func spanAttributes(a Action) []SpanAttribute {
+	md := a.Metadata()
+	var ret []SpanAttribute
+	if md.ResourceID != nil {
+		ret = append(ret, SpanAttribute{Key: SpanAttrResourceID, Value: md.ResourceID.String()})
+	}
+	ret = append(ret, SpanAttribute{Key: SpanAttrOperation, Value: string(md.Type)})
+	if v := actionVersion(a); v != "" {
+		ret = append(ret, SpanAttribute{Key: SpanAttrVersion, Value: v})
+	}
+	return ret
+}
+
+// runWithSpan calls run for a inside of a Span created by s. s may be nil.
+func runWithSpan(ctx context.Context, s SpanStarter, a Action, run func(context.Context) (EventList, error)) (EventList, error) {
+	md := a.Metadata()
+	if s == nil || md.Type == ActionTypeMeta {
+		return run(ctx)
+	}
+	ctx, span := s.StartSpan(ctx, md.Name, spanAttributes(a))
+	events, err := run(ctx)
+	if err != nil {
+		span.End(SpanResultError, err)
+	} else {
+		span.End(SpanResultOK, nil)
+	}
+	return events, err
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+type spanKey struct{}
+
+type recordingSpans struct {
+	lock  sync.Mutex
+	spans []string
+}
+
+type recordingSpan struct {
+	s    *recordingSpans
+	name string
+}
+
+func (s *recordingSpans) StartSpan(ctx context.Context, name string, attrs []SpanAttribute) (context.Context, Span) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.spans = append(s.spans, fmt.Sprintf("start %s parent=%v %v", name, ctx.Value(spanKey{}), attrs))
+	return context.WithValue(ctx, spanKey{}, name), &recordingSpan{s: s, name: name}
+}
+
+func (s *recordingSpan) End(result string, err error) {
+	s.s.lock.Lock()
+	defer s.s.lock.Unlock()
+	s.s.spans = append(s.s.spans, fmt.Sprintf("end %s %s err=%v", s.name, result, err))
+}
+
+// ctxAction records the span in the context passed to Run.
+type ctxAction struct {
+	*testAction
+	span any
+}
+
+func (a *ctxAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
+	a.span = ctx.Value(spanKey{})
+	return a.testAction.Run(ctx, c)
+}
+
+type callsAction struct {
+	*testAction
+}
+
+func (a *callsAction) Calls() []Call { return []Call{{Method: "Insert", Version: meta.VersionBeta}} }
+
+func TestSpans(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var actions []Action
+			for _, a := range actionsFromGraphStr("A -> !B -> C") {
+				ta := a.(*testAction)
+				ta.id = &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(ta.name)}
+				actions = append(actions, &ctxAction{testAction: ta})
+			}
+			actions = append(actions, NewExistsAction(&cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey("D")}))
+
+			var spans recordingSpans
+			ex, err := tc.new(actions, SpanStarterOption(&spans))
+			if err != nil {
+				t.Fatalf("new executor = %v, want nil", err)
+			}
+			ctx := context.WithValue(context.Background(), spanKey{}, "plan")
+			if _, err := ex.Run(ctx, nil); err == nil {
+				t.Fatal("Run() = nil, want error")
+			}
+
+			// The order of the spans is not deterministic for the
+			// parallel executor.
+			sort.Strings(spans.spans)
+			want := []string{
+				"end A([A]) ok err=<nil>",
+				"end B([B]) error err=injected",
+				"start A([A]) parent=plan [{rgraph.resource_id fakes:proj/A} {rgraph.operation Custom}]",
+				"start B([B]) parent=plan [{rgraph.resource_id fakes:proj/B} {rgraph.operation Custom}]",
+			}
+			if diff := cmp.Diff(spans.spans, want); diff != "" {
+				t.Errorf("spans: diff -got,+want: %s", diff)
+			}
+			for _, a := range actions {
+				ca, ok := a.(*ctxAction)
+				if !ok || ca.name == "C" {
+					continue
+				}
+				if want := ca.Metadata().Name; ca.span != want {
+					t.Errorf("span in Run(%s) context = %v, want %v", ca.name, ca.span, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSpanAttributes(t *testing.T) {
+	a := &callsAction{testAction: &testAction{name: "A"}}
+	got := spanAttributes(a)
+	want := []SpanAttribute{
+		{Key: SpanAttrOperation, Value: "Custom"},
+		{Key: SpanAttrVersion, Value: "beta"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("spanAttributes(); -got,+want: %s", diff)
+	}
+}
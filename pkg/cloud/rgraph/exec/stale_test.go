@@ -0,0 +1,165 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeClock is a clock that is advanced by the test.
+type fakeClock struct {
+	lock sync.Mutex
+	t    time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// advancingAction advances the clock when it is run.
+type advancingAction struct {
+	*testAction
+	clock *fakeClock
+	d     time.Duration
+}
+
+func (a *advancingAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
+	a.clock.Advance(a.d)
+	return a.testAction.Run(ctx, c)
+}
+
+func TestStaleState(t *testing.T) {
+	newExecutors := []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	}
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := func(name string) *cloud.ResourceID {
+		return &cloud.ResourceID{Resource: "fakes", ProjectID: "proj", Key: meta.GlobalKey(name)}
+	}
+
+	for _, tc := range []struct {
+		name string
+		// Action "A" advances the clock by advance.
+		advance time.Duration
+		opts    []Option
+
+		wantOutcomes map[string]Outcome
+		wantStale    bool
+	}{
+		{
+			name:    "fresh",
+			advance: 30 * time.Second,
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeCompleted,
+				"C([C])": OutcomeCompleted,
+				"D([D])": OutcomeCompleted,
+			},
+		},
+		{
+			name:    "stale during execution",
+			advance: 2 * time.Minute,
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeFailed,
+				"C([C])": OutcomePending,
+				"D([D])": OutcomeCompleted,
+			},
+			wantStale: true,
+		},
+		{
+			name:    "not checked without StaleStateOption",
+			advance: 2 * time.Minute,
+			opts:    []Option{StaleStateOption(0, nil)},
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeCompleted,
+				"C([C])": OutcomeCompleted,
+				"D([D])": OutcomeCompleted,
+			},
+		},
+	} {
+		for _, ne := range newExecutors {
+			t.Run(tc.name+"/"+ne.name, func(t *testing.T) {
+				clock := &fakeClock{t: t0}
+				// fetchTimes of the resources. "d" has an unknown fetch
+				// time and is never stale.
+				fetchTimes := map[string]time.Time{"a": t0, "b": t0, "c": t0}
+				var acts []Action
+				for _, a := range actionsFromGraphStr("A -> B -> C; A -> D") {
+					ta := a.(*testAction)
+					ta.id = id(strings.ToLower(ta.name))
+					if ta.name == "A" {
+						a = &advancingAction{testAction: ta, clock: clock, d: tc.advance}
+					}
+					acts = append(acts, a)
+				}
+				opts := append([]Option{
+					ErrorStrategyOption(ContinueOnError),
+					ClockOption(clock.Now),
+					StaleStateOption(time.Minute, func(id *cloud.ResourceID) time.Time { return fetchTimes[id.Key.Name] }),
+				}, tc.opts...)
+				ex, err := ne.new(acts, opts...)
+				if err != nil {
+					t.Fatalf("new() = %v, want nil", err)
+				}
+				result, err := ex.Run(context.Background(), nil)
+				if gotStale := errors.Is(err, ErrStaleState); gotStale != tc.wantStale {
+					t.Errorf("errors.Is(%v, ErrStaleState) = %t, want %t", err, gotStale, tc.wantStale)
+				}
+				if diff := cmp.Diff(result.Outcomes(), tc.wantOutcomes); diff != "" {
+					t.Errorf("Outcomes(): diff -got,+want: %s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestStaleStateOptionInvalid(t *testing.T) {
+	for _, opt := range []Option{StaleStateOption(-1, nil), StaleStateOption(time.Minute, nil), ClockOption(nil)} {
+		if _, err := NewSerialExecutor(nil, opt); err == nil {
+			t.Error("NewSerialExecutor() = nil, want error")
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/google/go-cmp/cmp"
+)
+
+// blockingAction blocks until its context is done.
+type blockingAction struct {
+	*testAction
+}
+
+func (a *blockingAction) Run(ctx context.Context, c cloud.Cloud) (EventList, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestTimeouts(t *testing.T) {
+	newExecutors := []struct {
+		name string
+		new  func([]Action, ...Option) (Executor, error)
+	}{
+		{
+			name: "serial",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewSerialExecutor(a, opts...) },
+		},
+		{
+			name: "parallel",
+			new:  func(a []Action, opts ...Option) (Executor, error) { return NewParallelExecutor(a, opts...) },
+		},
+	}
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		// Action "B" blocks until its context is done.
+		graph string
+
+		wantOutcomes map[string]Outcome
+		wantTimeout  bool
+		wantCtxErr   error
+	}{
+		{
+			name:  "action timeout",
+			opts:  []Option{ActionTimeoutOption(10 * time.Millisecond), ErrorStrategyOption(ContinueOnError)},
+			graph: "A -> B -> C; A -> D",
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeFailed,
+				"C([C])": OutcomePending,
+				"D([D])": OutcomeCompleted,
+			},
+			wantTimeout: true,
+		},
+		{
+			name:  "plan timeout",
+			opts:  []Option{PlanTimeoutOption(10 * time.Millisecond)},
+			graph: "A -> B -> C; B -> D",
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeFailed,
+				"C([C])": OutcomeSkipped,
+				"D([D])": OutcomeSkipped,
+			},
+			wantCtxErr: context.DeadlineExceeded,
+		},
+		{
+			name:  "plan timeout with ContinueOnError",
+			opts:  []Option{PlanTimeoutOption(10 * time.Millisecond), ErrorStrategyOption(ContinueOnError)},
+			graph: "A -> B -> C",
+			wantOutcomes: map[string]Outcome{
+				"A([A])": OutcomeCompleted,
+				"B([B])": OutcomeFailed,
+				"C([C])": OutcomeSkipped,
+			},
+			wantCtxErr: context.DeadlineExceeded,
+		},
+	} {
+		for _, ne := range newExecutors {
+			t.Run(tc.name+"/"+ne.name, func(t *testing.T) {
+				var acts []Action
+				for _, a := range actionsFromGraphStr(tc.graph) {
+					if a.(*testAction).name == "B" {
+						a = &blockingAction{testAction: a.(*testAction)}
+					}
+					acts = append(acts, a)
+				}
+				ex, err := ne.new(acts, tc.opts...)
+				if err != nil {
+					t.Fatalf("new() = %v, want nil", err)
+				}
+				result, err := ex.Run(context.Background(), nil)
+				if err == nil {
+					t.Fatal("Run() = nil, want error")
+				}
+				if diff := cmp.Diff(result.Outcomes(), tc.wantOutcomes); diff != "" {
+					t.Errorf("Outcomes(): diff -got,+want: %s", diff)
+				}
+				if gotTimeout := errors.Is(err, ErrActionTimeout); gotTimeout != tc.wantTimeout {
+					t.Errorf("errors.Is(%v, ErrActionTimeout) = %t, want %t", err, gotTimeout, tc.wantTimeout)
+				}
+				var execErr *ExecError
+				if !errors.As(err, &execErr) {
+					t.Fatalf("Run() = %v, want *ExecError", err)
+				}
+				if execErr.Err != tc.wantCtxErr {
+					t.Errorf("ExecError.Err = %v, want %v", execErr.Err, tc.wantCtxErr)
+				}
+				if tc.wantCtxErr != nil && !errors.Is(err, tc.wantCtxErr) {
+					t.Errorf("errors.Is(%v, %v) = false, want true", err, tc.wantCtxErr)
+				}
+			})
+		}
+	}
+}
+
+func TestTimeoutOptionsInvalid(t *testing.T) {
+	for _, opt := range []Option{ActionTimeoutOption(-1), PlanTimeoutOption(-1)} {
+		if _, err := NewSerialExecutor(nil, opt); err == nil {
+			t.Error("NewSerialExecutor() = nil, want error")
+		}
+	}
+}
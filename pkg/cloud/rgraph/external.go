@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// AddExternal adds a reference-only Node for the existing resource id. The
+// resource is read from the Cloud and the Node is OwnershipExternal: other
+// Nodes can reference it but planning will never update or delete it (e.g. a
+// security policy managed by the customer that is attached to a managed
+// BackendService). The type of id must be registered (see
+// rnode.RegisterNodeType()).
+func (g *Builder) AddExternal(ctx context.Context, cl cloud.Cloud, id *cloud.ResourceID) (rnode.Builder, error) {
+	if g.Get(id) != nil {
+		return nil, fmt.Errorf("%s: AddExternal: node %v is already in the graph", builderErrPrefix, id)
+	}
+	nb, err := rnode.NewBuilderForID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%s: AddExternal: %w", builderErrPrefix, err)
+	}
+	nb.SetOwnership(rnode.OwnershipExternal)
+	if err := nb.SyncFromCloud(ctx, cl); err != nil {
+		return nil, fmt.Errorf("%s: AddExternal %v: %w", builderErrPrefix, id, err)
+	}
+	if nb.State() != rnode.NodeExists {
+		return nil, fmt.Errorf("%s: AddExternal: resource %v does not exist", builderErrPrefix, id)
+	}
+	g.Add(nb)
+	return nb, nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fanout models a logical resource that is expanded into one
+// concrete resource per location, e.g. a NetworkEndpointGroup in each zone
+// of a cluster.
+//
+// The concrete resources are ordinary Nodes in the Graph; a Logical groups
+// them so that their state and plans can be reported as one.
+package fanout
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// IDFunc returns the ResourceID of the concrete resource with key.
+type IDFunc func(key *meta.Key) *cloud.ResourceID
+
+// BuilderFunc returns the Builder for the concrete resource id. The Builder
+// should have its resource, state and ownership set.
+type BuilderFunc func(id *cloud.ResourceID) (rnode.Builder, error)
+
+// Logical is a resource expanded into a concrete resource per location.
+type Logical struct {
+	// Name of the logical resource. This is also the name of the
+	// concrete resources.
+	Name string
+	// IDs of the concrete resources, sorted by location.
+	IDs []*cloud.ResourceID
+}
+
+// NewZonal returns the Logical resource name expanded into each of the
+// zones.
+func NewZonal(name string, zones []string, id IDFunc) *Logical {
+	l := &Logical{Name: name}
+	for _, z := range zones {
+		l.IDs = append(l.IDs, id(meta.ZonalKey(name, z)))
+	}
+	l.sort()
+	return l
+}
+
+// NewRegional returns the Logical resource name expanded into each of the
+// regions.
+func NewRegional(name string, regions []string, id IDFunc) *Logical {
+	l := &Logical{Name: name}
+	for _, r := range regions {
+		l.IDs = append(l.IDs, id(meta.RegionalKey(name, r)))
+	}
+	l.sort()
+	return l
+}
+
+func (l *Logical) sort() {
+	sort.Slice(l.IDs, func(i, j int) bool { return Location(l.IDs[i]) < Location(l.IDs[j]) })
+}
+
+// Location of the concrete resource id (the zone or region). This is ""
+// for a global resource.
+func Location(id *cloud.ResourceID) string {
+	switch id.Key.Type() {
+	case meta.Zonal:
+		return id.Key.Zone
+	case meta.Regional:
+		return id.Key.Region
+	}
+	return ""
+}
+
+// Locations of the concrete resources.
+func (l *Logical) Locations() []string {
+	var ret []string
+	for _, id := range l.IDs {
+		ret = append(ret, Location(id))
+	}
+	return ret
+}
+
+// Add the Builders for each of the concrete resources to b.
+func (l *Logical) Add(b *rgraph.Builder, f BuilderFunc) error {
+	for _, id := range l.IDs {
+		nb, err := f(id)
+		if err != nil {
+			return fmt.Errorf("fanout %s: %w", l.Name, err)
+		}
+		if !nb.ID().Equal(id) {
+			return fmt.Errorf("fanout %s: Builder has id %v, want %v", l.Name, nb.ID(), id)
+		}
+		b.Add(nb)
+	}
+	return nil
+}
+
+// EnsureAbsent marks all of the concrete resources in b for deletion (see
+// rgraph.Builder.EnsureAbsent()).
+func (l *Logical) EnsureAbsent(b *rgraph.Builder) error {
+	for _, id := range l.IDs {
+		if err := b.EnsureAbsent(id); err != nil {
+			return fmt.Errorf("fanout %s: %w", l.Name, err)
+		}
+	}
+	return nil
+}
+
+// Nodes returns the concrete Nodes in g. Returns an error if any are
+// missing.
+func (l *Logical) Nodes(g *rgraph.Graph) ([]rnode.Node, error) {
+	var ret []rnode.Node
+	for _, id := range l.IDs {
+		n := g.Get(id)
+		if n == nil {
+			return nil, fmt.Errorf("fanout %s: node %v is not in the graph", l.Name, id)
+		}
+		ret = append(ret, n)
+	}
+	return ret, nil
+}
+
+// State of the Logical resource aggregated over the concrete resources.
+type State string
+
+const (
+	// StateExists means all of the concrete resources exist.
+	StateExists State = "Exists"
+	// StateDoesNotExist means none of the concrete resources exist.
+	StateDoesNotExist State = "DoesNotExist"
+	// StatePartial means some of the concrete resources exist.
+	StatePartial State = "Partial"
+	// StateUnknown means the state of some of the concrete resources is
+	// not known (e.g. there was an error fetching them).
+	StateUnknown State = "Unknown"
+)
+
+// State aggregates the state of the concrete resources in g.
+func (l *Logical) State(g *rgraph.Graph) (State, error) {
+	nodes, err := l.Nodes(g)
+	if err != nil {
+		return StateUnknown, err
+	}
+	var exists, notExists int
+	for _, n := range nodes {
+		switch n.State() {
+		case rnode.NodeExists:
+			exists++
+		case rnode.NodeDoesNotExist:
+			notExists++
+		default:
+			return StateUnknown, nil
+		}
+	}
+	switch {
+	case notExists == 0:
+		return StateExists, nil
+	case exists == 0:
+		return StateDoesNotExist, nil
+	}
+	return StatePartial, nil
+}
+
+// Plan of the Logical resource aggregated over the concrete resources.
+type Plan struct {
+	// Name of the Logical resource.
+	Name string
+	// Op is the aggregate Operation. This is the Operation of the
+	// concrete resources if they are all the same, OpNothing if none of
+	// them change and OpUpdate otherwise.
+	Op rnode.Operation
+	// ByLocation are the plans of the concrete resources.
+	ByLocation map[string]rnode.PlanDetails
+}
+
+// Changed returns the locations with changes planned, sorted.
+func (p *Plan) Changed() []string {
+	var ret []string
+	for loc, d := range p.ByLocation {
+		if d.Operation != rnode.OpNothing {
+			ret = append(ret, loc)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Diffs returns the diffs of the concrete resources that have one, by
+// location.
+func (p *Plan) Diffs() map[string]*api.DiffResult {
+	ret := map[string]*api.DiffResult{}
+	for loc, d := range p.ByLocation {
+		if d.Diff != nil {
+			ret[loc] = d.Diff
+		}
+	}
+	return ret
+}
+
+// String implements Stringer.
+func (p *Plan) String() string {
+	var locs []string
+	for loc := range p.ByLocation {
+		locs = append(locs, loc)
+	}
+	sort.Strings(locs)
+	var parts []string
+	for _, loc := range locs {
+		parts = append(parts, fmt.Sprintf("%s=%s", loc, p.ByLocation[loc].Operation))
+	}
+	return fmt.Sprintf("%s: %s [%s]", p.Name, p.Op, strings.Join(parts, " "))
+}
+
+// Plan aggregates the plans of the concrete resources in want. want must
+// have been planned (see plan.Do()).
+func (l *Logical) Plan(want *rgraph.Graph) (*Plan, error) {
+	nodes, err := l.Nodes(want)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Plan{Name: l.Name, ByLocation: map[string]rnode.PlanDetails{}}
+	ops := map[rnode.Operation]bool{}
+	for _, n := range nodes {
+		details := n.Plan().Details()
+		if details == nil {
+			return nil, fmt.Errorf("fanout %s: node %v has no plan", l.Name, n.ID())
+		}
+		ret.ByLocation[Location(n.ID())] = *details
+		ops[details.Operation] = true
+	}
+	switch {
+	case len(ops) == 1:
+		for op := range ops {
+			ret.Op = op
+		}
+	case len(ops) == 0:
+		ret.Op = rnode.OpNothing
+	default:
+		ret.Op = rnode.OpUpdate
+	}
+	return ret, nil
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fanout
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+const proj = "proj-1"
+
+var zones = []string{"us-central1-c", "us-central1-a", "us-central1-b"}
+
+func fakeID(key *meta.Key) *cloud.ResourceID { return fake.ID(proj, key) }
+
+// graph returns a Graph with the concrete resources of l. The resources
+// in the locations in exists have the given value; the others do not exist.
+func graph(t *testing.T, l *Logical, exists map[string]string) *rgraph.Graph {
+	t.Helper()
+	b := rgraph.NewBuilder()
+	err := l.Add(b, func(id *cloud.ResourceID) (rnode.Builder, error) {
+		nb := fake.NewBuilder(id)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		v, ok := exists[Location(id)]
+		if !ok {
+			nb.SetState(rnode.NodeDoesNotExist)
+			return nb, nil
+		}
+		mr := fake.NewMutableFake(proj, id.Key)
+		mr.Access(func(x *fake.FakeResource) { x.Value = v })
+		r, err := mr.Freeze()
+		if err != nil {
+			return nil, err
+		}
+		nb.SetState(rnode.NodeExists)
+		return nb, nb.SetResource(r)
+	})
+	if err != nil {
+		t.Fatalf("Add() = %v", err)
+	}
+	return b.MustBuild()
+}
+
+func TestLocations(t *testing.T) {
+	l := NewZonal("neg", zones, fakeID)
+	want := []string{"us-central1-a", "us-central1-b", "us-central1-c"}
+	if diff := cmp.Diff(l.Locations(), want); diff != "" {
+		t.Errorf("Locations(); -got,+want: %s", diff)
+	}
+	r := NewRegional("neg", []string{"us-east1", "europe-west1"}, fakeID)
+	if diff := cmp.Diff(r.Locations(), []string{"europe-west1", "us-east1"}); diff != "" {
+		t.Errorf("Locations(); -got,+want: %s", diff)
+	}
+	if got := Location(fakeID(meta.GlobalKey("x"))); got != "" {
+		t.Errorf("Location(global) = %q, want \"\"", got)
+	}
+}
+
+func TestAddWrongID(t *testing.T) {
+	l := NewZonal("neg", zones, fakeID)
+	err := l.Add(rgraph.NewBuilder(), func(id *cloud.ResourceID) (rnode.Builder, error) {
+		return fake.NewBuilder(fakeID(meta.GlobalKey("x"))), nil
+	})
+	if err == nil {
+		t.Errorf("Add() = nil, want error")
+	}
+}
+
+func TestState(t *testing.T) {
+	l := NewZonal("neg", zones, fakeID)
+	for _, tc := range []struct {
+		name   string
+		exists map[string]string
+		want   State
+	}{
+		{"none", nil, StateDoesNotExist},
+		{"some", map[string]string{"us-central1-a": ""}, StatePartial},
+		{"all", map[string]string{"us-central1-a": "", "us-central1-b": "", "us-central1-c": ""}, StateExists},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := l.State(graph(t, l, tc.exists))
+			if err != nil || got != tc.want {
+				t.Errorf("State() = %v, %v; want %v, nil", got, err, tc.want)
+			}
+		})
+	}
+	if _, err := l.State(rgraph.NewBuilder().MustBuild()); err == nil {
+		t.Errorf("State(empty graph) = nil, want error")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	l := NewZonal("neg", zones, fakeID)
+	all := func(v string) map[string]string {
+		return map[string]string{"us-central1-a": v, "us-central1-b": v, "us-central1-c": v}
+	}
+	for _, tc := range []struct {
+		name        string
+		got, want   map[string]string
+		wantOp      rnode.Operation
+		wantChanged []string
+	}{
+		{
+			name:   "no change",
+			got:    all("a"),
+			want:   all("a"),
+			wantOp: rnode.OpNothing,
+		},
+		{
+			name:        "create",
+			want:        all("a"),
+			wantOp:      rnode.OpCreate,
+			wantChanged: []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+		},
+		{
+			name:        "delete",
+			got:         all("a"),
+			wantOp:      rnode.OpDelete,
+			wantChanged: []string{"us-central1-a", "us-central1-b", "us-central1-c"},
+		},
+		{
+			name:        "expand to a new zone",
+			got:         map[string]string{"us-central1-a": "a", "us-central1-b": "a"},
+			want:        all("a"),
+			wantOp:      rnode.OpUpdate,
+			wantChanged: []string{"us-central1-c"},
+		},
+		{
+			name:        "update one zone",
+			got:         map[string]string{"us-central1-a": "a", "us-central1-b": "b", "us-central1-c": "a"},
+			want:        all("a"),
+			wantOp:      rnode.OpUpdate,
+			wantChanged: []string{"us-central1-b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := graph(t, l, tc.got)
+			want := graph(t, l, tc.want)
+			if err := localplan.PlanWantGraph(got, want); err != nil {
+				t.Fatalf("PlanWantGraph() = %v", err)
+			}
+			p, err := l.Plan(want)
+			if err != nil {
+				t.Fatalf("Plan() = %v", err)
+			}
+			if p.Op != tc.wantOp {
+				t.Errorf("Plan().Op = %s, want %s (%v)", p.Op, tc.wantOp, p)
+			}
+			if diff := cmp.Diff(p.Changed(), tc.wantChanged); diff != "" {
+				t.Errorf("Changed(); -got,+want: %s", diff)
+			}
+			for loc := range p.Diffs() {
+				if p.ByLocation[loc].Operation != rnode.OpUpdate {
+					t.Errorf("Diffs() has %s with operation %s", loc, p.ByLocation[loc].Operation)
+				}
+			}
+		})
+	}
+}
+
+func TestEnsureAbsent(t *testing.T) {
+	l := NewZonal("neg", zones, fakeID)
+	b := graph(t, l, map[string]string{"us-central1-a": "a"}).NewBuilderWithEmptyNodes()
+	for _, nb := range b.All() {
+		nb.SetOwnership(rnode.OwnershipManaged)
+	}
+	if err := l.EnsureAbsent(b); err != nil {
+		t.Fatalf("EnsureAbsent() = %v", err)
+	}
+	for _, id := range l.IDs {
+		if s := b.Get(id).State(); s != rnode.NodeDoesNotExist {
+			t.Errorf("State(%v) = %s, want %s", id, s, rnode.NodeDoesNotExist)
+		}
+	}
+}
@@ -33,6 +33,8 @@ func newGraph() *Graph {
 // the Builder to manipulate the set of resource nodes.
 type Graph struct {
 	nodes map[cloud.ResourceMapKey]rnode.Node
+	// collisions from the Builder (see Validate()).
+	collisions []*cloud.ResourceID
 }
 
 // All of the nodes in the Graph.
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// NodeDiffState is how a Node differs between two Graphs.
+type NodeDiffState string
+
+const (
+	// NodeAdded means the resource exists in want but not in got.
+	NodeAdded NodeDiffState = "Added"
+	// NodeRemoved means the resource exists in got but not in want.
+	NodeRemoved NodeDiffState = "Removed"
+	// NodeChanged means the resource exists in both Graphs with different
+	// values.
+	NodeChanged NodeDiffState = "Changed"
+)
+
+// NodeDiff is the difference for a single resource.
+type NodeDiff struct {
+	ID    *cloud.ResourceID
+	State NodeDiffState
+	// Diff between the resources in got and want. This is only set for
+	// NodeChanged.
+	Diff *api.DiffResult
+}
+
+// Paths are the fields that differ for NodeChanged.
+func (d *NodeDiff) Paths() []api.Path {
+	if d.Diff == nil {
+		return nil
+	}
+	var ret []api.Path
+	for _, item := range d.Diff.Items {
+		ret = append(ret, item.Path)
+	}
+	return ret
+}
+
+// GraphDiff is the result of Diff().
+type GraphDiff struct {
+	// Nodes that differ, sorted by ID.
+	Nodes []NodeDiff
+}
+
+// HasDiff is true if any of the Nodes differ.
+func (d *GraphDiff) HasDiff() bool { return len(d.Nodes) > 0 }
+
+// String returns one line per Node that differs, e.g.
+// "Changed compute/addresses:proj/us-central1/addr: *.Description".
+func (d *GraphDiff) String() string {
+	var lines []string
+	for _, nd := range d.Nodes {
+		line := fmt.Sprintf("%s %s", nd.State, nd.ID)
+		if paths := nd.Paths(); len(paths) > 0 {
+			var ps []string
+			for _, p := range paths {
+				ps = append(ps, p.String())
+			}
+			line += ": " + strings.Join(ps, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Diff returns the differences between the resources in want and got. This
+// does not plan or modify the Graphs, e.g. it can be used to report what has
+// changed since the last sync. Nodes that are tombstones (NodeDoesNotExist)
+// are treated the same as Nodes that are not in the Graph. The IgnoreDiffPaths
+// of the Nodes in want are applied.
+//
+// It is an error if a Node in either Graph is not in the NodeExists or
+// NodeDoesNotExist state.
+func Diff(want, got *Graph) (*GraphDiff, error) {
+	ids := map[cloud.ResourceMapKey]*cloud.ResourceID{}
+	for _, g := range []*Graph{want, got} {
+		for k, n := range g.nodes {
+			ids[k] = n.ID()
+		}
+	}
+
+	ret := &GraphDiff{}
+	for k, id := range ids {
+		wantNode, wantExists, err := diffNode(want, k)
+		if err != nil {
+			return nil, err
+		}
+		gotNode, gotExists, err := diffNode(got, k)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case wantExists && !gotExists:
+			ret.Nodes = append(ret.Nodes, NodeDiff{ID: id, State: NodeAdded})
+		case !wantExists && gotExists:
+			ret.Nodes = append(ret.Nodes, NodeDiff{ID: id, State: NodeRemoved})
+		case wantExists && gotExists:
+			details, err := wantNode.Diff(gotNode)
+			if err != nil {
+				return nil, fmt.Errorf("rgraph: Diff %s: %w", id, err)
+			}
+			if details.Diff != nil && details.Diff.HasDiff() {
+				ret.Nodes = append(ret.Nodes, NodeDiff{ID: id, State: NodeChanged, Diff: details.Diff})
+			}
+		}
+	}
+	sort.Slice(ret.Nodes, func(i, j int) bool {
+		return ret.Nodes[i].ID.String() < ret.Nodes[j].ID.String()
+	})
+
+	return ret, nil
+}
+
+// diffNode returns the Node for k in g and whether the resource exists.
+func diffNode(g *Graph, k cloud.ResourceMapKey) (rnode.Node, bool, error) {
+	n, ok := g.nodes[k]
+	if !ok {
+		return nil, false, nil
+	}
+	switch n.State() {
+	case rnode.NodeExists:
+		return n, true, nil
+	case rnode.NodeDoesNotExist:
+		return n, false, nil
+	}
+	return nil, false, fmt.Errorf("rgraph: Diff %s: node is in state %s", n.ID(), n.State())
+}
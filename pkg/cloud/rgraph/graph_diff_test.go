@@ -0,0 +1,126 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeGraph builds a Graph of Fake resources. nodes maps the name of the
+// resource to its Value. An empty Value is a tombstone.
+func fakeGraph(t *testing.T, nodes map[string]string) *Graph {
+	t.Helper()
+	b := NewBuilder()
+	for name, value := range nodes {
+		nb := fake.NewBuilder(fake.ID("proj", meta.GlobalKey(name)))
+		nb.SetOwnership(rnode.OwnershipManaged)
+		if value == "" {
+			nb.SetState(rnode.NodeDoesNotExist)
+		} else {
+			r := fake.NewMutableFake("proj", meta.GlobalKey(name))
+			if err := r.Access(func(x *fake.FakeResource) {
+				x.Name = name
+				x.Value = value
+				x.NullFields = []string{"Dependencies"}
+			}); err != nil {
+				t.Fatalf("Access() = %v", err)
+			}
+			fr, err := r.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v", err)
+			}
+			nb.SetState(rnode.NodeExists)
+			if err := nb.SetResource(fr); err != nil {
+				t.Fatalf("SetResource() = %v", err)
+			}
+		}
+		b.Add(nb)
+	}
+	return b.MustBuild()
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		want    map[string]string
+		got     map[string]string
+		wantStr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "no diff",
+			want: map[string]string{"a": "1", "b": "2", "c": ""},
+			got:  map[string]string{"a": "1", "b": "2"},
+		},
+		{
+			name:    "added",
+			want:    map[string]string{"a": "1", "b": "2"},
+			got:     map[string]string{"a": "1", "b": ""},
+			wantStr: "Added fakes:proj/b",
+		},
+		{
+			name:    "removed",
+			want:    map[string]string{"a": ""},
+			got:     map[string]string{"a": "1", "b": "2"},
+			wantStr: "Removed fakes:proj/a\nRemoved fakes:proj/b",
+		},
+		{
+			name:    "changed",
+			want:    map[string]string{"a": "1", "b": "3"},
+			got:     map[string]string{"a": "1", "b": "2"},
+			wantStr: "Changed fakes:proj/b: *.Value",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := Diff(fakeGraph(t, tc.want), fakeGraph(t, tc.got))
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if d.HasDiff() != (tc.wantStr != "") {
+				t.Errorf("HasDiff() = %t, want %t", d.HasDiff(), tc.wantStr != "")
+			}
+			if diff := cmp.Diff(d.String(), tc.wantStr); diff != "" {
+				t.Errorf("String() diff -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDiffInvalidState(t *testing.T) {
+	want := fakeGraph(t, map[string]string{"a": "1"})
+	got := fakeGraph(t, map[string]string{"a": "1"})
+	for _, n := range got.All() {
+		nb := n.Builder()
+		nb.SetState(rnode.NodeStateError)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		en, err := nb.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		got.add(en)
+	}
+	if _, err := Diff(want, got); err == nil {
+		t.Error("Diff() = nil, want error")
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// graphJSON is the serialized form of a Graph.
+type graphJSON struct {
+	Nodes []nodeJSON `json:"nodes"`
+}
+
+// nodeJSON is the serialized form of a Node. InRefs and OutRefs are not
+// saved; they are computed from the resources when the Graph is restored.
+type nodeJSON struct {
+	ID              *cloud.ResourceID     `json:"id"`
+	State           rnode.NodeState       `json:"state"`
+	Ownership       rnode.OwnershipStatus `json:"ownership"`
+	IgnoreDiffPaths []api.Path            `json:"ignoreDiffPaths,omitempty"`
+	FetchTime       *time.Time            `json:"fetchTime,omitempty"`
+	// Resource is the JSON serialized api.Resource. This is omitted if
+	// the Node has no resource (e.g. tombstones).
+	Resource json.RawMessage `json:"resource,omitempty"`
+	// Plan is the history of the Plan for the Node (see
+	// rnode.Plan.History()).
+	Plan []rnode.PlanDetails `json:"plan,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. The Nodes are serialized with their
+// state, resource and plan so that a planned Graph can be checkpointed and
+// restored with UnmarshalJSON(). Nodes are sorted by ID for a stable
+// encoding.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	gj := graphJSON{Nodes: []nodeJSON{}}
+	for _, n := range g.nodes {
+		nj := nodeJSON{
+			ID:              n.ID(),
+			State:           n.State(),
+			Ownership:       n.Ownership(),
+			IgnoreDiffPaths: n.IgnoreDiffPaths(),
+			Plan:            n.Plan().History(),
+		}
+		if t := n.FetchTime(); !t.IsZero() {
+			nj.FetchTime = &t
+		}
+		if r := n.Resource(); r != nil {
+			b, err := json.Marshal(r)
+			if err != nil {
+				return nil, fmt.Errorf("graph: MarshalJSON %s: %w", n.ID(), err)
+			}
+			nj.Resource = b
+		}
+		gj.Nodes = append(gj.Nodes, nj)
+	}
+	sort.Slice(gj.Nodes, func(i, j int) bool {
+		return gj.Nodes[i].ID.String() < gj.Nodes[j].ID.String()
+	})
+	return json.Marshal(gj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The contents of g are replaced
+// with the Graph serialized with MarshalJSON(). The Node types must be
+// registered with rnode.RegisterNodeType(); this is done by importing the
+// package for the resource (e.g. rnode/targetsslproxy).
+//
+// Diffs in the restored Plans contain values decoded into generic types
+// (e.g. map[string]any instead of the API struct).
+func (g *Graph) UnmarshalJSON(b []byte) error {
+	var gj graphJSON
+	if err := json.Unmarshal(b, &gj); err != nil {
+		return err
+	}
+
+	builder := NewBuilder()
+	for _, nj := range gj.Nodes {
+		if nj.ID == nil {
+			return fmt.Errorf("graph: UnmarshalJSON: node without id")
+		}
+		if builder.Get(nj.ID) != nil {
+			return fmt.Errorf("graph: UnmarshalJSON: duplicate node %s", nj.ID)
+		}
+		nb, err := rnode.NewBuilderForID(nj.ID)
+		if err != nil {
+			return fmt.Errorf("graph: UnmarshalJSON: %w", err)
+		}
+		nb.SetState(nj.State)
+		nb.SetOwnership(nj.Ownership)
+		if len(nj.IgnoreDiffPaths) > 0 {
+			nb.SetIgnoreDiffPaths(nj.IgnoreDiffPaths)
+		}
+		if nj.FetchTime != nil {
+			nb.SetFetchTime(*nj.FetchTime)
+		}
+		if len(nj.Resource) > 0 {
+			r, err := rnode.UnmarshalResource(nj.ID, nj.Resource)
+			if err != nil {
+				return fmt.Errorf("graph: UnmarshalJSON: %w", err)
+			}
+			if err := nb.SetResource(r); err != nil {
+				return fmt.Errorf("graph: UnmarshalJSON: %w", err)
+			}
+		}
+		builder.Add(nb)
+	}
+	// Tombstones are added to a Graph without validation (see
+	// AddTombstone()) so the Builder validation is not applied here.
+	if err := builder.computeInRefs(); err != nil {
+		return fmt.Errorf("graph: UnmarshalJSON: %w", err)
+	}
+
+	newGraph := newGraph()
+	for _, nj := range gj.Nodes {
+		n, err := builder.Get(nj.ID).Build()
+		if err != nil {
+			return fmt.Errorf("graph: UnmarshalJSON: %w", err)
+		}
+		for _, d := range nj.Plan {
+			n.Plan().Set(d)
+		}
+		newGraph.add(n)
+	}
+	g.nodes = newGraph.nodes
+
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheckservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestGraphJSON(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+		neURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/notificationEndpoints/ne"
+	)
+	fetchTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	ne := notificationendpoint.NewMutableNotificationEndpoint(proj, meta.RegionalKey("ne", region))
+	if err := ne.Access(func(x *compute.NotificationEndpoint) {
+		x.Name = "ne"
+		x.NullFields = []string{"Description", "GrpcSettings"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	neFrozen, err := ne.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	hcs := healthcheckservice.NewMutableHealthCheckService(proj, meta.RegionalKey("hcs", region))
+	if err := hcs.Access(func(x *compute.HealthCheckService) {
+		x.Name = "hcs"
+		x.Description = "desc"
+		x.NotificationEndpoints = []string{neURL}
+		x.NullFields = []string{"HealthChecks", "HealthStatusAggregationPolicy", "NetworkEndpointGroups"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	hcsFrozen, err := hcs.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+
+	b := NewBuilder()
+	neb := notificationendpoint.NewBuilderWithResource(neFrozen)
+	neb.SetState(rnode.NodeExists)
+	neb.SetOwnership(rnode.OwnershipExternal)
+	neb.SetFetchTime(fetchTime)
+	b.Add(neb)
+	hcsb := healthcheckservice.NewBuilderWithResource(hcsFrozen)
+	hcsb.SetState(rnode.NodeExists)
+	hcsb.SetOwnership(rnode.OwnershipManaged)
+	hcsb.SetIgnoreDiffPaths([]api.Path{api.Path{}.Pointer().Field("Description")})
+	b.Add(hcsb)
+	g := b.MustBuild()
+
+	tombstoneb := notificationendpoint.NewBuilder(notificationendpoint.ID(proj, meta.RegionalKey("old", region)))
+	tombstoneb.SetState(rnode.NodeDoesNotExist)
+	tombstone, err := tombstoneb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	if err := g.AddTombstone(tombstone); err != nil {
+		t.Fatalf("AddTombstone() = %v", err)
+	}
+	g.Get(hcsFrozen.ResourceID()).Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate, Why: "first"})
+	g.Get(hcsFrozen.ResourceID()).Plan().Set(rnode.PlanDetails{
+		Operation: rnode.OpUpdate,
+		Why:       "update in place",
+		Diff: &api.DiffResult{Items: []api.DiffItem{{
+			State: api.DiffItemDifferent,
+			Path:  api.Path{}.Pointer().Field("Description"),
+			A:     "a",
+			B:     "b",
+		}}},
+	})
+	tombstone.Plan().Set(rnode.PlanDetails{Operation: rnode.OpDelete, Why: "tombstone"})
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	t.Logf("json = %s", data)
+
+	g2 := &Graph{}
+	if err := json.Unmarshal(data, g2); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+
+	if len(g2.All()) != len(g.All()) {
+		t.Fatalf("len(g2.All()) = %d, want %d", len(g2.All()), len(g.All()))
+	}
+	for _, n := range g.All() {
+		n2 := g2.Get(n.ID())
+		if n2 == nil {
+			t.Errorf("node %s is missing", n.ID())
+			continue
+		}
+		if n2.State() != n.State() || n2.Ownership() != n.Ownership() {
+			t.Errorf("%s: state, ownership = %s, %s; want %s, %s", n.ID(), n2.State(), n2.Ownership(), n.State(), n.Ownership())
+		}
+		if !n2.FetchTime().Equal(n.FetchTime()) {
+			t.Errorf("%s: FetchTime() = %v, want %v", n.ID(), n2.FetchTime(), n.FetchTime())
+		}
+		if diff := cmp.Diff(n2.IgnoreDiffPaths(), n.IgnoreDiffPaths()); diff != "" {
+			t.Errorf("%s: IgnoreDiffPaths() diff -got,+want: %s", n.ID(), diff)
+		}
+		if len(n2.InRefs()) != len(n.InRefs()) || len(n2.OutRefs()) != len(n.OutRefs()) {
+			t.Errorf("%s: got %d InRefs, %d OutRefs; want %d, %d", n.ID(), len(n2.InRefs()), len(n2.OutRefs()), len(n.InRefs()), len(n.OutRefs()))
+		}
+		if n2.Plan().Op() != n.Plan().Op() || len(n2.Plan().History()) != len(n.Plan().History()) {
+			t.Errorf("%s: Plan() = %v, want %v", n.ID(), n2.Plan(), n.Plan())
+		}
+		if (n.Resource() == nil) != (n2.Resource() == nil) {
+			t.Errorf("%s: Resource() = %v, want %v", n.ID(), n2.Resource(), n.Resource())
+		}
+	}
+
+	r2, ok := g2.Get(hcsFrozen.ResourceID()).Resource().(healthcheckservice.HealthCheckService)
+	if !ok {
+		t.Fatalf("Resource() = %T, want HealthCheckService", g2.Get(hcsFrozen.ResourceID()).Resource())
+	}
+	if d, err := r2.Diff(hcsFrozen); err != nil || d.HasDiff() {
+		t.Errorf("restored resource Diff() = %+v, %v; want no diff", d, err)
+	}
+
+	// The serialization is stable.
+	data2, err := json.Marshal(g2)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	if diff := cmp.Diff(string(data2), string(data)); diff != "" {
+		t.Errorf("json.Marshal(restored) diff -got,+want: %s", diff)
+	}
+}
+
+func TestGraphJSONErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data string
+	}{
+		{name: "invalid json", data: `{`},
+		{name: "missing id", data: `{"nodes":[{"state":"Exists"}]}`},
+		{
+			name: "unregistered type",
+			data: `{"nodes":[{"id":{"Resource":"unknown","Key":{"Name":"x"}},"state":"Exists","ownership":"Managed"}]}`,
+		},
+		{
+			name: "missing reference",
+			data: `{"nodes":[{"id":{"Resource":"healthCheckServices","APIGroup":"compute","ProjectID":"p","Key":{"Name":"x","Region":"r"}},` +
+				`"state":"Exists","ownership":"Managed","resource":{` +
+				`"resourceID":{"Resource":"healthCheckServices","APIGroup":"compute","ProjectID":"p","Key":{"Name":"x","Region":"r"}},` +
+				`"version":"ga","ga":{"object":{"name":"x","notificationEndpoints":["projects/p/regions/r/notificationEndpoints/ne"]}}}}]}`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var g Graph
+			if err := json.Unmarshal([]byte(tc.data), &g); err == nil {
+				t.Errorf("json.Unmarshal() = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rlog has the conventions for structured logging (logr) in rgraph.
+//
+// The planner and the executor accept a logr.Logger (e.g. from a controller
+// using klog or zap) and log with the keys defined here so that the log lines
+// for a resource can be correlated across planning and execution.
+package rlog
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+)
+
+// Keys for the key/value pairs in the log lines.
+const (
+	// KeyNode is the ResourceID of the Node.
+	KeyNode = "node"
+	// KeyOp is the planned Operation or the ActionType that was run.
+	KeyOp = "op"
+	// KeyVersion is the API version of the resource.
+	KeyVersion = "version"
+	// KeyAttempt is the number of the attempt (e.g. the iteration of a
+	// reconcile loop).
+	KeyAttempt = "attempt"
+)
+
+// NewContext returns a copy of ctx with the Logger l. The planner and the
+// executor log to the Logger in their context unless they were given one
+// explicitly. This is the same as klog.NewContext() and logr.NewContext().
+func NewContext(ctx context.Context, l logr.Logger) context.Context {
+	return klog.NewContext(ctx, l)
+}
+
+// FromContext returns the Logger in ctx. If there is none, this returns the
+// global klog Logger (see klog.FromContext()).
+func FromContext(ctx context.Context) logr.Logger {
+	return klog.FromContext(ctx)
+}
+
+// OrContext returns l if it is set and FromContext(ctx) otherwise. The zero
+// logr.Logger is not set.
+func OrContext(ctx context.Context, l logr.Logger) logr.Logger {
+	if l.GetSink() == nil {
+		return FromContext(ctx)
+	}
+	return l
+}
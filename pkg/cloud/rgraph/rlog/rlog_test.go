@@ -0,0 +1,50 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rlog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func TestContext(t *testing.T) {
+	if l := FromContext(context.Background()); l.GetSink() == nil {
+		t.Fatalf("FromContext(empty).GetSink() = nil, want klog")
+	}
+	l := logr.Discard().WithName("test")
+	ctx := NewContext(context.Background(), l)
+	if got := FromContext(ctx); got != l {
+		t.Errorf("FromContext(NewContext(l)) = %v, want %v", got, l)
+	}
+	if got, err := logr.FromContext(ctx); err != nil || got != l {
+		t.Errorf("logr.FromContext(NewContext(l)) = %v, %v, want %v, nil", got, err, l)
+	}
+}
+
+func TestOrContext(t *testing.T) {
+	ctxLogger := logr.Discard().WithName("ctx")
+	ctx := NewContext(context.Background(), ctxLogger)
+	if got := OrContext(ctx, logr.Logger{}); got != ctxLogger {
+		t.Errorf("OrContext(ctx, zero) = %v, want %v", got, ctxLogger)
+	}
+	l := logr.Discard().WithName("explicit")
+	if got := OrContext(ctx, l); got != l {
+		t.Errorf("OrContext(ctx, l) = %v, want %v", got, l)
+	}
+}
@@ -0,0 +1,419 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+)
+
+// CreatePreconditions are the Events that must occur before the want Node
+// can be created: all of the resources it references must exist.
+func CreatePreconditions(want Node) exec.EventList {
+	var ret exec.EventList
+	for _, ref := range want.OutRefs() {
+		ret = append(ret, exec.NewExistsEvent(ref.To))
+	}
+	return ret
+}
+
+// DeletePreconditions are the Events that must occur before the got Node can
+// be deleted: all of the references to it must be removed.
+func DeletePreconditions(got Node) exec.EventList {
+	var ret exec.EventList
+	for _, ref := range got.InRefs() {
+		ret = append(ret, exec.NewDropRefEvent(ref.From, ref.To))
+	}
+	return ret
+}
+
+// UpdatePreconditions are the Events that must occur before the Node can be
+// updated: all of the resources referenced by want must exist.
+func UpdatePreconditions(got, want Node) exec.EventList {
+	return CreatePreconditions(want)
+}
+
+// UpdateEvents are signalled when an update completes: the resource exists
+// and references that are in got but not in want have been dropped.
+func UpdateEvents(got, want Node) exec.EventList {
+	ret := exec.EventList{exec.NewExistsEvent(want.ID())}
+	wantRefs := map[cloud.ResourceMapKey]bool{}
+	for _, ref := range want.OutRefs() {
+		wantRefs[ref.To.MapKey()] = true
+	}
+	for _, ref := range got.OutRefs() {
+		if !wantRefs[ref.To.MapKey()] {
+			ret = append(ret, exec.NewDropRefEvent(ref.From, ref.To))
+		}
+	}
+	return ret
+}
+
+// ExistsActions are the Actions for a Node that has no changes planned.
+func ExistsActions(want Node) []exec.Action {
+	if want.State() == NodeDoesNotExist {
+		return []exec.Action{exec.NewDoesNotExistAction(want.ID())}
+	}
+	return []exec.Action{exec.NewExistsAction(want.ID())}
+}
+
+// CreateActions returns the Actions to create the resource for want.
+func CreateActions[GA any, Alpha any, Beta any](
+	ops GenericOps[GA, Alpha, Beta],
+	got, want Node,
+	resource api.Resource[GA, Alpha, Beta],
+) ([]exec.Action, error) {
+	if resource == nil {
+		return nil, fmt.Errorf("CreateActions %s: resource is nil", want.ID())
+	}
+	return []exec.Action{
+		newGenericCreateAction(CreatePreconditions(want), ops, want, resource),
+	}, nil
+}
+
+// DeleteActions returns the Actions to delete the resource in got.
+func DeleteActions[GA any, Alpha any, Beta any](
+	ops GenericOps[GA, Alpha, Beta],
+	got, want Node,
+) ([]exec.Action, error) {
+	return []exec.Action{
+		NewGenericDeleteAction(DeletePreconditions(got), ops, got),
+	}, nil
+}
+
+// RecreateActions returns the Actions to delete and then create the
+// resource. The live resource cannot be deleted while it is in use, so the
+// delete waits for all of the references to it to be dropped, i.e. the
+// referrers are deleted, recreated or updated to no longer reference the
+// resource (see localplan.PlanWantGraph()).
+func RecreateActions[GA any, Alpha any, Beta any](
+	ops GenericOps[GA, Alpha, Beta],
+	got, want Node,
+	resource api.Resource[GA, Alpha, Beta],
+) ([]exec.Action, error) {
+	if resource == nil {
+		return nil, fmt.Errorf("RecreateActions %s: resource is nil", want.ID())
+	}
+	deleteEvents := DeletePreconditions(got)
+	deleteAction := NewGenericDeleteAction(deleteEvents, ops, got)
+	// The delete signals NotExists for the resource, which the create
+	// waits on.
+	createEvents := append(CreatePreconditions(want), exec.NewNotExistsEvent(want.ID()))
+	createAction := newGenericCreateAction(createEvents, ops, want, resource)
+
+	return []exec.Action{deleteAction, createAction}, nil
+}
+
+// NewGenericCreateAction returns an Action that creates the resource once
+// the want Events have been signalled.
+func NewGenericCreateAction[GA any, Alpha any, Beta any](
+	want exec.EventList,
+	ops GenericOps[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	resource api.Resource[GA, Alpha, Beta],
+) exec.Action {
+	return &genericCreateAction[GA, Alpha, Beta]{
+		ActionBase: exec.ActionBase{Want: want},
+		ops:        ops,
+		id:         id,
+		resource:   resource,
+	}
+}
+
+// newGenericCreateAction returns a create Action for the want Node. Unlike
+// NewGenericCreateAction, the Action can be resumed (see Resume()).
+func newGenericCreateAction[GA any, Alpha any, Beta any](
+	wantEvents exec.EventList,
+	ops GenericOps[GA, Alpha, Beta],
+	want Node,
+	resource api.Resource[GA, Alpha, Beta],
+) exec.Action {
+	return &genericCreateAction[GA, Alpha, Beta]{
+		ActionBase: exec.ActionBase{Want: wantEvents},
+		ops:        ops,
+		id:         want.ID(),
+		resource:   resource,
+		want:       want,
+	}
+}
+
+type genericCreateAction[GA any, Alpha any, Beta any] struct {
+	exec.ActionBase
+	ops      GenericOps[GA, Alpha, Beta]
+	id       *cloud.ResourceID
+	resource api.Resource[GA, Alpha, Beta]
+	// want is the Node being created. This is nil if the Action was not
+	// created from a Node.
+	want Node
+}
+
+// genericCreateAction is resumable.
+var _ exec.ResumableAction = (*genericCreateAction[any, any, any])(nil)
+
+func (a *genericCreateAction[GA, Alpha, Beta]) Run(ctx context.Context, gcp cloud.Cloud) (exec.EventList, error) {
+	if err := GenericCreate(ctx, gcp, a.id.Resource, a.ops, a.resource); err != nil {
+		return nil, err
+	}
+	return a.DryRun(), nil
+}
+
+// Resume checks if the resource already exists, e.g. created by a prior
+// execution that did not complete. An existing resource is only adopted if
+// adopt returns true for it; a resource that is not owned is not modified
+// and an error is returned. An adopted resource that matches want is
+// verified (no operation) or updated in place. A resource that would have to
+// be recreated to match want is not modified and an error is returned, as it
+// may not be the resource this Action would have created.
+func (a *genericCreateAction[GA, Alpha, Beta]) Resume(ctx context.Context, gcp cloud.Cloud, adopt exec.AdoptFunc) (exec.EventList, bool, error) {
+	if a.want == nil || a.want.Ownership() != OwnershipManaged {
+		return nil, false, nil
+	}
+	b := a.want.Builder()
+	if err := b.SyncFromCloud(ctx, gcp); err != nil {
+		return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+	}
+	if b.State() != NodeExists {
+		return nil, false, nil
+	}
+	got, err := b.Build()
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+	}
+	gotRes, ok := got.Resource().(api.Resource[GA, Alpha, Beta])
+	if !ok {
+		return nil, false, fmt.Errorf("%s: Resume: invalid resource type %T", a, got.Resource())
+	}
+	obj, err := apiObject(gotRes)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+	}
+	if adopt == nil || !adopt(a.id, obj) {
+		return nil, false, fmt.Errorf("%s: Resume: resource already exists and is not owned", a)
+	}
+	details, err := a.want.Diff(got)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+	}
+	rlog.FromContext(ctx).V(2).Info("Resource already exists, resuming", rlog.KeyNode, a.id.String(), rlog.KeyOp, string(details.Operation), "why", details.Why)
+
+	switch details.Operation {
+	case OpNothing:
+		return a.DryRun(), true, nil
+	case OpUpdate:
+		// Actions() are computed from the Plan of the Node. Plan the update
+		// on a copy to avoid changing the Plan of want.
+		wb, err := CloneBuilder(a.want)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+		}
+		w, err := wb.Build()
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+		}
+		w.Plan().Set(*details)
+		actions, err := w.Actions(got)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+		}
+		// The preconditions of the update are a subset of the create
+		// preconditions, which have been met.
+		for _, act := range actions {
+			if _, err := act.Run(ctx, gcp); err != nil {
+				return nil, false, fmt.Errorf("%s: Resume: %w", a, err)
+			}
+		}
+		return a.DryRun(), true, nil
+	}
+	return nil, false, fmt.Errorf("%s: Resume: existing resource cannot be updated to match (%s: %s)", a, details.Operation, details.Why)
+}
+
+func (a *genericCreateAction[GA, Alpha, Beta]) DryRun() exec.EventList {
+	return exec.EventList{exec.NewExistsEvent(a.id)}
+}
+
+// Calls implements exec.CallDescriber.
+func (a *genericCreateAction[GA, Alpha, Beta]) Calls() []exec.Call {
+	return []exec.Call{{
+		Method:  "Insert",
+		Version: a.resource.Version(),
+		ID:      a.id,
+		Body:    resourceBody(a.resource),
+	}}
+}
+
+// resourceBody returns the exec.BodySummary() of the API object for the
+// Version of r.
+func resourceBody[GA any, Alpha any, Beta any](r api.Resource[GA, Alpha, Beta]) string {
+	obj, err := apiObject(r)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return exec.BodySummary(obj)
+}
+
+// apiObject returns the API object (e.g. *compute.Address) for the Version
+// of r.
+func apiObject[GA any, Alpha any, Beta any](r api.Resource[GA, Alpha, Beta]) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		return r.ToGA()
+	case meta.VersionAlpha:
+		return r.ToAlpha()
+	case meta.VersionBeta:
+		return r.ToBeta()
+	}
+	return nil, fmt.Errorf("invalid version %q", r.Version())
+}
+
+func (a *genericCreateAction[GA, Alpha, Beta]) String() string {
+	return fmt.Sprintf("GenericCreateAction(%v)", a.id)
+}
+
+func (a *genericCreateAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("GenericCreateAction(%v)", a.id),
+		Type:       exec.ActionTypeCreate,
+		Summary:    fmt.Sprintf("Create %v", a.id),
+		ResourceID: a.id,
+	}
+}
+
+// NewGenericDeleteAction returns an Action that deletes the resource in got
+// once the want Events have been signalled.
+func NewGenericDeleteAction[GA any, Alpha any, Beta any](
+	want exec.EventList,
+	ops GenericOps[GA, Alpha, Beta],
+	got Node,
+) exec.Action {
+	ver := meta.VersionGA
+	if r := got.Resource(); r != nil {
+		ver = r.Version()
+	}
+	return &genericDeleteAction[GA, Alpha, Beta]{
+		ActionBase: exec.ActionBase{Want: want},
+		ops:        ops,
+		id:         got.ID(),
+		ver:        ver,
+		outRefs:    got.OutRefs(),
+	}
+}
+
+type genericDeleteAction[GA any, Alpha any, Beta any] struct {
+	exec.ActionBase
+	ops     GenericOps[GA, Alpha, Beta]
+	id      *cloud.ResourceID
+	ver     meta.Version
+	outRefs []ResourceRef
+}
+
+func (a *genericDeleteAction[GA, Alpha, Beta]) Run(ctx context.Context, gcp cloud.Cloud) (exec.EventList, error) {
+	if err := GenericDelete(ctx, gcp, a.id.Resource, a.ops, a.id, a.ver); err != nil {
+		return nil, err
+	}
+	return a.DryRun(), nil
+}
+
+// DryRun signals that the resource no longer exists and that the references
+// from the resource have been dropped.
+func (a *genericDeleteAction[GA, Alpha, Beta]) DryRun() exec.EventList {
+	ret := exec.EventList{exec.NewNotExistsEvent(a.id)}
+	for _, ref := range a.outRefs {
+		ret = append(ret, exec.NewDropRefEvent(ref.From, ref.To))
+	}
+	return ret
+}
+
+// Calls implements exec.CallDescriber.
+func (a *genericDeleteAction[GA, Alpha, Beta]) Calls() []exec.Call {
+	return []exec.Call{{Method: "Delete", Version: a.ver, ID: a.id}}
+}
+
+func (a *genericDeleteAction[GA, Alpha, Beta]) String() string {
+	return fmt.Sprintf("GenericDeleteAction(%v)", a.id)
+}
+
+func (a *genericDeleteAction[GA, Alpha, Beta]) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("GenericDeleteAction(%v)", a.id),
+		Type:       exec.ActionTypeDelete,
+		Summary:    fmt.Sprintf("Delete %v", a.id),
+		ResourceID: a.id,
+	}
+}
+
+// NewUpdateAction returns an Action that calls update once the want Events
+// have been signalled and signals events on success. This is used by Node
+// types to implement resource specific update methods. update is called in
+// the project of id (see cloud.WithProjectID()). calls describe the Cloud
+// calls made by update (see exec.CallDescriber).
+func NewUpdateAction(
+	want exec.EventList,
+	id *cloud.ResourceID,
+	summary string,
+	events exec.EventList,
+	update func(context.Context, cloud.Cloud) error,
+	calls ...exec.Call,
+) exec.Action {
+	return &updateAction{
+		ActionBase: exec.ActionBase{Want: want},
+		id:         id,
+		summary:    summary,
+		events:     events,
+		update:     update,
+		calls:      calls,
+	}
+}
+
+type updateAction struct {
+	exec.ActionBase
+	id      *cloud.ResourceID
+	summary string
+	events  exec.EventList
+	update  func(context.Context, cloud.Cloud) error
+	calls   []exec.Call
+}
+
+// Calls implements exec.CallDescriber.
+func (a *updateAction) Calls() []exec.Call { return a.calls }
+
+func (a *updateAction) Run(ctx context.Context, gcp cloud.Cloud) (exec.EventList, error) {
+	if err := a.update(withProjectID(ctx, a.id), gcp); err != nil {
+		return nil, err
+	}
+	return a.events, nil
+}
+
+func (a *updateAction) DryRun() exec.EventList { return a.events }
+
+func (a *updateAction) String() string {
+	return fmt.Sprintf("UpdateAction(%v)", a.id)
+}
+
+func (a *updateAction) Metadata() *exec.ActionMetadata {
+	return &exec.ActionMetadata{
+		Name:       fmt.Sprintf("UpdateAction(%v)", a.id),
+		Type:       exec.ActionTypeUpdate,
+		Summary:    a.summary,
+		ResourceID: a.id,
+	}
+}
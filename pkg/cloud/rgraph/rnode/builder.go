@@ -18,12 +18,15 @@ package rnode
 
 import (
 	"context"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 )
 
-// Builder is a Node in the graph Builder.
+// Builder is a Node in the graph Builder. Implementations must embed
+// BuilderBase.
 type Builder interface {
 	// ID uniquely identifying this resource.
 	ID() *cloud.ResourceID
@@ -47,6 +50,18 @@ type Builder interface {
 	// resource from the Cloud.
 	Version() meta.Version
 
+	// IgnoreDiffPaths are the fields that are ignored when computing the
+	// Diff for the Node (e.g. fields with values defaulted by the server).
+	IgnoreDiffPaths() []api.Path
+	// SetIgnoreDiffPaths for the Node.
+	SetIgnoreDiffPaths(paths []api.Path)
+
+	// FetchTime is when the resource was last fetched from the Cloud. This
+	// is zero if the resource has not been fetched.
+	FetchTime() time.Time
+	// SetFetchTime of the resource.
+	SetFetchTime(t time.Time)
+
 	// OutRefs parses the outgoing references of the Resource.
 	OutRefs() ([]ResourceRef, error)
 	// AddInRef to this node Builder.
@@ -73,6 +88,9 @@ type BuilderBase struct {
 	ownership OwnershipStatus
 	version   meta.Version
 
+	ignoreDiffPaths []api.Path
+	fetchTime       time.Time
+
 	curInRefs []ResourceRef
 }
 
@@ -83,6 +101,14 @@ func (b *BuilderBase) Ownership() OwnershipStatus      { return b.ownership }
 func (b *BuilderBase) SetOwnership(os OwnershipStatus) { b.ownership = os }
 func (b *BuilderBase) Version() meta.Version           { return b.version }
 
+func (b *BuilderBase) IgnoreDiffPaths() []api.Path { return b.ignoreDiffPaths }
+func (b *BuilderBase) SetIgnoreDiffPaths(paths []api.Path) {
+	b.ignoreDiffPaths = append([]api.Path{}, paths...)
+}
+
+func (b *BuilderBase) FetchTime() time.Time     { return b.fetchTime }
+func (b *BuilderBase) SetFetchTime(t time.Time) { b.fetchTime = t }
+
 func (b *BuilderBase) AddInRef(ref ResourceRef) { b.curInRefs = append(b.curInRefs, ref) }
 func (b *BuilderBase) inRefs() []ResourceRef    { return b.curInRefs }
 
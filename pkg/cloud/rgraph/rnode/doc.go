@@ -0,0 +1,41 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rnode defines the Nodes in the resource graph (rgraph) and the
+// generic helpers used to implement them.
+//
+// Each kind of resource (e.g. compute targetSslProxies) has a node type,
+// consisting of:
+//
+//   - A Builder, embedding BuilderBase, that fetches the resource from the
+//     Cloud (SyncFromCloud) and parses its references to other resources
+//     (OutRefs).
+//   - A Node, embedding NodeBase, that compares the wanted resource to the
+//     current one (Diff) and returns the exec.Actions that carry out its
+//     Plan (Actions).
+//   - A registration with RegisterNodeType(), typically from init(), so that
+//     Graphs containing the type can be restored (see NewBuilderForID()).
+//     The registration checks the FieldTraits against the resource types.
+//
+// Node types do not need to be part of this repository. An external package
+// can implement a node type for a resource that is not supported here (e.g.
+// networkservices meshes) using the same helpers as the subpackages of
+// rnode: GenericOps and the generic Actions (CreateActions(),
+// DeleteActions(), ...) for the Cloud operations, and NewUpdateAction() for
+// resource-specific updates. Resources whose API is not part of cloud.Cloud
+// can be handled with a GenericOps that uses its own client and ignores the
+// cloud.Cloud argument.
+package rnode
@@ -42,6 +42,7 @@ func (n *fakeNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
 	if err != nil {
 		return nil, fmt.Errorf("fakeNode %s: Diff %w", n.ID(), err)
 	}
+	diff = n.IgnoreDiff(diff)
 	if diff.HasDiff() {
 		return &rnode.PlanDetails{
 			Operation: rnode.OpUpdate,
@@ -78,5 +79,6 @@ func (n *fakeNode) Actions(got rnode.Node) ([]exec.Action, error) {
 func (n *fakeNode) Builder() rnode.Builder {
 	b := &Builder{}
 	b.Init(n.ID(), n.State(), n.Ownership(), nil)
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
 	return b
 }
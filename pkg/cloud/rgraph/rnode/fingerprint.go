@@ -0,0 +1,41 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import "fmt"
+
+// Fingerprint returns a digest of the state of n (see api.Resource.Hash()).
+// Nodes with the same Fingerprint have the same state, ownership and
+// resource values, ignoring OutputOnly and System fields. This is used to
+// detect that a Node has not changed between two syncs so that the prior
+// result can be reused.
+func Fingerprint(n Node) (string, error) {
+	ret := fmt.Sprintf("%s/%s/%v", n.State(), n.Ownership(), n.IgnoreDiffPaths())
+	r := n.Resource()
+	if r == nil {
+		return ret, nil
+	}
+	hr, ok := r.(interface{ Hash() (string, error) })
+	if !ok {
+		return "", fmt.Errorf("Fingerprint %s: resource %T cannot be hashed", n.ID(), r)
+	}
+	h, err := hr.Hash()
+	if err != nil {
+		return "", fmt.Errorf("Fingerprint %s: %w", n.ID(), err)
+	}
+	return ret + "/" + h, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+func init() {
+	rnode.RegisterNodeType[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](meta.APIGroupCompute, resourcePlural, NewBuilder, &typeTrait{})
+}
+
+// NewBuilder returns a Builder for the ForwardingRule id.
+func NewBuilder(id *cloud.ResourceID) rnode.Builder {
+	b := &builder{}
+	b.Defaults(id)
+	return b
+}
+
+// NewBuilderWithResource returns a Builder for the resource r.
+func NewBuilderWithResource(r ForwardingRule) rnode.Builder {
+	b := &builder{resource: r}
+	b.Init(r.ResourceID(), rnode.NodeUnknown, rnode.OwnershipUnknown, r)
+	return b
+}
+
+type builder struct {
+	rnode.BuilderBase
+	resource ForwardingRule
+}
+
+// builder implements rnode.Builder.
+var _ rnode.Builder = (*builder)(nil)
+
+func (b *builder) Resource() rnode.UntypedResource {
+	if b.resource == nil {
+		return nil
+	}
+	return b.resource
+}
+
+func (b *builder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.(ForwardingRule)
+	if !ok {
+		return fmt.Errorf("ForwardingRule: invalid type for SetResource: %T", u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *builder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	return rnode.GenericGetBuilder[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](ctx, gcp, "ForwardingRule", &ops{}, &typeTrait{}, b)
+}
+
+func (b *builder) OutRefs() ([]rnode.ResourceRef, error) {
+	if b.resource == nil {
+		return nil, nil
+	}
+	// The Target references one of targetResources, so the type is not
+	// checked as in rnode.GenericOutRefs().
+	refs, err := b.resource.References()
+	if err != nil {
+		return nil, fmt.Errorf("ForwardingRule %s: OutRefs: %w", b.ID(), err)
+	}
+	var ret []rnode.ResourceRef
+	for _, ref := range refs {
+		isTarget := ref.Path.Equal(targetPath)
+		if isTarget && !strings.Contains(ref.URL, "/") {
+			// Private Service Connect for Google APIs targets a bundle
+			// (e.g. "all-apis"), not a resource.
+			continue
+		}
+		rr, err := rnode.ParseRef(b.ID(), ref.Path, ref.URL)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case isTarget && !targetResources[rr.To.Resource]:
+			return nil, fmt.Errorf("ForwardingRule %s: reference in %s is to %q, which cannot be a Target", b.ID(), ref.Path, rr.To.Resource)
+		case !isTarget && rr.To.Resource != ref.Resource:
+			return nil, fmt.Errorf("ForwardingRule %s: reference in %s is to %q, want %q", b.ID(), ref.Path, rr.To.Resource, ref.Resource)
+		}
+		ret = append(ret, rr)
+	}
+	return ret, nil
+}
+
+func (b *builder) Build() (rnode.Node, error) {
+	if b.State() == rnode.NodeExists && b.resource == nil {
+		return nil, fmt.Errorf("ForwardingRule %s: resource must be set if the node exists", b.ID())
+	}
+	ret := &forwardingRuleNode{resource: b.resource}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj       = "proj-1"
+	region     = "us-central1"
+	saURL      = "https://www.googleapis.com/compute/v1/projects/producer/regions/us-central1/serviceAttachments/sa"
+	networkURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/networks/net"
+	subnetURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/subnetworks/subnet"
+	ipURL      = "10.0.0.5"
+)
+
+func newResource(t *testing.T, key *meta.Key, f func(*compute.ForwardingRule)) ForwardingRule {
+	t.Helper()
+	return newResourceWithAlpha(t, key, f, nil)
+}
+
+// newResourceWithAlpha returns a resource with fAlpha applied to the alpha
+// version after f. fAlpha is optional.
+func newResourceWithAlpha(t *testing.T, key *meta.Key, f func(*compute.ForwardingRule), fAlpha func(*alpha.ForwardingRule)) ForwardingRule {
+	t.Helper()
+	r := NewMutableForwardingRule(proj, key)
+	if err := r.Access(func(x *compute.ForwardingRule) {
+		x.Name = key.Name
+		x.IPAddress = ipURL
+		x.Network = networkURL
+		x.Target = saURL
+		x.ForceSendFields = []string{
+			"AllPorts", "AllowGlobalAccess", "BackendService", "Description", "IPProtocol", "IpVersion",
+			"IsMirroringCollector", "Labels", "LoadBalancingScheme", "MetadataFilters", "NetworkTier",
+			"NoAutomateDnsZone", "PortRange", "Ports", "ServiceDirectoryRegistrations", "ServiceLabel",
+			"SourceIpRanges", "Subnetwork",
+		}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	if fAlpha != nil {
+		if err := r.AccessAlpha(fAlpha); err != nil {
+			t.Fatalf("AccessAlpha() = %v", err)
+		}
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return fr
+}
+
+func newNode(t *testing.T, key *meta.Key, f func(*compute.ForwardingRule)) rnode.Node {
+	t.Helper()
+	return newNodeWithAlpha(t, key, f, nil)
+}
+
+func newNodeWithAlpha(t *testing.T, key *meta.Key, f func(*compute.ForwardingRule), fAlpha func(*alpha.ForwardingRule)) rnode.Node {
+	t.Helper()
+	return ez.ManagedNode(newResourceWithAlpha(t, key, f, fAlpha), NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		f       func(*compute.ForwardingRule)
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "service attachment",
+			f:    func(x *compute.ForwardingRule) { x.Subnetwork = subnetURL },
+			want: []string{
+				"*.Network => networks",
+				"*.Subnetwork => subnetworks",
+				"*.Target => serviceAttachments",
+			},
+		},
+		{
+			name: "target proxy",
+			f: func(x *compute.ForwardingRule) {
+				x.Target = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/targetHttpProxies/thp"
+			},
+			want: []string{
+				"*.Network => networks",
+				"*.Target => targetHttpProxies",
+			},
+		},
+		{
+			// Private Service Connect for Google APIs.
+			name: "google apis bundle",
+			f:    func(x *compute.ForwardingRule) { x.Target = "all-apis" },
+			want: []string{"*.Network => networks"},
+		},
+		{
+			name: "invalid target",
+			f: func(x *compute.ForwardingRule) {
+				x.Target = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid network",
+			f: func(x *compute.ForwardingRule) {
+				x.Network = subnetURL
+			},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// Build() fails on invalid references so the Builder is used.
+			refs, err := NewBuilderWithResource(newResource(t, meta.RegionalKey("fr", region), tc.f)).OutRefs()
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("OutRefs() = %v; gotErr = %t, want %t", err, gotErr, tc.wantErr)
+			}
+			var got []string
+			for _, ref := range refs {
+				got = append(got, ref.Path.String()+" => "+ref.To.Resource)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("OutRefs(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		key    *meta.Key
+		f      func(*compute.ForwardingRule)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", key: meta.RegionalKey("fr", region), wantOp: rnode.OpNothing},
+		{
+			name:   "target",
+			key:    meta.RegionalKey("fr", region),
+			f:      func(x *compute.ForwardingRule) { x.Target = saURL + "2" },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "labels",
+			key:    meta.GlobalKey("fr"),
+			f:      func(x *compute.ForwardingRule) { x.Labels = map[string]string{"k": "v"} },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "ip address",
+			key:    meta.RegionalKey("fr", region),
+			f:      func(x *compute.ForwardingRule) { x.IPAddress = "10.0.0.6" },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name:   "network",
+			key:    meta.RegionalKey("fr", region),
+			f:      func(x *compute.ForwardingRule) { x.Network = networkURL + "2" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNode(t, tc.key, nil)
+			pd, err := newNode(t, tc.key, tc.f).Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %v, want op %v", pd, tc.wantOp)
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	// AllowPscGlobalAccess is only in the alpha API.
+	alphaOnly := func(x *alpha.ForwardingRule) {
+		x.AllowPscGlobalAccess = true
+		x.ForceSendFields = append(x.ForceSendFields, "SelfLinkWithId")
+	}
+	for _, tc := range []struct {
+		name   string
+		key    *meta.Key
+		f      func(*compute.ForwardingRule)
+		fAlpha func(*alpha.ForwardingRule)
+		want   []string
+	}{
+		{
+			name: "regional",
+			key:  meta.RegionalKey("fr", region),
+			f: func(x *compute.ForwardingRule) {
+				x.Labels = map[string]string{"k": "v"}
+				x.Target = saURL + "2"
+			},
+			want: []string{
+				`SetLabels ga compute/forwardingRules:proj-1/us-central1/fr {"labels":{"k":"v"}}`,
+				`SetTarget ga compute/forwardingRules:proj-1/us-central1/fr {"target":"` + saURL + `2"}`,
+			},
+		},
+		{
+			name: "global",
+			key:  meta.GlobalKey("fr"),
+			f: func(x *compute.ForwardingRule) {
+				x.Target = "https://www.googleapis.com/compute/v1/projects/proj-1/global/targetHttpProxies/thp"
+			},
+			want: []string{
+				`SetTarget ga compute/forwardingRules:proj-1/fr {"target":"https://www.googleapis.com/compute/v1/projects/proj-1/global/targetHttpProxies/thp"}`,
+			},
+		},
+		{
+			name: "alpha",
+			key:  meta.RegionalKey("fr", region),
+			f: func(x *compute.ForwardingRule) {
+				x.Labels = map[string]string{"k": "v"}
+			},
+			fAlpha: alphaOnly,
+			want: []string{
+				`SetLabels alpha compute/forwardingRules:proj-1/us-central1/fr {"labels":{"k":"v"}}`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNodeWithAlpha(t, tc.key, nil, tc.fAlpha)
+			want := newNodeWithAlpha(t, tc.key, tc.f, tc.fAlpha)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			want.Plan().Set(*pd)
+			actions, err := want.Actions(got)
+			if err != nil {
+				t.Fatalf("Actions() = %v, want nil", err)
+			}
+			var gotCalls []string
+			for _, a := range actions {
+				if cd, ok := a.(exec.CallDescriber); ok {
+					for _, c := range cd.Calls() {
+						gotCalls = append(gotCalls, c.String())
+					}
+				}
+			}
+			if diff := cmp.Diff(gotCalls, tc.want); diff != "" {
+				t.Errorf("Calls(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// updatableFields can be changed without recreating the resource. Each field
+// is updated with the corresponding Set*() method.
+var updatableFields = []string{
+	"Labels",
+	"Target",
+}
+
+type forwardingRuleNode struct {
+	rnode.NodeBase
+	resource ForwardingRule
+}
+
+var _ rnode.Node = (*forwardingRuleNode)(nil)
+
+func (n *forwardingRuleNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *forwardingRuleNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*forwardingRuleNode)
+	if !ok {
+		return nil, fmt.Errorf("ForwardingRuleNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("ForwardingRuleNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+// updatableField returns the name of the updatable top-level field that
+// contains p. Returns "" if the field cannot be updated.
+func updatableField(p api.Path) string {
+	for _, f := range updatableFields {
+		if p.HasPrefix(api.Path{}.Pointer().Field(f)) {
+			return f
+		}
+	}
+	return ""
+}
+
+func (n *forwardingRuleNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("ForwardingRuleNode: invalid plan op %s", op)
+}
+
+func (n *forwardingRuleNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("ForwardingRuleNode: update %s: plan has no diff", n.ID())
+	}
+	changed := map[string]bool{}
+	for _, item := range details.Diff.Items {
+		field := updatableField(item.Path)
+		if field == "" {
+			return nil, fmt.Errorf("ForwardingRuleNode: update %s: field %s cannot be updated", n.ID(), item.Path)
+		}
+		changed[field] = true
+	}
+	// SetLabels() requires the label fingerprint of the current resource.
+	gotRes, ok := got.Resource().(ForwardingRule)
+	if !ok || gotRes == nil {
+		return nil, fmt.Errorf("ForwardingRuleNode: update %s: got has no resource", n.ID())
+	}
+	gotGA, err := gotRes.ToGA()
+	if gotGA == nil {
+		return nil, fmt.Errorf("ForwardingRuleNode: update %s: %w", n.ID(), err)
+	}
+	r := n.resource
+	key := n.ID().Key
+	regional := key.Type() == meta.Regional
+	labelFingerprint := gotGA.LabelFingerprint
+	var (
+		fields []string
+		reqs   []any
+	)
+	for _, f := range updatableFields {
+		if !changed[f] {
+			continue
+		}
+		req, err := setRequest(r, f, regional, labelFingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("ForwardingRuleNode: update %s: %w", n.ID(), err)
+		}
+		fields = append(fields, f)
+		reqs = append(reqs, req)
+	}
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		for i, f := range fields {
+			if err := callSet(ctx, gcp, key, reqs[i]); err != nil {
+				return fmt.Errorf("ForwardingRule %s: Set%s: %w", n.ID(), f, err)
+			}
+		}
+		return nil
+	}
+	var calls []exec.Call
+	for i, f := range fields {
+		calls = append(calls, exec.Call{
+			Method:  "Set" + f,
+			Version: r.Version(),
+			ID:      n.ID(),
+			Body:    exec.BodySummary(reqs[i]),
+		})
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (%s)", n.ID(), strings.Join(fields, ", ")),
+			rnode.UpdateEvents(got, n),
+			update,
+			calls...,
+		),
+	}, nil
+}
+
+// setRequest returns the request for the Set*() method that updates field f
+// at the version of r.
+func setRequest(r ForwardingRule, f string, regional bool, labelFingerprint string) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "Labels":
+			if regional {
+				return &compute.RegionSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+			}
+			return &compute.GlobalSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+		case "Target":
+			return &compute.TargetReference{Target: obj.Target}, nil
+		}
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "Labels":
+			if regional {
+				return &alpha.RegionSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+			}
+			return &alpha.GlobalSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+		case "Target":
+			return &alpha.TargetReference{Target: obj.Target}, nil
+		}
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "Labels":
+			if regional {
+				return &beta.RegionSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+			}
+			return &beta.GlobalSetLabelsRequest{Labels: obj.Labels, LabelFingerprint: labelFingerprint}, nil
+		case "Target":
+			return &beta.TargetReference{Target: obj.Target}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s cannot be set for version %q", f, r.Version())
+}
+
+// callSet calls the Set*() method for the request req on the global or
+// regional forwarding rule key. The method and the API version are given by
+// the type of req (see setRequest()).
+func callSet(ctx context.Context, gcp cloud.Cloud, key *meta.Key, req any) error {
+	regional := key.Type() == meta.Regional
+	switch req := req.(type) {
+	case *compute.RegionSetLabelsRequest:
+		return gcp.ForwardingRules().SetLabels(ctx, key, req)
+	case *compute.GlobalSetLabelsRequest:
+		return gcp.GlobalForwardingRules().SetLabels(ctx, key, req)
+	case *compute.TargetReference:
+		if regional {
+			return gcp.ForwardingRules().SetTarget(ctx, key, req)
+		}
+		return gcp.GlobalForwardingRules().SetTarget(ctx, key, req)
+	case *alpha.RegionSetLabelsRequest:
+		return gcp.AlphaForwardingRules().SetLabels(ctx, key, req)
+	case *alpha.GlobalSetLabelsRequest:
+		return gcp.AlphaGlobalForwardingRules().SetLabels(ctx, key, req)
+	case *alpha.TargetReference:
+		if regional {
+			return gcp.AlphaForwardingRules().SetTarget(ctx, key, req)
+		}
+		return gcp.AlphaGlobalForwardingRules().SetTarget(ctx, key, req)
+	case *beta.RegionSetLabelsRequest:
+		return gcp.BetaForwardingRules().SetLabels(ctx, key, req)
+	case *beta.GlobalSetLabelsRequest:
+		return gcp.BetaGlobalForwardingRules().SetLabels(ctx, key, req)
+	case *beta.TargetReference:
+		if regional {
+			return gcp.BetaForwardingRules().SetTarget(ctx, key, req)
+		}
+		return gcp.BetaGlobalForwardingRules().SetTarget(ctx, key, req)
+	}
+	return fmt.Errorf("invalid request type %T", req)
+}
+
+func (n *forwardingRuleNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
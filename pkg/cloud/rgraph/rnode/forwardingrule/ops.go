@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package forwardingrule
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule] {
+	return &rnode.GetFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]{
+		GA:    rnode.GetByScope(gcp.GlobalForwardingRules().Get, gcp.ForwardingRules().Get),
+		Alpha: rnode.GetByScope(gcp.AlphaGlobalForwardingRules().Get, gcp.AlphaForwardingRules().Get),
+		Beta:  rnode.GetByScope(gcp.BetaGlobalForwardingRules().Get, gcp.BetaForwardingRules().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule] {
+	return &rnode.CreateFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]{
+		GA:    rnode.CreateByScope(gcp.GlobalForwardingRules().Insert, gcp.ForwardingRules().Insert),
+		Alpha: rnode.CreateByScope(gcp.AlphaGlobalForwardingRules().Insert, gcp.AlphaForwardingRules().Insert),
+		Beta:  rnode.CreateByScope(gcp.BetaGlobalForwardingRules().Insert, gcp.BetaForwardingRules().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule] {
+	return &rnode.DeleteFuncs[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]{
+		GA:    rnode.DeleteByScope(gcp.GlobalForwardingRules().Delete, gcp.ForwardingRules().Delete),
+		Alpha: rnode.DeleteByScope(gcp.AlphaGlobalForwardingRules().Delete, gcp.AlphaForwardingRules().Delete),
+		Beta:  rnode.DeleteByScope(gcp.BetaGlobalForwardingRules().Delete, gcp.BetaForwardingRules().Delete),
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package forwardingrule implements the rnode for global and regional
+// ForwardingRule resources.
+//
+// The Target of a ForwardingRule is one of several types of resources (e.g.
+// a target proxy or, for a Private Service Connect consumer, a
+// ServiceAttachment; see package serviceattachment). Targets that are not
+// resources (the Google APIs bundles "all-apis" and "vpc-sc") are not
+// references.
+package forwardingrule
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "forwardingRules"
+
+// ForwardingRule is the frozen resource type.
+type ForwardingRule = api.Resource[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]
+
+// MutableForwardingRule is the mutable resource type.
+type MutableForwardingRule = api.MutableResource[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]
+
+// ID of the ForwardingRule resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableForwardingRule returns a new mutable ForwardingRule.
+func NewMutableForwardingRule(project string, key *meta.Key) MutableForwardingRule {
+	return api.NewResource[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.ForwardingRule, alpha.ForwardingRule, beta.ForwardingRule]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	if v == meta.VersionAlpha {
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+	}
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	dt.System(api.Path{}.Pointer().Field("LabelFingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("BaseForwardingRule"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("PscConnectionId"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("PscConnectionStatus"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("ServiceName"))
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("Ports"))
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("SourceIpRanges"))
+	// An ephemeral address and the network tier are assigned by the Cloud if
+	// they are not set.
+	dt.Compare(api.Path{}.Pointer().Field("IPAddress"), compareAssigned)
+	dt.Compare(api.Path{}.Pointer().Field("NetworkTier"), compareAssigned)
+	// Fields other than the updatableFields cannot be changed in place.
+	for _, f := range immutableFields {
+		dt.Immutable(api.Path{}.Pointer().Field(f))
+	}
+	if v != meta.VersionGA {
+		dt.Immutable(api.Path{}.Pointer().Field("AllowPscGlobalAccess"))
+	}
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("BackendService"), "backendServices")
+	dt.Reference(api.Path{}.Pointer().Field("Network"), "networks")
+	dt.Reference(api.Path{}.Pointer().Field("Subnetwork"), "subnetworks")
+	// The resource type of the Target is checked in builder.OutRefs().
+	dt.Reference(targetPath, "")
+
+	return dt
+}
+
+// immutableFields are the fields (in all versions) that cannot be changed
+// without recreating the resource.
+var immutableFields = []string{
+	"AllPorts",
+	"AllowGlobalAccess",
+	"BackendService",
+	"Description",
+	"IPAddress",
+	"IPProtocol",
+	"IpVersion",
+	"IsMirroringCollector",
+	"LoadBalancingScheme",
+	"MetadataFilters",
+	"Name",
+	"Network",
+	"NetworkTier",
+	"NoAutomateDnsZone",
+	"PortRange",
+	"Ports",
+	"ServiceDirectoryRegistrations",
+	"ServiceLabel",
+	"SourceIpRanges",
+	"Subnetwork",
+}
+
+var targetPath = api.Path{}.Pointer().Field("Target")
+
+// targetResources are the types of resources that can be the Target.
+var targetResources = map[string]bool{
+	"serviceAttachments": true,
+	"targetGrpcProxies":  true,
+	"targetHttpProxies":  true,
+	"targetHttpsProxies": true,
+	"targetInstances":    true,
+	"targetPools":        true,
+	"targetSslProxies":   true,
+	"targetTcpProxies":   true,
+	"targetVpnGateways":  true,
+}
+
+// compareAssigned compares fields that are assigned by the Cloud if they are
+// not set. An empty value in want matches the assigned value in got.
+func compareAssigned(a, b any) bool {
+	return a == "" || b == "" || a == b
+}
@@ -0,0 +1,334 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+)
+
+// GenericOps are the typed Cloud operations for a resource type. These are
+// used by the generic implementations of Get, Create and Delete shared by the
+// Node types.
+type GenericOps[GA any, Alpha any, Beta any] interface {
+	GetFuncs(gcp cloud.Cloud) *GetFuncs[GA, Alpha, Beta]
+	CreateFuncs(gcp cloud.Cloud) *CreateFuncs[GA, Alpha, Beta]
+	DeleteFuncs(gcp cloud.Cloud) *DeleteFuncs[GA, Alpha, Beta]
+}
+
+// GetFuncs are the Get methods by API version. Funcs are nil if the version
+// is not supported.
+type GetFuncs[GA any, Alpha any, Beta any] struct {
+	GA    func(context.Context, *meta.Key) (*GA, error)
+	Alpha func(context.Context, *meta.Key) (*Alpha, error)
+	Beta  func(context.Context, *meta.Key) (*Beta, error)
+}
+
+// CreateFuncs are the Insert methods by API version. Funcs are nil if the
+// version is not supported.
+type CreateFuncs[GA any, Alpha any, Beta any] struct {
+	GA    func(context.Context, *meta.Key, *GA) error
+	Alpha func(context.Context, *meta.Key, *Alpha) error
+	Beta  func(context.Context, *meta.Key, *Beta) error
+}
+
+// DeleteFuncs are the Delete methods by API version. Funcs are nil if the
+// version is not supported.
+type DeleteFuncs[GA any, Alpha any, Beta any] struct {
+	GA    func(context.Context, *meta.Key) error
+	Alpha func(context.Context, *meta.Key) error
+	Beta  func(context.Context, *meta.Key) error
+}
+
+// GetByScope returns a Get method that calls global for global keys and
+// regional for regional keys. This is used in the GetFuncs of resources with
+// global and regional variants (e.g. urlMaps). global or regional may be nil
+// if the scope is not supported.
+func GetByScope[T any](global, regional func(context.Context, *meta.Key) (*T, error)) func(context.Context, *meta.Key) (*T, error) {
+	return func(ctx context.Context, key *meta.Key) (*T, error) {
+		f := global
+		switch key.Type() {
+		case meta.Regional:
+			f = regional
+		case meta.Zonal:
+			f = nil
+		}
+		if f == nil {
+			return nil, errUnsupportedScope(key)
+		}
+		return f(ctx, key)
+	}
+}
+
+// CreateByScope is GetByScope() for the Insert methods.
+func CreateByScope[T any](global, regional func(context.Context, *meta.Key, *T) error) func(context.Context, *meta.Key, *T) error {
+	return func(ctx context.Context, key *meta.Key, obj *T) error {
+		f := global
+		switch key.Type() {
+		case meta.Regional:
+			f = regional
+		case meta.Zonal:
+			f = nil
+		}
+		if f == nil {
+			return errUnsupportedScope(key)
+		}
+		return f(ctx, key, obj)
+	}
+}
+
+// DeleteByScope is GetByScope() for the Delete methods.
+func DeleteByScope(global, regional func(context.Context, *meta.Key) error) func(context.Context, *meta.Key) error {
+	return func(ctx context.Context, key *meta.Key) error {
+		f := global
+		switch key.Type() {
+		case meta.Regional:
+			f = regional
+		case meta.Zonal:
+			f = nil
+		}
+		if f == nil {
+			return errUnsupportedScope(key)
+		}
+		return f(ctx, key)
+	}
+}
+
+func errUnsupportedScope(key *meta.Key) error {
+	return fmt.Errorf("%s resources are not supported (key %s)", key.Type(), key)
+}
+
+// withProjectID returns ctx with the project of id (see
+// cloud.WithProjectID()) so that the calls for resources in other projects
+// are routed to their project by a cloud.ContextProjectRouter.
+func withProjectID(ctx context.Context, id *cloud.ResourceID) context.Context {
+	if id == nil || id.ProjectID == "" {
+		return ctx
+	}
+	return cloud.WithProjectID(ctx, id.ProjectID)
+}
+
+// GenericGet fetches the resource id from the Cloud at version ver. The call
+// is made in the project of id (see withProjectID()).
+func GenericGet[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	gcp cloud.Cloud,
+	resourceName string,
+	ops GenericOps[GA, Alpha, Beta],
+	typeTrait api.TypeTrait[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	ver meta.Version,
+) (api.Resource[GA, Alpha, Beta], error) {
+	rlog.FromContext(ctx).V(5).Info("GenericGet", rlog.KeyNode, id.String(), rlog.KeyVersion, string(ver))
+
+	ctx = withProjectID(ctx, id)
+	r := api.NewResource[GA, Alpha, Beta](id, typeTrait)
+	funcs := ops.GetFuncs(gcp)
+	errUnsupported := fmt.Errorf("%s: Get %s not supported for version %s", resourceName, id, ver)
+
+	switch ver {
+	case meta.VersionGA:
+		if funcs.GA == nil {
+			return nil, errUnsupported
+		}
+		obj, err := funcs.GA(ctx, id.Key)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.Set(obj); err != nil {
+			return nil, fmt.Errorf("%s: Get %s: %w", resourceName, id, err)
+		}
+	case meta.VersionAlpha:
+		if funcs.Alpha == nil {
+			return nil, errUnsupported
+		}
+		obj, err := funcs.Alpha(ctx, id.Key)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.SetAlpha(obj); err != nil {
+			return nil, fmt.Errorf("%s: Get %s: %w", resourceName, id, err)
+		}
+	case meta.VersionBeta:
+		if funcs.Beta == nil {
+			return nil, errUnsupported
+		}
+		obj, err := funcs.Beta(ctx, id.Key)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.SetBeta(obj); err != nil {
+			return nil, fmt.Errorf("%s: Get %s: %w", resourceName, id, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: Get %s: invalid version %q", resourceName, id, ver)
+	}
+
+	return r.Freeze()
+}
+
+// GenericGetBuilder fetches the resource for the Builder b from the Cloud
+// and sets the state of b accordingly. A resource that is not found is not
+// an error; the Builder state is set to NodeDoesNotExist.
+func GenericGetBuilder[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	gcp cloud.Cloud,
+	resourceName string,
+	ops GenericOps[GA, Alpha, Beta],
+	typeTrait api.TypeTrait[GA, Alpha, Beta],
+	b Builder,
+) error {
+	r, err := GenericGet(ctx, gcp, resourceName, ops, typeTrait, b.ID(), b.Version())
+	switch {
+	case cloud.IsNotFound(err):
+		b.SetState(NodeDoesNotExist)
+		return nil
+	case err != nil:
+		b.SetState(NodeStateError)
+		return err
+	}
+	b.SetState(NodeExists)
+	return b.SetResource(r)
+}
+
+// GenericCreate creates the resource in the Cloud using the version of the
+// resource. The call is made in the project of the resource (see
+// withProjectID()).
+func GenericCreate[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	gcp cloud.Cloud,
+	resourceName string,
+	ops GenericOps[GA, Alpha, Beta],
+	r api.Resource[GA, Alpha, Beta],
+) error {
+	id := r.ResourceID()
+	rlog.FromContext(ctx).V(5).Info("GenericCreate", rlog.KeyNode, id.String(), rlog.KeyVersion, string(r.Version()))
+
+	ctx = withProjectID(ctx, id)
+
+	funcs := ops.CreateFuncs(gcp)
+	errUnsupported := fmt.Errorf("%s: Create %s not supported for version %s", resourceName, id, r.Version())
+
+	switch r.Version() {
+	case meta.VersionGA:
+		if funcs.GA == nil {
+			return errUnsupported
+		}
+		obj, err := r.ToGA()
+		if err != nil {
+			return fmt.Errorf("%s: Create %s: %w", resourceName, id, err)
+		}
+		return funcs.GA(ctx, id.Key, obj)
+	case meta.VersionAlpha:
+		if funcs.Alpha == nil {
+			return errUnsupported
+		}
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return fmt.Errorf("%s: Create %s: %w", resourceName, id, err)
+		}
+		return funcs.Alpha(ctx, id.Key, obj)
+	case meta.VersionBeta:
+		if funcs.Beta == nil {
+			return errUnsupported
+		}
+		obj, err := r.ToBeta()
+		if err != nil {
+			return fmt.Errorf("%s: Create %s: %w", resourceName, id, err)
+		}
+		return funcs.Beta(ctx, id.Key, obj)
+	}
+	return fmt.Errorf("%s: Create %s: invalid version %q", resourceName, id, r.Version())
+}
+
+// GenericDelete deletes the resource id from the Cloud using version ver. The
+// call is made in the project of id (see withProjectID()).
+func GenericDelete[GA any, Alpha any, Beta any](
+	ctx context.Context,
+	gcp cloud.Cloud,
+	resourceName string,
+	ops GenericOps[GA, Alpha, Beta],
+	id *cloud.ResourceID,
+	ver meta.Version,
+) error {
+	rlog.FromContext(ctx).V(5).Info("GenericDelete", rlog.KeyNode, id.String(), rlog.KeyVersion, string(ver))
+
+	ctx = withProjectID(ctx, id)
+
+	funcs := ops.DeleteFuncs(gcp)
+	var f func(context.Context, *meta.Key) error
+	switch ver {
+	case meta.VersionGA:
+		f = funcs.GA
+	case meta.VersionAlpha:
+		f = funcs.Alpha
+	case meta.VersionBeta:
+		f = funcs.Beta
+	default:
+		return fmt.Errorf("%s: Delete %s: invalid version %q", resourceName, id, ver)
+	}
+	if f == nil {
+		return fmt.Errorf("%s: Delete %s not supported for version %s", resourceName, id, ver)
+	}
+	return f(ctx, id.Key)
+}
+
+// GenericOutRefs returns the references in the fields of r declared with
+// FieldTraits.Reference(). Returns an error if a reference is to a different
+// type of resource than declared in the trait.
+func GenericOutRefs[GA any, Alpha any, Beta any](r api.Resource[GA, Alpha, Beta]) ([]ResourceRef, error) {
+	refs, err := r.References()
+	if err != nil {
+		return nil, fmt.Errorf("%s: OutRefs: %w", r.ResourceID(), err)
+	}
+	var ret []ResourceRef
+	for _, ref := range refs {
+		rr, err := ParseRef(r.ResourceID(), ref.Path, ref.URL)
+		if err != nil {
+			return nil, err
+		}
+		if rr.To.Resource != ref.Resource {
+			return nil, fmt.Errorf("%s: reference in %s is to %q, want %q", r.ResourceID(), ref.Path, rr.To.Resource, ref.Resource)
+		}
+		ret = append(ret, rr)
+	}
+	return ret, nil
+}
+
+// ParseRef parses the reference url in the field path of from. References
+// without an API group are assumed to be to compute resources. References
+// without a project (e.g. "global/backendServices/bs") are to the project of
+// from; references with a project may be to a different project than from
+// (e.g. a Shared VPC subnetwork in the host project).
+func ParseRef(from *cloud.ResourceID, path api.Path, url string) (ResourceRef, error) {
+	to, err := cloud.ParseResourceURL(url)
+	if err != nil {
+		return ResourceRef{}, fmt.Errorf("%s: invalid reference in %s: %w", from, path, err)
+	}
+	if to.APIGroup == "" {
+		to.APIGroup = meta.APIGroupCompute
+	}
+	if to.ProjectID == "" {
+		to.ProjectID = from.ProjectID
+	}
+	return ResourceRef{From: from, Path: path, To: to}, nil
+}
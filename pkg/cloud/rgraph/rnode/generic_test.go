@@ -0,0 +1,119 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+func TestByScope(t *testing.T) {
+	ctx := context.Background()
+	var called string
+	get := func(scope string) func(context.Context, *meta.Key) (*string, error) {
+		return func(context.Context, *meta.Key) (*string, error) {
+			called = scope
+			return &scope, nil
+		}
+	}
+	create := func(scope string) func(context.Context, *meta.Key, *string) error {
+		return func(context.Context, *meta.Key, *string) error {
+			called = scope
+			return nil
+		}
+	}
+	del := func(scope string) func(context.Context, *meta.Key) error {
+		return func(context.Context, *meta.Key) error {
+			called = scope
+			return nil
+		}
+	}
+
+	for _, tc := range []struct {
+		name     string
+		key      *meta.Key
+		regional bool
+		want     string
+	}{
+		{name: "global", key: meta.GlobalKey("x"), regional: true, want: "global"},
+		{name: "regional", key: meta.RegionalKey("x", "us-central1"), regional: true, want: "regional"},
+		{name: "regional not supported", key: meta.RegionalKey("x", "us-central1")},
+		{name: "zonal", key: meta.ZonalKey("x", "us-central1-a"), regional: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				getRegional    func(context.Context, *meta.Key) (*string, error)
+				createRegional func(context.Context, *meta.Key, *string) error
+				deleteRegional func(context.Context, *meta.Key) error
+			)
+			if tc.regional {
+				getRegional = get("regional")
+				createRegional = create("regional")
+				deleteRegional = del("regional")
+			}
+			wantErr := tc.want == ""
+
+			called = ""
+			_, err := GetByScope(get("global"), getRegional)(ctx, tc.key)
+			if gotErr := err != nil; gotErr != wantErr || called != tc.want {
+				t.Errorf("GetByScope()(%v) = %v, called %q; want err %t, called %q", tc.key, err, called, wantErr, tc.want)
+			}
+			called = ""
+			err = CreateByScope(create("global"), createRegional)(ctx, tc.key, nil)
+			if gotErr := err != nil; gotErr != wantErr || called != tc.want {
+				t.Errorf("CreateByScope()(%v) = %v, called %q; want err %t, called %q", tc.key, err, called, wantErr, tc.want)
+			}
+			called = ""
+			err = DeleteByScope(del("global"), deleteRegional)(ctx, tc.key)
+			if gotErr := err != nil; gotErr != wantErr || called != tc.want {
+				t.Errorf("DeleteByScope()(%v) = %v, called %q; want err %t, called %q", tc.key, err, called, wantErr, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	from := &cloud.ResourceID{
+		Resource:  "targetTcpProxies",
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: "proj",
+		Key:       meta.GlobalKey("tp"),
+	}
+	for _, tc := range []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "global/backendServices/bs", want: "compute/backendServices:proj/bs"},
+		{url: "projects/host/regions/us-central1/subnetworks/sn", want: "compute/subnetworks:host/us-central1/sn"},
+		{url: "https://www.googleapis.com/compute/v1/projects/other/global/backendServices/bs", want: "compute/backendServices:other/bs"},
+		{url: "invalid", wantErr: true},
+	} {
+		ref, err := ParseRef(from, api.Path{}.Pointer().Field("Service"), tc.url)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("ParseRef(%q) = %v; gotErr = %t, want %t", tc.url, err, gotErr, tc.wantErr)
+			continue
+		}
+		if err == nil && ref.To.String() != tc.want {
+			t.Errorf("ParseRef(%q).To = %s, want %s", tc.url, ref.To, tc.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheckservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	region = "us-central1"
+	hcURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/healthChecks/hc"
+	negURL = "https://www.googleapis.com/compute/v1/projects/proj-1/zones/us-central1-a/networkEndpointGroups/neg"
+	neURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/notificationEndpoints/ne"
+)
+
+func newNode(t *testing.T, f func(*compute.HealthCheckService)) rnode.Node {
+	t.Helper()
+	r := NewMutableHealthCheckService(proj, meta.RegionalKey("hcs", region))
+	if err := r.Access(func(x *compute.HealthCheckService) {
+		x.Name = "hcs"
+		x.HealthChecks = []string{hcURL}
+		x.NetworkEndpointGroups = []string{negURL}
+		x.NotificationEndpoints = []string{neURL}
+		x.HealthStatusAggregationPolicy = "AND"
+		x.ForceSendFields = []string{"Description"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	refs := newNode(t, nil).OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.To.String())
+	}
+	want := []string{
+		"compute/healthChecks:proj-1/us-central1/hc",
+		"compute/networkEndpointGroups:proj-1/us-central1-a/neg",
+		"compute/notificationEndpoints:proj-1/us-central1/ne",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var patches []*compute.HealthCheckService
+	m.MockRegionHealthCheckServices.PatchHook = func(ctx context.Context, key *meta.Key, obj *compute.HealthCheckService, m *cloud.MockRegionHealthCheckServices) error {
+		patches = append(patches, obj)
+		return mock.PatchRegionHealthCheckServiceHook(ctx, key, obj, m)
+	}
+	key := meta.RegionalKey("hcs", region)
+
+	got := newNode(t, nil)
+	gotObj, _ := got.Resource().(HealthCheckService).ToGA()
+	if err := m.RegionHealthCheckServices().Insert(ctx, key, gotObj); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	want := newNode(t, func(x *compute.HealthCheckService) { x.HealthStatusAggregationPolicy = "NO_AGGREGATION" })
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v", err)
+	}
+	if pd.Operation != rnode.OpUpdate {
+		t.Fatalf("Diff() = %v, want op %v", pd, rnode.OpUpdate)
+	}
+	want.Plan().Set(*pd)
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+	hcs, err := m.RegionHealthCheckServices().Get(ctx, key)
+	if err != nil || hcs.HealthStatusAggregationPolicy != "NO_AGGREGATION" {
+		t.Errorf("Get() = %+v, %v; want HealthStatusAggregationPolicy=NO_AGGREGATION", hcs, err)
+	}
+	// Only the changed field is sent.
+	wantPatch := []*compute.HealthCheckService{{HealthStatusAggregationPolicy: "NO_AGGREGATION"}}
+	if diff := cmp.Diff(patches, wantPatch); diff != "" {
+		t.Errorf("Patch(); -got,+want: %s", diff)
+	}
+}
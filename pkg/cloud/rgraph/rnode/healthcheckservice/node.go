@@ -0,0 +1,156 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheckservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type healthCheckServiceNode struct {
+	rnode.NodeBase
+	resource HealthCheckService
+}
+
+var _ rnode.Node = (*healthCheckServiceNode)(nil)
+
+func (n *healthCheckServiceNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *healthCheckServiceNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*healthCheckServiceNode)
+	if !ok {
+		return nil, fmt.Errorf("HealthCheckServiceNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("HealthCheckServiceNode: Diff %w", err)
+	}
+	// All of the user settable fields other than the Name can be changed
+	// with Patch().
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+func (n *healthCheckServiceNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("HealthCheckServiceNode: invalid plan op %s", op)
+}
+
+func (n *healthCheckServiceNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	// Patch() requires the fingerprint of the current resource.
+	gotRes, ok := got.Resource().(HealthCheckService)
+	if !ok || gotRes == nil {
+		return nil, fmt.Errorf("HealthCheckServiceNode: update %s: got has no resource", n.ID())
+	}
+	gotGA, err := gotRes.ToGA()
+	if gotGA == nil {
+		return nil, fmt.Errorf("HealthCheckServiceNode: update %s: %w", n.ID(), err)
+	}
+	fingerprint := gotGA.Fingerprint
+	// Only the changed fields are sent so that fields managed by the
+	// server are not overwritten.
+	delta, err := api.UpdateDelta(n.resource, gotRes)
+	if err != nil {
+		return nil, fmt.Errorf("HealthCheckServiceNode: update %s: %w", n.ID(), err)
+	}
+
+	r := n.resource
+	var body any
+	switch r.Version() {
+	case meta.VersionGA:
+		delta.GA.Fingerprint = fingerprint
+		body = delta.GA
+	case meta.VersionAlpha:
+		delta.Alpha.Fingerprint = fingerprint
+		body = delta.Alpha
+	case meta.VersionBeta:
+		delta.Beta.Fingerprint = fingerprint
+		body = delta.Beta
+	default:
+		return nil, fmt.Errorf("HealthCheckServiceNode: update %s: invalid version %q", n.ID(), r.Version())
+	}
+	if err := api.StripOutputOnly((&typeTrait{}).FieldTraits(r.Version()), body); err != nil {
+		return nil, fmt.Errorf("HealthCheckServiceNode: update %s: %w", n.ID(), err)
+	}
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		switch r.Version() {
+		case meta.VersionGA:
+			return gcp.RegionHealthCheckServices().Patch(ctx, key, delta.GA)
+		case meta.VersionAlpha:
+			return gcp.AlphaRegionHealthCheckServices().Patch(ctx, key, delta.Alpha)
+		case meta.VersionBeta:
+			return gcp.BetaRegionHealthCheckServices().Patch(ctx, key, delta.Beta)
+		}
+		return fmt.Errorf("HealthCheckService %s: invalid version %q", n.ID(), r.Version())
+	}
+	call := exec.Call{
+		Method:  "Patch",
+		Version: r.Version(),
+		ID:      n.ID(),
+		Body:    exec.BodySummary(body),
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Patch %v", n.ID()),
+			rnode.UpdateEvents(got, n),
+			update,
+			call,
+		),
+	}, nil
+}
+
+func (n *healthCheckServiceNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
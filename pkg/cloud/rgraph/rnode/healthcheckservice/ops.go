@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthcheckservice
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService] {
+	return &rnode.GetFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]{
+		GA:    gcp.RegionHealthCheckServices().Get,
+		Alpha: gcp.AlphaRegionHealthCheckServices().Get,
+		Beta:  gcp.BetaRegionHealthCheckServices().Get,
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService] {
+	return &rnode.CreateFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]{
+		GA:    gcp.RegionHealthCheckServices().Insert,
+		Alpha: gcp.AlphaRegionHealthCheckServices().Insert,
+		Beta:  gcp.BetaRegionHealthCheckServices().Insert,
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService] {
+	return &rnode.DeleteFuncs[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]{
+		GA:    gcp.RegionHealthCheckServices().Delete,
+		Alpha: gcp.AlphaRegionHealthCheckServices().Delete,
+		Beta:  gcp.BetaRegionHealthCheckServices().Delete,
+	}
+}
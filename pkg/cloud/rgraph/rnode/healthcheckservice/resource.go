@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package healthcheckservice implements the rnode for regional
+// HealthCheckService resources.
+package healthcheckservice
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "healthCheckServices"
+
+// HealthCheckService is the frozen resource type.
+type HealthCheckService = api.Resource[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]
+
+// MutableHealthCheckService is the mutable resource type.
+type MutableHealthCheckService = api.MutableResource[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]
+
+// ID of the HealthCheckService resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableHealthCheckService returns a new mutable HealthCheckService.
+func NewMutableHealthCheckService(project string, key *meta.Key) MutableHealthCheckService {
+	return api.NewResource[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.HealthCheckService, alpha.HealthCheckService, beta.HealthCheckService]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Enum(api.Path{}.Pointer().Field("HealthStatusAggregationPolicy"), "AND", "NO_AGGREGATION")
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("HealthChecks").AnySliceIndex(), "healthChecks")
+	dt.Reference(api.Path{}.Pointer().Field("NetworkEndpointGroups").AnySliceIndex(), "networkEndpointGroups")
+	dt.Reference(api.Path{}.Pointer().Field("NotificationEndpoints").AnySliceIndex(), "notificationEndpoints")
+
+	return dt
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroupmanager
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj  = "proj-1"
+	zone  = "us-central1-a"
+	itURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/instanceTemplates/it"
+	hcURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/healthChecks/hc"
+)
+
+func newMock() *cloud.MockGCE {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	m.MockInstanceGroupManagers.PatchHook = mock.PatchInstanceGroupManagerHook
+	m.MockInstanceGroupManagers.ResizeHook = mock.ResizeInstanceGroupManagerHook
+	m.MockInstanceGroupManagers.SetInstanceTemplateHook = mock.SetInstanceTemplateInstanceGroupManagerHook
+	m.MockInstanceGroups.SetNamedPortsHook = mock.SetNamedPortsHook
+	return m
+}
+
+func newNode(t *testing.T, f func(*compute.InstanceGroupManager)) rnode.Node {
+	t.Helper()
+	r := NewMutableInstanceGroupManager(proj, meta.ZonalKey("mig", zone))
+	if err := r.Access(func(x *compute.InstanceGroupManager) {
+		x.Name = "mig"
+		x.BaseInstanceName = "inst"
+		x.InstanceTemplate = itURL
+		x.TargetSize = 3
+		x.NamedPorts = []*compute.NamedPort{{Name: "http", Port: 80}}
+		x.AutoHealingPolicies = []*compute.InstanceGroupManagerAutoHealingPolicy{{HealthCheck: hcURL, InitialDelaySec: 30}}
+		x.NullFields = []string{"DistributionPolicy", "StatefulPolicy", "TargetPools", "UpdatePolicy", "Versions"}
+		x.ForceSendFields = []string{"Description", "ListManagedInstancesResults"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	n := newNode(t, nil)
+	refs := n.OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" "+ref.To.String())
+	}
+	want := []string{
+		"*.InstanceTemplate " + "compute/instanceTemplates:proj-1/it",
+		"*.AutoHealingPolicies!0*.HealthCheck " + "compute/healthChecks:proj-1/hc",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := newNode(t, nil)
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.InstanceGroupManager)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "target size",
+			f:      func(x *compute.InstanceGroupManager) { x.TargetSize = 5 },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "instance template",
+			f:      func(x *compute.InstanceGroupManager) { x.InstanceTemplate = itURL + "2" },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "autohealing",
+			f:      func(x *compute.InstanceGroupManager) { x.AutoHealingPolicies[0].InitialDelaySec = 60 },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "named ports",
+			f:      func(x *compute.InstanceGroupManager) { x.NamedPorts[0].Port = 8080 },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "base instance name",
+			f:      func(x *compute.InstanceGroupManager) { x.BaseInstanceName = "other" },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name: "target pools",
+			f: func(x *compute.InstanceGroupManager) {
+				x.TargetPools = []string{"https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/targetPools/tp"}
+				x.NullFields = []string{"DistributionPolicy", "StatefulPolicy", "UpdatePolicy", "Versions"}
+			},
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := newNode(t, tc.f)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %v, want op %v", pd, tc.wantOp)
+			}
+			if gotRecreate := len(pd.RecreatePaths) > 0; gotRecreate != (tc.wantOp == rnode.OpRecreate) {
+				t.Errorf("Diff().RecreatePaths = %v; want set only for %v", pd.RecreatePaths, rnode.OpRecreate)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	key := meta.ZonalKey("mig", zone)
+
+	got := newNode(t, nil)
+	gotObj, _ := got.Resource().(InstanceGroupManager).ToGA()
+	if err := m.InstanceGroupManagers().Insert(ctx, key, gotObj); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := m.InstanceGroups().Insert(ctx, key, &compute.InstanceGroup{Name: "mig", NamedPorts: gotObj.NamedPorts}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	want := newNode(t, func(x *compute.InstanceGroupManager) {
+		x.InstanceTemplate = itURL + "2"
+		x.TargetSize = 5
+		x.NamedPorts = []*compute.NamedPort{{Name: "http", Port: 8080}}
+		x.AutoHealingPolicies = nil
+		x.NullFields = append(x.NullFields, "AutoHealingPolicies")
+	})
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v", err)
+	}
+	want.Plan().Set(*pd)
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+
+	mig, err := m.InstanceGroupManagers().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if mig.InstanceTemplate != itURL+"2" || mig.TargetSize != 5 || len(mig.AutoHealingPolicies) != 0 {
+		t.Errorf("Get() = %+v; want InstanceTemplate=%s, TargetSize=5, AutoHealingPolicies=[]", mig, itURL+"2")
+	}
+	ig, err := m.InstanceGroups().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if len(ig.NamedPorts) != 1 || ig.NamedPorts[0].Port != 8080 {
+		t.Errorf("InstanceGroup.NamedPorts = %v, want port 8080", ig.NamedPorts)
+	}
+}
+
+func TestUpdateUnsupportedVersion(t *testing.T) {
+	got := newNode(t, nil)
+	r := NewMutableInstanceGroupManager(proj, meta.ZonalKey("mig", zone))
+	r.VersionPreference(meta.VersionAlpha)
+	gotObj, _ := got.Resource().(InstanceGroupManager).ToGA()
+	if err := r.Set(gotObj); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	if err := r.Access(func(x *compute.InstanceGroupManager) { x.TargetSize = 5 }); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	want := ez.ManagedNode(fr, NewBuilderWithResource)
+	// Only the GA API is supported for InstanceGroupManagers.
+	if pd, err := want.Diff(got); err == nil {
+		t.Errorf("Diff() with an alpha resource = %v, nil; want error", pd)
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroupmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// updateMethod is the API method used to change a field in place.
+type updateMethod string
+
+const (
+	methodSetInstanceTemplate updateMethod = "SetInstanceTemplate"
+	methodPatch               updateMethod = "Patch"
+	methodSetNamedPorts       updateMethod = "SetNamedPorts"
+	methodResize              updateMethod = "Resize"
+)
+
+// methodOrder is the order in which the update methods are called.
+var methodOrder = []updateMethod{
+	methodSetInstanceTemplate,
+	methodPatch,
+	methodSetNamedPorts,
+	methodResize,
+}
+
+type instanceGroupManagerNode struct {
+	rnode.NodeBase
+	resource InstanceGroupManager
+}
+
+var _ rnode.Node = (*instanceGroupManagerNode)(nil)
+
+func (n *instanceGroupManagerNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *instanceGroupManagerNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*instanceGroupManagerNode)
+	if !ok {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: Diff %w", err)
+	}
+	pd := rnode.PlanForDiff(n.IgnoreDiff(diff))
+	// Only the GA API is supported by the Cloud interface (see ops).
+	if ver := n.resource.Version(); pd.Operation == rnode.OpUpdate && ver != meta.VersionGA {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: update %s not supported for version %s", n.ID(), ver)
+	}
+	return pd, nil
+}
+
+// updateMethodFor returns the method used to change field in place. The
+// fields that cannot be changed in place are Immutable in the typeTrait.
+func updateMethodFor(field string) (updateMethod, bool) {
+	switch field {
+	case "InstanceTemplate":
+		return methodSetInstanceTemplate, true
+	case "AutoHealingPolicies", "StatefulPolicy", "UpdatePolicy", "Versions":
+		return methodPatch, true
+	case "NamedPorts":
+		return methodSetNamedPorts, true
+	case "TargetSize":
+		return methodResize, true
+	}
+	return "", false
+}
+
+// fieldName returns the name of the top-level field that contains p.
+func fieldName(p api.Path) string {
+	if len(p) < 2 || len(p[1]) < 2 || p[1][0] != '.' {
+		return p.String()
+	}
+	return p[1][1:]
+}
+
+func (n *instanceGroupManagerNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("InstanceGroupManagerNode: invalid plan op %s", op)
+}
+
+func (n *instanceGroupManagerNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: update %s: plan has no diff", n.ID())
+	}
+	obj, err := n.resource.ToGA()
+	if err != nil {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: update %s: %w", n.ID(), err)
+	}
+
+	// Group the fields by method so that a single Patch is issued for all
+	// of the patched fields.
+	var (
+		fields  []string
+		called  = map[updateMethod]bool{}
+		methods []updateMethod
+		patch   = &compute.InstanceGroupManager{}
+	)
+	for _, item := range details.Diff.Items {
+		f := fieldName(item.Path)
+		if contains(fields, f) {
+			continue
+		}
+		m, ok := updateMethodFor(f)
+		if !ok {
+			return nil, fmt.Errorf("InstanceGroupManagerNode: update %s: no method to update %s", n.ID(), f)
+		}
+		if m == methodPatch {
+			setPatchField(patch, obj, f)
+		}
+		fields = append(fields, f)
+		called[m] = true
+	}
+	for _, m := range methodOrder {
+		if called[m] {
+			methods = append(methods, m)
+		}
+	}
+
+	if err := api.StripOutputOnly((&typeTrait{}).FieldTraits(meta.VersionGA), patch); err != nil {
+		return nil, fmt.Errorf("InstanceGroupManagerNode: update %s: %w", n.ID(), err)
+	}
+
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		for _, m := range methods {
+			var err error
+			switch m {
+			case methodSetInstanceTemplate:
+				err = gcp.InstanceGroupManagers().SetInstanceTemplate(ctx, key, &compute.InstanceGroupManagersSetInstanceTemplateRequest{InstanceTemplate: obj.InstanceTemplate})
+			case methodPatch:
+				err = gcp.InstanceGroupManagers().Patch(ctx, key, patch)
+			case methodSetNamedPorts:
+				// The named ports are stored in the InstanceGroup
+				// created by the InstanceGroupManager, which has the
+				// same key.
+				err = gcp.InstanceGroups().SetNamedPorts(ctx, key, &compute.InstanceGroupsSetNamedPortsRequest{NamedPorts: obj.NamedPorts})
+			case methodResize:
+				err = gcp.InstanceGroupManagers().Resize(ctx, key, obj.TargetSize)
+			}
+			if err != nil {
+				return fmt.Errorf("InstanceGroupManager %s: %s: %w", n.ID(), m, err)
+			}
+		}
+		return nil
+	}
+
+	var calls []exec.Call
+	for _, m := range methods {
+		call := exec.Call{Method: string(m), Version: meta.VersionGA, ID: n.ID()}
+		switch m {
+		case methodSetInstanceTemplate:
+			call.Body = exec.BodySummary(&compute.InstanceGroupManagersSetInstanceTemplateRequest{InstanceTemplate: obj.InstanceTemplate})
+		case methodPatch:
+			call.Body = exec.BodySummary(patch)
+		case methodSetNamedPorts:
+			call.ID = cloud.NewInstanceGroupsResourceID(n.ID().ProjectID, key.Zone, key.Name)
+			call.Body = exec.BodySummary(&compute.InstanceGroupsSetNamedPortsRequest{NamedPorts: obj.NamedPorts})
+		case methodResize:
+			call.Body = exec.BodySummary(obj.TargetSize)
+		}
+		calls = append(calls, call)
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (%s)", n.ID(), strings.Join(fields, ", ")),
+			rnode.UpdateEvents(got, n),
+			update,
+			calls...,
+		),
+	}, nil
+}
+
+// setPatchField copies field from obj to the patch. Empty values are sent as
+// nulls to clear the field.
+func setPatchField(patch, obj *compute.InstanceGroupManager, field string) {
+	var empty bool
+	switch field {
+	case "AutoHealingPolicies":
+		patch.AutoHealingPolicies = obj.AutoHealingPolicies
+		empty = len(obj.AutoHealingPolicies) == 0
+	case "StatefulPolicy":
+		patch.StatefulPolicy = obj.StatefulPolicy
+		empty = obj.StatefulPolicy == nil
+	case "UpdatePolicy":
+		patch.UpdatePolicy = obj.UpdatePolicy
+		empty = obj.UpdatePolicy == nil
+	case "Versions":
+		patch.Versions = obj.Versions
+		empty = len(obj.Versions) == 0
+	}
+	if empty {
+		patch.NullFields = append(patch.NullFields, field)
+	}
+}
+
+func contains[T comparable](l []T, v T) bool {
+	for _, x := range l {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *instanceGroupManagerNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
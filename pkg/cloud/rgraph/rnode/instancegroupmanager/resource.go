@@ -0,0 +1,97 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package instancegroupmanager implements the rnode for zonal
+// InstanceGroupManager (managed instance group) resources.
+package instancegroupmanager
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "instanceGroupManagers"
+
+// InstanceGroupManager is the frozen resource type.
+type InstanceGroupManager = api.Resource[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager]
+
+// MutableInstanceGroupManager is the mutable resource type.
+type MutableInstanceGroupManager = api.MutableResource[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager]
+
+// ID of the InstanceGroupManager resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableInstanceGroupManager returns a new mutable InstanceGroupManager.
+func NewMutableInstanceGroupManager(project string, key *meta.Key) MutableInstanceGroupManager {
+	return api.NewResource[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.InstanceGroupManager, alpha.InstanceGroupManager, beta.InstanceGroupManager]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("CurrentActions"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("InstanceGroup"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Status"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Zone"))
+	// Fields other than AutoHealingPolicies, InstanceTemplate, NamedPorts,
+	// StatefulPolicy, TargetSize, UpdatePolicy and Versions cannot be
+	// changed in place.
+	dt.Immutable(api.Path{}.Pointer().Field("BaseInstanceName"))
+	dt.Immutable(api.Path{}.Pointer().Field("Description"))
+	dt.Immutable(api.Path{}.Pointer().Field("DistributionPolicy"))
+	dt.Immutable(api.Path{}.Pointer().Field("ListManagedInstancesResults"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Immutable(api.Path{}.Pointer().Field("TargetPools"))
+	if v != meta.VersionGA {
+		dt.Immutable(api.Path{}.Pointer().Field("AllInstancesConfig"))
+		dt.Immutable(api.Path{}.Pointer().Field("FailoverAction"))
+		dt.Immutable(api.Path{}.Pointer().Field("InstanceLifecyclePolicy"))
+		dt.Immutable(api.Path{}.Pointer().Field("ServiceAccount"))
+	}
+	if v == meta.VersionAlpha {
+		dt.OutputOnly(api.Path{}.Pointer().Field("SelfLinkWithId"))
+		dt.Immutable(api.Path{}.Pointer().Field("StandbyPolicy"))
+		dt.Immutable(api.Path{}.Pointer().Field("TargetStoppedSize"))
+		dt.Immutable(api.Path{}.Pointer().Field("TargetSuspendedSize"))
+	}
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("InstanceTemplate"), "instanceTemplates")
+	dt.Reference(api.Path{}.Pointer().Field("Versions").AnySliceIndex().Pointer().Field("InstanceTemplate"), "instanceTemplates")
+	dt.Reference(api.Path{}.Pointer().Field("AutoHealingPolicies").AnySliceIndex().Pointer().Field("HealthCheck"), "healthChecks")
+	dt.Reference(api.Path{}.Pointer().Field("TargetPools").AnySliceIndex(), "targetPools")
+
+	return dt
+}
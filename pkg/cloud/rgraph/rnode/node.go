@@ -17,7 +17,11 @@ limitations under the License.
 package rnode
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
 )
@@ -42,6 +46,13 @@ type Node interface {
 	InRefs() []ResourceRef
 	// Resource is the cloud resource (e.g. the Resource[compute.Address,...]).
 	Resource() UntypedResource
+	// IgnoreDiffPaths are the fields that are ignored when computing the
+	// Diff.
+	IgnoreDiffPaths() []api.Path
+	// FetchTime is when the resource was fetched from the Cloud. This is
+	// zero for Nodes that were not fetched (e.g. Nodes in the "want"
+	// graph).
+	FetchTime() time.Time
 	// Builder returns a node builder that has the same attributes and
 	// underlying type but has no contents in the resource. This is used to
 	// populate a graph for getting the current state from Cloud (i.e. the "got"
@@ -58,6 +69,13 @@ type Node interface {
 	Actions(got Node) ([]exec.Action, error)
 }
 
+// OrderHinter is implemented by Nodes that require their Actions to be ordered
+// relative to the Actions of other types of resources in ways that are not
+// expressed by references (see exec.OrderHint).
+type OrderHinter interface {
+	OrderHints() []exec.OrderHint
+}
+
 // NodeBase are common non-typed fields for implementing a Node in the graph.
 type NodeBase struct {
 	id        *cloud.ResourceID
@@ -66,6 +84,9 @@ type NodeBase struct {
 	outRefs   []ResourceRef
 	inRefs    []ResourceRef
 	plan      Plan
+
+	ignoreDiffPaths []api.Path
+	fetchTime       time.Time
 }
 
 func (n *NodeBase) ID() *cloud.ResourceID      { return n.id }
@@ -75,6 +96,40 @@ func (n *NodeBase) OutRefs() []ResourceRef     { return n.outRefs }
 func (n *NodeBase) InRefs() []ResourceRef      { return n.inRefs }
 func (n *NodeBase) Plan() *Plan                { return &n.plan }
 
+func (n *NodeBase) IgnoreDiffPaths() []api.Path { return n.ignoreDiffPaths }
+func (n *NodeBase) FetchTime() time.Time        { return n.fetchTime }
+
+// IsStale returns true if the Node was fetched from the Cloud more than ttl
+// before now. Nodes that have not been fetched are always stale.
+func IsStale(n Node, ttl time.Duration, now time.Time) bool {
+	t := n.FetchTime()
+	return t.IsZero() || now.Sub(t) > ttl
+}
+
+// CloneBuilder returns a Builder for n that, unlike n.Builder(), has the
+// resource of n. The resource is shared as resources are immutable. Use this
+// when a copy of the Node is needed, e.g. to plan without changing the Plan()
+// of n.
+func CloneBuilder(n Node) (Builder, error) {
+	b := n.Builder()
+	if r := n.Resource(); r != nil {
+		if err := b.SetResource(r); err != nil {
+			return nil, fmt.Errorf("CloneBuilder %s: %w", n.ID(), err)
+		}
+	}
+	b.SetFetchTime(n.FetchTime())
+	return b, nil
+}
+
+// IgnoreDiff removes the IgnoreDiffPaths from d. This should be called by
+// Diff() implementations before deciding on the Operation.
+func (n *NodeBase) IgnoreDiff(d *api.DiffResult) *api.DiffResult {
+	if len(n.ignoreDiffPaths) == 0 {
+		return d
+	}
+	return d.Ignore(n.ignoreDiffPaths)
+}
+
 // InitFromBuilder is an rgraph library internal method for common
 // initialization from a Builder.
 func (n *NodeBase) InitFromBuilder(b Builder) error {
@@ -87,6 +142,8 @@ func (n *NodeBase) InitFromBuilder(b Builder) error {
 	}
 	n.outRefs = outRefs
 	n.inRefs = b.inRefs()
+	n.ignoreDiffPaths = b.IgnoreDiffPaths()
+	n.fetchTime = b.FetchTime()
 
 	return nil
 }
@@ -19,6 +19,7 @@ package rnode
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
@@ -66,3 +67,30 @@ func TestNodeBase(t *testing.T) {
 
 	t.Log(n)
 }
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("res1")}
+
+	for _, tc := range []struct {
+		name      string
+		fetchTime time.Time
+		want      bool
+	}{
+		{name: "not fetched", want: true},
+		{name: "fresh", fetchTime: now.Add(-time.Second)},
+		{name: "at TTL", fetchTime: now.Add(-time.Minute)},
+		{name: "stale", fetchTime: now.Add(-time.Minute - time.Second), want: true},
+	} {
+		nb := &fakeBuilder{}
+		nb.Defaults(id)
+		nb.SetFetchTime(tc.fetchTime)
+		n, _ := nb.Build()
+		if !n.FetchTime().Equal(tc.fetchTime) {
+			t.Errorf("%s: FetchTime() = %v, want %v", tc.name, n.FetchTime(), tc.fetchTime)
+		}
+		if got := IsStale(n, time.Minute, now); got != tc.want {
+			t.Errorf("%s: IsStale() = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
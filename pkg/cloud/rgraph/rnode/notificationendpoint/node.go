@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationendpoint
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type notificationEndpointNode struct {
+	rnode.NodeBase
+	resource NotificationEndpoint
+}
+
+var _ rnode.Node = (*notificationEndpointNode)(nil)
+
+func (n *notificationEndpointNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *notificationEndpointNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*notificationEndpointNode)
+	if !ok {
+		return nil, fmt.Errorf("NotificationEndpointNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("NotificationEndpointNode: Diff %w", err)
+	}
+	// All fields are Immutable() as NotificationEndpoints cannot be
+	// updated.
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+func (n *notificationEndpointNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint](&ops{}, got, n, n.resource)
+	}
+
+	return nil, fmt.Errorf("NotificationEndpointNode: invalid plan op %s", op)
+}
+
+func (n *notificationEndpointNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationendpoint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	region = "us-central1"
+)
+
+func newNode(t *testing.T, f func(*compute.NotificationEndpoint)) rnode.Node {
+	t.Helper()
+	r := NewMutableNotificationEndpoint(proj, meta.RegionalKey("ne", region))
+	if err := r.Access(func(x *compute.NotificationEndpoint) {
+		x.Name = "ne"
+		x.GrpcSettings = &compute.NotificationEndpointGrpcSettings{
+			Authority:        "example.com",
+			Endpoint:         "https://example.com",
+			PayloadName:      "payload",
+			RetryDurationSec: 10,
+			ResendInterval:   &compute.Duration{Seconds: 5, ForceSendFields: []string{"Nanos"}},
+		}
+		x.ForceSendFields = []string{"Description"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestNotificationEndpoint(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	id := ID(proj, meta.RegionalKey("ne", region))
+
+	b := NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil || b.State() != rnode.NodeDoesNotExist {
+		t.Fatalf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeDoesNotExist)
+	}
+	gotNode, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	want := newNode(t, nil)
+	want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	actions, err := want.Actions(gotNode)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+
+	b = NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil || b.State() != rnode.NodeExists {
+		t.Fatalf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeExists)
+	}
+	got, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.NotificationEndpoint)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "grpc settings",
+			f:      func(x *compute.NotificationEndpoint) { x.GrpcSettings.RetryDurationSec = 20 },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		pd, err := newNode(t, tc.f).Diff(got)
+		if err != nil {
+			t.Fatalf("%s: Diff() = %v", tc.name, err)
+		}
+		if pd.Operation != tc.wantOp {
+			t.Errorf("%s: Diff() = %v, want op %v", tc.name, pd, tc.wantOp)
+		}
+	}
+}
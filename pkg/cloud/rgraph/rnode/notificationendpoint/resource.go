@@ -0,0 +1,67 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notificationendpoint implements the rnode for regional
+// NotificationEndpoint resources.
+package notificationendpoint
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "notificationEndpoints"
+
+// NotificationEndpoint is the frozen resource type.
+type NotificationEndpoint = api.Resource[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint]
+
+// MutableNotificationEndpoint is the mutable resource type.
+type MutableNotificationEndpoint = api.MutableResource[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint]
+
+// ID of the NotificationEndpoint resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableNotificationEndpoint returns a new mutable NotificationEndpoint.
+func NewMutableNotificationEndpoint(project string, key *meta.Key) MutableNotificationEndpoint {
+	return api.NewResource[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.NotificationEndpoint, alpha.NotificationEndpoint, beta.NotificationEndpoint]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	// There is no update method.
+	dt.Immutable(api.Path{}.Pointer())
+
+	return dt
+}
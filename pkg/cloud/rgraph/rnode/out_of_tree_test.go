@@ -0,0 +1,303 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file implements a node type outside of package rnode, using only the
+// exported API, to check that node types can be added by other packages.
+
+package rnode_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"google.golang.org/api/googleapi"
+)
+
+// Mesh is a resource whose API is not part of cloud.Cloud.
+type Mesh struct {
+	Name            string
+	Description     string
+	NullFields      []string
+	ForceSendFields []string
+}
+
+type meshResource = api.Resource[Mesh, Mesh, Mesh]
+
+type meshTypeTrait struct {
+	api.BaseTypeTrait[Mesh, Mesh, Mesh]
+}
+
+func meshID(name string) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		APIGroup:  meta.APIGroupNetworkServices,
+		Resource:  "meshes",
+		ProjectID: "proj",
+		Key:       meta.GlobalKey(name),
+	}
+}
+
+// meshClient is an in-memory Mesh API.
+type meshClient struct {
+	objs map[string]Mesh
+}
+
+var testMeshClient = &meshClient{objs: map[string]Mesh{}}
+
+func init() {
+	rnode.RegisterNodeType[Mesh, Mesh, Mesh](meta.APIGroupNetworkServices, "meshes", newMeshBuilder, &meshTypeTrait{})
+}
+
+// meshOps uses the meshClient instead of cloud.Cloud.
+type meshOps struct{ c *meshClient }
+
+func (o *meshOps) GetFuncs(cloud.Cloud) *rnode.GetFuncs[Mesh, Mesh, Mesh] {
+	return &rnode.GetFuncs[Mesh, Mesh, Mesh]{
+		GA: func(_ context.Context, key *meta.Key) (*Mesh, error) {
+			obj, ok := o.c.objs[key.Name]
+			if !ok {
+				return nil, &googleapi.Error{Code: http.StatusNotFound}
+			}
+			return &obj, nil
+		},
+	}
+}
+
+func (o *meshOps) CreateFuncs(cloud.Cloud) *rnode.CreateFuncs[Mesh, Mesh, Mesh] {
+	return &rnode.CreateFuncs[Mesh, Mesh, Mesh]{
+		GA: func(_ context.Context, key *meta.Key, obj *Mesh) error {
+			o.c.objs[key.Name] = *obj
+			return nil
+		},
+	}
+}
+
+func (o *meshOps) DeleteFuncs(cloud.Cloud) *rnode.DeleteFuncs[Mesh, Mesh, Mesh] {
+	return &rnode.DeleteFuncs[Mesh, Mesh, Mesh]{
+		GA: func(_ context.Context, key *meta.Key) error {
+			delete(o.c.objs, key.Name)
+			return nil
+		},
+	}
+}
+
+type meshBuilder struct {
+	rnode.BuilderBase
+	resource meshResource
+}
+
+func newMeshBuilder(id *cloud.ResourceID) rnode.Builder {
+	b := &meshBuilder{}
+	b.Defaults(id)
+	return b
+}
+
+func (b *meshBuilder) Resource() rnode.UntypedResource {
+	if b.resource == nil {
+		return nil
+	}
+	return b.resource
+}
+
+func (b *meshBuilder) SetResource(u rnode.UntypedResource) error {
+	r, ok := u.(meshResource)
+	if !ok {
+		return fmt.Errorf("meshBuilder: invalid type for SetResource: %T", u)
+	}
+	b.resource = r
+	return nil
+}
+
+func (b *meshBuilder) SyncFromCloud(ctx context.Context, gcp cloud.Cloud) error {
+	return rnode.GenericGetBuilder[Mesh, Mesh, Mesh](ctx, gcp, "Mesh", &meshOps{testMeshClient}, &meshTypeTrait{}, b)
+}
+
+func (b *meshBuilder) OutRefs() ([]rnode.ResourceRef, error) { return nil, nil }
+
+func (b *meshBuilder) Build() (rnode.Node, error) {
+	ret := &meshNode{resource: b.resource}
+	if err := ret.InitFromBuilder(b); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+type meshNode struct {
+	rnode.NodeBase
+	resource meshResource
+}
+
+func (n *meshNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *meshNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*meshNode)
+	if !ok {
+		return nil, fmt.Errorf("meshNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("meshNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+func (n *meshNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	ops := &meshOps{testMeshClient}
+	switch op := n.Plan().Op(); op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[Mesh, Mesh, Mesh](ops, got, n, n.resource)
+	case rnode.OpDelete:
+		return rnode.DeleteActions[Mesh, Mesh, Mesh](ops, got, n)
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[Mesh, Mesh, Mesh](ops, got, n, n.resource)
+	case rnode.OpUpdate:
+		obj, err := n.resource.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		update := func(context.Context, cloud.Cloud) error {
+			m := testMeshClient.objs[n.ID().Key.Name]
+			m.Description = obj.Description
+			testMeshClient.objs[n.ID().Key.Name] = m
+			return nil
+		}
+		return []exec.Action{
+			rnode.NewUpdateAction(rnode.UpdatePreconditions(got, n), n.ID(), "Patch", rnode.UpdateEvents(got, n), update),
+		}, nil
+	default:
+		return nil, fmt.Errorf("meshNode: invalid plan op %s", op)
+	}
+}
+
+func (n *meshNode) Builder() rnode.Builder {
+	b := &meshBuilder{resource: n.resource}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
+
+func TestOutOfTreeNodeType(t *testing.T) {
+	ctx := context.Background()
+
+	var registered bool
+	for _, k := range rnode.RegisteredNodeTypes() {
+		registered = registered || k.String() == "networkservices/meshes"
+	}
+	if !registered {
+		t.Fatalf("RegisteredNodeTypes() = %v, want networkservices/meshes", rnode.RegisteredNodeTypes())
+	}
+
+	newWant := func(description string) *rgraph.Graph {
+		r := api.NewResource[Mesh, Mesh, Mesh](meshID("m"), &meshTypeTrait{})
+		if err := r.Access(func(x *Mesh) {
+			x.Name = "m"
+			x.Description = description
+		}); err != nil {
+			t.Fatalf("Access() = %v", err)
+		}
+		fr, err := r.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		nb := newMeshBuilder(meshID("m"))
+		nb.SetState(rnode.NodeExists)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		if err := nb.SetResource(fr); err != nil {
+			t.Fatalf("SetResource() = %v", err)
+		}
+		b := rgraph.NewBuilder()
+		b.Add(nb)
+		return b.MustBuild()
+	}
+	// apply plans and executes want. The Mesh does not use cloud.Cloud.
+	apply := func(want *rgraph.Graph) rnode.Operation {
+		pr, err := plan.Do(ctx, nil, want)
+		if err != nil {
+			t.Fatalf("plan.Do() = %v, want nil", err)
+		}
+		ex, err := exec.NewSerialExecutor(pr.Actions)
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+		}
+		if _, err := ex.Run(ctx, nil); err != nil {
+			t.Fatalf("Run() = %v, want nil", err)
+		}
+		return want.Get(meshID("m")).Plan().Op()
+	}
+
+	if op := apply(newWant("a")); op != rnode.OpCreate {
+		t.Errorf("op = %s, want %s", op, rnode.OpCreate)
+	}
+	if got := testMeshClient.objs["m"].Description; got != "a" {
+		t.Errorf("Description = %q, want %q", got, "a")
+	}
+	if op := apply(newWant("b")); op != rnode.OpUpdate {
+		t.Errorf("op = %s, want %s", op, rnode.OpUpdate)
+	}
+	if got := testMeshClient.objs["m"].Description; got != "b" {
+		t.Errorf("Description = %q, want %q", got, "b")
+	}
+
+	// Graphs with the type can be restored.
+	want := newWant("b")
+	b, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	var restored rgraph.Graph
+	if err := json.Unmarshal(b, &restored); err != nil {
+		t.Fatalf("json.Unmarshal() = %v, want nil", err)
+	}
+	if _, ok := restored.Get(meshID("m")).(*meshNode); !ok {
+		t.Errorf("restored node = %T, want *meshNode", restored.Get(meshID("m")))
+	}
+}
+
+// badMeshTypeTrait references a field that does not exist in Mesh.
+type badMeshTypeTrait struct {
+	api.BaseTypeTrait[Mesh, Mesh, Mesh]
+}
+
+func (*badMeshTypeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	dt.OutputOnly(api.Path{}.Pointer().Field("Missing"))
+	return dt
+}
+
+func TestRegisterNodeTypeCheckSchema(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("RegisterNodeType() did not panic, want panic for a FieldTraits path not in the schema")
+		}
+	}()
+	rnode.RegisterNodeType[Mesh, Mesh, Mesh](meta.APIGroupNetworkServices, "badMeshes", newMeshBuilder, &badMeshTypeTrait{})
+}
@@ -18,6 +18,7 @@ package rnode
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 )
@@ -63,6 +64,44 @@ type PlanDetails struct {
 	// Diff is an optional description of the diff between the current and
 	// wanted resources.
 	Diff *api.DiffResult
+	// RecreatePaths are the changed fields that cannot be updated in
+	// place and forced OpRecreate.
+	RecreatePaths []api.Path `json:",omitempty"`
+}
+
+// PlanForDiff returns the PlanDetails for the diff between the got and want
+// resources (see Node.Diff()). The resource is recreated if any of the
+// changed fields are declared with FieldTraits.Immutable() (see
+// DiffResult.NeedsRecreate()), otherwise it is updated in place. IgnoreDiff()
+// should be applied to diff first.
+func PlanForDiff(diff *api.DiffResult) *PlanDetails {
+	if !diff.HasDiff() {
+		return &PlanDetails{
+			Operation: OpNothing,
+			Why:       "No diff between got and want",
+		}
+	}
+	if diff.NeedsRecreate() {
+		var (
+			paths    []string
+			recreate []api.Path
+		)
+		for _, item := range diff.ImmutableItems() {
+			paths = append(paths, item.Path.String())
+			recreate = append(recreate, item.Path)
+		}
+		return &PlanDetails{
+			Operation:     OpRecreate,
+			Why:           fmt.Sprintf("immutable fields changed: %s", strings.Join(paths, ", ")),
+			Diff:          diff,
+			RecreatePaths: recreate,
+		}
+	}
+	return &PlanDetails{
+		Operation: OpUpdate,
+		Why:       "update in place",
+		Diff:      diff,
+	}
 }
 
 // Op to perform.
@@ -83,6 +122,12 @@ func (p *Plan) Details() *PlanDetails {
 	return &p.details[len(p.details)-1]
 }
 
+// History returns all of the PlanDetails that have been Set(), oldest
+// first. The current plan is the last element.
+func (p *Plan) History() []PlanDetails {
+	return append([]PlanDetails{}, p.details...)
+}
+
 // Set the plan to the specified action.
 func (p *Plan) Set(a PlanDetails) {
 	p.details = append(p.details, a)
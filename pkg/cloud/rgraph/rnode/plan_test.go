@@ -19,6 +19,7 @@ package rnode
 import (
 	"testing"
 
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -60,3 +61,27 @@ func TestPlan(t *testing.T) {
 		}
 	}
 }
+
+func TestPlanForDiff(t *testing.T) {
+	immutable := api.DiffItem{Path: api.Path{}.Pointer().Field("A"), Immutable: true}
+	mutable := api.DiffItem{Path: api.Path{}.Pointer().Field("B")}
+
+	for _, tc := range []struct {
+		name   string
+		diff   *api.DiffResult
+		wantOp Operation
+	}{
+		{name: "no diff", diff: &api.DiffResult{}, wantOp: OpNothing},
+		{name: "mutable", diff: &api.DiffResult{Items: []api.DiffItem{mutable}}, wantOp: OpUpdate},
+		{name: "immutable", diff: &api.DiffResult{Items: []api.DiffItem{mutable, immutable}}, wantOp: OpRecreate},
+	} {
+		pd := PlanForDiff(tc.diff)
+		if pd.Operation != tc.wantOp {
+			t.Errorf("%s: PlanForDiff() = %+v, want op %v", tc.name, pd, tc.wantOp)
+		}
+		wantRecreate := tc.wantOp == OpRecreate
+		if gotRecreate := len(pd.RecreatePaths) == 1 && pd.RecreatePaths[0].Equal(immutable.Path); gotRecreate != wantRecreate {
+			t.Errorf("%s: PlanForDiff().RecreatePaths = %v, want [%v] only if recreated", tc.name, pd.RecreatePaths, immutable.Path)
+		}
+	}
+}
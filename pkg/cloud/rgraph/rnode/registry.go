@@ -0,0 +1,191 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rnode
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+)
+
+// nodeTypeKey identifies a kind of resource in the node type registry.
+type nodeTypeKey struct {
+	apiGroup meta.APIGroup
+	resource string
+}
+
+func newNodeTypeKey(apiGroup meta.APIGroup, resource string) nodeTypeKey {
+	// An unspecified API group is "compute" (see meta.APIGroup).
+	if apiGroup == "" {
+		apiGroup = meta.APIGroupCompute
+	}
+	return nodeTypeKey{apiGroup: apiGroup, resource: resource}
+}
+
+// nodeType are the constructors for a kind of resource.
+type nodeType struct {
+	newBuilder        func(*cloud.ResourceID) Builder
+	unmarshalResource func([]byte) (UntypedResource, error)
+}
+
+var nodeTypeRegistry = struct {
+	lock  sync.RWMutex
+	types map[nodeTypeKey]nodeType
+}{
+	types: map[nodeTypeKey]nodeType{},
+}
+
+// RegisterNodeType registers the Builder constructor and the TypeTrait for
+// resources of the given kind (e.g. meta.APIGroupCompute,
+// "targetSslProxies"). This is used to restore Nodes from their serialized
+// form (see NewBuilderForID() and UnmarshalResource()). The TypeTrait is also
+// registered with api.RegisterTypeTrait() so that it is used for resources of
+// the kind created without an explicit TypeTrait. It panics if the kind is
+// already registered or if the FieldTraits do not match the schema of the
+// resource types (see api.FieldTraits.CheckSchema()).
+//
+// Node types defined outside of this repository are registered in the same
+// way as the ones in the rnode subpackages (see the package documentation).
+// Registration should be done from init() or otherwise before any Graph
+// containing the type is restored.
+func RegisterNodeType[GA any, Alpha any, Beta any](
+	apiGroup meta.APIGroup,
+	resource string,
+	newBuilder func(*cloud.ResourceID) Builder,
+	typeTrait api.TypeTrait[GA, Alpha, Beta],
+) {
+	key := newNodeTypeKey(apiGroup, resource)
+
+	nodeTypeRegistry.lock.Lock()
+	defer nodeTypeRegistry.lock.Unlock()
+
+	if _, ok := nodeTypeRegistry.types[key]; ok {
+		panic(fmt.Sprintf("RegisterNodeType: duplicate registration for %s/%s", key.apiGroup, key.resource))
+	}
+	if err := checkFieldTraits(typeTrait); err != nil {
+		panic(fmt.Sprintf("RegisterNodeType: %s/%s: %v", key.apiGroup, key.resource, err))
+	}
+	api.RegisterTypeTrait[GA, Alpha, Beta](apiGroup, resource, typeTrait)
+	nodeTypeRegistry.types[key] = nodeType{
+		newBuilder: newBuilder,
+		unmarshalResource: func(b []byte) (UntypedResource, error) {
+			return api.UnmarshalResource(b, typeTrait)
+		},
+	}
+}
+
+// checkFieldTraits checks the FieldTraits of each version against the schema
+// of the resource type for the version. Versions that are api.NoVersion are
+// skipped.
+func checkFieldTraits[GA any, Alpha any, Beta any](typeTrait api.TypeTrait[GA, Alpha, Beta]) error {
+	if typeTrait == nil {
+		return nil
+	}
+	for _, x := range []struct {
+		ver meta.Version
+		t   reflect.Type
+	}{
+		{meta.VersionGA, reflect.TypeOf(new(GA))},
+		{meta.VersionAlpha, reflect.TypeOf(new(Alpha))},
+		{meta.VersionBeta, reflect.TypeOf(new(Beta))},
+	} {
+		if x.t.Elem() == reflect.TypeOf(api.NoVersion{}) {
+			continue
+		}
+		ft := typeTrait.FieldTraits(x.ver)
+		if ft == nil {
+			continue
+		}
+		if err := ft.CheckSchema(x.t); err != nil {
+			return fmt.Errorf("FieldTraits(%s): %w", x.ver, err)
+		}
+	}
+	return nil
+}
+
+// NodeTypeKind is the kind of resource handled by a registered node type.
+type NodeTypeKind struct {
+	APIGroup meta.APIGroup
+	Resource string
+}
+
+// String returns "apiGroup/resource".
+func (k NodeTypeKind) String() string { return fmt.Sprintf("%s/%s", k.APIGroup, k.Resource) }
+
+// RegisteredNodeTypes returns the kinds registered with RegisterNodeType(),
+// sorted by API group and resource.
+func RegisteredNodeTypes() []NodeTypeKind {
+	nodeTypeRegistry.lock.RLock()
+	defer nodeTypeRegistry.lock.RUnlock()
+
+	var ret []NodeTypeKind
+	for key := range nodeTypeRegistry.types {
+		ret = append(ret, NodeTypeKind{APIGroup: key.apiGroup, Resource: key.resource})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].APIGroup != ret[j].APIGroup {
+			return ret[i].APIGroup < ret[j].APIGroup
+		}
+		return ret[i].Resource < ret[j].Resource
+	})
+	return ret
+}
+
+func lookupNodeType(id *cloud.ResourceID) (nodeType, error) {
+	key := newNodeTypeKey(id.APIGroup, id.Resource)
+
+	nodeTypeRegistry.lock.RLock()
+	defer nodeTypeRegistry.lock.RUnlock()
+
+	nt, ok := nodeTypeRegistry.types[key]
+	if !ok {
+		return nodeType{}, fmt.Errorf("no node type registered for %s/%s", key.apiGroup, key.resource)
+	}
+	return nt, nil
+}
+
+// NewBuilderForID returns an empty Builder for id using the constructor
+// registered with RegisterNodeType().
+func NewBuilderForID(id *cloud.ResourceID) (Builder, error) {
+	nt, err := lookupNodeType(id)
+	if err != nil {
+		return nil, fmt.Errorf("NewBuilderForID %s: %w", id, err)
+	}
+	return nt.newBuilder(id), nil
+}
+
+// UnmarshalResource restores the resource id serialized with
+// json.Marshal() using the TypeTrait registered with RegisterNodeType().
+func UnmarshalResource(id *cloud.ResourceID, b []byte) (UntypedResource, error) {
+	nt, err := lookupNodeType(id)
+	if err != nil {
+		return nil, fmt.Errorf("UnmarshalResource %s: %w", id, err)
+	}
+	r, err := nt.unmarshalResource(b)
+	if err != nil {
+		return nil, fmt.Errorf("UnmarshalResource %s: %w", id, err)
+	}
+	if !r.ResourceID().Equal(id) {
+		return nil, fmt.Errorf("UnmarshalResource %s: resource has id %s", id, r.ResourceID())
+	}
+	return r, nil
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceattachment
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// updatableFields can be changed with Patch(). Changes to any other field
+// require the ServiceAttachment to be recreated.
+var updatableFields = []string{
+	"ConnectionPreference",
+	"ConsumerAcceptLists",
+	"ConsumerRejectLists",
+	"Description",
+	"EnableProxyProtocol",
+	"NatSubnets",
+}
+
+type serviceAttachmentNode struct {
+	rnode.NodeBase
+	resource ServiceAttachment
+}
+
+var _ rnode.Node = (*serviceAttachmentNode)(nil)
+
+func (n *serviceAttachmentNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *serviceAttachmentNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*serviceAttachmentNode)
+	if !ok {
+		return nil, fmt.Errorf("ServiceAttachmentNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("ServiceAttachmentNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+// updatableField returns the name of the updatable top-level field that
+// contains p. Returns "" if the field cannot be updated.
+func updatableField(p api.Path) string {
+	for _, f := range updatableFields {
+		if p.HasPrefix(api.Path{}.Pointer().Field(f)) {
+			return f
+		}
+	}
+	return ""
+}
+
+func (n *serviceAttachmentNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("ServiceAttachmentNode: invalid plan op %s", op)
+}
+
+func (n *serviceAttachmentNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("ServiceAttachmentNode: update %s: plan has no diff", n.ID())
+	}
+	changed := map[string]bool{}
+	for _, item := range details.Diff.Items {
+		field := updatableField(item.Path)
+		if field == "" {
+			return nil, fmt.Errorf("ServiceAttachmentNode: update %s: field %s cannot be updated", n.ID(), item.Path)
+		}
+		changed[field] = true
+	}
+	var fields []string
+	for _, f := range updatableFields {
+		if changed[f] {
+			fields = append(fields, f)
+		}
+	}
+	// Patch() requires the fingerprint of the current resource.
+	gotRes, ok := got.Resource().(ServiceAttachment)
+	if !ok || gotRes == nil {
+		return nil, fmt.Errorf("ServiceAttachmentNode: update %s: got has no resource", n.ID())
+	}
+	gotGA, err := gotRes.ToGA()
+	if gotGA == nil {
+		return nil, fmt.Errorf("ServiceAttachmentNode: update %s: %w", n.ID(), err)
+	}
+	r := n.resource
+	var patch any
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, fmt.Errorf("ServiceAttachmentNode: update %s: %w", n.ID(), err)
+		}
+		patch = patchRequest(obj, fields, gotGA.Fingerprint)
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, fmt.Errorf("ServiceAttachmentNode: update %s: %w", n.ID(), err)
+		}
+		patch = patchRequest(obj, fields, gotGA.Fingerprint)
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, fmt.Errorf("ServiceAttachmentNode: update %s: %w", n.ID(), err)
+		}
+		patch = patchRequest(obj, fields, gotGA.Fingerprint)
+	default:
+		return nil, fmt.Errorf("ServiceAttachmentNode: update %s: invalid version %q", n.ID(), r.Version())
+	}
+	if err := api.StripOutputOnly((&typeTrait{}).FieldTraits(r.Version()), patch); err != nil {
+		return nil, fmt.Errorf("ServiceAttachmentNode: update %s: %w", n.ID(), err)
+	}
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		switch patch := patch.(type) {
+		case *compute.ServiceAttachment:
+			return gcp.ServiceAttachments().Patch(ctx, key, patch)
+		case *alpha.ServiceAttachment:
+			return gcp.AlphaServiceAttachments().Patch(ctx, key, patch)
+		case *beta.ServiceAttachment:
+			return gcp.BetaServiceAttachments().Patch(ctx, key, patch)
+		}
+		return fmt.Errorf("ServiceAttachment %s: invalid patch type %T", n.ID(), patch)
+	}
+	call := exec.Call{
+		Method:  "Patch",
+		Version: r.Version(),
+		ID:      n.ID(),
+		Body:    exec.BodySummary(patch),
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (%s)", n.ID(), strings.Join(fields, ", ")),
+			rnode.UpdateEvents(got, n),
+			update,
+			call,
+		),
+	}, nil
+}
+
+// patchRequest returns the body for Patch() that sets fields to their values
+// in obj. The fields are in ForceSendFields so that they are cleared if they
+// are empty in obj. T is the ServiceAttachment type of any version; the
+// updatable fields have the same names in all versions.
+func patchRequest[T any](obj *T, fields []string, fingerprint string) *T {
+	ret := new(T)
+	src := reflect.ValueOf(obj).Elem()
+	dest := reflect.ValueOf(ret).Elem()
+	dest.FieldByName("Fingerprint").SetString(fingerprint)
+	dest.FieldByName("ForceSendFields").Set(reflect.ValueOf(fields))
+	for _, f := range fields {
+		dest.FieldByName(f).Set(src.FieldByName(f))
+	}
+	return ret
+}
+
+func (n *serviceAttachmentNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
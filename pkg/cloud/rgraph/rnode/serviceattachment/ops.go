@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceattachment
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment] {
+	return &rnode.GetFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]{
+		GA:    rnode.GetByScope(nil, gcp.ServiceAttachments().Get),
+		Alpha: rnode.GetByScope(nil, gcp.AlphaServiceAttachments().Get),
+		Beta:  rnode.GetByScope(nil, gcp.BetaServiceAttachments().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment] {
+	return &rnode.CreateFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]{
+		GA:    rnode.CreateByScope(nil, gcp.ServiceAttachments().Insert),
+		Alpha: rnode.CreateByScope(nil, gcp.AlphaServiceAttachments().Insert),
+		Beta:  rnode.CreateByScope(nil, gcp.BetaServiceAttachments().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment] {
+	return &rnode.DeleteFuncs[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]{
+		GA:    rnode.DeleteByScope(nil, gcp.ServiceAttachments().Delete),
+		Alpha: rnode.DeleteByScope(nil, gcp.AlphaServiceAttachments().Delete),
+		Beta:  rnode.DeleteByScope(nil, gcp.BetaServiceAttachments().Delete),
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package serviceattachment implements the rnode for ServiceAttachment
+// resources, the producer side of Private Service Connect (PSC).
+//
+// A ServiceAttachment publishes the service behind the producer forwarding
+// rule in TargetService. Consumers connect to it with a forwarding rule whose
+// Target is the ServiceAttachment (see package forwardingrule); the consumer
+// is often in a different project, in which case the ServiceAttachment is an
+// OwnershipExternal node in the consumer's graph.
+package serviceattachment
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "serviceAttachments"
+
+// ServiceAttachment is the frozen resource type.
+type ServiceAttachment = api.Resource[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]
+
+// MutableServiceAttachment is the mutable resource type.
+type MutableServiceAttachment = api.MutableResource[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]
+
+// ID of the ServiceAttachment resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableServiceAttachment returns a new mutable ServiceAttachment.
+func NewMutableServiceAttachment(project string, key *meta.Key) MutableServiceAttachment {
+	return api.NewResource[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.ServiceAttachment, alpha.ServiceAttachment, beta.ServiceAttachment]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("ConnectedEndpoints"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("PscServiceAttachmentId"))
+	// ProducerForwardingRule is deprecated in favor of TargetService.
+	dt.OutputOnly(api.Path{}.Pointer().Field("ProducerForwardingRule"))
+	dt.Enum(api.Path{}.Pointer().Field("ConnectionPreference"), "ACCEPT_AUTOMATIC", "ACCEPT_MANUAL")
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("ConsumerRejectLists"))
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("DomainNames"))
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("NatSubnets"))
+	// Fields other than the updatableFields cannot be changed with Patch().
+	dt.Immutable(api.Path{}.Pointer().Field("DomainNames"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Immutable(api.Path{}.Pointer().Field("TargetService"))
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("TargetService"), "forwardingRules")
+	dt.Reference(api.Path{}.Pointer().Field("NatSubnets").AnySliceIndex(), "subnetworks")
+
+	return dt
+}
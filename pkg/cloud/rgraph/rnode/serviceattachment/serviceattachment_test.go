@@ -0,0 +1,163 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceattachment
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj      = "proj-1"
+	region    = "us-central1"
+	frURL     = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/forwardingRules/fr"
+	subnetURL = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/subnetworks/psc-nat"
+)
+
+func newNode(t *testing.T, f func(*compute.ServiceAttachment)) rnode.Node {
+	t.Helper()
+	return newNodeAtVersion(t, meta.VersionGA, f)
+}
+
+// newNodeAtVersion returns a Node with a resource frozen at version ver.
+func newNodeAtVersion(t *testing.T, ver meta.Version, f func(*compute.ServiceAttachment)) rnode.Node {
+	t.Helper()
+	key := meta.RegionalKey("sa", region)
+	r := NewMutableServiceAttachment(proj, key)
+	r.VersionPreference(ver)
+	if err := r.Access(func(x *compute.ServiceAttachment) {
+		x.Name = key.Name
+		x.TargetService = frURL
+		x.NatSubnets = []string{subnetURL}
+		x.ConnectionPreference = "ACCEPT_AUTOMATIC"
+		x.ForceSendFields = []string{"ConsumerAcceptLists", "ConsumerRejectLists", "Description", "DomainNames", "EnableProxyProtocol"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	refs := newNode(t, nil).OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.Resource)
+	}
+	want := []string{
+		"*.TargetService => forwardingRules",
+		"*.NatSubnets!0 => subnetworks",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.ServiceAttachment)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "connection preference",
+			f:      func(x *compute.ServiceAttachment) { x.ConnectionPreference = "ACCEPT_MANUAL" },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name: "consumer accept list",
+			f: func(x *compute.ServiceAttachment) {
+				x.ConsumerAcceptLists = []*compute.ServiceAttachmentConsumerProjectLimit{{
+					ProjectIdOrNum:  "consumer",
+					ConnectionLimit: 10,
+					ForceSendFields: []string{"NetworkUrl"},
+				}}
+			},
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "target service",
+			f:      func(x *compute.ServiceAttachment) { x.TargetService = frURL + "2" },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name:   "domain names",
+			f:      func(x *compute.ServiceAttachment) { x.DomainNames = []string{"example.com."} },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNode(t, nil)
+			pd, err := newNode(t, tc.f).Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %v, want op %v", pd, tc.wantOp)
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	update := func(x *compute.ServiceAttachment) {
+		x.ConnectionPreference = "ACCEPT_MANUAL"
+		x.ConsumerRejectLists = []string{"bad-project"}
+	}
+	for _, ver := range []meta.Version{meta.VersionGA, meta.VersionBeta} {
+		t.Run(string(ver), func(t *testing.T) {
+			got := newNodeAtVersion(t, ver, nil)
+			want := newNodeAtVersion(t, ver, update)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			want.Plan().Set(*pd)
+			actions, err := want.Actions(got)
+			if err != nil {
+				t.Fatalf("Actions() = %v, want nil", err)
+			}
+			var gotCalls []string
+			for _, a := range actions {
+				if cd, ok := a.(exec.CallDescriber); ok {
+					for _, c := range cd.Calls() {
+						gotCalls = append(gotCalls, c.String())
+					}
+				}
+			}
+			wantCalls := []string{
+				`Patch ` + string(ver) + ` compute/serviceAttachments:proj-1/us-central1/sa {"connectionPreference":"ACCEPT_MANUAL","consumerRejectLists":["bad-project"]}`,
+			}
+			if diff := cmp.Diff(gotCalls, wantCalls); diff != "" {
+				t.Errorf("Calls(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
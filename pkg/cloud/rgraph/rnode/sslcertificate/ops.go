@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sslcertificate
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.GetFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA:    rnode.GetByScope(gcp.SslCertificates().Get, gcp.RegionSslCertificates().Get),
+		Alpha: rnode.GetByScope(gcp.AlphaSslCertificates().Get, gcp.AlphaRegionSslCertificates().Get),
+		Beta:  rnode.GetByScope(gcp.BetaSslCertificates().Get, gcp.BetaRegionSslCertificates().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.CreateFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA:    rnode.CreateByScope(gcp.SslCertificates().Insert, gcp.RegionSslCertificates().Insert),
+		Alpha: rnode.CreateByScope(gcp.AlphaSslCertificates().Insert, gcp.AlphaRegionSslCertificates().Insert),
+		Beta:  rnode.CreateByScope(gcp.BetaSslCertificates().Insert, gcp.BetaRegionSslCertificates().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate] {
+	return &rnode.DeleteFuncs[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]{
+		GA:    rnode.DeleteByScope(gcp.SslCertificates().Delete, gcp.RegionSslCertificates().Delete),
+		Alpha: rnode.DeleteByScope(gcp.AlphaSslCertificates().Delete, gcp.AlphaRegionSslCertificates().Delete),
+		Beta:  rnode.DeleteByScope(gcp.BetaSslCertificates().Delete, gcp.BetaRegionSslCertificates().Delete),
+	}
+}
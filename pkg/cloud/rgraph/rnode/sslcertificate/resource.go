@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sslcertificate implements the rnode for global and regional
+// SslCertificate resources.
+package sslcertificate
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "sslCertificates"
+
+// SslCertificate is the frozen resource type.
+type SslCertificate = api.Resource[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
+
+// MutableSslCertificate is the mutable resource type.
+type MutableSslCertificate = api.MutableResource[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
+
+// ID of the SslCertificate resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableSslCertificate returns a new mutable SslCertificate.
+func NewMutableSslCertificate(project string, key *meta.Key) MutableSslCertificate {
+	return api.NewResource[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.SslCertificate, alpha.SslCertificate, beta.SslCertificate]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("ExpireTime"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("SubjectAlternativeNames"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Managed").Pointer().Field("DomainStatus"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Managed").Pointer().Field("Status"))
+	dt.Enum(api.Path{}.Pointer().Field("Type"), "MANAGED", "SELF_MANAGED")
+	// The private key is never returned by the API, so it is only compared
+	// if it is set in both resources.
+	dt.Compare(api.Path{}.Pointer().Field("PrivateKey"), compareInputOnly)
+	dt.Compare(api.Path{}.Pointer().Field("SelfManaged").Pointer().Field("PrivateKey"), compareInputOnly)
+	// There is no update method.
+	dt.Immutable(api.Path{}.Pointer())
+
+	return dt
+}
+
+// compareInputOnly compares fields that are not returned by the API. An
+// empty value, as in the resource fetched from the Cloud, matches any value.
+func compareInputOnly(a, b any) bool {
+	return a == "" || b == "" || a == b
+}
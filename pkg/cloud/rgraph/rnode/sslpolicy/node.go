@@ -0,0 +1,81 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sslpolicy
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type sslPolicyNode struct {
+	rnode.NodeBase
+	resource SslPolicy
+}
+
+var _ rnode.Node = (*sslPolicyNode)(nil)
+
+func (n *sslPolicyNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *sslPolicyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*sslPolicyNode)
+	if !ok {
+		return nil, fmt.Errorf("SslPolicyNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("SslPolicyNode: Diff %w", err)
+	}
+	// All fields are Immutable() as SslPolicies cannot be updated.
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+func (n *sslPolicyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy](&ops{}, got, n, n.resource)
+	}
+
+	return nil, fmt.Errorf("SslPolicyNode: invalid plan op %s", op)
+}
+
+func (n *sslPolicyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sslpolicy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy] {
+	return &rnode.GetFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]{
+		GA: gcp.SslPolicies().Get,
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy] {
+	return &rnode.CreateFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]{
+		GA: gcp.SslPolicies().Insert,
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy] {
+	return &rnode.DeleteFuncs[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]{
+		GA: gcp.SslPolicies().Delete,
+	}
+}
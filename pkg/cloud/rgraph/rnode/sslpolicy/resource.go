@@ -0,0 +1,72 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sslpolicy implements the rnode for global SslPolicy resources.
+package sslpolicy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "sslPolicies"
+
+// SslPolicy is the frozen resource type.
+type SslPolicy = api.Resource[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]
+
+// MutableSslPolicy is the mutable resource type.
+type MutableSslPolicy = api.MutableResource[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]
+
+// ID of the SslPolicy resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableSslPolicy returns a new mutable SslPolicy.
+func NewMutableSslPolicy(project string, key *meta.Key) MutableSslPolicy {
+	return api.NewResource[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.SslPolicy, alpha.SslPolicy, beta.SslPolicy]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("EnabledFeatures"))
+	dt.OutputOnly(api.Path{}.Pointer().Field("Warnings"))
+	dt.Enum(api.Path{}.Pointer().Field("Profile"), "COMPATIBLE", "MODERN", "RESTRICTED", "CUSTOM")
+	dt.Enum(api.Path{}.Pointer().Field("MinTlsVersion"), "TLS_1_0", "TLS_1_1", "TLS_1_2")
+	dt.UnorderedSlice(api.Path{}.Pointer().Field("CustomFeatures"))
+	// There is no update method.
+	dt.Immutable(api.Path{}.Pointer())
+
+	return dt
+}
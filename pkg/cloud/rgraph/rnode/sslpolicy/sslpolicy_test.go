@@ -0,0 +1,145 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sslpolicy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"google.golang.org/api/compute/v1"
+)
+
+const proj = "proj-1"
+
+func newNode(t *testing.T, f func(*compute.SslPolicy)) rnode.Node {
+	t.Helper()
+	r := NewMutableSslPolicy(proj, meta.GlobalKey("pol"))
+	if err := r.Access(func(x *compute.SslPolicy) {
+		x.Name = "pol"
+		x.Profile = "CUSTOM"
+		x.MinTlsVersion = "TLS_1_2"
+		x.CustomFeatures = []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+		x.ForceSendFields = []string{"Description"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestSslPolicy(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	id := ID(proj, meta.GlobalKey("pol"))
+
+	b := NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil || b.State() != rnode.NodeDoesNotExist {
+		t.Fatalf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeDoesNotExist)
+	}
+	gotNode, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	want := newNode(t, nil)
+	want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	actions, err := want.Actions(gotNode)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+
+	b = NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil || b.State() != rnode.NodeExists {
+		t.Fatalf("SyncFromCloud() = %v, state %v; want nil, %v", err, b.State(), rnode.NodeExists)
+	}
+	got, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.SslPolicy)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name: "custom features order",
+			f: func(x *compute.SslPolicy) {
+				x.CustomFeatures = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}
+			},
+			wantOp: rnode.OpNothing,
+		},
+		{
+			name:   "min TLS version",
+			f:      func(x *compute.SslPolicy) { x.MinTlsVersion = "TLS_1_1" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		pd, err := newNode(t, tc.f).Diff(got)
+		if err != nil {
+			t.Fatalf("%s: Diff() = %v", tc.name, err)
+		}
+		if pd.Operation != tc.wantOp {
+			t.Errorf("%s: Diff() = %v, want op %v", tc.name, pd, tc.wantOp)
+		}
+	}
+
+}
+
+func TestBuilder(t *testing.T) {
+	n := newNode(t, nil)
+
+	// Builder() has no resource; it is used to fetch the got state.
+	b := n.Builder()
+	if b.Resource() != nil {
+		t.Errorf("Builder().Resource() = %v, want nil", b.Resource())
+	}
+	if !b.ID().Equal(n.ID()) || b.State() != n.State() || b.Ownership() != n.Ownership() {
+		t.Errorf("Builder() = %v %s %s, want %v %s %s", b.ID(), b.State(), b.Ownership(), n.ID(), n.State(), n.Ownership())
+	}
+
+	cb, err := rnode.CloneBuilder(n)
+	if err != nil {
+		t.Fatalf("CloneBuilder() = %v", err)
+	}
+	if cb.Resource() != n.Resource() {
+		t.Errorf("CloneBuilder().Resource() = %v, want %v", cb.Resource(), n.Resource())
+	}
+	cn, err := cb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	if pd, err := cn.Diff(n); err != nil || pd.Operation != rnode.OpNothing {
+		t.Errorf("Diff() = %v, %v; want %s", pd, err, rnode.OpNothing)
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpproxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type targetHttpProxyNode struct {
+	rnode.NodeBase
+	resource TargetHttpProxy
+}
+
+var _ rnode.Node = (*targetHttpProxyNode)(nil)
+
+func (n *targetHttpProxyNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *targetHttpProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetHttpProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetHttpProxyNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetHttpProxyNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+var urlMapPath = api.Path{}.Pointer().Field("UrlMap")
+
+func (n *targetHttpProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("TargetHttpProxyNode: invalid plan op %s", op)
+}
+
+func (n *targetHttpProxyNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("TargetHttpProxyNode: update %s: plan has no diff", n.ID())
+	}
+	for _, item := range details.Diff.Items {
+		if !item.Path.HasPrefix(urlMapPath) {
+			return nil, fmt.Errorf("TargetHttpProxyNode: update %s: field %s cannot be updated", n.ID(), item.Path)
+		}
+	}
+	r := n.resource
+	req, err := setUrlMapRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("TargetHttpProxyNode: update %s: %w", n.ID(), err)
+	}
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		if err := callSetUrlMap(ctx, gcp, key, req); err != nil {
+			return fmt.Errorf("TargetHttpProxy %s: SetUrlMap: %w", n.ID(), err)
+		}
+		return nil
+	}
+	call := exec.Call{
+		Method:  "SetUrlMap",
+		Version: r.Version(),
+		ID:      n.ID(),
+		Body:    exec.BodySummary(req),
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (UrlMap)", n.ID()),
+			rnode.UpdateEvents(got, n),
+			update,
+			call,
+		),
+	}, nil
+}
+
+// setUrlMapRequest returns the request for SetUrlMap() at the version of r.
+func setUrlMapRequest(r TargetHttpProxy) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		return &compute.UrlMapReference{UrlMap: obj.UrlMap}, nil
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, err
+		}
+		return &alpha.UrlMapReference{UrlMap: obj.UrlMap}, nil
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		return &beta.UrlMapReference{UrlMap: obj.UrlMap}, nil
+	}
+	return nil, fmt.Errorf("invalid version %q", r.Version())
+}
+
+// callSetUrlMap calls SetUrlMap() for the global or regional proxy key. The
+// API version is given by the type of req (see setUrlMapRequest()).
+func callSetUrlMap(ctx context.Context, gcp cloud.Cloud, key *meta.Key, req any) error {
+	regional := key.Type() == meta.Regional
+	switch req := req.(type) {
+	case *compute.UrlMapReference:
+		if regional {
+			return gcp.RegionTargetHttpProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.TargetHttpProxies().SetUrlMap(ctx, key, req)
+	case *alpha.UrlMapReference:
+		if regional {
+			return gcp.AlphaRegionTargetHttpProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.AlphaTargetHttpProxies().SetUrlMap(ctx, key, req)
+	case *beta.UrlMapReference:
+		if regional {
+			return gcp.BetaRegionTargetHttpProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.BetaTargetHttpProxies().SetUrlMap(ctx, key, req)
+	}
+	return fmt.Errorf("invalid request type %T", req)
+}
+
+func (n *targetHttpProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
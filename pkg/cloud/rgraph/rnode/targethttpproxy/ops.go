@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy] {
+	return &rnode.GetFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]{
+		GA:    rnode.GetByScope(gcp.TargetHttpProxies().Get, gcp.RegionTargetHttpProxies().Get),
+		Alpha: rnode.GetByScope(gcp.AlphaTargetHttpProxies().Get, gcp.AlphaRegionTargetHttpProxies().Get),
+		Beta:  rnode.GetByScope(gcp.BetaTargetHttpProxies().Get, gcp.BetaRegionTargetHttpProxies().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy] {
+	return &rnode.CreateFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]{
+		GA:    rnode.CreateByScope(gcp.TargetHttpProxies().Insert, gcp.RegionTargetHttpProxies().Insert),
+		Alpha: rnode.CreateByScope(gcp.AlphaTargetHttpProxies().Insert, gcp.AlphaRegionTargetHttpProxies().Insert),
+		Beta:  rnode.CreateByScope(gcp.BetaTargetHttpProxies().Insert, gcp.BetaRegionTargetHttpProxies().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy] {
+	return &rnode.DeleteFuncs[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]{
+		GA:    rnode.DeleteByScope(gcp.TargetHttpProxies().Delete, gcp.RegionTargetHttpProxies().Delete),
+		Alpha: rnode.DeleteByScope(gcp.AlphaTargetHttpProxies().Delete, gcp.AlphaRegionTargetHttpProxies().Delete),
+		Beta:  rnode.DeleteByScope(gcp.BetaTargetHttpProxies().Delete, gcp.BetaRegionTargetHttpProxies().Delete),
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package targethttpproxy implements the rnode for global and regional
+// TargetHttpProxy resources.
+package targethttpproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "targetHttpProxies"
+
+// TargetHttpProxy is the frozen resource type.
+type TargetHttpProxy = api.Resource[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]
+
+// MutableTargetHttpProxy is the mutable resource type.
+type MutableTargetHttpProxy = api.MutableResource[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]
+
+// ID of the TargetHttpProxy resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableTargetHttpProxy returns a new mutable TargetHttpProxy.
+func NewMutableTargetHttpProxy(project string, key *meta.Key) MutableTargetHttpProxy {
+	return api.NewResource[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.TargetHttpProxy, alpha.TargetHttpProxy, beta.TargetHttpProxy]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	// Only the UrlMap can be changed (with SetUrlMap()).
+	dt.Immutable(api.Path{}.Pointer().Field("Description"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Immutable(api.Path{}.Pointer().Field("ProxyBind"))
+	if v != meta.VersionGA {
+		dt.Immutable(api.Path{}.Pointer().Field("HttpFilters"))
+	}
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("UrlMap"), "urlMaps")
+
+	return dt
+}
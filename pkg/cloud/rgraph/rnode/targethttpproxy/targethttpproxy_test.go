@@ -0,0 +1,241 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj    = "proj-1"
+	region  = "us-central1"
+	umURL   = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+	um2URL  = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um2"
+	rumURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/urlMaps/um"
+	rum2URL = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/urlMaps/um2"
+)
+
+func newNode(t *testing.T, key *meta.Key, f func(*compute.TargetHttpProxy)) rnode.Node {
+	t.Helper()
+	r := NewMutableTargetHttpProxy(proj, key)
+	if err := r.Access(func(x *compute.TargetHttpProxy) {
+		x.Name = key.Name
+		x.UrlMap = umURL
+		if key.Type() == meta.Regional {
+			x.UrlMap = rumURL
+		}
+		x.ForceSendFields = []string{"Description", "ProxyBind"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	refs := newNode(t, meta.RegionalKey("thp", region), nil).OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.String())
+	}
+	want := []string{"*.UrlMap => compute/urlMaps:proj-1/us-central1/um"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	key := meta.GlobalKey("thp")
+	got := newNode(t, key, nil)
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.TargetHttpProxy)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "url map",
+			f:      func(x *compute.TargetHttpProxy) { x.UrlMap = um2URL },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "description",
+			f:      func(x *compute.TargetHttpProxy) { x.Description = "abc" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		pd, err := newNode(t, key, tc.f).Diff(got)
+		if err != nil {
+			t.Fatalf("%s: Diff() = %v", tc.name, err)
+		}
+		if pd.Operation != tc.wantOp {
+			t.Errorf("%s: Diff() = %v, want op %v", tc.name, pd, tc.wantOp)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	m.MockTargetHttpProxies.SetUrlMapHook = mock.SetURLMapTargetHTTPProxyHook
+	m.MockRegionTargetHttpProxies.SetUrlMapHook = mock.SetURLMapRegionTargetHTTPProxyHook
+
+	for _, tc := range []struct {
+		key      *meta.Key
+		newURL   string
+		wantCall string
+	}{
+		{
+			key:      meta.GlobalKey("thp"),
+			newURL:   um2URL,
+			wantCall: `SetUrlMap ga compute/targetHttpProxies:proj-1/thp {"urlMap":"` + um2URL + `"}`,
+		},
+		{
+			key:      meta.RegionalKey("thp", region),
+			newURL:   rum2URL,
+			wantCall: `SetUrlMap ga compute/targetHttpProxies:proj-1/us-central1/thp {"urlMap":"` + rum2URL + `"}`,
+		},
+	} {
+		got := newNode(t, tc.key, nil)
+		want := newNode(t, tc.key, func(x *compute.TargetHttpProxy) { x.UrlMap = tc.newURL })
+		gotObj, _ := got.Resource().(TargetHttpProxy).ToGA()
+		if tc.key.Type() == meta.Regional {
+			m.RegionTargetHttpProxies().Insert(ctx, tc.key, gotObj)
+		} else {
+			m.TargetHttpProxies().Insert(ctx, tc.key, gotObj)
+		}
+
+		pd, err := want.Diff(got)
+		if err != nil {
+			t.Fatalf("Diff() = %v", err)
+		}
+		want.Plan().Set(*pd)
+		actions, err := want.Actions(got)
+		if err != nil {
+			t.Fatalf("Actions() = %v, want nil", err)
+		}
+		var calls []string
+		for _, a := range actions {
+			if cd, ok := a.(exec.CallDescriber); ok {
+				for _, c := range cd.Calls() {
+					calls = append(calls, c.String())
+				}
+			}
+			if _, err := a.Run(ctx, m); err != nil {
+				t.Fatalf("%v.Run() = %v, want nil", a, err)
+			}
+		}
+		if diff := cmp.Diff(calls, []string{tc.wantCall}); diff != "" {
+			t.Errorf("Calls(); -got,+want: %s", diff)
+		}
+
+		var (
+			thp    *compute.TargetHttpProxy
+			getErr error
+		)
+		if tc.key.Type() == meta.Regional {
+			thp, getErr = m.RegionTargetHttpProxies().Get(ctx, tc.key)
+		} else {
+			thp, getErr = m.TargetHttpProxies().Get(ctx, tc.key)
+		}
+		if getErr != nil || thp.UrlMap != tc.newURL {
+			t.Errorf("Get(%v) = %+v, %v; want UrlMap=%s", tc.key, thp, getErr, tc.newURL)
+		}
+	}
+}
+
+func TestUpdateAlpha(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var gotURL string
+	m.MockAlphaTargetHttpProxies.SetUrlMapHook = func(_ context.Context, _ *meta.Key, req *alpha.UrlMapReference, _ *cloud.MockAlphaTargetHttpProxies) error {
+		gotURL = req.UrlMap
+		return nil
+	}
+	key := meta.GlobalKey("thp")
+
+	// HttpFilters is only in the alpha API.
+	newAlphaNode := func(url string) rnode.Node {
+		r := NewMutableTargetHttpProxy(proj, key)
+		if err := r.AccessAlpha(func(x *alpha.TargetHttpProxy) {
+			x.Name = key.Name
+			x.UrlMap = url
+			x.HttpFilters = []string{"filter"}
+			x.ForceSendFields = []string{"Description", "ProxyBind", "SelfLinkWithId"}
+		}); err != nil {
+			t.Fatalf("AccessAlpha() = %v", err)
+		}
+		fr, err := r.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		b := NewBuilderWithResource(fr)
+		b.SetState(rnode.NodeExists)
+		b.SetOwnership(rnode.OwnershipManaged)
+		n, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		return n
+	}
+	got := newAlphaNode(umURL)
+	want := newAlphaNode(um2URL)
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v", err)
+	}
+	want.Plan().Set(*pd)
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	var calls []string
+	for _, a := range actions {
+		if cd, ok := a.(exec.CallDescriber); ok {
+			for _, c := range cd.Calls() {
+				calls = append(calls, c.String())
+			}
+		}
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+	wantCall := `SetUrlMap alpha compute/targetHttpProxies:proj-1/thp {"urlMap":"` + um2URL + `"}`
+	if diff := cmp.Diff(calls, []string{wantCall}); diff != "" {
+		t.Errorf("Calls(); -got,+want: %s", diff)
+	}
+	if gotURL != um2URL {
+		t.Errorf("alpha SetUrlMap(%q), want %q", gotURL, um2URL)
+	}
+}
@@ -0,0 +1,306 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpsproxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+// updatableFields can be changed without recreating the resource. Each field
+// is updated with the corresponding Set*() method. Regional proxies only
+// support a subset of the methods (see regionalUpdatableFields).
+var updatableFields = []string{
+	"CertificateMap",
+	"SslCertificates",
+	"SslPolicy",
+	"UrlMap",
+}
+
+var regionalUpdatableFields = []string{
+	"SslCertificates",
+	"UrlMap",
+}
+
+type targetHttpsProxyNode struct {
+	rnode.NodeBase
+	resource TargetHttpsProxy
+}
+
+var _ rnode.Node = (*targetHttpsProxyNode)(nil)
+
+func (n *targetHttpsProxyNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *targetHttpsProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetHttpsProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetHttpsProxyNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetHttpsProxyNode: Diff %w", err)
+	}
+	diff = n.IgnoreDiff(diff)
+	if n.ID().Key.Type() == meta.Regional {
+		// The field traits are the same for global and regional proxies
+		// but regional proxies do not have SetCertificateMap() and
+		// SetSslPolicy().
+		for i, item := range diff.Items {
+			if n.updatableField(item.Path) == "" {
+				diff.Items[i].Immutable = true
+			}
+		}
+	}
+	return rnode.PlanForDiff(diff), nil
+}
+
+// fields returns the updatable fields for the scope of the node.
+func (n *targetHttpsProxyNode) fields() []string {
+	if n.ID().Key.Type() == meta.Regional {
+		return regionalUpdatableFields
+	}
+	return updatableFields
+}
+
+// updatableField returns the name of the updatable top-level field that
+// contains p. Returns "" if the field cannot be updated.
+func (n *targetHttpsProxyNode) updatableField(p api.Path) string {
+	for _, f := range n.fields() {
+		if p.HasPrefix(api.Path{}.Pointer().Field(f)) {
+			return f
+		}
+	}
+	return ""
+}
+
+func (n *targetHttpsProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("TargetHttpsProxyNode: invalid plan op %s", op)
+}
+
+func (n *targetHttpsProxyNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("TargetHttpsProxyNode: update %s: plan has no diff", n.ID())
+	}
+	changed := map[string]bool{}
+	for _, item := range details.Diff.Items {
+		field := n.updatableField(item.Path)
+		if field == "" {
+			return nil, fmt.Errorf("TargetHttpsProxyNode: update %s: field %s cannot be updated", n.ID(), item.Path)
+		}
+		changed[field] = true
+	}
+	r := n.resource
+	key := n.ID().Key
+	regional := key.Type() == meta.Regional
+	var (
+		fields []string
+		reqs   []any
+	)
+	for _, f := range n.fields() {
+		if !changed[f] {
+			continue
+		}
+		req, err := setRequest(r, f, regional)
+		if err != nil {
+			return nil, fmt.Errorf("TargetHttpsProxyNode: update %s: %w", n.ID(), err)
+		}
+		fields = append(fields, f)
+		reqs = append(reqs, req)
+	}
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		for i, f := range fields {
+			if err := callSet(ctx, gcp, key, reqs[i]); err != nil {
+				return fmt.Errorf("TargetHttpsProxy %s: Set%s: %w", n.ID(), f, err)
+			}
+		}
+		return nil
+	}
+	var calls []exec.Call
+	for i, f := range fields {
+		calls = append(calls, exec.Call{
+			Method:  "Set" + f,
+			Version: r.Version(),
+			ID:      n.ID(),
+			Body:    exec.BodySummary(reqs[i]),
+		})
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (%s)", n.ID(), strings.Join(fields, ", ")),
+			rnode.UpdateEvents(got, n),
+			update,
+			calls...,
+		),
+	}, nil
+}
+
+// setRequest returns the request for the Set*() method that updates field f
+// at the version of r.
+func setRequest(r TargetHttpsProxy, f string, regional bool) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &compute.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "SslCertificates":
+			if regional {
+				return &compute.RegionTargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+			}
+			return &compute.TargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			// An empty SslPolicy clears the policy.
+			return &compute.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		case "UrlMap":
+			return &compute.UrlMapReference{UrlMap: obj.UrlMap}, nil
+		}
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &alpha.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "SslCertificates":
+			if regional {
+				return &alpha.RegionTargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+			}
+			return &alpha.TargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			return &alpha.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		case "UrlMap":
+			return &alpha.UrlMapReference{UrlMap: obj.UrlMap}, nil
+		}
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &beta.TargetHttpsProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "SslCertificates":
+			if regional {
+				return &beta.RegionTargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+			}
+			return &beta.TargetHttpsProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			return &beta.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		case "UrlMap":
+			return &beta.UrlMapReference{UrlMap: obj.UrlMap}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s cannot be set for version %q", f, r.Version())
+}
+
+// callSet calls the Set*() method for the request req on the global or
+// regional proxy key. The method and the API version are given by the type
+// of req (see setRequest()).
+func callSet(ctx context.Context, gcp cloud.Cloud, key *meta.Key, req any) error {
+	regional := key.Type() == meta.Regional
+	switch req := req.(type) {
+	case *compute.TargetHttpsProxiesSetCertificateMapRequest:
+		return gcp.TargetHttpsProxies().SetCertificateMap(ctx, key, req)
+	case *compute.RegionTargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.RegionTargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *compute.TargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.TargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *compute.SslPolicyReference:
+		return gcp.TargetHttpsProxies().SetSslPolicy(ctx, key, req)
+	case *compute.UrlMapReference:
+		if regional {
+			return gcp.RegionTargetHttpsProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.TargetHttpsProxies().SetUrlMap(ctx, key, req)
+	case *alpha.TargetHttpsProxiesSetCertificateMapRequest:
+		return gcp.AlphaTargetHttpsProxies().SetCertificateMap(ctx, key, req)
+	case *alpha.RegionTargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.AlphaRegionTargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *alpha.TargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.AlphaTargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *alpha.SslPolicyReference:
+		return gcp.AlphaTargetHttpsProxies().SetSslPolicy(ctx, key, req)
+	case *alpha.UrlMapReference:
+		if regional {
+			return gcp.AlphaRegionTargetHttpsProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.AlphaTargetHttpsProxies().SetUrlMap(ctx, key, req)
+	case *beta.TargetHttpsProxiesSetCertificateMapRequest:
+		return gcp.BetaTargetHttpsProxies().SetCertificateMap(ctx, key, req)
+	case *beta.RegionTargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.BetaRegionTargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *beta.TargetHttpsProxiesSetSslCertificatesRequest:
+		return gcp.BetaTargetHttpsProxies().SetSslCertificates(ctx, key, req)
+	case *beta.SslPolicyReference:
+		return gcp.BetaTargetHttpsProxies().SetSslPolicy(ctx, key, req)
+	case *beta.UrlMapReference:
+		if regional {
+			return gcp.BetaRegionTargetHttpsProxies().SetUrlMap(ctx, key, req)
+		}
+		return gcp.BetaTargetHttpsProxies().SetUrlMap(ctx, key, req)
+	}
+	return fmt.Errorf("invalid request type %T", req)
+}
+
+func (n *targetHttpsProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpsproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.GetFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA:    rnode.GetByScope(gcp.TargetHttpsProxies().Get, gcp.RegionTargetHttpsProxies().Get),
+		Alpha: rnode.GetByScope(gcp.AlphaTargetHttpsProxies().Get, gcp.AlphaRegionTargetHttpsProxies().Get),
+		Beta:  rnode.GetByScope(gcp.BetaTargetHttpsProxies().Get, gcp.BetaRegionTargetHttpsProxies().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.CreateFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA:    rnode.CreateByScope(gcp.TargetHttpsProxies().Insert, gcp.RegionTargetHttpsProxies().Insert),
+		Alpha: rnode.CreateByScope(gcp.AlphaTargetHttpsProxies().Insert, gcp.AlphaRegionTargetHttpsProxies().Insert),
+		Beta:  rnode.CreateByScope(gcp.BetaTargetHttpsProxies().Insert, gcp.BetaRegionTargetHttpsProxies().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy] {
+	return &rnode.DeleteFuncs[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]{
+		GA:    rnode.DeleteByScope(gcp.TargetHttpsProxies().Delete, gcp.RegionTargetHttpsProxies().Delete),
+		Alpha: rnode.DeleteByScope(gcp.AlphaTargetHttpsProxies().Delete, gcp.AlphaRegionTargetHttpsProxies().Delete),
+		Beta:  rnode.DeleteByScope(gcp.BetaTargetHttpsProxies().Delete, gcp.BetaRegionTargetHttpsProxies().Delete),
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package targethttpsproxy implements the rnode for global and regional
+// TargetHttpsProxy resources.
+package targethttpsproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "targetHttpsProxies"
+
+// TargetHttpsProxy is the frozen resource type.
+type TargetHttpsProxy = api.Resource[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]
+
+// MutableTargetHttpsProxy is the mutable resource type.
+type MutableTargetHttpsProxy = api.MutableResource[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]
+
+// ID of the TargetHttpsProxy resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableTargetHttpsProxy returns a new mutable TargetHttpsProxy.
+func NewMutableTargetHttpsProxy(project string, key *meta.Key) MutableTargetHttpsProxy {
+	return api.NewResource[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.TargetHttpsProxy, alpha.TargetHttpsProxy, beta.TargetHttpsProxy]
+}
+
+func (*typeTrait) FieldTraits(v meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.Enum(api.Path{}.Pointer().Field("QuicOverride"), "NONE", "ENABLE", "DISABLE")
+	// Fields other than the updatableFields cannot be changed in place.
+	dt.Immutable(api.Path{}.Pointer().Field("AuthorizationPolicy"))
+	dt.Immutable(api.Path{}.Pointer().Field("Description"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Immutable(api.Path{}.Pointer().Field("ProxyBind"))
+	dt.Immutable(api.Path{}.Pointer().Field("QuicOverride"))
+	dt.Immutable(api.Path{}.Pointer().Field("ServerTlsPolicy"))
+	if v != meta.VersionGA {
+		dt.Immutable(api.Path{}.Pointer().Field("Authentication"))
+		dt.Immutable(api.Path{}.Pointer().Field("Authorization"))
+		dt.Immutable(api.Path{}.Pointer().Field("HttpFilters"))
+	}
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("UrlMap"), "urlMaps")
+	dt.Reference(api.Path{}.Pointer().Field("SslCertificates").AnySliceIndex(), "sslCertificates")
+	dt.Reference(api.Path{}.Pointer().Field("SslPolicy"), "sslPolicies")
+	// AuthorizationPolicy, CertificateMap and ServerTlsPolicy are not
+	// compute resources and are not modeled in the graph.
+
+	return dt
+}
@@ -0,0 +1,211 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethttpsproxy
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj    = "proj-1"
+	region  = "us-central1"
+	umURL   = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um"
+	certURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslCertificates/cert"
+	polURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslPolicies/pol"
+)
+
+func newNode(t *testing.T, key *meta.Key, f func(*compute.TargetHttpsProxy)) rnode.Node {
+	t.Helper()
+	return newNodeWithAlpha(t, key, f, nil)
+}
+
+// newNodeWithAlpha returns a Node with fAlpha applied to the alpha version
+// of the resource after f. fAlpha is optional.
+func newNodeWithAlpha(t *testing.T, key *meta.Key, f func(*compute.TargetHttpsProxy), fAlpha func(*alpha.TargetHttpsProxy)) rnode.Node {
+	t.Helper()
+	r := NewMutableTargetHttpsProxy(proj, key)
+	if err := r.Access(func(x *compute.TargetHttpsProxy) {
+		x.Name = key.Name
+		x.UrlMap = umURL
+		x.SslCertificates = []string{certURL}
+		x.QuicOverride = "NONE"
+		x.ForceSendFields = []string{"AuthorizationPolicy", "CertificateMap", "Description", "ProxyBind", "ServerTlsPolicy", "SslPolicy"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	if fAlpha != nil {
+		if err := r.AccessAlpha(fAlpha); err != nil {
+			t.Fatalf("AccessAlpha() = %v", err)
+		}
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	n := newNode(t, meta.GlobalKey("thp"), func(x *compute.TargetHttpsProxy) { x.SslPolicy = polURL })
+	refs := n.OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.Resource)
+	}
+	want := []string{
+		"*.UrlMap => urlMaps",
+		"*.SslCertificates!0 => sslCertificates",
+		"*.SslPolicy => sslPolicies",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		key    *meta.Key
+		f      func(*compute.TargetHttpsProxy)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", key: meta.GlobalKey("thp"), wantOp: rnode.OpNothing},
+		{
+			name:   "ssl policy",
+			key:    meta.GlobalKey("thp"),
+			f:      func(x *compute.TargetHttpsProxy) { x.SslPolicy = polURL },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			// Regional proxies do not have SetSslPolicy().
+			name:   "regional ssl policy",
+			key:    meta.RegionalKey("thp", region),
+			f:      func(x *compute.TargetHttpsProxy) { x.SslPolicy = polURL },
+			wantOp: rnode.OpRecreate,
+		},
+		{
+			name: "regional ssl certificates",
+			key:  meta.RegionalKey("thp", region),
+			f: func(x *compute.TargetHttpsProxy) {
+				x.SslCertificates = append(x.SslCertificates, "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/sslCertificates/cert2")
+			},
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "quic override",
+			key:    meta.GlobalKey("thp"),
+			f:      func(x *compute.TargetHttpsProxy) { x.QuicOverride = "ENABLE" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNode(t, tc.key, nil)
+			pd, err := newNode(t, tc.key, tc.f).Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %v, want op %v", pd, tc.wantOp)
+			}
+		})
+	}
+}
+
+func TestCalls(t *testing.T) {
+	// Authentication is only in the alpha API.
+	alphaOnly := func(x *alpha.TargetHttpsProxy) {
+		x.Authentication = "auth"
+		x.ForceSendFields = append(x.ForceSendFields, "Authorization", "HttpFilters", "SelfLinkWithId")
+	}
+	for _, tc := range []struct {
+		name   string
+		key    *meta.Key
+		f      func(*compute.TargetHttpsProxy)
+		fAlpha func(*alpha.TargetHttpsProxy)
+		want   []string
+	}{
+		{
+			name: "global",
+			key:  meta.GlobalKey("thp"),
+			f: func(x *compute.TargetHttpsProxy) {
+				x.SslPolicy = polURL
+				x.UrlMap = "https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um2"
+			},
+			want: []string{
+				`SetSslPolicy ga compute/targetHttpsProxies:proj-1/thp {"sslPolicy":"` + polURL + `"}`,
+				`SetUrlMap ga compute/targetHttpsProxies:proj-1/thp {"urlMap":"https://www.googleapis.com/compute/v1/projects/proj-1/global/urlMaps/um2"}`,
+			},
+		},
+		{
+			name: "regional",
+			key:  meta.RegionalKey("thp", region),
+			f: func(x *compute.TargetHttpsProxy) {
+				x.SslCertificates = []string{"https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/sslCertificates/cert2"}
+			},
+			want: []string{
+				`SetSslCertificates ga compute/targetHttpsProxies:proj-1/us-central1/thp {"sslCertificates":["https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/sslCertificates/cert2"]}`,
+			},
+		},
+		{
+			name: "alpha",
+			key:  meta.GlobalKey("thp"),
+			f: func(x *compute.TargetHttpsProxy) {
+				x.SslPolicy = polURL
+			},
+			fAlpha: alphaOnly,
+			want: []string{
+				`SetSslPolicy alpha compute/targetHttpsProxies:proj-1/thp {"sslPolicy":"` + polURL + `"}`,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := newNodeWithAlpha(t, tc.key, nil, tc.fAlpha)
+			want := newNodeWithAlpha(t, tc.key, tc.f, tc.fAlpha)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v", err)
+			}
+			want.Plan().Set(*pd)
+			actions, err := want.Actions(got)
+			if err != nil {
+				t.Fatalf("Actions() = %v, want nil", err)
+			}
+			var gotCalls []string
+			for _, a := range actions {
+				if cd, ok := a.(exec.CallDescriber); ok {
+					for _, c := range cd.Calls() {
+						gotCalls = append(gotCalls, c.String())
+					}
+				}
+			}
+			if diff := cmp.Diff(gotCalls, tc.want); diff != "" {
+				t.Errorf("Calls(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
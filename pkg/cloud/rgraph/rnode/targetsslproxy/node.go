@@ -0,0 +1,264 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetsslproxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type targetSslProxyNode struct {
+	rnode.NodeBase
+	resource TargetSslProxy
+}
+
+var _ rnode.Node = (*targetSslProxyNode)(nil)
+
+func (n *targetSslProxyNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *targetSslProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetSslProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetSslProxyNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetSslProxyNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+// fieldName returns the name of the top-level field that contains p.
+func fieldName(p api.Path) string {
+	if len(p) < 2 || len(p[1]) < 2 || p[1][0] != '.' {
+		return p.String()
+	}
+	return p[1][1:]
+}
+
+func (n *targetSslProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("TargetSslProxyNode: invalid plan op %s", op)
+}
+
+func (n *targetSslProxyNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("TargetSslProxyNode: update %s: plan has no diff", n.ID())
+	}
+	r := n.resource
+	var (
+		fields []string
+		reqs   []any
+	)
+	for _, item := range details.Diff.Items {
+		f := fieldName(item.Path)
+		if contains(fields, f) {
+			continue
+		}
+		req, err := setRequest(r, f)
+		if err != nil {
+			return nil, fmt.Errorf("TargetSslProxyNode: update %s: %w", n.ID(), err)
+		}
+		fields = append(fields, f)
+		reqs = append(reqs, req)
+	}
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		for i, f := range fields {
+			if err := callSet(ctx, gcp, key, reqs[i]); err != nil {
+				return fmt.Errorf("TargetSslProxy %s: %s: %w", n.ID(), setMethod(f), err)
+			}
+		}
+		return nil
+	}
+	var calls []exec.Call
+	for i, f := range fields {
+		calls = append(calls, exec.Call{
+			Method:  setMethod(f),
+			Version: r.Version(),
+			ID:      n.ID(),
+			Body:    exec.BodySummary(reqs[i]),
+		})
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (%s)", n.ID(), strings.Join(fields, ", ")),
+			rnode.UpdateEvents(got, n),
+			update,
+			calls...,
+		),
+	}, nil
+}
+
+// setRequest returns the request for the Set*() method that updates field f
+// at the version of r.
+func setRequest(r TargetSslProxy, f string) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &compute.TargetSslProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "ProxyHeader":
+			return &compute.TargetSslProxiesSetProxyHeaderRequest{ProxyHeader: obj.ProxyHeader}, nil
+		case "Service":
+			return &compute.TargetSslProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+		case "SslCertificates":
+			return &compute.TargetSslProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			// An empty SslPolicy clears the policy.
+			return &compute.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		}
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &alpha.TargetSslProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "ProxyHeader":
+			return &alpha.TargetSslProxiesSetProxyHeaderRequest{ProxyHeader: obj.ProxyHeader}, nil
+		case "Service":
+			return &alpha.TargetSslProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+		case "SslCertificates":
+			return &alpha.TargetSslProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			return &alpha.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		}
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		switch f {
+		case "CertificateMap":
+			return &beta.TargetSslProxiesSetCertificateMapRequest{CertificateMap: obj.CertificateMap}, nil
+		case "ProxyHeader":
+			return &beta.TargetSslProxiesSetProxyHeaderRequest{ProxyHeader: obj.ProxyHeader}, nil
+		case "Service":
+			return &beta.TargetSslProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+		case "SslCertificates":
+			return &beta.TargetSslProxiesSetSslCertificatesRequest{SslCertificates: obj.SslCertificates}, nil
+		case "SslPolicy":
+			return &beta.SslPolicyReference{SslPolicy: obj.SslPolicy}, nil
+		}
+	}
+	return nil, fmt.Errorf("%s cannot be set for version %q", f, r.Version())
+}
+
+// callSet calls the Set*() method for the request req. The method and the
+// API version are given by the type of req (see setRequest()).
+func callSet(ctx context.Context, gcp cloud.Cloud, key *meta.Key, req any) error {
+	switch req := req.(type) {
+	case *compute.TargetSslProxiesSetCertificateMapRequest:
+		return gcp.TargetSslProxies().SetCertificateMap(ctx, key, req)
+	case *compute.TargetSslProxiesSetProxyHeaderRequest:
+		return gcp.TargetSslProxies().SetProxyHeader(ctx, key, req)
+	case *compute.TargetSslProxiesSetBackendServiceRequest:
+		return gcp.TargetSslProxies().SetBackendService(ctx, key, req)
+	case *compute.TargetSslProxiesSetSslCertificatesRequest:
+		return gcp.TargetSslProxies().SetSslCertificates(ctx, key, req)
+	case *compute.SslPolicyReference:
+		return gcp.TargetSslProxies().SetSslPolicy(ctx, key, req)
+	case *alpha.TargetSslProxiesSetCertificateMapRequest:
+		return gcp.AlphaTargetSslProxies().SetCertificateMap(ctx, key, req)
+	case *alpha.TargetSslProxiesSetProxyHeaderRequest:
+		return gcp.AlphaTargetSslProxies().SetProxyHeader(ctx, key, req)
+	case *alpha.TargetSslProxiesSetBackendServiceRequest:
+		return gcp.AlphaTargetSslProxies().SetBackendService(ctx, key, req)
+	case *alpha.TargetSslProxiesSetSslCertificatesRequest:
+		return gcp.AlphaTargetSslProxies().SetSslCertificates(ctx, key, req)
+	case *alpha.SslPolicyReference:
+		return gcp.AlphaTargetSslProxies().SetSslPolicy(ctx, key, req)
+	case *beta.TargetSslProxiesSetCertificateMapRequest:
+		return gcp.BetaTargetSslProxies().SetCertificateMap(ctx, key, req)
+	case *beta.TargetSslProxiesSetProxyHeaderRequest:
+		return gcp.BetaTargetSslProxies().SetProxyHeader(ctx, key, req)
+	case *beta.TargetSslProxiesSetBackendServiceRequest:
+		return gcp.BetaTargetSslProxies().SetBackendService(ctx, key, req)
+	case *beta.TargetSslProxiesSetSslCertificatesRequest:
+		return gcp.BetaTargetSslProxies().SetSslCertificates(ctx, key, req)
+	case *beta.SslPolicyReference:
+		return gcp.BetaTargetSslProxies().SetSslPolicy(ctx, key, req)
+	}
+	return fmt.Errorf("invalid request type %T", req)
+}
+
+func contains(l []string, v string) bool {
+	for _, x := range l {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// setMethod returns the name of the method that updates field f.
+func setMethod(f string) string {
+	if f == "Service" {
+		return "SetBackendService"
+	}
+	return "Set" + f
+}
+
+func (n *targetSslProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetsslproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy] {
+	return &rnode.GetFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]{
+		GA:    gcp.TargetSslProxies().Get,
+		Alpha: gcp.AlphaTargetSslProxies().Get,
+		Beta:  gcp.BetaTargetSslProxies().Get,
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy] {
+	return &rnode.CreateFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]{
+		GA:    gcp.TargetSslProxies().Insert,
+		Alpha: gcp.AlphaTargetSslProxies().Insert,
+		Beta:  gcp.BetaTargetSslProxies().Insert,
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy] {
+	return &rnode.DeleteFuncs[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]{
+		GA:    gcp.TargetSslProxies().Delete,
+		Alpha: gcp.AlphaTargetSslProxies().Delete,
+		Beta:  gcp.BetaTargetSslProxies().Delete,
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package targetsslproxy implements the rnode for TargetSslProxy resources.
+package targetsslproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "targetSslProxies"
+
+// TargetSslProxy is the frozen resource type.
+type TargetSslProxy = api.Resource[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]
+
+// MutableTargetSslProxy is the mutable resource type.
+type MutableTargetSslProxy = api.MutableResource[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]
+
+// ID of the TargetSslProxy resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableTargetSslProxy returns a new mutable TargetSslProxy.
+func NewMutableTargetSslProxy(project string, key *meta.Key) MutableTargetSslProxy {
+	return api.NewResource[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.TargetSslProxy, alpha.TargetSslProxy, beta.TargetSslProxy]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	// Resource-specific
+	// Fields without a Set*() method cannot be changed in place.
+	dt.Immutable(api.Path{}.Pointer().Field("Description"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("Service"), "backendServices")
+	dt.Reference(api.Path{}.Pointer().Field("SslCertificates").AnySliceIndex(), "sslCertificates")
+	dt.Reference(api.Path{}.Pointer().Field("SslPolicy"), "sslPolicies")
+	// CertificateMap is a Certificate Manager resource and is not modeled in
+	// the graph.
+
+	return dt
+}
@@ -0,0 +1,397 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targetsslproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj    = "proj-1"
+	bsURL   = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs"
+	certURL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslCertificates/cert"
+)
+
+func newMock() *cloud.MockGCE {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	m.MockTargetSslProxies.SetBackendServiceHook = mock.SetBackendServiceTargetSSLProxyHook
+	m.MockTargetSslProxies.SetSslCertificatesHook = mock.SetSslCertificatesTargetSSLProxyHook
+	m.MockTargetSslProxies.SetSslPolicyHook = mock.SetSslPolicyTargetSSLProxyHook
+	m.MockTargetSslProxies.SetProxyHeaderHook = mock.SetProxyHeaderTargetSSLProxyHook
+	m.MockTargetSslProxies.SetCertificateMapHook = mock.SetCertificateMapTargetSSLProxyHook
+	return m
+}
+
+func newNode(t *testing.T, f func(*compute.TargetSslProxy)) rnode.Node {
+	t.Helper()
+	return newNodeAtVersion(t, meta.VersionGA, f)
+}
+
+// newNodeAtVersion returns a Node with a resource frozen at version ver.
+func newNodeAtVersion(t *testing.T, ver meta.Version, f func(*compute.TargetSslProxy)) rnode.Node {
+	t.Helper()
+	r := NewMutableTargetSslProxy(proj, meta.GlobalKey("tsp"))
+	r.VersionPreference(ver)
+	if err := r.Access(func(x *compute.TargetSslProxy) {
+		x.Name = "tsp"
+		x.Service = bsURL
+		x.SslCertificates = []string{certURL}
+		x.ProxyHeader = "NONE"
+		x.ForceSendFields = []string{"CertificateMap", "Description", "SslPolicy"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestSyncFromCloud(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	id := ID(proj, meta.GlobalKey("tsp"))
+
+	b := NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if b.State() != rnode.NodeDoesNotExist {
+		t.Errorf("State() = %v, want %v", b.State(), rnode.NodeDoesNotExist)
+	}
+
+	m.TargetSslProxies().Insert(ctx, id.Key, &compute.TargetSslProxy{Name: "tsp", Service: bsURL, SslCertificates: []string{certURL}})
+	b = NewBuilder(id)
+	if err := b.SyncFromCloud(ctx, m); err != nil {
+		t.Fatalf("SyncFromCloud() = %v, want nil", err)
+	}
+	if b.State() != rnode.NodeExists {
+		t.Errorf("State() = %v, want %v", b.State(), rnode.NodeExists)
+	}
+	refs, err := b.OutRefs()
+	if err != nil {
+		t.Fatalf("OutRefs() = %v, want nil", err)
+	}
+	if len(refs) != 2 {
+		t.Errorf("OutRefs() = %v, want 2 refs", refs)
+	}
+}
+
+func TestOutRefs(t *testing.T) {
+	n := newNode(t, func(x *compute.TargetSslProxy) {
+		x.SslPolicy = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslPolicies/pol"
+	})
+	refs := n.OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.Resource)
+	}
+	want := []string{
+		"*.Service => backendServices",
+		"*.SslCertificates!0 => sslCertificates",
+		"*.SslPolicy => sslPolicies",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+
+	// Reference to the wrong type of resource.
+	r := NewMutableTargetSslProxy(proj, meta.GlobalKey("tsp"))
+	r.Access(func(x *compute.TargetSslProxy) {
+		x.Name = "tsp"
+		x.SslPolicy = bsURL
+	})
+	fr, _ := r.Freeze()
+	if _, err := NewBuilderWithResource(fr).OutRefs(); err == nil {
+		t.Error("OutRefs() = _, nil; want error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := newNode(t, nil)
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.TargetSslProxy)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "proxy header",
+			f:      func(x *compute.TargetSslProxy) { x.ProxyHeader = "PROXY_V1" },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name: "ssl policy",
+			f: func(x *compute.TargetSslProxy) {
+				x.SslPolicy = "https://www.googleapis.com/compute/v1/projects/proj-1/global/sslPolicies/p"
+			},
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "description",
+			f:      func(x *compute.TargetSslProxy) { x.Description = "abc" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want := newNode(t, tc.f)
+			pd, err := want.Diff(got)
+			if err != nil {
+				t.Fatalf("Diff() = %v, want nil", err)
+			}
+			if pd.Operation != tc.wantOp {
+				t.Errorf("Diff() = %v, want op %v", pd, tc.wantOp)
+			}
+			if gotRecreate := len(pd.RecreatePaths) > 0; gotRecreate != (tc.wantOp == rnode.OpRecreate) {
+				t.Errorf("Diff().RecreatePaths = %v; want set only for %v", pd.RecreatePaths, rnode.OpRecreate)
+			}
+		})
+	}
+}
+
+func TestActions(t *testing.T) {
+	ctx := context.Background()
+	m := newMock()
+	key := meta.GlobalKey("tsp")
+
+	// Create.
+	got := newNode(t, nil)
+	gotB := NewBuilder(ID(proj, key))
+	gotB.SetState(rnode.NodeDoesNotExist)
+	gotNode, err := gotB.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	want := newNode(t, nil)
+	want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+	runActions(t, m, want, gotNode)
+	if _, err := m.TargetSslProxies().Get(ctx, key); err != nil {
+		t.Fatalf("Get() after create = %v, want nil", err)
+	}
+
+	// Update.
+	want = newNode(t, func(x *compute.TargetSslProxy) { x.ProxyHeader = "PROXY_V1" })
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v", err)
+	}
+	want.Plan().Set(*pd)
+	runActions(t, m, want, got)
+	tsp, err := m.TargetSslProxies().Get(ctx, key)
+	if err != nil || tsp.ProxyHeader != "PROXY_V1" {
+		t.Errorf("Get() after update = %+v, %v; want ProxyHeader=PROXY_V1", tsp, err)
+	}
+
+	// Delete.
+	want = newNode(t, nil)
+	want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpDelete})
+	runActions(t, m, want, got)
+	if _, err := m.TargetSslProxies().Get(ctx, key); err == nil {
+		t.Errorf("Get() after delete = _, nil; want error")
+	}
+}
+
+func runActions(t *testing.T, m cloud.Cloud, want, got rnode.Node) {
+	t.Helper()
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(context.Background(), m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+}
+
+func TestResumeCreate(t *testing.T) {
+	ctx := context.Background()
+	key := meta.GlobalKey("tsp")
+
+	createAction := func(t *testing.T, f func(*compute.TargetSslProxy)) exec.ResumableAction {
+		t.Helper()
+		gotB := NewBuilder(ID(proj, key))
+		gotB.SetState(rnode.NodeDoesNotExist)
+		got, err := gotB.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		want := newNode(t, f)
+		want.Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+		actions, err := want.Actions(got)
+		if err != nil || len(actions) != 1 {
+			t.Fatalf("Actions() = %v, %v; want 1 action", actions, err)
+		}
+		ra, ok := actions[0].(exec.ResumableAction)
+		if !ok {
+			t.Fatalf("%v is not a ResumableAction", actions[0])
+		}
+		return ra
+	}
+
+	// The test owns the resources named "tsp".
+	adopt := func(id *cloud.ResourceID, obj any) bool {
+		tsp, ok := obj.(*compute.TargetSslProxy)
+		return ok && id.Key.Name == "tsp" && tsp.Name == "tsp"
+	}
+	notOwned := func(*cloud.ResourceID, any) bool { return false }
+
+	m := newMock()
+	// Resource does not exist.
+	if _, done, err := createAction(t, nil).Resume(ctx, m, adopt); err != nil || done {
+		t.Fatalf("Resume() = _, %t, %v; want false, nil", done, err)
+	}
+	if err := m.TargetSslProxies().Insert(ctx, key, &compute.TargetSslProxy{Name: "tsp", Service: bsURL, SslCertificates: []string{certURL}, ProxyHeader: "NONE"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	// Resource exists but is not owned.
+	if _, done, err := createAction(t, func(x *compute.TargetSslProxy) { x.ProxyHeader = "PROXY_V1" }).Resume(ctx, m, notOwned); err == nil || done {
+		t.Errorf("Resume() = _, %t, %v; want false, error", done, err)
+	}
+	if tsp, err := m.TargetSslProxies().Get(ctx, key); err != nil || tsp.ProxyHeader != "NONE" {
+		t.Errorf("Get() after Resume() = %+v, %v; want ProxyHeader=NONE", tsp, err)
+	}
+
+	// Resource exists and matches.
+	if _, done, err := createAction(t, nil).Resume(ctx, m, adopt); err != nil || !done {
+		t.Errorf("Resume() = _, %t, %v; want true, nil", done, err)
+	}
+
+	// Resource exists and can be updated.
+	a := createAction(t, func(x *compute.TargetSslProxy) { x.ProxyHeader = "PROXY_V1" })
+	if _, done, err := a.Resume(ctx, m, adopt); err != nil || !done {
+		t.Errorf("Resume() = _, %t, %v; want true, nil", done, err)
+	}
+	tsp, err := m.TargetSslProxies().Get(ctx, key)
+	if err != nil || tsp.ProxyHeader != "PROXY_V1" {
+		t.Errorf("Get() after Resume() = %+v, %v; want ProxyHeader=PROXY_V1", tsp, err)
+	}
+
+	// Resource exists but would have to be recreated.
+	a = createAction(t, func(x *compute.TargetSslProxy) { x.Description = "abc" })
+	if _, _, err := a.Resume(ctx, m, adopt); err == nil {
+		t.Error("Resume() = _, _, nil; want error")
+	}
+}
+
+func TestCalls(t *testing.T) {
+	got := newNode(t, nil)
+	gotB := NewBuilder(ID(proj, meta.GlobalKey("tsp")))
+	gotB.SetState(rnode.NodeDoesNotExist)
+	missing, err := gotB.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	update := func(x *compute.TargetSslProxy) {
+		x.ProxyHeader = "PROXY_V1"
+		x.Service = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs2"
+	}
+
+	for _, tc := range []struct {
+		name string
+		ver  meta.Version
+		f    func(*compute.TargetSslProxy)
+		got  rnode.Node
+		op   rnode.Operation
+		want []string
+	}{
+		{
+			name: "create",
+			got:  missing,
+			op:   rnode.OpCreate,
+			want: []string{
+				`Insert ga compute/targetSslProxies:proj-1/tsp {"certificateMap":"","description":"","name":"tsp","proxyHeader":"NONE","service":"` + bsURL + `","sslCertificates":["` + certURL + `"],"sslPolicy":""}`,
+			},
+		},
+		{
+			name: "update",
+			f:    update,
+			got:  got,
+			op:   rnode.OpUpdate,
+			want: []string{
+				`SetProxyHeader ga compute/targetSslProxies:proj-1/tsp {"proxyHeader":"PROXY_V1"}`,
+				`SetBackendService ga compute/targetSslProxies:proj-1/tsp {"service":"https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs2"}`,
+			},
+		},
+		{
+			name: "update alpha",
+			ver:  meta.VersionAlpha,
+			f:    update,
+			got:  got,
+			op:   rnode.OpUpdate,
+			want: []string{
+				`SetProxyHeader alpha compute/targetSslProxies:proj-1/tsp {"proxyHeader":"PROXY_V1"}`,
+				`SetBackendService alpha compute/targetSslProxies:proj-1/tsp {"service":"https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs2"}`,
+			},
+		},
+		{
+			name: "delete",
+			got:  got,
+			op:   rnode.OpDelete,
+			want: []string{"Delete ga compute/targetSslProxies:proj-1/tsp"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ver := tc.ver
+			if ver == "" {
+				ver = meta.VersionGA
+			}
+			want := newNodeAtVersion(t, ver, tc.f)
+			if tc.op == rnode.OpUpdate {
+				pd, err := want.Diff(tc.got)
+				if err != nil {
+					t.Fatalf("Diff() = %v", err)
+				}
+				want.Plan().Set(*pd)
+			} else {
+				want.Plan().Set(rnode.PlanDetails{Operation: tc.op})
+			}
+			actions, err := want.Actions(tc.got)
+			if err != nil {
+				t.Fatalf("Actions() = %v, want nil", err)
+			}
+			// The Actions wait on Events from other Nodes, so the Calls
+			// are taken directly from the Actions.
+			var gotCalls []string
+			for _, a := range actions {
+				if cd, ok := a.(exec.CallDescriber); ok {
+					for _, c := range cd.Calls() {
+						gotCalls = append(gotCalls, c.String())
+					}
+				}
+			}
+			if diff := cmp.Diff(gotCalls, tc.want); diff != "" {
+				t.Errorf("Calls(); -got,+want: %s", diff)
+			}
+		})
+	}
+}
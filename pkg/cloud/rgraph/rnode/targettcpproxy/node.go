@@ -0,0 +1,170 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targettcpproxy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type targetTcpProxyNode struct {
+	rnode.NodeBase
+	resource TargetTcpProxy
+}
+
+var _ rnode.Node = (*targetTcpProxyNode)(nil)
+
+func (n *targetTcpProxyNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *targetTcpProxyNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*targetTcpProxyNode)
+	if !ok {
+		return nil, fmt.Errorf("TargetTcpProxyNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("TargetTcpProxyNode: Diff %w", err)
+	}
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+var servicePath = api.Path{}.Pointer().Field("Service")
+
+func (n *targetTcpProxyNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("TargetTcpProxyNode: invalid plan op %s", op)
+}
+
+func (n *targetTcpProxyNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	details := n.Plan().Details()
+	if details == nil || details.Diff == nil {
+		return nil, fmt.Errorf("TargetTcpProxyNode: update %s: plan has no diff", n.ID())
+	}
+	for _, item := range details.Diff.Items {
+		if !item.Path.HasPrefix(servicePath) {
+			return nil, fmt.Errorf("TargetTcpProxyNode: update %s: field %s cannot be updated", n.ID(), item.Path)
+		}
+	}
+	r := n.resource
+	req, err := setBackendServiceRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("TargetTcpProxyNode: update %s: %w", n.ID(), err)
+	}
+	key := n.ID().Key
+	update := func(ctx context.Context, gcp cloud.Cloud) error {
+		if err := callSetBackendService(ctx, gcp, key, req); err != nil {
+			return fmt.Errorf("TargetTcpProxy %s: SetBackendService: %w", n.ID(), err)
+		}
+		return nil
+	}
+	call := exec.Call{
+		Method:  "SetBackendService",
+		Version: r.Version(),
+		ID:      n.ID(),
+		Body:    exec.BodySummary(req),
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v (Service)", n.ID()),
+			rnode.UpdateEvents(got, n),
+			update,
+			call,
+		),
+	}, nil
+}
+
+// setBackendServiceRequest returns the request for SetBackendService() at
+// the version of r.
+func setBackendServiceRequest(r TargetTcpProxy) (any, error) {
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, err
+		}
+		return &compute.TargetTcpProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, err
+		}
+		return &alpha.TargetTcpProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, err
+		}
+		return &beta.TargetTcpProxiesSetBackendServiceRequest{Service: obj.Service}, nil
+	}
+	return nil, fmt.Errorf("invalid version %q", r.Version())
+}
+
+// callSetBackendService calls SetBackendService(). The API version is given
+// by the type of req (see setBackendServiceRequest()).
+func callSetBackendService(ctx context.Context, gcp cloud.Cloud, key *meta.Key, req any) error {
+	switch req := req.(type) {
+	case *compute.TargetTcpProxiesSetBackendServiceRequest:
+		return gcp.TargetTcpProxies().SetBackendService(ctx, key, req)
+	case *alpha.TargetTcpProxiesSetBackendServiceRequest:
+		return gcp.AlphaTargetTcpProxies().SetBackendService(ctx, key, req)
+	case *beta.TargetTcpProxiesSetBackendServiceRequest:
+		return gcp.BetaTargetTcpProxies().SetBackendService(ctx, key, req)
+	}
+	return fmt.Errorf("invalid request type %T", req)
+}
+
+func (n *targetTcpProxyNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
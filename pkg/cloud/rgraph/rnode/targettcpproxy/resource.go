@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package targettcpproxy implements the rnode for TargetTcpProxy resources.
+package targettcpproxy
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "targetTcpProxies"
+
+// TargetTcpProxy is the frozen resource type.
+type TargetTcpProxy = api.Resource[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy]
+
+// MutableTargetTcpProxy is the mutable resource type.
+type MutableTargetTcpProxy = api.MutableResource[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy]
+
+// ID of the TargetTcpProxy resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableTargetTcpProxy returns a new mutable TargetTcpProxy.
+func NewMutableTargetTcpProxy(project string, key *meta.Key) MutableTargetTcpProxy {
+	return api.NewResource[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.TargetTcpProxy, alpha.TargetTcpProxy, beta.TargetTcpProxy]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.Enum(api.Path{}.Pointer().Field("ProxyHeader"), "NONE", "PROXY_V1")
+	// Only the Service can be changed (with SetBackendService()).
+	dt.Immutable(api.Path{}.Pointer().Field("Description"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+	dt.Immutable(api.Path{}.Pointer().Field("ProxyBind"))
+	dt.Immutable(api.Path{}.Pointer().Field("ProxyHeader"))
+
+	// References
+	dt.Reference(api.Path{}.Pointer().Field("Service"), "backendServices")
+
+	return dt
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targettcpproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	bsURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs"
+	bs2URL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs2"
+)
+
+func newNode(t *testing.T, f func(*compute.TargetTcpProxy)) rnode.Node {
+	t.Helper()
+	return newNodeAtVersion(t, meta.VersionGA, f)
+}
+
+// newNodeAtVersion returns a Node with a resource frozen at version ver.
+func newNodeAtVersion(t *testing.T, ver meta.Version, f func(*compute.TargetTcpProxy)) rnode.Node {
+	t.Helper()
+	r := NewMutableTargetTcpProxy(proj, meta.GlobalKey("ttp"))
+	r.VersionPreference(ver)
+	if err := r.Access(func(x *compute.TargetTcpProxy) {
+		x.Name = "ttp"
+		x.Service = bsURL
+		x.ProxyHeader = "NONE"
+		x.ForceSendFields = []string{"Description", "ProxyBind"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	refs := newNode(t, nil).OutRefs()
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.String())
+	}
+	want := []string{"*.Service => compute/backendServices:proj-1/bs"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var reqs []*compute.TargetTcpProxiesSetBackendServiceRequest
+	m.MockTargetTcpProxies.SetBackendServiceHook = func(ctx context.Context, key *meta.Key, req *compute.TargetTcpProxiesSetBackendServiceRequest, m *cloud.MockTargetTcpProxies) error {
+		reqs = append(reqs, req)
+		return nil
+	}
+	got := newNode(t, nil)
+
+	for _, tc := range []struct {
+		name   string
+		f      func(*compute.TargetTcpProxy)
+		wantOp rnode.Operation
+	}{
+		{name: "no diff", wantOp: rnode.OpNothing},
+		{
+			name:   "service",
+			f:      func(x *compute.TargetTcpProxy) { x.Service = bs2URL },
+			wantOp: rnode.OpUpdate,
+		},
+		{
+			name:   "proxy header",
+			f:      func(x *compute.TargetTcpProxy) { x.ProxyHeader = "PROXY_V1" },
+			wantOp: rnode.OpRecreate,
+		},
+	} {
+		want := newNode(t, tc.f)
+		pd, err := want.Diff(got)
+		if err != nil {
+			t.Fatalf("%s: Diff() = %v", tc.name, err)
+		}
+		if pd.Operation != tc.wantOp {
+			t.Errorf("%s: Diff() = %v, want op %v", tc.name, pd, tc.wantOp)
+		}
+		if pd.Operation != rnode.OpUpdate {
+			continue
+		}
+		want.Plan().Set(*pd)
+		actions, err := want.Actions(got)
+		if err != nil {
+			t.Fatalf("%s: Actions() = %v, want nil", tc.name, err)
+		}
+		for _, a := range actions {
+			if _, err := a.Run(ctx, m); err != nil {
+				t.Fatalf("%s: %v.Run() = %v, want nil", tc.name, a, err)
+			}
+		}
+	}
+	wantReqs := []*compute.TargetTcpProxiesSetBackendServiceRequest{{Service: bs2URL}}
+	if diff := cmp.Diff(reqs, wantReqs); diff != "" {
+		t.Errorf("SetBackendService(); -got,+want: %s", diff)
+	}
+}
+
+func TestUpdateBeta(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var reqs []*beta.TargetTcpProxiesSetBackendServiceRequest
+	m.MockBetaTargetTcpProxies.SetBackendServiceHook = func(ctx context.Context, key *meta.Key, req *beta.TargetTcpProxiesSetBackendServiceRequest, m *cloud.MockBetaTargetTcpProxies) error {
+		reqs = append(reqs, req)
+		return nil
+	}
+	got := newNodeAtVersion(t, meta.VersionBeta, nil)
+	want := newNodeAtVersion(t, meta.VersionBeta, func(x *compute.TargetTcpProxy) { x.Service = bs2URL })
+	pd, err := want.Diff(got)
+	if err != nil {
+		t.Fatalf("Diff() = %v", err)
+	}
+	want.Plan().Set(*pd)
+	actions, err := want.Actions(got)
+	if err != nil {
+		t.Fatalf("Actions() = %v, want nil", err)
+	}
+	for _, a := range actions {
+		if _, err := a.Run(ctx, m); err != nil {
+			t.Fatalf("%v.Run() = %v, want nil", a, err)
+		}
+	}
+	wantReqs := []*beta.TargetTcpProxiesSetBackendServiceRequest{{Service: bs2URL}}
+	if diff := cmp.Diff(reqs, wantReqs); diff != "" {
+		t.Errorf("beta SetBackendService(); -got,+want: %s", diff)
+	}
+}
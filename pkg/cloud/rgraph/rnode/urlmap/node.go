@@ -0,0 +1,186 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package urlmap
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type urlMapNode struct {
+	rnode.NodeBase
+	resource UrlMap
+}
+
+var _ rnode.Node = (*urlMapNode)(nil)
+
+func (n *urlMapNode) Resource() rnode.UntypedResource {
+	if n.resource == nil {
+		return nil
+	}
+	return n.resource
+}
+
+func (n *urlMapNode) Diff(gotNode rnode.Node) (*rnode.PlanDetails, error) {
+	got, ok := gotNode.(*urlMapNode)
+	if !ok {
+		return nil, fmt.Errorf("UrlMapNode: invalid type to Diff: %T", gotNode)
+	}
+	diff, err := got.resource.Diff(n.resource)
+	if err != nil {
+		return nil, fmt.Errorf("UrlMapNode: Diff %w", err)
+	}
+	// All of the user settable fields other than the Name can be changed
+	// with Update().
+	return rnode.PlanForDiff(n.IgnoreDiff(diff)), nil
+}
+
+func (n *urlMapNode) Actions(got rnode.Node) ([]exec.Action, error) {
+	op := n.Plan().Op()
+
+	switch op {
+	case rnode.OpCreate:
+		return rnode.CreateActions[compute.UrlMap, alpha.UrlMap, beta.UrlMap](&ops{}, got, n, n.resource)
+
+	case rnode.OpDelete:
+		return rnode.DeleteActions[compute.UrlMap, alpha.UrlMap, beta.UrlMap](&ops{}, got, n)
+
+	case rnode.OpNothing:
+		return rnode.ExistsActions(n), nil
+
+	case rnode.OpRecreate:
+		return rnode.RecreateActions[compute.UrlMap, alpha.UrlMap, beta.UrlMap](&ops{}, got, n, n.resource)
+
+	case rnode.OpUpdate:
+		return n.updateActions(got)
+	}
+
+	return nil, fmt.Errorf("UrlMapNode: invalid plan op %s", op)
+}
+
+func (n *urlMapNode) updateActions(got rnode.Node) ([]exec.Action, error) {
+	// Update() requires the fingerprint of the current resource.
+	gotRes, ok := got.Resource().(UrlMap)
+	if !ok || gotRes == nil {
+		return nil, fmt.Errorf("UrlMapNode: update %s: got has no resource", n.ID())
+	}
+	gotGA, err := gotRes.ToGA()
+	if gotGA == nil {
+		return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+	}
+	fingerprint := gotGA.Fingerprint
+
+	// Update() replaces the whole resource. The objects are copied so that
+	// setting the Fingerprint and clearing the OutputOnly fields do not
+	// modify the frozen resource.
+	r := n.resource
+	traits := (&typeTrait{}).FieldTraits(r.Version())
+	key := n.ID().Key
+	regional := key.Type() == meta.Regional
+	var (
+		body   any
+		update func(context.Context, cloud.Cloud) error
+	)
+	switch r.Version() {
+	case meta.VersionGA:
+		obj, err := r.ToGA()
+		if err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x := *obj
+		if err := api.StripOutputOnly(traits, &x); err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x.Fingerprint = fingerprint
+		body = &x
+		update = func(ctx context.Context, gcp cloud.Cloud) error {
+			if regional {
+				return gcp.RegionUrlMaps().Update(ctx, key, &x)
+			}
+			return gcp.UrlMaps().Update(ctx, key, &x)
+		}
+	case meta.VersionAlpha:
+		obj, err := r.ToAlpha()
+		if err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x := *obj
+		if err := api.StripOutputOnly(traits, &x); err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x.Fingerprint = fingerprint
+		body = &x
+		update = func(ctx context.Context, gcp cloud.Cloud) error {
+			if regional {
+				return gcp.AlphaRegionUrlMaps().Update(ctx, key, &x)
+			}
+			return gcp.AlphaUrlMaps().Update(ctx, key, &x)
+		}
+	case meta.VersionBeta:
+		obj, err := r.ToBeta()
+		if err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x := *obj
+		if err := api.StripOutputOnly(traits, &x); err != nil {
+			return nil, fmt.Errorf("UrlMapNode: update %s: %w", n.ID(), err)
+		}
+		x.Fingerprint = fingerprint
+		body = &x
+		update = func(ctx context.Context, gcp cloud.Cloud) error {
+			if regional {
+				return gcp.BetaRegionUrlMaps().Update(ctx, key, &x)
+			}
+			return gcp.BetaUrlMaps().Update(ctx, key, &x)
+		}
+	default:
+		return nil, fmt.Errorf("UrlMapNode: update %s: invalid version %q", n.ID(), r.Version())
+	}
+	call := exec.Call{
+		Method:  "Update",
+		Version: r.Version(),
+		ID:      n.ID(),
+		Body:    exec.BodySummary(body),
+	}
+
+	return []exec.Action{
+		rnode.NewUpdateAction(
+			rnode.UpdatePreconditions(got, n),
+			n.ID(),
+			fmt.Sprintf("Update %v", n.ID()),
+			rnode.UpdateEvents(got, n),
+			update,
+			call,
+		),
+	}, nil
+}
+
+func (n *urlMapNode) Builder() rnode.Builder {
+	b := &builder{}
+	b.Init(n.ID(), n.State(), n.Ownership(), n.Resource())
+	b.SetIgnoreDiffPaths(n.IgnoreDiffPaths())
+	return b
+}
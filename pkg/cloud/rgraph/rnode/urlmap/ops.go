@@ -0,0 +1,53 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package urlmap
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+type ops struct{}
+
+var _ rnode.GenericOps[compute.UrlMap, alpha.UrlMap, beta.UrlMap] = (*ops)(nil)
+
+func (*ops) GetFuncs(gcp cloud.Cloud) *rnode.GetFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap] {
+	return &rnode.GetFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap]{
+		GA:    rnode.GetByScope(gcp.UrlMaps().Get, gcp.RegionUrlMaps().Get),
+		Alpha: rnode.GetByScope(gcp.AlphaUrlMaps().Get, gcp.AlphaRegionUrlMaps().Get),
+		Beta:  rnode.GetByScope(gcp.BetaUrlMaps().Get, gcp.BetaRegionUrlMaps().Get),
+	}
+}
+
+func (*ops) CreateFuncs(gcp cloud.Cloud) *rnode.CreateFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap] {
+	return &rnode.CreateFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap]{
+		GA:    rnode.CreateByScope(gcp.UrlMaps().Insert, gcp.RegionUrlMaps().Insert),
+		Alpha: rnode.CreateByScope(gcp.AlphaUrlMaps().Insert, gcp.AlphaRegionUrlMaps().Insert),
+		Beta:  rnode.CreateByScope(gcp.BetaUrlMaps().Insert, gcp.BetaRegionUrlMaps().Insert),
+	}
+}
+
+func (*ops) DeleteFuncs(gcp cloud.Cloud) *rnode.DeleteFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap] {
+	return &rnode.DeleteFuncs[compute.UrlMap, alpha.UrlMap, beta.UrlMap]{
+		GA:    rnode.DeleteByScope(gcp.UrlMaps().Delete, gcp.RegionUrlMaps().Delete),
+		Alpha: rnode.DeleteByScope(gcp.AlphaUrlMaps().Delete, gcp.AlphaRegionUrlMaps().Delete),
+		Beta:  rnode.DeleteByScope(gcp.BetaUrlMaps().Delete, gcp.BetaRegionUrlMaps().Delete),
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package urlmap implements the rnode for global and regional UrlMap
+// resources.
+package urlmap
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	alpha "google.golang.org/api/compute/v0.alpha"
+	beta "google.golang.org/api/compute/v0.beta"
+	"google.golang.org/api/compute/v1"
+)
+
+const resourcePlural = "urlMaps"
+
+// UrlMap is the frozen resource type.
+type UrlMap = api.Resource[compute.UrlMap, alpha.UrlMap, beta.UrlMap]
+
+// MutableUrlMap is the mutable resource type.
+type MutableUrlMap = api.MutableResource[compute.UrlMap, alpha.UrlMap, beta.UrlMap]
+
+// ID of the UrlMap resource.
+func ID(project string, key *meta.Key) *cloud.ResourceID {
+	return &cloud.ResourceID{
+		Resource:  resourcePlural,
+		APIGroup:  meta.APIGroupCompute,
+		ProjectID: project,
+		Key:       key,
+	}
+}
+
+// NewMutableUrlMap returns a new mutable UrlMap.
+func NewMutableUrlMap(project string, key *meta.Key) MutableUrlMap {
+	return api.NewResource[compute.UrlMap, alpha.UrlMap, beta.UrlMap](ID(project, key), &typeTrait{})
+}
+
+type typeTrait struct {
+	api.BaseTypeTrait[compute.UrlMap, alpha.UrlMap, beta.UrlMap]
+}
+
+func (*typeTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	// Built-ins
+	dt.OutputOnlyBuiltins()
+	dt.System(api.Path{}.Pointer().Field("Fingerprint"))
+	// Resource-specific
+	dt.OutputOnly(api.Path{}.Pointer().Field("Region"))
+	dt.Immutable(api.Path{}.Pointer().Field("Name"))
+
+	// References
+	for _, p := range serviceRefPaths {
+		dt.Reference(p, "backendServices")
+	}
+
+	return dt
+}
+
+// serviceRefPaths are the fields that reference a backend service. The
+// references can also be to backend buckets (see builder.OutRefs()).
+var serviceRefPaths = []api.Path{
+	api.Path{}.Pointer().Field("DefaultService"),
+	api.Path{}.Pointer().Field("DefaultRouteAction").Pointer().Field("WeightedBackendServices").AnySliceIndex().Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("DefaultRouteAction").Pointer().Field("RequestMirrorPolicy").Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("DefaultService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("DefaultRouteAction").Pointer().Field("WeightedBackendServices").AnySliceIndex().Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("DefaultRouteAction").Pointer().Field("RequestMirrorPolicy").Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("PathRules").AnySliceIndex().Pointer().Field("Service"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("PathRules").AnySliceIndex().Pointer().Field("RouteAction").Pointer().Field("WeightedBackendServices").AnySliceIndex().Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("PathRules").AnySliceIndex().Pointer().Field("RouteAction").Pointer().Field("RequestMirrorPolicy").Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("RouteRules").AnySliceIndex().Pointer().Field("Service"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("RouteRules").AnySliceIndex().Pointer().Field("RouteAction").Pointer().Field("WeightedBackendServices").AnySliceIndex().Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("PathMatchers").AnySliceIndex().Pointer().Field("RouteRules").AnySliceIndex().Pointer().Field("RouteAction").Pointer().Field("RequestMirrorPolicy").Pointer().Field("BackendService"),
+	api.Path{}.Pointer().Field("Tests").AnySliceIndex().Pointer().Field("Service"),
+}
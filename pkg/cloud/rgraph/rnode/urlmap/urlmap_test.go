@@ -0,0 +1,206 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package urlmap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/testing/ez"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	region = "us-central1"
+	bsURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs"
+	bs2URL = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendServices/bs2"
+	bbURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/global/backendBuckets/bb"
+)
+
+func newNode(t *testing.T, key *meta.Key, f func(*compute.UrlMap)) rnode.Node {
+	t.Helper()
+	r := NewMutableUrlMap(proj, key)
+	if err := r.Access(func(x *compute.UrlMap) {
+		x.Name = key.Name
+		x.DefaultService = bsURL
+		x.ForceSendFields = []string{"Description"}
+		x.NullFields = []string{"DefaultRouteAction", "DefaultUrlRedirect", "HeaderAction", "HostRules", "PathMatchers", "Tests"}
+		if f != nil {
+			f(x)
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	return ez.ManagedNode(fr, NewBuilderWithResource)
+}
+
+func TestOutRefs(t *testing.T) {
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	// outRefs of the UrlMap obj fetched from the Cloud.
+	outRefs := func(obj *compute.UrlMap) ([]rnode.ResourceRef, error) {
+		t.Helper()
+		key := meta.GlobalKey(obj.Name)
+		if err := m.UrlMaps().Insert(ctx, key, obj); err != nil {
+			t.Fatalf("Insert() = %v", err)
+		}
+		b := NewBuilder(ID(proj, key))
+		if err := b.SyncFromCloud(ctx, m); err != nil {
+			t.Fatalf("SyncFromCloud() = %v", err)
+		}
+		return b.OutRefs()
+	}
+
+	refs, err := outRefs(&compute.UrlMap{
+		Name:           "um",
+		DefaultService: bsURL,
+		HostRules:      []*compute.HostRule{{Hosts: []string{"example.com"}, PathMatcher: "pm"}},
+		PathMatchers: []*compute.PathMatcher{{
+			Name:           "pm",
+			DefaultService: bsURL,
+			PathRules: []*compute.PathRule{
+				{Paths: []string{"/static/*"}, Service: bbURL},
+				{
+					Paths: []string{"/api/*"},
+					RouteAction: &compute.HttpRouteAction{
+						WeightedBackendServices: []*compute.WeightedBackendService{{BackendService: bs2URL, Weight: 100}},
+					},
+				},
+			},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("OutRefs() = %v, want nil", err)
+	}
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Path.String()+" => "+ref.To.String())
+	}
+	want := []string{
+		"*.DefaultService => compute/backendServices:proj-1/bs",
+		"*.PathMatchers!0*.DefaultService => compute/backendServices:proj-1/bs",
+		"*.PathMatchers!0*.PathRules!0*.Service => compute/backendBuckets:proj-1/bb",
+		"*.PathMatchers!0*.PathRules!1*.RouteAction*.WeightedBackendServices!0*.BackendService => compute/backendServices:proj-1/bs2",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("OutRefs(); -got,+want: %s", diff)
+	}
+
+	// Reference to the wrong type of resource.
+	if _, err := outRefs(&compute.UrlMap{
+		Name:           "um2",
+		DefaultService: "https://www.googleapis.com/compute/v1/projects/proj-1/global/healthChecks/hc",
+	}); err == nil {
+		t.Error("OutRefs() = _, nil; want error")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	for _, key := range []*meta.Key{meta.GlobalKey("um"), meta.RegionalKey("um", region)} {
+		m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+		var updates []*compute.UrlMap
+		m.MockUrlMaps.UpdateHook = func(ctx context.Context, key *meta.Key, obj *compute.UrlMap, m *cloud.MockUrlMaps) error {
+			sent := *obj
+			updates = append(updates, &sent)
+			return mock.UpdateURLMapHook(ctx, key, obj, m)
+		}
+		m.MockRegionUrlMaps.UpdateHook = func(ctx context.Context, key *meta.Key, obj *compute.UrlMap, m *cloud.MockRegionUrlMaps) error {
+			sent := *obj
+			updates = append(updates, &sent)
+			return mock.UpdateRegionURLMapHook(ctx, key, obj, m)
+		}
+
+		got := newNode(t, key, nil)
+		gotObj, _ := got.Resource().(UrlMap).ToGA()
+		ins := *gotObj
+		ins.Fingerprint = "abc"
+		if key.Type() == meta.Regional {
+			m.RegionUrlMaps().Insert(ctx, key, &ins)
+		} else {
+			m.UrlMaps().Insert(ctx, key, &ins)
+		}
+		b := NewBuilder(ID(proj, key))
+		if err := b.SyncFromCloud(ctx, m); err != nil {
+			t.Fatalf("SyncFromCloud(%v) = %v", key, err)
+		}
+		got, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+
+		// want is derived from the resource in the Cloud, so it has the
+		// OutputOnly fields (e.g. SelfLink). These are not sent.
+		syncedObj, _ := got.Resource().(UrlMap).ToGA()
+		if syncedObj.SelfLink == "" {
+			t.Fatalf("SelfLink is not set in got")
+		}
+		wantObj := *syncedObj
+		wantObj.DefaultService = bs2URL
+		wantObj.Fingerprint = ""
+		r := NewMutableUrlMap(proj, key)
+		if err := r.Set(&wantObj); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		fr, err := r.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		wb := NewBuilderWithResource(fr)
+		wb.SetState(rnode.NodeExists)
+		wb.SetOwnership(rnode.OwnershipManaged)
+		want, err := wb.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		pd, err := want.Diff(got)
+		if err != nil {
+			t.Fatalf("Diff() = %v", err)
+		}
+		if pd.Operation != rnode.OpUpdate {
+			t.Fatalf("Diff() = %v, want op %v", pd, rnode.OpUpdate)
+		}
+		want.Plan().Set(*pd)
+		actions, err := want.Actions(got)
+		if err != nil {
+			t.Fatalf("Actions() = %v, want nil", err)
+		}
+		for _, a := range actions {
+			if _, err := a.Run(ctx, m); err != nil {
+				t.Fatalf("%v.Run() = %v, want nil", a, err)
+			}
+		}
+		if len(updates) != 1 || updates[0].DefaultService != bs2URL || updates[0].Fingerprint != "abc" || updates[0].SelfLink != "" {
+			t.Fatalf("Update(%v) = %+v; want 1 update with DefaultService=%s, Fingerprint=abc and no SelfLink", key, updates, bs2URL)
+		}
+		// The fingerprint is not set and the OutputOnly fields are not
+		// cleared in the wanted resource.
+		if wantObj, _ := want.Resource().(UrlMap).ToGA(); wantObj.Fingerprint != "" || wantObj.SelfLink == "" {
+			t.Errorf("want = %+v, want no Fingerprint and SelfLink set", wantObj)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ez
+
+import (
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// ManagedNode returns a Node for r that exists and is managed by the Graph.
+// newBuilder is the NewBuilderWithResource() of the node type, e.g.
+//
+//	n := ez.ManagedNode(r, urlmap.NewBuilderWithResource)
+//
+// Errors are handled by panic().
+func ManagedNode[GA any, Alpha any, Beta any](
+	r api.Resource[GA, Alpha, Beta],
+	newBuilder func(api.Resource[GA, Alpha, Beta]) rnode.Builder,
+) rnode.Node {
+	b := newBuilder(r)
+	b.SetState(rnode.NodeExists)
+	b.SetOwnership(rnode.OwnershipManaged)
+	n, err := b.Build()
+	if err != nil {
+		panicf("ManagedNode: Build() = %v", err)
+	}
+	return n
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Checks done by Validate().
+const (
+	// CheckDanglingRef is a reference from a resource that exists to one
+	// that does not.
+	CheckDanglingRef = "DanglingRef"
+	// CheckNameCollision is two different nodes added to the Builder with
+	// the same ID; only the last one is in the Graph.
+	CheckNameCollision = "NameCollision"
+	// CheckScopeMismatch is a reference from a zonal or regional
+	// resource to a resource in a different region.
+	CheckScopeMismatch = "ScopeMismatch"
+	// CheckName is a resource name that is invalid or does not match the
+	// ID.
+	CheckName = "Name"
+	// CheckForeignProject is a reference from a managed resource to a
+	// resource in a different project that is not OwnershipExternal.
+	CheckForeignProject = "ForeignProject"
+	// CheckRequired is a resource that is missing a field declared with
+	// FieldTraits.Required().
+	CheckRequired = "Required"
+)
+
+// Problem found by Validate().
+type Problem struct {
+	// ID of the Node with the problem.
+	ID *cloud.ResourceID
+	// Check that failed, e.g. CheckDanglingRef.
+	Check string
+	// Message describing the problem.
+	Message string
+}
+
+// String implements Stringer.
+func (p Problem) String() string { return fmt.Sprintf("%v: %s: %s", p.ID, p.Check, p.Message) }
+
+// ValidationError is returned by Validate().
+type ValidationError struct {
+	Problems []Problem
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	var s []string
+	for _, p := range e.Problems {
+		s = append(s, p.String())
+	}
+	return fmt.Sprintf("invalid graph: %s", strings.Join(s, "; "))
+}
+
+// Validate checks the wanted Graph g for problems that would otherwise only
+// be found when the Cloud rejects a call during execution:
+//
+//   - A managed resource that exists references a resource that does not
+//     (CheckDanglingRef).
+//   - Different Nodes with the same ID were added to the Builder
+//     (CheckNameCollision).
+//   - A zonal or regional resource references a zonal or regional resource
+//     in a different region (CheckScopeMismatch).
+//   - The name of a resource is not a valid resource name or the Name field
+//     of the resource does not match the ID (CheckName).
+//   - A managed resource references a resource in a different project that
+//     is not OwnershipExternal (CheckForeignProject). Resources in other
+//     projects (e.g. the host project of a Shared VPC) are only read; the
+//     Actions for managed resources are executed in their own project.
+//     References to resources in other projects that are not in the graph
+//     are also reported as their ownership is unknown.
+//   - A managed resource that exists does not set a field that is
+//     Required() by its FieldTraits (CheckRequired).
+//
+// Returns a *ValidationError listing all of the problems, sorted by ID.
+func Validate(g *Graph) error {
+	var problems []Problem
+	add := func(id *cloud.ResourceID, check, format string, args ...any) {
+		problems = append(problems, Problem{ID: id, Check: check, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, id := range g.collisions {
+		add(id, CheckNameCollision, "different nodes were added with the same ID")
+	}
+	for _, n := range g.All() {
+		if msg := checkName(n); msg != "" {
+			add(n.ID(), CheckName, "%s", msg)
+		}
+		if n.State() != rnode.NodeExists || n.Ownership() != rnode.OwnershipManaged {
+			continue
+		}
+		for _, msg := range checkRequired(n) {
+			add(n.ID(), CheckRequired, "%s", msg)
+		}
+		for _, ref := range n.OutRefs() {
+			if to := g.Get(ref.To); to != nil && to.State() == rnode.NodeDoesNotExist {
+				add(n.ID(), CheckDanglingRef, "%s references %v which does not exist", ref.Path, ref.To)
+			}
+			if from, to := region(n.ID().Key), region(ref.To.Key); from != "" && to != "" && from != to {
+				add(n.ID(), CheckScopeMismatch, "%s in region %s references %v in region %s", ref.Path, from, ref.To, to)
+			}
+			if ref.To.ProjectID != n.ID().ProjectID {
+				if to := g.Get(ref.To); to == nil {
+					add(n.ID(), CheckForeignProject, "%s references %v in project %s which is not in the graph, want %s", ref.Path, ref.To, ref.To.ProjectID, rnode.OwnershipExternal)
+				} else if to.Ownership() != rnode.OwnershipExternal {
+					add(n.ID(), CheckForeignProject, "%s references %v in project %s which is %s, want %s", ref.Path, ref.To, ref.To.ProjectID, to.Ownership(), rnode.OwnershipExternal)
+				}
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].ID.String() < problems[j].ID.String() })
+	return &ValidationError{Problems: problems}
+}
+
+// nameRE matches valid resource names (RFC1035).
+var nameRE = regexp.MustCompile(`^[a-z]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// checkName returns a description of the problem with the name of n.
+// Returns "" if the name is valid.
+func checkName(n rnode.Node) string {
+	name := n.ID().Key.Name
+	if !nameRE.MatchString(name) {
+		return fmt.Sprintf("invalid name %q", name)
+	}
+	if n.State() != rnode.NodeExists {
+		return ""
+	}
+	r, ok := n.Resource().(interface {
+		GetByPath(meta.Version, api.Path) (any, error)
+	})
+	if !ok {
+		return ""
+	}
+	v, err := r.GetByPath(n.Resource().Version(), api.Path{}.Pointer().Field("Name"))
+	if err != nil {
+		// The resource does not have a Name field.
+		return ""
+	}
+	if s, ok := v.(string); ok && s != name {
+		return fmt.Sprintf("resource has Name %q", s)
+	}
+	return ""
+}
+
+// checkRequired returns a description of each of the Required() fields that
+// are not set in the resource of n.
+func checkRequired(n rnode.Node) []string {
+	r, ok := n.Resource().(interface{ CheckRequired() error })
+	if !ok {
+		return nil
+	}
+	err := r.CheckRequired()
+	if err == nil {
+		return nil
+	}
+	var verr *api.ValidationError
+	if !errors.As(err, &verr) {
+		return []string{err.Error()}
+	}
+	var ret []string
+	for _, fe := range verr.Errors {
+		ret = append(ret, fe.Error())
+	}
+	return ret
+}
+
+// region of the resource with key. Returns "" for global resources.
+func region(key *meta.Key) string {
+	switch key.Type() {
+	case meta.Regional:
+		return key.Region
+	case meta.Zonal:
+		if i := strings.LastIndex(key.Zone, "-"); i > 0 {
+			return key.Zone[:i]
+		}
+		return key.Zone
+	}
+	return ""
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	// node returns a managed Fake that exists with a resource named
+	// resName, referencing the keys in refs.
+	node := func(key *meta.Key, resName string, refs ...*meta.Key) rnode.Builder {
+		id := fake.ID("proj", key)
+		nb := fake.NewBuilder(id)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		mr := fake.NewMutableFake("proj", key)
+		mr.Access(func(x *fake.FakeResource) { x.Name = resName })
+		r, _ := mr.Freeze()
+		nb.SetResource(r)
+		for _, to := range refs {
+			nb.FakeOutRefs = append(nb.FakeOutRefs, rnode.ResourceRef{
+				From: id,
+				Path: api.Path{}.Pointer().Field("Dependencies"),
+				To:   fake.ID("proj", to),
+			})
+		}
+		return nb
+	}
+	absent := func(key *meta.Key) rnode.Builder {
+		nb := fake.NewBuilder(fake.ID("proj", key))
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeDoesNotExist)
+		return nb
+	}
+	// hostNode returns a Fake in project "host" that exists with the given
+	// ownership.
+	hostNode := func(key *meta.Key, o rnode.OwnershipStatus) rnode.Builder {
+		nb := fake.NewBuilder(fake.ID("host", key))
+		nb.SetOwnership(o)
+		nb.SetState(rnode.NodeExists)
+		return nb
+	}
+	// withHostRef adds a reference from nb to key in project "host".
+	withHostRef := func(nb rnode.Builder, key *meta.Key) rnode.Builder {
+		fb := nb.(*fake.Builder)
+		fb.FakeOutRefs = append(fb.FakeOutRefs, rnode.ResourceRef{
+			From: nb.ID(),
+			Path: api.Path{}.Pointer().Field("Dependencies"),
+			To:   fake.ID("host", key),
+		})
+		return nb
+	}
+	// unsetRequired returns a managed Fake that exists without the Value
+	// field, which is Required() by its FieldTraits. The resource is
+	// restored with UnmarshalResource() which does not check the Required()
+	// fields.
+	unsetRequired := func(key *meta.Key) rnode.Builder {
+		nb := node(key, key.Name).(*fake.Builder)
+		r, _ := fake.NewMutableFake("proj", key).Freeze()
+		b, err := json.Marshal(r)
+		if err != nil {
+			t.Fatalf("json.Marshal() = %v", err)
+		}
+		ur, err := api.UnmarshalResource[fake.FakeResource, fake.FakeResource, fake.FakeResource](b, &requiredValueTrait{})
+		if err != nil {
+			t.Fatalf("UnmarshalResource() = %v", err)
+		}
+		nb.SetResource(ur)
+		return nb
+	}
+	var (
+		a      = meta.GlobalKey("a")
+		b      = meta.GlobalKey("b")
+		zonal  = meta.ZonalKey("z", "us-central1-a")
+		usReg  = meta.RegionalKey("r", "us-central1")
+		euReg  = meta.RegionalKey("r", "europe-west1")
+		badKey = meta.GlobalKey("Bad_Name")
+	)
+
+	for _, tc := range []struct {
+		name  string
+		nodes []rnode.Builder
+		want  []string
+	}{
+		{
+			name:  "valid",
+			nodes: []rnode.Builder{node(a, "a", b, usReg), node(b, "b"), node(zonal, "z", usReg), node(usReg, "r")},
+		},
+		{
+			name:  "dangling ref",
+			nodes: []rnode.Builder{node(a, "a", b), absent(b)},
+			want:  []string{"fakes:proj/a: DanglingRef"},
+		},
+		{
+			name:  "deleted nodes can reference deleted nodes",
+			nodes: []rnode.Builder{absent(a), absent(b)},
+		},
+		{
+			name:  "name collision",
+			nodes: []rnode.Builder{node(a, "a"), node(a, "a")},
+			want:  []string{"fakes:proj/a: NameCollision"},
+		},
+		{
+			name:  "zonal to regional scope mismatch",
+			nodes: []rnode.Builder{node(zonal, "z", euReg), node(euReg, "r")},
+			want:  []string{"fakes:proj/us-central1-a/z: ScopeMismatch"},
+		},
+		{
+			name:  "regional to regional scope mismatch",
+			nodes: []rnode.Builder{node(usReg, "r", euReg), node(euReg, "r")},
+			want:  []string{"fakes:proj/us-central1/r: ScopeMismatch"},
+		},
+		{
+			name:  "invalid name",
+			nodes: []rnode.Builder{node(badKey, "Bad_Name")},
+			want:  []string{"fakes:proj/Bad_Name: Name"},
+		},
+		{
+			name:  "reference to external node in another project",
+			nodes: []rnode.Builder{withHostRef(node(a, "a"), b), hostNode(b, rnode.OwnershipExternal)},
+		},
+		{
+			name:  "reference to managed node in another project",
+			nodes: []rnode.Builder{withHostRef(node(a, "a"), b), hostNode(b, rnode.OwnershipManaged)},
+			want:  []string{"fakes:proj/a: ForeignProject"},
+		},
+		{
+			name:  "required field not set",
+			nodes: []rnode.Builder{unsetRequired(a)},
+			want:  []string{"fakes:proj/a: Required"},
+		},
+		{
+			name:  "resource name mismatch",
+			nodes: []rnode.Builder{node(a, "x")},
+			want:  []string{"fakes:proj/a: Name"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gb := NewBuilder()
+			for _, nb := range tc.nodes {
+				gb.Add(nb)
+			}
+			err := Validate(gb.MustBuild())
+			var got []string
+			var ve *ValidationError
+			if errors.As(err, &ve) {
+				for _, p := range ve.Problems {
+					got = append(got, p.ID.String()+": "+p.Check)
+				}
+			} else if err != nil {
+				t.Fatalf("Validate() = %v, want nil or *ValidationError", err)
+			}
+			if diff := cmp.Diff(got, tc.want); diff != "" {
+				t.Errorf("Validate() = %v; -got,+want: %s", err, diff)
+			}
+		})
+	}
+}
+
+func TestValidateForeignProjectNotInGraph(t *testing.T) {
+	// The Builder rejects references to nodes that are not in the graph, so
+	// the Graph is assembled directly.
+	key := meta.GlobalKey("a")
+	id := fake.ID("proj", key)
+	nb := fake.NewBuilder(id)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	mr := fake.NewMutableFake("proj", key)
+	mr.Access(func(x *fake.FakeResource) { x.Name = "a" })
+	r, _ := mr.Freeze()
+	nb.SetResource(r)
+	nb.FakeOutRefs = []rnode.ResourceRef{{
+		From: id,
+		Path: api.Path{}.Pointer().Field("Dependencies"),
+		To:   fake.ID("host", meta.GlobalKey("b")),
+	}}
+	n, err := nb.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	g := newGraph()
+	g.add(n)
+
+	err = Validate(g)
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 1 || ve.Problems[0].Check != CheckForeignProject {
+		t.Errorf("Validate() = %v; want a single %s problem", err, CheckForeignProject)
+	}
+}
+
+// requiredValueTrait is the TypeTrait of a Fake with a Required() Value.
+type requiredValueTrait struct {
+	api.BaseTypeTrait[fake.FakeResource, fake.FakeResource, fake.FakeResource]
+}
+
+func (*requiredValueTrait) FieldTraits(meta.Version) *api.FieldTraits {
+	dt := api.NewFieldTraits()
+	dt.Required(api.Path{}.Pointer().Field("Value"))
+	return dt
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package gc finds and deletes orphaned resources.
+//
+// Resources created by a controller carry an ownership marker (e.g. a string
+// in the description). A resource that has the marker but is no longer in the
+// wanted Graph is an orphan, e.g. leaked by a failed or interrupted deletion.
+// The garbage collection lists the resources of the configured types in the
+// Cloud, finds the orphans and plans their deletion.
+package gc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+// Object is a resource returned by a ListFunc.
+type Object struct {
+	ID          *cloud.ResourceID
+	Description string
+	// Labels of the resource. This is nil for resources that do not
+	// support labels.
+	Labels map[string]string
+}
+
+// ListFunc lists the resources of a type (and location) in the Cloud.
+type ListFunc func(ctx context.Context, cl cloud.Cloud) ([]Object, error)
+
+// OwnedFunc returns true if the Object is owned by the caller.
+type OwnedFunc func(Object) bool
+
+// DescriptionMarker returns an OwnedFunc for resources whose description
+// contains marker.
+func DescriptionMarker(marker string) OwnedFunc {
+	return func(o Object) bool { return strings.Contains(o.Description, marker) }
+}
+
+// LabelMarker returns an OwnedFunc for resources with the label key=value.
+func LabelMarker(key, value string) OwnedFunc {
+	return func(o Object) bool {
+		v, ok := o.Labels[key]
+		return ok && v == value
+	}
+}
+
+// Config for the garbage collection.
+type Config struct {
+	// Owned identifies the resources owned by the caller. This must be set.
+	Owned OwnedFunc
+	// Listers for the types of resources to collect. The types must be
+	// registered node types (see rnode.RegisterNodeType()).
+	Listers []ListFunc
+}
+
+// Orphans returns the resources listed by c.Listers that are Owned but are
+// not in want, sorted.
+func Orphans(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph, c Config) ([]*cloud.ResourceID, error) {
+	if c.Owned == nil {
+		return nil, fmt.Errorf("gc: Config.Owned must be set")
+	}
+	var ret []*cloud.ResourceID
+	for _, list := range c.Listers {
+		objs, err := list(ctx, cl)
+		if err != nil {
+			return nil, fmt.Errorf("gc: list: %w", err)
+		}
+		for _, o := range objs {
+			if c.Owned(o) && want.Get(o.ID) == nil {
+				ret = append(ret, o.ID)
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].String() < ret[j].String() })
+	return ret, nil
+}
+
+// Plan finds the Orphans() and plans their deletion. The plan deletes the
+// orphans in dependency order; the resources they reference are included in
+// the plan but not changed. Returns a nil Result if there are no orphans.
+func Plan(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph, c Config, opts ...plan.Option) (*plan.Result, error) {
+	orphans, err := Orphans(ctx, cl, want, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(orphans) == 0 {
+		return nil, nil
+	}
+	rlog.FromContext(ctx).V(2).Info("Found orphans", "orphans", len(orphans), rlog.KeyNode, orphans[0].String())
+
+	g, err := orphanGraph(ctx, cl, orphans)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Do(ctx, cl, g, opts...)
+}
+
+// orphanGraph returns a Graph where the orphans do not exist. The resources
+// referenced by the orphans (transitively) are added as external Nodes so
+// that the Graph is complete.
+func orphanGraph(ctx context.Context, cl cloud.Cloud, orphans []*cloud.ResourceID) (*rgraph.Graph, error) {
+	b := rgraph.NewBuilder()
+	managed := map[cloud.ResourceMapKey]bool{}
+	for _, id := range orphans {
+		managed[id.MapKey()] = true
+	}
+	queue := append([]*cloud.ResourceID{}, orphans...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if b.Get(id) != nil {
+			continue
+		}
+		nb, err := rnode.NewBuilderForID(id)
+		if err != nil {
+			return nil, fmt.Errorf("gc: %w", err)
+		}
+		if managed[id.MapKey()] {
+			nb.SetOwnership(rnode.OwnershipManaged)
+		} else {
+			nb.SetOwnership(rnode.OwnershipExternal)
+		}
+		if err := nb.SyncFromCloud(ctx, cl); err != nil {
+			return nil, fmt.Errorf("gc: sync %v: %w", id, err)
+		}
+		b.Add(nb)
+		if nb.State() != rnode.NodeExists {
+			continue
+		}
+		refs, err := nb.OutRefs()
+		if err != nil {
+			return nil, fmt.Errorf("gc: %w", err)
+		}
+		for _, ref := range refs {
+			queue = append(queue, ref.To)
+		}
+	}
+	for _, id := range orphans {
+		if err := b.EnsureAbsent(id); err != nil {
+			return nil, fmt.Errorf("gc: %w", err)
+		}
+	}
+	g, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("gc: %w", err)
+	}
+	return g, nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	region = "us-central1"
+	marker = "owner=test"
+	neURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/notificationEndpoints/ne"
+)
+
+// newMock returns a mock with the following resources:
+//
+//	hcs   -> ne   (owned)
+//	keep          (owned, wanted)
+//	other         (not owned)
+func newMock(t *testing.T) *cloud.MockGCE {
+	t.Helper()
+	ctx := context.Background()
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	for _, ne := range []struct{ name, desc string }{
+		{"ne", marker},
+		{"keep", marker},
+		{"other", "someone else"},
+	} {
+		key := meta.RegionalKey(ne.name, region)
+		if err := m.RegionNotificationEndpoints().Insert(ctx, key, &compute.NotificationEndpoint{Name: ne.name, Description: ne.desc}); err != nil {
+			t.Fatalf("Insert(%v) = %v", key, err)
+		}
+	}
+	hcs := &compute.HealthCheckService{
+		Name:                  "hcs",
+		Description:           marker,
+		NotificationEndpoints: []string{neURL},
+	}
+	if err := m.RegionHealthCheckServices().Insert(ctx, meta.RegionalKey("hcs", region), hcs); err != nil {
+		t.Fatalf("Insert(hcs) = %v", err)
+	}
+	return m
+}
+
+// newWant returns a Graph with the "keep" NotificationEndpoint.
+func newWant(t *testing.T) *rgraph.Graph {
+	t.Helper()
+	key := meta.RegionalKey("keep", region)
+	r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+	if err := r.Access(func(x *compute.NotificationEndpoint) {
+		x.Name = key.Name
+		x.Description = marker
+		x.NullFields = []string{"GrpcSettings"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	b := rgraph.NewBuilder()
+	nb := notificationendpoint.NewBuilderWithResource(fr)
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	b.Add(nb)
+	return b.MustBuild()
+}
+
+func testConfig() Config {
+	return Config{
+		Owned: DescriptionMarker(marker),
+		Listers: []ListFunc{
+			NotificationEndpoints(proj, region),
+			HealthCheckServices(proj, region),
+		},
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	m := newMock(t)
+	got, err := Orphans(context.Background(), m, newWant(t), testConfig())
+	if err != nil {
+		t.Fatalf("Orphans() = %v, want nil", err)
+	}
+	var gotS []string
+	for _, id := range got {
+		gotS = append(gotS, id.String())
+	}
+	want := []string{
+		"compute/healthCheckServices:proj-1/us-central1/hcs",
+		"compute/notificationEndpoints:proj-1/us-central1/ne",
+	}
+	if len(gotS) != len(want) || gotS[0] != want[0] || gotS[1] != want[1] {
+		t.Errorf("Orphans() = %v, want %v", gotS, want)
+	}
+
+	if _, err := Orphans(context.Background(), m, newWant(t), Config{}); err == nil {
+		t.Errorf("Orphans(Config{}) = nil, want error")
+	}
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+	m := newMock(t)
+
+	pr, err := Plan(ctx, m, newWant(t), testConfig())
+	if err != nil {
+		t.Fatalf("Plan() = %v, want nil", err)
+	}
+	if got := pr.Summary().Total; got.Changed() != 2 || got.Delete != 2 {
+		t.Errorf("Plan() = %v; want 2 deletes", pr.Summary())
+	}
+	ex, err := exec.NewSerialExecutor(pr.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	if _, err := ex.Run(ctx, m); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	if _, err := m.RegionNotificationEndpoints().Get(ctx, meta.RegionalKey("ne", region)); err == nil {
+		t.Errorf("Get(ne) after gc = nil, want error")
+	}
+	if _, err := m.RegionHealthCheckServices().Get(ctx, meta.RegionalKey("hcs", region)); err == nil {
+		t.Errorf("Get(hcs) after gc = nil, want error")
+	}
+	for _, name := range []string{"keep", "other"} {
+		if _, err := m.RegionNotificationEndpoints().Get(ctx, meta.RegionalKey(name, region)); err != nil {
+			t.Errorf("Get(%s) after gc = %v, want nil", name, err)
+		}
+	}
+
+	// Nothing left to collect.
+	pr, err = Plan(ctx, m, newWant(t), testConfig())
+	if err != nil || pr != nil {
+		t.Errorf("Plan() = %v, %v; want nil, nil", pr, err)
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		owned OwnedFunc
+		obj   Object
+		want  bool
+	}{
+		{"description", DescriptionMarker("x=y"), Object{Description: "a x=y b"}, true},
+		{"description mismatch", DescriptionMarker("x=y"), Object{Description: "x=z"}, false},
+		{"label", LabelMarker("x", "y"), Object{Labels: map[string]string{"x": "y"}}, true},
+		{"label mismatch", LabelMarker("x", "y"), Object{Labels: map[string]string{"x": "z"}}, false},
+		{"label nil", LabelMarker("x", ""), Object{}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.owned(tc.obj); got != tc.want {
+				t.Errorf("owned(%+v) = %t, want %t", tc.obj, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gc
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/filter"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheckservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/instancegroupmanager"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/targetsslproxy"
+)
+
+// HealthCheckServices lists the HealthCheckServices in region.
+func HealthCheckServices(project, region string) ListFunc {
+	return func(ctx context.Context, cl cloud.Cloud) ([]Object, error) {
+		l, err := cl.RegionHealthCheckServices().List(ctx, region, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		var ret []Object
+		for _, o := range l {
+			ret = append(ret, Object{
+				ID:          healthcheckservice.ID(project, meta.RegionalKey(o.Name, region)),
+				Description: o.Description,
+			})
+		}
+		return ret, nil
+	}
+}
+
+// NotificationEndpoints lists the NotificationEndpoints in region.
+func NotificationEndpoints(project, region string) ListFunc {
+	return func(ctx context.Context, cl cloud.Cloud) ([]Object, error) {
+		l, err := cl.RegionNotificationEndpoints().List(ctx, region, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		var ret []Object
+		for _, o := range l {
+			ret = append(ret, Object{
+				ID:          notificationendpoint.ID(project, meta.RegionalKey(o.Name, region)),
+				Description: o.Description,
+			})
+		}
+		return ret, nil
+	}
+}
+
+// TargetSslProxies lists the TargetSslProxies.
+func TargetSslProxies(project string) ListFunc {
+	return func(ctx context.Context, cl cloud.Cloud) ([]Object, error) {
+		l, err := cl.TargetSslProxies().List(ctx, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		var ret []Object
+		for _, o := range l {
+			ret = append(ret, Object{
+				ID:          targetsslproxy.ID(project, meta.GlobalKey(o.Name)),
+				Description: o.Description,
+			})
+		}
+		return ret, nil
+	}
+}
+
+// InstanceGroupManagers lists the InstanceGroupManagers in zone.
+func InstanceGroupManagers(project, zone string) ListFunc {
+	return func(ctx context.Context, cl cloud.Cloud) ([]Object, error) {
+		l, err := cl.InstanceGroupManagers().List(ctx, zone, filter.None)
+		if err != nil {
+			return nil, err
+		}
+		var ret []Object
+		for _, o := range l {
+			ret = append(ret, Object{
+				ID:          instancegroupmanager.ID(project, meta.ZonalKey(o.Name, zone)),
+				Description: o.Description,
+			})
+		}
+		return ret, nil
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+)
+
+// Apply executes the Actions in r with a serial Executor. The OrderHints of
+// r are given to the Executor before opts. r may be restored from JSON (see
+// Result.UnmarshalJSON()), e.g. after it was reviewed. The state in r.Got
+// is not checked; use Refresh() before Apply() if r may be stale and
+// StaleStateOption() to check the state before each Action. The
+// Actions in r can only be executed once.
+//
+// The returned error is from exec.Executor.Run(); the exec.Result is
+// returned if execution was started.
+func Apply(ctx context.Context, cl cloud.Cloud, r *Result, opts ...exec.Option) (*exec.Result, error) {
+	if r == nil {
+		return nil, fmt.Errorf("plan: Apply: nil Result")
+	}
+	execOpts := append([]exec.Option{exec.OrderHintsOption(r.OrderHints...)}, opts...)
+	ex, err := exec.NewSerialExecutor(r.Actions, execOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("plan: Apply: %w", err)
+	}
+	return ex.Run(ctx, cl)
+}
+
+// StaleStateOption returns an exec.Option that fails the Actions on the
+// resources whose state in r.Got was fetched more than ttl before the Action
+// is run (see exec.StaleStateOption()). Execution of a long plan fails with
+// an error wrapping exec.ErrStaleState instead of acting on stale state; the
+// state should be fetched again with Refresh() or Do() and the new plan
+// applied.
+func StaleStateOption(r *Result, ttl time.Duration) exec.Option {
+	return exec.StaleStateOption(ttl, func(id *cloud.ResourceID) time.Time {
+		if r.Got == nil {
+			return time.Time{}
+		}
+		n := r.Got.Get(id)
+		if n == nil {
+			return time.Time{}
+		}
+		return n.FetchTime()
+	})
+}
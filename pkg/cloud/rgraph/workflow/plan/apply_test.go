@@ -0,0 +1,177 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestDoLocalApply(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	key := meta.RegionalKey("ne", region)
+	id := notificationendpoint.ID(proj, key)
+
+	r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+	if err := r.Access(func(x *compute.NotificationEndpoint) {
+		x.Name = "ne"
+		x.Description = "d"
+		x.NullFields = []string{"GrpcSettings"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	wb := notificationendpoint.NewBuilderWithResource(fr)
+	wb.SetState(rnode.NodeExists)
+	wb.SetOwnership(rnode.OwnershipManaged)
+	want := rgraph.NewBuilder()
+	want.Add(wb)
+
+	gb := notificationendpoint.NewBuilder(id)
+	gb.SetState(rnode.NodeDoesNotExist)
+	gb.SetOwnership(rnode.OwnershipManaged)
+	got := rgraph.NewBuilder()
+	got.Add(gb)
+
+	// Planning does not access the Cloud.
+	result, err := DoLocal(ctx, got.MustBuild(), want.MustBuild())
+	if err != nil {
+		t.Fatalf("DoLocal() = %v, want nil", err)
+	}
+	if op := result.Want.Get(id).Plan().Op(); op != rnode.OpCreate {
+		t.Fatalf("Op = %s, want %s", op, rnode.OpCreate)
+	}
+
+	// The plan is applied after a round trip through JSON, e.g. after
+	// being reviewed.
+	js, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	var reviewed Result
+	if err := json.Unmarshal(js, &reviewed); err != nil {
+		t.Fatalf("json.Unmarshal() = %v, want nil", err)
+	}
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	if _, err := Apply(ctx, mock, &reviewed); err != nil {
+		t.Fatalf("Apply() = %v, want nil", err)
+	}
+	ne, err := mock.RegionNotificationEndpoints().Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() = %v, want nil", err)
+	}
+	if ne.Description != "d" {
+		t.Errorf("Description = %q, want %q", ne.Description, "d")
+	}
+}
+
+func TestDoLocalMissingGot(t *testing.T) {
+	id := notificationendpoint.ID("proj-1", meta.RegionalKey("ne", "us-central1"))
+	nb := notificationendpoint.NewBuilder(id)
+	nb.SetState(rnode.NodeDoesNotExist)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	want := rgraph.NewBuilder()
+	want.Add(nb)
+
+	if _, err := DoLocal(context.Background(), rgraph.NewBuilder().MustBuild(), want.MustBuild()); err == nil {
+		t.Error("DoLocal() = nil, want error")
+	}
+}
+
+func TestApplyNil(t *testing.T) {
+	if _, err := Apply(context.Background(), nil, nil); err == nil {
+		t.Error("Apply(nil) = nil, want error")
+	}
+}
+
+func TestApplyStaleState(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	key := meta.RegionalKey("ne", region)
+	id := notificationendpoint.ID(proj, key)
+	fetched := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		now       time.Time
+		wantStale bool
+	}{
+		{name: "fresh", now: fetched.Add(30 * time.Second)},
+		{name: "stale", now: fetched.Add(2 * time.Minute), wantStale: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+			if err := r.Access(func(x *compute.NotificationEndpoint) {
+				x.Name = "ne"
+				x.NullFields = []string{"Description", "GrpcSettings"}
+			}); err != nil {
+				t.Fatalf("Access() = %v", err)
+			}
+			fr, err := r.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v", err)
+			}
+			wb := notificationendpoint.NewBuilderWithResource(fr)
+			wb.SetState(rnode.NodeExists)
+			wb.SetOwnership(rnode.OwnershipManaged)
+			want := rgraph.NewBuilder()
+			want.Add(wb)
+
+			gb := notificationendpoint.NewBuilder(id)
+			gb.SetState(rnode.NodeDoesNotExist)
+			gb.SetOwnership(rnode.OwnershipManaged)
+			gb.SetFetchTime(fetched)
+			got := rgraph.NewBuilder()
+			got.Add(gb)
+
+			result, err := DoLocal(ctx, got.MustBuild(), want.MustBuild())
+			if err != nil {
+				t.Fatalf("DoLocal() = %v, want nil", err)
+			}
+			mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+			_, err = Apply(ctx, mock, result, StaleStateOption(result, time.Minute), exec.ClockOption(func() time.Time { return tc.now }))
+			if gotStale := errors.Is(err, exec.ErrStaleState); gotStale != tc.wantStale {
+				t.Fatalf("Apply() = %v; errors.Is(ErrStaleState) = %t, want %t", err, gotStale, tc.wantStale)
+			}
+			_, err = mock.RegionNotificationEndpoints().Get(ctx, key)
+			if gotExists := err == nil; gotExists == tc.wantStale {
+				t.Errorf("Get() = %v; exists = %t, want %t", err, gotExists, !tc.wantStale)
+			}
+		})
+	}
+}
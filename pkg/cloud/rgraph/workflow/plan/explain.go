@@ -0,0 +1,177 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// Reason an Operation was planned for a resource.
+type Reason string
+
+const (
+	// ReasonNotManaged means the resource is not managed and will not
+	// be changed (OpNothing).
+	ReasonNotManaged Reason = "NotManaged"
+	// ReasonNoDiff means the resource matches want (OpNothing).
+	ReasonNoDiff Reason = "NoDiff"
+	// ReasonAbsent means the resource does not exist and is not wanted
+	// (OpNothing).
+	ReasonAbsent Reason = "Absent"
+	// ReasonMissing means the resource is wanted but does not exist
+	// (OpCreate).
+	ReasonMissing Reason = "Missing"
+	// ReasonNotWanted means the resource exists but is not wanted
+	// (OpDelete).
+	ReasonNotWanted Reason = "NotWanted"
+	// ReasonFieldsChanged means fields differ that can be updated in
+	// place (OpUpdate).
+	ReasonFieldsChanged Reason = "FieldsChanged"
+	// ReasonFieldsNotUpdatable means fields differ that cannot be updated
+	// in place (OpRecreate).
+	ReasonFieldsNotUpdatable Reason = "FieldsNotUpdatable"
+	// ReasonUnknown is for plans that do not match any of the above.
+	ReasonUnknown Reason = "Unknown"
+)
+
+// Explanation of the Operation planned for a resource.
+type Explanation struct {
+	ID        *cloud.ResourceID
+	Operation rnode.Operation
+	Reason    Reason
+	// Why is the human readable explanation from the plan.
+	Why string
+	// Changed are the paths of the fields that differ between got and
+	// want.
+	Changed []api.Path
+	// Recreate are the paths in Changed that cannot be updated in place
+	// and forced OpRecreate.
+	Recreate []api.Path
+	// ReferencedBy are the references to a Missing resource from the
+	// other wanted resources that need it to exist.
+	ReferencedBy []rnode.ResourceRef
+}
+
+// String implements Stringer.
+func (e *Explanation) String() string {
+	var parts []string
+	if len(e.Recreate) > 0 {
+		parts = append(parts, "recreate="+pathsString(e.Recreate))
+	} else if len(e.Changed) > 0 {
+		parts = append(parts, "changed="+pathsString(e.Changed))
+	}
+	if len(e.ReferencedBy) > 0 {
+		var refs []string
+		for _, ref := range e.ReferencedBy {
+			refs = append(refs, ref.From.String())
+		}
+		parts = append(parts, "referencedBy=["+strings.Join(refs, ", ")+"]")
+	}
+	ret := fmt.Sprintf("%v: %s (%s)", e.ID, e.Operation, e.Reason)
+	if len(parts) > 0 {
+		ret += " " + strings.Join(parts, " ")
+	}
+	return ret
+}
+
+func pathsString(paths []api.Path) string {
+	var s []string
+	for _, p := range paths {
+		s = append(s, p.String())
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// Explain returns the Explanations for the planned resources, sorted by
+// ResourceID.
+func (r *Result) Explain() []Explanation {
+	if r.Want == nil {
+		return nil
+	}
+	var ret []Explanation
+	for _, n := range r.Want.All() {
+		if e := r.explain(n); e != nil {
+			ret = append(ret, *e)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID.String() < ret[j].ID.String() })
+	return ret
+}
+
+// ExplainID returns the Explanation for id. Returns nil if the resource is
+// not in the plan.
+func (r *Result) ExplainID(id *cloud.ResourceID) *Explanation {
+	if r.Want == nil {
+		return nil
+	}
+	n := r.Want.Get(id)
+	if n == nil {
+		return nil
+	}
+	return r.explain(n)
+}
+
+func (r *Result) explain(n rnode.Node) *Explanation {
+	details := n.Plan().Details()
+	if details == nil {
+		return nil
+	}
+	ret := &Explanation{
+		ID:        n.ID(),
+		Operation: details.Operation,
+		Why:       details.Why,
+		Recreate:  details.RecreatePaths,
+	}
+	if details.Diff != nil {
+		for _, item := range details.Diff.Items {
+			ret.Changed = append(ret.Changed, item.Path)
+		}
+	}
+
+	switch details.Operation {
+	case rnode.OpNothing:
+		switch {
+		case n.Ownership() != rnode.OwnershipManaged:
+			ret.Reason = ReasonNotManaged
+		case n.State() == rnode.NodeDoesNotExist:
+			ret.Reason = ReasonAbsent
+		default:
+			ret.Reason = ReasonNoDiff
+		}
+	case rnode.OpCreate:
+		ret.Reason = ReasonMissing
+		ret.ReferencedBy = append([]rnode.ResourceRef{}, n.InRefs()...)
+		sort.Slice(ret.ReferencedBy, func(i, j int) bool {
+			return ret.ReferencedBy[i].From.String() < ret.ReferencedBy[j].From.String()
+		})
+	case rnode.OpDelete:
+		ret.Reason = ReasonNotWanted
+	case rnode.OpUpdate:
+		ret.Reason = ReasonFieldsChanged
+	case rnode.OpRecreate:
+		ret.Reason = ReasonFieldsNotUpdatable
+	default:
+		ret.Reason = ReasonUnknown
+	}
+	return ret
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExplain(t *testing.T) {
+	id := func(name string) *cloud.ResourceID { return fake.ID("proj", meta.GlobalKey(name)) }
+	field := func(name string) api.Path { return api.Path{}.Pointer().Field(name) }
+
+	b := rgraph.NewBuilder()
+	for _, name := range []string{"create", "ref1", "ref2", "recreate", "update", "delete", "nop", "absent", "ext"} {
+		nb := fake.NewBuilder(id(name))
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		switch name {
+		case "ref1", "ref2":
+			nb.FakeOutRefs = []rnode.ResourceRef{{From: id(name), Path: field("Dependencies"), To: id("create")}}
+		case "delete", "absent":
+			nb.SetState(rnode.NodeDoesNotExist)
+		case "ext":
+			nb.SetOwnership(rnode.OwnershipExternal)
+		}
+		b.Add(nb)
+	}
+	want := b.MustBuild()
+	plans := map[string]rnode.PlanDetails{
+		"create": {Operation: rnode.OpCreate},
+		"ref1":   {Operation: rnode.OpCreate},
+		"ref2":   {Operation: rnode.OpNothing},
+		"recreate": {
+			Operation:     rnode.OpRecreate,
+			Diff:          &api.DiffResult{Items: []api.DiffItem{{Path: field("A")}, {Path: field("B"), Immutable: true}}},
+			RecreatePaths: []api.Path{field("B")},
+		},
+		"update": {
+			Operation: rnode.OpUpdate,
+			Diff:      &api.DiffResult{Items: []api.DiffItem{{Path: field("A")}}},
+		},
+		"delete": {Operation: rnode.OpDelete},
+		"nop":    {Operation: rnode.OpNothing},
+		"absent": {Operation: rnode.OpNothing},
+		"ext":    {Operation: rnode.OpNothing},
+	}
+	for name, p := range plans {
+		want.Get(id(name)).Plan().Set(p)
+	}
+	r := &Result{Want: want}
+
+	var got []string
+	for _, e := range r.Explain() {
+		got = append(got, e.String())
+	}
+	wantStr := []string{
+		"fakes:proj/absent: Nothing (Absent)",
+		"fakes:proj/create: Create (Missing) referencedBy=[fakes:proj/ref1, fakes:proj/ref2]",
+		"fakes:proj/delete: Delete (NotWanted)",
+		"fakes:proj/ext: Nothing (NotManaged)",
+		"fakes:proj/nop: Nothing (NoDiff)",
+		"fakes:proj/recreate: Recreate (FieldsNotUpdatable) recreate=[*.B]",
+		"fakes:proj/ref1: Create (Missing)",
+		"fakes:proj/ref2: Nothing (NoDiff)",
+		"fakes:proj/update: Update (FieldsChanged) changed=[*.A]",
+	}
+	if diff := cmp.Diff(got, wantStr); diff != "" {
+		t.Errorf("Explain(); -got,+want: %s", diff)
+	}
+
+	e := r.ExplainID(id("recreate"))
+	if e == nil {
+		t.Fatalf("ExplainID(recreate) = nil")
+	}
+	if len(e.Changed) != 2 || len(e.Recreate) != 1 || !e.Recreate[0].Equal(field("B")) {
+		t.Errorf("ExplainID(recreate) = %+v; want Changed=[A, B], Recreate=[B]", e)
+	}
+	if e := r.ExplainID(id("missing")); e != nil {
+		t.Errorf("ExplainID(missing) = %v, want nil", e)
+	}
+}
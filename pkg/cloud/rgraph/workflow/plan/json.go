@@ -0,0 +1,59 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/actions"
+)
+
+// resultJSON is the serialized form of a Result. The Actions and OrderHints
+// are not saved; they are computed from the Graphs when the Result is
+// restored.
+type resultJSON struct {
+	Got  *rgraph.Graph `json:"got"`
+	Want *rgraph.Graph `json:"want"`
+}
+
+// MarshalJSON implements json.Marshaler. This saves the Got and Want Graphs
+// with their plans so that the Result can be restored with UnmarshalJSON(),
+// e.g. to resume execution after a restart with exec.ProgressOption().
+func (r *Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&resultJSON{Got: r.Got, Want: r.Want})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The Actions are computed from
+// the restored Graphs; their names are the same as in the Result that was
+// saved.
+func (r *Result) UnmarshalJSON(b []byte) error {
+	var rj resultJSON
+	if err := json.Unmarshal(b, &rj); err != nil {
+		return fmt.Errorf("plan: UnmarshalJSON: %w", err)
+	}
+	if rj.Got == nil || rj.Want == nil {
+		return fmt.Errorf("plan: UnmarshalJSON: missing got or want Graph")
+	}
+	acts, err := actions.Do(rj.Got, rj.Want)
+	if err != nil {
+		return fmt.Errorf("plan: UnmarshalJSON: %w", err)
+	}
+	*r = Result{Got: rj.Got, Want: rj.Want, Actions: acts, OrderHints: orderHints(rj.Want)}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestResultJSON(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	key := meta.RegionalKey("ne", region)
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var inserts int
+	mock.MockRegionNotificationEndpoints.InsertHook = func(context.Context, *meta.Key, *compute.NotificationEndpoint, *cloud.MockRegionNotificationEndpoints) (bool, error) {
+		inserts++
+		return false, nil
+	}
+
+	r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+	if err := r.Access(func(x *compute.NotificationEndpoint) {
+		x.Name = "ne"
+		x.Description = "d"
+		x.NullFields = []string{"GrpcSettings"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	nb := notificationendpoint.NewBuilderWithResource(fr)
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	b := rgraph.NewBuilder()
+	b.Add(nb)
+
+	result, err := Do(ctx, mock, b.MustBuild())
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	js, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v, want nil", err)
+	}
+	var restored Result
+	if err := json.Unmarshal(js, &restored); err != nil {
+		t.Fatalf("json.Unmarshal() = %v, want nil", err)
+	}
+
+	names := func(acts []exec.Action) []string {
+		var ret []string
+		for _, a := range acts {
+			ret = append(ret, a.Metadata().Name)
+		}
+		return ret
+	}
+	if diff := cmp.Diff(names(restored.Actions), names(result.Actions)); diff != "" {
+		t.Errorf("restored Actions: diff -got,+want: %s", diff)
+	}
+	if got := restored.Want.Get(notificationendpoint.ID(proj, key)).Plan().Op(); got != rnode.OpCreate {
+		t.Errorf("restored Op = %s, want %s", got, rnode.OpCreate)
+	}
+
+	// Executing the original and then the restored Result with the same
+	// progress does not create the resource twice.
+	store := &exec.FileProgressStore{Path: filepath.Join(t.TempDir(), "progress.json")}
+	for _, res := range []*Result{result, &restored} {
+		ex, err := exec.NewSerialExecutor(res.Actions, exec.ProgressOption(store))
+		if err != nil {
+			t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+		}
+		if _, err := ex.Run(ctx, mock); err != nil {
+			t.Fatalf("Run() = %v, want nil", err)
+		}
+	}
+	if inserts != 1 {
+		t.Errorf("inserts = %d, want 1", inserts)
+	}
+}
+
+func TestResultJSONErrors(t *testing.T) {
+	var r Result
+	if err := json.Unmarshal([]byte(`{"got":{"nodes":[]}}`), &r); err == nil {
+		t.Error("json.Unmarshal(missing want) = nil, want error")
+	}
+}
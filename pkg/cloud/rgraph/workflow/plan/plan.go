@@ -0,0 +1,340 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan computes the Actions needed to transform the current state of
+// the resources in the Cloud to a wanted Graph.
+//
+// Planning and execution are separate steps: Do() returns a Result that can
+// be inspected (see Result.Explain() and Result.Summary()) and serialized
+// (see Result.MarshalJSON()) before it is executed with Apply(). This allows
+// the changes to be reviewed before they are made. DoLocal() plans against a
+// given current state without accessing the Cloud, e.g. for testing.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/actions"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/algo/localplan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/eventsink"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/go-logr/logr"
+)
+
+// Result of planning.
+type Result struct {
+	// Got is the current state of the resources in the Cloud.
+	Got *rgraph.Graph
+	// Want is the wanted Graph, with the plan for each Node filled in.
+	Want *rgraph.Graph
+	// Actions to execute to transform Got to Want.
+	Actions []exec.Action
+	// OrderHints declared by the Nodes in Want (see rnode.OrderHinter).
+	// These should be given to the Executor with exec.OrderHintsOption().
+	OrderHints []exec.OrderHint
+}
+
+// Option for planning.
+type Option func(*config)
+
+// EventSinkOption sets a sink for Events about the planned changes.
+func EventSinkOption(s eventsink.Sink) Option {
+	return func(c *config) { c.eventSink = s }
+}
+
+// IncrementalOption reuses the state from a previous planning to reduce the
+// number of calls to the Cloud:
+//
+//   - Nodes in prev.Got that were fetched within ttl and did not have a
+//     change planned are not fetched again.
+//   - The plans in prev.Want are reused for Nodes that are unchanged in
+//     both the got and want Graphs (see localplan.ReuseOption()).
+//
+// The reuse of fetched Nodes is TTL-based caching: the resources are not
+// checked against the Cloud (e.g. by Fingerprint, which would need a Get
+// anyway), so a change made by another client within ttl of the fetch is
+// not seen until the Node is fetched again. Choose ttl accordingly; a ttl
+// of 0 fetches every Node.
+//
+// prev may be nil, in which case this option has no effect.
+func IncrementalOption(prev *Result, ttl time.Duration) Option {
+	return func(c *config) {
+		c.prev = prev
+		c.ttl = ttl
+	}
+}
+
+// LoggerOption sets the Logger for planning. The log lines for a Node have
+// the keys defined in package rlog. The default is the Logger in the context
+// (see rlog.FromContext()).
+func LoggerOption(l logr.Logger) Option {
+	return func(c *config) { c.logger = l }
+}
+
+type config struct {
+	eventSink eventsink.Sink
+	logger    logr.Logger
+
+	prev *Result
+	ttl  time.Duration
+}
+
+// Do fetches the current state of the resources in want from the Cloud and
+// plans the Actions needed to get to the want state. The Nodes in want will be
+// updated with their plans. Returns an error wrapping a *rgraph.CycleError if
+// the references between the resources form a cycle and a
+// *rgraph.ValidationError if want fails rgraph.Validate().
+func Do(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph, opts ...Option) (*Result, error) {
+	c := newConfig(ctx, opts)
+
+	// want is checked before fetching as the Actions for the Nodes in a
+	// cycle can never run.
+	if err := checkWant(want); err != nil {
+		return nil, err
+	}
+	got, err := syncGot(ctx, cl, want, c)
+	if err != nil {
+		return nil, err
+	}
+	return doWithGot(c, got, want)
+}
+
+// DoLocal plans the Actions needed to get from got to want without accessing
+// the Cloud. got is the current state of the resources, e.g. from the
+// Result.Got of a previous planning or built by hand in a test; it must
+// contain a Node for each Node in want. The Nodes in want will be updated
+// with their plans. Errors are the same as for Do().
+func DoLocal(ctx context.Context, got, want *rgraph.Graph, opts ...Option) (*Result, error) {
+	c := newConfig(ctx, opts)
+
+	if err := checkWant(want); err != nil {
+		return nil, err
+	}
+	for _, n := range want.All() {
+		if got.Get(n.ID()) == nil {
+			return nil, fmt.Errorf("plan: got: missing node %s", n.ID())
+		}
+	}
+	return doWithGot(c, got, want)
+}
+
+// checkWant returns an error if want has a cycle or fails rgraph.Validate().
+func checkWant(want *rgraph.Graph) error {
+	if ce := want.FindCycle(); ce != nil {
+		return fmt.Errorf("plan: want: %w", ce)
+	}
+	if err := rgraph.Validate(want); err != nil {
+		return fmt.Errorf("plan: want: %w", err)
+	}
+	return nil
+}
+
+// doWithGot plans want against the current state in got.
+func doWithGot(c *config, got, want *rgraph.Graph) (*Result, error) {
+	if ce := got.FindCycle(); ce != nil {
+		return nil, fmt.Errorf("plan: got: %w", ce)
+	}
+	var lpOpts []localplan.Option
+	if c.prev != nil {
+		lpOpts = append(lpOpts, localplan.ReuseOption(c.prev.Got, c.prev.Want))
+	}
+	if err := localplan.PlanWantGraph(got, want, lpOpts...); err != nil {
+		return nil, fmt.Errorf("plan: %w", err)
+	}
+	emitPlanEvents(c.eventSink, want)
+
+	acts, err := actions.Do(got, want)
+	if err != nil {
+		return nil, fmt.Errorf("plan: %w", err)
+	}
+	ret := &Result{Got: got, Want: want, Actions: acts, OrderHints: orderHints(want)}
+	logPlan(c.logger, want)
+	if l := c.logger.V(4); l.Enabled() {
+		l.Info("Planned", "nodes", len(want.All()), "actions", len(acts), "summary", ret.Summary().String())
+	}
+
+	return ret, nil
+}
+
+// Stale returns the resources with planned changes whose state in Got was
+// fetched more than ttl before now. Executing Actions against stale state
+// risks overwriting changes made in the Cloud since planning.
+func (r *Result) Stale(ttl time.Duration, now time.Time) []*cloud.ResourceID {
+	var ret []*cloud.ResourceID
+	for _, n := range r.Want.All() {
+		details := n.Plan().Details()
+		if details == nil || details.Operation == rnode.OpNothing {
+			continue
+		}
+		gotNode := r.Got.Get(n.ID())
+		if gotNode == nil || rnode.IsStale(gotNode, ttl, now) {
+			ret = append(ret, n.ID())
+		}
+	}
+	return ret
+}
+
+// Refresh re-plans if any of the resources with planned changes in r are
+// Stale(). The current state of all of the resources is re-fetched as the
+// plan for a resource can depend on the state of the other resources. Returns
+// r if nothing is stale.
+func Refresh(ctx context.Context, cl cloud.Cloud, r *Result, ttl time.Duration, opts ...Option) (*Result, error) {
+	stale := r.Stale(ttl, time.Now())
+	if len(stale) == 0 {
+		return r, nil
+	}
+	newConfig(ctx, opts).logger.V(4).Info("Stale resources, re-planning", "stale", len(stale), rlog.KeyNode, stale[0].String())
+	return Do(ctx, cl, r.Want, opts...)
+}
+
+// syncGot builds the Graph of the current state of the resources in want.
+func syncGot(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph, c *config) (*rgraph.Graph, error) {
+	gotBuilder := want.NewBuilderWithEmptyNodes()
+	now := time.Now()
+	var reused int
+	for _, nb := range gotBuilder.All() {
+		if n := c.reusableGot(nb.ID(), now); n != nil {
+			if err := copyToBuilder(nb, n); err != nil {
+				return nil, fmt.Errorf("plan: sync %s: %w", nb.ID(), err)
+			}
+			reused++
+			continue
+		}
+		if err := nb.SyncFromCloud(ctx, cl); err != nil {
+			return nil, fmt.Errorf("plan: sync %s: %w", nb.ID(), err)
+		}
+		nb.SetFetchTime(time.Now())
+		if l := c.logger.V(5); l.Enabled() {
+			kv := []any{rlog.KeyNode, nb.ID().String(), "state", string(nb.State())}
+			if r := nb.Resource(); r != nil {
+				kv = append(kv, rlog.KeyVersion, string(r.Version()))
+			}
+			l.Info("Fetched", kv...)
+		}
+	}
+	if c.prev != nil {
+		c.logger.V(4).Info("Reused fetched nodes", "reused", reused, "nodes", len(gotBuilder.All()))
+	}
+	got, err := gotBuilder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("plan: %w", err)
+	}
+	return got, nil
+}
+
+// reusableGot returns the Node for id from the previous planning if it can
+// be used without fetching the resource again. Returns nil otherwise. This
+// only checks the age of the Node (see IncrementalOption()).
+func (c *config) reusableGot(id *cloud.ResourceID, now time.Time) rnode.Node {
+	if c.prev == nil || c.prev.Got == nil || c.prev.Want == nil {
+		return nil
+	}
+	gotNode := c.prev.Got.Get(id)
+	if gotNode == nil || gotNode.State() != rnode.NodeExists && gotNode.State() != rnode.NodeDoesNotExist {
+		return nil
+	}
+	if rnode.IsStale(gotNode, c.ttl, now) {
+		return nil
+	}
+	// The resource may have been changed by executing the previous plan.
+	wantNode := c.prev.Want.Get(id)
+	if wantNode == nil || wantNode.Plan().Op() != rnode.OpNothing {
+		return nil
+	}
+	return gotNode
+}
+
+// copyToBuilder sets the state of nb to that of n.
+func copyToBuilder(nb rnode.Builder, n rnode.Node) error {
+	nb.SetState(n.State())
+	nb.SetFetchTime(n.FetchTime())
+	if r := n.Resource(); r != nil {
+		return nb.SetResource(r)
+	}
+	return nil
+}
+
+// orderHints returns the OrderHints of the Nodes in g, without duplicates.
+func orderHints(g *rgraph.Graph) []exec.OrderHint {
+	var ret []exec.OrderHint
+	seen := map[exec.OrderHint]bool{}
+	for _, n := range g.All() {
+		oh, ok := n.(rnode.OrderHinter)
+		if !ok {
+			continue
+		}
+		for _, h := range oh.OrderHints() {
+			if !seen[h] {
+				seen[h] = true
+				ret = append(ret, h)
+			}
+		}
+	}
+	return ret
+}
+
+func newConfig(ctx context.Context, opts []Option) *config {
+	c := &config{}
+	for _, o := range opts {
+		o(c)
+	}
+	c.logger = rlog.OrContext(ctx, c.logger)
+	return c
+}
+
+// logPlan logs the plan for each Node that has a change planned.
+func logPlan(logger logr.Logger, want *rgraph.Graph) {
+	l := logger.V(2)
+	if !l.Enabled() {
+		return
+	}
+	for _, n := range want.All() {
+		details := n.Plan().Details()
+		if details == nil || details.Operation == rnode.OpNothing {
+			continue
+		}
+		kv := []any{rlog.KeyNode, n.ID().String(), rlog.KeyOp, string(details.Operation), "why", details.Why}
+		if r := n.Resource(); r != nil {
+			kv = append(kv, rlog.KeyVersion, string(r.Version()))
+		}
+		l.Info("Planned change", kv...)
+	}
+}
+
+// emitPlanEvents emits an Event for each Node that has a change planned.
+func emitPlanEvents(sink eventsink.Sink, want *rgraph.Graph) {
+	if sink == nil {
+		return
+	}
+	for _, n := range want.All() {
+		details := n.Plan().Details()
+		if details == nil || details.Operation == rnode.OpNothing {
+			continue
+		}
+		sink.Emit(&eventsink.Event{
+			Severity:   eventsink.SeverityNormal,
+			Reason:     eventsink.ReasonPlanned,
+			ResourceID: n.ID(),
+			Message:    fmt.Sprintf("%s: %s", details.Operation, details.Why),
+		})
+	}
+}
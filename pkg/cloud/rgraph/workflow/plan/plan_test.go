@@ -0,0 +1,697 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/api"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/forwardingrule"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheckservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/serviceattachment"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/compute/v1"
+)
+
+func TestDoIncremental(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	key := meta.RegionalKey("ne", region)
+
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var gets int
+	mock.MockRegionNotificationEndpoints.GetHook = func(context.Context, *meta.Key, *cloud.MockRegionNotificationEndpoints) (bool, *compute.NotificationEndpoint, error) {
+		gets++
+		return false, nil, nil
+	}
+
+	newWant := func(description string) *rgraph.Graph {
+		r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+		if err := r.Access(func(x *compute.NotificationEndpoint) {
+			x.Name = "ne"
+			x.Description = description
+			x.NullFields = []string{"GrpcSettings"}
+		}); err != nil {
+			t.Fatalf("Access() = %v", err)
+		}
+		fr, err := r.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		nb := notificationendpoint.NewBuilderWithResource(fr)
+		nb.SetState(rnode.NodeExists)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		b := rgraph.NewBuilder()
+		b.Add(nb)
+		return b.MustBuild()
+	}
+	if err := mock.RegionNotificationEndpoints().Insert(ctx, key, &compute.NotificationEndpoint{Name: "ne", Description: "a"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	op := func(r *Result) rnode.Operation {
+		return r.Want.Get(notificationendpoint.ID(proj, key)).Plan().Op()
+	}
+
+	r1, err := Do(ctx, mock, newWant("a"))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if gets != 1 || op(r1) != rnode.OpNothing {
+		t.Fatalf("Do(): gets = %d, op = %s; want 1, %s", gets, op(r1), rnode.OpNothing)
+	}
+
+	// The got state is fresh and there was no change planned.
+	r2, err := Do(ctx, mock, newWant("a"), IncrementalOption(r1, time.Hour))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if gets != 1 || op(r2) != rnode.OpNothing {
+		t.Errorf("Do(incremental): gets = %d, op = %s; want 1, %s", gets, op(r2), rnode.OpNothing)
+	}
+
+	// want changed; the reused got state is diffed.
+	r3, err := Do(ctx, mock, newWant("b"), IncrementalOption(r2, time.Hour))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if gets != 1 || op(r3) != rnode.OpRecreate {
+		t.Errorf("Do(incremental, want changed): gets = %d, op = %s; want 1, %s", gets, op(r3), rnode.OpRecreate)
+	}
+
+	// A change was planned so the resource is fetched again.
+	if _, err := Do(ctx, mock, newWant("b"), IncrementalOption(r3, time.Hour)); err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if gets != 2 {
+		t.Errorf("Do(incremental, after change): gets = %d, want 2", gets)
+	}
+
+	// The got state is stale.
+	if _, err := Do(ctx, mock, newWant("a"), IncrementalOption(r2, 0)); err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if gets != 3 {
+		t.Errorf("Do(incremental, stale): gets = %d, want 3", gets)
+	}
+}
+
+func TestDoCycle(t *testing.T) {
+	path := api.Path{}.Pointer().Field("Dependencies")
+	a := fake.ID("proj", meta.GlobalKey("a"))
+	b := fake.ID("proj", meta.GlobalKey("b"))
+	gb := rgraph.NewBuilder()
+	for _, ref := range []rnode.ResourceRef{{From: a, Path: path, To: b}, {From: b, Path: path, To: a}} {
+		nb := fake.NewBuilder(ref.From)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		nb.FakeOutRefs = []rnode.ResourceRef{ref}
+		gb.Add(nb)
+	}
+
+	_, err := Do(context.Background(), nil, gb.MustBuild())
+	var ce *rgraph.CycleError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Do() = %v, want CycleError", err)
+	}
+	const want = "plan: want: reference cycle: fakes:proj/a (*.Dependencies) -> fakes:proj/b (*.Dependencies) -> fakes:proj/a"
+	if err.Error() != want {
+		t.Errorf("Do() = %q, want %q", err, want)
+	}
+}
+
+func TestDoInvalid(t *testing.T) {
+	a := fake.ID("proj", meta.GlobalKey("a"))
+	b := fake.ID("proj", meta.GlobalKey("b"))
+	gb := rgraph.NewBuilder()
+	nb := fake.NewBuilder(a)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeExists)
+	nb.FakeOutRefs = []rnode.ResourceRef{{From: a, Path: api.Path{}.Pointer().Field("Dependencies"), To: b}}
+	gb.Add(nb)
+	nb = fake.NewBuilder(b)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	nb.SetState(rnode.NodeDoesNotExist)
+	gb.Add(nb)
+
+	_, err := Do(context.Background(), nil, gb.MustBuild())
+	var ve *rgraph.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Do() = %v, want ValidationError", err)
+	}
+	if len(ve.Problems) != 1 || ve.Problems[0].Check != rgraph.CheckDanglingRef {
+		t.Errorf("Do() = %v, want a single %s problem", err, rgraph.CheckDanglingRef)
+	}
+}
+
+func TestDoEnsureAbsentTree(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	neKey := meta.RegionalKey("ne", region)
+	hcsKey := meta.RegionalKey("hcs", region)
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+
+	ne := &compute.NotificationEndpoint{Name: "ne"}
+	hcs := &compute.HealthCheckService{
+		Name:                  "hcs",
+		NotificationEndpoints: []string{cloud.SelfLink(meta.VersionGA, proj, "notificationEndpoints", neKey)},
+	}
+	if err := mock.RegionNotificationEndpoints().Insert(ctx, neKey, ne); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := mock.RegionHealthCheckServices().Insert(ctx, hcsKey, hcs); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	// The wanted state of the Nodes is not known; only that the
+	// HealthCheckService and the resources it uses should be removed.
+	b := rgraph.NewBuilder()
+	for _, nb := range []rnode.Builder{
+		notificationendpoint.NewBuilder(notificationendpoint.ID(proj, neKey)),
+		healthcheckservice.NewBuilder(healthcheckservice.ID(proj, hcsKey)),
+	} {
+		nb.SetOwnership(rnode.OwnershipManaged)
+		if err := nb.SyncFromCloud(ctx, mock); err != nil {
+			t.Fatalf("SyncFromCloud() = %v", err)
+		}
+		b.Add(nb)
+	}
+	if _, err := b.EnsureAbsentTree(healthcheckservice.ID(proj, hcsKey)); err != nil {
+		t.Fatalf("EnsureAbsentTree() = %v", err)
+	}
+	want, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	r, err := Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	ex, err := exec.NewSerialExecutor(r.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	res, err := ex.Run(ctx, mock)
+	if err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+
+	// The HealthCheckService must be deleted before the
+	// NotificationEndpoint it references.
+	var deleted []string
+	for _, a := range res.Completed {
+		if md := a.Metadata(); md.Type == exec.ActionTypeDelete {
+			deleted = append(deleted, md.ResourceID.Key.Name)
+		}
+	}
+	if diff := cmp.Diff(deleted, []string{"hcs", "ne"}); diff != "" {
+		t.Errorf("deleted; -got,+want: %s", diff)
+	}
+	if _, err := mock.RegionNotificationEndpoints().Get(ctx, neKey); err == nil {
+		t.Errorf("Get(ne) = nil, want error")
+	}
+}
+
+func TestDoExternal(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	neKey := meta.RegionalKey("ne", region)
+	hcsKey := meta.RegionalKey("hcs", region)
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+
+	ne := &compute.NotificationEndpoint{Name: "ne"}
+	hcs := &compute.HealthCheckService{
+		Name:                  "hcs",
+		NotificationEndpoints: []string{cloud.SelfLink(meta.VersionGA, proj, "notificationEndpoints", neKey)},
+	}
+	if err := mock.RegionNotificationEndpoints().Insert(ctx, neKey, ne); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := mock.RegionHealthCheckServices().Insert(ctx, hcsKey, hcs); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	// The NotificationEndpoint is shared with someone else and must be
+	// left alone when the HealthCheckService is deleted.
+	b := rgraph.NewBuilder()
+	if _, err := b.AddExternal(ctx, mock, notificationendpoint.ID(proj, neKey)); err != nil {
+		t.Fatalf("AddExternal() = %v, want nil", err)
+	}
+	if _, err := b.AddExternal(ctx, mock, notificationendpoint.ID(proj, neKey)); err == nil {
+		t.Errorf("AddExternal() (duplicate) = nil, want error")
+	}
+	if _, err := b.AddExternal(ctx, mock, notificationendpoint.ID(proj, meta.RegionalKey("missing", region))); err == nil {
+		t.Errorf("AddExternal() (missing) = nil, want error")
+	}
+	nb := healthcheckservice.NewBuilder(healthcheckservice.ID(proj, hcsKey))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	if err := nb.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v", err)
+	}
+	b.Add(nb)
+	ids, err := b.EnsureAbsentTree(healthcheckservice.ID(proj, hcsKey))
+	if err != nil {
+		t.Fatalf("EnsureAbsentTree() = %v", err)
+	}
+	if len(ids) != 1 {
+		t.Errorf("EnsureAbsentTree() = %v, want [hcs]", ids)
+	}
+	want, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	r, err := Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if op := want.Get(notificationendpoint.ID(proj, neKey)).Plan().Op(); op != rnode.OpNothing {
+		t.Errorf("plan for ne = %s, want %s", op, rnode.OpNothing)
+	}
+	ex, err := exec.NewSerialExecutor(r.Actions)
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v", err)
+	}
+	if _, err := ex.Run(ctx, mock); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	if _, err := mock.RegionNotificationEndpoints().Get(ctx, neKey); err != nil {
+		t.Errorf("Get(ne) = %v, want nil", err)
+	}
+
+	// Planning fails if the external resource is gone.
+	if err := mock.RegionNotificationEndpoints().Delete(ctx, neKey); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if _, err := Do(ctx, mock, want); err == nil {
+		t.Errorf("Do() = nil, want error (external resource does not exist)")
+	}
+}
+
+func TestDoCrossProject(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		host   = "host-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	neKey := meta.RegionalKey("ne", region)
+	hcsKey := meta.RegionalKey("hcs", region)
+	mock := cloud.NewMockGCE(&cloud.ContextProjectRouter{Router: &cloud.SingleProjectRouter{ID: proj}})
+	var neProjects []string
+	mock.MockRegionNotificationEndpoints.GetHook = func(ctx context.Context, _ *meta.Key, _ *cloud.MockRegionNotificationEndpoints) (bool, *compute.NotificationEndpoint, error) {
+		neProjects = append(neProjects, cloud.ProjectIDFromContext(ctx))
+		return false, nil, nil
+	}
+
+	// The NotificationEndpoint is in the host project.
+	hcs := &compute.HealthCheckService{
+		Name:                  "hcs",
+		NotificationEndpoints: []string{cloud.SelfLink(meta.VersionGA, host, "notificationEndpoints", neKey)},
+	}
+	if err := mock.RegionNotificationEndpoints().Insert(cloud.WithProjectID(ctx, host), neKey, &compute.NotificationEndpoint{Name: "ne"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	if err := mock.RegionHealthCheckServices().Insert(ctx, hcsKey, hcs); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+
+	b := rgraph.NewBuilder()
+	if _, err := b.AddExternal(ctx, mock, notificationendpoint.ID(host, neKey)); err != nil {
+		t.Fatalf("AddExternal() = %v, want nil", err)
+	}
+	nb := healthcheckservice.NewBuilder(healthcheckservice.ID(proj, hcsKey))
+	nb.SetOwnership(rnode.OwnershipManaged)
+	if err := nb.SyncFromCloud(ctx, mock); err != nil {
+		t.Fatalf("SyncFromCloud() = %v", err)
+	}
+	b.Add(nb)
+	want, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+
+	if _, err := Do(ctx, mock, want); err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	for _, id := range []*cloud.ResourceID{notificationendpoint.ID(host, neKey), healthcheckservice.ID(proj, hcsKey)} {
+		if op := want.Get(id).Plan().Op(); op != rnode.OpNothing {
+			t.Errorf("plan for %v = %s, want %s", id, op, rnode.OpNothing)
+		}
+	}
+	for _, p := range neProjects {
+		if p != host {
+			t.Errorf("NotificationEndpoint Get() in project %q, want %q", p, host)
+		}
+	}
+	if len(neProjects) == 0 {
+		t.Error("NotificationEndpoint Get() was not called")
+	}
+}
+
+func TestDoOtherProject(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		other  = "other-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	hcsKey := meta.RegionalKey("hcs", region)
+	hcsID := healthcheckservice.ID(other, hcsKey)
+	mock := cloud.NewMockGCE(&cloud.ContextProjectRouter{Router: &cloud.SingleProjectRouter{ID: proj}})
+	var calls []string
+	record := func(ctx context.Context, method string) {
+		calls = append(calls, method+" "+cloud.ProjectIDFromContext(ctx))
+	}
+	mock.MockRegionHealthCheckServices.InsertHook = func(ctx context.Context, _ *meta.Key, _ *compute.HealthCheckService, _ *cloud.MockRegionHealthCheckServices) (bool, error) {
+		record(ctx, "Insert")
+		return false, nil
+	}
+	mock.MockRegionHealthCheckServices.PatchHook = func(ctx context.Context, _ *meta.Key, _ *compute.HealthCheckService, _ *cloud.MockRegionHealthCheckServices) error {
+		record(ctx, "Patch")
+		return nil
+	}
+	mock.MockRegionHealthCheckServices.DeleteHook = func(ctx context.Context, _ *meta.Key, _ *cloud.MockRegionHealthCheckServices) (bool, error) {
+		record(ctx, "Delete")
+		return false, nil
+	}
+
+	// apply plans and executes the change to the HealthCheckService in the
+	// other project.
+	apply := func(setup func(rnode.Builder)) {
+		t.Helper()
+		nb := healthcheckservice.NewBuilder(hcsID)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		setup(nb)
+		b := rgraph.NewBuilder()
+		b.Add(nb)
+		want, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		r, err := Do(ctx, mock, want)
+		if err != nil {
+			t.Fatalf("Do() = %v, want nil", err)
+		}
+		if _, err := Apply(ctx, mock, r); err != nil {
+			t.Fatalf("Apply() = %v, want nil", err)
+		}
+	}
+	withDescription := func(desc string) func(rnode.Builder) {
+		return func(nb rnode.Builder) {
+			hcs := healthcheckservice.NewMutableHealthCheckService(other, hcsKey)
+			if err := hcs.Access(func(x *compute.HealthCheckService) {
+				x.Name = "hcs"
+				x.Description = desc
+				x.ForceSendFields = []string{"HealthChecks", "HealthStatusAggregationPolicy", "NetworkEndpointGroups", "NotificationEndpoints"}
+			}); err != nil {
+				t.Fatalf("Access() = %v", err)
+			}
+			res, err := hcs.Freeze()
+			if err != nil {
+				t.Fatalf("Freeze() = %v", err)
+			}
+			if err := nb.SetResource(res); err != nil {
+				t.Fatalf("SetResource() = %v", err)
+			}
+			nb.SetState(rnode.NodeExists)
+		}
+	}
+
+	apply(withDescription("a"))
+	apply(withDescription("b"))
+	apply(func(nb rnode.Builder) { nb.SetState(rnode.NodeDoesNotExist) })
+
+	wantCalls := []string{"Insert " + other, "Patch " + other, "Delete " + other}
+	if diff := cmp.Diff(calls, wantCalls); diff != "" {
+		t.Errorf("calls; -got,+want: %s", diff)
+	}
+}
+
+func TestDoPSC(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	producerKey := meta.RegionalKey("producer", region)
+	saKey := meta.RegionalKey("sa", region)
+	consumerKey := meta.RegionalKey("consumer", region)
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	var inserted []string
+	mock.MockForwardingRules.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.ForwardingRule, _ *cloud.MockForwardingRules) (bool, error) {
+		inserted = append(inserted, "forwardingRules/"+key.Name)
+		return false, nil
+	}
+	mock.MockServiceAttachments.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.ServiceAttachment, _ *cloud.MockServiceAttachments) (bool, error) {
+		inserted = append(inserted, "serviceAttachments/"+key.Name)
+		return false, nil
+	}
+
+	// The producer's internal load balancer is managed by someone else.
+	if err := mock.ForwardingRules().Insert(ctx, producerKey, &compute.ForwardingRule{Name: "producer", LoadBalancingScheme: "INTERNAL"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	inserted = nil
+
+	b := rgraph.NewBuilder()
+	if _, err := b.AddExternal(ctx, mock, forwardingrule.ID(proj, producerKey)); err != nil {
+		t.Fatalf("AddExternal() = %v, want nil", err)
+	}
+
+	// Networks and subnetworks do not have a Node type, so the references to
+	// them are left empty.
+	sa := serviceattachment.NewMutableServiceAttachment(proj, saKey)
+	if err := sa.Access(func(x *compute.ServiceAttachment) {
+		x.Name = "sa"
+		x.TargetService = cloud.SelfLink(meta.VersionGA, proj, "forwardingRules", producerKey)
+		x.ConnectionPreference = "ACCEPT_AUTOMATIC"
+		x.ForceSendFields = []string{"ConsumerAcceptLists", "ConsumerRejectLists", "Description", "DomainNames", "EnableProxyProtocol", "NatSubnets"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	saRes, err := sa.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	saBuilder := serviceattachment.NewBuilderWithResource(saRes)
+	saBuilder.SetOwnership(rnode.OwnershipManaged)
+	saBuilder.SetState(rnode.NodeExists)
+	b.Add(saBuilder)
+
+	consumer := forwardingrule.NewMutableForwardingRule(proj, consumerKey)
+	if err := consumer.Access(func(x *compute.ForwardingRule) {
+		x.Name = "consumer"
+		x.IPAddress = "10.0.0.5"
+		x.Target = cloud.SelfLink(meta.VersionGA, proj, "serviceAttachments", saKey)
+		x.ForceSendFields = []string{
+			"AllPorts", "AllowGlobalAccess", "BackendService", "Description", "IPProtocol", "IpVersion",
+			"IsMirroringCollector", "Labels", "LoadBalancingScheme", "MetadataFilters", "Network", "NetworkTier",
+			"NoAutomateDnsZone", "PortRange", "Ports", "ServiceDirectoryRegistrations", "ServiceLabel",
+			"SourceIpRanges", "Subnetwork",
+		}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	consumerRes, err := consumer.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	consumerBuilder := forwardingrule.NewBuilderWithResource(consumerRes)
+	consumerBuilder.SetOwnership(rnode.OwnershipManaged)
+	consumerBuilder.SetState(rnode.NodeExists)
+	b.Add(consumerBuilder)
+
+	want, err := b.Build()
+	if err != nil {
+		t.Fatalf("Build() = %v", err)
+	}
+	r, err := Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	for _, tc := range []struct {
+		id *cloud.ResourceID
+		op rnode.Operation
+	}{
+		{forwardingrule.ID(proj, producerKey), rnode.OpNothing},
+		{serviceattachment.ID(proj, saKey), rnode.OpCreate},
+		{forwardingrule.ID(proj, consumerKey), rnode.OpCreate},
+	} {
+		if op := want.Get(tc.id).Plan().Op(); op != tc.op {
+			t.Errorf("plan for %v = %s, want %s", tc.id, op, tc.op)
+		}
+	}
+	if _, err := Apply(ctx, mock, r); err != nil {
+		t.Fatalf("Apply() = %v, want nil", err)
+	}
+	// The ServiceAttachment must exist before the consumer connects to it.
+	wantInserted := []string{"serviceAttachments/sa", "forwardingRules/consumer"}
+	if diff := cmp.Diff(inserted, wantInserted); diff != "" {
+		t.Errorf("inserted; -got,+want: %s", diff)
+	}
+}
+
+func TestDoRecreateReferenced(t *testing.T) {
+	const (
+		proj   = "proj-1"
+		region = "us-central1"
+	)
+	ctx := context.Background()
+	producerKey := meta.RegionalKey("producer", region)
+	saKey := meta.RegionalKey("sa", region)
+	consumerKey := meta.RegionalKey("consumer", region)
+	mock := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	if err := mock.ForwardingRules().Insert(ctx, producerKey, &compute.ForwardingRule{Name: "producer", LoadBalancingScheme: "INTERNAL"}); err != nil {
+		t.Fatalf("Insert() = %v", err)
+	}
+	var calls []string
+	mock.MockForwardingRules.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.ForwardingRule, _ *cloud.MockForwardingRules) (bool, error) {
+		calls = append(calls, "Insert forwardingRules/"+key.Name)
+		return false, nil
+	}
+	mock.MockForwardingRules.DeleteHook = func(_ context.Context, key *meta.Key, _ *cloud.MockForwardingRules) (bool, error) {
+		calls = append(calls, "Delete forwardingRules/"+key.Name)
+		return false, nil
+	}
+	mock.MockServiceAttachments.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.ServiceAttachment, _ *cloud.MockServiceAttachments) (bool, error) {
+		calls = append(calls, "Insert serviceAttachments/"+key.Name)
+		return false, nil
+	}
+	mock.MockServiceAttachments.DeleteHook = func(_ context.Context, key *meta.Key, _ *cloud.MockServiceAttachments) (bool, error) {
+		calls = append(calls, "Delete serviceAttachments/"+key.Name)
+		return false, nil
+	}
+
+	// newWant returns the ServiceAttachment with the (immutable) domain
+	// and the consumer referencing it with the (immutable) description.
+	newWant := func(domain, description string) *rgraph.Graph {
+		t.Helper()
+		b := rgraph.NewBuilder()
+		if _, err := b.AddExternal(ctx, mock, forwardingrule.ID(proj, producerKey)); err != nil {
+			t.Fatalf("AddExternal() = %v, want nil", err)
+		}
+		sa := serviceattachment.NewMutableServiceAttachment(proj, saKey)
+		if err := sa.Access(func(x *compute.ServiceAttachment) {
+			x.Name = "sa"
+			x.TargetService = cloud.SelfLink(meta.VersionGA, proj, "forwardingRules", producerKey)
+			x.ConnectionPreference = "ACCEPT_AUTOMATIC"
+			x.DomainNames = []string{domain}
+			x.ForceSendFields = []string{"ConsumerAcceptLists", "ConsumerRejectLists", "Description", "EnableProxyProtocol", "NatSubnets"}
+		}); err != nil {
+			t.Fatalf("Access() = %v", err)
+		}
+		saRes, err := sa.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		saBuilder := serviceattachment.NewBuilderWithResource(saRes)
+		saBuilder.SetOwnership(rnode.OwnershipManaged)
+		saBuilder.SetState(rnode.NodeExists)
+		b.Add(saBuilder)
+
+		consumer := forwardingrule.NewMutableForwardingRule(proj, consumerKey)
+		if err := consumer.Access(func(x *compute.ForwardingRule) {
+			x.Name = "consumer"
+			x.Description = description
+			x.IPAddress = "10.0.0.5"
+			x.Target = cloud.SelfLink(meta.VersionGA, proj, "serviceAttachments", saKey)
+			x.ForceSendFields = []string{
+				"AllPorts", "AllowGlobalAccess", "BackendService", "IPProtocol", "IpVersion",
+				"IsMirroringCollector", "Labels", "LoadBalancingScheme", "MetadataFilters", "Network", "NetworkTier",
+				"NoAutomateDnsZone", "PortRange", "Ports", "ServiceDirectoryRegistrations", "ServiceLabel",
+				"SourceIpRanges", "Subnetwork",
+			}
+		}); err != nil {
+			t.Fatalf("Access() = %v", err)
+		}
+		consumerRes, err := consumer.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		consumerBuilder := forwardingrule.NewBuilderWithResource(consumerRes)
+		consumerBuilder.SetOwnership(rnode.OwnershipManaged)
+		consumerBuilder.SetState(rnode.NodeExists)
+		b.Add(consumerBuilder)
+
+		want, err := b.Build()
+		if err != nil {
+			t.Fatalf("Build() = %v", err)
+		}
+		return want
+	}
+
+	r, err := Do(ctx, mock, newWant("a.example.com", "a"))
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if _, err := Apply(ctx, mock, r); err != nil {
+		t.Fatalf("Apply() = %v, want nil", err)
+	}
+
+	// The consumer survives the recreate and keeps referencing the
+	// ServiceAttachment, which cannot be deleted while it is in use.
+	if _, err := Do(ctx, mock, newWant("b.example.com", "a")); err == nil {
+		t.Errorf("Do() = nil, want error (ServiceAttachment is still referenced)")
+	}
+
+	// The consumer is recreated, which drops the reference before the
+	// ServiceAttachment is deleted.
+	calls = nil
+	want := newWant("b.example.com", "b")
+	r, err = Do(ctx, mock, want)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	for _, id := range []*cloud.ResourceID{serviceattachment.ID(proj, saKey), forwardingrule.ID(proj, consumerKey)} {
+		if op := want.Get(id).Plan().Op(); op != rnode.OpRecreate {
+			t.Errorf("plan for %v = %s, want %s", id, op, rnode.OpRecreate)
+		}
+	}
+	if _, err := Apply(ctx, mock, r); err != nil {
+		t.Fatalf("Apply() = %v, want nil", err)
+	}
+	wantCalls := []string{
+		"Delete forwardingRules/consumer",
+		"Delete serviceAttachments/sa",
+		"Insert serviceAttachments/sa",
+		"Insert forwardingRules/consumer",
+	}
+	if diff := cmp.Diff(calls, wantCalls); diff != "" {
+		t.Errorf("calls; -got,+want: %s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+)
+
+// OperationCounts are the number of resources planned for each Operation.
+type OperationCounts struct {
+	Create   int
+	Update   int
+	Recreate int
+	Delete   int
+	Nothing  int
+}
+
+// Changed is the number of resources with a change planned.
+func (c OperationCounts) Changed() int {
+	return c.Create + c.Update + c.Recreate + c.Delete
+}
+
+func (c *OperationCounts) add(op rnode.Operation) {
+	switch op {
+	case rnode.OpCreate:
+		c.Create++
+	case rnode.OpUpdate:
+		c.Update++
+	case rnode.OpRecreate:
+		c.Recreate++
+	case rnode.OpDelete:
+		c.Delete++
+	case rnode.OpNothing:
+		c.Nothing++
+	}
+}
+
+// Summary statistics of a plan. This can be used to log/report the scope of
+// the changes or to enforce limits before the plan is executed.
+type Summary struct {
+	// ByResource are the counts by resource type (e.g. "backendServices").
+	ByResource map[string]*OperationCounts
+	// Total counts for all resources.
+	Total OperationCounts
+	// Affected is the number of resources with a change planned.
+	Affected int
+	// Operations is an estimate of the number of Cloud operations that
+	// will be issued by executing the plan. This is the number of Actions
+	// that are not internal to the executor (e.g. signalling that a
+	// resource exists).
+	Operations int
+}
+
+// String implements Stringer.
+func (s *Summary) String() string {
+	var resources []string
+	for r := range s.ByResource {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	var parts []string
+	for _, r := range resources {
+		c := s.ByResource[r]
+		if c.Changed() == 0 {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s(create=%d, update=%d, recreate=%d, delete=%d)", r, c.Create, c.Update, c.Recreate, c.Delete))
+	}
+	return fmt.Sprintf("%d resources affected, %d operations: [%s]", s.Affected, s.Operations, strings.Join(parts, " "))
+}
+
+// Summary computes the summary statistics for the plan.
+func (r *Result) Summary() *Summary {
+	ret := &Summary{ByResource: map[string]*OperationCounts{}}
+	if r.Want != nil {
+		for _, n := range r.Want.All() {
+			details := n.Plan().Details()
+			if details == nil {
+				continue
+			}
+			c, ok := ret.ByResource[n.ID().Resource]
+			if !ok {
+				c = &OperationCounts{}
+				ret.ByResource[n.ID().Resource] = c
+			}
+			c.add(details.Operation)
+			ret.Total.add(details.Operation)
+		}
+	}
+	ret.Affected = ret.Total.Changed()
+	for _, a := range r.Actions {
+		if a.Metadata().Type != exec.ActionTypeMeta {
+			ret.Operations++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSummary(t *testing.T) {
+	ops := map[string]rnode.Operation{
+		"a": rnode.OpCreate,
+		"b": rnode.OpCreate,
+		"c": rnode.OpUpdate,
+		"d": rnode.OpNothing,
+		"e": rnode.OpDelete,
+	}
+	b := rgraph.NewBuilder()
+	for name := range ops {
+		nb := fake.NewBuilder(fake.ID("proj", meta.GlobalKey(name)))
+		nb.SetOwnership(rnode.OwnershipManaged)
+		nb.SetState(rnode.NodeExists)
+		b.Add(nb)
+	}
+	want := b.MustBuild()
+	for name, op := range ops {
+		want.Get(fake.ID("proj", meta.GlobalKey(name))).Plan().Set(rnode.PlanDetails{Operation: op})
+	}
+	r := &Result{
+		Want: want,
+		Actions: []exec.Action{
+			exec.NewExistsAction(fake.ID("proj", meta.GlobalKey("d"))),
+			rnode.NewUpdateAction(nil, fake.ID("proj", meta.GlobalKey("c")), "update", nil, nil),
+		},
+	}
+
+	got := r.Summary()
+	wantSummary := &Summary{
+		ByResource: map[string]*OperationCounts{
+			"fakes": {Create: 2, Update: 1, Delete: 1, Nothing: 1},
+		},
+		Total:      OperationCounts{Create: 2, Update: 1, Delete: 1, Nothing: 1},
+		Affected:   4,
+		Operations: 1,
+	}
+	if diff := cmp.Diff(got, wantSummary); diff != "" {
+		t.Errorf("Summary(); -got,+want: %s", diff)
+	}
+	const wantStr = "4 resources affected, 1 operations: [fakes(create=2, update=1, recreate=0, delete=1)]"
+	if s := got.String(); s != wantStr {
+		t.Errorf("String() = %q, want %q", s, wantStr)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package reconcile implements a controller-style loop that repeatedly syncs
+// the resources in the Cloud to a wanted Graph.
+//
+// Each iteration of the Loop fetches the wanted Graph, plans against the
+// current state in the Cloud and executes the resulting Actions. Iterations
+// are run periodically, when a Trigger fires and, with backoff, after errors.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/eventsink"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/rollback"
+	"github.com/go-logr/logr"
+)
+
+// WantFunc returns the wanted state of the resources. This is called for each
+// iteration as planning modifies the Graph.
+type WantFunc func(ctx context.Context) (*rgraph.Graph, error)
+
+// PlanFunc plans the Actions to transform the current state to want. The
+// default is plan.Do.
+type PlanFunc func(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph) (*plan.Result, error)
+
+// Backoff computes the delay before retrying after consecutive failures.
+type Backoff interface {
+	// Delay returns the time to wait after the given number of consecutive
+	// failures (>= 1).
+	Delay(failures int) time.Duration
+}
+
+// ExponentialBackoff doubles the delay from Initial for each consecutive
+// failure, up to Max.
+type ExponentialBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay implements Backoff.
+func (b *ExponentialBackoff) Delay(failures int) time.Duration {
+	d := b.Initial
+	for i := 1; i < failures && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// Config for the Loop.
+type Config struct {
+	// Cloud to sync the resources with.
+	Cloud cloud.Cloud
+	// Want returns the wanted state of the resources.
+	Want WantFunc
+	// Plan is optional. If nil, plan.Do will be used.
+	Plan PlanFunc
+	// EventSink is optional. If set, Events from planning, execution and
+	// failed iterations are sent to the sink.
+	EventSink eventsink.Sink
+	// Interval between periodic reconciliations. If zero, reconciliation is
+	// only done on a Trigger or to retry an error.
+	Interval time.Duration
+	// Triggers cause an immediate reconciliation when they receive a value.
+	// Triggers received while a reconciliation is in progress are coalesced
+	// into a single reconciliation.
+	Triggers []<-chan struct{}
+	// Backoff is optional. If nil, a ExponentialBackoff from 1 second to 5
+	// minutes will be used.
+	Backoff Backoff
+	// StaleTTL is optional. If set, the plan is recomputed before execution
+	// if the state of any of the resources with planned changes was fetched
+	// more than StaleTTL ago (see plan.Result.Stale()). Actions on resources
+	// that become stale during execution fail and the resources are
+	// re-fetched and re-planned in the next iteration (see
+	// plan.StaleStateOption()).
+	StaleTTL time.Duration
+	// ExecutorOptions are passed to the Executor.
+	ExecutorOptions []exec.Option
+	// Rollback reverts the creates and updates made by an iteration if its
+	// execution fails, so that a partially built set of resources is not
+	// left behind. See package rollback.
+	Rollback bool
+	// OnResult is optional and called with the Result of each iteration.
+	OnResult func(*Result)
+	// Logger is optional. If unset, the Logger in the context given to Run()
+	// is used (see rlog.FromContext()). The Logger is given to planning and
+	// execution in the context of each iteration (see rlog.NewContext())
+	// with the iteration number as rlog.KeyAttempt.
+	Logger logr.Logger
+}
+
+// Result of a single iteration of the Loop.
+type Result struct {
+	// Plan is the result of planning. This is nil if planning did not
+	// complete.
+	Plan *plan.Result
+	// Exec is the result of execution. This is nil if execution did not
+	// start.
+	Exec *exec.Result
+	// Rollback is the result of the rollback after a failed execution.
+	// This is nil if no rollback was done.
+	Rollback *rollback.Result
+	// Err is the error for the iteration, if any.
+	Err error
+}
+
+// New returns a new Loop.
+func New(config Config) (*Loop, error) {
+	if config.Cloud == nil {
+		return nil, fmt.Errorf("reconcile: Config.Cloud must be set")
+	}
+	if config.Want == nil {
+		return nil, fmt.Errorf("reconcile: Config.Want must be set")
+	}
+	if config.Plan == nil {
+		sink := config.EventSink
+		config.Plan = func(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph) (*plan.Result, error) {
+			return plan.Do(ctx, cl, want, plan.EventSinkOption(sink))
+		}
+	}
+	if config.EventSink != nil {
+		config.ExecutorOptions = append(append([]exec.Option{}, config.ExecutorOptions...), exec.EventSinkOption(config.EventSink))
+	}
+	if config.Backoff == nil {
+		config.Backoff = &ExponentialBackoff{Initial: time.Second, Max: 5 * time.Minute}
+	}
+	return &Loop{
+		config: config,
+		kick:   make(chan struct{}, 1),
+	}, nil
+}
+
+// Loop reconciles the Cloud to the wanted state.
+type Loop struct {
+	config Config
+	kick   chan struct{}
+	// attempts is the number of iterations that have been started.
+	attempts int
+}
+
+// Trigger an immediate reconciliation. This does not block.
+func (l *Loop) Trigger() {
+	select {
+	case l.kick <- struct{}{}:
+	default:
+		// A reconciliation is already pending.
+	}
+}
+
+// Run the Loop until ctx is Done. The first reconciliation is done
+// immediately.
+func (l *Loop) Run(ctx context.Context) {
+	for _, t := range l.config.Triggers {
+		go l.forward(ctx, t)
+	}
+
+	var failures int
+	for {
+		res := l.Once(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if res.Err != nil {
+			failures++
+			delay := l.config.Backoff.Delay(failures)
+			rlog.OrContext(ctx, l.config.Logger).V(2).Info("Iteration failed, retrying", "failures", failures, "delay", delay.String(), "err", res.Err.Error())
+			// Retries wait for the backoff even if triggered.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+		failures = 0
+		var wait <-chan time.Time
+		if l.config.Interval > 0 {
+			wait = time.After(l.config.Interval)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-wait:
+		case <-l.kick:
+		}
+	}
+}
+
+// forward values from the trigger channel t to the Loop.
+func (l *Loop) forward(ctx context.Context, t <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-t:
+			if !ok {
+				return
+			}
+			l.Trigger()
+		}
+	}
+}
+
+// Once runs a single reconciliation.
+func (l *Loop) Once(ctx context.Context) *Result {
+	res := l.once(ctx)
+	if res.Err != nil {
+		eventsink.Emit(l.config.EventSink, &eventsink.Event{
+			Severity: eventsink.SeverityWarning,
+			Reason:   eventsink.ReasonReconcileFailed,
+			Message:  res.Err.Error(),
+		})
+	}
+	if l.config.OnResult != nil {
+		l.config.OnResult(res)
+	}
+	return res
+}
+
+func (l *Loop) once(ctx context.Context) *Result {
+	res := &Result{}
+	l.attempts++
+	logger := rlog.OrContext(ctx, l.config.Logger).WithValues(rlog.KeyAttempt, l.attempts)
+	ctx = rlog.NewContext(ctx, logger)
+
+	want, err := l.config.Want(ctx)
+	if err != nil {
+		res.Err = fmt.Errorf("reconcile: want: %w", err)
+		return res
+	}
+	res.Plan, err = l.config.Plan(ctx, l.config.Cloud, want)
+	if err != nil {
+		res.Err = fmt.Errorf("reconcile: plan: %w", err)
+		return res
+	}
+	if l.config.StaleTTL > 0 && res.Plan.Got != nil {
+		if stale := res.Plan.Stale(l.config.StaleTTL, time.Now()); len(stale) > 0 {
+			logger.V(4).Info("Stale resources, re-planning", "stale", len(stale))
+			res.Plan, err = l.config.Plan(ctx, l.config.Cloud, want)
+			if err != nil {
+				res.Err = fmt.Errorf("reconcile: plan: %w", err)
+				return res
+			}
+		}
+	}
+	execOpts := l.config.ExecutorOptions
+	if l.config.StaleTTL > 0 {
+		execOpts = append(append([]exec.Option{}, execOpts...), plan.StaleStateOption(res.Plan, l.config.StaleTTL))
+	}
+	res.Exec, err = plan.Apply(ctx, l.config.Cloud, res.Plan, execOpts...)
+	if err != nil {
+		res.Err = fmt.Errorf("reconcile: exec: %w", err)
+		if l.config.Rollback && res.Exec != nil {
+			l.rollback(ctx, res)
+		}
+	}
+	logger.V(4).Info("Iteration done", "actions", len(res.Plan.Actions), "failed", res.Err != nil)
+
+	return res
+}
+
+// rollback reverts the changes made by the failed execution in res.
+func (l *Loop) rollback(ctx context.Context, res *Result) {
+	var err error
+	res.Rollback, err = rollback.Do(ctx, l.config.Cloud, res.Plan, res.Exec,
+		rollback.PlanOptions(plan.EventSinkOption(l.config.EventSink)),
+		rollback.ExecutorOptions(l.config.ExecutorOptions...))
+	if err != nil {
+		res.Err = fmt.Errorf("%w (rollback failed: %v)", res.Err, err)
+		return
+	}
+	rlog.FromContext(ctx).V(2).Info("Rolled back after failed execution", "err", res.Err.Error())
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/fake"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	b := &ExponentialBackoff{Initial: time.Second, Max: 10 * time.Second}
+	for _, tc := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{100, 10 * time.Second},
+	} {
+		if got := b.Delay(tc.failures); got != tc.want {
+			t.Errorf("Delay(%d) = %v, want %v", tc.failures, got, tc.want)
+		}
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	if _, err := New(Config{Want: func(context.Context) (*rgraph.Graph, error) { return nil, nil }}); err == nil {
+		t.Errorf("New() with no Cloud = _, nil; want error")
+	}
+	if _, err := New(Config{Cloud: cloud.NewMockGCE(nil)}); err == nil {
+		t.Errorf("New() with no Want = _, nil; want error")
+	}
+}
+
+// testPlanner returns a single Action and can be made to fail.
+type testPlanner struct {
+	fail bool
+}
+
+func (p *testPlanner) plan(ctx context.Context, cl cloud.Cloud, want *rgraph.Graph) (*plan.Result, error) {
+	if p.fail {
+		p.fail = false
+		return nil, fmt.Errorf("injected error")
+	}
+	id := &cloud.ResourceID{Resource: "fake", Key: meta.GlobalKey("a")}
+	return &plan.Result{
+		Want:    want,
+		Actions: []exec.Action{exec.NewExistsAction(id)},
+	}, nil
+}
+
+func TestLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	planner := &testPlanner{fail: true}
+	trigger := make(chan struct{})
+	results := make(chan *Result)
+
+	l, err := New(Config{
+		Cloud:    cloud.NewMockGCE(nil),
+		Want:     func(context.Context) (*rgraph.Graph, error) { return rgraph.NewBuilder().MustBuild(), nil },
+		Plan:     planner.plan,
+		Triggers: []<-chan struct{}{trigger},
+		Backoff:  &ExponentialBackoff{Initial: time.Millisecond, Max: time.Millisecond},
+		OnResult: func(r *Result) { results <- r },
+	})
+	if err != nil {
+		t.Fatalf("New() = %v, want nil", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		l.Run(ctx)
+		close(done)
+	}()
+
+	// First iteration fails and is retried after the backoff.
+	if r := <-results; r.Err == nil {
+		t.Errorf("iteration 1: Err = nil, want error")
+	}
+	r := <-results
+	if r.Err != nil {
+		t.Fatalf("iteration 2: Err = %v, want nil", r.Err)
+	}
+	if len(r.Exec.Completed) != 1 {
+		t.Errorf("iteration 2: len(Exec.Completed) = %d, want 1", len(r.Exec.Completed))
+	}
+
+	// No Interval, so the next iteration happens only on a trigger.
+	select {
+	case r := <-results:
+		t.Fatalf("got unexpected iteration %+v", r)
+	case <-time.After(10 * time.Millisecond):
+	}
+	trigger <- struct{}{}
+	if r := <-results; r.Err != nil {
+		t.Errorf("iteration 3: Err = %v, want nil", r.Err)
+	}
+
+	cancel()
+	<-done
+}
+
+func TestLoopStaleTTL(t *testing.T) {
+	id := fake.ID("proj", meta.GlobalKey("a"))
+
+	var calls int
+	planFn := func(ctx context.Context, cl cloud.Cloud, _ *rgraph.Graph) (*plan.Result, error) {
+		calls++
+		gotNB := fake.NewBuilder(id)
+		gotNB.SetOwnership(rnode.OwnershipManaged)
+		gotNB.SetState(rnode.NodeDoesNotExist)
+		if calls > 1 {
+			gotNB.SetFetchTime(time.Now())
+		}
+		got := rgraph.NewBuilder()
+		got.Add(gotNB)
+
+		wantNB := fake.NewBuilder(id)
+		wantNB.SetOwnership(rnode.OwnershipManaged)
+		wantNB.SetState(rnode.NodeExists)
+		want := rgraph.NewBuilder()
+		want.Add(wantNB)
+		wantGraph := want.MustBuild()
+		wantGraph.Get(id).Plan().Set(rnode.PlanDetails{Operation: rnode.OpCreate})
+
+		return &plan.Result{
+			Got:     got.MustBuild(),
+			Want:    wantGraph,
+			Actions: []exec.Action{exec.NewExistsAction(id)},
+		}, nil
+	}
+
+	l, err := New(Config{
+		Cloud:    cloud.NewMockGCE(nil),
+		Want:     func(context.Context) (*rgraph.Graph, error) { return rgraph.NewBuilder().MustBuild(), nil },
+		Plan:     planFn,
+		StaleTTL: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("New() = %v, want nil", err)
+	}
+	// The first plan has a node that was never fetched and is re-planned.
+	r := l.Once(context.Background())
+	if r.Err != nil {
+		t.Fatalf("Once() = %v, want nil", r.Err)
+	}
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2", calls)
+	}
+	if stale := r.Plan.Stale(time.Hour, time.Now()); len(stale) != 0 {
+		t.Errorf("Stale() = %v, want []", stale)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rollback reverts the changes made by a plan that failed part way
+// through execution.
+//
+// The resources that were created or updated by the execution are restored
+// to their state before the execution, as recorded in plan.Result.Got.
+// Created resources are deleted and updated resources are updated back to
+// their prior values. Resources that were deleted are not restored.
+package rollback
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rlog"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+)
+
+// Result of the rollback.
+type Result struct {
+	// Plan to restore the prior state. This is nil if planning did not
+	// complete.
+	Plan *plan.Result
+	// Exec is the result of executing Plan. This is nil if execution did
+	// not start.
+	Exec *exec.Result
+}
+
+// Option for the rollback.
+type Option func(*config)
+
+// PlanOptions are passed to plan.Do when planning the rollback.
+func PlanOptions(opts ...plan.Option) Option {
+	return func(c *config) { c.planOpts = append(c.planOpts, opts...) }
+}
+
+// ExecutorOptions are passed to the Executor for the rollback.
+func ExecutorOptions(opts ...exec.Option) Option {
+	return func(c *config) { c.execOpts = append(c.execOpts, opts...) }
+}
+
+type config struct {
+	planOpts []plan.Option
+	execOpts []exec.Option
+}
+
+// Do reverts the creates and updates made by executing pr.Actions, with
+// result er. Actions that failed are also reverted as they may have been
+// partially applied (e.g. an update that calls multiple methods).
+//
+// The current state of the affected resources is fetched from the Cloud
+// and compared against the prior state, so resources that were not changed
+// by the execution are left alone.
+func Do(ctx context.Context, cl cloud.Cloud, pr *plan.Result, er *exec.Result, opts ...Option) (*Result, error) {
+	c := &config{}
+	for _, o := range opts {
+		o(c)
+	}
+	if pr == nil || pr.Got == nil || er == nil {
+		return nil, fmt.Errorf("rollback: missing plan or exec result")
+	}
+
+	ids := Changed(er)
+	if len(ids) == 0 {
+		rlog.FromContext(ctx).V(4).Info("Nothing to roll back")
+		return &Result{}, nil
+	}
+	want, err := priorGraph(pr.Got, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{}
+	res.Plan, err = plan.Do(ctx, cl, want, c.planOpts...)
+	if err != nil {
+		return res, fmt.Errorf("rollback: %w", err)
+	}
+	execOpts := append([]exec.Option{exec.OrderHintsOption(res.Plan.OrderHints...)}, c.execOpts...)
+	ex, err := exec.NewSerialExecutor(res.Plan.Actions, execOpts...)
+	if err != nil {
+		return res, fmt.Errorf("rollback: %w", err)
+	}
+	res.Exec, err = ex.Run(ctx, cl)
+	rlog.FromContext(ctx).V(4).Info("Rolled back", "resources", len(ids), "actions", len(res.Plan.Actions), "failed", err != nil)
+	if err != nil {
+		return res, fmt.Errorf("rollback: exec: %w", err)
+	}
+	return res, nil
+}
+
+// Changed returns the resources that may have been created or updated by
+// the execution with result er.
+func Changed(er *exec.Result) []*cloud.ResourceID {
+	var (
+		ret  []*cloud.ResourceID
+		seen = map[cloud.ResourceMapKey]bool{}
+	)
+	add := func(a exec.Action) {
+		md := a.Metadata()
+		if md.ResourceID == nil || seen[md.ResourceID.MapKey()] {
+			return
+		}
+		switch md.Type {
+		case exec.ActionTypeCreate, exec.ActionTypeUpdate:
+			seen[md.ResourceID.MapKey()] = true
+			ret = append(ret, md.ResourceID)
+		}
+	}
+	for _, a := range er.Completed {
+		add(a)
+	}
+	for _, ae := range er.Errors {
+		add(ae.Action)
+	}
+	return ret
+}
+
+// priorGraph returns a Graph with the prior state from got. Only the Nodes
+// in ids are managed; the other Nodes are present to resolve references and
+// will not be changed.
+func priorGraph(got *rgraph.Graph, ids []*cloud.ResourceID) (*rgraph.Graph, error) {
+	// The prior state is the resources in got. These are copied explicitly
+	// as the Builder of a Node does not have the resource.
+	b := rgraph.NewBuilder()
+	for _, n := range got.All() {
+		nb, err := rnode.CloneBuilder(n)
+		if err != nil {
+			return nil, fmt.Errorf("rollback: %w", err)
+		}
+		nb.SetOwnership(rnode.OwnershipExternal)
+		b.Add(nb)
+	}
+	for _, id := range ids {
+		nb := b.Get(id)
+		if nb == nil {
+			return nil, fmt.Errorf("rollback: %v is not in the plan", id)
+		}
+		switch nb.State() {
+		case rnode.NodeExists, rnode.NodeDoesNotExist:
+		default:
+			return nil, fmt.Errorf("rollback: prior state of %v is %s", id, nb.State())
+		}
+		nb.SetOwnership(rnode.OwnershipManaged)
+	}
+	g, err := b.Build()
+	if err != nil {
+		return nil, fmt.Errorf("rollback: %w", err)
+	}
+	return g, nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2023 Google LLC
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rollback
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/mock"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/exec"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/healthcheckservice"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/rnode/notificationendpoint"
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/rgraph/workflow/plan"
+	"google.golang.org/api/compute/v1"
+)
+
+const (
+	proj   = "proj-1"
+	region = "us-central1"
+	neURL  = "https://www.googleapis.com/compute/v1/projects/proj-1/regions/us-central1/notificationEndpoints/ne"
+)
+
+var (
+	neKey  = meta.RegionalKey("ne", region)
+	ne2Key = meta.RegionalKey("ne2", region)
+	hcsKey = meta.RegionalKey("hcs", region)
+)
+
+func newMock(failInsert string) *cloud.MockGCE {
+	m := cloud.NewMockGCE(&cloud.SingleProjectRouter{ID: proj})
+	m.MockRegionHealthCheckServices.PatchHook = mock.PatchRegionHealthCheckServiceHook
+	injected := errors.New("injected")
+	m.MockRegionNotificationEndpoints.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.NotificationEndpoint, _ *cloud.MockRegionNotificationEndpoints) (bool, error) {
+		return key.Name == failInsert, injected
+	}
+	m.MockRegionHealthCheckServices.InsertHook = func(_ context.Context, key *meta.Key, _ *compute.HealthCheckService, _ *cloud.MockRegionHealthCheckServices) (bool, error) {
+		return key.Name == failInsert, injected
+	}
+	return m
+}
+
+// newWant returns a Graph with the NotificationEndpoints in nes and a
+// HealthCheckService that references "ne".
+func newWant(t *testing.T, description string, nes ...*meta.Key) *rgraph.Graph {
+	t.Helper()
+	b := rgraph.NewBuilder()
+	for _, key := range nes {
+		r := notificationendpoint.NewMutableNotificationEndpoint(proj, key)
+		if err := r.Access(func(x *compute.NotificationEndpoint) {
+			x.Name = key.Name
+			x.NullFields = []string{"GrpcSettings"}
+			x.ForceSendFields = []string{"Description"}
+		}); err != nil {
+			t.Fatalf("Access() = %v", err)
+		}
+		fr, err := r.Freeze()
+		if err != nil {
+			t.Fatalf("Freeze() = %v", err)
+		}
+		nb := notificationendpoint.NewBuilderWithResource(fr)
+		nb.SetState(rnode.NodeExists)
+		nb.SetOwnership(rnode.OwnershipManaged)
+		b.Add(nb)
+	}
+	r := healthcheckservice.NewMutableHealthCheckService(proj, hcsKey)
+	if err := r.Access(func(x *compute.HealthCheckService) {
+		x.Name = "hcs"
+		x.Description = description
+		x.NotificationEndpoints = []string{neURL}
+		x.HealthStatusAggregationPolicy = "AND"
+		x.NullFields = []string{"HealthChecks", "NetworkEndpointGroups"}
+		x.ForceSendFields = []string{"Description"}
+	}); err != nil {
+		t.Fatalf("Access() = %v", err)
+	}
+	fr, err := r.Freeze()
+	if err != nil {
+		t.Fatalf("Freeze() = %v", err)
+	}
+	nb := healthcheckservice.NewBuilderWithResource(fr)
+	nb.SetState(rnode.NodeExists)
+	nb.SetOwnership(rnode.OwnershipManaged)
+	b.Add(nb)
+	return b.MustBuild()
+}
+
+// apply plans and executes want, returning the results.
+func apply(t *testing.T, m cloud.Cloud, want *rgraph.Graph) (*plan.Result, *exec.Result, error) {
+	t.Helper()
+	pr, err := plan.Do(context.Background(), m, want)
+	if err != nil {
+		t.Fatalf("plan.Do() = %v, want nil", err)
+	}
+	ex, err := exec.NewSerialExecutor(pr.Actions, exec.ErrorStrategyOption(exec.ContinueOnError))
+	if err != nil {
+		t.Fatalf("NewSerialExecutor() = %v, want nil", err)
+	}
+	er, err := ex.Run(context.Background(), m)
+	return pr, er, err
+}
+
+func TestRollbackCreate(t *testing.T) {
+	ctx := context.Background()
+	m := newMock("hcs")
+
+	pr, er, err := apply(t, m, newWant(t, "", neKey))
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if _, err := m.RegionNotificationEndpoints().Get(ctx, neKey); err != nil {
+		t.Fatalf("Get(ne) after failed exec = %v, want nil", err)
+	}
+
+	res, err := Do(ctx, m, pr, er)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	if res.Exec == nil || len(res.Exec.Errors) > 0 {
+		t.Errorf("Do() = %+v; want Exec with no errors", res)
+	}
+	if _, err := m.RegionNotificationEndpoints().Get(ctx, neKey); err == nil {
+		t.Errorf("Get(ne) after rollback = nil, want error")
+	}
+	if _, err := m.RegionHealthCheckServices().Get(ctx, hcsKey); err == nil {
+		t.Errorf("Get(hcs) after rollback = nil, want error")
+	}
+}
+
+func TestRollbackUpdate(t *testing.T) {
+	ctx := context.Background()
+	m := newMock("ne2")
+
+	if _, _, err := apply(t, m, newWant(t, "a", neKey)); err != nil {
+		t.Fatalf("Run() = %v, want nil", err)
+	}
+	pr, er, err := apply(t, m, newWant(t, "b", neKey, ne2Key))
+	if err == nil {
+		t.Fatalf("Run() = nil, want error")
+	}
+	if hcs, err := m.RegionHealthCheckServices().Get(ctx, hcsKey); err != nil || hcs.Description != "b" {
+		t.Fatalf("Get(hcs) after failed exec = %+v, %v; want Description=b", hcs, err)
+	}
+
+	res, err := Do(ctx, m, pr, er)
+	if err != nil {
+		t.Fatalf("Do() = %v, want nil", err)
+	}
+	// Only the HealthCheckService was changed.
+	if got := res.Plan.Summary().Total; got.Changed() != 1 || got.Update != 1 {
+		t.Errorf("Do() plan = %v; want 1 update", res.Plan.Summary())
+	}
+	if hcs, err := m.RegionHealthCheckServices().Get(ctx, hcsKey); err != nil || hcs.Description != "a" {
+		t.Errorf("Get(hcs) after rollback = %+v, %v; want Description=a", hcs, err)
+	}
+	if _, err := m.RegionNotificationEndpoints().Get(ctx, neKey); err != nil {
+		t.Errorf("Get(ne) after rollback = %v, want nil", err)
+	}
+}
+
+func TestChanged(t *testing.T) {
+	id := func(name string) *cloud.ResourceID {
+		return notificationendpoint.ID(proj, meta.RegionalKey(name, region))
+	}
+	er := &exec.Result{
+		Completed: []exec.Action{
+			rnode.NewUpdateAction(nil, id("a"), "", nil, nil),
+			rnode.NewUpdateAction(nil, id("a"), "", nil, nil),
+			exec.NewExistsAction(id("b")),
+		},
+		Errors: []exec.ActionWithErr{
+			{Action: rnode.NewUpdateAction(nil, id("c"), "", nil, nil), Err: errors.New("injected")},
+		},
+	}
+	var got []string
+	for _, id := range Changed(er) {
+		got = append(got, id.String())
+	}
+	want := []string{id("a").String(), id("c").String()}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Changed() = %v, want %v", got, want)
+	}
+}
@@ -18,6 +18,7 @@ package cloud
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -35,6 +36,20 @@ type Service struct {
 	Beta          *beta.Service
 	ProjectRouter ProjectRouter
 	RateLimiter   RateLimiter
+	// DryRun puts the Service in read-only mode. Mutating calls that
+	// support the validateOnly parameter are sent with validateOnly=true
+	// (the request is validated but not committed). All other mutating
+	// calls return an error wrapping ErrDryRun without calling the API.
+	DryRun bool
+}
+
+// ErrDryRun is returned by mutating calls that are blocked because the
+// Service is in DryRun mode.
+var ErrDryRun = errors.New("mutation blocked in dry run mode")
+
+// dryRunError returns the error for a mutating call blocked by DryRun.
+func dryRunError(ck *CallContextKey) error {
+	return fmt.Errorf("%s.%s(%s): %w", ck.Service, ck.Operation, ck.Version, ErrDryRun)
 }
 
 // wrapOperation wraps a GCE anyOP in a version generic operation type.
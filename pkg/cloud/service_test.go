@@ -19,8 +19,16 @@ package cloud
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	beta "google.golang.org/api/compute/v0.beta"
+	ga "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
 )
 
 func TestPollOperation(t *testing.T) {
@@ -99,3 +107,110 @@ func (f *fakeOperation) error() error {
 func (f *fakeOperation) rateLimitKey() *RateLimitKey {
 	return nil
 }
+
+func TestServiceDryRun(t *testing.T) {
+	t.Parallel()
+
+	var (
+		lock     sync.Mutex
+		requests []*http.Request
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, r)
+		lock.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "op-1", "status": "DONE"}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	opts := []option.ClientOption{option.WithEndpoint(server.URL), option.WithHTTPClient(server.Client())}
+	gaSvc, err := ga.NewService(ctx, opts...)
+	if err != nil {
+		t.Fatalf("ga.NewService() = %v", err)
+	}
+	betaSvc, err := beta.NewService(ctx, opts...)
+	if err != nil {
+		t.Fatalf("beta.NewService() = %v", err)
+	}
+	gce := NewGCE(&Service{
+		GA:            gaSvc,
+		Beta:          betaSvc,
+		ProjectRouter: &SingleProjectRouter{ID: "proj1"},
+		RateLimiter:   &NopRateLimiter{},
+		DryRun:        true,
+	})
+
+	for _, tc := range []struct {
+		name string
+		f    func() error
+		// wantValidateOnly is true if the call is sent with validateOnly.
+		// Otherwise the call should be blocked.
+		wantValidateOnly bool
+	}{
+		{
+			name: "Insert",
+			f:    func() error { return gce.Addresses().Insert(ctx, meta.RegionalKey("a", "us-central1"), &ga.Address{}) },
+		},
+		{
+			name: "Delete",
+			f:    func() error { return gce.Addresses().Delete(ctx, meta.RegionalKey("a", "us-central1")) },
+		},
+		{
+			name: "custom method",
+			f: func() error {
+				return gce.BackendServices().SetSecurityPolicy(ctx, meta.GlobalKey("bs"), &ga.SecurityPolicyReference{})
+			},
+		},
+		{
+			name: "SetCommonInstanceMetadata",
+			f:    func() error { return gce.Projects().SetCommonInstanceMetadata(ctx, "proj1", &ga.Metadata{}) },
+		},
+		{
+			name: "Insert validateOnly",
+			f: func() error {
+				return gce.BetaSecurityPolicies().Insert(ctx, meta.GlobalKey("sp"), &beta.SecurityPolicy{})
+			},
+			wantValidateOnly: true,
+		},
+		{
+			name: "custom method validateOnly",
+			f: func() error {
+				return gce.BetaSecurityPolicies().AddRule(ctx, meta.GlobalKey("sp"), &beta.SecurityPolicyRule{})
+			},
+			wantValidateOnly: true,
+		},
+	} {
+		lock.Lock()
+		requests = nil
+		lock.Unlock()
+
+		err := tc.f()
+
+		lock.Lock()
+		got := requests
+		lock.Unlock()
+
+		if !tc.wantValidateOnly {
+			if !errors.Is(err, ErrDryRun) {
+				t.Errorf("%s: f() = %v, want ErrDryRun", tc.name, err)
+			}
+			if len(got) != 0 {
+				t.Errorf("%s: got %d requests, want 0", tc.name, len(got))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: f() = %v, want nil", tc.name, err)
+		}
+		// The operation is not polled.
+		if len(got) != 1 {
+			t.Errorf("%s: got %d requests, want 1", tc.name, len(got))
+			continue
+		}
+		if v := got[0].URL.Query().Get("validateOnly"); v != "true" {
+			t.Errorf("%s: validateOnly = %q, want \"true\"", tc.name, v)
+		}
+	}
+}
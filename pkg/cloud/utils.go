@@ -17,18 +17,20 @@ limitations under the License.
 package cloud
 
 import (
-	"encoding/json"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/k8s-cloud-provider/pkg/cloud/meta"
+	"google.golang.org/api/googleapi"
 )
 
 var (
-	domainPrefix          = "https://www.googleapis.com"
-	computePrefix         = "https://www.googleapis.com/compute"
-	networkServicesPrefix = "https://www.googleapis.com/networkservices"
+	domainPrefix             = "https://www.googleapis.com"
+	computePrefix            = "https://www.googleapis.com/compute"
+	networkServicesPrefix    = "https://www.googleapis.com/networkservices"
+	certificateManagerPrefix = "https://www.googleapis.com/certificatemanager"
 )
 
 // SetAPIDomain sets the root of the URL for the API. The default domain is
@@ -37,6 +39,7 @@ func SetAPIDomain(domain string) {
 	domainPrefix = domain
 	computePrefix = domain + "/compute"
 	networkServicesPrefix = domain + "/networkservices"
+	certificateManagerPrefix = domain + "/certificatemanager"
 }
 
 // ResourceID identifies a GCE resource as parsed from compute resource URL.
@@ -166,6 +169,8 @@ func ParseResourceURL(url string) (*ResourceID, error) {
 			apiGroup = meta.APIGroupCompute
 		case "networkservices":
 			apiGroup = meta.APIGroupNetworkServices
+		case "certificatemanager":
+			apiGroup = meta.APIGroupCertificateManager
 		default:
 			return nil, fmt.Errorf("%q does not contain a supported API Group", url)
 		}
@@ -232,14 +237,6 @@ func ParseResourceURL(url string) (*ResourceID, error) {
 	return nil, errNotValid
 }
 
-func copyViaJSON(dest, src interface{}) error {
-	bytes, err := json.Marshal(src)
-	if err != nil {
-		return err
-	}
-	return json.Unmarshal(bytes, dest)
-}
-
 // ResourcePath returns the path starting from the location.
 // Example: regions/us-central1/subnetworks/my-subnet
 // Deprecated: Use SelfLinkWithGroup instead
@@ -289,22 +286,15 @@ func SelfLinkWithGroup(apiGroup meta.APIGroup, ver meta.Version, project, resour
 		prefix = computePrefix
 	case meta.APIGroupNetworkServices:
 		prefix = networkServicesPrefix
+	case meta.APIGroupCertificateManager:
+		prefix = certificateManagerPrefix
 	default:
 		prefix = domainPrefix + "/invalid-apigroup"
 	}
 
-	switch ver {
-	case meta.VersionAlpha:
-		prefix = prefix + "/alpha"
-	case meta.VersionBeta:
-		if apiGroup == meta.APIGroupNetworkServices {
-			prefix = prefix + "/v1beta1"
-		} else {
-			prefix = prefix + "/beta"
-		}
-	case meta.VersionGA:
-		prefix = prefix + "/v1"
-	default:
+	if vp := apiGroup.VersionPath(ver); vp != "" {
+		prefix = prefix + "/" + vp
+	} else {
 		prefix = "invalid-version"
 	}
 
@@ -324,3 +314,34 @@ func aggregatedListKey(k *meta.Key) string {
 		return "unknownScope"
 	}
 }
+
+// mockCheckKeyScope returns an error if the scope of key does not match the
+// scope of the resource. The error is shaped like the one returned by the
+// API: a key that is missing the location of the resource (e.g. a GlobalKey for
+// a zonal resource) results in a StatusBadRequest, a key that names a location
+// of the wrong type (e.g. a ZonalKey for a regional resource) results in a
+// StatusNotFound as the URL does not exist.
+//
+// The mocks check the keys given to Get(), Insert(), Delete() and the
+// additional methods of the service. List() and AggregatedList() do not take
+// a key: their scope is fixed by the arguments of the method.
+func mockCheckKeyScope(mockType string, key *meta.Key, want meta.KeyType) error {
+	got := key.Type()
+	if got == want {
+		return nil
+	}
+	if got == meta.Global {
+		field := "zone"
+		if want == meta.Regional {
+			field = "region"
+		}
+		return &googleapi.Error{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("%s: invalid value for field '%s': ''; %s key %v used for %s resource", mockType, field, got, key, want),
+		}
+	}
+	return &googleapi.Error{
+		Code:    http.StatusNotFound,
+		Message: fmt.Sprintf("%s: the requested URL was not found; %s key %v used for %s resource", mockType, got, key, want),
+	}
+}
@@ -153,6 +153,14 @@ func TestParseResourceURL(t *testing.T) {
 			"http://localhost:3990/networkservices/alpha/projects/some-gce-project/regions/dev-central1/addresses/my-address",
 			&ResourceID{"some-gce-project", meta.APIGroupNetworkServices, "addresses", meta.RegionalKey("my-address", "dev-central1")},
 		},
+		{
+			"https://www.googleapis.com/certificatemanager/v1/projects/some-gce-project/global/certificateMaps/map-1",
+			&ResourceID{"some-gce-project", meta.APIGroupCertificateManager, "certificateMaps", meta.GlobalKey("map-1")},
+		},
+		{
+			"https://www.googleapis.com/certificatemanager/v1beta1/projects/some-gce-project/regions/us-central1/certificates/cert-1",
+			&ResourceID{"some-gce-project", meta.APIGroupCertificateManager, "certificates", meta.RegionalKey("cert-1", "us-central1")},
+		},
 		{
 			"http://localhost:3990/compute/v1/projects/some-gce-project/zones/dev-central1-std/instances/instance-1",
 			&ResourceID{"some-gce-project", meta.APIGroupCompute, "instances", meta.ZonalKey("instance-1", "dev-central1-std")},
@@ -299,6 +307,21 @@ func TestResourceIdSelfLink(t *testing.T) {
 			meta.VersionAlpha,
 			"https://www.googleapis.com/compute/alpha/projects/proj1/global/res1/key1",
 		},
+		{
+			&ResourceID{"proj1", meta.APIGroupNetworkServices, "res1", meta.GlobalKey("key1")},
+			meta.VersionBeta,
+			"https://www.googleapis.com/networkservices/v1beta1/projects/proj1/global/res1/key1",
+		},
+		{
+			&ResourceID{"proj1", meta.APIGroupCertificateManager, "res1", meta.GlobalKey("key1")},
+			meta.VersionGA,
+			"https://www.googleapis.com/certificatemanager/v1/projects/proj1/global/res1/key1",
+		},
+		{
+			&ResourceID{"proj1", meta.APIGroupCertificateManager, "res1", meta.GlobalKey("key1")},
+			meta.VersionBeta,
+			"https://www.googleapis.com/certificatemanager/v1beta1/projects/proj1/global/res1/key1",
+		},
 		{
 			&ResourceID{"proj1", "", "res1", meta.GlobalKey("key1")},
 			meta.VersionAlpha,
@@ -311,6 +334,31 @@ func TestResourceIdSelfLink(t *testing.T) {
 	}
 }
 
+func TestResourceIDSelfLinkRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, group := range meta.AllAPIGroups {
+		for _, ver := range meta.AllVersions {
+			for _, key := range []*meta.Key{
+				meta.GlobalKey("key1"),
+				meta.RegionalKey("key1", "us-central1"),
+				meta.ZonalKey("key1", "us-central1-b"),
+			} {
+				id := &ResourceID{"proj1", group, "res1", key}
+				link := id.SelfLink(ver)
+				got, err := ParseResourceURL(link)
+				if err != nil {
+					t.Errorf("ParseResourceURL(%q) = %v, want nil", link, err)
+					continue
+				}
+				if !got.Equal(id) {
+					t.Errorf("ParseResourceURL(%q) = %+v, want %+v", link, got, id)
+				}
+			}
+		}
+	}
+}
+
 func TestSelfLink(t *testing.T) {
 	t.Parallel()
 
@@ -521,6 +569,19 @@ func TestSelfLinkWithSetAPIDomain(t *testing.T) {
 			t.Errorf("SelfLink(%v, %q, %q, %v) = %v, want %q", tc.ver, tc.project, tc.resource, tc.key, link, tc.want)
 		}
 	}
+
+	SetAPIDomain("https://foo.bar")
+	for _, tc := range []struct {
+		group meta.APIGroup
+		want  string
+	}{
+		{meta.APIGroupNetworkServices, "https://foo.bar/networkservices/v1/projects/proj1/global/res1/key1"},
+		{meta.APIGroupCertificateManager, "https://foo.bar/certificatemanager/v1/projects/proj1/global/res1/key1"},
+	} {
+		if link := SelfLinkWithGroup(tc.group, meta.VersionGA, "proj1", "res1", meta.GlobalKey("key1")); link != tc.want {
+			t.Errorf("SelfLinkWithGroup(%v, ...) = %v, want %q", tc.group, link, tc.want)
+		}
+	}
 }
 
 func TestAggregatedListKey(t *testing.T) {